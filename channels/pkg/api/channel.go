@@ -46,6 +46,9 @@ type AddonSpec struct {
 	// Manifest is the URL to the manifest that should be applied
 	Manifest *string `json:"manifest,omitempty"`
 
+	// Chart is a Helm chart that should be rendered and applied, instead of a manifest
+	Chart *ChartSpec `json:"chart,omitempty"`
+
 	// Manifesthash is the sha1 hash of our manifest
 	ManifestHash string `json:"manifestHash,omitempty"`
 
@@ -59,4 +62,47 @@ type AddonSpec struct {
 	// version of the software we are packaging.  But we always want to reinstall when we
 	// switch kubernetes versions.
 	Id string `json:"id,omitempty"`
+
+	// DependsOn lists the names of addons which must be applied (and be ready) before this addon is applied
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Readiness lists the objects which must be ready after this addon is applied, before any addon which
+	// depends on it is applied
+	Readiness []*ReadinessGate `json:"readiness,omitempty"`
+}
+
+// ChartSpec identifies a Helm chart, and the values with which it should be rendered
+type ChartSpec struct {
+	// Repository is the URL of the chart repository.  If not set, Name is the location of a chart archive.
+	// Relative locations are relative to the channel.
+	Repository string `json:"repository,omitempty"`
+
+	// Name is the name of the chart in the repository, or the location of a chart archive
+	Name string `json:"name"`
+
+	// Version is the version of the chart in the repository
+	Version string `json:"version,omitempty"`
+
+	// ReleaseName is exposed to the chart templates as .Release.Name, defaulting to the name of the chart
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// Namespace is the namespace the chart is installed into, defaulting to the namespace of the addon.
+	// It is created if it does not exist.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Values override the default values of the chart
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// ReadinessGate is an object which must become ready before an addon is considered to be ready
+type ReadinessGate struct {
+	// Kind is the kind of object: one of Deployment, DaemonSet or CustomResourceDefinition.
+	// A Deployment or DaemonSet is ready when it is fully rolled out, a CustomResourceDefinition when it is established.
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object, defaulting to the namespace of the addon.  Not used for CustomResourceDefinitions.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object
+	Name string `json:"name"`
 }
@@ -19,11 +19,13 @@ package channels
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 // Addon is a wrapper around a single version of an addon
@@ -32,6 +34,10 @@ type Addon struct {
 	ChannelName     string
 	ChannelLocation url.URL
 	Spec            *api.AddonSpec
+
+	// rendered holds the rendered manifest of a chart addon, and renderedHash its hash
+	rendered     []byte
+	renderedHash string
 }
 
 // AddonUpdate holds data about a proposed update to an addon
@@ -66,12 +72,17 @@ func (m *AddonMenu) MergeAddons(o *AddonMenu) {
 }
 
 func (a *Addon) ChannelVersion() *ChannelVersion {
+	// The hash of a chart addon is only known once the chart is rendered, so that changes to values trigger an update
+	manifestHash := a.Spec.ManifestHash
+	if a.renderedHash != "" {
+		manifestHash = a.renderedHash
+	}
 
 	return &ChannelVersion{
 		Channel:      &a.ChannelName,
 		Version:      a.Spec.Version,
 		Id:           a.Spec.Id,
-		ManifestHash: a.Spec.ManifestHash,
+		ManifestHash: manifestHash,
 	}
 }
 
@@ -88,7 +99,22 @@ func (a *Addon) buildChannel() *Channel {
 	return channel
 }
 
+// WaitForReadiness waits until the readiness gates of the addon are satisfied
+func (a *Addon) WaitForReadiness(applier *Applier, timeout time.Duration) error {
+	if len(a.Spec.Readiness) == 0 {
+		return nil
+	}
+	klog.Infof("Waiting for addon %q to become ready", a.Name)
+	return applier.WaitForReadiness(a.buildChannel().Namespace, a.Spec.Readiness, timeout)
+}
+
 func (a *Addon) GetRequiredUpdates(k8sClient kubernetes.Interface) (*AddonUpdate, error) {
+	if a.Spec.Chart != nil {
+		if err := a.renderChart(k8sClient); err != nil {
+			return nil, fmt.Errorf("error rendering chart for addon %q: %v", a.Name, err)
+		}
+	}
+
 	newVersion := a.ChannelVersion()
 
 	channel := a.buildChannel()
@@ -126,7 +152,9 @@ func (a *Addon) GetManifestFullUrl() (*url.URL, error) {
 	return manifestURL, nil
 }
 
-func (a *Addon) EnsureUpdated(k8sClient kubernetes.Interface) (*AddonUpdate, error) {
+// EnsureUpdated applies the addon if an update is required, labelling each object with the addon name and version.
+// If pruneOptions is not nil, objects applied by the previous version but no longer in the manifest are removed.
+func (a *Addon) EnsureUpdated(k8sClient kubernetes.Interface, applier *Applier, pruneOptions *PruneOptions) (*AddonUpdate, error) {
 	required, err := a.GetRequiredUpdates(k8sClient)
 	if err != nil {
 		return nil, err
@@ -134,22 +162,110 @@ func (a *Addon) EnsureUpdated(k8sClient kubernetes.Interface) (*AddonUpdate, err
 	if required == nil {
 		return nil, nil
 	}
-	manifestURL, err := a.GetManifestFullUrl()
+	if err := a.apply(k8sClient, applier, pruneOptions, required.ExistingVersion); err != nil {
+		return nil, err
+	}
+	return required, nil
+}
+
+// Repair reapplies the addon, restoring any objects that no longer match the manifest
+func (a *Addon) Repair(k8sClient kubernetes.Interface, applier *Applier) error {
+	existingVersion, err := a.buildChannel().GetInstalledVersion(k8sClient)
+	if err != nil {
+		return err
+	}
+	return a.apply(k8sClient, applier, nil, existingVersion)
+}
+
+// CheckDrift compares the live objects of the addon against the manifest, and checks its workloads are ready
+func (a *Addon) CheckDrift(k8sClient kubernetes.Interface, applier *Applier) ([]ObjectProblem, error) {
+	data, namespace, source, err := a.loadManifest(k8sClient)
 	if err != nil {
 		return nil, err
 	}
-	klog.Infof("Applying update from %q", manifestURL)
+	problems, err := applier.CheckDrift(data, namespace, AddonLabels(a.Name, a.Spec.Version))
+	if err != nil {
+		return nil, fmt.Errorf("error comparing objects with %s: %v", source, err)
+	}
+	return problems, nil
+}
+
+// loadManifest returns the manifest of the addon, rendering the chart of a chart addon,
+// along with the default namespace for its objects and a description of its source for messages
+func (a *Addon) loadManifest(k8sClient kubernetes.Interface) ([]byte, string, string, error) {
+	if a.Spec.Chart != nil {
+		if err := a.renderChart(k8sClient); err != nil {
+			return nil, "", "", fmt.Errorf("error rendering chart for addon %q: %v", a.Name, err)
+		}
+		return a.rendered, a.releaseNamespace(), "chart " + a.Spec.Chart.Name, nil
+	}
 
-	err = Apply(manifestURL.String())
+	manifestURL, err := a.GetManifestFullUrl()
+	if err != nil {
+		return nil, "", "", err
+	}
+	source := fmt.Sprintf("%q", manifestURL)
+	data, err := vfs.Context.ReadFile(manifestURL.String())
+	if err != nil {
+		return nil, "", "", fmt.Errorf("error reading manifest %s: %v", source, err)
+	}
+	return data, "", source, nil
+}
+
+// apply applies the manifest of the addon and records the installed version, pruning objects
+// which were part of the existing version if pruneOptions is not nil
+func (a *Addon) apply(k8sClient kubernetes.Interface, applier *Applier, pruneOptions *PruneOptions, existingVersion *ChannelVersion) error {
+	data, namespace, source, err := a.loadManifest(k8sClient)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Applying update from %s", source)
+
+	if a.Spec.Chart != nil {
+		if err := a.ensureNamespace(k8sClient); err != nil {
+			return err
+		}
+	}
+	inventory, err := applier.ApplyManifest(data, namespace, AddonLabels(a.Name, a.Spec.Version))
 	if err != nil {
-		return nil, fmt.Errorf("error applying update from %q: %v", manifestURL, err)
+		return fmt.Errorf("error applying update from %s: %v", source, err)
 	}
 
+	var previousInventory []ObjectReference
+	if existingVersion != nil {
+		previousInventory = existingVersion.Inventory
+	}
+
+	var pruneErr error
+	if pruneOptions != nil {
+		var retained []ObjectReference
+		retained, pruneErr = applier.Prune(a.Name, previousInventory, inventory, pruneOptions)
+		inventory = append(inventory, retained...)
+	} else {
+		// Remember the objects we did not prune, so that they can be pruned in future
+		keep := make(map[ObjectReference]bool)
+		for _, ref := range inventory {
+			keep[ref] = true
+		}
+		for _, ref := range previousInventory {
+			if !keep[ref] {
+				inventory = append(inventory, ref)
+			}
+		}
+	}
+
+	version := a.ChannelVersion()
+	version.Inventory = inventory
+
 	channel := a.buildChannel()
-	err = channel.SetInstalledVersion(k8sClient, a.ChannelVersion())
+	err = channel.SetInstalledVersion(k8sClient, version)
 	if err != nil {
-		return nil, fmt.Errorf("error applying annotation to record addon installation: %v", err)
+		return fmt.Errorf("error applying annotation to record addon installation: %v", err)
 	}
 
-	return required, nil
+	if pruneErr != nil {
+		return fmt.Errorf("error pruning objects removed from %s: %v", source, pruneErr)
+	}
+
+	return nil
 }
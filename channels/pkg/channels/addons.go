@@ -57,6 +57,14 @@ func ParseAddons(name string, location *url.URL, data []byte) (*Addons, error) {
 		}
 	}
 
+	for _, addon := range apiObject.Spec.Addons {
+		for _, gate := range addon.Readiness {
+			if err := ValidateReadinessGate(gate); err != nil {
+				return nil, fmt.Errorf("error parsing addons: %v", err)
+			}
+		}
+	}
+
 	return &Addons{ChannelName: name, ChannelLocation: *location, APIObject: apiObject}, nil
 }
 
@@ -17,60 +17,476 @@ limitations under the License.
 package channels
 
 import (
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"os/exec"
-	"path"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/klog"
+	"k8s.io/kops/pkg/kubemanifest"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
-// Apply calls kubectl apply to apply the manifest.
-// We will likely in future change this to create things directly (or more likely embed this logic into kubectl itself)
-func Apply(manifest string) error {
-	// We copy the manifest to a temp file because it is likely e.g. an s3 URL, which kubectl can't read
+const (
+	// LabelAddonName is the label applied to every object of an addon, holding the name of the addon
+	LabelAddonName = "addon.kops.k8s.io/name"
+	// LabelAddonVersion is the label applied to every object of an addon, holding the version of the addon
+	LabelAddonVersion = "addon.kops.k8s.io/version"
+
+	// lastAppliedConfigAnnotation records the configuration we applied, for computing the next patch.
+	// We share the annotation with kubectl apply, so that objects previously applied with kubectl are updated correctly.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// mappingBackoff controls how long we wait for the kind of an object to become known to the apiserver,
+// for example for a custom resource whose CustomResourceDefinition was created earlier in the same manifest
+var mappingBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Steps:    5,
+}
+
+// Applier applies manifests to the cluster in-process, creating or patching each object through the dynamic client
+type Applier struct {
+	client    dynamic.Interface
+	discovery discovery.DiscoveryInterface
+
+	mapper meta.RESTMapper
+}
+
+// NewApplier is the constructor for an Applier
+func NewApplier(client dynamic.Interface, discovery discovery.DiscoveryInterface) *Applier {
+	return &Applier{
+		client:    client,
+		discovery: discovery,
+	}
+}
+
+// AddonLabels returns the labels that identify the objects belonging to a version of an addon
+func AddonLabels(name string, version *string) map[string]string {
+	labels := make(map[string]string)
+	if errs := validation.IsValidLabelValue(name); len(errs) == 0 {
+		labels[LabelAddonName] = name
+	} else {
+		klog.Warningf("not labelling objects with addon name %q: %v", name, errs)
+	}
+	if version != nil {
+		if errs := validation.IsValidLabelValue(*version); len(errs) == 0 {
+			labels[LabelAddonVersion] = *version
+		} else {
+			klog.Warningf("not labelling objects with addon version %q: %v", *version, errs)
+		}
+	}
+	return labels
+}
+
+// Apply reads the manifest from the specified location and applies every object in it, adding the labels to each.
+// Errors applying individual objects do not stop the others from being applied; they are returned together.
+// The references to the objects in the manifest are returned, to be recorded as the inventory of the addon.
+func (a *Applier) Apply(manifest string, labels map[string]string) ([]ObjectReference, error) {
 	data, err := vfs.Context.ReadFile(manifest)
 	if err != nil {
-		return fmt.Errorf("error reading manifest: %v", err)
+		return nil, fmt.Errorf("error reading manifest: %v", err)
+	}
+
+	return a.ApplyManifest(data, "", labels)
+}
+
+// ApplyManifest applies every object in the manifest, adding the labels to each.
+// Namespaced objects that do not specify a namespace are created in the specified namespace, or "default" if it is empty.
+func (a *Applier) ApplyManifest(data []byte, namespace string, labels map[string]string) ([]ObjectReference, error) {
+	objects, err := parseManifestObjects(data)
+	if err != nil {
+		return nil, err
 	}
 
-	tmpDir, err := ioutil.TempDir("", "channel")
+	return a.ApplyObjects(objects, namespace, labels)
+}
+
+// parseManifestObjects parses the objects in a manifest, expanding any lists
+func parseManifestObjects(data []byte) ([]*unstructured.Unstructured, error) {
+	manifests, err := kubemanifest.LoadManifestsFrom(data)
 	if err != nil {
-		return fmt.Errorf("error creating temp dir: %v", err)
+		return nil, fmt.Errorf("error parsing manifest: %v", err)
 	}
 
-	defer func() {
-		if err := os.RemoveAll(tmpDir); err != nil {
-			klog.Warningf("error deleting temp dir %q: %v", tmpDir, err)
+	var objects []*unstructured.Unstructured
+	for _, m := range manifests {
+		o, err := m.ToObject()
+		if err != nil {
+			return nil, err
+		}
+		if o == nil {
+			continue
 		}
-	}()
+		if o.IsList() {
+			if err := o.EachListItem(func(item runtime.Object) error {
+				objects = append(objects, item.(*unstructured.Unstructured))
+				return nil
+			}); err != nil {
+				return nil, fmt.Errorf("error expanding list in manifest: %v", err)
+			}
+			continue
+		}
+		objects = append(objects, o)
+	}
+	return objects, nil
+}
 
-	localManifestFile := path.Join(tmpDir, "manifest.yaml")
-	if err := ioutil.WriteFile(localManifestFile, data, 0600); err != nil {
-		return fmt.Errorf("error writing temp file: %v", err)
+// ApplyObjects creates or patches each of the objects, adding the labels to each.
+// Namespaces and CustomResourceDefinitions are applied first, as other objects may depend on them.
+// Namespaced objects that do not specify a namespace are created in the specified namespace, or "default" if it is empty.
+func (a *Applier) ApplyObjects(objects []*unstructured.Unstructured, namespace string, labels map[string]string) ([]ObjectReference, error) {
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
 	}
 
-	_, err = execKubectl("apply", "-f", localManifestFile)
-	return err
+	sorted := make([]*unstructured.Unstructured, len(objects))
+	copy(sorted, objects)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return applyPriority(sorted[i].GroupVersionKind().GroupKind()) < applyPriority(sorted[j].GroupVersionKind().GroupKind())
+	})
+
+	var refs []ObjectReference
+	var errs []error
+	for _, o := range sorted {
+		ref, err := a.applyObject(o, namespace, labels)
+		if ref != nil {
+			refs = append(refs, *ref)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error applying %s %s: %v", o.GetKind(), describeObject(o), err))
+		}
+	}
+	return refs, utilerrors.NewAggregate(errs)
 }
 
-func execKubectl(args ...string) (string, error) {
-	kubectlPath := "kubectl" // Assume in PATH
-	cmd := exec.Command(kubectlPath, args...)
-	env := os.Environ()
-	cmd.Env = env
+// applyPriority orders kinds so that those that others depend on are applied first
+func applyPriority(gk schema.GroupKind) int {
+	switch gk {
+	case schema.GroupKind{Kind: "Namespace"}:
+		return 0
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return 1
+	default:
+		return 2
+	}
+}
 
-	human := strings.Join(cmd.Args, " ")
-	klog.V(2).Infof("Running command: %s", human)
-	output, err := cmd.CombinedOutput()
+// describeObject returns the namespace/name of an object, for messages
+func describeObject(o *unstructured.Unstructured) string {
+	if o.GetNamespace() == "" {
+		return o.GetName()
+	}
+	return o.GetNamespace() + "/" + o.GetName()
+}
+
+// applyObject creates the object if it does not exist, otherwise patches it with the changes since it was last applied.
+// The reference to the object is returned once its kind has been resolved, even if applying it then fails.
+func (a *Applier) applyObject(o *unstructured.Unstructured, namespace string, labels map[string]string) (*ObjectReference, error) {
+	o, client, ref, err := a.prepareObject(o, namespace, labels)
+	if err != nil {
+		return ref, err
+	}
+
+	current, err := client.Get(o.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return ref, fmt.Errorf("error reading current state: %v", err)
+		}
+
+		klog.V(2).Infof("creating %s %s", o.GetKind(), describeObject(o))
+		if _, err := client.Create(o, metav1.CreateOptions{}); err != nil {
+			return ref, fmt.Errorf("error creating: %v", err)
+		}
+		return ref, nil
+	}
+
+	patch, patchType, err := buildApplyPatch(current, o)
+	if err != nil {
+		return ref, err
+	}
+	if patch == nil {
+		klog.V(4).Infof("%s %s is unchanged", o.GetKind(), describeObject(o))
+		return ref, nil
+	}
+
+	klog.V(2).Infof("patching %s %s", o.GetKind(), describeObject(o))
+	if _, err := client.Patch(o.GetName(), patchType, patch, metav1.UpdateOptions{}); err != nil {
+		return ref, fmt.Errorf("error patching: %v", err)
+	}
+	return ref, nil
+}
+
+// prepareObject builds the object as it is to be applied, with the labels, namespace and last-applied
+// annotation set, and returns it along with a client for its resource.
+// The reference to the object is returned once its kind has been resolved.
+func (a *Applier) prepareObject(o *unstructured.Unstructured, namespace string, labels map[string]string) (*unstructured.Unstructured, dynamic.ResourceInterface, *ObjectReference, error) {
+	o = o.DeepCopy()
+	if o.GetName() == "" {
+		return nil, nil, nil, fmt.Errorf("object has no name")
+	}
+
+	if len(labels) != 0 {
+		objectLabels := o.GetLabels()
+		if objectLabels == nil {
+			objectLabels = make(map[string]string)
+		}
+		for k, v := range labels {
+			objectLabels[k] = v
+		}
+		o.SetLabels(objectLabels)
+	}
+
+	mapping, err := a.restMapping(o.GroupVersionKind())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var client dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if o.GetNamespace() == "" {
+			o.SetNamespace(namespace)
+		}
+		client = a.client.Resource(mapping.Resource).Namespace(o.GetNamespace())
+	} else {
+		o.SetNamespace("")
+		client = a.client.Resource(mapping.Resource)
+	}
+
+	ref := &ObjectReference{
+		Group:     mapping.GroupVersionKind.Group,
+		Kind:      mapping.GroupVersionKind.Kind,
+		Namespace: o.GetNamespace(),
+		Name:      o.GetName(),
+	}
+
+	// Record what we applied, without the record itself
+	annotations := o.GetAnnotations()
+	delete(annotations, lastAppliedConfigAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	o.SetAnnotations(annotations)
+	modified, err := o.MarshalJSON()
 	if err != nil {
-		klog.Infof("error running %s", human)
-		klog.Info(string(output))
-		return string(output), fmt.Errorf("error running kubectl")
+		return nil, nil, ref, err
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[lastAppliedConfigAnnotation] = string(modified)
+	o.SetAnnotations(annotations)
+
+	return o, client, ref, nil
+}
+
+// restMapping maps the kind to a resource, refreshing the discovery information if the kind is not (yet) known
+func (a *Applier) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	var mapping *meta.RESTMapping
+	var lastErr error
+	err := wait.ExponentialBackoff(mappingBackoff, func() (bool, error) {
+		mapper, err := a.restMapper()
+		if err != nil {
+			return false, err
+		}
+
+		m, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err == nil {
+			mapping = m
+			return true, nil
+		}
+		if !meta.IsNoMatchError(err) {
+			return false, err
+		}
+
+		// Discovery may be stale, e.g. if we just created a CustomResourceDefinition
+		klog.V(2).Infof("kind %v not yet known to apiserver; will retry", gvk)
+		lastErr = err
+		a.mapper = nil
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return nil, lastErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// restMapper returns the mapper from kinds to resources, querying the apiserver if we do not yet have one
+func (a *Applier) restMapper() (meta.RESTMapper, error) {
+	if a.mapper == nil {
+		groupResources, err := restmapper.GetAPIGroupResources(a.discovery)
+		if err != nil {
+			return nil, fmt.Errorf("error querying api resources: %v", err)
+		}
+		a.mapper = restmapper.NewDiscoveryRESTMapper(groupResources)
+	}
+	return a.mapper, nil
+}
+
+// buildApplyPatch builds the patch that changes the current object to the desired object, the way kubectl apply does.
+// Fields that were in the previously applied configuration but are no longer desired are removed;
+// fields set by others (e.g. defaults, status and entries added by admission controllers) are left untouched.
+// Kinds known to the client-go scheme are patched with a strategic merge patch, so that lists are merged by their
+// merge keys rather than replaced; other kinds (e.g. custom resources) are patched with a JSON merge patch.
+// Returns a nil patch if no change is needed.
+func buildApplyPatch(current *unstructured.Unstructured, desired *unstructured.Unstructured) ([]byte, types.PatchType, error) {
+	var original []byte
+	if s := current.GetAnnotations()[lastAppliedConfigAnnotation]; s != "" {
+		if json.Valid([]byte(s)) {
+			original = []byte(s)
+		} else {
+			klog.Warningf("ignoring unparseable %s annotation on %s", lastAppliedConfigAnnotation, describeObject(current))
+		}
+	}
+
+	modified, err := desired.MarshalJSON()
+	if err != nil {
+		return nil, "", err
+	}
+	existing, err := current.MarshalJSON()
+	if err != nil {
+		return nil, "", err
+	}
+
+	versionedObject, err := scheme.Scheme.New(desired.GroupVersionKind())
+	if err != nil {
+		if !runtime.IsNotRegisteredError(err) {
+			return nil, "", fmt.Errorf("error getting type of %s: %v", desired.GroupVersionKind(), err)
+		}
+
+		patch, err := buildJSONMergePatch(original, modified, existing)
+		if err != nil || patch == nil {
+			return nil, "", err
+		}
+		return patch, types.MergePatchType, nil
+	}
+
+	lookupPatchMeta, err := strategicpatch.NewPatchMetaFromStruct(versionedObject)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting patch metadata of %s: %v", desired.GroupVersionKind(), err)
+	}
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, existing, lookupPatchMeta, true)
+	if err != nil {
+		return nil, "", fmt.Errorf("error building patch: %v", err)
+	}
+	changes, err := strategicPatchHasChanges(patch)
+	if err != nil {
+		return nil, "", err
+	}
+	if !changes {
+		return nil, "", nil
+	}
+	return patch, types.StrategicMergePatchType, nil
+}
+
+// strategicPatchHasChanges returns false if the strategic merge patch is empty, or only sets the order of list
+// elements (which is added whenever the live object holds list entries that are not in the manifest)
+func strategicPatchHasChanges(patch []byte) (bool, error) {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(patch, &m); err != nil {
+		return false, fmt.Errorf("error parsing patch: %v", err)
+	}
+
+	var hasChanges func(m map[string]interface{}) bool
+	hasChanges = func(m map[string]interface{}) bool {
+		for k, v := range m {
+			if strings.HasPrefix(k, "$setElementOrder/") {
+				continue
+			}
+			if child, ok := v.(map[string]interface{}); ok && len(child) != 0 {
+				if hasChanges(child) {
+					return true
+				}
+				continue
+			}
+			return true
+		}
+		return false
+	}
+	return hasChanges(m), nil
+}
+
+// buildJSONMergePatch builds a three-way JSON merge patch, for kinds without the metadata for a strategic merge patch.
+// Returns nil if no change is needed.
+func buildJSONMergePatch(original, modified, current []byte) ([]byte, error) {
+	originalMap := make(map[string]interface{})
+	if len(original) != 0 {
+		if err := json.Unmarshal(original, &originalMap); err != nil {
+			return nil, err
+		}
+	}
+	modifiedMap := make(map[string]interface{})
+	if err := json.Unmarshal(modified, &modifiedMap); err != nil {
+		return nil, err
+	}
+	currentMap := make(map[string]interface{})
+	if err := json.Unmarshal(current, &currentMap); err != nil {
+		return nil, err
+	}
+
+	patch := threeWayMergePatch(originalMap, modifiedMap, currentMap)
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(patch)
+}
+
+// threeWayMergePatch computes a JSON merge patch (RFC 7386) which sets the fields of modified that differ in current,
+// and deletes the fields of original that are no longer in modified
+func threeWayMergePatch(original, modified, current map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+
+	for k, modifiedValue := range modified {
+		currentValue, found := current[k]
+		if !found {
+			patch[k] = modifiedValue
+			continue
+		}
+
+		modifiedMap, modifiedIsMap := modifiedValue.(map[string]interface{})
+		currentMap, currentIsMap := currentValue.(map[string]interface{})
+		if modifiedIsMap && currentIsMap {
+			originalMap, _ := original[k].(map[string]interface{})
+			if p := threeWayMergePatch(originalMap, modifiedMap, currentMap); len(p) != 0 {
+				patch[k] = p
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(modifiedValue, currentValue) {
+			patch[k] = modifiedValue
+		}
+	}
+
+	for k := range original {
+		if _, found := modified[k]; found {
+			continue
+		}
+		if _, found := current[k]; found {
+			patch[k] = nil
+		}
 	}
 
-	return string(output), err
+	return patch
 }
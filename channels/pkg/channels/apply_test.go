@@ -0,0 +1,420 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+// fakeDynamicClient is an in-memory implementation of dynamic.Interface
+type fakeDynamicClient struct {
+	objects map[string]*unstructured.Unstructured
+	// actions records the verbs and keys of the requests, in order
+	actions []string
+}
+
+var _ dynamic.Interface = &fakeDynamicClient{}
+
+func newFakeDynamicClient() *fakeDynamicClient {
+	return &fakeDynamicClient{objects: make(map[string]*unstructured.Unstructured)}
+}
+
+func (c *fakeDynamicClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &fakeResourceClient{client: c, resource: resource}
+}
+
+type fakeResourceClient struct {
+	client    *fakeDynamicClient
+	resource  schema.GroupVersionResource
+	namespace string
+}
+
+var _ dynamic.NamespaceableResourceInterface = &fakeResourceClient{}
+
+func (c *fakeResourceClient) Namespace(namespace string) dynamic.ResourceInterface {
+	return &fakeResourceClient{client: c.client, resource: c.resource, namespace: namespace}
+}
+
+func (c *fakeResourceClient) key(name string) string {
+	return c.resource.Resource + "/" + c.namespace + "/" + name
+}
+
+func (c *fakeResourceClient) Create(obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	key := c.key(obj.GetName())
+	c.client.actions = append(c.client.actions, "create "+key)
+	if c.client.objects[key] != nil {
+		return nil, errors.NewAlreadyExists(c.resource.GroupResource(), obj.GetName())
+	}
+	c.client.objects[key] = obj.DeepCopy()
+	return obj.DeepCopy(), nil
+}
+
+func (c *fakeResourceClient) Update(obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	key := c.key(obj.GetName())
+	c.client.actions = append(c.client.actions, "update "+key)
+	if c.client.objects[key] == nil {
+		return nil, errors.NewNotFound(c.resource.GroupResource(), obj.GetName())
+	}
+	c.client.objects[key] = obj.DeepCopy()
+	return obj.DeepCopy(), nil
+}
+
+func (c *fakeResourceClient) UpdateStatus(obj *unstructured.Unstructured, options metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	return c.Update(obj, options, "status")
+}
+
+func (c *fakeResourceClient) Delete(name string, options *metav1.DeleteOptions, subresources ...string) error {
+	key := c.key(name)
+	c.client.actions = append(c.client.actions, "delete "+key)
+	if c.client.objects[key] == nil {
+		return errors.NewNotFound(c.resource.GroupResource(), name)
+	}
+	delete(c.client.objects, key)
+	return nil
+}
+
+func (c *fakeResourceClient) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	return fmt.Errorf("DeleteCollection not implemented by fake")
+}
+
+func (c *fakeResourceClient) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	o := c.client.objects[c.key(name)]
+	if o == nil {
+		return nil, errors.NewNotFound(c.resource.GroupResource(), name)
+	}
+	return o.DeepCopy(), nil
+}
+
+func (c *fakeResourceClient) List(opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	prefix := c.resource.Resource + "/" + c.namespace + "/"
+	for k, o := range c.client.objects {
+		if strings.HasPrefix(k, prefix) {
+			list.Items = append(list.Items, *o.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+func (c *fakeResourceClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	return nil, fmt.Errorf("Watch not implemented by fake")
+}
+
+func (c *fakeResourceClient) Patch(name string, pt types.PatchType, data []byte, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	key := c.key(name)
+	c.client.actions = append(c.client.actions, "patch "+key)
+	o := c.client.objects[key]
+	if o == nil {
+		return nil, errors.NewNotFound(c.resource.GroupResource(), name)
+	}
+	current, err := o.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var patched []byte
+	switch pt {
+	case types.MergePatchType:
+		patched, err = jsonpatch.MergePatch(current, data)
+	case types.StrategicMergePatchType:
+		versionedObject, err := scheme.Scheme.New(o.GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		patched, err = strategicpatch.StrategicMergePatch(current, data, versionedObject)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("patch type %q not implemented by fake", pt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	updated := &unstructured.Unstructured{}
+	if err := updated.UnmarshalJSON(patched); err != nil {
+		return nil, err
+	}
+	c.client.objects[key] = updated
+	return updated.DeepCopy(), nil
+}
+
+func newFakeDiscovery() discovery.DiscoveryInterface {
+	return &fakediscovery.FakeDiscovery{
+		Fake: &k8stesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{
+						{Name: "namespaces", Kind: "Namespace", Namespaced: false},
+						{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+						{Name: "serviceaccounts", Kind: "ServiceAccount", Namespaced: true},
+					},
+				},
+				{
+					GroupVersion: "apps/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "deployments", Kind: "Deployment", Namespaced: true},
+						{Name: "daemonsets", Kind: "DaemonSet", Namespaced: true},
+					},
+				},
+				{
+					GroupVersion: "apiextensions.k8s.io/v1beta1",
+					APIResources: []metav1.APIResource{
+						{Name: "customresourcedefinitions", Kind: "CustomResourceDefinition", Namespaced: false},
+					},
+				},
+			},
+		},
+	}
+}
+
+func parseObjects(t *testing.T, manifest string) []*unstructured.Unstructured {
+	manifests, err := kubemanifest.LoadManifestsFrom([]byte(manifest))
+	if err != nil {
+		t.Fatalf("error parsing manifest: %v", err)
+	}
+	var objects []*unstructured.Unstructured
+	for _, m := range manifests {
+		o, err := m.ToObject()
+		if err != nil {
+			t.Fatalf("error converting manifest: %v", err)
+		}
+		if o != nil {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+func TestApplyObjects(t *testing.T) {
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+	version := "1.0.0"
+	labels := AddonLabels("test.addons.k8s.io", &version)
+
+	objects := parseObjects(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: test
+data:
+  a: "1"
+  b: "2"
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+`)
+	if _, err := applier.ApplyObjects(objects, "", labels); err != nil {
+		t.Fatalf("unexpected error from first apply: %v", err)
+	}
+
+	// The namespace must be created before the objects in it
+	expectedActions := []string{"create namespaces//test", "create configmaps/test/config"}
+	if fmt.Sprintf("%v", client.actions) != fmt.Sprintf("%v", expectedActions) {
+		t.Fatalf("unexpected actions; expected %v, got %v", expectedActions, client.actions)
+	}
+
+	configMap := client.objects["configmaps/test/config"]
+	if configMap.GetLabels()[LabelAddonName] != "test.addons.k8s.io" || configMap.GetLabels()[LabelAddonVersion] != "1.0.0" {
+		t.Errorf("addon labels not applied; got %v", configMap.GetLabels())
+	}
+	if configMap.GetAnnotations()[lastAppliedConfigAnnotation] == "" {
+		t.Errorf("last applied configuration was not recorded")
+	}
+
+	// Simulate a change made by another controller, which apply should preserve
+	data, _, _ := unstructured.NestedStringMap(configMap.Object, "data")
+	data["c"] = "3"
+	unstructured.SetNestedStringMap(configMap.Object, data, "data")
+
+	// Re-applying the same objects should be a no-op
+	client.actions = nil
+	if _, err := applier.ApplyObjects(objects, "", labels); err != nil {
+		t.Fatalf("unexpected error from re-apply: %v", err)
+	}
+	if len(client.actions) != 0 {
+		t.Errorf("expected no changes on re-apply, got %v", client.actions)
+	}
+
+	// Change a value and remove a field
+	objects = parseObjects(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: test
+data:
+  a: "10"
+`)
+	client.actions = nil
+	version = "1.1.0"
+	if _, err := applier.ApplyObjects(objects, "", AddonLabels("test.addons.k8s.io", &version)); err != nil {
+		t.Fatalf("unexpected error from update: %v", err)
+	}
+	expectedActions = []string{"patch configmaps/test/config"}
+	if fmt.Sprintf("%v", client.actions) != fmt.Sprintf("%v", expectedActions) {
+		t.Fatalf("unexpected actions; expected %v, got %v", expectedActions, client.actions)
+	}
+
+	configMap = client.objects["configmaps/test/config"]
+	data, _, _ = unstructured.NestedStringMap(configMap.Object, "data")
+	expectedData := map[string]string{"a": "10", "c": "3"}
+	if fmt.Sprintf("%v", data) != fmt.Sprintf("%v", expectedData) {
+		t.Errorf("unexpected data after update; expected %v, got %v", expectedData, data)
+	}
+	if configMap.GetLabels()[LabelAddonVersion] != "1.1.0" {
+		t.Errorf("addon version label not updated; got %v", configMap.GetLabels())
+	}
+}
+
+func TestApplyObjectsAggregatesErrors(t *testing.T) {
+	mappingBackoff = wait.Backoff{Steps: 1}
+
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+
+	objects := parseObjects(t, `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: unknown
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: sa
+---
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: unknown
+`)
+	_, err := applier.ApplyObjects(objects, "", nil)
+	if err == nil {
+		t.Fatalf("expected error applying unknown kinds")
+	}
+	for _, s := range []string{"Widget", "Gadget"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to mention %q, got %v", s, err)
+		}
+	}
+
+	// Objects of known kinds are still applied
+	if client.objects["serviceaccounts/default/sa"] == nil {
+		t.Errorf("expected ServiceAccount to be created in the default namespace; actions were %v", client.actions)
+	}
+}
+
+func TestApplyObjectsPreservesServerDefaults(t *testing.T) {
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+
+	manifest := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: kube-system
+spec:
+  selector:
+    matchLabels:
+      k8s-app: app
+  template:
+    metadata:
+      labels:
+        k8s-app: app
+    spec:
+      containers:
+      - name: app
+        image: app:1.0.0
+        env:
+        - name: A
+          value: "1"
+`
+	if _, err := applier.ApplyObjects(parseObjects(t, manifest), "", nil); err != nil {
+		t.Fatalf("unexpected error from first apply: %v", err)
+	}
+
+	// Simulate the apiserver defaulting fields, and an admission controller injecting a container
+	deployment := client.objects["deployments/kube-system/app"]
+	containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	container["imagePullPolicy"] = "IfNotPresent"
+	container["terminationMessagePath"] = "/dev/termination-log"
+	containers = append(containers, map[string]interface{}{"name": "sidecar", "image": "sidecar:1.0.0"})
+	unstructured.SetNestedSlice(deployment.Object, containers, "spec", "template", "spec", "containers")
+	unstructured.SetNestedField(deployment.Object, int64(1), "spec", "replicas")
+
+	// Re-applying the same objects should be a no-op
+	client.actions = nil
+	if _, err := applier.ApplyObjects(parseObjects(t, manifest), "", nil); err != nil {
+		t.Fatalf("unexpected error from re-apply: %v", err)
+	}
+	if len(client.actions) != 0 {
+		t.Errorf("expected no changes on re-apply, got %v", client.actions)
+	}
+
+	// Changing the image should patch only the image
+	client.actions = nil
+	if _, err := applier.ApplyObjects(parseObjects(t, strings.Replace(manifest, "app:1.0.0", "app:1.1.0", 1)), "", nil); err != nil {
+		t.Fatalf("unexpected error from update: %v", err)
+	}
+	expectedActions := []string{"patch deployments/kube-system/app"}
+	if fmt.Sprintf("%v", client.actions) != fmt.Sprintf("%v", expectedActions) {
+		t.Fatalf("unexpected actions; expected %v, got %v", expectedActions, client.actions)
+	}
+
+	deployment = client.objects["deployments/kube-system/app"]
+	containers, _, _ = unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	if len(containers) != 2 {
+		t.Fatalf("expected the injected container to be kept, got %v", containers)
+	}
+	container = containers[0].(map[string]interface{})
+	if container["image"] != "app:1.1.0" {
+		t.Errorf("image not updated; got %v", container["image"])
+	}
+	if container["imagePullPolicy"] != "IfNotPresent" || container["terminationMessagePath"] != "/dev/termination-log" {
+		t.Errorf("server defaulted fields were not kept; got %v", container)
+	}
+	if env, _, _ := unstructured.NestedSlice(container, "env"); len(env) != 1 {
+		t.Errorf("unexpected env %v", env)
+	}
+	if replicas, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas"); replicas != 1 {
+		t.Errorf("server defaulted replicas were not kept; got %d", replicas)
+	}
+}
@@ -41,6 +41,10 @@ type ChannelVersion struct {
 	Channel      *string `json:"channel,omitempty"`
 	Id           string  `json:"id,omitempty"`
 	ManifestHash string  `json:"manifestHash,omitempty"`
+
+	// Inventory records the objects applied for this version of the addon, so that any which are
+	// removed in a later version can be pruned
+	Inventory []ObjectReference `json:"inventory,omitempty"`
 }
 
 func stringValue(s *string) string {
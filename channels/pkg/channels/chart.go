@@ -0,0 +1,176 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"net/url"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+	"k8s.io/kops/channels/pkg/chart"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// resolveLocation resolves a location relative to the channel
+func (a *Addon) resolveLocation(location string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("error parsing location %q: %v", location, err)
+	}
+	if !u.IsAbs() {
+		u = a.ChannelLocation.ResolveReference(u)
+	}
+	return u.String(), nil
+}
+
+// fetchChart reads the chart archive, from the repository if one is specified
+func (a *Addon) fetchChart() (*chart.Chart, error) {
+	spec := a.Spec.Chart
+
+	if spec.Repository == "" {
+		location, err := a.resolveLocation(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		klog.V(2).Infof("Reading chart archive from %q", location)
+		archive, err := vfs.Context.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("error reading chart archive %q: %v", location, err)
+		}
+		return chart.LoadArchive(archive)
+	}
+
+	repository, err := a.resolveLocation(spec.Repository)
+	if err != nil {
+		return nil, err
+	}
+	index, err := vfs.Context.ReadFile(chart.IndexURL(repository))
+	if err != nil {
+		return nil, fmt.Errorf("error reading index of chart repository %q: %v", repository, err)
+	}
+	entry, err := chart.FindInIndex(repository, index, spec.Name, spec.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	klog.V(2).Infof("Reading chart archive from %q", entry.URLs[0])
+	archive, err := vfs.Context.ReadFile(entry.URLs[0])
+	if err != nil {
+		return nil, fmt.Errorf("error reading chart archive %q: %v", entry.URLs[0], err)
+	}
+	if err := entry.VerifyDigest(archive); err != nil {
+		return nil, err
+	}
+	return chart.LoadArchive(archive)
+}
+
+// releaseNamespace returns the namespace the chart of the addon is installed into
+func (a *Addon) releaseNamespace() string {
+	if a.Spec.Chart.Namespace != "" {
+		return a.Spec.Chart.Namespace
+	}
+	return a.buildChannel().Namespace
+}
+
+// ensureNamespace creates the namespace the chart is installed into, if it does not exist, as helm does
+func (a *Addon) ensureNamespace(k8sClient kubernetes.Interface) error {
+	namespace := a.releaseNamespace()
+	_, err := k8sClient.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("error querying namespace %q: %v", namespace, err)
+	}
+
+	klog.Infof("Creating namespace %q for chart %s", namespace, a.Spec.Chart.Name)
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	if _, err := k8sClient.CoreV1().Namespaces().Create(ns); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating namespace %q: %v", namespace, err)
+	}
+	return nil
+}
+
+// renderChart renders the chart of the addon for the cluster, recording the rendered manifest and its hash
+func (a *Addon) renderChart(k8sClient kubernetes.Interface) error {
+	if a.rendered != nil {
+		return nil
+	}
+
+	c, err := a.fetchChart()
+	if err != nil {
+		return err
+	}
+
+	serverVersion, err := k8sClient.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("error querying kubernetes version: %v", err)
+	}
+	groups, err := k8sClient.Discovery().ServerGroups()
+	if err != nil {
+		return fmt.Errorf("error querying api groups: %v", err)
+	}
+	var apiVersions chart.VersionSet
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			apiVersions = append(apiVersions, version.GroupVersion)
+		}
+	}
+
+	releaseName := a.Spec.Chart.ReleaseName
+	if releaseName == "" {
+		releaseName = c.Metadata.Name
+	}
+
+	options := &chart.RenderOptions{
+		Release: chart.Release{
+			Name:      releaseName,
+			Namespace: a.releaseNamespace(),
+			Service:   "channels",
+			Revision:  1,
+			IsInstall: true,
+		},
+		Capabilities: chart.Capabilities{
+			KubeVersion: chart.KubeVersion{
+				Major:      serverVersion.Major,
+				Minor:      serverVersion.Minor,
+				GitVersion: serverVersion.GitVersion,
+			},
+			APIVersions: apiVersions,
+		},
+		Values: a.Spec.Chart.Values,
+	}
+
+	rendered, err := c.Render(options)
+	if err != nil {
+		return fmt.Errorf("error rendering chart %s: %v", c.Metadata.Name, err)
+	}
+
+	hash, err := utils.HashString(string(rendered))
+	if err != nil {
+		return fmt.Errorf("error hashing rendered chart: %v", err)
+	}
+
+	a.rendered = rendered
+	a.renderedHash = hash
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+func writeChartArchive(t *testing.T, p string, files map[string]string) {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+	if err := ioutil.WriteFile(p, b.Bytes(), 0644); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+}
+
+func TestChartAddon(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chart")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeChartArchive(t, filepath.Join(dir, "example-0.1.0.tgz"), map[string]string{
+		"example/Chart.yaml":  "name: example\nversion: 0.1.0\n",
+		"example/values.yaml": "message: default\n",
+		"example/templates/configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  message: {{ .Values.message }}
+`,
+	})
+
+	channelLocation, err := url.Parse("file://" + filepath.Join(dir, "channel.yaml"))
+	if err != nil {
+		t.Fatalf("error parsing url: %v", err)
+	}
+
+	k8sClient := fake.NewSimpleClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+
+	version := "0.1.0"
+	newAddon := func(message string) *Addon {
+		return &Addon{
+			Name:            "example.addons.k8s.io",
+			ChannelName:     "test",
+			ChannelLocation: *channelLocation,
+			Spec: &api.AddonSpec{
+				Version: &version,
+				Chart: &api.ChartSpec{
+					Name:      "example-0.1.0.tgz",
+					Namespace: "example-system",
+					Values:    map[string]interface{}{"message": message},
+				},
+			},
+		}
+	}
+
+	update, err := newAddon("hello").EnsureUpdated(k8sClient, applier, nil)
+	if err != nil {
+		t.Fatalf("error applying chart addon: %v", err)
+	}
+	if update == nil {
+		t.Fatalf("expected chart addon to be applied")
+	}
+
+	if _, err := k8sClient.CoreV1().Namespaces().Get("example-system", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected release namespace to be created: %v", err)
+	}
+	configMap := client.objects["configmaps/example-system/example"]
+	if configMap == nil {
+		t.Fatalf("expected ConfigMap to be created in the release namespace; actions were %v", client.actions)
+	}
+	if configMap.Object["data"].(map[string]interface{})["message"] != "hello" {
+		t.Errorf("unexpected ConfigMap data %v", configMap.Object["data"])
+	}
+
+	// The same values should not require an update
+	update, err = newAddon("hello").GetRequiredUpdates(k8sClient)
+	if err != nil {
+		t.Fatalf("error checking for updates: %v", err)
+	}
+	if update != nil {
+		t.Errorf("expected no update to be required for unchanged values")
+	}
+
+	// Changing the values changes the rendered manifest, which requires an update even though the version is unchanged
+	update, err = newAddon("goodbye").GetRequiredUpdates(k8sClient)
+	if err != nil {
+		t.Fatalf("error checking for updates: %v", err)
+	}
+	if update == nil {
+		t.Errorf("expected an update to be required when values change")
+	}
+}
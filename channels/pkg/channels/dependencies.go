@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+// SortByDependencies orders the addons so that every addon comes after the addons it depends on.
+// Addons that do not depend on each other are ordered by name, so that the order is stable.
+// Dependencies on addons that are not in the list are ignored, as they are assumed to be managed elsewhere.
+func SortByDependencies(addons []*Addon) ([]*Addon, error) {
+	byName := make(map[string]*Addon)
+	for _, a := range addons {
+		byName[a.Name] = a
+	}
+
+	// dependents maps from the name of an addon to the names of the addons that depend on it
+	dependents := make(map[string][]string)
+	pending := make(map[string]int)
+	for _, a := range addons {
+		pending[a.Name] = 0
+		for _, dep := range a.Spec.DependsOn {
+			if byName[dep] == nil {
+				klog.V(2).Infof("addon %q depends on %q, which is not being applied; ignoring", a.Name, dep)
+				continue
+			}
+			dependents[dep] = append(dependents[dep], a.Name)
+			pending[a.Name]++
+		}
+	}
+
+	var ready []string
+	for name, count := range pending {
+		if count == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	var sorted []*Addon
+	for len(ready) != 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+
+		sorted = append(sorted, byName[name])
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(sorted) != len(byName) {
+		var cycle []string
+		for name, count := range pending {
+			if count != 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("addons have circular dependencies: %s", strings.Join(cycle, ", "))
+	}
+
+	return sorted, nil
+}
+
+// DependencyClosure returns the names of the specified addons and of every addon they depend on, directly or
+// through other addons.  Dependencies on addons that are not in the list are ignored, as in SortByDependencies.
+func DependencyClosure(addons []*Addon, names map[string]bool) map[string]bool {
+	byName := make(map[string]*Addon)
+	for _, a := range addons {
+		byName[a.Name] = a
+	}
+
+	closure := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		a := byName[name]
+		if a == nil || closure[name] {
+			return
+		}
+		closure[name] = true
+		for _, dep := range a.Spec.DependsOn {
+			visit(dep)
+		}
+	}
+	for name := range names {
+		visit(name)
+	}
+	return closure
+}
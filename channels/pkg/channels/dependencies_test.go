@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/channels/pkg/api"
+)
+
+func TestSortByDependencies(t *testing.T) {
+	grid := []struct {
+		Addons   map[string][]string
+		Expected string
+		Error    string
+	}{
+		{
+			Addons:   map[string][]string{"b": nil, "a": nil, "c": nil},
+			Expected: "a,b,c",
+		},
+		{
+			Addons: map[string][]string{
+				"coredns":    {"networking"},
+				"networking": nil,
+				"autoscaler": {"coredns"},
+				"core":       nil,
+			},
+			Expected: "core,networking,coredns,autoscaler",
+		},
+		{
+			// Dependencies on addons that are not being applied are ignored
+			Addons:   map[string][]string{"a": {"missing"}, "b": {"a"}},
+			Expected: "a,b",
+		},
+		{
+			Addons: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}, "d": nil},
+			Error:  "circular dependencies: a, b, c",
+		},
+	}
+
+	for _, g := range grid {
+		var addons []*Addon
+		for name, deps := range g.Addons {
+			addons = append(addons, &Addon{Name: name, Spec: &api.AddonSpec{DependsOn: deps}})
+		}
+
+		sorted, err := SortByDependencies(addons)
+		if g.Error != "" {
+			if err == nil || !strings.Contains(err.Error(), g.Error) {
+				t.Errorf("expected error containing %q for %v, got %v", g.Error, g.Addons, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", g.Addons, err)
+			continue
+		}
+
+		var names []string
+		for _, a := range sorted {
+			names = append(names, a.Name)
+		}
+		actual := strings.Join(names, ",")
+		if actual != g.Expected {
+			t.Errorf("unexpected order for %v; expected %q, got %q", g.Addons, g.Expected, actual)
+		}
+	}
+}
+
+func TestDependencyClosure(t *testing.T) {
+	addons := []*Addon{
+		{Name: "networking", Spec: &api.AddonSpec{}},
+		{Name: "coredns", Spec: &api.AddonSpec{DependsOn: []string{"networking"}}},
+		{Name: "autoscaler", Spec: &api.AddonSpec{DependsOn: []string{"coredns", "missing"}}},
+		{Name: "dashboard", Spec: &api.AddonSpec{}},
+	}
+
+	grid := []struct {
+		Names    []string
+		Expected string
+	}{
+		{
+			// A chain of three addons: the dependencies of dependencies are included
+			Names:    []string{"autoscaler"},
+			Expected: "autoscaler,coredns,networking",
+		},
+		{
+			Names:    []string{"coredns"},
+			Expected: "coredns,networking",
+		},
+		{
+			Names:    []string{"dashboard", "networking"},
+			Expected: "dashboard,networking",
+		},
+		{
+			Names:    nil,
+			Expected: "",
+		},
+	}
+
+	for _, g := range grid {
+		names := make(map[string]bool)
+		for _, name := range g.Names {
+			names[name] = true
+		}
+
+		var actual []string
+		for name := range DependencyClosure(addons, names) {
+			actual = append(actual, name)
+		}
+		sort.Strings(actual)
+		if strings.Join(actual, ",") != g.Expected {
+			t.Errorf("unexpected closure of %v; expected %q, got %q", g.Names, g.Expected, strings.Join(actual, ","))
+		}
+	}
+}
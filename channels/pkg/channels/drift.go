@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Problems reported for the objects of an addon
+const (
+	// ProblemMissing is reported for an object in the manifest that does not exist
+	ProblemMissing = "Missing"
+	// ProblemModified is reported for an object that does not match the manifest
+	ProblemModified = "Modified"
+	// ProblemUnhealthy is reported for a workload that is not ready
+	ProblemUnhealthy = "Unhealthy"
+)
+
+// ObjectProblem describes an object of an addon that does not match the manifest, or is not healthy
+type ObjectProblem struct {
+	Object  ObjectReference `json:"object"`
+	Problem string          `json:"problem"`
+	Message string          `json:"message,omitempty"`
+}
+
+// CheckDrift compares the live objects against the objects in the manifest, as they would be applied with the labels,
+// and checks that the workloads are ready.  An object is reported as modified if applying the manifest would change it;
+// as when applying, fields set by others (e.g. defaults set by the apiserver) are not drift.
+func (a *Applier) CheckDrift(data []byte, namespace string, labels map[string]string) ([]ObjectProblem, error) {
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	objects, err := parseManifestObjects(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []ObjectProblem
+	for _, o := range objects {
+		desired, client, ref, err := a.prepareObject(o, namespace, labels)
+		if err != nil {
+			return nil, fmt.Errorf("error checking %s %s: %v", o.GetKind(), describeObject(o), err)
+		}
+
+		current, err := client.Get(desired.GetName(), metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				problems = append(problems, ObjectProblem{Object: *ref, Problem: ProblemMissing})
+				continue
+			}
+			return nil, fmt.Errorf("error reading %s %s: %v", o.GetKind(), describeObject(desired), err)
+		}
+
+		patch, _, err := buildApplyPatch(current, desired)
+		if err != nil {
+			return nil, err
+		}
+		if patch != nil {
+			fields, err := patchedFields(patch)
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) != 0 {
+				problems = append(problems, ObjectProblem{
+					Object:  *ref,
+					Problem: ProblemModified,
+					Message: "fields differ: " + strings.Join(fields, ", "),
+				})
+				continue
+			}
+		}
+
+		if ready, waitingFor := isWorkloadReady(current); !ready {
+			problems = append(problems, ObjectProblem{Object: *ref, Problem: ProblemUnhealthy, Message: waitingFor})
+		}
+	}
+
+	return problems, nil
+}
+
+// isWorkloadReady checks the readiness of the kinds we know how to wait for; other objects are always ready
+func isWorkloadReady(o *unstructured.Unstructured) (bool, string) {
+	kind, found := readinessKinds[o.GetKind()]
+	if !found || kind.groupKind != o.GroupVersionKind().GroupKind() {
+		return true, ""
+	}
+	return kind.ready(o)
+}
+
+// patchedFields returns the paths of the fields changed by a strategic or JSON merge patch,
+// ignoring the change to the record of the last applied configuration and the directives of strategic merge patches
+func patchedFields(patch []byte) ([]string, error) {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(patch, &m); err != nil {
+		return nil, fmt.Errorf("error parsing patch: %v", err)
+	}
+
+	var fields []string
+	var visit func(prefix string, m map[string]interface{})
+	visit = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			path := prefix + k
+			if path == "metadata.annotations."+lastAppliedConfigAnnotation || strings.HasPrefix(k, "$") {
+				continue
+			}
+			if child, ok := v.(map[string]interface{}); ok && len(child) != 0 {
+				visit(path+".", child)
+				continue
+			}
+			fields = append(fields, path)
+		}
+	}
+	visit("", m)
+
+	sort.Strings(fields)
+	return fields, nil
+}
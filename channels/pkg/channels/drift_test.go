@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCheckDrift(t *testing.T) {
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+	version := "1.0.0"
+	labels := AddonLabels("test.addons.k8s.io", &version)
+
+	manifest := []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: test
+data:
+  a: "1"
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: account
+  namespace: test
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: workload
+  namespace: test
+spec:
+  replicas: 1
+`)
+	if _, err := applier.ApplyManifest(manifest, "", labels); err != nil {
+		t.Fatalf("unexpected error applying manifest: %v", err)
+	}
+
+	deployment := client.objects["deployments/test/workload"]
+	unstructured.SetNestedField(deployment.Object, int64(1), "status", "observedGeneration")
+	unstructured.SetNestedField(deployment.Object, int64(1), "status", "updatedReplicas")
+	unstructured.SetNestedField(deployment.Object, int64(1), "status", "availableReplicas")
+
+	problems, err := applier.CheckDrift(manifest, "", labels)
+	if err != nil {
+		t.Fatalf("unexpected error checking drift: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems immediately after apply, got %v", problems)
+	}
+
+	// Fields set by others are not drift, but changes to fields in the manifest are
+	configMap := client.objects["configmaps/test/config"]
+	unstructured.SetNestedStringMap(configMap.Object, map[string]string{"a": "2", "b": "3"}, "data")
+	delete(client.objects, "serviceaccounts/test/account")
+	unstructured.SetNestedField(deployment.Object, int64(0), "status", "availableReplicas")
+
+	problems, err = applier.CheckDrift(manifest, "", labels)
+	if err != nil {
+		t.Fatalf("unexpected error checking drift: %v", err)
+	}
+	expected := []ObjectProblem{
+		{Object: ObjectReference{Kind: "ConfigMap", Namespace: "test", Name: "config"}, Problem: ProblemModified, Message: "fields differ: data.a"},
+		{Object: ObjectReference{Kind: "ServiceAccount", Namespace: "test", Name: "account"}, Problem: ProblemMissing},
+		{Object: ObjectReference{Group: "apps", Kind: "Deployment", Namespace: "test", Name: "workload"}, Problem: ProblemUnhealthy, Message: "0 of 1 updated replicas available"},
+	}
+	if fmt.Sprintf("%v", problems) != fmt.Sprintf("%v", expected) {
+		t.Errorf("unexpected problems;\nexpected %v\ngot      %v", expected, problems)
+	}
+
+	// Reapplying repairs the drift
+	if _, err := applier.ApplyManifest(manifest, "", labels); err != nil {
+		t.Fatalf("unexpected error applying manifest: %v", err)
+	}
+	problems, err = applier.CheckDrift(manifest, "", labels)
+	if err != nil {
+		t.Fatalf("unexpected error checking drift: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Problem != ProblemUnhealthy {
+		t.Errorf("expected only the unhealthy deployment after reapplying, got %v", problems)
+	}
+}
+
+func TestCheckDriftIgnoresServerDefaults(t *testing.T) {
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+
+	manifest := []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: workload
+  namespace: test
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      k8s-app: workload
+  template:
+    metadata:
+      labels:
+        k8s-app: workload
+    spec:
+      containers:
+      - name: workload
+        image: workload:1.0.0
+        ports:
+        - containerPort: 8080
+`)
+	if _, err := applier.ApplyManifest(manifest, "", nil); err != nil {
+		t.Fatalf("unexpected error applying manifest: %v", err)
+	}
+
+	// The fake client does not default fields as the apiserver does, so we set some defaults ourselves
+	deployment := client.objects["deployments/test/workload"]
+	containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	container["imagePullPolicy"] = "IfNotPresent"
+	container["terminationMessagePath"] = "/dev/termination-log"
+	ports, _, _ := unstructured.NestedSlice(container, "ports")
+	ports[0].(map[string]interface{})["protocol"] = "TCP"
+	unstructured.SetNestedSlice(container, ports, "ports")
+	unstructured.SetNestedSlice(deployment.Object, containers, "spec", "template", "spec", "containers")
+	unstructured.SetNestedField(deployment.Object, "ClusterFirst", "spec", "template", "spec", "dnsPolicy")
+	unstructured.SetNestedField(deployment.Object, int64(10), "spec", "revisionHistoryLimit")
+	unstructured.SetNestedField(deployment.Object, int64(1), "status", "observedGeneration")
+	unstructured.SetNestedField(deployment.Object, int64(1), "status", "updatedReplicas")
+	unstructured.SetNestedField(deployment.Object, int64(1), "status", "availableReplicas")
+
+	problems, err := applier.CheckDrift(manifest, "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error checking drift: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected server defaults not to be reported as drift, got %v", problems)
+	}
+
+	// A change to a field of the manifest within a list is still drift
+	container["image"] = "workload:2.0.0"
+	unstructured.SetNestedSlice(deployment.Object, containers, "spec", "template", "spec", "containers")
+
+	problems, err = applier.CheckDrift(manifest, "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error checking drift: %v", err)
+	}
+	expected := []ObjectProblem{
+		{Object: ObjectReference{Group: "apps", Kind: "Deployment", Namespace: "test", Name: "workload"}, Problem: ProblemModified, Message: "fields differ: spec.template.spec.containers"},
+	}
+	if fmt.Sprintf("%v", problems) != fmt.Sprintf("%v", expected) {
+		t.Errorf("unexpected problems;\nexpected %v\ngot      %v", expected, problems)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog"
+)
+
+// AnnotationPrune can be set to "false" on an object to prevent it being pruned
+// when it is removed from the manifest of an addon
+const AnnotationPrune = "addon.kops.k8s.io/prune"
+
+// ObjectReference identifies an object applied as part of an addon.
+// We record the group rather than the version, so that the reference remains valid as the apiserver is upgraded.
+type ObjectReference struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (r *ObjectReference) String() string {
+	s := r.Kind
+	if r.Group != "" {
+		s += "." + r.Group
+	}
+	s += " "
+	if r.Namespace != "" {
+		s += r.Namespace + "/"
+	}
+	return s + r.Name
+}
+
+// PruneOptions controls the removal of objects that are no longer part of an addon
+type PruneOptions struct {
+	// DryRun reports the objects that would be pruned, without deleting them
+	DryRun bool
+}
+
+// Prune deletes the objects in the previous inventory of the addon which are not in the current inventory.
+// Only objects labelled as belonging to the addon are deleted, and objects annotated with
+// addon.kops.k8s.io/prune=false are left in place.
+// It returns the objects that were not pruned but should be retained in the inventory,
+// so that pruning is attempted again: those skipped in dry-run mode or that we failed to delete.
+func (a *Applier) Prune(addonName string, previous []ObjectReference, current []ObjectReference, options *PruneOptions) ([]ObjectReference, error) {
+	keep := make(map[ObjectReference]bool)
+	for _, ref := range current {
+		keep[ref] = true
+	}
+
+	var candidates []ObjectReference
+	for _, ref := range previous {
+		if !keep[ref] {
+			candidates = append(candidates, ref)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// Delete in the reverse of the order in which we apply, so namespaces are removed last
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return applyPriority(schema.GroupKind{Group: candidates[i].Group, Kind: candidates[i].Kind}) > applyPriority(schema.GroupKind{Group: candidates[j].Group, Kind: candidates[j].Kind})
+	})
+
+	mapper, err := a.restMapper()
+	if err != nil {
+		return candidates, err
+	}
+
+	var retained []ObjectReference
+	var errs []error
+	for i := range candidates {
+		ref := candidates[i]
+		retain, err := a.pruneObject(mapper, addonName, &ref, options)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error pruning %s: %v", ref.String(), err))
+		}
+		if retain {
+			retained = append(retained, ref)
+		}
+	}
+	return retained, utilerrors.NewAggregate(errs)
+}
+
+// pruneObject deletes a single object, returning true if it should be retained in the inventory
+func (a *Applier) pruneObject(mapper meta.RESTMapper, addonName string, ref *ObjectReference, options *PruneOptions) (bool, error) {
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Group: ref.Group, Kind: ref.Kind})
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// The kind no longer exists (e.g. the CustomResourceDefinition was removed), so neither can the object
+			klog.V(2).Infof("not pruning %s; kind is no longer served", ref.String())
+			return false, nil
+		}
+		return true, err
+	}
+
+	var client dynamic.ResourceInterface
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		client = a.client.Resource(mapping.Resource).Namespace(ref.Namespace)
+	} else {
+		client = a.client.Resource(mapping.Resource)
+	}
+
+	o, err := client.Get(ref.Name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return true, fmt.Errorf("error reading object: %v", err)
+	}
+
+	if o.GetLabels()[LabelAddonName] != addonName {
+		klog.Warningf("not pruning %s; it is not labelled as belonging to addon %q", ref.String(), addonName)
+		return false, nil
+	}
+	if o.GetAnnotations()[AnnotationPrune] == "false" {
+		klog.Infof("not pruning %s; it is annotated %s=false", ref.String(), AnnotationPrune)
+		return false, nil
+	}
+
+	if options.DryRun {
+		klog.Infof("would prune %s (dry-run)", ref.String())
+		return true, nil
+	}
+
+	klog.Infof("pruning %s", ref.String())
+	propagationPolicy := metav1.DeletePropagationBackground
+	if err := client.Delete(ref.Name, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return true, err
+	}
+	return false, nil
+}
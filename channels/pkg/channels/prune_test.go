@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+	version := "1.0.0"
+	labels := AddonLabels("test.addons.k8s.io", &version)
+
+	previous, err := applier.ApplyObjects(parseObjects(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+  namespace: kube-system
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: removed
+  namespace: kube-system
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: protected
+  namespace: kube-system
+  annotations:
+    addon.kops.k8s.io/prune: "false"
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: removed
+  namespace: kube-system
+`), "", labels)
+	if err != nil {
+		t.Fatalf("unexpected error applying first version: %v", err)
+	}
+
+	// An object in the inventory that has since been taken over by another addon must not be pruned
+	client.objects["serviceaccounts/kube-system/removed"].SetLabels(map[string]string{LabelAddonName: "other.addons.k8s.io"})
+
+	version = "1.1.0"
+	current, err := applier.ApplyObjects(parseObjects(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kept
+  namespace: kube-system
+`), "", AddonLabels("test.addons.k8s.io", &version))
+	if err != nil {
+		t.Fatalf("unexpected error applying second version: %v", err)
+	}
+
+	// A dry-run should not delete anything, and should retain the candidates
+	client.actions = nil
+	retained, err := applier.Prune("test.addons.k8s.io", previous, current, &PruneOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error from dry-run prune: %v", err)
+	}
+	if len(client.actions) != 0 {
+		t.Errorf("expected no changes from dry-run prune, got %v", client.actions)
+	}
+	expectedRetained := "[ConfigMap kube-system/removed]"
+	if fmt.Sprintf("%v", refStrings(retained)) != expectedRetained {
+		t.Errorf("unexpected objects retained by dry-run; expected %s, got %v", expectedRetained, refStrings(retained))
+	}
+
+	retained, err = applier.Prune("test.addons.k8s.io", previous, current, &PruneOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error from prune: %v", err)
+	}
+	if len(retained) != 0 {
+		t.Errorf("expected no objects to be retained, got %v", refStrings(retained))
+	}
+
+	expectedActions := "[delete configmaps/kube-system/removed]"
+	if fmt.Sprintf("%v", client.actions) != expectedActions {
+		t.Errorf("unexpected actions; expected %s, got %v", expectedActions, client.actions)
+	}
+	for _, key := range []string{"configmaps/kube-system/kept", "configmaps/kube-system/protected", "serviceaccounts/kube-system/removed"} {
+		if client.objects[key] == nil {
+			t.Errorf("expected %s not to be pruned", key)
+		}
+	}
+}
+
+func refStrings(refs []ObjectReference) []string {
+	var s []string
+	for i := range refs {
+		s = append(s, refs[i].String())
+	}
+	return s
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+// readinessPollInterval is how often we check readiness gates
+var readinessPollInterval = 5 * time.Second
+
+// readinessCheck reports whether an object is ready, and if not a description of what we are waiting for
+type readinessCheck func(o *unstructured.Unstructured) (bool, string)
+
+// readinessKinds holds the kinds of object that can be used as readiness gates
+var readinessKinds = map[string]struct {
+	groupKind schema.GroupKind
+	ready     readinessCheck
+}{
+	"Deployment":               {schema.GroupKind{Group: "apps", Kind: "Deployment"}, isDeploymentReady},
+	"DaemonSet":                {schema.GroupKind{Group: "apps", Kind: "DaemonSet"}, isDaemonSetReady},
+	"CustomResourceDefinition": {schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}, isCRDReady},
+}
+
+// ValidateReadinessGate checks that the gate refers to a kind of object we know how to wait for
+func ValidateReadinessGate(gate *api.ReadinessGate) error {
+	if _, found := readinessKinds[gate.Kind]; !found {
+		return fmt.Errorf("unsupported readiness kind %q", gate.Kind)
+	}
+	if gate.Name == "" {
+		return fmt.Errorf("readiness gate for %s must specify a name", gate.Kind)
+	}
+	return nil
+}
+
+// WaitForReadiness waits until the objects named by all the gates are ready, or the timeout is reached.
+// Gates without a namespace refer to objects in the specified namespace.
+func (a *Applier) WaitForReadiness(namespace string, gates []*api.ReadinessGate, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, gate := range gates {
+		if err := ValidateReadinessGate(gate); err != nil {
+			return err
+		}
+
+		kind := readinessKinds[gate.Kind]
+		gateNamespace := gate.Namespace
+		if gateNamespace == "" {
+			gateNamespace = namespace
+		}
+
+		var client dynamic.ResourceInterface
+		var description string
+		err := wait.PollImmediate(readinessPollInterval, time.Until(deadline), func() (bool, error) {
+			if client == nil {
+				mapper, err := a.restMapper()
+				if err != nil {
+					return false, err
+				}
+				mapping, err := mapper.RESTMapping(kind.groupKind)
+				if err != nil {
+					if meta.IsNoMatchError(err) {
+						// The kind may not be served yet; refresh our discovery information and retry
+						a.mapper = nil
+						description = "kind not yet served"
+						return false, nil
+					}
+					return false, err
+				}
+				if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+					client = a.client.Resource(mapping.Resource).Namespace(gateNamespace)
+				} else {
+					client = a.client.Resource(mapping.Resource)
+				}
+			}
+
+			o, err := client.Get(gate.Name, metav1.GetOptions{})
+			if err != nil {
+				if errors.IsNotFound(err) {
+					description = "not found"
+					return false, nil
+				}
+				return false, err
+			}
+
+			ready, waitingFor := kind.ready(o)
+			if !ready {
+				description = waitingFor
+				klog.V(2).Infof("waiting for %s %s: %s", gate.Kind, describeObject(o), waitingFor)
+			}
+			return ready, nil
+		})
+		if err == wait.ErrWaitTimeout {
+			return fmt.Errorf("timed out waiting for %s %q to become ready: %s", gate.Kind, gate.Name, description)
+		}
+		if err != nil {
+			return fmt.Errorf("error waiting for %s %q to become ready: %v", gate.Kind, gate.Name, err)
+		}
+	}
+	return nil
+}
+
+// isObservedGeneration returns true if the controller has observed the latest spec of the object
+func isObservedGeneration(o *unstructured.Unstructured) bool {
+	observedGeneration, _, _ := unstructured.NestedInt64(o.Object, "status", "observedGeneration")
+	return observedGeneration >= o.GetGeneration()
+}
+
+// isDeploymentReady follows the logic of kubectl rollout status
+func isDeploymentReady(o *unstructured.Unstructured) (bool, string) {
+	if !isObservedGeneration(o) {
+		return false, "rollout not yet started"
+	}
+
+	replicas, found, _ := unstructured.NestedInt64(o.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	statusReplicas, _, _ := unstructured.NestedInt64(o.Object, "status", "replicas")
+	updatedReplicas, _, _ := unstructured.NestedInt64(o.Object, "status", "updatedReplicas")
+	availableReplicas, _, _ := unstructured.NestedInt64(o.Object, "status", "availableReplicas")
+
+	if updatedReplicas < replicas {
+		return false, fmt.Sprintf("%d of %d replicas updated", updatedReplicas, replicas)
+	}
+	if statusReplicas > updatedReplicas {
+		return false, fmt.Sprintf("%d old replicas pending termination", statusReplicas-updatedReplicas)
+	}
+	if availableReplicas < updatedReplicas {
+		return false, fmt.Sprintf("%d of %d updated replicas available", availableReplicas, updatedReplicas)
+	}
+	return true, ""
+}
+
+// isDaemonSetReady follows the logic of kubectl rollout status
+func isDaemonSetReady(o *unstructured.Unstructured) (bool, string) {
+	if !isObservedGeneration(o) {
+		return false, "rollout not yet started"
+	}
+
+	desired, _, _ := unstructured.NestedInt64(o.Object, "status", "desiredNumberScheduled")
+	updated, _, _ := unstructured.NestedInt64(o.Object, "status", "updatedNumberScheduled")
+	available, _, _ := unstructured.NestedInt64(o.Object, "status", "numberAvailable")
+
+	if updated < desired {
+		return false, fmt.Sprintf("%d of %d pods updated", updated, desired)
+	}
+	if available < desired {
+		return false, fmt.Sprintf("%d of %d updated pods available", available, desired)
+	}
+	return true, ""
+}
+
+// isCRDReady returns true when the CustomResourceDefinition is established
+func isCRDReady(o *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(o.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Established" && condition["status"] == "True" {
+			return true, ""
+		}
+	}
+	return false, "not yet established"
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+func TestReadinessChecks(t *testing.T) {
+	grid := []struct {
+		Object   string
+		Expected bool
+	}{
+		{
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: coredns
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  replicas: 2
+  updatedReplicas: 2
+  availableReplicas: 2
+`,
+			Expected: true,
+		},
+		{
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: coredns
+  generation: 3
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  replicas: 2
+  updatedReplicas: 2
+  availableReplicas: 2
+`,
+			Expected: false,
+		},
+		{
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: coredns
+spec:
+  replicas: 2
+status:
+  replicas: 3
+  updatedReplicas: 2
+  availableReplicas: 2
+`,
+			Expected: false,
+		},
+		{
+			Object: `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: weave-net
+status:
+  desiredNumberScheduled: 3
+  updatedNumberScheduled: 3
+  numberAvailable: 2
+`,
+			Expected: false,
+		},
+		{
+			Object: `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: weave-net
+status:
+  desiredNumberScheduled: 3
+  updatedNumberScheduled: 3
+  numberAvailable: 3
+`,
+			Expected: true,
+		},
+		{
+			Object: `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+status:
+  conditions:
+  - type: NamesAccepted
+    status: "True"
+`,
+			Expected: false,
+		},
+		{
+			Object: `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+status:
+  conditions:
+  - type: NamesAccepted
+    status: "True"
+  - type: Established
+    status: "True"
+`,
+			Expected: true,
+		},
+	}
+
+	for _, g := range grid {
+		o := parseObjects(t, g.Object)[0]
+		ready, _ := readinessKinds[o.GetKind()].ready(o)
+		if ready != g.Expected {
+			t.Errorf("unexpected readiness for %s; expected %v, got %v", g.Object, g.Expected, ready)
+		}
+	}
+}
+
+func TestWaitForReadiness(t *testing.T) {
+	readinessPollInterval = time.Millisecond
+
+	client := newFakeDynamicClient()
+	applier := NewApplier(client, newFakeDiscovery())
+
+	gates := []*api.ReadinessGate{{Kind: "Deployment", Name: "coredns"}}
+
+	err := applier.WaitForReadiness("kube-system", gates, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected timeout waiting for missing deployment, got %v", err)
+	}
+
+	deployment := parseObjects(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: coredns
+  namespace: kube-system
+spec:
+  replicas: 1
+status:
+  replicas: 1
+  updatedReplicas: 1
+  availableReplicas: 0
+`)[0]
+	if _, err := applier.ApplyObjects([]*unstructured.Unstructured{deployment}, "", nil); err != nil {
+		t.Fatalf("error creating deployment: %v", err)
+	}
+
+	err = applier.WaitForReadiness("kube-system", gates, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "0 of 1 updated replicas available") {
+		t.Fatalf("expected timeout waiting for unavailable deployment, got %v", err)
+	}
+
+	unstructured.SetNestedField(client.objects["deployments/kube-system/coredns"].Object, int64(1), "status", "availableReplicas")
+	if err := applier.WaitForReadiness("kube-system", gates, time.Second); err != nil {
+		t.Fatalf("unexpected error waiting for ready deployment: %v", err)
+	}
+
+	if err := applier.WaitForReadiness("kube-system", []*api.ReadinessGate{{Kind: "Pod", Name: "foo"}}, time.Second); err == nil {
+		t.Fatalf("expected error for unsupported readiness kind")
+	}
+}
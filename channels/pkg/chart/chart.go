@@ -0,0 +1,220 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chart loads and renders Helm charts client-side, so that channels can apply them as manifests.
+// It follows the behaviour of `helm template` (Helm v2) for values, named templates, the Helm template functions
+// and subcharts.  Hooks are applied as ordinary objects.  The condition, tags and alias of the subcharts listed in
+// requirements.yaml are honoured, but subcharts are not downloaded: they must be included in the charts/ directory
+// of the chart.  import-values is not supported.
+package chart
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Metadata holds the fields of Chart.yaml, exposed to templates as .Chart
+type Metadata struct {
+	Name        string   `json:"name,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	AppVersion  string   `json:"appVersion,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Home        string   `json:"home,omitempty"`
+	Sources     []string `json:"sources,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	KubeVersion string   `json:"kubeVersion,omitempty"`
+	APIVersion  string   `json:"apiVersion,omitempty"`
+}
+
+// Chart is a loaded chart
+type Chart struct {
+	Metadata Metadata
+
+	// Values holds the default values from values.yaml
+	Values map[string]interface{}
+
+	// Templates maps from the path of each template (relative to the chart, e.g. templates/deployment.yaml) to its contents
+	Templates map[string]string
+
+	// Files maps from the path of each other file in the chart to its contents
+	Files map[string][]byte
+
+	// Dependencies are the subcharts, from the charts/ directory
+	Dependencies []*Chart
+
+	// Requirements are the dependencies listed in requirements.yaml
+	Requirements []*Requirement
+}
+
+// Requirement holds a dependency listed in requirements.yaml
+type Requirement struct {
+	// Name is the name of the subchart
+	Name string `json:"name"`
+	// Version is the version range of the subchart; it is not checked, as subcharts are not downloaded
+	Version string `json:"version,omitempty"`
+	// Repository is the repository of the subchart; it is not used, as subcharts are not downloaded
+	Repository string `json:"repository,omitempty"`
+	// Condition is a comma separated list of paths to boolean values; the first that is set enables or disables the subchart
+	Condition string `json:"condition,omitempty"`
+	// Tags enable the subchart if any is set to true under tags in the values, or disable it if they are only set to false
+	Tags []string `json:"tags,omitempty"`
+	// Alias is the name the subchart is rendered as, and under which its values are found
+	Alias string `json:"alias,omitempty"`
+}
+
+// requirementsFile is the structure of requirements.yaml
+type requirementsFile struct {
+	Dependencies []*Requirement `json:"dependencies"`
+}
+
+// LoadArchive loads a chart from a chart archive (a gzipped tar, as built by helm package)
+func LoadArchive(data []byte) (*Chart, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error reading chart archive: %v", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading chart archive: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		// Archives contain a single top-level directory, named for the chart
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %q from chart archive: %v", hdr.Name, err)
+		}
+		files[parts[1]] = b
+	}
+
+	return LoadFiles(files)
+}
+
+// LoadFiles loads a chart from its files, keyed by their path relative to the chart
+func LoadFiles(files map[string][]byte) (*Chart, error) {
+	c := &Chart{
+		Templates: make(map[string]string),
+		Files:     make(map[string][]byte),
+	}
+
+	chartYAML, found := files["Chart.yaml"]
+	if !found {
+		return nil, fmt.Errorf("chart does not contain Chart.yaml")
+	}
+	if err := yaml.Unmarshal(chartYAML, &c.Metadata); err != nil {
+		return nil, fmt.Errorf("error parsing Chart.yaml: %v", err)
+	}
+	if c.Metadata.Name == "" {
+		return nil, fmt.Errorf("Chart.yaml does not specify a name")
+	}
+
+	c.Values = make(map[string]interface{})
+	if b := files["values.yaml"]; len(b) != 0 {
+		if err := yaml.Unmarshal(b, &c.Values); err != nil {
+			return nil, fmt.Errorf("error parsing values.yaml of chart %q: %v", c.Metadata.Name, err)
+		}
+		if c.Values == nil {
+			c.Values = make(map[string]interface{})
+		}
+	}
+
+	if b := files["requirements.yaml"]; len(b) != 0 {
+		requirements := &requirementsFile{}
+		if err := yaml.Unmarshal(b, requirements); err != nil {
+			return nil, fmt.Errorf("error parsing requirements.yaml of chart %q: %v", c.Metadata.Name, err)
+		}
+		c.Requirements = requirements.Dependencies
+	}
+
+	subchartFiles := make(map[string]map[string][]byte)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := files[name]
+		switch {
+		case name == "Chart.yaml" || name == "values.yaml":
+			// Already processed
+
+		case strings.HasPrefix(name, "templates/"):
+			c.Templates[name] = string(data)
+
+		case strings.HasPrefix(name, "charts/"):
+			rel := strings.TrimPrefix(name, "charts/")
+			if !strings.Contains(rel, "/") {
+				if strings.HasSuffix(rel, ".tgz") {
+					subchart, err := LoadArchive(data)
+					if err != nil {
+						return nil, fmt.Errorf("error loading subchart %q of chart %q: %v", rel, c.Metadata.Name, err)
+					}
+					c.Dependencies = append(c.Dependencies, subchart)
+				}
+				continue
+			}
+			parts := strings.SplitN(rel, "/", 2)
+			if subchartFiles[parts[0]] == nil {
+				subchartFiles[parts[0]] = make(map[string][]byte)
+			}
+			subchartFiles[parts[0]][parts[1]] = data
+
+		default:
+			c.Files[name] = data
+		}
+	}
+
+	var subchartNames []string
+	for name := range subchartFiles {
+		subchartNames = append(subchartNames, name)
+	}
+	sort.Strings(subchartNames)
+	for _, name := range subchartNames {
+		subchart, err := LoadFiles(subchartFiles[name])
+		if err != nil {
+			return nil, fmt.Errorf("error loading subchart %q of chart %q: %v", name, c.Metadata.Name, err)
+		}
+		c.Dependencies = append(c.Dependencies, subchart)
+	}
+
+	return c, nil
+}
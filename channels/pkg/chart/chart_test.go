@@ -0,0 +1,354 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chart
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func buildArchive(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data := []byte(files[name])
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+	return b.Bytes()
+}
+
+var testChart = map[string]string{
+	"example/Chart.yaml": `
+name: example
+version: 1.2.3
+appVersion: "4.5"
+`,
+	"example/values.yaml": `
+replicas: 1
+image:
+  repository: example/server
+  tag: latest
+extraArgs: []
+greeting: "hello {{ .Release.Name }}"
+global:
+  region: us-east-1
+`,
+	"example/templates/_helpers.tpl": `
+{{- define "example.fullname" -}}
+{{ .Release.Name }}-{{ .Chart.Name }}
+{{- end -}}
+{{- define "example.labels" -}}
+app: {{ template "example.fullname" . }}
+version: {{ .Chart.AppVersion | quote }}
+{{- end -}}
+`,
+	"example/templates/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "example.fullname" . }}
+  namespace: {{ .Release.Namespace }}
+  labels:
+{{ include "example.labels" . | indent 4 }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+      - name: server
+        image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+        args:
+{{ toYaml .Values.extraArgs | indent 8 }}
+        env:
+        - name: GREETING
+          value: {{ tpl .Values.greeting . | quote }}
+`,
+	"example/templates/optional.yaml": `
+{{- if .Values.optional }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: optional
+{{- end }}
+`,
+	"example/templates/NOTES.txt": `Thank you for installing {{ .Chart.Name }}`,
+	"example/charts/sub/Chart.yaml": `
+name: sub
+version: 0.1.0
+`,
+	"example/charts/sub/values.yaml": `
+port: 80
+`,
+	"example/charts/sub/templates/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Release.Name }}-sub
+  annotations:
+    region: {{ .Values.global.region }}
+spec:
+  ports:
+  - port: {{ .Values.port }}
+`,
+}
+
+func TestRender(t *testing.T) {
+	c, err := LoadArchive(buildArchive(t, testChart))
+	if err != nil {
+		t.Fatalf("error loading chart: %v", err)
+	}
+	if c.Metadata.Name != "example" || c.Metadata.Version != "1.2.3" {
+		t.Fatalf("unexpected chart metadata %+v", c.Metadata)
+	}
+	if len(c.Dependencies) != 1 || c.Dependencies[0].Metadata.Name != "sub" {
+		t.Fatalf("expected subchart sub, got %v", c.Dependencies)
+	}
+
+	options := &RenderOptions{
+		Release: Release{Name: "myrelease", Namespace: "kube-system"},
+		Values: map[string]interface{}{
+			"replicas":  float64(3),
+			"image":     map[string]interface{}{"tag": "v1.0"},
+			"extraArgs": []interface{}{"--verbose"},
+			"sub":       map[string]interface{}{"port": float64(8080)},
+		},
+	}
+	rendered, err := c.Render(options)
+	if err != nil {
+		t.Fatalf("error rendering chart: %v", err)
+	}
+
+	expected := `---
+# Source: example/charts/sub/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: myrelease-sub
+  annotations:
+    region: us-east-1
+spec:
+  ports:
+  - port: 8080
+---
+# Source: example/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myrelease-example
+  namespace: kube-system
+  labels:
+    app: myrelease-example
+    version: "4.5"
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: server
+        image: "example/server:v1.0"
+        args:
+        - --verbose
+        env:
+        - name: GREETING
+          value: "hello myrelease"
+`
+	if string(rendered) != expected {
+		t.Errorf("unexpected rendered chart; expected:\n%s\ngot:\n%s", expected, string(rendered))
+	}
+}
+
+func TestRenderRequired(t *testing.T) {
+	c, err := LoadFiles(map[string][]byte{
+		"Chart.yaml":               []byte("name: required\nversion: 0.1.0\n"),
+		"templates/configmap.yaml": []byte(`value: {{ required "value must be set" .Values.value }}`),
+	})
+	if err != nil {
+		t.Fatalf("error loading chart: %v", err)
+	}
+
+	_, err = c.Render(&RenderOptions{})
+	if err == nil || !strings.Contains(err.Error(), "value must be set") {
+		t.Errorf("expected error for missing required value, got %v", err)
+	}
+
+	rendered, err := c.Render(&RenderOptions{Values: map[string]interface{}{"value": "set"}})
+	if err != nil {
+		t.Fatalf("unexpected error rendering chart: %v", err)
+	}
+	if !strings.Contains(string(rendered), "value: set") {
+		t.Errorf("unexpected rendered chart %q", string(rendered))
+	}
+}
+
+func TestRenderRequirements(t *testing.T) {
+	c, err := LoadFiles(map[string][]byte{
+		"Chart.yaml": []byte("name: app\nversion: 0.1.0\n"),
+		"requirements.yaml": []byte(`
+dependencies:
+- name: sub
+  version: 0.1.0
+  condition: sub.enabled
+- name: sub
+  version: 0.1.0
+  alias: other
+  condition: other.enabled,global.subEnabled
+- name: extra
+  version: 0.1.0
+  tags:
+  - extras
+`),
+		"charts/sub/Chart.yaml":          []byte("name: sub\nversion: 0.1.0\n"),
+		"charts/sub/values.yaml":         []byte("enabled: true\nport: 80\n"),
+		"charts/sub/templates/svc.yaml":  []byte("name: {{ .Chart.Name }}\nport: {{ .Values.port }}\n"),
+		"charts/extra/Chart.yaml":        []byte("name: extra\nversion: 0.1.0\n"),
+		"charts/extra/templates/cm.yaml": []byte("name: extra\n"),
+	})
+	if err != nil {
+		t.Fatalf("error loading chart: %v", err)
+	}
+	if len(c.Requirements) != 3 {
+		t.Fatalf("expected 3 requirements, got %v", c.Requirements)
+	}
+
+	grid := []struct {
+		Values   map[string]interface{}
+		Expected []string
+	}{
+		{
+			Values:   nil,
+			Expected: []string{"app/charts/extra/templates/cm.yaml", "app/charts/other/templates/svc.yaml", "app/charts/sub/templates/svc.yaml"},
+		},
+		{
+			// A disabled subchart is not rendered, but an alias of it can be
+			Values: map[string]interface{}{
+				"sub":   map[string]interface{}{"enabled": false},
+				"other": map[string]interface{}{"port": float64(8080)},
+			},
+			Expected: []string{"app/charts/extra/templates/cm.yaml", "app/charts/other/templates/svc.yaml"},
+		},
+		{
+			Values: map[string]interface{}{
+				"tags":  map[string]interface{}{"extras": false},
+				"other": map[string]interface{}{"enabled": false},
+			},
+			Expected: []string{"app/charts/sub/templates/svc.yaml"},
+		},
+		{
+			// The first condition that is set wins
+			Values: map[string]interface{}{
+				"other":  map[string]interface{}{"enabled": "yes"},
+				"global": map[string]interface{}{"subEnabled": false},
+				"tags":   map[string]interface{}{"extras": true},
+			},
+			Expected: []string{"app/charts/extra/templates/cm.yaml", "app/charts/sub/templates/svc.yaml"},
+		},
+	}
+	for i, g := range grid {
+		rendered, err := c.Render(&RenderOptions{Values: g.Values})
+		if err != nil {
+			t.Fatalf("test %d: error rendering chart: %v", i, err)
+		}
+
+		var sources []string
+		for _, line := range strings.Split(string(rendered), "\n") {
+			if strings.HasPrefix(line, "# Source: ") {
+				sources = append(sources, strings.TrimPrefix(line, "# Source: "))
+			}
+		}
+		if strings.Join(sources, ",") != strings.Join(g.Expected, ",") {
+			t.Errorf("test %d: rendered %v, expected %v", i, sources, g.Expected)
+		}
+	}
+
+	rendered, err := c.Render(&RenderOptions{Values: map[string]interface{}{"other": map[string]interface{}{"port": float64(8080)}}})
+	if err != nil {
+		t.Fatalf("error rendering chart: %v", err)
+	}
+	if !strings.Contains(string(rendered), "name: other\nport: 8080") || !strings.Contains(string(rendered), "name: sub\nport: 80") {
+		t.Errorf("aliased subchart was not rendered with its own name and values:\n%s", rendered)
+	}
+}
+
+func TestFindInIndex(t *testing.T) {
+	archive := []byte("archive")
+	sum := sha256.Sum256(archive)
+
+	index := []byte(`
+apiVersion: v1
+entries:
+  example:
+  - name: example
+    version: 1.0.0
+    urls:
+    - example-1.0.0.tgz
+    digest: ` + hex.EncodeToString(sum[:]) + `
+  - name: example
+    version: 1.1.0
+    urls:
+    - https://downloads.example.com/example-1.1.0.tgz
+`)
+
+	entry, err := FindInIndex("https://charts.example.com/stable", index, "example", "1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.URLs[0] != "https://charts.example.com/stable/example-1.0.0.tgz" {
+		t.Errorf("unexpected url %q", entry.URLs[0])
+	}
+	if err := entry.VerifyDigest(archive); err != nil {
+		t.Errorf("unexpected digest error: %v", err)
+	}
+	if err := entry.VerifyDigest([]byte("tampered")); err == nil {
+		t.Errorf("expected digest error for tampered archive")
+	}
+
+	entry, err = FindInIndex("https://charts.example.com/stable/", index, "example", "1.1.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.URLs[0] != "https://downloads.example.com/example-1.1.0.tgz" {
+		t.Errorf("unexpected url %q", entry.URLs[0])
+	}
+
+	if _, err := FindInIndex("https://charts.example.com/stable", index, "example", "2.0.0"); err == nil {
+		t.Errorf("expected error for missing version")
+	}
+}
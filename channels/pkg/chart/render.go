@@ -0,0 +1,433 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chart
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/ghodss/yaml"
+)
+
+// Release describes the release being rendered, exposed to templates as .Release
+type Release struct {
+	Name      string
+	Namespace string
+	Service   string
+	Revision  int
+	IsInstall bool
+	IsUpgrade bool
+}
+
+// KubeVersion describes the version of the target cluster
+type KubeVersion struct {
+	Major      string
+	Minor      string
+	GitVersion string
+}
+
+// Capabilities describes the target cluster, exposed to templates as .Capabilities
+type Capabilities struct {
+	KubeVersion KubeVersion
+	APIVersions VersionSet
+}
+
+// VersionSet is the set of api versions served by the cluster
+type VersionSet []string
+
+// Has returns true if the api version (e.g. apps/v1) is served by the cluster
+func (v VersionSet) Has(apiVersion string) bool {
+	for _, s := range v {
+		if s == apiVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// Files gives templates access to the non-template files of a chart, exposed to templates as .Files
+type Files map[string][]byte
+
+// Get returns the contents of the file, or an empty string if it does not exist
+func (f Files) Get(name string) string {
+	return string(f[name])
+}
+
+// GetBytes returns the contents of the file, or nil if it does not exist
+func (f Files) GetBytes(name string) []byte {
+	return f[name]
+}
+
+// Glob returns the files whose paths match the pattern
+func (f Files) Glob(pattern string) Files {
+	matches := make(Files)
+	for name, data := range f {
+		if ok, _ := path.Match(pattern, name); ok {
+			matches[name] = data
+		}
+	}
+	return matches
+}
+
+// RenderOptions holds the release-specific inputs to rendering a chart
+type RenderOptions struct {
+	Release      Release
+	Capabilities Capabilities
+	// Values override the default values of the chart
+	Values map[string]interface{}
+}
+
+// renderTemplate is a template to be rendered, with the scope that applies to it
+type renderTemplate struct {
+	name     string
+	basePath string
+	scope    map[string]interface{}
+}
+
+// Render renders the templates of the chart and its subcharts into a single multi-document manifest.
+// Templates are rendered in order of their names, and those that render to nothing but whitespace are omitted.
+func (c *Chart) Render(options *RenderOptions) ([]byte, error) {
+	values := CoalesceValues(options.Values, c.Values)
+
+	t := template.New("chart")
+	t.Option("missingkey=zero")
+	t.Funcs(funcMap(t))
+
+	var templates []*renderTemplate
+	if err := c.collectTemplates(t, c.Metadata.Name, values, values, options, &templates); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].name < templates[j].name
+	})
+
+	var b bytes.Buffer
+	for _, rt := range templates {
+		base := path.Base(rt.name)
+		if strings.HasPrefix(base, "_") || base == "NOTES.txt" {
+			// Partials only define named templates
+			continue
+		}
+
+		rt.scope["Template"] = map[string]interface{}{
+			"Name":     rt.name,
+			"BasePath": rt.basePath,
+		}
+
+		var out bytes.Buffer
+		if err := t.ExecuteTemplate(&out, rt.name, rt.scope); err != nil {
+			return nil, fmt.Errorf("error rendering template %q: %v", rt.name, err)
+		}
+
+		// Helm renders missing values as empty, rather than <no value>
+		rendered := strings.Replace(out.String(), "<no value>", "", -1)
+		if strings.TrimSpace(rendered) == "" {
+			continue
+		}
+
+		b.WriteString("---\n# Source: " + rt.name + "\n")
+		b.WriteString(strings.TrimSpace(rendered))
+		b.WriteString("\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+// collectTemplates parses the templates of the chart and its enabled subcharts into t, recording the scope in which each should be rendered.
+// values are the values of the chart, and topValues those of the top-level chart.
+func (c *Chart) collectTemplates(t *template.Template, prefix string, values map[string]interface{}, topValues map[string]interface{}, options *RenderOptions, templates *[]*renderTemplate) error {
+	scope := map[string]interface{}{
+		"Values":       values,
+		"Release":      options.Release,
+		"Capabilities": options.Capabilities,
+		"Chart":        chartScope(&c.Metadata),
+		"Files":        Files(c.Files),
+	}
+
+	var names []string
+	for name := range c.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fullName := path.Join(prefix, name)
+		if _, err := t.New(fullName).Parse(c.Templates[name]); err != nil {
+			return fmt.Errorf("error parsing template %q: %v", fullName, err)
+		}
+
+		// Each template has its own scope map, as .Template differs
+		templateScope := make(map[string]interface{})
+		for k, v := range scope {
+			templateScope[k] = v
+		}
+		*templates = append(*templates, &renderTemplate{
+			name:     fullName,
+			basePath: path.Join(prefix, "templates"),
+			scope:    templateScope,
+		})
+	}
+
+	for _, dep := range c.enabledDependencies(values, topValues) {
+		depValues, _ := values[dep.Metadata.Name].(map[string]interface{})
+		depValues = CoalesceValues(depValues, dep.Values)
+		if global, ok := values["global"].(map[string]interface{}); ok {
+			depGlobal, _ := depValues["global"].(map[string]interface{})
+			depValues["global"] = CoalesceValues(global, depGlobal)
+		}
+
+		if err := dep.collectTemplates(t, path.Join(prefix, "charts", dep.Metadata.Name), depValues, topValues, options, templates); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enabledDependencies returns the subcharts to render, following Helm's ProcessRequirementsEnabled.
+// Subcharts listed in requirements.yaml are renamed to their alias, and left out if disabled by their tags
+// (looked up in the values of the top-level chart) or their condition (looked up in the values of the chart,
+// including the defaults of the subcharts); a condition takes precedence over tags.  Subcharts that are not
+// listed are always rendered.
+func (c *Chart) enabledDependencies(values map[string]interface{}, topValues map[string]interface{}) []*Chart {
+	if len(c.Requirements) == 0 {
+		return c.Dependencies
+	}
+
+	var dependencies []*Chart
+	for _, dep := range c.Dependencies {
+		listed := false
+		for _, r := range c.Requirements {
+			if r.Name == dep.Metadata.Name {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			dependencies = append(dependencies, dep)
+		}
+	}
+
+	var required []*Chart
+	for _, r := range c.Requirements {
+		for _, dep := range c.Dependencies {
+			if dep.Metadata.Name != r.Name {
+				continue
+			}
+			if r.Alias != "" {
+				aliased := *dep
+				aliased.Metadata.Name = r.Alias
+				dep = &aliased
+			}
+			required = append(required, dep)
+			break
+		}
+	}
+
+	// Conditions can refer to the defaults of the subcharts
+	conditionValues := make(map[string]interface{})
+	for k, v := range values {
+		conditionValues[k] = v
+	}
+	for _, dep := range required {
+		depValues, _ := conditionValues[dep.Metadata.Name].(map[string]interface{})
+		conditionValues[dep.Metadata.Name] = CoalesceValues(depValues, dep.Values)
+	}
+	tags, _ := topValues["tags"].(map[string]interface{})
+
+	disabled := make(map[string]bool)
+	for _, r := range c.Requirements {
+		name := r.Name
+		if r.Alias != "" {
+			name = r.Alias
+		}
+		if !isRequirementEnabled(r, conditionValues, tags) {
+			disabled[name] = true
+		}
+	}
+
+	for _, dep := range required {
+		if !disabled[dep.Metadata.Name] {
+			dependencies = append(dependencies, dep)
+		}
+	}
+	return dependencies
+}
+
+// isRequirementEnabled evaluates the condition and tags of the requirement
+func isRequirementEnabled(r *Requirement, values map[string]interface{}, tags map[string]interface{}) bool {
+	enabled := true
+
+	hasTrue, hasFalse := false, false
+	for _, tag := range r.Tags {
+		if b, ok := tags[tag].(bool); ok {
+			if b {
+				hasTrue = true
+			} else {
+				hasFalse = true
+			}
+		}
+	}
+	if !hasTrue && hasFalse {
+		enabled = false
+	}
+
+	for _, condition := range strings.Split(r.Condition, ",") {
+		condition = strings.TrimSpace(condition)
+		if condition == "" {
+			continue
+		}
+		if b, ok := lookupValue(values, condition).(bool); ok {
+			// The first condition that is set wins
+			enabled = b
+			break
+		}
+	}
+
+	return enabled
+}
+
+// lookupValue returns the value at the dotted path, or nil if it is not set
+func lookupValue(values map[string]interface{}, path string) interface{} {
+	var v interface{} = values
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// chartScope returns the value of .Chart, which uses the capitalized field names of Helm's chart metadata
+func chartScope(m *Metadata) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":        m.Name,
+		"Version":     m.Version,
+		"AppVersion":  m.AppVersion,
+		"Description": m.Description,
+		"Home":        m.Home,
+		"Sources":     m.Sources,
+		"Keywords":    m.Keywords,
+		"KubeVersion": m.KubeVersion,
+		"ApiVersion":  m.APIVersion,
+	}
+}
+
+// CoalesceValues deep-merges values over defaults, returning a new map.
+// As with Helm, a null value removes the default.
+func CoalesceValues(values map[string]interface{}, defaults map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range values {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		valueMap, valueIsMap := v.(map[string]interface{})
+		defaultMap, defaultIsMap := merged[k].(map[string]interface{})
+		if valueIsMap && defaultIsMap {
+			merged[k] = CoalesceValues(valueMap, defaultMap)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// funcMap returns the template functions available to charts: those of sprig, plus those added by Helm
+func funcMap(t *template.Template) template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+
+	// Charts must render the same wherever they are rendered
+	delete(funcs, "env")
+	delete(funcs, "expandenv")
+
+	funcs["toYaml"] = func(v interface{}) string {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			// Swallow errors, as Helm does
+			return ""
+		}
+		return strings.TrimSuffix(string(b), "\n")
+	}
+	funcs["fromYaml"] = func(s string) map[string]interface{} {
+		m := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	funcs["toJson"] = func(v interface{}) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	funcs["fromJson"] = func(s string) map[string]interface{} {
+		m := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	funcs["required"] = func(message string, v interface{}) (interface{}, error) {
+		if v == nil {
+			return nil, errors.New(message)
+		}
+		if s, ok := v.(string); ok && s == "" {
+			return nil, errors.New(message)
+		}
+		return v, nil
+	}
+	funcs["include"] = func(name string, data interface{}) (string, error) {
+		var b bytes.Buffer
+		if err := t.ExecuteTemplate(&b, name, data); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	funcs["tpl"] = func(text string, data interface{}) (string, error) {
+		clone, err := t.Clone()
+		if err != nil {
+			return "", err
+		}
+		parsed, err := clone.New("tpl").Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("error parsing tpl: %v", err)
+		}
+		var b bytes.Buffer
+		if err := parsed.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("error rendering tpl: %v", err)
+		}
+		return strings.Replace(b.String(), "<no value>", "", -1), nil
+	}
+
+	return funcs
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chart
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// repositoryIndex is the index.yaml of a chart repository
+type repositoryIndex struct {
+	Entries map[string][]*IndexEntry `json:"entries"`
+}
+
+// IndexEntry is a chart version listed in the index of a chart repository
+type IndexEntry struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	URLs    []string `json:"urls"`
+	Digest  string   `json:"digest,omitempty"`
+}
+
+// IndexURL returns the location of the index of the chart repository
+func IndexURL(repository string) string {
+	return strings.TrimSuffix(repository, "/") + "/index.yaml"
+}
+
+// FindInIndex finds the version of the chart in the index of a chart repository,
+// returning the entry with its archive URL resolved against the repository
+func FindInIndex(repository string, index []byte, name string, version string) (*IndexEntry, error) {
+	parsed := &repositoryIndex{}
+	if err := yaml.Unmarshal(index, parsed); err != nil {
+		return nil, fmt.Errorf("error parsing index of chart repository %q: %v", repository, err)
+	}
+
+	for _, entry := range parsed.Entries[name] {
+		if entry.Version != version {
+			continue
+		}
+		if len(entry.URLs) == 0 {
+			return nil, fmt.Errorf("chart %s-%s in repository %q has no urls", name, version, repository)
+		}
+
+		base, err := url.Parse(strings.TrimSuffix(repository, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("error parsing chart repository %q: %v", repository, err)
+		}
+		u, err := url.Parse(entry.URLs[0])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing url %q of chart %s-%s: %v", entry.URLs[0], name, version, err)
+		}
+
+		resolved := *entry
+		resolved.URLs = []string{base.ResolveReference(u).String()}
+		return &resolved, nil
+	}
+
+	return nil, fmt.Errorf("chart %s version %s not found in repository %q", name, version, repository)
+}
+
+// VerifyDigest checks the archive matches the digest in the index, if the index specified one
+func (e *IndexEntry) VerifyDigest(archive []byte) error {
+	if e.Digest == "" {
+		return nil
+	}
+	sum := sha256.Sum256(archive)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(strings.TrimPrefix(e.Digest, "sha256:"), actual) {
+		return fmt.Errorf("digest of chart %s-%s was %s, expected %s", e.Name, e.Version, actual, e.Digest)
+	}
+	return nil
+}
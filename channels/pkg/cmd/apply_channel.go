@@ -21,10 +21,13 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/kops/channels/pkg/channels"
 	"k8s.io/kops/util/pkg/tables"
 )
@@ -32,10 +35,21 @@ import (
 type ApplyChannelOptions struct {
 	Yes   bool
 	Files []string
+
+	// Prune removes objects that were applied by a previous version of an addon but are no longer in its manifest
+	Prune bool
+	// PruneDryRun reports the objects that would be pruned, without deleting them
+	PruneDryRun bool
+
+	// ReadinessTimeout is the maximum time to wait for the readiness gates of an addon
+	ReadinessTimeout time.Duration
 }
 
 func NewCmdApplyChannel(f Factory, out io.Writer) *cobra.Command {
-	var options ApplyChannelOptions
+	options := ApplyChannelOptions{
+		Prune:            true,
+		ReadinessTimeout: 10 * time.Minute,
+	}
 
 	cmd := &cobra.Command{
 		Use:   "channel",
@@ -47,6 +61,9 @@ func NewCmdApplyChannel(f Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().BoolVar(&options.Yes, "yes", false, "Apply update")
 	cmd.Flags().StringSliceVarP(&options.Files, "filename", "f", []string{}, "Apply from a local file")
+	cmd.Flags().BoolVar(&options.Prune, "prune", options.Prune, "Remove objects that are no longer part of an addon when it is updated")
+	cmd.Flags().BoolVar(&options.PruneDryRun, "prune-dry-run", false, "Report the objects that would be pruned, without removing them")
+	cmd.Flags().DurationVar(&options.ReadinessTimeout, "readiness-timeout", options.ReadinessTimeout, "Maximum time to wait for an addon to become ready")
 
 	return cmd
 }
@@ -57,74 +74,30 @@ func RunApplyChannel(f Factory, out io.Writer, options *ApplyChannelOptions, arg
 		return err
 	}
 
-	kubernetesVersionInfo, err := k8sClient.Discovery().ServerVersion()
+	kubernetesVersion, err := getKubernetesVersion(k8sClient)
 	if err != nil {
-		return fmt.Errorf("error querying kubernetes version: %v", err)
-	}
-
-	//kubernetesVersion, err := semver.Parse(kubernetesVersionInfo.Major + "." + kubernetesVersionInfo.Minor + ".0")
-	//if err != nil {
-	//	return fmt.Errorf("cannot parse kubernetes version %q", kubernetesVersionInfo.Major+"."+kubernetesVersionInfo.Minor + ".0")
-	//}
-
-	kubernetesVersion, err := semver.ParseTolerant(kubernetesVersionInfo.GitVersion)
-	if err != nil {
-		return fmt.Errorf("cannot parse kubernetes version %q", kubernetesVersionInfo.GitVersion)
+		return err
 	}
 
-	// Remove Pre and Patch, as they make semver comparisons impractical
-	kubernetesVersion.Pre = nil
-
 	menu := channels.NewAddonMenu()
 
 	for _, name := range args {
-		location, err := url.Parse(name)
-		if err != nil {
-			return fmt.Errorf("unable to parse argument %q as url", name)
-		}
-		if !location.IsAbs() {
-			// We recognize the following "well-known" format:
-			// <name> with no slashes ->
-			if strings.Contains(name, "/") {
-				return fmt.Errorf("Channel format not recognized (did you mean to use `-f` to specify a local file?): %q", name)
-			}
-			expanded := "https://raw.githubusercontent.com/kubernetes/kops/master/addons/" + name + "/addon.yaml"
-			location, err = url.Parse(expanded)
-			if err != nil {
-				return fmt.Errorf("unable to parse expanded argument %q as url", expanded)
-			}
-		}
-		o, err := channels.LoadAddons(name, location)
+		o, err := loadChannel(name)
 		if err != nil {
-			return fmt.Errorf("error loading channel %q: %v", location, err)
+			return err
 		}
 
 		current, err := o.GetCurrent(kubernetesVersion)
 		if err != nil {
-			return fmt.Errorf("error processing latest versions in %q: %v", location, err)
+			return fmt.Errorf("error processing latest versions in %q: %v", name, err)
 		}
 		menu.MergeAddons(current)
 	}
 
 	for _, f := range options.Files {
-		location, err := url.Parse(f)
+		o, err := loadChannelFile(f)
 		if err != nil {
-			return fmt.Errorf("unable to parse argument %q as url", f)
-		}
-		if !location.IsAbs() {
-			cwd, err := os.Getwd()
-			if err != nil {
-				return fmt.Errorf("error getting current directory: %v", err)
-			}
-			baseURL, err := url.Parse(cwd + string(os.PathSeparator))
-			if err != nil {
-				return fmt.Errorf("error building url for current directory %q: %v", cwd, err)
-			}
-			location = baseURL.ResolveReference(location)
-		}
-		o, err := channels.LoadAddons(f, location)
-		if err != nil {
-			return fmt.Errorf("error loading file %q: %v", f, err)
+			return err
 		}
 
 		current, err := o.GetCurrent(kubernetesVersion)
@@ -134,9 +107,18 @@ func RunApplyChannel(f Factory, out io.Writer, options *ApplyChannelOptions, arg
 		menu.MergeAddons(current)
 	}
 
-	var updates []*channels.AddonUpdate
-	var needUpdates []*channels.Addon
+	var addons []*channels.Addon
 	for _, addon := range menu.Addons {
+		addons = append(addons, addon)
+	}
+	addons, err = channels.SortByDependencies(addons)
+	if err != nil {
+		return err
+	}
+
+	var updates []*channels.AddonUpdate
+	needUpdates := make(map[string]bool)
+	for _, addon := range addons {
 		// TODO: Cache lookups to prevent repeated lookups?
 		update, err := addon.GetRequiredUpdates(k8sClient)
 		if err != nil {
@@ -144,7 +126,7 @@ func RunApplyChannel(f Factory, out io.Writer, options *ApplyChannelOptions, arg
 		}
 		if update != nil {
 			updates = append(updates, update)
-			needUpdates = append(needUpdates, addon)
+			needUpdates[addon.Name] = true
 		}
 	}
 
@@ -189,22 +171,179 @@ func RunApplyChannel(f Factory, out io.Writer, options *ApplyChannelOptions, arg
 		return nil
 	}
 
-	for _, needUpdate := range needUpdates {
-		update, err := needUpdate.EnsureUpdated(k8sClient)
-		if err != nil {
-			return fmt.Errorf("error updating %q: %v", needUpdate.Name, err)
+	dynamicClient, err := f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	applier := channels.NewApplier(dynamicClient, k8sClient.Discovery())
+
+	var pruneOptions *channels.PruneOptions
+	if options.Prune || options.PruneDryRun {
+		pruneOptions = &channels.PruneOptions{
+			DryRun: options.PruneDryRun,
 		}
-		// Could have been a concurrent request
-		if update != nil {
-			if update.NewVersion.Version != nil {
-				fmt.Printf("Updated %q to %s\n", update.Name, *update.NewVersion.Version)
+	}
+
+	// We wait for the readiness of addons we update, and of any addons that an update depends on, directly or not
+	needReady := channels.DependencyClosure(addons, needUpdates)
+
+	var results []*addonResult
+	failed := make(map[string]bool)
+	for _, addon := range addons {
+		if !needUpdates[addon.Name] && !needReady[addon.Name] {
+			continue
+		}
+		result := &addonResult{Name: addon.Name, Version: addon.Spec.Version}
+		results = append(results, result)
+
+		blocked := ""
+		for _, dep := range addon.Spec.DependsOn {
+			if failed[dep] {
+				blocked = dep
+				break
+			}
+		}
+		if blocked != "" {
+			result.Status = fmt.Sprintf("Skipped: dependency %q failed", blocked)
+			failed[addon.Name] = true
+			continue
+		}
+
+		result.Status = "Ready"
+		if needUpdates[addon.Name] {
+			update, err := addon.EnsureUpdated(k8sClient, applier, pruneOptions)
+			if err != nil {
+				result.Status = fmt.Sprintf("Failed: %v", err)
+				failed[addon.Name] = true
+				continue
+			}
+			// Could have been a concurrent request
+			if update != nil {
+				result.Status = "Updated"
 			} else {
-				fmt.Printf("Updated %q\n", update.Name)
+				result.Status = "Unchanged"
+			}
+		}
+
+		if err := addon.WaitForReadiness(applier, options.ReadinessTimeout); err != nil {
+			result.Status = fmt.Sprintf("Not ready: %v", err)
+			failed[addon.Name] = true
+		}
+	}
+
+	fmt.Printf("\n")
+
+	{
+		t := &tables.Table{}
+		t.AddColumn("NAME", func(r *addonResult) string {
+			return r.Name
+		})
+		t.AddColumn("VERSION", func(r *addonResult) string {
+			if r.Version == nil {
+				return "-"
 			}
+			return *r.Version
+		})
+		t.AddColumn("STATUS", func(r *addonResult) string {
+			return r.Status
+		})
+
+		columns := []string{"NAME", "VERSION", "STATUS"}
+		err := t.Render(results, os.Stdout, columns...)
+		if err != nil {
+			return err
 		}
 	}
 
 	fmt.Printf("\n")
 
+	if len(failed) != 0 {
+		var names []string
+		for name := range failed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("failed to update addons: %s", strings.Join(names, ", "))
+	}
+
 	return nil
 }
+
+// addonResult holds the outcome of applying an addon, for reporting
+type addonResult struct {
+	Name    string
+	Version *string
+	Status  string
+}
+
+// getKubernetesVersion returns the version of the cluster, for selecting the addon versions that apply to it
+func getKubernetesVersion(k8sClient kubernetes.Interface) (semver.Version, error) {
+	kubernetesVersionInfo, err := k8sClient.Discovery().ServerVersion()
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("error querying kubernetes version: %v", err)
+	}
+
+	//kubernetesVersion, err := semver.Parse(kubernetesVersionInfo.Major + "." + kubernetesVersionInfo.Minor + ".0")
+	//if err != nil {
+	//	return fmt.Errorf("cannot parse kubernetes version %q", kubernetesVersionInfo.Major+"."+kubernetesVersionInfo.Minor + ".0")
+	//}
+
+	kubernetesVersion, err := semver.ParseTolerant(kubernetesVersionInfo.GitVersion)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("cannot parse kubernetes version %q", kubernetesVersionInfo.GitVersion)
+	}
+
+	// Remove Pre and Patch, as they make semver comparisons impractical
+	kubernetesVersion.Pre = nil
+
+	return kubernetesVersion, nil
+}
+
+// loadChannel loads the channel at the url, or the well-known channel with the name
+func loadChannel(name string) (*channels.Addons, error) {
+	location, err := url.Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse argument %q as url", name)
+	}
+	if !location.IsAbs() {
+		// We recognize the following "well-known" format:
+		// <name> with no slashes ->
+		if strings.Contains(name, "/") {
+			return nil, fmt.Errorf("Channel format not recognized (did you mean to use `-f` to specify a local file?): %q", name)
+		}
+		expanded := "https://raw.githubusercontent.com/kubernetes/kops/master/addons/" + name + "/addon.yaml"
+		location, err = url.Parse(expanded)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse expanded argument %q as url", expanded)
+		}
+	}
+	o, err := channels.LoadAddons(name, location)
+	if err != nil {
+		return nil, fmt.Errorf("error loading channel %q: %v", location, err)
+	}
+	return o, nil
+}
+
+// loadChannelFile loads the channel from a file, relative to the current directory
+func loadChannelFile(f string) (*channels.Addons, error) {
+	location, err := url.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse argument %q as url", f)
+	}
+	if !location.IsAbs() {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("error getting current directory: %v", err)
+		}
+		baseURL, err := url.Parse(cwd + string(os.PathSeparator))
+		if err != nil {
+			return nil, fmt.Errorf("error building url for current directory %q: %v", cwd, err)
+		}
+		location = baseURL.ResolveReference(location)
+	}
+	o, err := channels.LoadAddons(f, location)
+	if err != nil {
+		return nil, fmt.Errorf("error loading file %q: %v", f, err)
+	}
+	return o, nil
+}
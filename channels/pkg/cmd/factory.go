@@ -19,7 +19,9 @@ package cmd
 import (
 	"fmt"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -27,16 +29,19 @@ import (
 
 type Factory interface {
 	KubernetesClient() (kubernetes.Interface, error)
+	DynamicClient() (dynamic.Interface, error)
 }
 
 type DefaultFactory struct {
+	restConfig       *rest.Config
 	kubernetesClient kubernetes.Interface
+	dynamicClient    dynamic.Interface
 }
 
 var _ Factory = &DefaultFactory{}
 
-func (f *DefaultFactory) KubernetesClient() (kubernetes.Interface, error) {
-	if f.kubernetesClient == nil {
+func (f *DefaultFactory) buildRESTConfig() (*rest.Config, error) {
+	if f.restConfig == nil {
 		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 		loadingRules.DefaultClientConfig = &clientcmd.DefaultClientConfig
 
@@ -49,6 +54,18 @@ func (f *DefaultFactory) KubernetesClient() (kubernetes.Interface, error) {
 		if err != nil {
 			return nil, fmt.Errorf("cannot load kubecfg settings: %v", err)
 		}
+		f.restConfig = config
+	}
+
+	return f.restConfig, nil
+}
+
+func (f *DefaultFactory) KubernetesClient() (kubernetes.Interface, error) {
+	if f.kubernetesClient == nil {
+		config, err := f.buildRESTConfig()
+		if err != nil {
+			return nil, err
+		}
 
 		k8sClient, err := kubernetes.NewForConfig(config)
 		if err != nil {
@@ -59,3 +76,20 @@ func (f *DefaultFactory) KubernetesClient() (kubernetes.Interface, error) {
 
 	return f.kubernetesClient, nil
 }
+
+func (f *DefaultFactory) DynamicClient() (dynamic.Interface, error) {
+	if f.dynamicClient == nil {
+		config, err := f.buildRESTConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("cannot build dynamic client: %v", err)
+		}
+		f.dynamicClient = dynamicClient
+	}
+
+	return f.dynamicClient, nil
+}
@@ -33,10 +33,13 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"sort"
+	"strings"
 
+	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,22 +47,55 @@ import (
 	"k8s.io/kops/util/pkg/tables"
 )
 
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYaml  = "yaml"
+)
+
+// Statuses of an addon in the report
+const (
+	addonStatusHealthy         = "Healthy"
+	addonStatusDrifted         = "Drifted"
+	addonStatusUnhealthy       = "Unhealthy"
+	addonStatusUpdateAvailable = "UpdateAvailable"
+	addonStatusUnknown         = "Unknown"
+	addonStatusRepaired        = "Repaired"
+	addonStatusRepairFailed    = "RepairFailed"
+)
+
 type GetAddonsOptions struct {
+	Files []string
+
+	// Output is the output format: table, json or yaml
+	Output string
+	// Repair reapplies the addons whose objects no longer match their manifests
+	Repair bool
 }
 
 func NewCmdGetAddons(f Factory, out io.Writer) *cobra.Command {
-	var options GetAddonsOptions
+	options := GetAddonsOptions{
+		Output: OutputTable,
+	}
 
 	cmd := &cobra.Command{
-		Use:     "addons",
+		Use:     "addons [CHANNEL]...",
 		Aliases: []string{"addon"},
 		Short:   "get addons",
-		Long:    `List or get addons.`,
+		Long: `List the installed addons, and report on their health.
+
+The objects of each addon are compared with the manifest of the installed version, from the
+channels specified or, if none are specified, from the channel the addon was installed from.
+Objects that are missing or no longer match the manifest, and workloads that are not ready, are reported.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunGetAddons(f, out, &options)
+			return RunGetAddons(f, out, &options, args)
 		},
 	}
 
+	cmd.Flags().StringSliceVarP(&options.Files, "filename", "f", []string{}, "Compare with the addons in a local file")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of: table, json, yaml")
+	cmd.Flags().BoolVar(&options.Repair, "repair", false, "Reapply the addons that no longer match their manifests")
+
 	return cmd
 }
 
@@ -69,7 +105,30 @@ type addonInfo struct {
 	Namespace *v1.Namespace
 }
 
-func RunGetAddons(f Factory, out io.Writer, options *GetAddonsOptions) error {
+// addonReport is the health of an installed addon
+type addonReport struct {
+	Name      string                   `json:"name"`
+	Namespace string                   `json:"namespace"`
+	Version   string                   `json:"version,omitempty"`
+	Channel   string                   `json:"channel,omitempty"`
+	Status    string                   `json:"status"`
+	Message   string                   `json:"message,omitempty"`
+	Problems  []channels.ObjectProblem `json:"problems,omitempty"`
+}
+
+// objectProblem is an ObjectProblem of an addon, for display in a table
+type objectProblem struct {
+	Addon string
+	channels.ObjectProblem
+}
+
+func RunGetAddons(f Factory, out io.Writer, options *GetAddonsOptions, args []string) error {
+	switch options.Output {
+	case OutputTable, OutputJSON, OutputYaml:
+	default:
+		return fmt.Errorf("unknown output format: %q", options.Output)
+	}
+
 	k8sClient, err := f.KubernetesClient()
 	if err != nil {
 		return err
@@ -96,45 +155,258 @@ func RunGetAddons(f Factory, out io.Writer, options *GetAddonsOptions) error {
 	}
 
 	if len(info) == 0 {
-		fmt.Printf("\nNo managed addons found\n")
+		fmt.Fprintf(out, "\nNo managed addons found\n")
 		return nil
 	}
 
+	sort.Slice(info, func(i, j int) bool {
+		if info[i].Namespace.Name != info[j].Namespace.Name {
+			return info[i].Namespace.Name < info[j].Namespace.Name
+		}
+		return info[i].Name < info[j].Name
+	})
+
+	kubernetesVersion, err := getKubernetesVersion(k8sClient)
+	if err != nil {
+		return err
+	}
+
+	// If channels are specified we compare every addon with them, otherwise with the channel each addon was installed from
+	explicitChannels := len(args) != 0 || len(options.Files) != 0
+	menus := make(map[string]*channels.AddonMenu)
+	menuErrors := make(map[string]error)
+	if explicitChannels {
+		menu := channels.NewAddonMenu()
+		for _, name := range args {
+			o, err := loadChannel(name)
+			if err != nil {
+				return err
+			}
+			current, err := o.GetCurrent(kubernetesVersion)
+			if err != nil {
+				return fmt.Errorf("error processing latest versions in %q: %v", name, err)
+			}
+			menu.MergeAddons(current)
+		}
+		for _, f := range options.Files {
+			o, err := loadChannelFile(f)
+			if err != nil {
+				return err
+			}
+			current, err := o.GetCurrent(kubernetesVersion)
+			if err != nil {
+				return fmt.Errorf("error processing latest versions in %q: %v", f, err)
+			}
+			menu.MergeAddons(current)
+		}
+		menus[""] = menu
+	} else {
+		for _, i := range info {
+			if i.Version == nil || i.Version.Channel == nil {
+				continue
+			}
+			name := *i.Version.Channel
+			if menus[name] != nil || menuErrors[name] != nil {
+				continue
+			}
+			o, err := loadChannel(name)
+			if err == nil {
+				menus[name], err = o.GetCurrent(kubernetesVersion)
+			}
+			if err != nil {
+				menuErrors[name] = err
+			}
+		}
+	}
+
+	dynamicClient, err := f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	applier := channels.NewApplier(dynamicClient, k8sClient.Discovery())
+
+	var reports []*addonReport
+	failedRepairs := 0
+	for _, i := range info {
+		report := &addonReport{
+			Name:      i.Name,
+			Namespace: i.Namespace.Name,
+		}
+		reports = append(reports, report)
+
+		channelName := ""
+		if i.Version != nil {
+			report.Version = stringValue(i.Version.Version)
+			report.Channel = stringValue(i.Version.Channel)
+			if !explicitChannels {
+				channelName = report.Channel
+			}
+		}
+
+		if err := menuErrors[channelName]; err != nil {
+			report.Status = addonStatusUnknown
+			report.Message = err.Error()
+			continue
+		}
+		var addon *channels.Addon
+		if menu := menus[channelName]; menu != nil {
+			addon = menu.Addons[i.Name]
+		}
+		if addon == nil {
+			report.Status = addonStatusUnknown
+			report.Message = "addon not found in channel"
+			continue
+		}
+
+		update, err := addon.GetRequiredUpdates(k8sClient)
+		if err != nil {
+			report.Status = addonStatusUnknown
+			report.Message = err.Error()
+			continue
+		}
+		if update != nil {
+			report.Status = addonStatusUpdateAvailable
+			if update.NewVersion != nil {
+				report.Message = "channel has version " + stringValue(update.NewVersion.Version)
+			}
+			continue
+		}
+
+		problems, err := addon.CheckDrift(k8sClient, applier)
+		if err != nil {
+			report.Status = addonStatusUnknown
+			report.Message = err.Error()
+			continue
+		}
+		report.Problems = problems
+
+		report.Status = addonStatusHealthy
+		for _, problem := range problems {
+			if problem.Problem == channels.ProblemUnhealthy {
+				report.Status = addonStatusUnhealthy
+			}
+		}
+		for _, problem := range problems {
+			if problem.Problem != channels.ProblemUnhealthy {
+				report.Status = addonStatusDrifted
+			}
+		}
+
+		if options.Repair && report.Status == addonStatusDrifted {
+			if err := addon.Repair(k8sClient, applier); err != nil {
+				report.Status = addonStatusRepairFailed
+				report.Message = err.Error()
+				failedRepairs++
+			} else {
+				report.Status = addonStatusRepaired
+			}
+		}
+	}
+
+	switch options.Output {
+	case OutputJSON:
+		b, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %v", err)
+		}
+		if _, err := out.Write(append(b, '\n')); err != nil {
+			return err
+		}
+
+	case OutputYaml:
+		b, err := yaml.Marshal(reports)
+		if err != nil {
+			return fmt.Errorf("error marshaling yaml: %v", err)
+		}
+		if _, err := out.Write(b); err != nil {
+			return err
+		}
+
+	case OutputTable:
+		if err := addonReportTable(reports, out); err != nil {
+			return err
+		}
+	}
+
+	if failedRepairs != 0 {
+		return fmt.Errorf("failed to repair %d addons", failedRepairs)
+	}
+	return nil
+}
+
+func addonReportTable(reports []*addonReport, out io.Writer) error {
 	{
 		t := &tables.Table{}
-		t.AddColumn("NAME", func(r *addonInfo) string {
+		t.AddColumn("NAME", func(r *addonReport) string {
 			return r.Name
 		})
-		t.AddColumn("NAMESPACE", func(r *addonInfo) string {
-			return r.Namespace.Name
+		t.AddColumn("NAMESPACE", func(r *addonReport) string {
+			return r.Namespace
 		})
-		t.AddColumn("VERSION", func(r *addonInfo) string {
-			if r.Version == nil {
-				return "-"
+		t.AddColumn("VERSION", func(r *addonReport) string {
+			if r.Version == "" {
+				return "?"
 			}
-			if r.Version.Version != nil {
-				return *r.Version.Version
-			}
-			return "?"
+			return r.Version
 		})
-		t.AddColumn("CHANNEL", func(r *addonInfo) string {
-			if r.Version == nil {
-				return "-"
-			}
-			if r.Version.Channel != nil {
-				return *r.Version.Channel
+		t.AddColumn("CHANNEL", func(r *addonReport) string {
+			if r.Channel == "" {
+				return "?"
 			}
-			return "?"
+			return r.Channel
+		})
+		t.AddColumn("STATUS", func(r *addonReport) string {
+			return r.Status
+		})
+		t.AddColumn("MESSAGE", func(r *addonReport) string {
+			return r.Message
 		})
 
-		columns := []string{"NAMESPACE", "NAME", "VERSION", "CHANNEL"}
-		err := t.Render(info, os.Stdout, columns...)
+		columns := []string{"NAMESPACE", "NAME", "VERSION", "CHANNEL", "STATUS", "MESSAGE"}
+		err := t.Render(reports, out, columns...)
 		if err != nil {
 			return err
 		}
 	}
 
-	fmt.Printf("\n")
+	var problems []*objectProblem
+	for _, r := range reports {
+		for _, p := range r.Problems {
+			problems = append(problems, &objectProblem{Addon: r.Name, ObjectProblem: p})
+		}
+	}
+	if len(problems) != 0 {
+		fmt.Fprintf(out, "\n")
+
+		t := &tables.Table{}
+		t.AddColumn("ADDON", func(r *objectProblem) string {
+			return r.Addon
+		})
+		t.AddColumn("OBJECT", func(r *objectProblem) string {
+			return r.Object.String()
+		})
+		t.AddColumn("PROBLEM", func(r *objectProblem) string {
+			return r.Problem
+		})
+		t.AddColumn("MESSAGE", func(r *objectProblem) string {
+			return strings.TrimSpace(r.Message)
+		})
+
+		columns := []string{"ADDON", "OBJECT", "PROBLEM", "MESSAGE"}
+		err := t.Render(problems, out, columns...)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "\n")
 
 	return nil
 }
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
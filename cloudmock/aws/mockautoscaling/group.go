@@ -151,6 +151,45 @@ func (m *MockAutoscaling) DescribeAutoScalingGroups(input *autoscaling.DescribeA
 	}, nil
 }
 
+func (m *MockAutoscaling) UpdateAutoScalingGroup(input *autoscaling.UpdateAutoScalingGroupInput) (*autoscaling.UpdateAutoScalingGroupOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("UpdateAutoScalingGroup %v", input)
+
+	g := m.Groups[aws.StringValue(input.AutoScalingGroupName)]
+	if g == nil {
+		return nil, fmt.Errorf("AutoScaling Group not found")
+	}
+
+	if input.LaunchConfigurationName != nil {
+		g.LaunchConfigurationName = input.LaunchConfigurationName
+		g.LaunchTemplate = nil
+		g.MixedInstancesPolicy = nil
+	}
+	if input.LaunchTemplate != nil {
+		g.LaunchConfigurationName = nil
+		g.LaunchTemplate = input.LaunchTemplate
+		g.MixedInstancesPolicy = nil
+	}
+	if input.MixedInstancesPolicy != nil {
+		g.LaunchConfigurationName = nil
+		g.LaunchTemplate = nil
+		g.MixedInstancesPolicy = input.MixedInstancesPolicy
+	}
+	if input.MinSize != nil {
+		g.MinSize = input.MinSize
+	}
+	if input.MaxSize != nil {
+		g.MaxSize = input.MaxSize
+	}
+	if input.DesiredCapacity != nil {
+		g.DesiredCapacity = input.DesiredCapacity
+	}
+
+	return &autoscaling.UpdateAutoScalingGroupOutput{}, nil
+}
+
 func (m *MockAutoscaling) TerminateInstanceInAutoScalingGroup(input *autoscaling.TerminateInstanceInAutoScalingGroupInput) (*autoscaling.TerminateInstanceInAutoScalingGroupOutput, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
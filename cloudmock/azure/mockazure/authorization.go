@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockazure
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// MockRoleAssignments is a mock implementation of azureup.RoleAssignmentsClient
+type MockRoleAssignments struct {
+	mutex sync.Mutex
+
+	// RoleAssignments is keyed by the ID of the role assignment
+	RoleAssignments map[string]*azureup.RoleAssignment
+}
+
+var _ azureup.RoleAssignmentsClient = &MockRoleAssignments{}
+
+func (m *MockRoleAssignments) Create(ctx context.Context, scope, name string, parameters azureup.RoleAssignment) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Create RoleAssignment %q in %q", name, scope)
+
+	if m.RoleAssignments == nil {
+		m.RoleAssignments = make(map[string]*azureup.RoleAssignment)
+	}
+	parameters.Name = name
+	parameters.ID = scope + "/providers/Microsoft.Authorization/roleAssignments/" + name
+	parameters.Properties.Scope = scope
+	m.RoleAssignments[parameters.ID] = &parameters
+	return nil
+}
+
+func (m *MockRoleAssignments) List(ctx context.Context, scope string) ([]*azureup.RoleAssignment, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.RoleAssignment
+	for _, ra := range m.RoleAssignments {
+		if !strings.HasPrefix(strings.ToLower(ra.Properties.Scope), strings.ToLower(scope)) {
+			continue
+		}
+		c := *ra
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockRoleAssignments) Delete(ctx context.Context, scope, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete RoleAssignment %q in %q", name, scope)
+
+	delete(m.RoleAssignments, scope+"/providers/Microsoft.Authorization/roleAssignments/"+name)
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockazure
+
+import (
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// MockSubscriptionID is the subscription ID used in the IDs of mock resources
+const MockSubscriptionID = "00000000-0000-0000-0000-000000000000"
+
+// BuildMockAzureCloud builds an azureup.MockAzureCloud with all the mock clients set
+func BuildMockAzureCloud(location string, resourceGroupName string, tags map[string]string) *azureup.MockAzureCloud {
+	scaleSets := &MockVMScaleSets{}
+	virtualNetworks := &MockVirtualNetworks{}
+	return &azureup.MockAzureCloud{
+		Location:     location,
+		Subscription: MockSubscriptionID,
+		GroupName:    resourceGroupName,
+		ClusterTags:  tags,
+
+		MockResourceGroups:        &MockResourceGroups{},
+		MockVirtualNetworks:       virtualNetworks,
+		MockSubnets:               &MockSubnets{VirtualNetworks: virtualNetworks},
+		MockNetworkSecurityGroups: &MockNetworkSecurityGroups{},
+		MockRouteTables:           &MockRouteTables{},
+		MockPublicIPAddresses:     &MockPublicIPAddresses{},
+		MockLoadBalancers:         &MockLoadBalancers{},
+		MockVMScaleSets:           scaleSets,
+		MockVMScaleSetVMs:         &MockVMScaleSetVMs{ScaleSets: scaleSets},
+		MockNetworkInterfaces:     &MockNetworkInterfaces{ScaleSets: scaleSets},
+		MockDisks:                 &MockDisks{},
+		MockRoleAssignments:       &MockRoleAssignments{},
+	}
+}
+
+func key(names ...string) string {
+	k := ""
+	for i, name := range names {
+		if i != 0 {
+			k += "/"
+		}
+		k += name
+	}
+	return k
+}
+
+// All returns all the resources of the mock cloud, keyed by the type of resource and the key of the mock
+// (the name for role assignments, as their IDs already include the scope)
+func All(c *azureup.MockAzureCloud) map[string]interface{} {
+	all := make(map[string]interface{})
+	add := func(kind string, k string, v interface{}) {
+		all[kind+"/"+k] = v
+	}
+
+	if m, ok := c.MockResourceGroups.(*MockResourceGroups); ok {
+		m.mutex.Lock()
+		for k, v := range m.ResourceGroups {
+			add("resourceGroups", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockVirtualNetworks.(*MockVirtualNetworks); ok {
+		m.mutex.Lock()
+		for k, v := range m.VirtualNetworks {
+			add("virtualNetworks", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockSubnets.(*MockSubnets); ok {
+		m.mutex.Lock()
+		for k, v := range m.Subnets {
+			add("subnets", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockNetworkSecurityGroups.(*MockNetworkSecurityGroups); ok {
+		m.mutex.Lock()
+		for k, v := range m.NetworkSecurityGroups {
+			add("networkSecurityGroups", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockRouteTables.(*MockRouteTables); ok {
+		m.mutex.Lock()
+		for k, v := range m.RouteTables {
+			add("routeTables", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockPublicIPAddresses.(*MockPublicIPAddresses); ok {
+		m.mutex.Lock()
+		for k, v := range m.PublicIPAddresses {
+			add("publicIPAddresses", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockLoadBalancers.(*MockLoadBalancers); ok {
+		m.mutex.Lock()
+		for k, v := range m.LoadBalancers {
+			add("loadBalancers", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockVMScaleSets.(*MockVMScaleSets); ok {
+		m.mutex.Lock()
+		for k, v := range m.VMScaleSets {
+			add("virtualMachineScaleSets", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockDisks.(*MockDisks); ok {
+		m.mutex.Lock()
+		for k, v := range m.Disks {
+			add("disks", k, v)
+		}
+		m.mutex.Unlock()
+	}
+	if m, ok := c.MockRoleAssignments.(*MockRoleAssignments); ok {
+		m.mutex.Lock()
+		for _, v := range m.RoleAssignments {
+			add("roleAssignments", v.Name, v)
+		}
+		m.mutex.Unlock()
+	}
+	return all
+}
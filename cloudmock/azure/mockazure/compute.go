@@ -0,0 +1,290 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockazure
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// MockVMScaleSets is a mock implementation of azureup.VMScaleSetsClient.
+// VMs are created and removed to match the capacity of each scale set.
+type MockVMScaleSets struct {
+	mutex sync.Mutex
+
+	// VMScaleSets is keyed by resource group and name
+	VMScaleSets map[string]*azureup.VirtualMachineScaleSet
+	// VMs is keyed by resource group and scale set name, then by instance ID
+	VMs map[string]map[string]*azureup.VirtualMachineScaleSetVM
+
+	instanceCount int
+}
+
+var _ azureup.VMScaleSetsClient = &MockVMScaleSets{}
+
+func (m *MockVMScaleSets) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters azureup.VirtualMachineScaleSet) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate VMScaleSet %q", name)
+
+	if m.VMScaleSets == nil {
+		m.VMScaleSets = make(map[string]*azureup.VirtualMachineScaleSet)
+	}
+	if m.VMs == nil {
+		m.VMs = make(map[string]map[string]*azureup.VirtualMachineScaleSetVM)
+	}
+
+	k := key(resourceGroupName, name)
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Compute/virtualMachineScaleSets", name)
+	if parameters.Identity != nil && parameters.Identity.Type != "" {
+		parameters.Identity.PrincipalID = "principal-" + name
+	}
+
+	vms := m.VMs[k]
+	if vms == nil {
+		vms = make(map[string]*azureup.VirtualMachineScaleSetVM)
+		m.VMs[k] = vms
+	}
+
+	// Changing the model leaves the existing VMs on the previous model
+	if existing := m.VMScaleSets[k]; existing != nil && !reflect.DeepEqual(existing.Properties.VirtualMachineProfile, parameters.Properties.VirtualMachineProfile) {
+		for _, vm := range vms {
+			vm.Properties.LatestModelApplied = boolPointer(false)
+		}
+	}
+	m.VMScaleSets[k] = &parameters
+
+	capacity := 0
+	if parameters.Sku != nil && parameters.Sku.Capacity != nil {
+		capacity = int(*parameters.Sku.Capacity)
+	}
+	for len(vms) < capacity {
+		instanceID := strconv.Itoa(m.instanceCount)
+		m.instanceCount++
+		vms[instanceID] = &azureup.VirtualMachineScaleSetVM{
+			ID:         parameters.ID + "/virtualMachines/" + instanceID,
+			Name:       name + "_" + instanceID,
+			InstanceID: instanceID,
+			Location:   parameters.Location,
+			Tags:       parameters.Tags,
+			Properties: azureup.VirtualMachineScaleSetVMProperties{
+				LatestModelApplied: boolPointer(true),
+				StorageProfile:     &azureup.StorageProfile{},
+			},
+		}
+	}
+	for instanceID := range vms {
+		if len(vms) <= capacity {
+			break
+		}
+		delete(vms, instanceID)
+	}
+	return nil
+}
+
+func (m *MockVMScaleSets) List(ctx context.Context, resourceGroupName string) ([]*azureup.VirtualMachineScaleSet, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.VirtualMachineScaleSet
+	for k, vmss := range m.VMScaleSets {
+		if k != key(resourceGroupName, vmss.Name) {
+			continue
+		}
+		c := *vmss
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockVMScaleSets) Delete(ctx context.Context, resourceGroupName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete VMScaleSet %q", name)
+
+	delete(m.VMScaleSets, key(resourceGroupName, name))
+	delete(m.VMs, key(resourceGroupName, name))
+	return nil
+}
+
+// MockVMScaleSetVMs is a mock implementation of azureup.VMScaleSetVMsClient, operating on the VMs of ScaleSets
+type MockVMScaleSetVMs struct {
+	ScaleSets *MockVMScaleSets
+}
+
+var _ azureup.VMScaleSetVMsClient = &MockVMScaleSetVMs{}
+
+func (m *MockVMScaleSetVMs) Get(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string) (*azureup.VirtualMachineScaleSetVM, error) {
+	m.ScaleSets.mutex.Lock()
+	defer m.ScaleSets.mutex.Unlock()
+
+	vm := m.ScaleSets.VMs[key(resourceGroupName, vmScaleSetName)][instanceID]
+	if vm == nil {
+		return nil, nil
+	}
+	c := *vm
+	return &c, nil
+}
+
+func (m *MockVMScaleSetVMs) Update(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string, parameters azureup.VirtualMachineScaleSetVM) error {
+	m.ScaleSets.mutex.Lock()
+	defer m.ScaleSets.mutex.Unlock()
+
+	klog.V(2).Infof("Update VMScaleSetVM %q in %q", instanceID, vmScaleSetName)
+
+	vms := m.ScaleSets.VMs[key(resourceGroupName, vmScaleSetName)]
+	if vms[instanceID] == nil {
+		return fmt.Errorf("VM %q not found in VM scale set %q", instanceID, vmScaleSetName)
+	}
+	vms[instanceID] = &parameters
+	return nil
+}
+
+func (m *MockVMScaleSetVMs) List(ctx context.Context, resourceGroupName, vmScaleSetName string) ([]*azureup.VirtualMachineScaleSetVM, error) {
+	m.ScaleSets.mutex.Lock()
+	defer m.ScaleSets.mutex.Unlock()
+
+	var l []*azureup.VirtualMachineScaleSetVM
+	for _, vm := range m.ScaleSets.VMs[key(resourceGroupName, vmScaleSetName)] {
+		c := *vm
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockVMScaleSetVMs) Delete(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string) error {
+	m.ScaleSets.mutex.Lock()
+	defer m.ScaleSets.mutex.Unlock()
+
+	klog.V(2).Infof("Delete VMScaleSetVM %q in %q", instanceID, vmScaleSetName)
+
+	k := key(resourceGroupName, vmScaleSetName)
+	delete(m.ScaleSets.VMs[k], instanceID)
+
+	// As in Azure, deleting a VM reduces the capacity of the scale set
+	if vmss := m.ScaleSets.VMScaleSets[k]; vmss != nil && vmss.Sku != nil && vmss.Sku.Capacity != nil {
+		capacity := int64(len(m.ScaleSets.VMs[k]))
+		vmss.Sku.Capacity = &capacity
+	}
+	return nil
+}
+
+// MockNetworkInterfaces is a mock implementation of azureup.NetworkInterfacesClient.
+// Each VM in ScaleSets has a single network interface.
+type MockNetworkInterfaces struct {
+	ScaleSets *MockVMScaleSets
+}
+
+var _ azureup.NetworkInterfacesClient = &MockNetworkInterfaces{}
+
+func (m *MockNetworkInterfaces) ListScaleSetNetworkInterfaces(ctx context.Context, resourceGroupName, vmScaleSetName string) ([]*azureup.NetworkInterface, error) {
+	m.ScaleSets.mutex.Lock()
+	defer m.ScaleSets.mutex.Unlock()
+
+	var l []*azureup.NetworkInterface
+	for instanceID, vm := range m.ScaleSets.VMs[key(resourceGroupName, vmScaleSetName)] {
+		n, err := strconv.Atoi(instanceID)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected instance ID %q", instanceID)
+		}
+		primary := true
+		l = append(l, &azureup.NetworkInterface{
+			ID:   vm.ID + "/networkInterfaces/nic",
+			Name: "nic",
+			Properties: azureup.NetworkInterfaceProperties{
+				Primary: &primary,
+				IPConfigurations: []*azureup.IPConfiguration{
+					{
+						Name: "ipconfig",
+						Properties: azureup.IPConfigurationProperties{
+							Primary:          true,
+							PrivateIPAddress: fmt.Sprintf("10.0.%d.%d", n/250, n%250+4),
+						},
+					},
+				},
+				VirtualMachine: &azureup.SubResource{ID: vm.ID},
+			},
+		})
+	}
+	return l, nil
+}
+
+// MockDisks is a mock implementation of azureup.DisksClient
+type MockDisks struct {
+	mutex sync.Mutex
+
+	// Disks is keyed by resource group and name
+	Disks map[string]*azureup.Disk
+}
+
+var _ azureup.DisksClient = &MockDisks{}
+
+func (m *MockDisks) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters azureup.Disk) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate Disk %q", name)
+
+	if m.Disks == nil {
+		m.Disks = make(map[string]*azureup.Disk)
+	}
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Compute/disks", name)
+	if parameters.Properties.DiskState == "" {
+		parameters.Properties.DiskState = "Unattached"
+	}
+	m.Disks[key(resourceGroupName, name)] = &parameters
+	return nil
+}
+
+func (m *MockDisks) List(ctx context.Context, resourceGroupName string) ([]*azureup.Disk, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.Disk
+	for k, disk := range m.Disks {
+		if k != key(resourceGroupName, disk.Name) {
+			continue
+		}
+		c := *disk
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockDisks) Delete(ctx context.Context, resourceGroupName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete Disk %q", name)
+
+	delete(m.Disks, key(resourceGroupName, name))
+	return nil
+}
+
+func boolPointer(b bool) *bool {
+	return &b
+}
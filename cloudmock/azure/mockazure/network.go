@@ -0,0 +1,366 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockazure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// MockVirtualNetworks is a mock implementation of azureup.VirtualNetworksClient
+type MockVirtualNetworks struct {
+	mutex sync.Mutex
+
+	// VirtualNetworks is keyed by resource group and name
+	VirtualNetworks map[string]*azureup.VirtualNetwork
+}
+
+var _ azureup.VirtualNetworksClient = &MockVirtualNetworks{}
+
+func (m *MockVirtualNetworks) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters azureup.VirtualNetwork) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate VirtualNetwork %q", name)
+
+	if m.VirtualNetworks == nil {
+		m.VirtualNetworks = make(map[string]*azureup.VirtualNetwork)
+	}
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Network/virtualNetworks", name)
+	m.VirtualNetworks[key(resourceGroupName, name)] = &parameters
+	return nil
+}
+
+func (m *MockVirtualNetworks) List(ctx context.Context, resourceGroupName string) ([]*azureup.VirtualNetwork, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.VirtualNetwork
+	for k, vnet := range m.VirtualNetworks {
+		if k != key(resourceGroupName, vnet.Name) {
+			continue
+		}
+		c := *vnet
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockVirtualNetworks) Delete(ctx context.Context, resourceGroupName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete VirtualNetwork %q", name)
+
+	delete(m.VirtualNetworks, key(resourceGroupName, name))
+	return nil
+}
+
+func (m *MockVirtualNetworks) exists(resourceGroupName, name string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.VirtualNetworks[key(resourceGroupName, name)] != nil
+}
+
+// MockSubnets is a mock implementation of azureup.SubnetsClient
+type MockSubnets struct {
+	mutex sync.Mutex
+
+	VirtualNetworks *MockVirtualNetworks
+
+	// Subnets is keyed by resource group, virtual network and name
+	Subnets map[string]*azureup.Subnet
+}
+
+var _ azureup.SubnetsClient = &MockSubnets{}
+
+func (m *MockSubnets) CreateOrUpdate(ctx context.Context, resourceGroupName, virtualNetworkName, name string, parameters azureup.Subnet) error {
+	if m.VirtualNetworks != nil && !m.VirtualNetworks.exists(resourceGroupName, virtualNetworkName) {
+		return fmt.Errorf("virtual network %q not found", virtualNetworkName)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate Subnet %q", name)
+
+	if m.Subnets == nil {
+		m.Subnets = make(map[string]*azureup.Subnet)
+	}
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Network/virtualNetworks", virtualNetworkName, "subnets", name)
+	m.Subnets[key(resourceGroupName, virtualNetworkName, name)] = &parameters
+	return nil
+}
+
+func (m *MockSubnets) List(ctx context.Context, resourceGroupName, virtualNetworkName string) ([]*azureup.Subnet, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.Subnet
+	for k, subnet := range m.Subnets {
+		if k != key(resourceGroupName, virtualNetworkName, subnet.Name) {
+			continue
+		}
+		c := *subnet
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockSubnets) Delete(ctx context.Context, resourceGroupName, virtualNetworkName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete Subnet %q", name)
+
+	delete(m.Subnets, key(resourceGroupName, virtualNetworkName, name))
+	return nil
+}
+
+// MockNetworkSecurityGroups is a mock implementation of azureup.NetworkSecurityGroupsClient
+type MockNetworkSecurityGroups struct {
+	mutex sync.Mutex
+
+	// NetworkSecurityGroups is keyed by resource group and name
+	NetworkSecurityGroups map[string]*azureup.NetworkSecurityGroup
+}
+
+var _ azureup.NetworkSecurityGroupsClient = &MockNetworkSecurityGroups{}
+
+func (m *MockNetworkSecurityGroups) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters azureup.NetworkSecurityGroup) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate NetworkSecurityGroup %q", name)
+
+	if m.NetworkSecurityGroups == nil {
+		m.NetworkSecurityGroups = make(map[string]*azureup.NetworkSecurityGroup)
+	}
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Network/networkSecurityGroups", name)
+	for _, rule := range parameters.Properties.SecurityRules {
+		rule.ID = parameters.ID + "/securityRules/" + rule.Name
+	}
+	m.NetworkSecurityGroups[key(resourceGroupName, name)] = &parameters
+	return nil
+}
+
+func (m *MockNetworkSecurityGroups) List(ctx context.Context, resourceGroupName string) ([]*azureup.NetworkSecurityGroup, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.NetworkSecurityGroup
+	for k, nsg := range m.NetworkSecurityGroups {
+		if k != key(resourceGroupName, nsg.Name) {
+			continue
+		}
+		c := *nsg
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockNetworkSecurityGroups) Delete(ctx context.Context, resourceGroupName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete NetworkSecurityGroup %q", name)
+
+	delete(m.NetworkSecurityGroups, key(resourceGroupName, name))
+	return nil
+}
+
+// MockRouteTables is a mock implementation of azureup.RouteTablesClient
+type MockRouteTables struct {
+	mutex sync.Mutex
+
+	// RouteTables is keyed by resource group and name
+	RouteTables map[string]*azureup.RouteTable
+}
+
+var _ azureup.RouteTablesClient = &MockRouteTables{}
+
+func (m *MockRouteTables) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters azureup.RouteTable) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate RouteTable %q", name)
+
+	if m.RouteTables == nil {
+		m.RouteTables = make(map[string]*azureup.RouteTable)
+	}
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Network/routeTables", name)
+	m.RouteTables[key(resourceGroupName, name)] = &parameters
+	return nil
+}
+
+func (m *MockRouteTables) List(ctx context.Context, resourceGroupName string) ([]*azureup.RouteTable, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.RouteTable
+	for k, rt := range m.RouteTables {
+		if k != key(resourceGroupName, rt.Name) {
+			continue
+		}
+		c := *rt
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockRouteTables) Delete(ctx context.Context, resourceGroupName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete RouteTable %q", name)
+
+	delete(m.RouteTables, key(resourceGroupName, name))
+	return nil
+}
+
+// MockPublicIPAddresses is a mock implementation of azureup.PublicIPAddressesClient
+type MockPublicIPAddresses struct {
+	mutex sync.Mutex
+
+	// PublicIPAddresses is keyed by resource group and name
+	PublicIPAddresses map[string]*azureup.PublicIPAddress
+
+	ipCount int
+}
+
+var _ azureup.PublicIPAddressesClient = &MockPublicIPAddresses{}
+
+func (m *MockPublicIPAddresses) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters azureup.PublicIPAddress) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate PublicIPAddress %q", name)
+
+	if m.PublicIPAddresses == nil {
+		m.PublicIPAddresses = make(map[string]*azureup.PublicIPAddress)
+	}
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Network/publicIPAddresses", name)
+	if existing := m.PublicIPAddresses[key(resourceGroupName, name)]; existing != nil {
+		parameters.Properties.IPAddress = existing.Properties.IPAddress
+	} else {
+		m.ipCount++
+		parameters.Properties.IPAddress = fmt.Sprintf("192.0.2.%d", m.ipCount)
+	}
+	m.PublicIPAddresses[key(resourceGroupName, name)] = &parameters
+	return nil
+}
+
+func (m *MockPublicIPAddresses) List(ctx context.Context, resourceGroupName string) ([]*azureup.PublicIPAddress, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.PublicIPAddress
+	for k, pip := range m.PublicIPAddresses {
+		if k != key(resourceGroupName, pip.Name) {
+			continue
+		}
+		c := *pip
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockPublicIPAddresses) Delete(ctx context.Context, resourceGroupName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete PublicIPAddress %q", name)
+
+	delete(m.PublicIPAddresses, key(resourceGroupName, name))
+	return nil
+}
+
+// MockLoadBalancers is a mock implementation of azureup.LoadBalancersClient
+type MockLoadBalancers struct {
+	mutex sync.Mutex
+
+	// LoadBalancers is keyed by resource group and name
+	LoadBalancers map[string]*azureup.LoadBalancer
+}
+
+var _ azureup.LoadBalancersClient = &MockLoadBalancers{}
+
+func (m *MockLoadBalancers) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters azureup.LoadBalancer) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate LoadBalancer %q", name)
+
+	if m.LoadBalancers == nil {
+		m.LoadBalancers = make(map[string]*azureup.LoadBalancer)
+	}
+	parameters.Name = name
+	parameters.ID = azureup.ResourceID(MockSubscriptionID, resourceGroupName, "Microsoft.Network/loadBalancers", name)
+	for _, o := range parameters.Properties.FrontendIPConfigurations {
+		o.ID = parameters.ID + "/frontendIPConfigurations/" + o.Name
+		// Internal frontends are allocated an address from their subnet
+		if o.Properties.Subnet != nil && o.Properties.PrivateIPAddress == "" {
+			o.Properties.PrivateIPAddress = "10.0.0.4"
+		}
+	}
+	for _, o := range parameters.Properties.BackendAddressPools {
+		o.ID = parameters.ID + "/backendAddressPools/" + o.Name
+	}
+	for _, o := range parameters.Properties.LoadBalancingRules {
+		o.ID = parameters.ID + "/loadBalancingRules/" + o.Name
+	}
+	for _, o := range parameters.Properties.Probes {
+		o.ID = parameters.ID + "/probes/" + o.Name
+	}
+	m.LoadBalancers[key(resourceGroupName, name)] = &parameters
+	return nil
+}
+
+func (m *MockLoadBalancers) List(ctx context.Context, resourceGroupName string) ([]*azureup.LoadBalancer, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.LoadBalancer
+	for k, lb := range m.LoadBalancers {
+		if k != key(resourceGroupName, lb.Name) {
+			continue
+		}
+		c := *lb
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockLoadBalancers) Delete(ctx context.Context, resourceGroupName, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete LoadBalancer %q", name)
+
+	delete(m.LoadBalancers, key(resourceGroupName, name))
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockazure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// MockResourceGroups is a mock implementation of azureup.ResourceGroupsClient
+type MockResourceGroups struct {
+	mutex sync.Mutex
+
+	ResourceGroups map[string]*azureup.ResourceGroup
+}
+
+var _ azureup.ResourceGroupsClient = &MockResourceGroups{}
+
+func (m *MockResourceGroups) CreateOrUpdate(ctx context.Context, name string, parameters azureup.ResourceGroup) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("CreateOrUpdate ResourceGroup %q", name)
+
+	if m.ResourceGroups == nil {
+		m.ResourceGroups = make(map[string]*azureup.ResourceGroup)
+	}
+	parameters.Name = name
+	parameters.ID = fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", MockSubscriptionID, name)
+	m.ResourceGroups[name] = &parameters
+	return nil
+}
+
+func (m *MockResourceGroups) List(ctx context.Context) ([]*azureup.ResourceGroup, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var l []*azureup.ResourceGroup
+	for _, rg := range m.ResourceGroups {
+		c := *rg
+		l = append(l, &c)
+	}
+	return l, nil
+}
+
+func (m *MockResourceGroups) Delete(ctx context.Context, name string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.V(2).Infof("Delete ResourceGroup %q", name)
+
+	delete(m.ResourceGroups, name)
+	return nil
+}
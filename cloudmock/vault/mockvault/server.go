@@ -0,0 +1,361 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockvault
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockVault is an in-memory stand-in for a dev-mode HashiCorp Vault server.
+// It implements enough of the KV version 2 and PKI secrets engines, and of cloud auth login, to test kops against.
+// Any mount path can be used for either engine; the engine is inferred from the request path.
+type MockVault struct {
+	// RootToken is accepted for every request
+	RootToken string
+
+	// PKIRoles are the PKI roles that can issue certificates, keyed by <mount>/<role>, with the organizations of issued certificates
+	PKIRoles map[string][]string
+
+	// LoginRoles are the roles that can be logged in to, keyed by <auth mount>/<role>, with the token that is returned
+	LoginRoles map[string]string
+
+	// LoginRequests records the body of every login request
+	LoginRequests []map[string]interface{}
+
+	mutex sync.Mutex
+	kv    map[string]*kvEntry
+	cas   map[string]*caEntry
+}
+
+type kvEntry struct {
+	version int
+	data    map[string]interface{}
+}
+
+type caEntry struct {
+	certificate *x509.Certificate
+	privateKey  *rsa.PrivateKey
+}
+
+var _ http.Handler = &MockVault{}
+
+// NewMockVault builds a MockVault which accepts the given root token
+func NewMockVault(rootToken string) *MockVault {
+	return &MockVault{
+		RootToken:  rootToken,
+		PKIRoles:   make(map[string][]string),
+		LoginRoles: make(map[string]string),
+		kv:         make(map[string]*kvEntry),
+		cas:        make(map[string]*caEntry),
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (m *MockVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, "/v1/") {
+		writeError(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+
+	var body map[string]interface{}
+	if r.Body != nil {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(data) != 0 {
+			if err := json.Unmarshal(data, &body); err != nil {
+				writeError(w, http.StatusBadRequest, "failed to parse JSON input: "+err.Error())
+				return
+			}
+		}
+	}
+
+	if strings.HasPrefix(path, "auth/") && strings.HasSuffix(path, "/login") {
+		m.login(w, strings.TrimSuffix(strings.TrimPrefix(path, "auth/"), "/login"), body)
+		return
+	}
+
+	if !m.isAuthorized(r.Header.Get("X-Vault-Token")) {
+		writeError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	method := r.Method
+	if method == http.MethodGet && r.URL.Query().Get("list") == "true" {
+		method = "LIST"
+	}
+
+	tokens := strings.SplitN(path, "/", 3)
+	if len(tokens) < 2 {
+		writeError(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+	mount, op, rest := tokens[0], tokens[1], ""
+	if len(tokens) == 3 {
+		rest = tokens[2]
+	}
+
+	switch {
+	case op == "data" && method == http.MethodGet:
+		m.readKV(w, mount+"/"+rest)
+	case op == "data" && (method == http.MethodPost || method == http.MethodPut):
+		m.writeKV(w, mount+"/"+rest, body)
+	case op == "metadata" && method == "LIST":
+		m.listKV(w, mount+"/"+rest)
+	case op == "metadata" && method == http.MethodDelete:
+		delete(m.kv, mount+"/"+rest)
+		w.WriteHeader(http.StatusNoContent)
+	case op == "config" && rest == "ca" && method == http.MethodPost:
+		m.configureCA(w, mount, body)
+	case op == "issue" && method == http.MethodPost:
+		m.issue(w, mount, rest, body)
+	default:
+		writeError(w, http.StatusNotFound, "no handler for route")
+	}
+}
+
+func (m *MockVault) isAuthorized(token string) bool {
+	if token == "" {
+		return false
+	}
+	if token == m.RootToken {
+		return true
+	}
+	for _, t := range m.LoginRoles {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockVault) login(w http.ResponseWriter, mount string, body map[string]interface{}) {
+	m.LoginRequests = append(m.LoginRequests, body)
+
+	role, _ := body["role"].(string)
+	token, found := m.LoginRoles[mount+"/"+role]
+	if !found {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("role %q could not be found", role))
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token": token,
+		},
+	})
+}
+
+func (m *MockVault) readKV(w http.ResponseWriter, key string) {
+	entry := m.kv[key]
+	if entry == nil {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"data": map[string]interface{}{
+			"data": entry.data,
+			"metadata": map[string]interface{}{
+				"version": entry.version,
+			},
+		},
+	})
+}
+
+func (m *MockVault) writeKV(w http.ResponseWriter, key string, body map[string]interface{}) {
+	data, ok := body["data"].(map[string]interface{})
+	if !ok {
+		writeError(w, http.StatusBadRequest, "no data provided")
+		return
+	}
+
+	entry := m.kv[key]
+	current := 0
+	if entry != nil {
+		current = entry.version
+	}
+	if options, ok := body["options"].(map[string]interface{}); ok {
+		if cas, ok := options["cas"].(float64); ok && int(cas) != current {
+			writeError(w, http.StatusBadRequest, "check-and-set parameter did not match the current version")
+			return
+		}
+	}
+
+	m.kv[key] = &kvEntry{version: current + 1, data: data}
+	writeJSON(w, map[string]interface{}{
+		"data": map[string]interface{}{
+			"version": current + 1,
+		},
+	})
+}
+
+func (m *MockVault) listKV(w http.ResponseWriter, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+
+	found := make(map[string]bool)
+	for key := range m.kv {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, prefix)
+		if i := strings.Index(name, "/"); i != -1 {
+			name = name[:i+1]
+		}
+		found[name] = true
+	}
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound)
+		return
+	}
+
+	var keys []string
+	for k := range found {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	writeJSON(w, map[string]interface{}{
+		"data": map[string]interface{}{
+			"keys": keys,
+		},
+	})
+}
+
+func (m *MockVault) configureCA(w http.ResponseWriter, mount string, body map[string]interface{}) {
+	bundle, _ := body["pem_bundle"].(string)
+
+	ca := &caEntry{}
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			ca.certificate = cert
+		case "RSA PRIVATE KEY":
+			key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			ca.privateKey = key
+		}
+	}
+	if ca.certificate == nil || ca.privateKey == nil {
+		writeError(w, http.StatusBadRequest, "pem_bundle must contain a certificate and a private key")
+		return
+	}
+
+	m.cas[mount] = ca
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *MockVault) issue(w http.ResponseWriter, mount string, role string, body map[string]interface{}) {
+	organizations, found := m.PKIRoles[mount+"/"+role]
+	if !found {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown role: %s", role))
+		return
+	}
+	ca := m.cas[mount]
+	if ca == nil {
+		writeError(w, http.StatusBadRequest, "no CA configured")
+		return
+	}
+
+	commonName, _ := body["common_name"].(string)
+	ttl := 24 * time.Hour
+	if s, ok := body["ttl"].(string); ok && s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		ttl = d
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	serial := big.NewInt(time.Now().UnixNano())
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: organizations,
+		},
+		NotBefore:   time.Now().Add(-time.Minute),
+		NotAfter:    time.Now().Add(ttl),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &privateKey.PublicKey, ca.privateKey)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"data": map[string]interface{}{
+			"certificate":   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+			"private_key":   string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})),
+			"issuing_ca":    string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.certificate.Raw})),
+			"serial_number": serial.String(),
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, o interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(o)
+}
+
+func writeError(w http.ResponseWriter, status int, errors ...string) {
+	if errors == nil {
+		errors = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": errors})
+}
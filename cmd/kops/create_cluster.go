@@ -281,7 +281,7 @@ func NewCmdCreateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to immediately create the cluster")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Valid targets: %s, %s, %s. Set this flag to %s if you want kops to generate terraform", cloudup.TargetDirect, cloudup.TargetTerraform, cloudup.TargetCloudformation, cloudup.TargetTerraform))
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, fmt.Sprintf("Valid targets: %s, %s, %s, %s. Set this flag to %s if you want kops to generate terraform, or to %s for a terraform module", cloudup.TargetDirect, cloudup.TargetTerraform, cloudup.TargetTerraformModule, cloudup.TargetCloudformation, cloudup.TargetTerraform, cloudup.TargetTerraformModule))
 	cmd.Flags().StringVar(&options.Models, "model", options.Models, "Models to apply (separate multiple models with commas)")
 
 	// Configuration / state location
@@ -422,7 +422,7 @@ func RunCreateCluster(f *util.Factory, out io.Writer, c *CreateClusterOptions) e
 	// TODO: Reuse rootCommand stateStore logic?
 
 	if c.OutDir == "" {
-		if c.Target == cloudup.TargetTerraform {
+		if c.Target == cloudup.TargetTerraform || c.Target == cloudup.TargetTerraformModule {
 			c.OutDir = "out/terraform"
 		} else if c.Target == cloudup.TargetCloudformation {
 			c.OutDir = "out/cloudformation"
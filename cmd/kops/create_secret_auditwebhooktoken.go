@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	createSecretAuditWebhookTokenLong = templates.LongDesc(i18n.T(`
+	Create a new audit webhook token secret, and store it in the state store.
+	Used by the kube-apiserver to authenticate to the audit webhook backend
+	configured with kubeAPIServer.auditWebhook.tokenSecret.`))
+
+	createSecretAuditWebhookTokenExample = templates.Examples(i18n.T(`
+	# Install an audit webhook token.
+	kops create secret auditwebhooktoken -f /path/to/token \
+		--name k8s-cluster.example.com --state s3://example.com
+	# Install an audit webhook token via stdin.
+	kops create secret auditwebhooktoken -f - \
+		--name k8s-cluster.example.com --state s3://example.com
+	# Replace an existing audit webhook token.
+	kops create secret auditwebhooktoken -f /path/to/token --force \
+		--name k8s-cluster.example.com --state s3://example.com
+	`))
+
+	createSecretAuditWebhookTokenShort = i18n.T(`Create an audit webhook token.`)
+)
+
+type CreateSecretAuditWebhookTokenOptions struct {
+	ClusterName   string
+	TokenFilePath string
+	Force         bool
+}
+
+func NewCmdCreateSecretAuditWebhookToken(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &CreateSecretAuditWebhookTokenOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "auditwebhooktoken",
+		Short:   createSecretAuditWebhookTokenShort,
+		Long:    createSecretAuditWebhookTokenLong,
+		Example: createSecretAuditWebhookTokenExample,
+		Run: func(cmd *cobra.Command, args []string) {
+
+			err := rootCommand.ProcessArgs(args[0:])
+			if err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err = RunCreateSecretAuditWebhookToken(f, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.TokenFilePath, "", "f", "", "Path to the audit webhook token file")
+	cmd.Flags().BoolVar(&options.Force, "force", options.Force, "Force replace the kops secret if it already exists")
+
+	return cmd
+}
+
+func RunCreateSecretAuditWebhookToken(f *util.Factory, options *CreateSecretAuditWebhookTokenOptions) error {
+	if options.TokenFilePath == "" {
+		return fmt.Errorf("token file path is required (use -f)")
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if options.TokenFilePath == "-" {
+		data, err = ConsumeStdin()
+		if err != nil {
+			return fmt.Errorf("error reading audit webhook token from stdin: %v", err)
+		}
+	} else {
+		data, err = ioutil.ReadFile(options.TokenFilePath)
+		if err != nil {
+			return fmt.Errorf("error reading audit webhook token file %v: %v", options.TokenFilePath, err)
+		}
+	}
+
+	secret := &fi.Secret{Data: data}
+
+	if !options.Force {
+		_, created, err := secretStore.GetOrCreateSecret("auditwebhooktoken", secret)
+		if err != nil {
+			return fmt.Errorf("error adding auditwebhooktoken secret: %v", err)
+		}
+		if !created {
+			return fmt.Errorf("failed to create the auditwebhooktoken secret as it already exists. The `--force` flag can be passed to replace an existing secret")
+		}
+	} else {
+		_, err := secretStore.ReplaceSecret("auditwebhooktoken", secret)
+		if err != nil {
+			return fmt.Errorf("error updating auditwebhooktoken secret: %v", err)
+		}
+	}
+
+	return nil
+}
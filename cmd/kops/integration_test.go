@@ -20,10 +20,12 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -34,13 +36,16 @@ import (
 	"testing"
 	"time"
 
+	"k8s.io/kops/cloudmock/azure/mockazure"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/jsonutils"
 	"k8s.io/kops/pkg/testutils"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/util/pkg/vfs"
 
 	"github.com/ghodss/yaml"
 	"golang.org/x/crypto/ssh"
@@ -70,6 +75,10 @@ func TestHA(t *testing.T) {
 
 // TestHighAvailabilityGCE runs the test on a simple HA GCE configuration, similar to kops create cluster ha-gce.example.com
 // --zones us-test1-a,us-test1-b,us-test1-c --master-count=3
+func TestMinimalAzure(t *testing.T) {
+	runTestAzure(t, "minimal-azure.k8s.local", "minimal_azure", "v1alpha2")
+}
+
 func TestHighAvailabilityGCE(t *testing.T) {
 	runTestGCE(t, "ha-gce.example.com", "ha_gce", "v1alpha2", false, 3)
 }
@@ -492,6 +501,112 @@ func runTestGCE(t *testing.T, clusterName string, srcDir string, version string,
 	runTest(t, h, clusterName, srcDir, version, private, zones, expectedFilenames, "", nil, nil)
 }
 
+// azureTestFileAssets are the files that the Azure integration tests expect to find in the file repository
+var azureTestFileAssets = []string{
+	"kubernetes-release/release/v1.12.0/bin/linux/amd64/kubelet",
+	"kops/1.8.1/darwin/amd64/kops",
+	"kops/1.8.1/linux/amd64/kops",
+	"kops/1.8.1/images/protokube.tar.gz",
+	"kops/1.8.1/linux/amd64/nodeup",
+	"kops/1.8.1/linux/amd64/utils.tar.gz",
+	"kubernetes-release/release/v1.12.0/bin/linux/amd64/kubectl",
+}
+
+// runTestAzure applies the cluster to the mock Azure cloud, as Azure has no terraform output, and compares the resulting resources
+func runTestAzure(t *testing.T, clusterName string, srcDir string, version string) {
+	featureflag.ParseFlags("+AlphaAllowAzure")
+	defer featureflag.ParseFlags("-AlphaAllowAzure")
+
+	srcDir = updateClusterTestBase + srcDir
+	var stdout bytes.Buffer
+
+	inputYAML := "in-" + version + ".yaml"
+	expectedPath := "azure.yaml"
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.8.1")
+	cloud := h.SetupMockAzure(clusterName)
+
+	// The cluster uses a file repository in memfs, so stage the hashes of the files there instead of downloading them
+	for _, file := range azureTestFileAssets {
+		p, err := vfs.Context.BuildVfsPath("memfs://tests/assets/" + file + ".sha1")
+		if err != nil {
+			t.Fatalf("error building path for %q: %v", file, err)
+		}
+		if err := p.WriteFile(bytes.NewReader([]byte(fmt.Sprintf("%x", sha1.Sum([]byte(file))))), nil); err != nil {
+			t.Fatalf("error writing hash of %q: %v", file, err)
+		}
+	}
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, inputYAML)}
+
+		err := RunCreate(factory, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running %q create: %v", inputYAML, err)
+		}
+	}
+
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = clusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(srcDir, "id_rsa.pub")
+
+		err := RunCreateSecretPublicKey(factory, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running %q create: %v", inputYAML, err)
+		}
+	}
+
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.RunTasksOptions.MaxTaskDuration = 30 * time.Second
+		options.Yes = true
+
+		// We don't test it here, and it adds a dependency on kubectl
+		options.CreateKubecfg = false
+
+		_, err := RunUpdateCluster(factory, clusterName, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running update cluster %q: %v", clusterName, err)
+		}
+	}
+
+	resources := mockazure.All(cloud)
+
+	// Compare the custom data of the scale sets separately, as the terraform tests do with user data
+	for k, v := range resources {
+		vmss, ok := v.(*azureup.VirtualMachineScaleSet)
+		if !ok || vmss.Properties.VirtualMachineProfile == nil || vmss.Properties.VirtualMachineProfile.OsProfile == nil {
+			continue
+		}
+		osProfile := vmss.Properties.VirtualMachineProfile.OsProfile
+		customData, err := base64.StdEncoding.DecodeString(osProfile.CustomData)
+		if err != nil {
+			t.Fatalf("error decoding custom data of %q: %v", k, err)
+		}
+		testutils.AssertMatchesFile(t, string(customData), path.Join(srcDir, "data", vmss.Name+"_custom_data"))
+		osProfile.CustomData = ""
+	}
+
+	actual, err := yaml.Marshal(resources)
+	if err != nil {
+		t.Fatalf("error serializing azure resources: %v", err)
+	}
+
+	testutils.AssertMatchesFile(t, string(actual), path.Join(srcDir, expectedPath))
+}
+
 func runTestCloudformation(t *testing.T, clusterName string, srcDir string, version string, private bool, lifecycleOverrides []string) {
 	srcDir = updateClusterTestBase + srcDir
 	var stdout bytes.Buffer
@@ -94,6 +94,15 @@ var (
 		  --fail-on-validate-error="false" \
 		  --node-interval 8m \
 		  --instance-group nodes
+
+		# Roll the k8s-cluster.example.com kops cluster,
+		# replacing one canary instance per instance group first,
+		# and rolling the group back if the cluster does not
+		# keep validating for 10 minutes or the check fails.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --canary \
+		  --canary-soak-period 10m \
+		  --canary-check "./smoke-test.sh"
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -142,6 +151,18 @@ type RollingUpdateOptions struct {
 	// InstanceGroupRoles is the list of roles we should rolling-update
 	// if not specified, all instance groups will be updated
 	InstanceGroupRoles []string
+
+	// Canary replaces one instance of each master and node instance group first, and rolls the group back if it fails
+	Canary bool
+
+	// CanarySoakPeriod is how long the canary must keep passing validation and the canary checks
+	CanarySoakPeriod time.Duration
+
+	// CanaryCheckInterval is how often validation and the canary checks are run during the soak period
+	CanaryCheckInterval time.Duration
+
+	// CanaryChecks are shell commands which must succeed during the soak period
+	CanaryChecks []string
 }
 
 func (o *RollingUpdateOptions) InitDefaults() {
@@ -158,6 +179,10 @@ func (o *RollingUpdateOptions) InitDefaults() {
 
 	o.PostDrainDelay = 5 * time.Second
 	o.ValidationTimeout = 15 * time.Minute
+
+	o.Canary = false
+	o.CanarySoakPeriod = 5 * time.Minute
+	o.CanaryCheckInterval = 30 * time.Second
 }
 
 func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -184,6 +209,10 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to continue after each instance is updated")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "List of instance groups to update (defaults to all if not specified)")
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "If specified, only instance groups of the specified role will be updated (e.g. Master,Node,Bastion)")
+	cmd.Flags().BoolVar(&options.Canary, "canary", options.Canary, "Replace a single canary instance of each instance group first, and roll the group back if the canary fails")
+	cmd.Flags().DurationVar(&options.CanarySoakPeriod, "canary-soak-period", options.CanarySoakPeriod, "Time the canary must keep passing validation and the canary checks")
+	cmd.Flags().DurationVar(&options.CanaryCheckInterval, "canary-check-interval", options.CanaryCheckInterval, "Time between validations and canary checks during the soak period")
+	cmd.Flags().StringSliceVar(&options.CanaryChecks, "canary-check", options.CanaryChecks, "Shell command which must succeed during the canary soak period; may be repeated")
 
 	if featureflag.DrainAndValidateRollingUpdate.Enabled() {
 		cmd.Flags().BoolVar(&options.FailOnDrainError, "fail-on-drain-error", true, "The rolling-update will fail if draining a node fails.")
@@ -321,6 +350,10 @@ func RunRollingUpdateCluster(f *util.Factory, out io.Writer, options *RollingUpd
 		return err
 	}
 
+	for _, group := range groups {
+		group.MarkNodesRequiringReplacement()
+	}
+
 	{
 		t := &tables.Table{}
 		t.AddColumn("NAME", func(r *cloudinstances.CloudInstanceGroup) string {
@@ -405,6 +438,13 @@ func RunRollingUpdateCluster(f *util.Factory, out io.Writer, options *RollingUpd
 		ClusterName:       options.ClusterName,
 		PostDrainDelay:    options.PostDrainDelay,
 		ValidationTimeout: options.ValidationTimeout,
+
+		Canary:              options.Canary,
+		CanarySoakPeriod:    options.CanarySoakPeriod,
+		CanaryCheckInterval: options.CanaryCheckInterval,
+	}
+	for _, command := range options.CanaryChecks {
+		d.CanaryChecks = append(d.CanaryChecks, &instancegroups.CommandCanaryCheck{Command: command})
 	}
 	return d.RollingUpdate(groups, cluster, list)
 }
@@ -0,0 +1,340 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/klog"
+	"k8s.io/kops/cmd/kops/util"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/encryptionatrest"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	rotateEncryptionKeyLong = templates.LongDesc(i18n.T(`
+	Rotate the key that the kube-apiserver uses to encrypt resources in etcd,
+	when encryptionAtRest is enabled with the aescbc or secretbox provider.
+
+	The rotation adds a new key and rolling-updates the masters, so every
+	kube-apiserver can decrypt with it. It then makes the new key the key used
+	for encryption and rolling-updates the masters again, rewrites all
+	encrypted resources, and finally drops the old key with a last rolling
+	update of the masters.
+
+	The progress of the rotation is saved with the keys, so if the rotation
+	is interrupted, running the command again resumes it from the last
+	completed step.`))
+
+	rotateEncryptionKeyExample = templates.Examples(i18n.T(`
+	# Show what the rotation would do
+	kops rotate encryption-key --name k8s-cluster.example.com
+
+	# Rotate the encryption key
+	kops rotate encryption-key --name k8s-cluster.example.com --yes
+	`))
+
+	rotateEncryptionKeyShort = i18n.T(`Rotate the key used to encrypt resources in etcd.`)
+)
+
+type RotateEncryptionKeyOptions struct {
+	ClusterName string
+	Yes         bool
+
+	// ValidationTimeout is the timeout for the cluster to validate after each master is replaced
+	ValidationTimeout time.Duration
+
+	// MasterInterval is the minimum time to wait after stopping a master
+	MasterInterval time.Duration
+}
+
+func (o *RotateEncryptionKeyOptions) InitDefaults() {
+	o.Yes = false
+	o.ValidationTimeout = 15 * time.Minute
+	o.MasterInterval = 15 * time.Second
+}
+
+func NewCmdRotateEncryptionKey(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RotateEncryptionKeyOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "encryption-key",
+		Short:   rotateEncryptionKeyShort,
+		Long:    rotateEncryptionKeyLong,
+		Example: rotateEncryptionKeyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+			if options.ClusterName == "" {
+				exitWithError(fmt.Errorf("--name is required"))
+			}
+
+			err = RunRotateEncryptionKey(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Rotate the key immediately, without --yes the steps of the rotation are only listed")
+	cmd.Flags().DurationVar(&options.ValidationTimeout, "validation-timeout", options.ValidationTimeout, "Maximum time to wait for a cluster to validate")
+	cmd.Flags().DurationVar(&options.MasterInterval, "master-interval", options.MasterInterval, "Time to wait between restarting masters")
+
+	return cmd
+}
+
+func RunRotateEncryptionKey(f *util.Factory, out io.Writer, options *RotateEncryptionKeyOptions) error {
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	spec := cluster.Spec.EncryptionAtRest
+	if spec == nil {
+		return fmt.Errorf("encryptionAtRest is not enabled for cluster %q", cluster.ObjectMeta.Name)
+	}
+	if spec.Provider == encryptionatrest.ProviderKMS {
+		return fmt.Errorf("cluster %q uses the kms encryption provider; rotate the key in AWS KMS instead", cluster.ObjectMeta.Name)
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	keys, err := encryptionatrest.LoadKeySet(secretStore)
+	if err != nil {
+		return err
+	}
+	if keys == nil {
+		return fmt.Errorf("no encryption keys found for cluster %q; run kops update cluster first", cluster.ObjectMeta.Name)
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Rotating the encryption key of cluster %q will:\n", cluster.ObjectMeta.Name)
+		fmt.Fprintf(out, "  1. add a new key and rolling-update the masters\n")
+		fmt.Fprintf(out, "  2. encrypt with the new key and rolling-update the masters\n")
+		fmt.Fprintf(out, "  3. rewrite all %v\n", spec.Resources)
+		fmt.Fprintf(out, "  4. drop the old key and rolling-update the masters\n")
+		fmt.Fprintf(out, "\nMust specify --yes to rotate the encryption key\n")
+		return nil
+	}
+
+	rotation := &encryptionKeyRotation{
+		saveKeys: func(keys *encryptionatrest.KeySet) error {
+			return encryptionatrest.SaveKeySet(secretStore, keys)
+		},
+		rollMasters: func() error {
+			return rollMastersForEncryptionKey(f, out, options)
+		},
+		rewriteResources: func() error {
+			return rewriteEncryptedResources(cluster, out, spec.Resources)
+		},
+	}
+	if err := rotation.run(out, keys); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nEncryption key of cluster %q rotated\n", cluster.ObjectMeta.Name)
+	return nil
+}
+
+// encryptionKeyRotation holds the steps of a key rotation that act on the cluster
+type encryptionKeyRotation struct {
+	saveKeys         func(keys *encryptionatrest.KeySet) error
+	rollMasters      func() error
+	rewriteResources func() error
+}
+
+// run rotates the keys, resuming from the phase recorded in the key set.  The phase is saved with the keys after
+// every step, so the masters are always rolled between changing the keys and relying on the change.
+func (r *encryptionKeyRotation) run(out io.Writer, keys *encryptionatrest.KeySet) error {
+	phase := keys.RotationPhase()
+	if phase != "" {
+		fmt.Fprintf(out, "Resuming the rotation of encryption key %q from phase %s\n", keys.Newest().Name, phase)
+	}
+
+	// Add a new key, which the masters can decrypt with but don't encrypt with yet
+	if phase == "" {
+		key, err := keys.AddKey()
+		if err != nil {
+			return err
+		}
+		phase = encryptionatrest.RotationKeyAdded
+		if err := r.save(keys, phase); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Added encryption key %q\n", key.Name)
+	}
+	newest := keys.Newest().Name
+
+	// Only encrypt with the new key once every master is able to decrypt with it
+	if phase == encryptionatrest.RotationKeyAdded {
+		if err := r.rollMasters(); err != nil {
+			return err
+		}
+
+		if err := keys.Promote(newest); err != nil {
+			return err
+		}
+		phase = encryptionatrest.RotationKeyPromoted
+		if err := r.save(keys, phase); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Encrypting with key %q\n", newest)
+	}
+
+	if phase == encryptionatrest.RotationKeyPromoted {
+		if err := r.rollMasters(); err != nil {
+			return err
+		}
+
+		phase = encryptionatrest.RotationMastersUpdated
+		if err := r.save(keys, phase); err != nil {
+			return err
+		}
+	}
+
+	// Only drop the old keys once every master encrypts with the new key, and every resource was rewritten with it
+	if phase == encryptionatrest.RotationMastersUpdated {
+		if err := r.rewriteResources(); err != nil {
+			return err
+		}
+
+		if err := keys.Retain(newest); err != nil {
+			return err
+		}
+		phase = encryptionatrest.RotationOldKeysDropped
+		if err := r.save(keys, phase); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Dropped all encryption keys but %q\n", newest)
+	}
+
+	if phase == encryptionatrest.RotationOldKeysDropped {
+		if err := r.rollMasters(); err != nil {
+			return err
+		}
+
+		if err := r.save(keys, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// save records the phase of the rotation and saves the keys
+func (r *encryptionKeyRotation) save(keys *encryptionatrest.KeySet, phase string) error {
+	keys.Rotation = phase
+	return r.saveKeys(keys)
+}
+
+// rollMastersForEncryptionKey replaces all masters, so they pick up the encryption keys from the secret store.
+// The keys are not part of the instance configuration, so the rolling update has to be forced.
+func rollMastersForEncryptionKey(f *util.Factory, out io.Writer, options *RotateEncryptionKeyOptions) error {
+	rollingUpdate := &RollingUpdateOptions{}
+	rollingUpdate.InitDefaults()
+	rollingUpdate.Yes = true
+	rollingUpdate.Force = true
+	rollingUpdate.ClusterName = options.ClusterName
+	rollingUpdate.InstanceGroupRoles = []string{string(api.InstanceGroupRoleMaster)}
+	rollingUpdate.ValidationTimeout = options.ValidationTimeout
+	rollingUpdate.MasterInterval = options.MasterInterval
+
+	if err := RunRollingUpdateCluster(f, out, rollingUpdate); err != nil {
+		return fmt.Errorf("error rolling-updating the masters: %v", err)
+	}
+	return nil
+}
+
+// rewriteEncryptedResources updates every object of the resources unchanged, so the kube-apiserver stores them encrypted with the primary key
+func rewriteEncryptedResources(cluster *api.Cluster, out io.Writer, resources []string) error {
+	contextName := cluster.ObjectMeta.Name
+	clientGetter := genericclioptions.NewConfigFlags()
+	clientGetter.Context = &contextName
+
+	config, err := clientGetter.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("cannot load kubecfg settings for %q: %v", contextName, err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot build discovery client for %q: %v", contextName, err)
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return fmt.Errorf("error discovering the API resources of %q: %v", contextName, err)
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("cannot build kube client for %q: %v", contextName, err)
+	}
+
+	for _, resource := range resources {
+		gvr, err := mapper.ResourceFor(schema.ParseGroupResource(resource).WithVersion(""))
+		if err != nil {
+			return fmt.Errorf("error finding resource %q: %v", resource, err)
+		}
+
+		list, err := dynamicClient.Resource(gvr).List(metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing %s: %v", resource, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			_, err := dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(obj, metav1.UpdateOptions{})
+			if err != nil {
+				// The object was written, and thus encrypted, since we listed it
+				if errors.IsConflict(err) || errors.IsNotFound(err) {
+					klog.V(2).Infof("skipping %s %s/%s: %v", resource, obj.GetNamespace(), obj.GetName(), err)
+					continue
+				}
+				return fmt.Errorf("error rewriting %s %s/%s: %v", resource, obj.GetNamespace(), obj.GetName(), err)
+			}
+		}
+		fmt.Fprintf(out, "Rewrote %d %s\n", len(list.Items), resource)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/encryptionatrest"
+)
+
+// fakeEncryptionKeyRotation records the steps of a key rotation, saving the keys as the secret store would
+type fakeEncryptionKeyRotation struct {
+	saved []byte
+	steps []string
+	// failRoll fails the rolling update of the masters with this number, counting from 1
+	failRoll int
+	rolls    int
+}
+
+func (f *fakeEncryptionKeyRotation) rotation() *encryptionKeyRotation {
+	return &encryptionKeyRotation{
+		saveKeys: func(keys *encryptionatrest.KeySet) error {
+			data, err := keys.Encode()
+			if err != nil {
+				return err
+			}
+			f.saved = data
+			f.steps = append(f.steps, fmt.Sprintf("save %s %s", keyNames(keys), keys.Rotation))
+			return nil
+		},
+		rollMasters: func() error {
+			f.rolls++
+			if f.rolls == f.failRoll {
+				return fmt.Errorf("interrupted")
+			}
+			f.steps = append(f.steps, "roll")
+			return nil
+		},
+		rewriteResources: func() error {
+			f.steps = append(f.steps, "rewrite")
+			return nil
+		},
+	}
+}
+
+func (f *fakeEncryptionKeyRotation) load(t *testing.T) *encryptionatrest.KeySet {
+	keys, err := encryptionatrest.ParseKeySet(f.saved)
+	if err != nil {
+		t.Fatalf("error parsing saved keys: %v", err)
+	}
+	return keys
+}
+
+func keyNames(keys *encryptionatrest.KeySet) string {
+	var names []string
+	for _, key := range keys.Keys {
+		names = append(names, key.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRotateEncryptionKey(t *testing.T) {
+	keys := &encryptionatrest.KeySet{Keys: []encryptionatrest.Key{{Name: "key1", Secret: "Zmlyc3Q="}}}
+	f := &fakeEncryptionKeyRotation{}
+
+	if err := f.rotation().run(&bytes.Buffer{}, keys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"save key1,key2 KeyAdded",
+		"roll",
+		"save key2,key1 KeyPromoted",
+		"roll",
+		"save key2,key1 MastersUpdated",
+		"rewrite",
+		"save key2 OldKeysDropped",
+		"roll",
+		"save key2 ",
+	}
+	if strings.Join(f.steps, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected steps:\n%s\nexpected:\n%s", strings.Join(f.steps, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestRotateEncryptionKeyResume(t *testing.T) {
+	grid := []struct {
+		// failRoll is the rolling update that is interrupted
+		failRoll int
+		expected []string
+	}{
+		{
+			// before any master decrypts with the new key
+			failRoll: 1,
+			expected: []string{
+				"roll",
+				"save key2,key1 KeyPromoted",
+				"roll",
+				"save key2,key1 MastersUpdated",
+				"rewrite",
+				"save key2 OldKeysDropped",
+				"roll",
+				"save key2 ",
+			},
+		},
+		{
+			// after the new key was promoted, but before every master encrypts with it
+			failRoll: 2,
+			expected: []string{
+				"roll",
+				"save key2,key1 MastersUpdated",
+				"rewrite",
+				"save key2 OldKeysDropped",
+				"roll",
+				"save key2 ",
+			},
+		},
+		{
+			// after the old key was dropped, but before the masters forgot it
+			failRoll: 3,
+			expected: []string{
+				"roll",
+				"save key2 ",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		keys := &encryptionatrest.KeySet{Keys: []encryptionatrest.Key{{Name: "key1", Secret: "Zmlyc3Q="}}}
+		f := &fakeEncryptionKeyRotation{failRoll: g.failRoll}
+		if err := f.rotation().run(&bytes.Buffer{}, keys); err == nil {
+			t.Fatalf("expected the rotation to be interrupted at roll %d", g.failRoll)
+		}
+
+		f.steps = nil
+		f.failRoll = 0
+		if err := f.rotation().run(&bytes.Buffer{}, f.load(t)); err != nil {
+			t.Fatalf("unexpected error resuming at roll %d: %v", g.failRoll, err)
+		}
+		if strings.Join(f.steps, "\n") != strings.Join(g.expected, "\n") {
+			t.Errorf("unexpected steps resuming at roll %d:\n%s\nexpected:\n%s", g.failRoll, strings.Join(f.steps, "\n"), strings.Join(g.expected, "\n"))
+		}
+	}
+}
+
+func TestRotateEncryptionKeyResumeWithoutPhase(t *testing.T) {
+	// Keys saved before the phase of the rotation was recorded: the new key is primary, but the masters may not
+	// all encrypt with it, so they are rolled before the resources are rewritten and the old key is dropped
+	keys := &encryptionatrest.KeySet{Keys: []encryptionatrest.Key{{Name: "key2", Secret: "c2Vjb25k"}, {Name: "key1", Secret: "Zmlyc3Q="}}}
+	f := &fakeEncryptionKeyRotation{}
+
+	if err := f.rotation().run(&bytes.Buffer{}, keys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"roll",
+		"save key2,key1 MastersUpdated",
+		"rewrite",
+		"save key2 OldKeysDropped",
+		"roll",
+		"save key2 ",
+	}
+	if strings.Join(f.steps, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected steps:\n%s\nexpected:\n%s", strings.Join(f.steps, "\n"), strings.Join(expected, "\n"))
+	}
+}
@@ -45,10 +45,14 @@ func NewCmdToolbox(f *util.Factory, out io.Writer) *cobra.Command {
 		Example: toolboxExample,
 	}
 
+	cmd.AddCommand(NewCmdToolboxAssets(f, out))
 	cmd.AddCommand(NewCmdToolboxConvertImported(f, out))
 	cmd.AddCommand(NewCmdToolboxDump(f, out))
+	cmd.AddCommand(NewCmdToolboxReencrypt(f, out))
 	cmd.AddCommand(NewCmdToolboxBundle(f, out))
+	cmd.AddCommand(NewCmdToolboxCheckHardening(f, out))
 	cmd.AddCommand(NewCmdToolboxTemplate(f, out))
+	cmd.AddCommand(NewCmdToolboxTerraformImport(f, out))
 
 	return cmd
 }
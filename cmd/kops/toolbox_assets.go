@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxAssetsLong = templates.LongDesc(i18n.T(`
+	Export the assets of a cluster to a bundle, and import a bundle into a container registry and file repository.
+
+	Bundles carry the files, container images and manifests a cluster needs into an environment without network access.`))
+
+	toolboxAssetsExample = templates.Examples(i18n.T(`
+	# Export the assets of a cluster
+	kops toolbox assets export --name k8s-cluster.example.com --out bundle.tar
+
+	# Import the assets into a local registry and file repository
+	kops toolbox assets import bundle.tar --registry registry.example.com:5000 --file-repository s3://assets-bucket/kops
+	`))
+
+	toolboxAssetsShort = i18n.T(`Export and import asset bundles`)
+)
+
+func NewCmdToolboxAssets(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "assets",
+		Short:   toolboxAssetsShort,
+		Long:    toolboxAssetsLong,
+		Example: toolboxAssetsExample,
+	}
+
+	// subcommands
+	cmd.AddCommand(NewCmdToolboxAssetsExport(f, out))
+	cmd.AddCommand(NewCmdToolboxAssetsImport(f, out))
+
+	return cmd
+}
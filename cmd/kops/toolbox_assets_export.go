@@ -0,0 +1,253 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assetbundle"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxAssetsExportLong = templates.LongDesc(i18n.T(`
+	Export the assets of a cluster to a bundle.
+
+	The bundle holds the file assets with their hashes, the container images in the OCI image layout,
+	and the channel and addon manifests of the cluster. Images are pulled with the docker CLI.`))
+
+	toolboxAssetsExportExample = templates.Examples(i18n.T(`
+	# Export the assets of a cluster
+	kops toolbox assets export --name k8s-cluster.example.com --out bundle.tar
+	`))
+
+	toolboxAssetsExportShort = i18n.T(`Export the assets of a cluster to a bundle`)
+)
+
+type ToolboxAssetsExportOptions struct {
+	// Out is the file the bundle is written to
+	Out string
+}
+
+func (o *ToolboxAssetsExportOptions) InitDefaults() {
+	o.Out = "bundle.tar"
+}
+
+func NewCmdToolboxAssetsExport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxAssetsExportOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "export",
+		Short:   toolboxAssetsExportShort,
+		Long:    toolboxAssetsExportLong,
+		Example: toolboxAssetsExportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			err = RunToolboxAssetsExport(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Out, "out", options.Out, "File to write the bundle to")
+
+	return cmd
+}
+
+func RunToolboxAssetsExport(f *util.Factory, out io.Writer, options *ToolboxAssetsExportOptions) error {
+	if options.Out == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	cluster, err := rootCommand.Cluster()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	// We discover the assets as the assets phase of kops update cluster does, reading the hashes from the canonical locations
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Clientset:  clientset,
+		Cluster:    cluster,
+		DryRun:     true,
+		Phase:      cloudup.PhaseStageAssets,
+		TargetName: cloudup.TargetDryRun,
+		GetAssets:  true,
+	}
+	if err := applyCmd.Run(); err != nil {
+		return err
+	}
+
+	tmpDir, err := ioutil.TempDir("", "kops-assets")
+	if err != nil {
+		return fmt.Errorf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	bundleFile, err := os.Create(options.Out)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %v", options.Out, err)
+	}
+	defer bundleFile.Close()
+
+	w := assetbundle.NewWriter(bundleFile)
+	w.Index.KopsVersion = kops.Version
+	w.Index.ClusterName = cluster.ObjectMeta.Name
+	w.Index.KubernetesVersion = cluster.Spec.KubernetesVersion
+
+	var signatureKey crypto.PublicKey
+	if cluster.Spec.Assets != nil && cluster.Spec.Assets.FileSignatureKey != nil {
+		signatureKey, err = assets.ParseSignatureKey(*cluster.Spec.Assets.FileSignatureKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, asset := range applyCmd.FileAssets {
+		u := asset.DownloadURL
+		if asset.CanonicalURL != nil {
+			u = asset.CanonicalURL
+		}
+		if asset.SHAValue == "" {
+			return fmt.Errorf("no hash is known for %q", u)
+		}
+
+		fmt.Fprintf(out, "Exporting file %s\n", u)
+		data, err := vfs.Context.ReadFile(u.String())
+		if err != nil {
+			return fmt.Errorf("error reading %q: %v", u, err)
+		}
+
+		// Nodes refuse files without a valid signature when fileSignatureKey is set, so the bundle carries them
+		var signature []byte
+		if signatureKey != nil {
+			signatureURL := u.String() + assets.SignatureSuffix
+			signature, err = vfs.Context.ReadFile(signatureURL)
+			if err != nil {
+				return fmt.Errorf("error reading signature %q, fileSignatureKey requires signatures to be published alongside the files: %v", signatureURL, err)
+			}
+			if err := assets.VerifySignature(signatureKey, data, signature); err != nil {
+				return fmt.Errorf("error verifying signature %q: %v", signatureURL, err)
+			}
+		}
+
+		if err := w.AddFile(u.Path, u.String(), asset.SHAValue, data, signature); err != nil {
+			return err
+		}
+	}
+
+	images := make(map[string]bool)
+	for _, asset := range applyCmd.ImageAssets {
+		image := asset.DockerImage
+		if asset.CanonicalLocation != "" {
+			image = asset.CanonicalLocation
+		}
+		if images[image] {
+			continue
+		}
+		images[image] = true
+
+		fmt.Fprintf(out, "Exporting image %s\n", image)
+		archivePath := filepath.Join(tmpDir, "image.tar")
+		if err := assetbundle.SaveDockerImage(image, archivePath); err != nil {
+			return err
+		}
+		if err := w.AddDockerArchive(image, archivePath); err != nil {
+			return err
+		}
+		if err := os.Remove(archivePath); err != nil {
+			return fmt.Errorf("error removing %q: %v", archivePath, err)
+		}
+	}
+
+	{
+		channelLocation := cluster.Spec.Channel
+		if channelLocation == "" {
+			channelLocation = kopsapi.DefaultChannel
+		}
+		u, err := kopsapi.ResolveChannel(channelLocation)
+		if err != nil {
+			return err
+		}
+		data, err := vfs.Context.ReadFile(u.String())
+		if err != nil {
+			return fmt.Errorf("error reading channel %q: %v", u, err)
+		}
+		p := path.Join("channels", path.Base(u.Path))
+		if err := w.AddManifest(p, data); err != nil {
+			return err
+		}
+		w.Index.Channel = p
+	}
+
+	var addonPaths []string
+	addons := make(map[string]*fitasks.ManagedFile)
+	for _, task := range applyCmd.TaskMap {
+		managedFile, ok := task.(*fitasks.ManagedFile)
+		if !ok || !strings.HasPrefix(fi.StringValue(managedFile.Location), "addons/") {
+			continue
+		}
+		p := fi.StringValue(managedFile.Location)
+		addonPaths = append(addonPaths, p)
+		addons[p] = managedFile
+	}
+	sort.Strings(addonPaths)
+	for _, p := range addonPaths {
+		data, err := addons[p].Contents.AsBytes()
+		if err != nil {
+			return fmt.Errorf("error rendering addon manifest %q: %v", p, err)
+		}
+		if err := w.AddManifest(p, data); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nWrote %d files, %d images and %d manifests to %s\n", len(w.Index.Files), len(w.Index.Images), len(w.Index.Manifests), options.Out)
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/assetbundle"
+	"k8s.io/kops/pkg/values"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxAssetsImportLong = templates.LongDesc(i18n.T(`
+	Import a bundle written by kops toolbox assets export.
+
+	The images of the bundle are pushed to the container registry, named as assets.containerRegistry
+	expects them, and the files and manifests are written to the file repository with their hashes.
+	If neither --registry nor --file-repository is set, those of the assets of the cluster are used.`))
+
+	toolboxAssetsImportExample = templates.Examples(i18n.T(`
+	# Import a bundle into a local registry and file repository
+	kops toolbox assets import bundle.tar --registry registry.example.com:5000 --file-repository s3://assets-bucket/kops
+
+	# Import a bundle into the registry and file repository of a cluster
+	kops toolbox assets import bundle.tar --name k8s-cluster.example.com
+	`))
+
+	toolboxAssetsImportShort = i18n.T(`Import a bundle into a container registry and file repository`)
+)
+
+type ToolboxAssetsImportOptions struct {
+	// Registry is the container registry the images are pushed to
+	Registry string
+	// InsecureRegistry is true if the registry is served over plain http
+	InsecureRegistry bool
+	// RegistryUsername and RegistryPassword are used to authenticate to the registry
+	RegistryUsername string
+	RegistryPassword string
+
+	// FileRepository is the vfs location the files and manifests are written to
+	FileRepository string
+}
+
+func (o *ToolboxAssetsImportOptions) InitDefaults() {
+	o.RegistryUsername = os.Getenv("KOPS_REGISTRY_USERNAME")
+	o.RegistryPassword = os.Getenv("KOPS_REGISTRY_PASSWORD")
+}
+
+func NewCmdToolboxAssetsImport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxAssetsImportOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "import BUNDLE",
+		Short:   toolboxAssetsImportShort,
+		Long:    toolboxAssetsImportLong,
+		Example: toolboxAssetsImportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := RunToolboxAssetsImport(f, out, options, args)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Registry, "registry", options.Registry, "Container registry to push the images to, such as registry.example.com:5000/kops")
+	cmd.Flags().BoolVar(&options.InsecureRegistry, "insecure-registry", options.InsecureRegistry, "Push to the registry over plain http")
+	cmd.Flags().StringVar(&options.RegistryUsername, "registry-username", options.RegistryUsername, "Username to authenticate to the registry (defaults to KOPS_REGISTRY_USERNAME)")
+	cmd.Flags().StringVar(&options.FileRepository, "file-repository", options.FileRepository, "Location to write the files and manifests to, such as s3://assets-bucket/kops")
+
+	return cmd
+}
+
+func RunToolboxAssetsImport(f *util.Factory, out io.Writer, options *ToolboxAssetsImportOptions, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Specify the bundle to import")
+	}
+	bundlePath := args[0]
+
+	if options.Registry == "" && options.FileRepository == "" && rootCommand.ClusterName() != "" {
+		cluster, err := rootCommand.Cluster()
+		if err != nil {
+			return err
+		}
+		if cluster.Spec.Assets != nil {
+			options.Registry = values.StringValue(cluster.Spec.Assets.ContainerRegistry)
+			options.FileRepository = values.StringValue(cluster.Spec.Assets.FileRepository)
+		}
+	}
+	if options.Registry == "" && options.FileRepository == "" {
+		return fmt.Errorf("--registry or --file-repository is required")
+	}
+
+	importer := &assetbundle.Importer{}
+
+	if options.Registry != "" {
+		registry, err := assetbundle.ParseRegistry(options.Registry)
+		if err != nil {
+			return err
+		}
+		if options.InsecureRegistry {
+			registry.Insecure = true
+		}
+		registry.Username = options.RegistryUsername
+		registry.Password = options.RegistryPassword
+		importer.Registry = registry
+	}
+
+	if options.FileRepository != "" {
+		p, err := vfs.Context.BuildVfsPath(options.FileRepository)
+		if err != nil {
+			return fmt.Errorf("error building path for %q: %v", options.FileRepository, err)
+		}
+		importer.FileRepository = p
+	}
+
+	tmpDir, err := ioutil.TempDir("", "kops-assets")
+	if err != nil {
+		return fmt.Errorf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	bundleFile, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %v", bundlePath, err)
+	}
+	defer bundleFile.Close()
+
+	index, err := assetbundle.Extract(bundleFile, tmpDir)
+	if err != nil {
+		return fmt.Errorf("error extracting %q: %v", bundlePath, err)
+	}
+	importer.Dir = tmpDir
+	importer.Index = index
+
+	if err := importer.Import(); err != nil {
+		return err
+	}
+
+	if importer.Registry != nil {
+		fmt.Fprintf(out, "Pushed %d images to %s\n", len(index.Images), importer.Registry.Location())
+	}
+	if importer.FileRepository != nil {
+		fmt.Fprintf(out, "Wrote %d files and %d manifests to %s\n", len(index.Files), len(index.Manifests), importer.FileRepository)
+		if index.Channel != "" {
+			fmt.Fprintf(out, "\nSet spec.channel to %s to use the channel of the bundle\n", importer.FileRepository.Join(index.Channel))
+		}
+	}
+	return nil
+}
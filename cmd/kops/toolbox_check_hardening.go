@@ -0,0 +1,221 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/homedir"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/hardening"
+	"k8s.io/kops/upup/pkg/kutil"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxCheckHardeningLong = templates.LongDesc(i18n.T(`
+	Checks over SSH that the nodeHardening settings of an instance group are applied on its instances.
+
+	Each instance is checked for its kernel parameters, blacklisted kernel modules, auditd rules,
+	sshd settings, disabled services and the modes of the files written by nodeup.`))
+
+	toolboxCheckHardeningExample = templates.Examples(i18n.T(`
+	# Check two instances of the nodes instance group
+	kops toolbox check-hardening --name k8s-cluster.example.com --instance-group nodes 172.20.40.12 172.20.61.4
+	`))
+
+	toolboxCheckHardeningShort = i18n.T(`Check the hardening of instances`)
+)
+
+type ToolboxCheckHardeningOptions struct {
+	// InstanceGroup is the name of the instance group of the checked instances
+	InstanceGroup string
+
+	// SSHUser is the user to log in as; admin and ubuntu are tried if not set
+	SSHUser string
+	// SSHPrivateKey is the path of the private key to log in with
+	SSHPrivateKey string
+}
+
+func (o *ToolboxCheckHardeningOptions) InitDefaults() {
+	o.SSHPrivateKey = filepath.Join(homedir.HomeDir(), ".ssh", "id_rsa")
+}
+
+func NewCmdToolboxCheckHardening(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxCheckHardeningOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "check-hardening",
+		Short:   toolboxCheckHardeningShort,
+		Long:    toolboxCheckHardeningLong,
+		Example: toolboxCheckHardeningExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := RunToolboxCheckHardening(f, out, options, args)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "instance group of the instances")
+	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "SSH user (defaults to trying admin and ubuntu)")
+	cmd.Flags().StringVar(&options.SSHPrivateKey, "ssh-private-key", options.SSHPrivateKey, "SSH private key")
+
+	return cmd
+}
+
+// hardeningCheckResult is the result of a check on an instance
+type hardeningCheckResult struct {
+	Host   string
+	Check  string
+	Passed bool
+	Detail string
+}
+
+func RunToolboxCheckHardening(f *util.Factory, out io.Writer, options *ToolboxCheckHardeningOptions, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Specify the addresses of the instances to check")
+	}
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("--instance-group is required")
+	}
+
+	cluster, err := rootCommand.Cluster()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	ig, err := clientset.InstanceGroupsFor(cluster).Get(options.InstanceGroup, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading InstanceGroup %q: %v", options.InstanceGroup, err)
+	}
+	if ig == nil {
+		return fmt.Errorf("InstanceGroup %q not found", options.InstanceGroup)
+	}
+
+	settings, err := hardening.Build(cluster.Spec.NodeHardening, ig.Spec.NodeHardening)
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		return fmt.Errorf("nodeHardening is not configured for InstanceGroup %q", ig.Name)
+	}
+	checks := settings.Checks()
+
+	var results []*hardeningCheckResult
+	for _, host := range args {
+		nodeSSH := &kutil.NodeSSH{
+			Hostname: host,
+		}
+		nodeSSH.SSHConfig.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+		nodeSSH.SSHConfig.User = options.SSHUser
+		if err := kutil.AddSSHIdentity(&nodeSSH.SSHConfig, options.SSHPrivateKey); err != nil {
+			return err
+		}
+
+		sshClient, err := nodeSSH.GetSSHClient()
+		if err != nil {
+			return fmt.Errorf("error getting SSH client: %v", err)
+		}
+
+		for _, check := range checks {
+			result := &hardeningCheckResult{
+				Host:  host,
+				Check: check.Name,
+			}
+			output, err := runSshCommandOutput(sshClient, "sudo sh -c "+shellQuote(check.Command))
+			if err != nil {
+				result.Detail = err.Error()
+			} else {
+				result.Passed, result.Detail = check.Evaluate(output)
+			}
+			results = append(results, result)
+		}
+		sshClient.Close()
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("HOST", func(r *hardeningCheckResult) string {
+		return r.Host
+	})
+	t.AddColumn("CHECK", func(r *hardeningCheckResult) string {
+		return r.Check
+	})
+	t.AddColumn("RESULT", func(r *hardeningCheckResult) string {
+		if r.Passed {
+			return "PASS"
+		}
+		return "FAIL"
+	})
+	t.AddColumn("DETAIL", func(r *hardeningCheckResult) string {
+		return r.Detail
+	})
+	if err := t.Render(results, out, "HOST", "CHECK", "RESULT", "DETAIL"); err != nil {
+		return fmt.Errorf("error rendering results table: %v", err)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d of %d hardening checks failed", failed, len(results))
+	}
+	fmt.Fprintf(out, "\nAll %d hardening checks passed\n", len(results))
+
+	return nil
+}
+
+// runSshCommandOutput runs a command, returning its stdout
+func runSshCommandOutput(sshClient *ssh.Client, cmd string) (string, error) {
+	s, err := sshClient.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("error creating ssh session: %v", err)
+	}
+	defer s.Close()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	s.Stdout = &stdout
+	s.Stderr = &stderr
+
+	if err := s.Run(cmd); err != nil {
+		return "", fmt.Errorf("error running command: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
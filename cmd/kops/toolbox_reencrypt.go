@@ -0,0 +1,192 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/secretencryption"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxReencryptLong = templates.LongDesc(i18n.T(`
+	Re-encrypts the secrets and private keys of a cluster in the state store.
+
+	Files are rewritten with the key configured in secretEncryption on the cluster, or written
+	in plaintext if secretEncryption is not set.  Run this after enabling, changing or disabling
+	secretEncryption, and before the next rolling update.  Files encrypted with an earlier key are
+	decrypted with the previousKeys of secretEncryption.`))
+
+	toolboxReencryptExample = templates.Examples(i18n.T(`
+	# List the files that need to be re-encrypted
+	kops toolbox reencrypt --name k8s-cluster.example.com
+
+	# Re-encrypt the files
+	kops toolbox reencrypt --name k8s-cluster.example.com --yes
+	`))
+
+	toolboxReencryptShort = i18n.T(`Re-encrypt secrets and private keys in the state store`)
+)
+
+type ToolboxReencryptOptions struct {
+	ClusterName string
+	Yes         bool
+}
+
+func NewCmdToolboxReencrypt(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxReencryptOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "reencrypt",
+		Short:   toolboxReencryptShort,
+		Long:    toolboxReencryptLong,
+		Example: toolboxReencryptExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxReencrypt(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Rewrite the files; without this flag the files that need re-encrypting are only listed")
+
+	return cmd
+}
+
+func RunToolboxReencrypt(f *util.Factory, out io.Writer, options *ToolboxReencryptOptions) error {
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	cluster, err := clientset.GetCluster(options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	encrypter, err := secretencryption.ForCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	var files []vfs.Path
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+	if hasVFSPath, ok := secretStore.(fi.HasVFSPath); ok {
+		secrets, err := hasVFSPath.VFSPath().ReadTree()
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error listing secrets: %v", err)
+		}
+		files = append(files, secrets...)
+	} else {
+		return fmt.Errorf("secret store %T is not stored in VFS", secretStore)
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	if hasVFSPath, ok := keyStore.(fi.HasVFSPath); ok {
+		privateKeys, err := hasVFSPath.VFSPath().Join("private").ReadTree()
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error listing private keys: %v", err)
+		}
+		files = append(files, privateKeys...)
+	} else {
+		return fmt.Errorf("key store %T is not stored in VFS", keyStore)
+	}
+
+	changed := 0
+	for _, p := range files {
+		data, err := p.ReadFile()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", p, err)
+		}
+		if encrypter.IsCurrent(data) {
+			continue
+		}
+		changed++
+
+		if !options.Yes {
+			fmt.Fprintf(out, "Will re-encrypt %s\n", p)
+			continue
+		}
+
+		if err := reencryptFile(cluster, encrypter, p, data); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Re-encrypted %s\n", p)
+	}
+
+	if changed == 0 {
+		fmt.Fprintf(out, "All %d secrets and private keys are up to date\n", len(files))
+	} else if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to re-encrypt %d files\n", changed)
+	}
+
+	return nil
+}
+
+// reencryptFile rewrites the file with the current encryption, preserving its ACL
+func reencryptFile(cluster *kops.Cluster, encrypter *secretencryption.Encrypter, p vfs.Path, data []byte) error {
+	plaintext, err := secretencryption.Decrypt(cluster, data)
+	if err != nil {
+		return fmt.Errorf("error decrypting %s: %v", p, err)
+	}
+	encrypted, err := encrypter.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("error encrypting %s: %v", p, err)
+	}
+
+	acl, err := acls.GetACL(p, cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteFile(bytes.NewReader(encrypted), acl); err != nil {
+		return fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return nil
+}
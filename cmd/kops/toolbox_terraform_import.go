@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxTerraformImportLong = templates.LongDesc(i18n.T(`
+	Generate the terraform import commands for the resources of a cluster which already exist.
+
+	The resources of the cluster are looked up in the cloud, as kops update cluster does, and every
+	resource found is mapped to the address the terraform target writes it to. This lets a cluster
+	created with --target=direct be moved to --target=terraform without recreating its resources.
+
+	The output is a shell script running terraform import. Resources which exist but cannot be
+	imported automatically are listed as comments.`))
+
+	toolboxTerraformImportExample = templates.Examples(i18n.T(`
+	# Write the terraform configuration of a cluster, then import its existing resources
+	kops update cluster --name k8s-cluster.example.com --target=terraform --out=.
+	kops toolbox terraform-import --name k8s-cluster.example.com --out import.sh
+	sh import.sh
+
+	# Import the resources of a cluster used through the terraform-module target
+	kops toolbox terraform-import --name k8s-cluster.example.com --module k8s-cluster-example-com --out import.sh
+	`))
+
+	toolboxTerraformImportShort = i18n.T(`Generate terraform import commands for the existing resources of a cluster`)
+)
+
+type ToolboxTerraformImportOptions struct {
+	// Module is the name of the module the configuration of the cluster is used through, if any
+	Module string
+
+	// Out is the file the imports are written to; they are written to stdout if not set
+	Out string
+}
+
+func NewCmdToolboxTerraformImport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxTerraformImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "terraform-import",
+		Short:   toolboxTerraformImportShort,
+		Long:    toolboxTerraformImportLong,
+		Example: toolboxTerraformImportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			err = RunToolboxTerraformImport(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Module, "module", options.Module, "Name of the module block the terraform-module output is used through")
+	cmd.Flags().StringVar(&options.Out, "out", options.Out, "File to write the imports to (defaults to stdout)")
+
+	return cmd
+}
+
+func RunToolboxTerraformImport(f *util.Factory, out io.Writer, options *ToolboxTerraformImportOptions) error {
+	cluster, err := rootCommand.Cluster()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Clientset:            clientset,
+		Cluster:              cluster,
+		DryRun:               true,
+		TargetName:           cloudup.TargetTerraform,
+		FindTerraformImports: true,
+	}
+	if err := applyCmd.Run(); err != nil {
+		return err
+	}
+
+	data := terraform.RenderImportScript(applyCmd.TerraformImports, options.Module)
+
+	skipped := 0
+	for _, i := range applyCmd.TerraformImports {
+		if i.ID == "" {
+			klog.Warningf("%s exists, but cannot be imported automatically", i.Address())
+			skipped++
+		}
+	}
+
+	if options.Out == "" {
+		_, err := out.Write(data)
+		return err
+	}
+
+	if err := ioutil.WriteFile(options.Out, data, 0644); err != nil {
+		return fmt.Errorf("error writing %q: %v", options.Out, err)
+	}
+	fmt.Fprintf(out, "Wrote %d imports to %s\n", len(applyCmd.TerraformImports)-skipped, options.Out)
+	if skipped != 0 {
+		fmt.Fprintf(out, "%d existing resources cannot be imported automatically, and are listed as comments\n", skipped)
+	}
+	return nil
+}
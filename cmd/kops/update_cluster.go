@@ -108,7 +108,7 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Create cloud resources, without --yes update is in dry run mode")
-	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, cloudformation")
+	cmd.Flags().StringVar(&options.Target, "target", options.Target, "Target - direct, terraform, terraform-module, cloudformation")
 	cmd.Flags().StringVar(&options.Models, "model", options.Models, "Models to apply (separate multiple models with commas)")
 	cmd.Flags().StringVar(&options.SSHPublicKey, "ssh-public-key", options.SSHPublicKey, "SSH public key to use (deprecated: use kops create secret instead)")
 	cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Path to write any local output")
@@ -148,7 +148,7 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 	}
 
 	if c.OutDir == "" {
-		if c.Target == cloudup.TargetTerraform {
+		if c.Target == cloudup.TargetTerraform || c.Target == cloudup.TargetTerraformModule {
 			c.OutDir = "out/terraform"
 		} else if c.Target == cloudup.TargetCloudformation {
 			c.OutDir = "out/cloudformation"
@@ -319,6 +319,22 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 				fmt.Fprintf(sb, "   terraform apply\n")
 				fmt.Fprintf(sb, "\n")
 			}
+		} else if c.Target == cloudup.TargetTerraformModule {
+			fmt.Fprintf(sb, "\n")
+			fmt.Fprintf(sb, "Terraform module has been placed into %s\n", c.OutDir)
+
+			if firstRun {
+				// Terraform only treats paths starting with ./ or ../ as local modules
+				source := c.OutDir
+				if !filepath.IsAbs(source) && !strings.HasPrefix(source, ".") {
+					source = "./" + source
+				}
+				fmt.Fprintf(sb, "Use the module from your root module, which configures the provider:\n")
+				fmt.Fprintf(sb, "   module %q {\n", strings.Replace(clusterName, ".", "-", -1))
+				fmt.Fprintf(sb, "     source = %q\n", source)
+				fmt.Fprintf(sb, "   }\n")
+				fmt.Fprintf(sb, "\n")
+			}
 		} else if c.Target == cloudup.TargetCloudformation {
 			fmt.Fprintf(sb, "\n")
 			fmt.Fprintf(sb, "Cloudformation output has been placed into %s\n", c.OutDir)
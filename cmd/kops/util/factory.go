@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
+	azureacls "k8s.io/kops/pkg/acls/azure"
 	gceacls "k8s.io/kops/pkg/acls/gce"
 	s3acls "k8s.io/kops/pkg/acls/s3"
 	kopsclient "k8s.io/kops/pkg/client/clientset_generated/clientset"
@@ -43,6 +44,7 @@ type Factory struct {
 }
 
 func NewFactory(options *FactoryOptions) *Factory {
+	azureacls.Register()
 	gceacls.Register()
 	s3acls.Register()
 
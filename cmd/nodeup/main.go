@@ -60,6 +60,11 @@ func main() {
 	installSystemdUnit := false
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
 
+	reconcile := false
+	flag.BoolVar(&reconcile, "reconcile", reconcile, "If true, will keep running and periodically apply the configuration changes that are safe to apply in place")
+	var flagInterval time.Duration
+	flag.DurationVar(&flagInterval, "interval", 5*time.Minute, "the time between two reconciliations, with --reconcile")
+
 	if dryrun {
 		target = "dryrun"
 	}
@@ -117,6 +122,17 @@ func main() {
 				fmt.Printf("service installed")
 				os.Exit(0)
 			}
+		} else if reconcile {
+			cmd := &nodeup.NodeUpCommand{
+				ConfigLocation: flagConf,
+				Target:         target,
+				CacheDir:       flagCacheDir,
+				FSRoot:         flagRootFS,
+				ModelDir:       models.NewAssetPath("nodeup"),
+			}
+			// Reconcile only returns on invalid configuration, so we don't retry
+			err = cmd.Reconcile(flagInterval)
+			klog.Exitf("error running nodeup: %v", err)
 		} else {
 			cmd := &nodeup.NodeUpCommand{
 				ConfigLocation: flagConf,
@@ -110,6 +110,32 @@ func (i *Installation) buildSystemdJob() *nodetasks.Service {
 	manifest.Set("Unit", "Description", "Run kops bootstrap (nodeup)")
 	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
 
+	if env := ServiceEnvironment(); env != "" {
+		manifest.Set("Service", "Environment", env)
+	}
+
+	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	manifest.Set("Service", "ExecStart", command)
+	manifest.Set("Service", "Type", "oneshot")
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", serviceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       serviceName,
+		Definition: fi.String(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
+
+// ServiceEnvironment returns the systemd Environment setting that passes the cloud and state store
+// credentials nodeup was started with on to a nodeup service.
+func ServiceEnvironment() string {
 	var buffer bytes.Buffer
 
 	if os.Getenv("AWS_REGION") != "" {
@@ -129,6 +155,13 @@ func (i *Installation) buildSystemdJob() *nodetasks.Service {
 		buffer.WriteString("\"S3_ENDPOINT=")
 		buffer.WriteString(os.Getenv("S3_ENDPOINT"))
 		buffer.WriteString("\" ")
+	}
+	if os.Getenv("S3_BUCKET_ENDPOINTS") != "" {
+		buffer.WriteString("\"S3_BUCKET_ENDPOINTS=")
+		buffer.WriteString(os.Getenv("S3_BUCKET_ENDPOINTS"))
+		buffer.WriteString("\" ")
+	}
+	if os.Getenv("S3_ENDPOINT") != "" || os.Getenv("S3_BUCKET_ENDPOINTS") != "" {
 		buffer.WriteString("\"S3_REGION=")
 		buffer.WriteString(os.Getenv("S3_REGION"))
 		buffer.WriteString("\" ")
@@ -180,25 +213,5 @@ func (i *Installation) buildSystemdJob() *nodetasks.Service {
 		buffer.WriteString("\" ")
 	}
 
-	if buffer.String() != "" {
-		manifest.Set("Service", "Environment", buffer.String())
-	}
-
-	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
-	manifest.Set("Service", "ExecStart", command)
-	manifest.Set("Service", "Type", "oneshot")
-
-	manifest.Set("Install", "WantedBy", "multi-user.target")
-
-	manifestString := manifest.Render()
-	klog.V(8).Infof("Built service manifest %q\n%s", serviceName, manifestString)
-
-	service := &nodetasks.Service{
-		Name:       serviceName,
-		Definition: fi.String(manifestString),
-	}
-
-	service.InitDefaults()
-
-	return service
+	return buffer.String()
 }
@@ -18,6 +18,7 @@ package model
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/try"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
 
@@ -162,6 +164,19 @@ func (b *CloudConfigBuilder) Build(c *fi.ModelBuilderContext) error {
 				fmt.Sprintf("ignore-volume-az=%t", fi.BoolValue(bs.IgnoreAZ)),
 				"")
 		}
+	case "azure":
+		// The azure cloudprovider reads a JSON document rather than an ini file
+		config, err := b.buildAzureCloudConfig()
+		if err != nil {
+			return err
+		}
+		c.AddTask(&nodetasks.File{
+			Path:     CloudConfigFilePath,
+			Contents: fi.NewBytesResource(config),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0600"),
+		})
+		return nil
 	}
 
 	config := "[global]\n" + strings.Join(lines, "\n") + "\n"
@@ -176,6 +191,61 @@ func (b *CloudConfigBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// azureCloudConfig is the configuration file of the azure cloudprovider
+type azureCloudConfig struct {
+	Cloud                       string `json:"cloud,omitempty"`
+	TenantID                    string `json:"tenantId,omitempty"`
+	SubscriptionID              string `json:"subscriptionId,omitempty"`
+	ResourceGroup               string `json:"resourceGroup,omitempty"`
+	Location                    string `json:"location,omitempty"`
+	VnetName                    string `json:"vnetName,omitempty"`
+	SubnetName                  string `json:"subnetName,omitempty"`
+	SecurityGroupName           string `json:"securityGroupName,omitempty"`
+	RouteTableName              string `json:"routeTableName,omitempty"`
+	VMType                      string `json:"vmType,omitempty"`
+	LoadBalancerSku             string `json:"loadBalancerSku,omitempty"`
+	UseManagedIdentityExtension bool   `json:"useManagedIdentityExtension"`
+	UseInstanceMetadata         bool   `json:"useInstanceMetadata"`
+}
+
+// buildAzureCloudConfig builds the azure cloudprovider configuration; the VMs authenticate with their managed identity
+func (b *CloudConfigBuilder) buildAzureCloudConfig() ([]byte, error) {
+	region, err := azureup.FindRegion(b.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &azureCloudConfig{
+		Cloud:                       os.Getenv("AZURE_ENVIRONMENT"),
+		SubscriptionID:              azureup.FindSubscriptionID(b.Cluster),
+		ResourceGroup:               azureup.FindResourceGroupName(b.Cluster),
+		Location:                    region,
+		VnetName:                    azureup.VirtualNetworkName(b.Cluster),
+		SecurityGroupName:           azureup.NetworkSecurityGroupName(b.Cluster),
+		RouteTableName:              azureup.RouteTableName(b.Cluster),
+		VMType:                      "vmss",
+		LoadBalancerSku:             "standard",
+		UseManagedIdentityExtension: true,
+		UseInstanceMetadata:         true,
+	}
+	if config.Cloud == "" {
+		config.Cloud = "AzurePublicCloud"
+	}
+	if cloudConfig := b.Cluster.Spec.CloudConfig; cloudConfig != nil && cloudConfig.Azure != nil {
+		config.TenantID = cloudConfig.Azure.TenantID
+	}
+	// The cloudprovider creates internal load balancers in this subnet
+	if len(b.Cluster.Spec.Subnets) != 0 {
+		config.SubnetName = azureup.SubnetName(b.Cluster, &b.Cluster.Spec.Subnets[0])
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error building azure cloud config: %v", err)
+	}
+	return data, nil
+}
+
 // We need this for vSphere CloudProvider
 // getVMUUID gets instance uuid of the VM from the file written by cloud-init
 func getVMUUID(kubernetesVersion string) (string, error) {
@@ -58,6 +58,14 @@ type NodeupModelContext struct {
 	kubernetesVersion semver.Version
 }
 
+// InPlaceSafeBuilder is implemented by the model builders whose tasks can be re-applied to a running node,
+// when nodeup runs in reconcile mode; changes to the tasks of any other builder require replacing the node
+type InPlaceSafeBuilder interface {
+	fi.ModelBuilder
+	// InPlaceSafe returns true if changes to the tasks of the builder can be applied in place
+	InPlaceSafe() bool
+}
+
 // Init completes initialization of the object, for example pre-parsing the kubernetes version
 func (c *NodeupModelContext) Init() error {
 	k8sVersion, err := util.ParseKubernetesVersion(c.Cluster.Spec.KubernetesVersion)
@@ -293,6 +301,24 @@ func (c *NodeupModelContext) IsKubernetesGTE(version string) bool {
 	return util.IsKubernetesGTE(version, c.kubernetesVersion)
 }
 
+// RemapImage returns the image to run, pinned to the digest recorded in the nodeup config.
+// If the nodeup config requires pinned images, an image we cannot pin is an error.
+func (c *NodeupModelContext) RemapImage(image string) (string, error) {
+	if strings.Contains(image, "@sha256:") {
+		return image, nil
+	}
+	if c.NodeupConfig == nil {
+		return image, nil
+	}
+	if pinned, found := c.NodeupConfig.ImageDigests[image]; found {
+		return pinned, nil
+	}
+	if c.NodeupConfig.RequireImageDigests {
+		return "", fmt.Errorf("refusing to run image %q, which is not pinned to a digest", image)
+	}
+	return image, nil
+}
+
 // UseEtcdManager checks if the etcd cluster has etcd-manager enabled
 func (c *NodeupModelContext) UseEtcdManager() bool {
 	for _, x := range c.Cluster.Spec.EtcdClusters {
@@ -34,6 +34,12 @@ type FileAssetsBuilder struct {
 }
 
 var _ fi.ModelBuilder = &FileAssetsBuilder{}
+var _ InPlaceSafeBuilder = &FileAssetsBuilder{}
+
+// InPlaceSafe implements InPlaceSafeBuilder
+func (f *FileAssetsBuilder) InPlaceSafe() bool {
+	return true
+}
 
 var templateFuncs = template.FuncMap{
 	"split": strings.Split,
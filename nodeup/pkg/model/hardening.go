@@ -0,0 +1,268 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kops/nodeup/pkg/distros"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/hardening"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// HardeningServiceName is the name of the service that applies the hardening settings that are not files
+const HardeningServiceName = "kops-hardening.service"
+
+// HardeningBuilder applies the OS hardening profile of the node.
+// It must run after all the builders that write files, as it restricts the modes of those files.
+type HardeningBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &HardeningBuilder{}
+
+// Build is responsible for hardening the node
+func (b *HardeningBuilder) Build(c *fi.ModelBuilderContext) error {
+	var igSpec *kops.NodeHardeningSpec
+	if b.InstanceGroup != nil {
+		igSpec = b.InstanceGroup.Spec.NodeHardening
+	}
+	settings, err := hardening.Build(b.Cluster.Spec.NodeHardening, igSpec)
+	if err != nil {
+		return err
+	}
+	if settings == nil {
+		return nil
+	}
+
+	if len(settings.Sysctls) != 0 {
+		var lines []string
+		lines = append(lines, "# Hardening settings, applied before the Kubernetes settings of 99-k8s-general.conf", "")
+		for _, k := range sortedSettingKeys(settings.Sysctls) {
+			lines = append(lines, k+" = "+settings.Sysctls[k])
+		}
+		lines = append(lines, "")
+
+		c.AddTask(&nodetasks.File{
+			Path:            "/etc/sysctl.d/90-kops-hardening.conf",
+			Contents:        fi.NewStringResource(strings.Join(lines, "\n")),
+			Type:            nodetasks.FileType_File,
+			OnChangeExecute: [][]string{{"sysctl", "--system"}},
+		})
+	}
+
+	if len(settings.BlacklistedModules) != 0 {
+		var lines []string
+		for _, m := range settings.BlacklistedModules {
+			lines = append(lines, "install "+m+" /bin/true", "blacklist "+m)
+		}
+		lines = append(lines, "")
+
+		c.AddTask(&nodetasks.File{
+			Path:     "/etc/modprobe.d/kops-hardening.conf",
+			Contents: fi.NewStringResource(strings.Join(lines, "\n")),
+			Type:     nodetasks.FileType_File,
+		})
+	}
+
+	if len(settings.AuditRules) != 0 {
+		if err := b.buildAuditRules(c, settings.AuditRules); err != nil {
+			return err
+		}
+	}
+
+	if len(settings.SSHD) != 0 {
+		if err := b.buildSSHDConfig(c, settings.SSHD); err != nil {
+			return err
+		}
+	}
+
+	if len(settings.DisabledServices) != 0 {
+		c.AddTask(b.buildHardeningService(settings.DisabledServices))
+	}
+
+	if len(settings.FileModes) != 0 {
+		if err := b.restrictFileModes(c, settings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *HardeningBuilder) buildAuditRules(c *fi.ModelBuilderContext, rules []string) error {
+	file := &nodetasks.File{
+		Path:     "/etc/audit/rules.d/kops-hardening.rules",
+		Contents: fi.NewStringResource(strings.Join(rules, "\n") + "\n"),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0600"),
+	}
+
+	switch {
+	case b.Distribution.IsDebianFamily():
+		// The auditd service loads the rules when the package is installed
+		c.AddTask(&nodetasks.Package{Name: "auditd"})
+		file.OnChangeExecute = [][]string{{"/bin/sh", "-c", "if command -v augenrules >/dev/null; then augenrules --load; fi"}}
+	case b.Distribution.IsRHELFamily():
+		c.AddTask(&nodetasks.Package{Name: "audit"})
+		file.OnChangeExecute = [][]string{{"/bin/sh", "-c", "if command -v augenrules >/dev/null; then augenrules --load; fi"}}
+	case b.Distribution == distros.DistributionCoreOS || b.Distribution == distros.DistributionFlatcar:
+		file.OnChangeExecute = [][]string{{"systemctl", "restart", "audit-rules.service"}}
+		service := &nodetasks.Service{Name: "auditd.service"}
+		service.InitDefaults()
+		c.AddTask(service)
+	default:
+		klog.Warningf("auditd rules are not supported on distribution %q", b.Distribution)
+		return nil
+	}
+
+	c.AddTask(file)
+	return nil
+}
+
+// sshdHardeningConfig holds the sshd settings of the hardening profile, which are merged into /etc/ssh/sshd_config
+const sshdHardeningConfig = "/etc/ssh/sshd_config.kops-hardening"
+
+// buildSSHDConfig writes the sshd settings and merges them into the sshd configuration of the distribution.
+// sshd uses the first value of each keyword, so the settings are prepended to the existing configuration; the settings
+// we do not manage (e.g. AuthorizedKeysCommand) are kept.  Many of the sshd versions we support do not
+// understand Include, so we cannot use a drop-in file.
+func (b *HardeningBuilder) buildSSHDConfig(c *fi.ModelBuilderContext, settings map[string]string) error {
+	var serviceName string
+	switch {
+	case b.Distribution.IsDebianFamily():
+		serviceName = "ssh.service"
+	case b.Distribution.IsRHELFamily():
+		serviceName = "sshd.service"
+	default:
+		// CoreOS and Flatcar link sshd_config to a read-only file
+		klog.Warningf("sshd hardening is not supported on distribution %q", b.Distribution)
+		return nil
+	}
+
+	var lines []string
+	lines = append(lines, "# BEGIN kops nodeHardening")
+	for _, k := range sortedSettingKeys(settings) {
+		lines = append(lines, k+" "+settings[k])
+	}
+	lines = append(lines, "# END kops nodeHardening", "")
+
+	// Replace the block we previously merged, and check the result before installing it
+	merge := strings.Join([]string{
+		"set -e",
+		"merged=$(mktemp)",
+		"trap 'rm -f \"${merged}\"' EXIT",
+		"{ cat " + sshdHardeningConfig + "; sed '/^# BEGIN kops nodeHardening$/,/^# END kops nodeHardening$/d' /etc/ssh/sshd_config; } > \"${merged}\"",
+		"/usr/sbin/sshd -t -f \"${merged}\"",
+		"cat \"${merged}\" > /etc/ssh/sshd_config",
+		"systemctl reload-or-restart " + serviceName,
+	}, "\n")
+
+	c.AddTask(&nodetasks.File{
+		Path:            sshdHardeningConfig,
+		Contents:        fi.NewStringResource(strings.Join(lines, "\n")),
+		Type:            nodetasks.FileType_File,
+		Mode:            s("0600"),
+		OnChangeExecute: [][]string{{"/bin/sh", "-c", merge}},
+	})
+	return nil
+}
+
+// buildHardeningService builds a oneshot service disabling the unused services.
+// Services that are not installed are ignored, so that profiles can list services of all distributions.
+func (b *HardeningBuilder) buildHardeningService(disabled []string) *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Apply the node hardening profile")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+
+	manifest.Set("Service", "Type", "oneshot")
+	manifest.Set("Service", "RemainAfterExit", "yes")
+	for _, name := range disabled {
+		manifest.Set("Service", "ExecStart", "-/bin/systemctl disable --now "+name)
+	}
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", HardeningServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       HardeningServiceName,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	return service
+}
+
+// restrictFileModes removes the permissions that the hardening settings do not allow from the files written by nodeup,
+// and records the files so that their state can be checked.
+func (b *HardeningBuilder) restrictFileModes(c *fi.ModelBuilderContext, settings *hardening.Settings) error {
+	var paths []string
+	for _, task := range c.Tasks {
+		file, ok := task.(*nodetasks.File)
+		if !ok || file.Type == nodetasks.FileType_Symlink {
+			continue
+		}
+
+		modeString := fi.StringValue(file.Mode)
+		if modeString == "" {
+			modeString = "0644"
+			if file.Type == nodetasks.FileType_Directory {
+				modeString = "0755"
+			}
+		}
+		mode, err := hardening.ParseMode(modeString)
+		if err != nil {
+			return fmt.Errorf("invalid file mode for %q: %v", file.Path, err)
+		}
+
+		limit, found := settings.FileModeLimit(file.Path, file.Type == nodetasks.FileType_Directory || mode&0111 != 0)
+		if !found {
+			continue
+		}
+		paths = append(paths, file.Path)
+
+		if mode&^limit != 0 {
+			file.Mode = s(fi.FileModeToString(mode & limit))
+		}
+	}
+	sort.Strings(paths)
+
+	c.AddTask(&nodetasks.File{
+		Path:     hardening.FileListPath,
+		Contents: fi.NewStringResource(strings.Join(paths, "\n") + "\n"),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0600"),
+	})
+	return nil
+}
+
+func sortedSettingKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func Test_RunHardeningBuilder(t *testing.T) {
+	basedir := "tests/hardening"
+
+	context := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	nodeUpModelContext, err := BuildNodeupModelContext(basedir)
+	if err != nil {
+		t.Fatalf("error loading model %q: %v", basedir, err)
+		return
+	}
+
+	// Files written by other builders, whose modes are restricted
+	context.AddTask(&nodetasks.File{
+		Path: "/etc/kubernetes/manifests",
+		Type: nodetasks.FileType_Directory,
+		Mode: s("0755"),
+	})
+	context.AddTask(&nodetasks.File{
+		Path:     "/srv/kubernetes/ca.crt",
+		Contents: fi.NewStringResource("ca"),
+		Type:     nodetasks.FileType_File,
+	})
+	context.AddTask(&nodetasks.File{
+		Path:     "/var/lib/kubelet/kubeconfig",
+		Contents: fi.NewStringResource("kubeconfig"),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0400"),
+	})
+	context.AddTask(&nodetasks.File{
+		Path:     "/usr/local/bin/kubelet",
+		Contents: fi.NewStringResource("kubelet"),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0755"),
+	})
+
+	builder := HardeningBuilder{NodeupModelContext: nodeUpModelContext}
+	if err := builder.Build(context); err != nil {
+		t.Fatalf("error from HardeningBuilder Build: %v", err)
+		return
+	}
+
+	testutils.ValidateTasks(t, basedir, context)
+}
@@ -35,6 +35,12 @@ type HookBuilder struct {
 }
 
 var _ fi.ModelBuilder = &HookBuilder{}
+var _ InPlaceSafeBuilder = &HookBuilder{}
+
+// InPlaceSafe implements InPlaceSafeBuilder
+func (h *HookBuilder) InPlaceSafe() bool {
+	return true
+}
 
 // Build is responsible for implementing the cluster hook
 func (h *HookBuilder) Build(c *fi.ModelBuilderContext) error {
@@ -147,6 +153,11 @@ func (h *HookBuilder) buildSystemdService(name string, hook *kops.HookSpec) (*no
 
 // buildDockerService is responsible for generating a docker exec unit file
 func (h *HookBuilder) buildDockerService(unit *systemd.Manifest, hook *kops.HookSpec) error {
+	image, err := h.RemapImage(hook.ExecContainer.Image)
+	if err != nil {
+		return err
+	}
+
 	dockerArgs := []string{
 		"/usr/bin/docker", "run",
 		"-v", "/:/rootfs/",
@@ -156,11 +167,11 @@ func (h *HookBuilder) buildDockerService(unit *systemd.Manifest, hook *kops.Hook
 		"--privileged",
 	}
 	dockerArgs = append(dockerArgs, buildDockerEnvironmentVars(hook.ExecContainer.Environment)...)
-	dockerArgs = append(dockerArgs, hook.ExecContainer.Image)
+	dockerArgs = append(dockerArgs, image)
 	dockerArgs = append(dockerArgs, hook.ExecContainer.Command...)
 
 	dockerRunCommand := systemd.EscapeCommand(dockerArgs)
-	dockerPullCommand := systemd.EscapeCommand([]string{"/usr/bin/docker", "pull", hook.ExecContainer.Image})
+	dockerPullCommand := systemd.EscapeCommand([]string{"/usr/bin/docker", "pull", image})
 
 	unit.Set("Unit", "Requires", "docker.service")
 	unit.Set("Service", "ExecStartPre", dockerPullCommand)
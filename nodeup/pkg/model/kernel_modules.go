@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+const (
+	// kernelModulesOptionsPath holds the options of the kernel modules
+	kernelModulesOptionsPath = "/etc/modprobe.d/kops.conf"
+	// kernelModulesLoadPath lists the kernel modules that systemd loads at boot
+	kernelModulesLoadPath = "/etc/modules-load.d/kops.conf"
+)
+
+// KernelModulesBuilder loads the kernel modules of the cluster and instance group specs
+type KernelModulesBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &KernelModulesBuilder{}
+var _ InPlaceSafeBuilder = &KernelModulesBuilder{}
+
+// InPlaceSafe implements InPlaceSafeBuilder
+func (b *KernelModulesBuilder) InPlaceSafe() bool {
+	return true
+}
+
+// Build is responsible for loading the kernel modules now and at boot
+func (b *KernelModulesBuilder) Build(c *fi.ModelBuilderContext) error {
+	modules := b.kernelModules()
+	if len(modules) == 0 {
+		return nil
+	}
+
+	var names []string
+	var options []string
+	for _, module := range modules {
+		names = append(names, module.Name)
+		if len(module.Parameters) != 0 {
+			options = append(options, "options "+module.Name+" "+strings.Join(module.Parameters, " "))
+		}
+	}
+
+	load := &nodetasks.File{
+		Path:     kernelModulesLoadPath,
+		Contents: fi.NewStringResource(strings.Join(names, "\n") + "\n"),
+		Type:     nodetasks.FileType_File,
+		// Loads the modules now; the services, such as docker and kubelet, are only started once the files are written
+		OnChangeExecute: [][]string{{"systemctl", "restart", "systemd-modules-load.service"}},
+	}
+	if len(options) != 0 {
+		c.AddTask(&nodetasks.File{
+			Path:     kernelModulesOptionsPath,
+			Contents: fi.NewStringResource(strings.Join(options, "\n") + "\n"),
+			Type:     nodetasks.FileType_File,
+		})
+		load.AfterFiles = []string{kernelModulesOptionsPath}
+	}
+	c.AddTask(load)
+
+	return nil
+}
+
+// kernelModules returns the kernel modules of the instance group, followed by those of the cluster it does not override
+func (b *KernelModulesBuilder) kernelModules() []kops.KernelModuleSpec {
+	var modules []kops.KernelModuleSpec
+	names := make(map[string]bool)
+	for _, spec := range [][]kops.KernelModuleSpec{b.InstanceGroup.Spec.KernelModules, b.Cluster.Spec.KernelModules} {
+		for _, module := range spec {
+			if len(module.Roles) > 0 && !containsRole(b.InstanceGroup.Spec.Role, module.Roles) {
+				continue
+			}
+			if names[module.Name] {
+				continue
+			}
+			names[module.Name] = true
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
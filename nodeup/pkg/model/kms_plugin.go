@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/encryptionatrest"
+	"k8s.io/kops/pkg/k8scodecs"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/proxy"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// KMSPluginBuilder installs the aws-encryption-provider KMS plugin, which the kube-apiserver uses to encrypt resources in etcd
+type KMSPluginBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &KMSPluginBuilder{}
+
+// Build is responsible for building the manifest of the KMS plugin
+func (b *KMSPluginBuilder) Build(c *fi.ModelBuilderContext) error {
+	if !b.IsMaster {
+		return nil
+	}
+	if b.Cluster.Spec.EncryptionAtRest == nil || b.Cluster.Spec.EncryptionAtRest.Provider != encryptionatrest.ProviderKMS {
+		return nil
+	}
+
+	pod, err := b.buildPod()
+	if err != nil {
+		return fmt.Errorf("error building %s pod: %v", encryptionatrest.KMSPluginName, err)
+	}
+
+	manifest, err := k8scodecs.ToVersionedYaml(pod)
+	if err != nil {
+		return fmt.Errorf("error marshaling pod to yaml: %v", err)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     "/etc/kubernetes/manifests/" + encryptionatrest.KMSPluginName + ".manifest",
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+	})
+
+	return nil
+}
+
+// buildPod is responsible for constructing the pod specification
+func (b *KMSPluginBuilder) buildPod() (*v1.Pod, error) {
+	kms := b.Cluster.Spec.EncryptionAtRest.KMS
+	if kms == nil {
+		return nil, fmt.Errorf("encryptionAtRest provider %q requires the kms settings", encryptionatrest.ProviderKMS)
+	}
+
+	region := kms.Region
+	if region == "" {
+		var err error
+		region, err = awsup.FindRegion(b.Cluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pod := &v1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Pod",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      encryptionatrest.KMSPluginName,
+			Namespace: "kube-system",
+			Labels: map[string]string{
+				"k8s-app": encryptionatrest.KMSPluginName,
+			},
+		},
+		Spec: v1.PodSpec{
+			// The plugin reads its credentials from the instance metadata
+			HostNetwork: true,
+		},
+	}
+
+	image, err := b.RemapImage(kms.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	container := &v1.Container{
+		Name:    encryptionatrest.KMSPluginName,
+		Image:   image,
+		Command: []string{"/aws-encryption-provider"},
+		Args: []string{
+			"--key=" + kms.KeyID,
+			"--region=" + region,
+			"--listen=" + encryptionatrest.KMSPluginSocket,
+		},
+		Env: proxy.GetProxyEnvVars(b.Cluster.Spec.EgressProxy),
+		LivenessProbe: &v1.Probe{
+			Handler: v1.Handler{
+				HTTPGet: &v1.HTTPGetAction{
+					Host: "127.0.0.1",
+					Path: "/healthz",
+					Port: intstr.FromInt(8083),
+				},
+			},
+			InitialDelaySeconds: 15,
+			TimeoutSeconds:      15,
+		},
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("10m"),
+			},
+		},
+	}
+
+	volumeType := v1.HostPathDirectoryOrCreate
+	addHostPathVolume(pod, container,
+		v1.HostPathVolumeSource{
+			Path: encryptionatrest.KMSPluginSocketDir,
+			Type: &volumeType,
+		},
+		v1.VolumeMount{
+			Name: "kmsplugin",
+		})
+
+	pod.Spec.Containers = append(pod.Spec.Containers, *container)
+
+	kubemanifest.MarkPodAsCritical(pod)
+	kubemanifest.MarkPodAsClusterCritical(pod)
+
+	return pod, nil
+}
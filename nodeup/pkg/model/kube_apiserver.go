@@ -22,6 +22,8 @@ import (
 	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/audit"
+	"k8s.io/kops/pkg/encryptionatrest"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubeconfig"
@@ -40,6 +42,9 @@ import (
 // PathAuthnConfig is the path to the custom webhook authentication config
 const PathAuthnConfig = "/etc/kubernetes/authn.config"
 
+// PathAuditConfig is the directory holding the audit policy and audit webhook config generated from the cluster spec
+const PathAuditConfig = "/etc/kubernetes/audit"
+
 // KubeAPIServerBuilder install kube-apiserver (just the manifest at the moment)
 type KubeAPIServerBuilder struct {
 	*NodeupModelContext
@@ -57,6 +62,10 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 		return err
 	}
 
+	if err := b.writeAuditConfig(c); err != nil {
+		return err
+	}
+
 	if b.Cluster.Spec.EncryptionConfig != nil {
 		if *b.Cluster.Spec.EncryptionConfig && b.IsKubernetesGTE("1.7") {
 			b.Cluster.Spec.KubeAPIServer.ExperimentalEncryptionProviderConfig = fi.String(filepath.Join(b.PathSrvKubernetes(), "encryptionconfig.yaml"))
@@ -74,6 +83,11 @@ func (b *KubeAPIServerBuilder) Build(c *fi.ModelBuilderContext) error {
 			}
 		}
 	}
+
+	if err := b.writeEncryptionAtRestConfig(c); err != nil {
+		return err
+	}
+
 	{
 		pod, err := b.buildPod()
 		if err != nil {
@@ -278,6 +292,117 @@ func (b *KubeAPIServerBuilder) writeAuthenticationConfig(c *fi.ModelBuilderConte
 	return fmt.Errorf("Unrecognized authentication config %v", b.Cluster.Spec.Authentication)
 }
 
+// writeAuditConfig writes the audit policy and the kubeconfig of the audit webhook, and points the kube-apiserver at them
+func (b *KubeAPIServerBuilder) writeAuditConfig(c *fi.ModelBuilderContext) error {
+	kubeAPIServer := b.Cluster.Spec.KubeAPIServer
+
+	if kubeAPIServer.AuditPolicy != nil {
+		manifest, err := audit.PolicyYAML(kubeAPIServer.AuditPolicy, audit.DefaultAPIVersion(b.kubernetesVersion))
+		if err != nil {
+			return err
+		}
+
+		kubeAPIServer.AuditPolicyFile = filepath.Join(PathAuditConfig, "policy.yaml")
+		c.AddTask(&nodetasks.File{
+			Path:     kubeAPIServer.AuditPolicyFile,
+			Contents: fi.NewBytesResource(manifest),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("600"),
+		})
+	}
+
+	if kubeAPIServer.AuditWebhook != nil {
+		webhook := kubeAPIServer.AuditWebhook
+
+		cluster := kubeconfig.KubectlCluster{
+			Server:                   webhook.Server,
+			CertificateAuthorityData: []byte(webhook.CACertificate),
+		}
+		user := kubeconfig.KubectlUser{}
+		if webhook.TokenSecret != "" {
+			secret, err := b.SecretStore.Secret(webhook.TokenSecret)
+			if err != nil {
+				return fmt.Errorf("error fetching audit webhook token secret %q: %v", webhook.TokenSecret, err)
+			}
+			user.Token = strings.TrimSpace(string(secret.Data))
+		}
+
+		config := kubeconfig.KubectlConfig{
+			Kind:       "Config",
+			ApiVersion: "v1",
+		}
+		config.Clusters = append(config.Clusters, &kubeconfig.KubectlClusterWithName{
+			Name:    "audit-webhook",
+			Cluster: cluster,
+		})
+		config.Users = append(config.Users, &kubeconfig.KubectlUserWithName{
+			Name: "kube-apiserver",
+			User: user,
+		})
+		config.CurrentContext = "audit-webhook"
+		config.Contexts = append(config.Contexts, &kubeconfig.KubectlContextWithName{
+			Name: "audit-webhook",
+			Context: kubeconfig.KubectlContext{
+				Cluster: "audit-webhook",
+				User:    "kube-apiserver",
+			},
+		})
+
+		manifest, err := kops.ToRawYaml(config)
+		if err != nil {
+			return fmt.Errorf("error marshaling audit webhook config to yaml: %v", err)
+		}
+
+		kubeAPIServer.AuditWebhookConfigFile = filepath.Join(PathAuditConfig, "webhook-config.yaml")
+		c.AddTask(&nodetasks.File{
+			Path:     kubeAPIServer.AuditWebhookConfigFile,
+			Contents: fi.NewBytesResource(manifest),
+			Type:     nodetasks.FileType_File,
+			Mode:     fi.String("600"),
+		})
+	}
+
+	return nil
+}
+
+// writeEncryptionAtRestConfig writes the encryption provider config generated from the encryption keys in the secret store, or for the KMS plugin
+func (b *KubeAPIServerBuilder) writeEncryptionAtRestConfig(c *fi.ModelBuilderContext) error {
+	spec := b.Cluster.Spec.EncryptionAtRest
+	if spec == nil {
+		return nil
+	}
+
+	var keys *encryptionatrest.KeySet
+	if spec.Provider != encryptionatrest.ProviderKMS {
+		var err error
+		keys, err = encryptionatrest.LoadKeySet(b.SecretStore)
+		if err != nil {
+			return err
+		}
+	}
+
+	config, err := encryptionatrest.BuildEncryptionConfig(spec, keys, b.kubernetesVersion)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(b.PathSrvKubernetes(), "encryption-at-rest.yaml")
+	if b.IsKubernetesGTE("1.13") {
+		b.Cluster.Spec.KubeAPIServer.EncryptionProviderConfig = fi.String(path)
+	} else {
+		b.Cluster.Spec.KubeAPIServer.ExperimentalEncryptionProviderConfig = fi.String(path)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:     path,
+		Contents: fi.NewBytesResource(config),
+		Type:     nodetasks.FileType_File,
+		Mode:     fi.String("600"),
+	})
+
+	return nil
+}
+
 // buildPod is responsible for generating the kube-apiserver pod and thus manifest file
 func (b *KubeAPIServerBuilder) buildPod() (*v1.Pod, error) {
 	kubeAPIServer := b.Cluster.Spec.KubeAPIServer
@@ -393,9 +518,14 @@ func (b *KubeAPIServerBuilder) buildPod() (*v1.Pod, error) {
 		requestCPU = resource.MustParse(b.Cluster.Spec.KubeAPIServer.CPURequest)
 	}
 
+	image, err := b.RemapImage(b.Cluster.Spec.KubeAPIServer.Image)
+	if err != nil {
+		return nil, err
+	}
+
 	container := &v1.Container{
 		Name:  "kube-apiserver",
-		Image: b.Cluster.Spec.KubeAPIServer.Image,
+		Image: image,
 		Env:   proxy.GetProxyEnvVars(b.Cluster.Spec.EgressProxy),
 		LivenessProbe: &v1.Probe{
 			Handler: v1.Handler{
@@ -485,6 +615,22 @@ func (b *KubeAPIServerBuilder) buildPod() (*v1.Pod, error) {
 		addHostPathMapping(pod, container, "auditlogpathdir", auditLogPathDir).ReadOnly = false
 	}
 
+	if b.Cluster.Spec.KubeAPIServer.AuditPolicy != nil || b.Cluster.Spec.KubeAPIServer.AuditWebhook != nil {
+		addHostPathMapping(pod, container, "audit-config", PathAuditConfig)
+	}
+
+	if b.Cluster.Spec.EncryptionAtRest != nil && b.Cluster.Spec.EncryptionAtRest.Provider == encryptionatrest.ProviderKMS {
+		volumeType := v1.HostPathDirectoryOrCreate
+		addHostPathVolume(pod, container,
+			v1.HostPathVolumeSource{
+				Path: encryptionatrest.KMSPluginSocketDir,
+				Type: &volumeType,
+			},
+			v1.VolumeMount{
+				Name: "kmsplugin",
+			})
+	}
+
 	if b.Cluster.Spec.Authentication != nil {
 		if b.Cluster.Spec.Authentication.Kopeio != nil || b.Cluster.Spec.Authentication.Aws != nil {
 			addHostPathMapping(pod, container, "authn-config", PathAuthnConfig)
@@ -17,11 +17,19 @@ limitations under the License.
 package model
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/blang/semver"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kops/nodeup/pkg/distros"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/encryptionatrest"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/upup/pkg/fi/secrets"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 func Test_KubeAPIServer_BuildFlags(t *testing.T) {
@@ -92,6 +100,15 @@ func Test_KubeAPIServer_BuildFlags(t *testing.T) {
 			},
 			"--experimental-encryption-provider-config=/srv/kubernetes/encryptionconfig.yaml --insecure-port=0 --secure-port=0",
 		},
+		{
+			kops.KubeAPIServerConfig{
+				AuditPolicy:            &runtime.RawExtension{Raw: []byte(`{"rules":[{"level":"Metadata"}]}`)},
+				AuditPolicyFile:        "/etc/kubernetes/audit/policy.yaml",
+				AuditWebhook:           &kops.AuditWebhookSpec{Server: "https://audit.example.com/events"},
+				AuditWebhookConfigFile: "/etc/kubernetes/audit/webhook-config.yaml",
+			},
+			"--audit-policy-file=/etc/kubernetes/audit/policy.yaml --audit-webhook-config-file=/etc/kubernetes/audit/webhook-config.yaml --insecure-port=0 --secure-port=0",
+		},
 		{
 			kops.KubeAPIServerConfig{
 				TargetRamMb: 320,
@@ -111,3 +128,139 @@ func Test_KubeAPIServer_BuildFlags(t *testing.T) {
 		}
 	}
 }
+
+func Test_KubeAPIServer_AuditConfig(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.KubeAPIServer = &kops.KubeAPIServerConfig{
+		AuditPolicy: &runtime.RawExtension{Raw: []byte(`{"rules":[{"level":"Metadata"}]}`)},
+		AuditWebhook: &kops.AuditWebhookSpec{
+			Server:      "https://audit.example.com/events",
+			TokenSecret: "auditwebhooktoken",
+		},
+	}
+
+	vfs.Context.ResetMemfsContext(true)
+	basedir, err := vfs.Context.BuildVfsPath("memfs://tests/secrets")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	secretStore := secrets.NewVFSSecretStore(cluster, basedir)
+	if _, _, err := secretStore.GetOrCreateSecret("auditwebhooktoken", &fi.Secret{Data: []byte("s3cr3t\n")}); err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+
+	b := &KubeAPIServerBuilder{
+		NodeupModelContext: &NodeupModelContext{
+			Cluster:           cluster,
+			Distribution:      distros.DistributionXenial,
+			SecretStore:       secretStore,
+			kubernetesVersion: semver.MustParse("1.15.3"),
+		},
+	}
+	c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+	if err := b.writeAuditConfig(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"/etc/kubernetes/audit/policy.yaml": `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+- level: Metadata
+`,
+		"/etc/kubernetes/audit/webhook-config.yaml": `apiVersion: v1
+clusters:
+- cluster:
+    server: https://audit.example.com/events
+  name: audit-webhook
+contexts:
+- context:
+    cluster: audit-webhook
+    user: kube-apiserver
+  name: audit-webhook
+current-context: audit-webhook
+kind: Config
+users:
+- name: kube-apiserver
+  user:
+    token: s3cr3t
+`,
+	}
+	for path, contents := range expected {
+		task, found := c.Tasks["File/"+path]
+		if !found {
+			t.Errorf("file %q was not written", path)
+			continue
+		}
+		actual, err := fi.ResourceAsString(task.(*nodetasks.File).Contents)
+		if err != nil {
+			t.Fatalf("error reading contents of %q: %v", path, err)
+		}
+		if actual != contents {
+			t.Errorf("unexpected contents of %q\nexpected:\n%s\ngot:\n%s", path, contents, actual)
+		}
+	}
+
+	if cluster.Spec.KubeAPIServer.AuditPolicyFile != "/etc/kubernetes/audit/policy.yaml" || cluster.Spec.KubeAPIServer.AuditWebhookConfigFile != "/etc/kubernetes/audit/webhook-config.yaml" {
+		t.Errorf("kube-apiserver was not pointed at the audit config: %+v", cluster.Spec.KubeAPIServer)
+	}
+}
+
+func Test_KubeAPIServer_EncryptionAtRestConfig(t *testing.T) {
+	grid := []struct {
+		version      string
+		expectedFlag string
+	}{
+		{version: "1.15.3", expectedFlag: "--encryption-provider-config=/srv/kubernetes/encryption-at-rest.yaml"},
+		{version: "1.12.10", expectedFlag: "--experimental-encryption-provider-config=/srv/kubernetes/encryption-at-rest.yaml"},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{}
+		cluster.Spec.KubeAPIServer = &kops.KubeAPIServerConfig{}
+		cluster.Spec.EncryptionAtRest = &kops.EncryptionAtRestSpec{Provider: "aescbc", Resources: []string{"secrets"}}
+
+		vfs.Context.ResetMemfsContext(true)
+		basedir, err := vfs.Context.BuildVfsPath("memfs://tests/secrets")
+		if err != nil {
+			t.Fatalf("error building vfs path: %v", err)
+		}
+		secretStore := secrets.NewVFSSecretStore(cluster, basedir)
+		keys := &encryptionatrest.KeySet{Keys: []encryptionatrest.Key{{Name: "key1", Secret: "Zmlyc3Q="}}}
+		if err := encryptionatrest.SaveKeySet(secretStore, keys); err != nil {
+			t.Fatalf("error saving keys: %v", err)
+		}
+
+		b := &KubeAPIServerBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Cluster:           cluster,
+				Distribution:      distros.DistributionXenial,
+				SecretStore:       secretStore,
+				kubernetesVersion: semver.MustParse(g.version),
+			},
+		}
+		c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+		if err := b.writeEncryptionAtRestConfig(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		task, found := c.Tasks["File//srv/kubernetes/encryption-at-rest.yaml"]
+		if !found {
+			t.Fatalf("encryption provider config was not written for %s", g.version)
+		}
+		actual, err := fi.ResourceAsString(task.(*nodetasks.File).Contents)
+		if err != nil {
+			t.Fatalf("error reading encryption provider config: %v", err)
+		}
+		if !strings.Contains(actual, "- name: key1\n        secret: Zmlyc3Q=\n") {
+			t.Errorf("encryption provider config doesn't hold the key:\n%s", actual)
+		}
+
+		flags, err := flagbuilder.BuildFlags(cluster.Spec.KubeAPIServer)
+		if err != nil {
+			t.Fatalf("error building flags: %v", err)
+		}
+		if !strings.Contains(flags, g.expectedFlag) {
+			t.Errorf("expected %q in the flags of %s, got %q", g.expectedFlag, g.version, flags)
+		}
+	}
+}
@@ -174,9 +174,14 @@ func (b *KubeControllerManagerBuilder) buildPod() (*v1.Pod, error) {
 	// Add the volumePluginDir flag if provided in the kubelet spec, or set above based on the OS
 	flags = append(flags, "--flex-volume-plugin-dir="+volumePluginDir)
 
+	image, err := b.RemapImage(b.Cluster.Spec.KubeControllerManager.Image)
+	if err != nil {
+		return nil, err
+	}
+
 	container := &v1.Container{
 		Name:  "kube-controller-manager",
-		Image: b.Cluster.Spec.KubeControllerManager.Image,
+		Image: image,
 		Env:   proxy.GetProxyEnvVars(b.Cluster.Spec.EgressProxy),
 		LivenessProbe: &v1.Probe{
 			Handler: v1.Handler{
@@ -19,10 +19,12 @@ package model
 import (
 	"fmt"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/dns"
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/kubeproxyconfig"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/exec"
@@ -33,6 +35,9 @@ import (
 	"k8s.io/klog"
 )
 
+// kubeProxyConfigFilePath is the path of the kube-proxy config file, used from kubernetes 1.12
+const kubeProxyConfigFilePath = "/var/lib/kube-proxy/config.yaml"
+
 // KubeProxyBuilder installs kube-proxy
 type KubeProxyBuilder struct {
 	*NodeupModelContext
@@ -89,6 +94,14 @@ func (b *KubeProxyBuilder) Build(c *fi.ModelBuilderContext) error {
 		})
 	}
 
+	if b.usesConfigFile() {
+		t, err := b.buildConfigFile()
+		if err != nil {
+			return err
+		}
+		c.AddTask(t)
+	}
+
 	{
 		c.AddTask(&nodetasks.File{
 			Path:        "/var/log/kube-proxy.log",
@@ -102,8 +115,13 @@ func (b *KubeProxyBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
-// buildPod is responsible constructing the pod spec
-func (b *KubeProxyBuilder) buildPod() (*v1.Pod, error) {
+// usesConfigFile is true if kube-proxy is configured with a KubeProxyConfiguration file rather than flags
+func (b *KubeProxyBuilder) usesConfigFile() bool {
+	return b.IsKubernetesGTE("1.12")
+}
+
+// buildKubeProxyConfig returns the kube-proxy spec, with the defaults that depend on the node
+func (b *KubeProxyBuilder) buildKubeProxyConfig() (*kops.KubeProxyConfig, error) {
 	c := b.Cluster.Spec.KubeProxy
 	if c == nil {
 		return nil, fmt.Errorf("KubeProxy not configured")
@@ -124,6 +142,54 @@ func (b *KubeProxyBuilder) buildPod() (*v1.Pod, error) {
 		}
 	}
 
+	if c.ConntrackMaxPerCore == nil {
+		defaultConntrackMaxPerCore := int32(131072)
+		c.ConntrackMaxPerCore = &defaultConntrackMaxPerCore
+	}
+
+	return c, nil
+}
+
+// buildConfigFile renders the KubeProxyConfiguration file
+func (b *KubeProxyBuilder) buildConfigFile() (*nodetasks.File, error) {
+	c, err := b.buildKubeProxyConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := kubeproxyconfig.BuildConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("error building kube-proxy config: %v", err)
+	}
+	config.ClientConnection = &kubeproxyconfig.ClientConnectionConfiguration{
+		Kubeconfig: "/var/lib/kube-proxy/kubeconfig",
+	}
+	config.OOMScoreAdj = fi.Int32(-998)
+	if !b.IsKubernetesGTE("1.16") {
+		// Removed in 1.16: https://github.com/kubernetes/kubernetes/pull/78294
+		config.ResourceContainer = fi.String("")
+	}
+
+	manifest, err := kubeproxyconfig.Marshal(config, c.ConfigOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering kube-proxy config: %v", err)
+	}
+
+	return &nodetasks.File{
+		Path:     kubeProxyConfigFilePath,
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0400"),
+	}, nil
+}
+
+// buildPod is responsible constructing the pod spec
+func (b *KubeProxyBuilder) buildPod() (*v1.Pod, error) {
+	c, err := b.buildKubeProxyConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	resourceRequests := v1.ResourceList{}
 	resourceLimits := v1.ResourceList{}
 
@@ -158,24 +224,35 @@ func (b *KubeProxyBuilder) buildPod() (*v1.Pod, error) {
 		resourceLimits["memory"] = memoryLimit
 	}
 
-	if c.ConntrackMaxPerCore == nil {
-		defaultConntrackMaxPerCore := int32(131072)
-		c.ConntrackMaxPerCore = &defaultConntrackMaxPerCore
-	}
-
-	flags, err := flagbuilder.BuildFlagsList(c)
-	if err != nil {
-		return nil, fmt.Errorf("error building kubeproxy flags: %v", err)
-	}
-	image := c.Image
+	var flags []string
+	if b.usesConfigFile() {
+		_, remaining, err := kubeproxyconfig.BuildConfig(c)
+		if err != nil {
+			return nil, fmt.Errorf("error building kube-proxy config: %v", err)
+		}
+		flags, err = flagbuilder.BuildFlagsList(remaining)
+		if err != nil {
+			return nil, fmt.Errorf("error building kubeproxy flags: %v", err)
+		}
+		flags = append(flags, "--config="+kubeProxyConfigFilePath)
+	} else {
+		flags, err = flagbuilder.BuildFlagsList(c)
+		if err != nil {
+			return nil, fmt.Errorf("error building kubeproxy flags: %v", err)
+		}
 
-	flags = append(flags, []string{
-		"--kubeconfig=/var/lib/kube-proxy/kubeconfig",
-		"--oom-score-adj=-998"}...)
+		flags = append(flags, []string{
+			"--kubeconfig=/var/lib/kube-proxy/kubeconfig",
+			"--oom-score-adj=-998"}...)
 
-	if !b.IsKubernetesGTE("1.16") {
-		// Removed in 1.16: https://github.com/kubernetes/kubernetes/pull/78294
-		flags = append(flags, `--resource-container=""`)
+		if !b.IsKubernetesGTE("1.16") {
+			// Removed in 1.16: https://github.com/kubernetes/kubernetes/pull/78294
+			flags = append(flags, `--resource-container=""`)
+		}
+	}
+	image, err := b.RemapImage(c.Image)
+	if err != nil {
+		return nil, err
 	}
 
 	container := &v1.Container{
@@ -225,6 +302,9 @@ func (b *KubeProxyBuilder) buildPod() (*v1.Pod, error) {
 
 	{
 		addHostPathMapping(pod, container, "kubeconfig", "/var/lib/kube-proxy/kubeconfig")
+		if b.usesConfigFile() {
+			addHostPathMapping(pod, container, "config", kubeProxyConfigFilePath)
+		}
 		// @note: mapping the host modules directory to fix the missing ipvs kernel module
 		addHostPathMapping(pod, container, "modules", "/lib/modules")
 
@@ -23,6 +23,10 @@ import (
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/pkg/k8scodecs"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/exec"
 
 	"github.com/blang/semver"
@@ -153,3 +157,39 @@ func TestKubeProxyBuilder_buildPod(t *testing.T) {
 		})
 	}
 }
+
+func Test_RunKubeProxyBuilder(t *testing.T) {
+	basedir := "tests/kubeproxy/ipvs"
+
+	context := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	nodeUpModelContext, err := BuildNodeupModelContext(basedir)
+	if err != nil {
+		t.Fatalf("error loading model %q: %v", basedir, err)
+	}
+
+	builder := KubeProxyBuilder{NodeupModelContext: nodeUpModelContext}
+
+	pod, err := builder.buildPod()
+	if err != nil {
+		t.Fatalf("error from KubeProxyBuilder buildPod: %v", err)
+	}
+	manifest, err := k8scodecs.ToVersionedYaml(pod)
+	if err != nil {
+		t.Fatalf("error marshaling manifest to yaml: %v", err)
+	}
+	context.AddTask(&nodetasks.File{
+		Path:     "/etc/kubernetes/manifests/kube-proxy.manifest",
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+	})
+
+	task, err := builder.buildConfigFile()
+	if err != nil {
+		t.Fatalf("error from KubeProxyBuilder buildConfigFile: %v", err)
+	}
+	context.AddTask(task)
+
+	testutils.ValidateTasks(t, basedir, context)
+}
@@ -22,6 +22,7 @@ import (
 	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/k8scodecs"
 	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/pkg/kubeschedulerconfig"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/util/pkg/exec"
@@ -33,6 +34,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// kubeSchedulerConfigFilePath is the path of the kube-scheduler config file, used from kubernetes 1.12
+const kubeSchedulerConfigFilePath = "/var/lib/kube-scheduler/config.yaml"
+
 // KubeSchedulerBuilder install kube-scheduler
 type KubeSchedulerBuilder struct {
 	*NodeupModelContext
@@ -78,6 +82,14 @@ func (b *KubeSchedulerBuilder) Build(c *fi.ModelBuilderContext) error {
 		})
 	}
 
+	if b.usesConfigFile() {
+		t, err := b.buildConfigFile()
+		if err != nil {
+			return err
+		}
+		c.AddTask(t)
+	}
+
 	{
 		c.AddTask(&nodetasks.File{
 			Path:        "/var/log/kube-scheduler.log",
@@ -91,16 +103,59 @@ func (b *KubeSchedulerBuilder) Build(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// usesConfigFile is true if the scheduler is configured with a KubeSchedulerConfiguration file rather than flags
+func (b *KubeSchedulerBuilder) usesConfigFile() bool {
+	return b.IsKubernetesGTE("1.12")
+}
+
+// buildConfigFile renders the KubeSchedulerConfiguration file
+func (b *KubeSchedulerBuilder) buildConfigFile() (*nodetasks.File, error) {
+	c := b.Cluster.Spec.KubeScheduler
+
+	config, _, err := kubeschedulerconfig.BuildConfig(c, b.kubernetesVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error building kube-scheduler config: %v", err)
+	}
+	config.ClientConnection = &kubeschedulerconfig.ClientConnectionConfiguration{
+		Kubeconfig: "/var/lib/kube-scheduler/kubeconfig",
+	}
+
+	manifest, err := kubeschedulerconfig.Marshal(config, c.ConfigOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering kube-scheduler config: %v", err)
+	}
+
+	return &nodetasks.File{
+		Path:     kubeSchedulerConfigFilePath,
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0400"),
+	}, nil
+}
+
 // buildPod is responsible for constructing the pod specification
 func (b *KubeSchedulerBuilder) buildPod() (*v1.Pod, error) {
 	c := b.Cluster.Spec.KubeScheduler
 
+	if b.usesConfigFile() {
+		// The config file lives in /var/lib/kube-scheduler, which is mounted below
+		_, remaining, err := kubeschedulerconfig.BuildConfig(c, b.kubernetesVersion)
+		if err != nil {
+			return nil, fmt.Errorf("error building kube-scheduler config: %v", err)
+		}
+		c = remaining
+	}
+
 	flags, err := flagbuilder.BuildFlagsList(c)
 	if err != nil {
 		return nil, fmt.Errorf("error building kube-scheduler flags: %v", err)
 	}
-	// Add kubeconfig flag
-	flags = append(flags, "--kubeconfig="+"/var/lib/kube-scheduler/kubeconfig")
+	if b.usesConfigFile() {
+		flags = append(flags, "--config="+kubeSchedulerConfigFilePath)
+	} else {
+		// Add kubeconfig flag
+		flags = append(flags, "--kubeconfig="+"/var/lib/kube-scheduler/kubeconfig")
+	}
 
 	if c.UsePolicyConfigMap != nil {
 		flags = append(flags, "--policy-configmap=scheduler-policy --policy-configmap-namespace=kube-system")
@@ -123,9 +178,14 @@ func (b *KubeSchedulerBuilder) buildPod() (*v1.Pod, error) {
 		},
 	}
 
+	image, err := b.RemapImage(c.Image)
+	if err != nil {
+		return nil, err
+	}
+
 	container := &v1.Container{
 		Name:  "kube-scheduler",
-		Image: c.Image,
+		Image: image,
 		Env:   proxy.GetProxyEnvVars(b.Cluster.Spec.EgressProxy),
 		LivenessProbe: &v1.Probe{
 			Handler: v1.Handler{
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/k8scodecs"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func Test_RunKubeSchedulerBuilder(t *testing.T) {
+	for _, basedir := range []string{"tests/kubescheduler/singleprofile", "tests/kubescheduler/profiles"} {
+		runKubeSchedulerBuilder(t, basedir)
+	}
+}
+
+func runKubeSchedulerBuilder(t *testing.T, basedir string) {
+	context := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	nodeUpModelContext, err := BuildNodeupModelContext(basedir)
+	if err != nil {
+		t.Fatalf("error loading model %q: %v", basedir, err)
+		return
+	}
+
+	builder := KubeSchedulerBuilder{NodeupModelContext: nodeUpModelContext}
+
+	pod, err := builder.buildPod()
+	if err != nil {
+		t.Fatalf("error from KubeSchedulerBuilder buildPod: %v", err)
+		return
+	}
+	manifest, err := k8scodecs.ToVersionedYaml(pod)
+	if err != nil {
+		t.Fatalf("error marshaling manifest to yaml: %v", err)
+		return
+	}
+	context.AddTask(&nodetasks.File{
+		Path:     "/etc/kubernetes/manifests/kube-scheduler.manifest",
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+	})
+
+	task, err := builder.buildConfigFile()
+	if err != nil {
+		t.Fatalf("error from KubeSchedulerBuilder buildConfigFile: %v", err)
+		return
+	}
+	context.AddTask(task)
+
+	testutils.ValidateTasks(t, basedir, context)
+}
@@ -19,10 +19,13 @@ package model
 import (
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
+	"io"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
@@ -35,6 +38,7 @@ import (
 	"k8s.io/kops/nodeup/pkg/distros"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/pkg/kubeletconfig"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/systemd"
 	"k8s.io/kops/upup/pkg/fi"
@@ -46,6 +50,9 @@ import (
 const (
 	// containerizedMounterHome is the path where we install the containerized mounter (on ContainerOS)
 	containerizedMounterHome = "/home/kubernetes/containerized_mounter"
+
+	// kubeletConfigFilePath is the path of the KubeletConfiguration file
+	kubeletConfigFilePath = "/var/lib/kubelet/config.yaml"
 )
 
 // KubeletBuilder installs kubelet
@@ -70,6 +77,14 @@ func (b *KubeletBuilder) Build(c *fi.ModelBuilderContext) error {
 		c.AddTask(t)
 	}
 
+	if b.usesKubeletConfigFile() {
+		t, err := b.buildKubeletConfigFile(kubeletConfig)
+		if err != nil {
+			return err
+		}
+		c.AddTask(t)
+	}
+
 	{
 		// @TODO Extract to common function?
 		assetName := "kubelet"
@@ -120,14 +135,14 @@ func (b *KubeletBuilder) Build(c *fi.ModelBuilderContext) error {
 				c.AddTask(task)
 			}
 		} else {
-			kubeconfig, err := b.BuildPKIKubeconfig("kubelet")
+			kubeconfig, err := b.buildKubeletKubeconfig()
 			if err != nil {
 				return err
 			}
 
 			c.AddTask(&nodetasks.File{
 				Path:     b.KubeletKubeConfig(),
-				Contents: fi.NewStringResource(kubeconfig),
+				Contents: kubeconfig,
 				Type:     nodetasks.FileType_File,
 				Mode:     s("0400"),
 			})
@@ -195,12 +210,26 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		}
 	}
 
+	// Settings which the config file supports are written there, the rest are passed as flags
+	flagsConfig := kubeletConfig
+	if b.usesKubeletConfigFile() {
+		_, remaining, err := kubeletconfig.BuildConfig(kubeletConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error building kubelet config file: %v", err)
+		}
+		flagsConfig = remaining
+	}
+
 	// TODO: Dump the separate file for flags - just complexity!
-	flags, err := flagbuilder.BuildFlags(kubeletConfig)
+	flags, err := flagbuilder.BuildFlags(flagsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error building kubelet flags: %v", err)
 	}
 
+	if b.usesKubeletConfigFile() {
+		flags += " --config=" + kubeletConfigFilePath
+	}
+
 	// Add cloud config file if needed
 	// We build this flag differently because it depends on CloudConfig, and to expose it directly
 	// would be a degree of freedom we don't have (we'd have to write the config to different files)
@@ -252,6 +281,33 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 	return t, nil
 }
 
+// usesKubeletConfigFile checks if the kubelet is configured with a KubeletConfiguration file, available from kubernetes 1.10
+func (b *KubeletBuilder) usesKubeletConfigFile() bool {
+	return b.IsKubernetesGTE("1.10")
+}
+
+// buildKubeletConfigFile renders the KubeletConfiguration file, including the configOverrides of the spec
+func (b *KubeletBuilder) buildKubeletConfigFile(kubeletConfig *kops.KubeletConfigSpec) (*nodetasks.File, error) {
+	config, _, err := kubeletconfig.BuildConfig(kubeletConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubelet config file: %v", err)
+	}
+
+	manifest, err := kubeletconfig.Marshal(config, kubeletConfig.ConfigOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &nodetasks.File{
+		Path:     kubeletConfigFilePath,
+		Contents: fi.NewBytesResource(manifest),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0600"),
+	}
+
+	return t, nil
+}
+
 // buildSystemdService is responsible for generating the kubelet systemd unit
 func (b *KubeletBuilder) buildSystemdService() *nodetasks.Service {
 	kubeletCommand := b.kubeletPath()
@@ -609,6 +665,115 @@ func (b *KubeletBuilder) buildKubeletConfigSpec() (*kops.KubeletConfigSpec, erro
 	return c, nil
 }
 
+// kubeletKubeconfig is the kubeconfig of the kubelet with a client certificate for this node.  The certificate is
+// signed or issued on the first Open rather than when the task is built, so that building the tasks again (as the
+// nodeup reconcile loop does) neither issues a new certificate nor changes the task.
+type kubeletKubeconfig struct {
+	// inputs describes the kubeconfig and certificate, as compared by the reconcile loop
+	inputs []byte
+	// generate builds the kubeconfig, signing or issuing the certificate
+	generate func() (string, error)
+
+	mutex    sync.Mutex
+	contents string
+}
+
+var _ fi.GeneratedResource = &kubeletKubeconfig{}
+
+// kubeletCertificateInputs are the inputs of a kubelet kubeconfig
+type kubeletCertificateInputs struct {
+	Kubeconfig   string             `json:"kubeconfig"`
+	CommonName   string             `json:"commonName"`
+	Organization []string           `json:"organization,omitempty"`
+	KeyUsage     x509.KeyUsage      `json:"keyUsage,omitempty"`
+	ExtKeyUsage  []x509.ExtKeyUsage `json:"extKeyUsage,omitempty"`
+	Issuer       string             `json:"issuer"`
+}
+
+// Open implements fi.Resource, generating the kubeconfig the first time it is called
+func (k *kubeletKubeconfig) Open() (io.Reader, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.contents == "" {
+		contents, err := k.generate()
+		if err != nil {
+			return nil, err
+		}
+		k.contents = contents
+	}
+
+	return strings.NewReader(k.contents), nil
+}
+
+// Inputs implements fi.GeneratedResource
+func (k *kubeletKubeconfig) Inputs() ([]byte, error) {
+	return k.inputs, nil
+}
+
+// newKubeletKubeconfig builds a kubeletKubeconfig from the certificate inputs, signed by the ca
+func (b *KubeletBuilder) newKubeletKubeconfig(ca []byte, inputs *kubeletCertificateInputs, generate func() (string, error)) (*kubeletKubeconfig, error) {
+	// the kubeconfig without credentials covers the server and the CA
+	kubeconfig, err := b.BuildKubeConfig("kubelet", ca, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	inputs.Kubeconfig = kubeconfig
+
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing kubelet certificate inputs: %v", err)
+	}
+
+	return &kubeletKubeconfig{inputs: data, generate: generate}, nil
+}
+
+// buildKubeletKubeconfig builds the kubeconfig for the kubelet, using a certificate issued for this node
+// if the keystore can issue one, otherwise the shared kubelet certificate
+func (b *KubeletBuilder) buildKubeletKubeconfig() (fi.Resource, error) {
+	issuer, ok := b.KeyStore.(fi.NodeCertificateIssuer)
+	if !ok || b.Cluster.Spec.Vault == nil || b.Cluster.Spec.Vault.NodeCertificateRole == "" {
+		kubeconfig, err := b.BuildPKIKubeconfig("kubelet")
+		if err != nil {
+			return nil, err
+		}
+		return fi.NewStringResource(kubeconfig), nil
+	}
+
+	nodeName, err := b.NodeName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting NodeName: %v", err)
+	}
+
+	ca, err := b.FindCert(fi.CertificateId_CA)
+	if err != nil {
+		return nil, err
+	}
+
+	commonName := fmt.Sprintf("system:node:%s", nodeName)
+	inputs := &kubeletCertificateInputs{
+		CommonName: commonName,
+		Issuer:     path.Join("vault", b.Cluster.Spec.Vault.PKIMount, b.Cluster.Spec.Vault.NodeCertificateRole),
+	}
+
+	return b.newKubeletKubeconfig(ca, inputs, func() (string, error) {
+		certificate, privateKey, err := issuer.IssueNodeCertificate(commonName)
+		if err != nil {
+			return "", fmt.Errorf("error issuing kubelet certificate: %v", err)
+		}
+		certBytes, err := certificate.AsBytes()
+		if err != nil {
+			return "", err
+		}
+		keyBytes, err := privateKey.AsBytes()
+		if err != nil {
+			return "", err
+		}
+
+		return b.BuildKubeConfig("kubelet", ca, certBytes, keyBytes)
+	})
+}
+
 // buildMasterKubeletKubeconfig builds a kubeconfig for the master kubelet, self-signing the kubelet cert
 func (b *KubeletBuilder) buildMasterKubeletKubeconfig() (*nodetasks.File, error) {
 	nodeName, err := b.NodeName()
@@ -632,9 +797,9 @@ func (b *KubeletBuilder) buildMasterKubeletKubeconfig() (*nodetasks.File, error)
 		return nil, fmt.Errorf("unable to find CA key %q in keystore", fi.CertificateId_CA)
 	}
 
-	privateKey, err := pki.GeneratePrivateKey()
+	caBytes, err := caCert.AsBytes()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get certificate authority data: %s", err)
 	}
 
 	template := &x509.Certificate{
@@ -660,35 +825,46 @@ func (b *KubeletBuilder) buildMasterKubeletKubeconfig() (*nodetasks.File, error)
 	// authenticate itself to the TLS server.
 	template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
 
-	t := time.Now().UnixNano()
-	template.SerialNumber = pki.BuildPKISerial(t)
-
-	certificate, err := pki.SignNewCertificate(privateKey, template, caCert.Certificate, caKey)
-	if err != nil {
-		return nil, fmt.Errorf("error signing certificate for master kubelet: %v", err)
+	inputs := &kubeletCertificateInputs{
+		CommonName:   template.Subject.CommonName,
+		Organization: template.Subject.Organization,
+		KeyUsage:     template.KeyUsage,
+		ExtKeyUsage:  template.ExtKeyUsage,
+		Issuer:       fi.CertificateId_CA,
 	}
 
-	caBytes, err := caCert.AsBytes()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get certificate authority data: %s", err)
-	}
-	certBytes, err := certificate.AsBytes()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get certificate data: %s", err)
-	}
-	keyBytes, err := privateKey.AsBytes()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get private key data: %s", err)
-	}
+	kubeconfig, err := b.newKubeletKubeconfig(caBytes, inputs, func() (string, error) {
+		privateKey, err := pki.GeneratePrivateKey()
+		if err != nil {
+			return "", err
+		}
+
+		t := time.Now().UnixNano()
+		template.SerialNumber = pki.BuildPKISerial(t)
+
+		certificate, err := pki.SignNewCertificate(privateKey, template, caCert.Certificate, caKey)
+		if err != nil {
+			return "", fmt.Errorf("error signing certificate for master kubelet: %v", err)
+		}
 
-	content, err := b.BuildKubeConfig("kubelet", caBytes, certBytes, keyBytes)
+		certBytes, err := certificate.AsBytes()
+		if err != nil {
+			return "", fmt.Errorf("failed to get certificate data: %s", err)
+		}
+		keyBytes, err := privateKey.AsBytes()
+		if err != nil {
+			return "", fmt.Errorf("failed to get private key data: %s", err)
+		}
+
+		return b.BuildKubeConfig("kubelet", caBytes, certBytes, keyBytes)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &nodetasks.File{
 		Path:     b.KubeletKubeConfig(),
-		Contents: fi.NewStringResource(content),
+		Contents: kubeconfig,
 		Type:     nodetasks.FileType_File,
 		Mode:     s("600"),
 	}, nil
@@ -17,13 +17,22 @@ limitations under the License.
 package model
 
 import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/blang/semver"
+
 	"k8s.io/kops/nodeup/pkg/distros"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/testutils"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 func Test_InstanceGroupKubeletMerge(t *testing.T) {
@@ -156,8 +165,12 @@ func stringSlicesEqual(exp, other []string) bool {
 }
 
 func Test_RunKubeletBuilder(t *testing.T) {
-	basedir := "tests/kubelet/featuregates"
+	for _, basedir := range []string{"tests/kubelet/featuregates", "tests/kubelet/configfile"} {
+		runKubeletBuilder(t, basedir)
+	}
+}
 
+func runKubeletBuilder(t *testing.T, basedir string) {
 	context := &fi.ModelBuilderContext{
 		Tasks: make(map[string]fi.Task),
 	}
@@ -182,6 +195,15 @@ func Test_RunKubeletBuilder(t *testing.T) {
 	}
 	context.AddTask(fileTask)
 
+	if builder.usesKubeletConfigFile() {
+		task, err := builder.buildKubeletConfigFile(kubeletConfig)
+		if err != nil {
+			t.Fatalf("error from KubeletBuilder buildKubeletConfigFile: %v", err)
+			return
+		}
+		context.AddTask(task)
+	}
+
 	{
 		task, err := builder.buildManifestDirectory(kubeletConfig)
 		if err != nil {
@@ -233,3 +255,132 @@ func BuildNodeupModelContext(basedir string) (*NodeupModelContext, error) {
 
 	return nodeUpModelContext, nil
 }
+
+// fakeNodeCertificateIssuer is a keystore that issues node certificates signed by the CA, counting the certificates issued
+type fakeNodeCertificateIssuer struct {
+	fi.CAStore
+	issued int
+}
+
+func (f *fakeNodeCertificateIssuer) IssueNodeCertificate(commonName string) (*pki.Certificate, *pki.PrivateKey, error) {
+	f.issued++
+
+	caCert, caKey, _, err := f.FindKeypair(fi.CertificateId_CA)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certificate, err := pki.SignNewCertificate(privateKey, template, caCert.Certificate, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certificate, privateKey, nil
+}
+
+func Test_KubeletKubeconfigIsStable(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.Spec.MasterInternalName = "api.internal.minimal.example.com"
+	cluster.Spec.Kubelet = &kops.KubeletConfigSpec{HostnameOverride: "node-1"}
+	cluster.Spec.MasterKubelet = &kops.KubeletConfigSpec{HostnameOverride: "master-1"}
+	cluster.Spec.Vault = &kops.VaultSpec{PKIMount: "pki", NodeCertificateRole: "kubelet"}
+
+	vfs.Context.ResetMemfsContext(true)
+	basedir, err := vfs.Context.BuildVfsPath("memfs://tests/pki")
+	if err != nil {
+		t.Fatalf("error building vfs path: %v", err)
+	}
+	caKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating CA key: %v", err)
+	}
+	caCert, err := pki.SignNewCertificate(caKey, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("error signing CA certificate: %v", err)
+	}
+	keyStore := &fakeNodeCertificateIssuer{CAStore: fi.NewVFSCAStore(cluster, basedir, false)}
+	if err := keyStore.StoreKeypair(fi.CertificateId_CA, caCert, caKey); err != nil {
+		t.Fatalf("error storing CA: %v", err)
+	}
+
+	for _, isMaster := range []bool{true, false} {
+		b := &KubeletBuilder{
+			NodeupModelContext: &NodeupModelContext{
+				Cluster:           cluster,
+				IsMaster:          isMaster,
+				KeyStore:          keyStore,
+				kubernetesVersion: semver.MustParse("1.15.3"),
+			},
+		}
+
+		build := func() *nodetasks.File {
+			if isMaster {
+				task, err := b.buildMasterKubeletKubeconfig()
+				if err != nil {
+					t.Fatalf("error building master kubelet kubeconfig: %v", err)
+				}
+				return task
+			}
+			kubeconfig, err := b.buildKubeletKubeconfig()
+			if err != nil {
+				t.Fatalf("error building kubelet kubeconfig: %v", err)
+			}
+			return &nodetasks.File{Path: b.KubeletKubeConfig(), Contents: kubeconfig, Type: nodetasks.FileType_File}
+		}
+
+		// what the nodeup reconcile loop compares between builds
+		state := func(task *nodetasks.File) string {
+			data, err := kops.ToRawYaml(task)
+			if err != nil {
+				t.Fatalf("error serializing task: %v", err)
+			}
+			generated, ok := task.Contents.(fi.GeneratedResource)
+			if !ok {
+				t.Fatalf("expected kubelet kubeconfig to be a generated resource, got %T", task.Contents)
+			}
+			inputs, err := generated.Inputs()
+			if err != nil {
+				t.Fatalf("error getting inputs: %v", err)
+			}
+			return string(data) + string(inputs)
+		}
+
+		first, second := build(), build()
+		if state(first) != state(second) {
+			t.Errorf("master=%v: expected consecutive builds to produce identical state\nfirst: %s\nsecond: %s", isMaster, state(first), state(second))
+		}
+		if keyStore.issued != 0 {
+			t.Errorf("master=%v: expected no certificate to be issued while building, got %d", isMaster, keyStore.issued)
+		}
+
+		contents, err := fi.ResourceAsBytes(first.Contents)
+		if err != nil {
+			t.Fatalf("master=%v: error generating kubeconfig: %v", isMaster, err)
+		}
+		if !strings.Contains(string(contents), "client-certificate-data") {
+			t.Errorf("master=%v: expected a client certificate in the kubeconfig, got:\n%s", isMaster, contents)
+		}
+		again, err := fi.ResourceAsBytes(first.Contents)
+		if err != nil {
+			t.Fatalf("master=%v: error generating kubeconfig: %v", isMaster, err)
+		}
+		if !bytes.Equal(contents, again) {
+			t.Errorf("master=%v: expected the kubeconfig to be generated once", isMaster)
+		}
+	}
+
+	if keyStore.issued != 1 {
+		t.Errorf("expected a single certificate to be issued for the node, got %d", keyStore.issued)
+	}
+}
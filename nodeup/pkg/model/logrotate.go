@@ -35,6 +35,12 @@ type LogrotateBuilder struct {
 }
 
 var _ fi.ModelBuilder = &LogrotateBuilder{}
+var _ InPlaceSafeBuilder = &LogrotateBuilder{}
+
+// InPlaceSafe implements InPlaceSafeBuilder
+func (b *LogrotateBuilder) InPlaceSafe() bool {
+	return true
+}
 
 // Build is responsible for configuring logrotate
 func (b *LogrotateBuilder) Build(c *fi.ModelBuilderContext) error {
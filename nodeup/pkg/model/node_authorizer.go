@@ -69,6 +69,10 @@ func (b *NodeAuthorizationBuilder) Build(c *fi.ModelBuilderContext) error {
 	// @check if the NodeAuthorizer provision the client service for nodes
 	if b.UseNodeAuthorizer() && !b.IsMaster {
 		na := b.Cluster.Spec.NodeAuthorization.NodeAuthorizer
+		image, err := b.RemapImage(na.Image)
+		if err != nil {
+			return err
+		}
 
 		klog.V(3).Infof("node authorization service is enabled, authorizer: %s", na.Authorizer)
 		klog.V(3).Infof("node authorization url: %s", na.NodeURL)
@@ -85,7 +89,7 @@ func (b *NodeAuthorizationBuilder) Build(c *fi.ModelBuilderContext) error {
 		man.Set("Service", "RemainAfterExit", "yes")
 		man.Set("Service", "EnvironmentFile", "/etc/environment")
 		man.Set("Service", "ExecStartPre", "/bin/mkdir -p /var/lib/kubelet")
-		man.Set("Service", "ExecStartPre", "/usr/bin/docker pull "+na.Image)
+		man.Set("Service", "ExecStartPre", "/usr/bin/docker pull "+image)
 		man.Set("Service", "ExecStartPre", "/bin/bash -c 'while [ ! -f "+clientCert+" ]; do sleep 5; done; sleep 5'")
 
 		interval := 10 * time.Second
@@ -105,7 +109,7 @@ func (b *NodeAuthorizationBuilder) Build(c *fi.ModelBuilderContext) error {
 			"--net=host",
 			"--volume=" + path.Dir(b.KubeletBootstrapKubeconfig()) + ":/var/lib/kubelet",
 			"--volume=" + filepath.Join(b.PathSrvKubernetes(), authorizerDir) + ":/config:ro",
-			na.Image,
+			image,
 			"client",
 			"--authorizer=" + na.Authorizer,
 			"--interval=" + interval.String(),
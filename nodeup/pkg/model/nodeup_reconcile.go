@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/kops/nodeup/pkg/bootstrap"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+
+	"k8s.io/klog"
+)
+
+// DefaultNodeupReconcileInterval is the time between two reconciliations, when nodeReconcile.interval is not set
+const DefaultNodeupReconcileInterval = 5 * time.Minute
+
+// NodeupReconcileServiceName is the name of the service that runs nodeup in reconcile mode
+const NodeupReconcileServiceName = "kops-reconcile.service"
+
+// NodeupReconcileBuilder installs the service that runs nodeup in reconcile mode
+type NodeupReconcileBuilder struct {
+	*NodeupModelContext
+
+	// Command is the command line that runs nodeup with the configuration of this node
+	Command []string
+}
+
+var _ fi.ModelBuilder = &NodeupReconcileBuilder{}
+
+// Build is responsible for creating the nodeup reconcile service
+func (b *NodeupReconcileBuilder) Build(c *fi.ModelBuilderContext) error {
+	spec := b.Cluster.Spec.NodeReconcile
+	if spec == nil || !fi.BoolValue(spec.Enabled) {
+		return nil
+	}
+
+	interval := DefaultNodeupReconcileInterval
+	if spec.Interval != nil {
+		interval = spec.Interval.Duration
+	}
+
+	var command []string
+	command = append(command, b.Command...)
+	command = append(command, "--reconcile", "--interval="+interval.String())
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Reconcile the node configuration (nodeup)")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	manifest.Set("Unit", "After", "kops-configuration.service")
+
+	if env := bootstrap.ServiceEnvironment(); env != "" {
+		manifest.Set("Service", "Environment", env)
+	}
+	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	manifest.Set("Service", "ExecStart", strings.Join(command, " "))
+	manifest.Set("Service", "Restart", "always")
+	manifest.Set("Service", "RestartSec", "30s")
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", NodeupReconcileServiceName, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       NodeupReconcileServiceName,
+		Definition: s(manifestString),
+	}
+	service.InitDefaults()
+
+	c.AddTask(service)
+
+	return nil
+}
@@ -291,7 +291,10 @@ func (t *ProtokubeBuilder) ProtokubeFlags(k8sVersion semver.Version) (*Protokube
 			return nil, fmt.Errorf("unable to remap container %q: %v", image, err)
 		}
 
-		image = remapped
+		image, err = t.RemapImage(remapped)
+		if err != nil {
+			return nil, err
+		}
 		f.EtcdImage = s(image)
 
 		// check if we are using tls and add the options to protokube
@@ -426,6 +429,19 @@ func (t *ProtokubeBuilder) ProtokubeEnvironmentVariables() string {
 		buffer.WriteString("'")
 		buffer.WriteString(os.Getenv("S3_ENDPOINT"))
 		buffer.WriteString("'")
+		buffer.WriteString(" ")
+	}
+
+	if os.Getenv("S3_BUCKET_ENDPOINTS") != "" {
+		buffer.WriteString(" ")
+		buffer.WriteString("-e S3_BUCKET_ENDPOINTS=")
+		buffer.WriteString("'")
+		buffer.WriteString(os.Getenv("S3_BUCKET_ENDPOINTS"))
+		buffer.WriteString("'")
+		buffer.WriteString(" ")
+	}
+
+	if os.Getenv("S3_ENDPOINT") != "" || os.Getenv("S3_BUCKET_ENDPOINTS") != "" {
 		buffer.WriteString(" -e S3_REGION=")
 		buffer.WriteString("'")
 		buffer.WriteString(os.Getenv("S3_REGION"))
@@ -486,6 +502,14 @@ func (t *ProtokubeBuilder) ProtokubeEnvironmentVariables() string {
 		buffer.WriteString(" ")
 	}
 
+	if os.Getenv("AZURE_ENVIRONMENT") != "" {
+		buffer.WriteString(" ")
+		buffer.WriteString("-e 'AZURE_ENVIRONMENT=")
+		buffer.WriteString(os.Getenv("AZURE_ENVIRONMENT"))
+		buffer.WriteString("'")
+		buffer.WriteString(" ")
+	}
+
 	t.writeProxyEnvVars(&buffer)
 
 	return buffer.String()
@@ -44,11 +44,6 @@ func (b *SecretBuilder) Build(c *fi.ModelBuilderContext) error {
 		return fmt.Errorf("KeyStore not set")
 	}
 
-	// @step: retrieve the platform ca
-	if err := b.BuildCertificateTask(c, fi.CertificateId_CA, "ca.crt"); err != nil {
-		return err
-	}
-
 	if b.SecretStore != nil {
 		key := "dockerconfig"
 		dockercfg, _ := b.SecretStore.Secret(key)
@@ -30,6 +30,12 @@ type SysctlBuilder struct {
 }
 
 var _ fi.ModelBuilder = &SysctlBuilder{}
+var _ InPlaceSafeBuilder = &SysctlBuilder{}
+
+// InPlaceSafe implements InPlaceSafeBuilder
+func (b *SysctlBuilder) InPlaceSafe() bool {
+	return true
+}
 
 // Build is responsible for configuring sysctl settings
 func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
@@ -129,6 +135,14 @@ func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
 		"net.ipv4.ip_forward=1",
 		"")
 
+	if params := b.sysctlParameters(); len(params) != 0 {
+		sysctls = append(sysctls,
+			"# Custom sysctl parameters from the cluster and instance group specs",
+			"")
+		sysctls = append(sysctls, params...)
+		sysctls = append(sysctls, "")
+	}
+
 	c.AddTask(&nodetasks.File{
 		Path:            "/etc/sysctl.d/99-k8s-general.conf",
 		Contents:        fi.NewStringResource(strings.Join(sysctls, "\n")),
@@ -138,3 +152,23 @@ func (b *SysctlBuilder) Build(c *fi.ModelBuilderContext) error {
 
 	return nil
 }
+
+// sysctlParameters merges the sysctl parameters of the cluster and the instance group,
+// the instance group value replacing the cluster value of a parameter
+func (b *SysctlBuilder) sysctlParameters() []string {
+	var params []string
+	index := make(map[string]int)
+	for _, spec := range [][]string{b.Cluster.Spec.SysctlParameters, b.InstanceGroup.Spec.SysctlParameters} {
+		for _, param := range spec {
+			param = strings.TrimSpace(param)
+			key := strings.TrimSpace(strings.SplitN(param, "=", 2)[0])
+			if i, found := index[key]; found {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// SystemdUnitsBuilder installs the systemd units of the cluster and instance group specs
+type SystemdUnitsBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SystemdUnitsBuilder{}
+var _ InPlaceSafeBuilder = &SystemdUnitsBuilder{}
+
+// InPlaceSafe implements InPlaceSafeBuilder
+func (b *SystemdUnitsBuilder) InPlaceSafe() bool {
+	return true
+}
+
+// Build is responsible for installing the systemd units; units of the instance group override the cluster ones with the same name
+func (b *SystemdUnitsBuilder) Build(c *fi.ModelBuilderContext) error {
+	names := make(map[string]bool)
+	for _, spec := range [][]kops.SystemdUnitSpec{b.InstanceGroup.Spec.SystemdUnits, b.Cluster.Spec.SystemdUnits} {
+		for i := range spec {
+			unit := &spec[i]
+			if len(unit.Roles) > 0 && !containsRole(b.InstanceGroup.Spec.Role, unit.Roles) {
+				continue
+			}
+
+			name := b.EnsureSystemdSuffix(unit.Name)
+			if names[name] {
+				klog.V(2).Infof("Skipping the systemd unit %s, overridden by the instance group", name)
+				continue
+			}
+			names[name] = true
+
+			if unit.Disabled {
+				enabled := false
+				managed := true
+				c.AddTask(&nodetasks.Service{
+					Name:        name,
+					ManageState: &managed,
+					Enabled:     &enabled,
+					Running:     &enabled,
+				})
+				continue
+			}
+
+			c.AddTask(b.buildService(name, unit))
+		}
+	}
+
+	return nil
+}
+
+// buildService renders the unit, and orders its task after the services it requires or is started after
+func (b *SystemdUnitsBuilder) buildService(name string, unit *kops.SystemdUnitSpec) *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Kops systemd unit "+name)
+
+	var after, before []string
+	for _, x := range unit.Requires {
+		x = b.EnsureSystemdSuffix(x)
+		manifest.Set("Unit", "Requires", x)
+		after = append(after, x)
+	}
+	for _, x := range unit.After {
+		x = b.EnsureSystemdSuffix(x)
+		manifest.Set("Unit", "After", x)
+		if !sets.NewString(after...).Has(x) {
+			after = append(after, x)
+		}
+	}
+	for _, x := range unit.Before {
+		x = b.EnsureSystemdSuffix(x)
+		manifest.Set("Unit", "Before", x)
+		before = append(before, x)
+	}
+
+	// The section of a unit is named after its type, e.g. [Timer] for a .timer unit
+	unitType := strings.TrimPrefix(path.Ext(name), ".")
+	content := unit.Manifest
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	manifest.SetSection(strings.Title(unitType), content)
+
+	switch unitType {
+	case "timer":
+		manifest.Set("Install", "WantedBy", "timers.target")
+	case "socket":
+		manifest.Set("Install", "WantedBy", "sockets.target")
+	default:
+		manifest.Set("Install", "WantedBy", "multi-user.target")
+	}
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", name, manifestString)
+
+	service := &nodetasks.Service{
+		Name:       name,
+		Definition: s(manifestString),
+		After:      after,
+		Before:     before,
+	}
+	service.InitDefaults()
+
+	return service
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// TrustedCABuilder writes the bundle of CA certificates trusted by the components of the node
+type TrustedCABuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &TrustedCABuilder{}
+var _ InPlaceSafeBuilder = &TrustedCABuilder{}
+
+// InPlaceSafe implements InPlaceSafeBuilder; the bundle is only ever extended or rotated, which running nodes can pick up
+func (b *TrustedCABuilder) InPlaceSafe() bool {
+	return true
+}
+
+// Build is responsible for writing the CA bundle
+func (b *TrustedCABuilder) Build(c *fi.ModelBuilderContext) error {
+	if b.KeyStore == nil {
+		return fmt.Errorf("KeyStore not set")
+	}
+
+	// @step: retrieve the platform ca
+	return b.BuildCertificateTask(c, fi.CertificateId_CA, "ca.crt")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/values"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// azureBlobPublicAclStrategy is the AclStrategy for blobs that must be publicly readable.
+// This strategy is used by custom file assets served from an Azure Blob Storage container.
+type azureBlobPublicAclStrategy struct {
+}
+
+var _ acls.ACLStrategy = &azureBlobPublicAclStrategy{}
+
+// GetACL requires public read access to the container when the path is inside the file repository.
+// Files in the state store are never made public.
+func (s *azureBlobPublicAclStrategy) GetACL(p vfs.Path, cluster *kops.Cluster) (vfs.ACL, error) {
+	if cluster.Spec.Assets == nil || cluster.Spec.Assets.FileRepository == nil {
+		return nil, nil
+	}
+
+	blobPath, ok := p.(*vfs.AzureBlobPath)
+	if !ok {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(blobPath.Path(), strings.TrimSuffix(cluster.Spec.ConfigStore, "/")+"/") {
+		klog.V(8).Infof("path %q is inside of config store %q, not requiring public access", blobPath, cluster.Spec.ConfigStore)
+		return nil, nil
+	}
+
+	fileRepository := values.StringValue(cluster.Spec.Assets.FileRepository)
+	u, err := url.Parse(fileRepository)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse: %q", fileRepository)
+	}
+
+	// The file repository is served over https from the blob endpoint of the storage account
+	account := strings.SplitN(u.Host, ".", 2)[0]
+	container := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)[0]
+	if !strings.Contains(u.Host, ".blob.") || account != blobPath.Account() || container != blobPath.Container() {
+		klog.V(8).Infof("path %q is not inside the file repository %q, not requiring public access", blobPath, fileRepository)
+		return nil, nil
+	}
+
+	return &vfs.AzureBlobAcl{
+		PublicAccess: "blob",
+	}, nil
+}
+
+func Register() {
+	acls.RegisterPlugin("k8s.io/kops/acl/azure", &azureBlobPublicAclStrategy{})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/values"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func Test_Strategy(t *testing.T) {
+	grid := []struct {
+		Path         vfs.Path
+		ExpectPublic bool
+	}{
+		{
+			Path:         vfs.NewAzureBlobPath(nil, "assets", "files", "kubernetes/kubelet"),
+			ExpectPublic: true,
+		},
+		{
+			// Other containers in the storage account are not the file repository
+			Path: vfs.NewAzureBlobPath(nil, "assets", "private", "kubernetes/kubelet"),
+		},
+		{
+			// The state store is never public
+			Path: vfs.NewAzureBlobPath(nil, "assets", "files", "cluster/config"),
+		},
+		{
+			Path: vfs.NewAzureBlobPath(nil, "other", "files", "kubernetes/kubelet"),
+		},
+	}
+
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			ConfigStore: "azureblob://assets/files/cluster",
+			Assets: &kops.Assets{
+				FileRepository: values.String("https://assets.blob.core.windows.net/files"),
+			},
+		},
+	}
+
+	s := &azureBlobPublicAclStrategy{}
+	for _, g := range grid {
+		acl, err := s.GetACL(g.Path, cluster)
+		if err != nil {
+			t.Errorf("error getting ACL for %s: %v", g.Path, err)
+			continue
+		}
+		if g.ExpectPublic {
+			azureBlobAcl, ok := acl.(*vfs.AzureBlobAcl)
+			if !ok || azureBlobAcl.PublicAccess != "blob" {
+				t.Errorf("expected public blob access for %s, got %v", g.Path, acl)
+			}
+		} else if acl != nil {
+			t.Errorf("expected no ACL for %s, got %v", g.Path, acl)
+		}
+	}
+}
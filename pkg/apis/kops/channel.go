@@ -86,18 +86,9 @@ type ChannelImageSpec struct {
 
 // LoadChannel loads a Channel object from the specified VFS location
 func LoadChannel(location string) (*Channel, error) {
-	u, err := url.Parse(location)
+	u, err := ResolveChannel(location)
 	if err != nil {
-		return nil, fmt.Errorf("invalid channel: %q", location)
-	}
-
-	if !u.IsAbs() {
-		base, err := url.Parse(DefaultChannelBase)
-		if err != nil {
-			return nil, fmt.Errorf("invalid base channel location: %q", DefaultChannelBase)
-		}
-		klog.V(4).Infof("resolving %q against default channel location %q", location, DefaultChannelBase)
-		u = base.ResolveReference(u)
+		return nil, err
 	}
 
 	resolved := u.String()
@@ -115,6 +106,25 @@ func LoadChannel(location string) (*Channel, error) {
 	return channel, nil
 }
 
+// ResolveChannel returns the URL of a channel, resolving names such as "stable" against DefaultChannelBase
+func ResolveChannel(location string) (*url.URL, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid channel: %q", location)
+	}
+
+	if !u.IsAbs() {
+		base, err := url.Parse(DefaultChannelBase)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base channel location: %q", DefaultChannelBase)
+		}
+		klog.V(4).Infof("resolving %q against default channel location %q", location, DefaultChannelBase)
+		u = base.ResolveReference(u)
+	}
+
+	return u, nil
+}
+
 // ParseChannel parses a Channel object
 func ParseChannel(channelBytes []byte) (*Channel, error) {
 	channel := &Channel{}
@@ -253,6 +263,7 @@ type CloudProviderID string
 const (
 	CloudProviderALI       CloudProviderID = "alicloud"
 	CloudProviderAWS       CloudProviderID = "aws"
+	CloudProviderAzure     CloudProviderID = "azure"
 	CloudProviderBareMetal CloudProviderID = "baremetal"
 	CloudProviderDO        CloudProviderID = "digitalocean"
 	CloudProviderGCE       CloudProviderID = "gce"
@@ -50,6 +50,8 @@ type ClusterSpec struct {
 	Channel string `json:"channel,omitempty"`
 	// Additional addons that should be installed on the cluster
 	Addons []AddonSpec `json:"addons,omitempty"`
+	// AddonOverrides are patches applied to the manifests of the built-in addons
+	AddonOverrides []AddonOverrideSpec `json:"addonOverrides,omitempty"`
 	// ConfigBase is the path where we store configuration for the cluster
 	// This might be different than the location where the cluster spec itself is stored,
 	// both because this must be accessible to the cluster,
@@ -81,10 +83,12 @@ type ClusterSpec struct {
 	// This is heavily weighted towards AWS for the time being, but should also be agnostic enough
 	// to port out to GCE later if needed
 	Topology *TopologySpec `json:"topology,omitempty"`
-	// SecretStore is the VFS path to where secrets are stored
+	// SecretStore is the VFS path to where secrets are stored, or a vault:// path to store them in HashiCorp Vault
 	SecretStore string `json:"secretStore,omitempty"`
-	// KeyStore is the VFS path to where SSL keys and certificates are stored
+	// KeyStore is the VFS path to where SSL keys and certificates are stored, or a vault:// path to store them in HashiCorp Vault
 	KeyStore string `json:"keyStore,omitempty"`
+	// Vault configures access to HashiCorp Vault, when it is used for the secret or key store
+	Vault *VaultSpec `json:"vault,omitempty"`
 	// ConfigStore is the VFS path to where the configuration (Cluster, InstanceGroups etc) is stored
 	ConfigStore string `json:"configStore,omitempty"`
 	// DNSZone is the DNS zone we should use when configuring DNS
@@ -146,9 +150,12 @@ type ClusterSpec struct {
 	MasterKubelet                  *KubeletConfigSpec            `json:"masterKubelet,omitempty"`
 	CloudConfig                    *CloudConfiguration           `json:"cloudConfig,omitempty"`
 	ExternalDNS                    *ExternalDNSConfig            `json:"externalDns,omitempty"`
+	ClusterAutoscaler              *ClusterAutoscalerConfig      `json:"clusterAutoscaler,omitempty"`
 
 	// Networking configuration
 	Networking *NetworkingSpec `json:"networking,omitempty"`
+	// Storage configures how persistent volumes are provisioned and attached
+	Storage *StorageSpec `json:"storage,omitempty"`
 	// API field controls how the API is exposed outside the cluster
 	API *AccessSpec `json:"api,omitempty"`
 	// Authentication field controls how the cluster is configured for authentication
@@ -157,16 +164,30 @@ type ClusterSpec struct {
 	Authorization *AuthorizationSpec `json:"authorization,omitempty"`
 	// NodeAuthorization defined the custom node authorization configuration
 	NodeAuthorization *NodeAuthorizationSpec `json:"nodeAuthorization,omitempty"`
+	// NodeReconcile runs nodeup as a service that periodically re-applies the configuration changes that are safe to apply in place
+	NodeReconcile *NodeReconcileSpec `json:"nodeReconcile,omitempty"`
+	// NodeHardening applies an OS hardening profile to all instances
+	NodeHardening *NodeHardeningSpec `json:"nodeHardening,omitempty"`
 	// Tags for AWS instance groups
 	CloudLabels map[string]string `json:"cloudLabels,omitempty"`
 	// Hooks for custom actions e.g. on first installation
 	Hooks []HookSpec `json:"hooks,omitempty"`
+	// SysctlParameters are kernel parameters set on all instances, e.g. net.ipv4.tcp_keepalive_time=200
+	SysctlParameters []string `json:"sysctlParameters,omitempty"`
+	// KernelModules are kernel modules loaded on the instances at boot
+	KernelModules []KernelModuleSpec `json:"kernelModules,omitempty"`
+	// SystemdUnits are systemd units installed on the instances
+	SystemdUnits []SystemdUnitSpec `json:"systemdUnits,omitempty"`
 	// Assets is alternative locations for files and containers; the API under construction, will remove this comment once this API is fully functional.
 	Assets *Assets `json:"assets,omitempty"`
 	// IAM field adds control over the IAM security policies applied to resources
 	IAM *IAMSpec `json:"iam,omitempty"`
 	// EncryptionConfig controls if encryption is enabled
 	EncryptionConfig *bool `json:"encryptionConfig,omitempty"`
+	// EncryptionAtRest generates the encryption provider config of the kube-apiserver from keys kept in the secret store, or from a KMS plugin
+	EncryptionAtRest *EncryptionAtRestSpec `json:"encryptionAtRest,omitempty"`
+	// SecretEncryption enables client-side encryption of secrets and private keys in the state store
+	SecretEncryption *SecretEncryptionSpec `json:"secretEncryption,omitempty"`
 	// DisableSubnetTags controls if subnets are tagged in AWS
 	DisableSubnetTags bool `json:"disableSubnetTags,omitempty"`
 	// Target allows for us to nest extra config for targets such as terraform
@@ -203,6 +224,42 @@ type NodeAuthorizerSpec struct {
 type AddonSpec struct {
 	// Manifest is a path to the manifest that defines the addon
 	Manifest string `json:"manifest,omitempty"`
+	// Name is the name of the addon, required when installing a chart
+	Name string `json:"name,omitempty"`
+	// Chart installs the addon from a Helm chart, instead of from a manifest
+	Chart *ChartSpec `json:"chart,omitempty"`
+}
+
+// ChartSpec identifies a Helm chart to install as an addon
+type ChartSpec struct {
+	// Repository is the URL of the chart repository.  If not set, Name is the location of a chart archive.
+	Repository string `json:"repository,omitempty"`
+	// Name is the name of the chart in the repository, or the location of a chart archive
+	Name string `json:"name,omitempty"`
+	// Version is the version of the chart
+	Version string `json:"version,omitempty"`
+	// Namespace is the namespace the chart is installed into, defaulting to kube-system
+	Namespace string `json:"namespace,omitempty"`
+	// ReleaseName is exposed to the chart templates as .Release.Name, defaulting to the name of the chart
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Values is a YAML document of values, overriding the default values of the chart
+	Values string `json:"values,omitempty"`
+}
+
+// AddonOverrideSpec is a patch applied to an object in the manifest of a built-in addon
+type AddonOverrideSpec struct {
+	// Addon is the name of the addon, for example coredns.addons.k8s.io
+	Addon string `json:"addon,omitempty"`
+	// Kind is the kind of the object to patch
+	Kind string `json:"kind,omitempty"`
+	// Namespace is the namespace of the object to patch; if not set, the object is matched in any namespace
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object to patch
+	Name string `json:"name,omitempty"`
+	// Type is the type of patch: strategic (the default) for a strategic merge patch, or json for a JSON6902 patch
+	Type string `json:"type,omitempty"`
+	// Patch is the patch, in YAML or JSON
+	Patch string `json:"patch,omitempty"`
 }
 
 // FileAssetSpec defines the structure for a file asset
@@ -227,6 +284,13 @@ type Assets struct {
 	FileRepository *string `json:"fileRepository,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a docker registry
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// PinImageDigests resolves the container images kops deploys to their sha256 digests when updating the cluster,
+	// and makes nodes refuse to run images which are not pinned to a digest
+	PinImageDigests *bool `json:"pinImageDigests,omitempty"`
+	// FileSignatureKey is a PEM encoded RSA or ECDSA public key. When set, nodes verify the detached signature
+	// (the file with a .sig suffix) of every file asset they download against it. The nodeup binary, which the
+	// bootstrap script downloads, is only verified against its hash.
+	FileSignatureKey *string `json:"fileSignatureKey,omitempty"`
 }
 
 // IAMSpec adds control over the IAM security policies applied to resources
@@ -257,6 +321,34 @@ type HookSpec struct {
 	UseRawManifest bool `json:"useRawManifest,omitempty"`
 }
 
+// KernelModuleSpec defines a kernel module loaded at boot
+type KernelModuleSpec struct {
+	// Name is the name of the module, e.g. br_netfilter
+	Name string `json:"name,omitempty"`
+	// Roles is an optional list of roles the module is loaded on, defaults to all
+	Roles []InstanceGroupRole `json:"roles,omitempty"`
+	// Parameters are the options the module is loaded with, e.g. hashsize=32768
+	Parameters []string `json:"parameters,omitempty"`
+}
+
+// SystemdUnitSpec defines a systemd unit installed and started by nodeup
+type SystemdUnitSpec struct {
+	// Name is the name of the unit, including its type, e.g. log-shipper.service
+	Name string `json:"name,omitempty"`
+	// Disabled stops and disables the unit, e.g. to turn off a cluster unit on an instance group
+	Disabled bool `json:"disabled,omitempty"`
+	// Roles is an optional list of roles the unit is installed on, defaults to all
+	Roles []InstanceGroupRole `json:"roles,omitempty"`
+	// Requires is a series of systemd units the unit requires
+	Requires []string `json:"requires,omitempty"`
+	// After is a series of systemd units the unit is started after, e.g. docker.service or kubelet.service
+	After []string `json:"after,omitempty"`
+	// Before is a series of systemd units the unit is started before
+	Before []string `json:"before,omitempty"`
+	// Manifest is the content of the section of the unit type, e.g. the [Service] section of a service
+	Manifest string `json:"manifest,omitempty"`
+}
+
 // ExecContainerAction defines an hood action
 type ExecContainerAction struct {
 	// Image is the docker image
@@ -386,6 +478,50 @@ type ExternalDNSConfig struct {
 	WatchNamespace string `json:"watchNamespace,omitempty"`
 }
 
+// ClusterAutoscalerConfig is the configuration of the cluster-autoscaler addon.
+// The node groups are the InstanceGroups whose MinSize and MaxSize differ.
+type ClusterAutoscalerConfig struct {
+	// Enabled installs the cluster-autoscaler addon
+	Enabled *bool `json:"enabled,omitempty"`
+	// Expander is the strategy for choosing the node group to scale up: random, most-pods, least-waste or priority. Default: random
+	Expander *string `json:"expander,omitempty"`
+	// BalanceSimilarNodeGroups keeps the sizes of node groups with the same instance type and labels balanced. Default: false
+	BalanceSimilarNodeGroups *bool `json:"balanceSimilarNodeGroups,omitempty"`
+	// ScaleDownEnabled allows the cluster-autoscaler to remove nodes. Default: true
+	ScaleDownEnabled *bool `json:"scaleDownEnabled,omitempty"`
+	// ScaleDownUtilizationThreshold is the fraction of a node's capacity requested by pods below which the node may be removed. Default: 0.5
+	ScaleDownUtilizationThreshold *string `json:"scaleDownUtilizationThreshold,omitempty"`
+	// ScaleDownUnneededTime is how long a node must be unneeded before it is removed. Default: 10m
+	ScaleDownUnneededTime *metav1.Duration `json:"scaleDownUnneededTime,omitempty"`
+	// ScaleDownDelayAfterAdd is how long after a scale up before nodes may be removed. Default: 10m
+	ScaleDownDelayAfterAdd *metav1.Duration `json:"scaleDownDelayAfterAdd,omitempty"`
+	// SkipNodesWithLocalStorage prevents the removal of nodes running pods with local storage. Default: true
+	SkipNodesWithLocalStorage *bool `json:"skipNodesWithLocalStorage,omitempty"`
+	// SkipNodesWithSystemPods prevents the removal of nodes running kube-system pods, other than those of daemonsets. Default: true
+	SkipNodesWithSystemPods *bool `json:"skipNodesWithSystemPods,omitempty"`
+	// Image is the cluster-autoscaler image. Default: the release matching the kubernetes version
+	Image *string `json:"image,omitempty"`
+	// MemoryRequest of the cluster-autoscaler container. Default: 300Mi
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+	// CPURequest of the cluster-autoscaler container. Default: 100m
+	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
+}
+
+// StorageSpec configures how persistent volumes are provisioned and attached
+type StorageSpec struct {
+	// CSI installs the Container Storage Interface driver of the cloudprovider
+	CSI *CSISpec `json:"csi,omitempty"`
+}
+
+// CSISpec is the configuration of the CSI driver addon.
+// On AWS this is the EBS CSI driver, which provisions volumes through the ebs-csi StorageClass.
+type CSISpec struct {
+	// Enabled installs the CSI driver addon
+	Enabled *bool `json:"enabled,omitempty"`
+	// Version is the version of the CSI driver. Default: v0.4.0 on AWS
+	Version *string `json:"version,omitempty"`
+}
+
 // EtcdProviderType describes etcd cluster provisioning types (Standalone, Manager)
 type EtcdProviderType string
 
@@ -505,6 +641,126 @@ type HTTPProxy struct {
 	// Password string `json:"password,omitempty"`
 }
 
+// SecretEncryptionSpec configures client-side envelope encryption of secrets and private keys in the state store.
+// Exactly one key provider must be set, unless encryption is being disabled and only PreviousKeys are set.
+type SecretEncryptionSpec struct {
+	// AWSKMS wraps data keys with an AWS KMS key
+	AWSKMS *AWSKMSSecretEncryptionSpec `json:"awsKMS,omitempty"`
+	// GCPKMS wraps data keys with a Google Cloud KMS key
+	GCPKMS *GCPKMSSecretEncryptionSpec `json:"gcpKMS,omitempty"`
+	// VaultTransit wraps data keys with a HashiCorp Vault transit key
+	VaultTransit *VaultTransitSecretEncryptionSpec `json:"vaultTransit,omitempty"`
+	// Local wraps data keys with a symmetric key read from a local file; intended for testing
+	Local *LocalSecretEncryptionSpec `json:"local,omitempty"`
+	// PreviousKeys are the key providers that data may still be encrypted with after the key was changed.
+	// They are only used for decrypting, and can be removed once kops toolbox reencrypt has rewritten the data.
+	PreviousKeys []SecretEncryptionSpec `json:"previousKeys,omitempty"`
+}
+
+// AWSKMSSecretEncryptionSpec configures an AWS KMS key for secret encryption
+type AWSKMSSecretEncryptionSpec struct {
+	// KeyID is the ARN of the KMS key; an alias or key id requires Region to be set
+	KeyID string `json:"keyID,omitempty"`
+	// Region is the region of the KMS key, if KeyID is not an ARN
+	Region string `json:"region,omitempty"`
+}
+
+// GCPKMSSecretEncryptionSpec configures a Google Cloud KMS key for secret encryption
+type GCPKMSSecretEncryptionSpec struct {
+	// KeyName is the resource name of the key: projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>
+	KeyName string `json:"keyName,omitempty"`
+}
+
+// VaultTransitSecretEncryptionSpec configures a Vault transit key for secret encryption.
+// The token is read from the VAULT_TOKEN environment variable.
+type VaultTransitSecretEncryptionSpec struct {
+	// Address is the URL of the Vault server
+	Address string `json:"address,omitempty"`
+	// MountPath is the path the transit secrets engine is mounted at; defaults to transit
+	MountPath string `json:"mountPath,omitempty"`
+	// KeyName is the name of the transit key
+	KeyName string `json:"keyName,omitempty"`
+}
+
+// LocalSecretEncryptionSpec configures a local key for secret encryption.
+// The base64 encoded 32 byte key is read from the file named by the KOPS_SECRET_ENCRYPTION_KEY_FILE environment variable.
+type LocalSecretEncryptionSpec struct {
+}
+
+// EncryptionAtRestSpec configures encryption of resources stored in etcd by the kube-apiserver
+type EncryptionAtRestSpec struct {
+	// Provider is the encryption provider: aescbc, secretbox or kms. Default: aescbc
+	Provider string `json:"provider,omitempty"`
+	// Resources is the list of resources to encrypt. Default: secrets
+	Resources []string `json:"resources,omitempty"`
+	// KMS configures the AWS KMS provider plugin, which runs as a static pod on the masters
+	KMS *KMSEncryptionAtRestSpec `json:"kms,omitempty"`
+}
+
+// KMSEncryptionAtRestSpec configures the aws-encryption-provider KMS plugin
+type KMSEncryptionAtRestSpec struct {
+	// KeyID is the ARN of the KMS key used to wrap the data encryption keys
+	KeyID string `json:"keyID,omitempty"`
+	// Region is the region of the KMS key. Default: the region of the cluster
+	Region string `json:"region,omitempty"`
+	// Image is the aws-encryption-provider image
+	Image string `json:"image,omitempty"`
+	// CacheSize is the number of data encryption keys the kube-apiserver keeps in memory. Default: 1000
+	CacheSize *int32 `json:"cacheSize,omitempty"`
+}
+
+// VaultSpec configures access to HashiCorp Vault for the secret and key stores.
+// Secrets and keysets are kept in a KV version 2 secrets engine, named by the first path segment of the vault:// store path.
+type VaultSpec struct {
+	// Namespace is the Vault Enterprise namespace to use
+	Namespace string `json:"namespace,omitempty"`
+	// AuthMethod is the cloud auth method that instances use to log in to Vault: aws or gcp
+	AuthMethod string `json:"authMethod,omitempty"`
+	// AuthMount is the path the auth method is mounted at; defaults to the name of the auth method
+	AuthMount string `json:"authMount,omitempty"`
+	// MasterRole is the Vault role that masters log in as
+	MasterRole string `json:"masterRole,omitempty"`
+	// NodeRole is the Vault role that nodes log in as
+	NodeRole string `json:"nodeRole,omitempty"`
+	// PKIMount is the path of a PKI secrets engine; when set the cluster CA is loaded into it so that Vault can issue certificates
+	PKIMount string `json:"pkiMount,omitempty"`
+	// NodeCertificateRole is the PKI role used to issue per-node kubelet client certificates, instead of the shared kubelet certificate
+	NodeCertificateRole string `json:"nodeCertificateRole,omitempty"`
+}
+
+// NodeReconcileSpec configures the nodeup reconcile service.
+// Changes to file assets, hooks, sysctls and log rotation are applied to running nodes; any other change is recorded
+// in the kops.k8s.io/requires-replacement annotation of the node, so that kops rolling-update replaces it.
+type NodeReconcileSpec struct {
+	// Enabled installs the reconcile service on all nodes
+	Enabled *bool `json:"enabled,omitempty"`
+	// Interval is the time between two reconciliations; defaults to 5m
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// NodeHardeningSpec configures the OS hardening of the instances.
+// The settings of a named profile can be extended or overridden; instance group settings override cluster settings.
+type NodeHardeningSpec struct {
+	// Profile is the named hardening profile to apply: cis-level1 or cis-level2
+	Profile string `json:"profile,omitempty"`
+	// Sysctls are kernel parameters to set, overriding those of the profile
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// BlacklistedModules are kernel modules that are prevented from loading, in addition to those of the profile
+	BlacklistedModules []string `json:"blacklistedModules,omitempty"`
+	// AllowedModules are kernel modules blacklisted by the profile that are allowed to load
+	AllowedModules []string `json:"allowedModules,omitempty"`
+	// AuditRules are auditd rules to load, in addition to those of the profile
+	AuditRules []string `json:"auditRules,omitempty"`
+	// SSHD are sshd_config settings, overriding those of the profile
+	SSHD map[string]string `json:"sshd,omitempty"`
+	// DisabledServices are systemd units to stop and disable, in addition to those of the profile
+	DisabledServices []string `json:"disabledServices,omitempty"`
+	// EnabledServices are systemd units disabled by the profile that are left alone
+	EnabledServices []string `json:"enabledServices,omitempty"`
+	// FileModes are the most permissive modes of the files written by nodeup below a path, e.g. /etc/kubernetes: "0600", overriding those of the profile
+	FileModes map[string]string `json:"fileModes,omitempty"`
+}
+
 // TargetSpec allows for specifying target config in an extensible way
 type TargetSpec struct {
 	Terraform *TerraformSpec `json:"terraform,omitempty"`
@@ -28,6 +28,9 @@ const (
 	LabelClusterName = "kops.k8s.io/cluster"
 	// NodeLabelInstanceGroup is a node label set to the name of the instance group
 	NodeLabelInstanceGroup = "kops.k8s.io/instancegroup"
+	// NodeAnnotationRequiresReplacement is a node annotation set by nodeup in reconcile mode, listing the
+	// changes to the node configuration that cannot be applied in place
+	NodeAnnotationRequiresReplacement = "kops.k8s.io/requires-replacement"
 	// Deprecated - use the new labels & taints node-role.kubernetes.io/master and node-role.kubernetes.io/node
 	TaintNoScheduleMaster15 = "dedicated=master:NoSchedule"
 )
@@ -72,6 +75,16 @@ var AllInstanceGroupRoles = []InstanceGroupRole{
 	InstanceGroupRoleBastion,
 }
 
+const (
+	// BootstrapFormatScript bootstraps instances with a shell script, run by cloud-init
+	BootstrapFormatScript = "script"
+	// BootstrapFormatIgnition bootstraps instances with an Ignition config
+	BootstrapFormatIgnition = "ignition"
+
+	// UserDataTypeIgnition is the type of additional user-data holding an Ignition config, merged into the Ignition config of the instance
+	UserDataTypeIgnition = "application/vnd.coreos.ignition+json"
+)
+
 const (
 	// BtfsFilesystem indicates a btfs filesystem
 	BtfsFilesystem = "btfs"
@@ -117,6 +130,12 @@ type InstanceGroupSpec struct {
 	Zones []string `json:"zones,omitempty"`
 	// Hooks is a list of hooks for this instanceGroup, note: these can override the cluster wide ones if required
 	Hooks []HookSpec `json:"hooks,omitempty"`
+	// SysctlParameters are kernel parameters set on the instances, overriding the cluster ones with the same name
+	SysctlParameters []string `json:"sysctlParameters,omitempty"`
+	// KernelModules are kernel modules loaded on the instances at boot, overriding the cluster ones with the same name
+	KernelModules []KernelModuleSpec `json:"kernelModules,omitempty"`
+	// SystemdUnits are systemd units installed on the instances, overriding the cluster ones with the same name
+	SystemdUnits []SystemdUnitSpec `json:"systemdUnits,omitempty"`
 	// MaxPrice indicates this is a spot-pricing group, with the specified value as our max-price bid
 	MaxPrice *string `json:"maxPrice,omitempty"`
 	// AssociatePublicIP is true if we want instances to have a public IP
@@ -139,6 +158,10 @@ type InstanceGroupSpec struct {
 	MixedInstancesPolicy *MixedInstancesPolicySpec `json:"mixedInstancesPolicy,omitempty"`
 	// AdditionalUserData is any additional user-data to be passed to the host
 	AdditionalUserData []UserData `json:"additionalUserData,omitempty"`
+	// BootstrapFormat is the format of the user-data that bootstraps the instances: script (the default), or ignition for Flatcar and CoreOS
+	BootstrapFormat string `json:"bootstrapFormat,omitempty"`
+	// NodeHardening applies an OS hardening profile to the instances, overriding the cluster nodeHardening
+	NodeHardening *NodeHardeningSpec `json:"nodeHardening,omitempty"`
 	// SuspendProcesses disables the listed Scaling Policies
 	SuspendProcesses []string `json:"suspendProcesses,omitempty"`
 	// ExternalLoadBalancers define loadbalancers that should be attached to the instancegroup
@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1alpha1
 
-import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 // KubeletConfigSpec defines the kubelet configuration
 type KubeletConfigSpec struct {
@@ -193,6 +196,9 @@ type KubeletConfigSpec struct {
 	RegistryPullQPS *int32 `json:"registryPullQPS,omitempty" flag:"registry-qps"`
 	//RegistryBurst Maximum size of a bursty pulls, temporarily allows pulls to burst to this number, while still not exceeding registry-qps. Only used if --registry-qps > 0 (default 10)
 	RegistryBurst *int32 `json:"registryBurst,omitempty" flag:"registry-burst"`
+	// ConfigOverrides are raw KubeletConfiguration fields merged into the kubelet config file, for settings kops does not model.
+	// The overrides of an instance group replace those of the cluster.
+	ConfigOverrides *runtime.RawExtension `json:"configOverrides,omitempty" flag:"-"`
 }
 
 // KubeProxyConfig defines the configuration for a proxy
@@ -237,6 +243,16 @@ type KubeProxyConfig struct {
 	ConntrackMaxPerCore *int32 `json:"conntrackMaxPerCore,omitempty" flag:"conntrack-max-per-core"`
 	// Minimum number of conntrack entries to allocate, regardless of conntrack-max-per-core
 	ConntrackMin *int32 `json:"conntrackMin,omitempty" flag:"conntrack-min"`
+	// IPVSStrictARP configures arp_ignore and arp_announce to avoid answering ARP queries from the kube-ipvs0 interface
+	IPVSStrictARP *bool `json:"ipvsStrictArp,omitempty" flag:"-"`
+	// IPVSTCPTimeout is the timeout for idle IPVS TCP sessions
+	IPVSTCPTimeout *metav1.Duration `json:"ipvsTcpTimeout,omitempty" flag:"-"`
+	// IPVSTCPFinTimeout is the timeout for IPVS TCP sessions after receiving a FIN
+	IPVSTCPFinTimeout *metav1.Duration `json:"ipvsTcpFinTimeout,omitempty" flag:"-"`
+	// IPVSUDPTimeout is the timeout for IPVS UDP packets
+	IPVSUDPTimeout *metav1.Duration `json:"ipvsUdpTimeout,omitempty" flag:"-"`
+	// ConfigOverrides are raw KubeProxyConfiguration fields merged into the kube-proxy config file, for settings kops does not model.
+	ConfigOverrides *runtime.RawExtension `json:"configOverrides,omitempty" flag:"-"`
 }
 
 // KubeAPIServerConfig defines the configuration for the kube api
@@ -379,6 +395,10 @@ type KubeAPIServerConfig struct {
 	AuditWebhookInitialBackoff *metav1.Duration `json:"auditWebhookInitialBackoff,omitempty" flag:"audit-webhook-initial-backoff"`
 	// AuditWebhookMode is Strategy for sending audit events. Blocking indicates sending events should block server responses. Batch causes the backend to buffer and write events asynchronously. Known modes are batch,blocking. (default "batch")
 	AuditWebhookMode string `json:"auditWebhookMode,omitempty" flag:"audit-webhook-mode"`
+	// AuditPolicy is an inline audit.k8s.io Policy, which is written to the masters and used as the AuditPolicyFile
+	AuditPolicy *runtime.RawExtension `json:"auditPolicy,omitempty" flag:"-"`
+	// AuditWebhook configures the audit webhook backend, for which a kubeconfig is written to the masters and used as the AuditWebhookConfigFile
+	AuditWebhook *AuditWebhookSpec `json:"auditWebhook,omitempty" flag:"-"`
 	// File with webhook configuration for token authentication in kubeconfig format. The API server will query the remote service to determine authentication for bearer tokens.
 	AuthenticationTokenWebhookConfigFile *string `json:"authenticationTokenWebhookConfigFile,omitempty" flag:"authentication-token-webhook-config-file"`
 	// The duration to cache responses from the webhook token authenticator. Default is 2m. (default 2m0s)
@@ -395,6 +415,8 @@ type KubeAPIServerConfig struct {
 	AuthorizationRBACSuperUser *string `json:"authorizationRbacSuperUser,omitempty" flag:"authorization-rbac-super-user"`
 	// ExperimentalEncryptionProviderConfig enables encryption at rest for secrets.
 	ExperimentalEncryptionProviderConfig *string `json:"experimentalEncryptionProviderConfig,omitempty" flag:"experimental-encryption-provider-config"`
+	// EncryptionProviderConfig enables encryption at rest for secrets; it replaces ExperimentalEncryptionProviderConfig from kubernetes 1.13.
+	EncryptionProviderConfig *string `json:"encryptionProviderConfig,omitempty" flag:"encryption-provider-config"`
 
 	// List of request headers to inspect for usernames. X-Remote-User is common.
 	RequestheaderUsernameHeaders []string `json:"requestheaderUsernameHeaders,omitempty" flag:"requestheader-username-headers"`
@@ -518,6 +540,17 @@ type KubeControllerManagerConfig struct {
 	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty" flag:"kube-api-burst"`
 }
 
+// AuditWebhookSpec is the configuration of the audit webhook backend of the kube-apiserver
+type AuditWebhookSpec struct {
+	// Server is the https URL the audit events are sent to
+	Server string `json:"server,omitempty"`
+	// CACertificate is the PEM encoded certificate authority used to verify the server, instead of the system roots
+	CACertificate string `json:"caCertificate,omitempty"`
+	// TokenSecret is the name of the secret in the state store holding the bearer token presented to the server,
+	// as created by kops create secret auditwebhooktoken
+	TokenSecret string `json:"tokenSecret,omitempty"`
+}
+
 // CloudControllerManagerConfig is the configuration of the cloud controller
 type CloudControllerManagerConfig struct {
 	// Master is the url for the kube api master.
@@ -559,6 +592,61 @@ type KubeSchedulerConfig struct {
 	UsePolicyConfigMap *bool `json:"usePolicyConfigMap,omitempty"`
 	// FeatureGates is set of key=value pairs that describe feature gates for alpha/experimental features.
 	FeatureGates map[string]string `json:"featureGates,omitempty" flag:"feature-gates"`
+	// Profiles are the scheduling profiles; pods select a profile by its schedulerName.
+	// Kubernetes versions before 1.18 support a single profile.
+	Profiles []SchedulerProfile `json:"profiles,omitempty" flag:"-"`
+	// ConfigOverrides are raw KubeSchedulerConfiguration fields merged into the kube-scheduler config file, for settings kops does not model.
+	ConfigOverrides *runtime.RawExtension `json:"configOverrides,omitempty" flag:"-"`
+}
+
+// SchedulerProfile is a scheduling profile of the kube-scheduler
+type SchedulerProfile struct {
+	// SchedulerName is the name pods use to select this profile, defaults to default-scheduler
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// Plugins enables and disables the scheduler plugins of each extension point
+	Plugins *SchedulerPlugins `json:"plugins,omitempty"`
+	// PluginConfig holds the arguments of the plugins
+	PluginConfig []SchedulerPluginConfig `json:"pluginConfig,omitempty"`
+}
+
+// SchedulerPlugins holds the plugins of each extension point of the scheduling framework
+type SchedulerPlugins struct {
+	QueueSort  *SchedulerPluginSet `json:"queueSort,omitempty"`
+	PreFilter  *SchedulerPluginSet `json:"preFilter,omitempty"`
+	Filter     *SchedulerPluginSet `json:"filter,omitempty"`
+	PostFilter *SchedulerPluginSet `json:"postFilter,omitempty"`
+	PreScore   *SchedulerPluginSet `json:"preScore,omitempty"`
+	Score      *SchedulerPluginSet `json:"score,omitempty"`
+	Reserve    *SchedulerPluginSet `json:"reserve,omitempty"`
+	Permit     *SchedulerPluginSet `json:"permit,omitempty"`
+	PreBind    *SchedulerPluginSet `json:"preBind,omitempty"`
+	Bind       *SchedulerPluginSet `json:"bind,omitempty"`
+	PostBind   *SchedulerPluginSet `json:"postBind,omitempty"`
+	Unreserve  *SchedulerPluginSet `json:"unreserve,omitempty"`
+}
+
+// SchedulerPluginSet lists the plugins enabled and disabled at an extension point
+type SchedulerPluginSet struct {
+	// Enabled are the plugins to run in addition to the default plugins
+	Enabled []SchedulerPlugin `json:"enabled,omitempty"`
+	// Disabled are default plugins to turn off, "*" disables them all
+	Disabled []SchedulerPlugin `json:"disabled,omitempty"`
+}
+
+// SchedulerPlugin names a scheduler plugin
+type SchedulerPlugin struct {
+	// Name is the name of the plugin
+	Name string `json:"name"`
+	// Weight is the weight of a score plugin
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// SchedulerPluginConfig holds the arguments of a scheduler plugin
+type SchedulerPluginConfig struct {
+	// Name is the name of the plugin
+	Name string `json:"name"`
+	// Args are the plugin arguments, as expected by the plugin
+	Args *runtime.RawExtension `json:"args,omitempty"`
 }
 
 // LeaderElectionConfiguration defines the configuration of leader election
@@ -610,6 +698,21 @@ type OpenstackConfiguration struct {
 	BlockStorage *OpenstackBlockStorageConfig `json:"blockStorage,omitempty"`
 }
 
+// AzureConfiguration defines cloud config elements for the azure cloud provider
+type AzureConfiguration struct {
+	// SubscriptionID is the ID of the Azure subscription the cluster is created in
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+	// TenantID is the ID of the Azure Active Directory tenant of the subscription
+	TenantID string `json:"tenantId,omitempty"`
+	// ResourceGroupName is the name of an existing resource group to create the cluster resources in.
+	// If unset, a resource group named after the cluster is created, and deleted with the cluster.
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+	// RouteTableName is the name of the route table used for pod routing
+	RouteTableName string `json:"routeTableName,omitempty"`
+	// AdminUser is the name of the admin user created on the VMs
+	AdminUser string `json:"adminUser,omitempty"`
+}
+
 // CloudConfiguration defines the cloud provider configuration
 type CloudConfiguration struct {
 	// GCE cloud-config options
@@ -632,6 +735,8 @@ type CloudConfiguration struct {
 	SpotinstOrientation *string `json:"spotinstOrientation,omitempty"`
 	// Openstack cloud-config options
 	Openstack *OpenstackConfiguration `json:"openstack,omitempty"`
+	// Azure cloud-config options
+	Azure *AzureConfiguration `json:"azure,omitempty"`
 }
 
 // HasAdmissionController checks if a specific admission controller is enabled
@@ -33,6 +33,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AWSKMSSecretEncryptionSpec)(nil), (*kops.AWSKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(a.(*AWSKMSSecretEncryptionSpec), b.(*kops.AWSKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AWSKMSSecretEncryptionSpec)(nil), (*AWSKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha1_AWSKMSSecretEncryptionSpec(a.(*kops.AWSKMSSecretEncryptionSpec), b.(*AWSKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AccessSpec)(nil), (*kops.AccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_AccessSpec_To_kops_AccessSpec(a.(*AccessSpec), b.(*kops.AccessSpec), scope)
 	}); err != nil {
@@ -43,6 +53,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AddonOverrideSpec)(nil), (*kops.AddonOverrideSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AddonOverrideSpec_To_kops_AddonOverrideSpec(a.(*AddonOverrideSpec), b.(*kops.AddonOverrideSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AddonOverrideSpec)(nil), (*AddonOverrideSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AddonOverrideSpec_To_v1alpha1_AddonOverrideSpec(a.(*kops.AddonOverrideSpec), b.(*AddonOverrideSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AddonSpec)(nil), (*kops.AddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_AddonSpec_To_kops_AddonSpec(a.(*AddonSpec), b.(*kops.AddonSpec), scope)
 	}); err != nil {
@@ -83,6 +103,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AuditWebhookSpec)(nil), (*kops.AuditWebhookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AuditWebhookSpec_To_kops_AuditWebhookSpec(a.(*AuditWebhookSpec), b.(*kops.AuditWebhookSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditWebhookSpec)(nil), (*AuditWebhookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditWebhookSpec_To_v1alpha1_AuditWebhookSpec(a.(*kops.AuditWebhookSpec), b.(*AuditWebhookSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AuthenticationSpec)(nil), (*kops.AuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_AuthenticationSpec_To_kops_AuthenticationSpec(a.(*AuthenticationSpec), b.(*kops.AuthenticationSpec), scope)
 	}); err != nil {
@@ -113,6 +143,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AzureConfiguration)(nil), (*kops.AzureConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AzureConfiguration_To_kops_AzureConfiguration(a.(*AzureConfiguration), b.(*kops.AzureConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AzureConfiguration)(nil), (*AzureConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AzureConfiguration_To_v1alpha1_AzureConfiguration(a.(*kops.AzureConfiguration), b.(*AzureConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CNINetworkingSpec)(nil), (*kops.CNINetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_CNINetworkingSpec_To_kops_CNINetworkingSpec(a.(*CNINetworkingSpec), b.(*kops.CNINetworkingSpec), scope)
 	}); err != nil {
@@ -123,6 +163,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CSISpec)(nil), (*kops.CSISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CSISpec_To_kops_CSISpec(a.(*CSISpec), b.(*kops.CSISpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.CSISpec)(nil), (*CSISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_CSISpec_To_v1alpha1_CSISpec(a.(*kops.CSISpec), b.(*CSISpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CalicoNetworkingSpec)(nil), (*kops.CalicoNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_CalicoNetworkingSpec_To_kops_CalicoNetworkingSpec(a.(*CalicoNetworkingSpec), b.(*kops.CalicoNetworkingSpec), scope)
 	}); err != nil {
@@ -143,6 +193,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ChartSpec)(nil), (*kops.ChartSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ChartSpec_To_kops_ChartSpec(a.(*ChartSpec), b.(*kops.ChartSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ChartSpec)(nil), (*ChartSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ChartSpec_To_v1alpha1_ChartSpec(a.(*kops.ChartSpec), b.(*ChartSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CiliumNetworkingSpec)(nil), (*kops.CiliumNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_CiliumNetworkingSpec_To_kops_CiliumNetworkingSpec(a.(*CiliumNetworkingSpec), b.(*kops.CiliumNetworkingSpec), scope)
 	}); err != nil {
@@ -193,6 +253,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterAutoscalerConfig)(nil), (*kops.ClusterAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(a.(*ClusterAutoscalerConfig), b.(*kops.ClusterAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ClusterAutoscalerConfig)(nil), (*ClusterAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(a.(*kops.ClusterAutoscalerConfig), b.(*ClusterAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ClusterList)(nil), (*kops.ClusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ClusterList_To_kops_ClusterList(a.(*ClusterList), b.(*kops.ClusterList), scope)
 	}); err != nil {
@@ -253,6 +323,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EncryptionAtRestSpec)(nil), (*kops.EncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(a.(*EncryptionAtRestSpec), b.(*kops.EncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EncryptionAtRestSpec)(nil), (*EncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EncryptionAtRestSpec_To_v1alpha1_EncryptionAtRestSpec(a.(*kops.EncryptionAtRestSpec), b.(*EncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EtcdBackupSpec)(nil), (*kops.EtcdBackupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_EtcdBackupSpec_To_kops_EtcdBackupSpec(a.(*EtcdBackupSpec), b.(*kops.EtcdBackupSpec), scope)
 	}); err != nil {
@@ -353,6 +433,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GCPKMSSecretEncryptionSpec)(nil), (*kops.GCPKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(a.(*GCPKMSSecretEncryptionSpec), b.(*kops.GCPKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.GCPKMSSecretEncryptionSpec)(nil), (*GCPKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha1_GCPKMSSecretEncryptionSpec(a.(*kops.GCPKMSSecretEncryptionSpec), b.(*GCPKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HTTPProxy)(nil), (*kops.HTTPProxy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_HTTPProxy_To_kops_HTTPProxy(a.(*HTTPProxy), b.(*kops.HTTPProxy), scope)
 	}); err != nil {
@@ -423,6 +513,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KMSEncryptionAtRestSpec)(nil), (*kops.KMSEncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(a.(*KMSEncryptionAtRestSpec), b.(*kops.KMSEncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KMSEncryptionAtRestSpec)(nil), (*KMSEncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha1_KMSEncryptionAtRestSpec(a.(*kops.KMSEncryptionAtRestSpec), b.(*KMSEncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KernelModuleSpec)(nil), (*kops.KernelModuleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KernelModuleSpec_To_kops_KernelModuleSpec(a.(*KernelModuleSpec), b.(*kops.KernelModuleSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KernelModuleSpec)(nil), (*KernelModuleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KernelModuleSpec_To_v1alpha1_KernelModuleSpec(a.(*kops.KernelModuleSpec), b.(*KernelModuleSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KopeioAuthenticationSpec)(nil), (*kops.KopeioAuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_KopeioAuthenticationSpec_To_kops_KopeioAuthenticationSpec(a.(*KopeioAuthenticationSpec), b.(*kops.KopeioAuthenticationSpec), scope)
 	}); err != nil {
@@ -553,6 +663,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LocalSecretEncryptionSpec)(nil), (*kops.LocalSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(a.(*LocalSecretEncryptionSpec), b.(*kops.LocalSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LocalSecretEncryptionSpec)(nil), (*LocalSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LocalSecretEncryptionSpec_To_v1alpha1_LocalSecretEncryptionSpec(a.(*kops.LocalSecretEncryptionSpec), b.(*LocalSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LyftVPCNetworkingSpec)(nil), (*kops.LyftVPCNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_LyftVPCNetworkingSpec_To_kops_LyftVPCNetworkingSpec(a.(*LyftVPCNetworkingSpec), b.(*kops.LyftVPCNetworkingSpec), scope)
 	}); err != nil {
@@ -603,6 +723,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeHardeningSpec)(nil), (*kops.NodeHardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeHardeningSpec_To_kops_NodeHardeningSpec(a.(*NodeHardeningSpec), b.(*kops.NodeHardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeHardeningSpec)(nil), (*NodeHardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeHardeningSpec_To_v1alpha1_NodeHardeningSpec(a.(*kops.NodeHardeningSpec), b.(*NodeHardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeReconcileSpec)(nil), (*kops.NodeReconcileSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeReconcileSpec_To_kops_NodeReconcileSpec(a.(*NodeReconcileSpec), b.(*kops.NodeReconcileSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeReconcileSpec)(nil), (*NodeReconcileSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeReconcileSpec_To_v1alpha1_NodeReconcileSpec(a.(*kops.NodeReconcileSpec), b.(*NodeReconcileSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenstackBlockStorageConfig)(nil), (*kops.OpenstackBlockStorageConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(a.(*OpenstackBlockStorageConfig), b.(*kops.OpenstackBlockStorageConfig), scope)
 	}); err != nil {
@@ -703,6 +843,86 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPlugin)(nil), (*kops.SchedulerPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SchedulerPlugin_To_kops_SchedulerPlugin(a.(*SchedulerPlugin), b.(*kops.SchedulerPlugin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPlugin)(nil), (*SchedulerPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPlugin_To_v1alpha1_SchedulerPlugin(a.(*kops.SchedulerPlugin), b.(*SchedulerPlugin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPluginConfig)(nil), (*kops.SchedulerPluginConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(a.(*SchedulerPluginConfig), b.(*kops.SchedulerPluginConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPluginConfig)(nil), (*SchedulerPluginConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPluginConfig_To_v1alpha1_SchedulerPluginConfig(a.(*kops.SchedulerPluginConfig), b.(*SchedulerPluginConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPluginSet)(nil), (*kops.SchedulerPluginSet)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(a.(*SchedulerPluginSet), b.(*kops.SchedulerPluginSet), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPluginSet)(nil), (*SchedulerPluginSet)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(a.(*kops.SchedulerPluginSet), b.(*SchedulerPluginSet), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPlugins)(nil), (*kops.SchedulerPlugins)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SchedulerPlugins_To_kops_SchedulerPlugins(a.(*SchedulerPlugins), b.(*kops.SchedulerPlugins), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPlugins)(nil), (*SchedulerPlugins)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPlugins_To_v1alpha1_SchedulerPlugins(a.(*kops.SchedulerPlugins), b.(*SchedulerPlugins), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerProfile)(nil), (*kops.SchedulerProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SchedulerProfile_To_kops_SchedulerProfile(a.(*SchedulerProfile), b.(*kops.SchedulerProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerProfile)(nil), (*SchedulerProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerProfile_To_v1alpha1_SchedulerProfile(a.(*kops.SchedulerProfile), b.(*SchedulerProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecretEncryptionSpec)(nil), (*kops.SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(a.(*SecretEncryptionSpec), b.(*kops.SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SecretEncryptionSpec)(nil), (*SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(a.(*kops.SecretEncryptionSpec), b.(*SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StorageSpec)(nil), (*kops.StorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_StorageSpec_To_kops_StorageSpec(a.(*StorageSpec), b.(*kops.StorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.StorageSpec)(nil), (*StorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_StorageSpec_To_v1alpha1_StorageSpec(a.(*kops.StorageSpec), b.(*StorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SystemdUnitSpec)(nil), (*kops.SystemdUnitSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SystemdUnitSpec_To_kops_SystemdUnitSpec(a.(*SystemdUnitSpec), b.(*kops.SystemdUnitSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SystemdUnitSpec)(nil), (*SystemdUnitSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SystemdUnitSpec_To_v1alpha1_SystemdUnitSpec(a.(*kops.SystemdUnitSpec), b.(*SystemdUnitSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -733,6 +953,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*VaultSpec)(nil), (*kops.VaultSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VaultSpec_To_kops_VaultSpec(a.(*VaultSpec), b.(*kops.VaultSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.VaultSpec)(nil), (*VaultSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_VaultSpec_To_v1alpha1_VaultSpec(a.(*kops.VaultSpec), b.(*VaultSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VaultTransitSecretEncryptionSpec)(nil), (*kops.VaultTransitSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(a.(*VaultTransitSecretEncryptionSpec), b.(*kops.VaultTransitSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.VaultTransitSecretEncryptionSpec)(nil), (*VaultTransitSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha1_VaultTransitSecretEncryptionSpec(a.(*kops.VaultTransitSecretEncryptionSpec), b.(*VaultTransitSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -816,6 +1056,28 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha1_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(in *AWSKMSSecretEncryptionSpec, out *kops.AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1alpha1_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha1_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(in *AWSKMSSecretEncryptionSpec, out *kops.AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha1_AWSKMSSecretEncryptionSpec(in *kops.AWSKMSSecretEncryptionSpec, out *AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha1_AWSKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha1_AWSKMSSecretEncryptionSpec(in *kops.AWSKMSSecretEncryptionSpec, out *AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha1_AWSKMSSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_AccessSpec_To_kops_AccessSpec(in *AccessSpec, out *kops.AccessSpec, s conversion.Scope) error {
 	if in.DNS != nil {
 		in, out := &in.DNS, &out.DNS
@@ -870,8 +1132,48 @@ func Convert_kops_AccessSpec_To_v1alpha1_AccessSpec(in *kops.AccessSpec, out *Ac
 	return autoConvert_kops_AccessSpec_To_v1alpha1_AccessSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_AddonOverrideSpec_To_kops_AddonOverrideSpec(in *AddonOverrideSpec, out *kops.AddonOverrideSpec, s conversion.Scope) error {
+	out.Addon = in.Addon
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.Type = in.Type
+	out.Patch = in.Patch
+	return nil
+}
+
+// Convert_v1alpha1_AddonOverrideSpec_To_kops_AddonOverrideSpec is an autogenerated conversion function.
+func Convert_v1alpha1_AddonOverrideSpec_To_kops_AddonOverrideSpec(in *AddonOverrideSpec, out *kops.AddonOverrideSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AddonOverrideSpec_To_kops_AddonOverrideSpec(in, out, s)
+}
+
+func autoConvert_kops_AddonOverrideSpec_To_v1alpha1_AddonOverrideSpec(in *kops.AddonOverrideSpec, out *AddonOverrideSpec, s conversion.Scope) error {
+	out.Addon = in.Addon
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.Type = in.Type
+	out.Patch = in.Patch
+	return nil
+}
+
+// Convert_kops_AddonOverrideSpec_To_v1alpha1_AddonOverrideSpec is an autogenerated conversion function.
+func Convert_kops_AddonOverrideSpec_To_v1alpha1_AddonOverrideSpec(in *kops.AddonOverrideSpec, out *AddonOverrideSpec, s conversion.Scope) error {
+	return autoConvert_kops_AddonOverrideSpec_To_v1alpha1_AddonOverrideSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	out.Name = in.Name
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(kops.ChartSpec)
+		if err := Convert_v1alpha1_ChartSpec_To_kops_ChartSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -882,6 +1184,16 @@ func Convert_v1alpha1_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.Addon
 
 func autoConvert_kops_AddonSpec_To_v1alpha1_AddonSpec(in *kops.AddonSpec, out *AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	out.Name = in.Name
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(ChartSpec)
+		if err := Convert_kops_ChartSpec_To_v1alpha1_ChartSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -932,6 +1244,8 @@ func autoConvert_v1alpha1_Assets_To_kops_Assets(in *Assets, out *kops.Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.FileSignatureKey = in.FileSignatureKey
 	return nil
 }
 
@@ -944,6 +1258,8 @@ func autoConvert_kops_Assets_To_v1alpha1_Assets(in *kops.Assets, out *Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.FileSignatureKey = in.FileSignatureKey
 	return nil
 }
 
@@ -952,6 +1268,30 @@ func Convert_kops_Assets_To_v1alpha1_Assets(in *kops.Assets, out *Assets, s conv
 	return autoConvert_kops_Assets_To_v1alpha1_Assets(in, out, s)
 }
 
+func autoConvert_v1alpha1_AuditWebhookSpec_To_kops_AuditWebhookSpec(in *AuditWebhookSpec, out *kops.AuditWebhookSpec, s conversion.Scope) error {
+	out.Server = in.Server
+	out.CACertificate = in.CACertificate
+	out.TokenSecret = in.TokenSecret
+	return nil
+}
+
+// Convert_v1alpha1_AuditWebhookSpec_To_kops_AuditWebhookSpec is an autogenerated conversion function.
+func Convert_v1alpha1_AuditWebhookSpec_To_kops_AuditWebhookSpec(in *AuditWebhookSpec, out *kops.AuditWebhookSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AuditWebhookSpec_To_kops_AuditWebhookSpec(in, out, s)
+}
+
+func autoConvert_kops_AuditWebhookSpec_To_v1alpha1_AuditWebhookSpec(in *kops.AuditWebhookSpec, out *AuditWebhookSpec, s conversion.Scope) error {
+	out.Server = in.Server
+	out.CACertificate = in.CACertificate
+	out.TokenSecret = in.TokenSecret
+	return nil
+}
+
+// Convert_kops_AuditWebhookSpec_To_v1alpha1_AuditWebhookSpec is an autogenerated conversion function.
+func Convert_kops_AuditWebhookSpec_To_v1alpha1_AuditWebhookSpec(in *kops.AuditWebhookSpec, out *AuditWebhookSpec, s conversion.Scope) error {
+	return autoConvert_kops_AuditWebhookSpec_To_v1alpha1_AuditWebhookSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_AuthenticationSpec_To_kops_AuthenticationSpec(in *AuthenticationSpec, out *kops.AuthenticationSpec, s conversion.Scope) error {
 	if in.Kopeio != nil {
 		in, out := &in.Kopeio, &out.Kopeio
@@ -1088,6 +1428,34 @@ func Convert_kops_AwsAuthenticationSpec_To_v1alpha1_AwsAuthenticationSpec(in *ko
 	return autoConvert_kops_AwsAuthenticationSpec_To_v1alpha1_AwsAuthenticationSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_AzureConfiguration_To_kops_AzureConfiguration(in *AzureConfiguration, out *kops.AzureConfiguration, s conversion.Scope) error {
+	out.SubscriptionID = in.SubscriptionID
+	out.TenantID = in.TenantID
+	out.ResourceGroupName = in.ResourceGroupName
+	out.RouteTableName = in.RouteTableName
+	out.AdminUser = in.AdminUser
+	return nil
+}
+
+// Convert_v1alpha1_AzureConfiguration_To_kops_AzureConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_AzureConfiguration_To_kops_AzureConfiguration(in *AzureConfiguration, out *kops.AzureConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AzureConfiguration_To_kops_AzureConfiguration(in, out, s)
+}
+
+func autoConvert_kops_AzureConfiguration_To_v1alpha1_AzureConfiguration(in *kops.AzureConfiguration, out *AzureConfiguration, s conversion.Scope) error {
+	out.SubscriptionID = in.SubscriptionID
+	out.TenantID = in.TenantID
+	out.ResourceGroupName = in.ResourceGroupName
+	out.RouteTableName = in.RouteTableName
+	out.AdminUser = in.AdminUser
+	return nil
+}
+
+// Convert_kops_AzureConfiguration_To_v1alpha1_AzureConfiguration is an autogenerated conversion function.
+func Convert_kops_AzureConfiguration_To_v1alpha1_AzureConfiguration(in *kops.AzureConfiguration, out *AzureConfiguration, s conversion.Scope) error {
+	return autoConvert_kops_AzureConfiguration_To_v1alpha1_AzureConfiguration(in, out, s)
+}
+
 func autoConvert_v1alpha1_CNINetworkingSpec_To_kops_CNINetworkingSpec(in *CNINetworkingSpec, out *kops.CNINetworkingSpec, s conversion.Scope) error {
 	out.UsesSecondaryIP = in.UsesSecondaryIP
 	return nil
@@ -1108,6 +1476,28 @@ func Convert_kops_CNINetworkingSpec_To_v1alpha1_CNINetworkingSpec(in *kops.CNINe
 	return autoConvert_kops_CNINetworkingSpec_To_v1alpha1_CNINetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_CSISpec_To_kops_CSISpec(in *CSISpec, out *kops.CSISpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1alpha1_CSISpec_To_kops_CSISpec is an autogenerated conversion function.
+func Convert_v1alpha1_CSISpec_To_kops_CSISpec(in *CSISpec, out *kops.CSISpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CSISpec_To_kops_CSISpec(in, out, s)
+}
+
+func autoConvert_kops_CSISpec_To_v1alpha1_CSISpec(in *kops.CSISpec, out *CSISpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_kops_CSISpec_To_v1alpha1_CSISpec is an autogenerated conversion function.
+func Convert_kops_CSISpec_To_v1alpha1_CSISpec(in *kops.CSISpec, out *CSISpec, s conversion.Scope) error {
+	return autoConvert_kops_CSISpec_To_v1alpha1_CSISpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_CalicoNetworkingSpec_To_kops_CalicoNetworkingSpec(in *CalicoNetworkingSpec, out *kops.CalicoNetworkingSpec, s conversion.Scope) error {
 	out.CrossSubnet = in.CrossSubnet
 	out.LogSeverityScreen = in.LogSeverityScreen
@@ -1178,6 +1568,36 @@ func Convert_kops_CanalNetworkingSpec_To_v1alpha1_CanalNetworkingSpec(in *kops.C
 	return autoConvert_kops_CanalNetworkingSpec_To_v1alpha1_CanalNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_ChartSpec_To_kops_ChartSpec(in *ChartSpec, out *kops.ChartSpec, s conversion.Scope) error {
+	out.Repository = in.Repository
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Namespace = in.Namespace
+	out.ReleaseName = in.ReleaseName
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_v1alpha1_ChartSpec_To_kops_ChartSpec is an autogenerated conversion function.
+func Convert_v1alpha1_ChartSpec_To_kops_ChartSpec(in *ChartSpec, out *kops.ChartSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ChartSpec_To_kops_ChartSpec(in, out, s)
+}
+
+func autoConvert_kops_ChartSpec_To_v1alpha1_ChartSpec(in *kops.ChartSpec, out *ChartSpec, s conversion.Scope) error {
+	out.Repository = in.Repository
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Namespace = in.Namespace
+	out.ReleaseName = in.ReleaseName
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_kops_ChartSpec_To_v1alpha1_ChartSpec is an autogenerated conversion function.
+func Convert_kops_ChartSpec_To_v1alpha1_ChartSpec(in *kops.ChartSpec, out *ChartSpec, s conversion.Scope) error {
+	return autoConvert_kops_ChartSpec_To_v1alpha1_ChartSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_CiliumNetworkingSpec_To_kops_CiliumNetworkingSpec(in *CiliumNetworkingSpec, out *kops.CiliumNetworkingSpec, s conversion.Scope) error {
 	out.Version = in.Version
 	out.AccessLog = in.AccessLog
@@ -1336,6 +1756,15 @@ func autoConvert_v1alpha1_CloudConfiguration_To_kops_CloudConfiguration(in *Clou
 	} else {
 		out.Openstack = nil
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(kops.AzureConfiguration)
+		if err := Convert_v1alpha1_AzureConfiguration_To_kops_AzureConfiguration(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Azure = nil
+	}
 	return nil
 }
 
@@ -1368,6 +1797,15 @@ func autoConvert_kops_CloudConfiguration_To_v1alpha1_CloudConfiguration(in *kops
 	} else {
 		out.Openstack = nil
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureConfiguration)
+		if err := Convert_kops_AzureConfiguration_To_v1alpha1_AzureConfiguration(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Azure = nil
+	}
 	return nil
 }
 
@@ -1458,6 +1896,48 @@ func Convert_kops_Cluster_To_v1alpha1_Cluster(in *kops.Cluster, out *Cluster, s
 	return autoConvert_kops_Cluster_To_v1alpha1_Cluster(in, out, s)
 }
 
+func autoConvert_v1alpha1_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(in *ClusterAutoscalerConfig, out *kops.ClusterAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Expander = in.Expander
+	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	out.ScaleDownEnabled = in.ScaleDownEnabled
+	out.ScaleDownUtilizationThreshold = in.ScaleDownUtilizationThreshold
+	out.ScaleDownUnneededTime = in.ScaleDownUnneededTime
+	out.ScaleDownDelayAfterAdd = in.ScaleDownDelayAfterAdd
+	out.SkipNodesWithLocalStorage = in.SkipNodesWithLocalStorage
+	out.SkipNodesWithSystemPods = in.SkipNodesWithSystemPods
+	out.Image = in.Image
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	return nil
+}
+
+// Convert_v1alpha1_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(in *ClusterAutoscalerConfig, out *kops.ClusterAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(in, out, s)
+}
+
+func autoConvert_kops_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(in *kops.ClusterAutoscalerConfig, out *ClusterAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Expander = in.Expander
+	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	out.ScaleDownEnabled = in.ScaleDownEnabled
+	out.ScaleDownUtilizationThreshold = in.ScaleDownUtilizationThreshold
+	out.ScaleDownUnneededTime = in.ScaleDownUnneededTime
+	out.ScaleDownDelayAfterAdd = in.ScaleDownDelayAfterAdd
+	out.SkipNodesWithLocalStorage = in.SkipNodesWithLocalStorage
+	out.SkipNodesWithSystemPods = in.SkipNodesWithSystemPods
+	out.Image = in.Image
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	return nil
+}
+
+// Convert_kops_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig is an autogenerated conversion function.
+func Convert_kops_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(in *kops.ClusterAutoscalerConfig, out *ClusterAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_kops_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_ClusterList_To_kops_ClusterList(in *ClusterList, out *kops.ClusterList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -1513,10 +1993,21 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Addons = nil
 	}
-	out.ConfigBase = in.ConfigBase
-	out.CloudProvider = in.CloudProvider
-	out.KubernetesVersion = in.KubernetesVersion
-	// WARNING: in.Zones requires manual conversion: does not exist in peer-type
+	if in.AddonOverrides != nil {
+		in, out := &in.AddonOverrides, &out.AddonOverrides
+		*out = make([]kops.AddonOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_AddonOverrideSpec_To_kops_AddonOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AddonOverrides = nil
+	}
+	out.ConfigBase = in.ConfigBase
+	out.CloudProvider = in.CloudProvider
+	out.KubernetesVersion = in.KubernetesVersion
+	// WARNING: in.Zones requires manual conversion: does not exist in peer-type
 	out.Project = in.Project
 	out.MasterPublicName = in.MasterPublicName
 	out.MasterInternalName = in.MasterInternalName
@@ -1534,6 +2025,15 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(kops.VaultSpec)
+		if err := Convert_v1alpha1_VaultSpec_To_kops_VaultSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	out.AdditionalSANs = in.AdditionalSANs
@@ -1678,6 +2178,15 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.ExternalDNS = nil
 	}
+	if in.ClusterAutoscaler != nil {
+		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
+		*out = new(kops.ClusterAutoscalerConfig)
+		if err := Convert_v1alpha1_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClusterAutoscaler = nil
+	}
 	if in.Networking != nil {
 		in, out := &in.Networking, &out.Networking
 		*out = new(kops.NetworkingSpec)
@@ -1687,6 +2196,15 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Networking = nil
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(kops.StorageSpec)
+		if err := Convert_v1alpha1_StorageSpec_To_kops_StorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Storage = nil
+	}
 	if in.API != nil {
 		in, out := &in.API, &out.API
 		*out = new(kops.AccessSpec)
@@ -1723,6 +2241,24 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.NodeAuthorization = nil
 	}
+	if in.NodeReconcile != nil {
+		in, out := &in.NodeReconcile, &out.NodeReconcile
+		*out = new(kops.NodeReconcileSpec)
+		if err := Convert_v1alpha1_NodeReconcileSpec_To_kops_NodeReconcileSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconcile = nil
+	}
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(kops.NodeHardeningSpec)
+		if err := Convert_v1alpha1_NodeHardeningSpec_To_kops_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -1735,6 +2271,29 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]kops.KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_KernelModuleSpec_To_kops_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]kops.SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_SystemdUnitSpec_To_kops_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(kops.Assets)
@@ -1754,6 +2313,24 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(kops.EncryptionAtRestSpec)
+		if err := Convert_v1alpha1_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EncryptionAtRest = nil
+	}
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(kops.SecretEncryptionSpec)
+		if err := Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.DisableSubnetTags = in.DisableSubnetTags
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
@@ -1780,6 +2357,17 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Addons = nil
 	}
+	if in.AddonOverrides != nil {
+		in, out := &in.AddonOverrides, &out.AddonOverrides
+		*out = make([]AddonOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_AddonOverrideSpec_To_v1alpha1_AddonOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AddonOverrides = nil
+	}
 	out.ConfigBase = in.ConfigBase
 	out.CloudProvider = in.CloudProvider
 	out.KubernetesVersion = in.KubernetesVersion
@@ -1801,6 +2389,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultSpec)
+		if err := Convert_kops_VaultSpec_To_v1alpha1_VaultSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	out.AdditionalSANs = in.AdditionalSANs
@@ -1946,6 +2543,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.ExternalDNS = nil
 	}
+	if in.ClusterAutoscaler != nil {
+		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
+		*out = new(ClusterAutoscalerConfig)
+		if err := Convert_kops_ClusterAutoscalerConfig_To_v1alpha1_ClusterAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClusterAutoscaler = nil
+	}
 	if in.Networking != nil {
 		in, out := &in.Networking, &out.Networking
 		*out = new(NetworkingSpec)
@@ -1955,6 +2561,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Networking = nil
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		if err := Convert_kops_StorageSpec_To_v1alpha1_StorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Storage = nil
+	}
 	if in.API != nil {
 		in, out := &in.API, &out.API
 		*out = new(AccessSpec)
@@ -1991,6 +2606,24 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.NodeAuthorization = nil
 	}
+	if in.NodeReconcile != nil {
+		in, out := &in.NodeReconcile, &out.NodeReconcile
+		*out = new(NodeReconcileSpec)
+		if err := Convert_kops_NodeReconcileSpec_To_v1alpha1_NodeReconcileSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconcile = nil
+	}
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(NodeHardeningSpec)
+		if err := Convert_kops_NodeHardeningSpec_To_v1alpha1_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -2003,6 +2636,29 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KernelModuleSpec_To_v1alpha1_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SystemdUnitSpec_To_v1alpha1_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(Assets)
@@ -2022,6 +2678,24 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRestSpec)
+		if err := Convert_kops_EncryptionAtRestSpec_To_v1alpha1_EncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EncryptionAtRest = nil
+	}
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(SecretEncryptionSpec)
+		if err := Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.DisableSubnetTags = in.DisableSubnetTags
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
@@ -2163,6 +2837,46 @@ func Convert_kops_EgressProxySpec_To_v1alpha1_EgressProxySpec(in *kops.EgressPro
 	return autoConvert_kops_EgressProxySpec_To_v1alpha1_EgressProxySpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in *EncryptionAtRestSpec, out *kops.EncryptionAtRestSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.Resources = in.Resources
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(kops.KMSEncryptionAtRestSpec)
+		if err := Convert_v1alpha1_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KMS = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_v1alpha1_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in *EncryptionAtRestSpec, out *kops.EncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in, out, s)
+}
+
+func autoConvert_kops_EncryptionAtRestSpec_To_v1alpha1_EncryptionAtRestSpec(in *kops.EncryptionAtRestSpec, out *EncryptionAtRestSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.Resources = in.Resources
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(KMSEncryptionAtRestSpec)
+		if err := Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha1_KMSEncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KMS = nil
+	}
+	return nil
+}
+
+// Convert_kops_EncryptionAtRestSpec_To_v1alpha1_EncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_kops_EncryptionAtRestSpec_To_v1alpha1_EncryptionAtRestSpec(in *kops.EncryptionAtRestSpec, out *EncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_kops_EncryptionAtRestSpec_To_v1alpha1_EncryptionAtRestSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_EtcdBackupSpec_To_kops_EtcdBackupSpec(in *EtcdBackupSpec, out *kops.EtcdBackupSpec, s conversion.Scope) error {
 	out.BackupStore = in.BackupStore
 	out.Image = in.Image
@@ -2475,6 +3189,26 @@ func Convert_kops_GCENetworkingSpec_To_v1alpha1_GCENetworkingSpec(in *kops.GCENe
 	return autoConvert_kops_GCENetworkingSpec_To_v1alpha1_GCENetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(in *GCPKMSSecretEncryptionSpec, out *kops.GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_v1alpha1_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha1_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(in *GCPKMSSecretEncryptionSpec, out *kops.GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha1_GCPKMSSecretEncryptionSpec(in *kops.GCPKMSSecretEncryptionSpec, out *GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha1_GCPKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha1_GCPKMSSecretEncryptionSpec(in *kops.GCPKMSSecretEncryptionSpec, out *GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha1_GCPKMSSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_HTTPProxy_To_kops_HTTPProxy(in *HTTPProxy, out *kops.HTTPProxy, s conversion.Scope) error {
 	out.Host = in.Host
 	out.Port = in.Port
@@ -2718,6 +3452,29 @@ func autoConvert_v1alpha1_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]kops.KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_KernelModuleSpec_To_kops_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]kops.SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_SystemdUnitSpec_To_kops_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.AssociatePublicIP = in.AssociatePublicIP
 	out.AdditionalSecurityGroups = in.AdditionalSecurityGroups
@@ -2765,6 +3522,16 @@ func autoConvert_v1alpha1_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.BootstrapFormat = in.BootstrapFormat
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(kops.NodeHardeningSpec)
+		if err := Convert_v1alpha1_NodeHardeningSpec_To_kops_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.Zones = in.Zones
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
@@ -2840,6 +3607,29 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha1_InstanceGroupSpec(in *kops.I
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KernelModuleSpec_To_v1alpha1_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SystemdUnitSpec_To_v1alpha1_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.AssociatePublicIP = in.AssociatePublicIP
 	out.AdditionalSecurityGroups = in.AdditionalSecurityGroups
@@ -2887,6 +3677,16 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha1_InstanceGroupSpec(in *kops.I
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.BootstrapFormat = in.BootstrapFormat
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(NodeHardeningSpec)
+		if err := Convert_kops_NodeHardeningSpec_To_v1alpha1_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
@@ -2914,6 +3714,72 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha1_InstanceGroupSpec(in *kops.I
 	return nil
 }
 
+func autoConvert_v1alpha1_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(in *KMSEncryptionAtRestSpec, out *kops.KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	out.Image = in.Image
+	out.CacheSize = in.CacheSize
+	return nil
+}
+
+// Convert_v1alpha1_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_v1alpha1_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(in *KMSEncryptionAtRestSpec, out *kops.KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(in, out, s)
+}
+
+func autoConvert_kops_KMSEncryptionAtRestSpec_To_v1alpha1_KMSEncryptionAtRestSpec(in *kops.KMSEncryptionAtRestSpec, out *KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	out.Image = in.Image
+	out.CacheSize = in.CacheSize
+	return nil
+}
+
+// Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha1_KMSEncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha1_KMSEncryptionAtRestSpec(in *kops.KMSEncryptionAtRestSpec, out *KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_kops_KMSEncryptionAtRestSpec_To_v1alpha1_KMSEncryptionAtRestSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_KernelModuleSpec_To_kops_KernelModuleSpec(in *KernelModuleSpec, out *kops.KernelModuleSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]kops.InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Parameters = in.Parameters
+	return nil
+}
+
+// Convert_v1alpha1_KernelModuleSpec_To_kops_KernelModuleSpec is an autogenerated conversion function.
+func Convert_v1alpha1_KernelModuleSpec_To_kops_KernelModuleSpec(in *KernelModuleSpec, out *kops.KernelModuleSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KernelModuleSpec_To_kops_KernelModuleSpec(in, out, s)
+}
+
+func autoConvert_kops_KernelModuleSpec_To_v1alpha1_KernelModuleSpec(in *kops.KernelModuleSpec, out *KernelModuleSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Parameters = in.Parameters
+	return nil
+}
+
+// Convert_kops_KernelModuleSpec_To_v1alpha1_KernelModuleSpec is an autogenerated conversion function.
+func Convert_kops_KernelModuleSpec_To_v1alpha1_KernelModuleSpec(in *kops.KernelModuleSpec, out *KernelModuleSpec, s conversion.Scope) error {
+	return autoConvert_kops_KernelModuleSpec_To_v1alpha1_KernelModuleSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_KopeioAuthenticationSpec_To_kops_KopeioAuthenticationSpec(in *KopeioAuthenticationSpec, out *kops.KopeioAuthenticationSpec, s conversion.Scope) error {
 	return nil
 }
@@ -3014,6 +3880,16 @@ func autoConvert_v1alpha1_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(in *Ku
 	out.AuditWebhookConfigFile = in.AuditWebhookConfigFile
 	out.AuditWebhookInitialBackoff = in.AuditWebhookInitialBackoff
 	out.AuditWebhookMode = in.AuditWebhookMode
+	out.AuditPolicy = in.AuditPolicy
+	if in.AuditWebhook != nil {
+		in, out := &in.AuditWebhook, &out.AuditWebhook
+		*out = new(kops.AuditWebhookSpec)
+		if err := Convert_v1alpha1_AuditWebhookSpec_To_kops_AuditWebhookSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AuditWebhook = nil
+	}
 	out.AuthenticationTokenWebhookConfigFile = in.AuthenticationTokenWebhookConfigFile
 	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
 	out.AuthorizationMode = in.AuthorizationMode
@@ -3022,6 +3898,7 @@ func autoConvert_v1alpha1_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(in *Ku
 	out.AuthorizationWebhookCacheUnauthorizedTTL = in.AuthorizationWebhookCacheUnauthorizedTTL
 	out.AuthorizationRBACSuperUser = in.AuthorizationRBACSuperUser
 	out.ExperimentalEncryptionProviderConfig = in.ExperimentalEncryptionProviderConfig
+	out.EncryptionProviderConfig = in.EncryptionProviderConfig
 	out.RequestheaderUsernameHeaders = in.RequestheaderUsernameHeaders
 	out.RequestheaderGroupHeaders = in.RequestheaderGroupHeaders
 	out.RequestheaderExtraHeaderPrefixes = in.RequestheaderExtraHeaderPrefixes
@@ -3108,6 +3985,16 @@ func autoConvert_kops_KubeAPIServerConfig_To_v1alpha1_KubeAPIServerConfig(in *ko
 	out.AuditWebhookConfigFile = in.AuditWebhookConfigFile
 	out.AuditWebhookInitialBackoff = in.AuditWebhookInitialBackoff
 	out.AuditWebhookMode = in.AuditWebhookMode
+	out.AuditPolicy = in.AuditPolicy
+	if in.AuditWebhook != nil {
+		in, out := &in.AuditWebhook, &out.AuditWebhook
+		*out = new(AuditWebhookSpec)
+		if err := Convert_kops_AuditWebhookSpec_To_v1alpha1_AuditWebhookSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AuditWebhook = nil
+	}
 	out.AuthenticationTokenWebhookConfigFile = in.AuthenticationTokenWebhookConfigFile
 	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
 	out.AuthorizationMode = in.AuthorizationMode
@@ -3116,6 +4003,7 @@ func autoConvert_kops_KubeAPIServerConfig_To_v1alpha1_KubeAPIServerConfig(in *ko
 	out.AuthorizationWebhookCacheUnauthorizedTTL = in.AuthorizationWebhookCacheUnauthorizedTTL
 	out.AuthorizationRBACSuperUser = in.AuthorizationRBACSuperUser
 	out.ExperimentalEncryptionProviderConfig = in.ExperimentalEncryptionProviderConfig
+	out.EncryptionProviderConfig = in.EncryptionProviderConfig
 	out.RequestheaderUsernameHeaders = in.RequestheaderUsernameHeaders
 	out.RequestheaderGroupHeaders = in.RequestheaderGroupHeaders
 	out.RequestheaderExtraHeaderPrefixes = in.RequestheaderExtraHeaderPrefixes
@@ -3299,6 +4187,11 @@ func autoConvert_v1alpha1_KubeProxyConfig_To_kops_KubeProxyConfig(in *KubeProxyC
 	out.FeatureGates = in.FeatureGates
 	out.ConntrackMaxPerCore = in.ConntrackMaxPerCore
 	out.ConntrackMin = in.ConntrackMin
+	out.IPVSStrictARP = in.IPVSStrictARP
+	out.IPVSTCPTimeout = in.IPVSTCPTimeout
+	out.IPVSTCPFinTimeout = in.IPVSTCPFinTimeout
+	out.IPVSUDPTimeout = in.IPVSUDPTimeout
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3328,6 +4221,11 @@ func autoConvert_kops_KubeProxyConfig_To_v1alpha1_KubeProxyConfig(in *kops.KubeP
 	out.FeatureGates = in.FeatureGates
 	out.ConntrackMaxPerCore = in.ConntrackMaxPerCore
 	out.ConntrackMin = in.ConntrackMin
+	out.IPVSStrictARP = in.IPVSStrictARP
+	out.IPVSTCPTimeout = in.IPVSTCPTimeout
+	out.IPVSTCPFinTimeout = in.IPVSTCPFinTimeout
+	out.IPVSUDPTimeout = in.IPVSUDPTimeout
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3351,6 +4249,18 @@ func autoConvert_v1alpha1_KubeSchedulerConfig_To_kops_KubeSchedulerConfig(in *Ku
 	}
 	out.UsePolicyConfigMap = in.UsePolicyConfigMap
 	out.FeatureGates = in.FeatureGates
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]kops.SchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_SchedulerProfile_To_kops_SchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3374,6 +4284,18 @@ func autoConvert_kops_KubeSchedulerConfig_To_v1alpha1_KubeSchedulerConfig(in *ko
 	}
 	out.UsePolicyConfigMap = in.UsePolicyConfigMap
 	out.FeatureGates = in.FeatureGates
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]SchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerProfile_To_v1alpha1_SchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3458,6 +4380,7 @@ func autoConvert_v1alpha1_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *Kubele
 	out.CpuManagerPolicy = in.CpuManagerPolicy
 	out.RegistryPullQPS = in.RegistryPullQPS
 	out.RegistryBurst = in.RegistryBurst
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3542,6 +4465,7 @@ func autoConvert_kops_KubeletConfigSpec_To_v1alpha1_KubeletConfigSpec(in *kops.K
 	out.CpuManagerPolicy = in.CpuManagerPolicy
 	out.RegistryPullQPS = in.RegistryPullQPS
 	out.RegistryBurst = in.RegistryBurst
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3660,6 +4584,24 @@ func Convert_kops_LoadBalancerAccessSpec_To_v1alpha1_LoadBalancerAccessSpec(in *
 	return autoConvert_kops_LoadBalancerAccessSpec_To_v1alpha1_LoadBalancerAccessSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(in *LocalSecretEncryptionSpec, out *kops.LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_v1alpha1_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha1_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(in *LocalSecretEncryptionSpec, out *kops.LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_LocalSecretEncryptionSpec_To_v1alpha1_LocalSecretEncryptionSpec(in *kops.LocalSecretEncryptionSpec, out *LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_kops_LocalSecretEncryptionSpec_To_v1alpha1_LocalSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_LocalSecretEncryptionSpec_To_v1alpha1_LocalSecretEncryptionSpec(in *kops.LocalSecretEncryptionSpec, out *LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_LocalSecretEncryptionSpec_To_v1alpha1_LocalSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_LyftVPCNetworkingSpec_To_kops_LyftVPCNetworkingSpec(in *LyftVPCNetworkingSpec, out *kops.LyftVPCNetworkingSpec, s conversion.Scope) error {
 	out.SubnetTags = in.SubnetTags
 	return nil
@@ -4068,6 +5010,64 @@ func Convert_kops_NodeAuthorizerSpec_To_v1alpha1_NodeAuthorizerSpec(in *kops.Nod
 	return autoConvert_kops_NodeAuthorizerSpec_To_v1alpha1_NodeAuthorizerSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_NodeHardeningSpec_To_kops_NodeHardeningSpec(in *NodeHardeningSpec, out *kops.NodeHardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	out.Sysctls = in.Sysctls
+	out.BlacklistedModules = in.BlacklistedModules
+	out.AllowedModules = in.AllowedModules
+	out.AuditRules = in.AuditRules
+	out.SSHD = in.SSHD
+	out.DisabledServices = in.DisabledServices
+	out.EnabledServices = in.EnabledServices
+	out.FileModes = in.FileModes
+	return nil
+}
+
+// Convert_v1alpha1_NodeHardeningSpec_To_kops_NodeHardeningSpec is an autogenerated conversion function.
+func Convert_v1alpha1_NodeHardeningSpec_To_kops_NodeHardeningSpec(in *NodeHardeningSpec, out *kops.NodeHardeningSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeHardeningSpec_To_kops_NodeHardeningSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeHardeningSpec_To_v1alpha1_NodeHardeningSpec(in *kops.NodeHardeningSpec, out *NodeHardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	out.Sysctls = in.Sysctls
+	out.BlacklistedModules = in.BlacklistedModules
+	out.AllowedModules = in.AllowedModules
+	out.AuditRules = in.AuditRules
+	out.SSHD = in.SSHD
+	out.DisabledServices = in.DisabledServices
+	out.EnabledServices = in.EnabledServices
+	out.FileModes = in.FileModes
+	return nil
+}
+
+// Convert_kops_NodeHardeningSpec_To_v1alpha1_NodeHardeningSpec is an autogenerated conversion function.
+func Convert_kops_NodeHardeningSpec_To_v1alpha1_NodeHardeningSpec(in *kops.NodeHardeningSpec, out *NodeHardeningSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeHardeningSpec_To_v1alpha1_NodeHardeningSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_NodeReconcileSpec_To_kops_NodeReconcileSpec(in *NodeReconcileSpec, out *kops.NodeReconcileSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha1_NodeReconcileSpec_To_kops_NodeReconcileSpec is an autogenerated conversion function.
+func Convert_v1alpha1_NodeReconcileSpec_To_kops_NodeReconcileSpec(in *NodeReconcileSpec, out *kops.NodeReconcileSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeReconcileSpec_To_kops_NodeReconcileSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeReconcileSpec_To_v1alpha1_NodeReconcileSpec(in *kops.NodeReconcileSpec, out *NodeReconcileSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_kops_NodeReconcileSpec_To_v1alpha1_NodeReconcileSpec is an autogenerated conversion function.
+func Convert_kops_NodeReconcileSpec_To_v1alpha1_NodeReconcileSpec(in *kops.NodeReconcileSpec, out *NodeReconcileSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeReconcileSpec_To_v1alpha1_NodeReconcileSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(in *OpenstackBlockStorageConfig, out *kops.OpenstackBlockStorageConfig, s conversion.Scope) error {
 	out.Version = in.Version
 	out.IgnoreAZ = in.IgnoreAZ
@@ -4392,6 +5392,602 @@ func Convert_kops_SSHCredentialSpec_To_v1alpha1_SSHCredentialSpec(in *kops.SSHCr
 	return autoConvert_kops_SSHCredentialSpec_To_v1alpha1_SSHCredentialSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_SchedulerPlugin_To_kops_SchedulerPlugin(in *SchedulerPlugin, out *kops.SchedulerPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_v1alpha1_SchedulerPlugin_To_kops_SchedulerPlugin is an autogenerated conversion function.
+func Convert_v1alpha1_SchedulerPlugin_To_kops_SchedulerPlugin(in *SchedulerPlugin, out *kops.SchedulerPlugin, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SchedulerPlugin_To_kops_SchedulerPlugin(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPlugin_To_v1alpha1_SchedulerPlugin(in *kops.SchedulerPlugin, out *SchedulerPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_kops_SchedulerPlugin_To_v1alpha1_SchedulerPlugin is an autogenerated conversion function.
+func Convert_kops_SchedulerPlugin_To_v1alpha1_SchedulerPlugin(in *kops.SchedulerPlugin, out *SchedulerPlugin, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPlugin_To_v1alpha1_SchedulerPlugin(in, out, s)
+}
+
+func autoConvert_v1alpha1_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(in *SchedulerPluginConfig, out *kops.SchedulerPluginConfig, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_v1alpha1_SchedulerPluginConfig_To_kops_SchedulerPluginConfig is an autogenerated conversion function.
+func Convert_v1alpha1_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(in *SchedulerPluginConfig, out *kops.SchedulerPluginConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPluginConfig_To_v1alpha1_SchedulerPluginConfig(in *kops.SchedulerPluginConfig, out *SchedulerPluginConfig, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_kops_SchedulerPluginConfig_To_v1alpha1_SchedulerPluginConfig is an autogenerated conversion function.
+func Convert_kops_SchedulerPluginConfig_To_v1alpha1_SchedulerPluginConfig(in *kops.SchedulerPluginConfig, out *SchedulerPluginConfig, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPluginConfig_To_v1alpha1_SchedulerPluginConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(in *SchedulerPluginSet, out *kops.SchedulerPluginSet, s conversion.Scope) error {
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]kops.SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_SchedulerPlugin_To_kops_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Enabled = nil
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]kops.SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_SchedulerPlugin_To_kops_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Disabled = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet is an autogenerated conversion function.
+func Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(in *SchedulerPluginSet, out *kops.SchedulerPluginSet, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(in *kops.SchedulerPluginSet, out *SchedulerPluginSet, s conversion.Scope) error {
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerPlugin_To_v1alpha1_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Enabled = nil
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerPlugin_To_v1alpha1_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Disabled = nil
+	}
+	return nil
+}
+
+// Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet is an autogenerated conversion function.
+func Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(in *kops.SchedulerPluginSet, out *SchedulerPluginSet, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(in, out, s)
+}
+
+func autoConvert_v1alpha1_SchedulerPlugins_To_kops_SchedulerPlugins(in *SchedulerPlugins, out *kops.SchedulerPlugins, s conversion.Scope) error {
+	if in.QueueSort != nil {
+		in, out := &in.QueueSort, &out.QueueSort
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.QueueSort = nil
+	}
+	if in.PreFilter != nil {
+		in, out := &in.PreFilter, &out.PreFilter
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreFilter = nil
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Filter = nil
+	}
+	if in.PostFilter != nil {
+		in, out := &in.PostFilter, &out.PostFilter
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostFilter = nil
+	}
+	if in.PreScore != nil {
+		in, out := &in.PreScore, &out.PreScore
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreScore = nil
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Score = nil
+	}
+	if in.Reserve != nil {
+		in, out := &in.Reserve, &out.Reserve
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reserve = nil
+	}
+	if in.Permit != nil {
+		in, out := &in.Permit, &out.Permit
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Permit = nil
+	}
+	if in.PreBind != nil {
+		in, out := &in.PreBind, &out.PreBind
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreBind = nil
+	}
+	if in.Bind != nil {
+		in, out := &in.Bind, &out.Bind
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bind = nil
+	}
+	if in.PostBind != nil {
+		in, out := &in.PostBind, &out.PostBind
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostBind = nil
+	}
+	if in.Unreserve != nil {
+		in, out := &in.Unreserve, &out.Unreserve
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha1_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Unreserve = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_SchedulerPlugins_To_kops_SchedulerPlugins is an autogenerated conversion function.
+func Convert_v1alpha1_SchedulerPlugins_To_kops_SchedulerPlugins(in *SchedulerPlugins, out *kops.SchedulerPlugins, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SchedulerPlugins_To_kops_SchedulerPlugins(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPlugins_To_v1alpha1_SchedulerPlugins(in *kops.SchedulerPlugins, out *SchedulerPlugins, s conversion.Scope) error {
+	if in.QueueSort != nil {
+		in, out := &in.QueueSort, &out.QueueSort
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.QueueSort = nil
+	}
+	if in.PreFilter != nil {
+		in, out := &in.PreFilter, &out.PreFilter
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreFilter = nil
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Filter = nil
+	}
+	if in.PostFilter != nil {
+		in, out := &in.PostFilter, &out.PostFilter
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostFilter = nil
+	}
+	if in.PreScore != nil {
+		in, out := &in.PreScore, &out.PreScore
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreScore = nil
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Score = nil
+	}
+	if in.Reserve != nil {
+		in, out := &in.Reserve, &out.Reserve
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reserve = nil
+	}
+	if in.Permit != nil {
+		in, out := &in.Permit, &out.Permit
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Permit = nil
+	}
+	if in.PreBind != nil {
+		in, out := &in.PreBind, &out.PreBind
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreBind = nil
+	}
+	if in.Bind != nil {
+		in, out := &in.Bind, &out.Bind
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bind = nil
+	}
+	if in.PostBind != nil {
+		in, out := &in.PostBind, &out.PostBind
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostBind = nil
+	}
+	if in.Unreserve != nil {
+		in, out := &in.Unreserve, &out.Unreserve
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha1_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Unreserve = nil
+	}
+	return nil
+}
+
+// Convert_kops_SchedulerPlugins_To_v1alpha1_SchedulerPlugins is an autogenerated conversion function.
+func Convert_kops_SchedulerPlugins_To_v1alpha1_SchedulerPlugins(in *kops.SchedulerPlugins, out *SchedulerPlugins, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPlugins_To_v1alpha1_SchedulerPlugins(in, out, s)
+}
+
+func autoConvert_v1alpha1_SchedulerProfile_To_kops_SchedulerProfile(in *SchedulerProfile, out *kops.SchedulerProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(kops.SchedulerPlugins)
+		if err := Convert_v1alpha1_SchedulerPlugins_To_kops_SchedulerPlugins(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugins = nil
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = make([]kops.SchedulerPluginConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PluginConfig = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_SchedulerProfile_To_kops_SchedulerProfile is an autogenerated conversion function.
+func Convert_v1alpha1_SchedulerProfile_To_kops_SchedulerProfile(in *SchedulerProfile, out *kops.SchedulerProfile, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SchedulerProfile_To_kops_SchedulerProfile(in, out, s)
+}
+
+func autoConvert_kops_SchedulerProfile_To_v1alpha1_SchedulerProfile(in *kops.SchedulerProfile, out *SchedulerProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(SchedulerPlugins)
+		if err := Convert_kops_SchedulerPlugins_To_v1alpha1_SchedulerPlugins(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugins = nil
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = make([]SchedulerPluginConfig, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerPluginConfig_To_v1alpha1_SchedulerPluginConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PluginConfig = nil
+	}
+	return nil
+}
+
+// Convert_kops_SchedulerProfile_To_v1alpha1_SchedulerProfile is an autogenerated conversion function.
+func Convert_kops_SchedulerProfile_To_v1alpha1_SchedulerProfile(in *kops.SchedulerProfile, out *SchedulerProfile, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerProfile_To_v1alpha1_SchedulerProfile(in, out, s)
+}
+
+func autoConvert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	if in.AWSKMS != nil {
+		in, out := &in.AWSKMS, &out.AWSKMS
+		*out = new(kops.AWSKMSSecretEncryptionSpec)
+		if err := Convert_v1alpha1_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSKMS = nil
+	}
+	if in.GCPKMS != nil {
+		in, out := &in.GCPKMS, &out.GCPKMS
+		*out = new(kops.GCPKMSSecretEncryptionSpec)
+		if err := Convert_v1alpha1_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCPKMS = nil
+	}
+	if in.VaultTransit != nil {
+		in, out := &in.VaultTransit, &out.VaultTransit
+		*out = new(kops.VaultTransitSecretEncryptionSpec)
+		if err := Convert_v1alpha1_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.VaultTransit = nil
+	}
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(kops.LocalSecretEncryptionSpec)
+		if err := Convert_v1alpha1_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Local = nil
+	}
+	if in.PreviousKeys != nil {
+		in, out := &in.PreviousKeys, &out.PreviousKeys
+		*out = make([]kops.SecretEncryptionSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreviousKeys = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	if in.AWSKMS != nil {
+		in, out := &in.AWSKMS, &out.AWSKMS
+		*out = new(AWSKMSSecretEncryptionSpec)
+		if err := Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha1_AWSKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSKMS = nil
+	}
+	if in.GCPKMS != nil {
+		in, out := &in.GCPKMS, &out.GCPKMS
+		*out = new(GCPKMSSecretEncryptionSpec)
+		if err := Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha1_GCPKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCPKMS = nil
+	}
+	if in.VaultTransit != nil {
+		in, out := &in.VaultTransit, &out.VaultTransit
+		*out = new(VaultTransitSecretEncryptionSpec)
+		if err := Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha1_VaultTransitSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.VaultTransit = nil
+	}
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalSecretEncryptionSpec)
+		if err := Convert_kops_LocalSecretEncryptionSpec_To_v1alpha1_LocalSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Local = nil
+	}
+	if in.PreviousKeys != nil {
+		in, out := &in.PreviousKeys, &out.PreviousKeys
+		*out = make([]SecretEncryptionSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreviousKeys = nil
+	}
+	return nil
+}
+
+// Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_StorageSpec_To_kops_StorageSpec(in *StorageSpec, out *kops.StorageSpec, s conversion.Scope) error {
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(kops.CSISpec)
+		if err := Convert_v1alpha1_CSISpec_To_kops_CSISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CSI = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_StorageSpec_To_kops_StorageSpec is an autogenerated conversion function.
+func Convert_v1alpha1_StorageSpec_To_kops_StorageSpec(in *StorageSpec, out *kops.StorageSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_StorageSpec_To_kops_StorageSpec(in, out, s)
+}
+
+func autoConvert_kops_StorageSpec_To_v1alpha1_StorageSpec(in *kops.StorageSpec, out *StorageSpec, s conversion.Scope) error {
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(CSISpec)
+		if err := Convert_kops_CSISpec_To_v1alpha1_CSISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CSI = nil
+	}
+	return nil
+}
+
+// Convert_kops_StorageSpec_To_v1alpha1_StorageSpec is an autogenerated conversion function.
+func Convert_kops_StorageSpec_To_v1alpha1_StorageSpec(in *kops.StorageSpec, out *StorageSpec, s conversion.Scope) error {
+	return autoConvert_kops_StorageSpec_To_v1alpha1_StorageSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_SystemdUnitSpec_To_kops_SystemdUnitSpec(in *SystemdUnitSpec, out *kops.SystemdUnitSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]kops.InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Requires = in.Requires
+	out.After = in.After
+	out.Before = in.Before
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_v1alpha1_SystemdUnitSpec_To_kops_SystemdUnitSpec is an autogenerated conversion function.
+func Convert_v1alpha1_SystemdUnitSpec_To_kops_SystemdUnitSpec(in *SystemdUnitSpec, out *kops.SystemdUnitSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SystemdUnitSpec_To_kops_SystemdUnitSpec(in, out, s)
+}
+
+func autoConvert_kops_SystemdUnitSpec_To_v1alpha1_SystemdUnitSpec(in *kops.SystemdUnitSpec, out *SystemdUnitSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Requires = in.Requires
+	out.After = in.After
+	out.Before = in.Before
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_kops_SystemdUnitSpec_To_v1alpha1_SystemdUnitSpec is an autogenerated conversion function.
+func Convert_kops_SystemdUnitSpec_To_v1alpha1_SystemdUnitSpec(in *kops.SystemdUnitSpec, out *SystemdUnitSpec, s conversion.Scope) error {
+	return autoConvert_kops_SystemdUnitSpec_To_v1alpha1_SystemdUnitSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -4472,6 +6068,62 @@ func Convert_kops_UserData_To_v1alpha1_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha1_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha1_VaultSpec_To_kops_VaultSpec(in *VaultSpec, out *kops.VaultSpec, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.AuthMethod = in.AuthMethod
+	out.AuthMount = in.AuthMount
+	out.MasterRole = in.MasterRole
+	out.NodeRole = in.NodeRole
+	out.PKIMount = in.PKIMount
+	out.NodeCertificateRole = in.NodeCertificateRole
+	return nil
+}
+
+// Convert_v1alpha1_VaultSpec_To_kops_VaultSpec is an autogenerated conversion function.
+func Convert_v1alpha1_VaultSpec_To_kops_VaultSpec(in *VaultSpec, out *kops.VaultSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VaultSpec_To_kops_VaultSpec(in, out, s)
+}
+
+func autoConvert_kops_VaultSpec_To_v1alpha1_VaultSpec(in *kops.VaultSpec, out *VaultSpec, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.AuthMethod = in.AuthMethod
+	out.AuthMount = in.AuthMount
+	out.MasterRole = in.MasterRole
+	out.NodeRole = in.NodeRole
+	out.PKIMount = in.PKIMount
+	out.NodeCertificateRole = in.NodeCertificateRole
+	return nil
+}
+
+// Convert_kops_VaultSpec_To_v1alpha1_VaultSpec is an autogenerated conversion function.
+func Convert_kops_VaultSpec_To_v1alpha1_VaultSpec(in *kops.VaultSpec, out *VaultSpec, s conversion.Scope) error {
+	return autoConvert_kops_VaultSpec_To_v1alpha1_VaultSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(in *VaultTransitSecretEncryptionSpec, out *kops.VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	out.Address = in.Address
+	out.MountPath = in.MountPath
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_v1alpha1_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha1_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(in *VaultTransitSecretEncryptionSpec, out *kops.VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha1_VaultTransitSecretEncryptionSpec(in *kops.VaultTransitSecretEncryptionSpec, out *VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	out.Address = in.Address
+	out.MountPath = in.MountPath
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha1_VaultTransitSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha1_VaultTransitSecretEncryptionSpec(in *kops.VaultTransitSecretEncryptionSpec, out *VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha1_VaultTransitSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -110,6 +110,12 @@ type InstanceGroupSpec struct {
 	Zones []string `json:"zones,omitempty"`
 	// Hooks is a list of hooks for this instanceGroup, note: these can override the cluster wide ones if required
 	Hooks []HookSpec `json:"hooks,omitempty"`
+	// SysctlParameters are kernel parameters set on the instances, overriding the cluster ones with the same name
+	SysctlParameters []string `json:"sysctlParameters,omitempty"`
+	// KernelModules are kernel modules loaded on the instances at boot, overriding the cluster ones with the same name
+	KernelModules []KernelModuleSpec `json:"kernelModules,omitempty"`
+	// SystemdUnits are systemd units installed on the instances, overriding the cluster ones with the same name
+	SystemdUnits []SystemdUnitSpec `json:"systemdUnits,omitempty"`
 	// MaxPrice indicates this is a spot-pricing group, with the specified value as our max-price bid
 	MaxPrice *string `json:"maxPrice,omitempty"`
 	// AssociatePublicIP is true if we want instances to have a public IP
@@ -133,6 +139,10 @@ type InstanceGroupSpec struct {
 	MixedInstancesPolicy *MixedInstancesPolicySpec `json:"mixedInstancesPolicy,omitempty"`
 	// AdditionalUserData is any additional user-data to be passed to the host
 	AdditionalUserData []UserData `json:"additionalUserData,omitempty"`
+	// BootstrapFormat is the format of the user-data that bootstraps the instances: script (the default), or ignition for Flatcar and CoreOS
+	BootstrapFormat string `json:"bootstrapFormat,omitempty"`
+	// NodeHardening applies an OS hardening profile to the instances, overriding the cluster nodeHardening
+	NodeHardening *NodeHardeningSpec `json:"nodeHardening,omitempty"`
 	// SuspendProcesses disables the listed Scaling Policies
 	SuspendProcesses []string `json:"suspendProcesses,omitempty"`
 	// ExternalLoadBalancers define loadbalancers that should be attached to the instancegroup
@@ -33,6 +33,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AWSKMSSecretEncryptionSpec)(nil), (*kops.AWSKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(a.(*AWSKMSSecretEncryptionSpec), b.(*kops.AWSKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AWSKMSSecretEncryptionSpec)(nil), (*AWSKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha2_AWSKMSSecretEncryptionSpec(a.(*kops.AWSKMSSecretEncryptionSpec), b.(*AWSKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AccessSpec)(nil), (*kops.AccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AccessSpec_To_kops_AccessSpec(a.(*AccessSpec), b.(*kops.AccessSpec), scope)
 	}); err != nil {
@@ -43,6 +53,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AddonOverrideSpec)(nil), (*kops.AddonOverrideSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AddonOverrideSpec_To_kops_AddonOverrideSpec(a.(*AddonOverrideSpec), b.(*kops.AddonOverrideSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AddonOverrideSpec)(nil), (*AddonOverrideSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AddonOverrideSpec_To_v1alpha2_AddonOverrideSpec(a.(*kops.AddonOverrideSpec), b.(*AddonOverrideSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AddonSpec)(nil), (*kops.AddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AddonSpec_To_kops_AddonSpec(a.(*AddonSpec), b.(*kops.AddonSpec), scope)
 	}); err != nil {
@@ -83,6 +103,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AuditWebhookSpec)(nil), (*kops.AuditWebhookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(a.(*AuditWebhookSpec), b.(*kops.AuditWebhookSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AuditWebhookSpec)(nil), (*AuditWebhookSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(a.(*kops.AuditWebhookSpec), b.(*AuditWebhookSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AuthenticationSpec)(nil), (*kops.AuthenticationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_AuthenticationSpec_To_kops_AuthenticationSpec(a.(*AuthenticationSpec), b.(*kops.AuthenticationSpec), scope)
 	}); err != nil {
@@ -113,6 +143,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AzureConfiguration)(nil), (*kops.AzureConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_AzureConfiguration_To_kops_AzureConfiguration(a.(*AzureConfiguration), b.(*kops.AzureConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AzureConfiguration)(nil), (*AzureConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AzureConfiguration_To_v1alpha2_AzureConfiguration(a.(*kops.AzureConfiguration), b.(*AzureConfiguration), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*BastionSpec)(nil), (*kops.BastionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_BastionSpec_To_kops_BastionSpec(a.(*BastionSpec), b.(*kops.BastionSpec), scope)
 	}); err != nil {
@@ -133,6 +173,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CSISpec)(nil), (*kops.CSISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CSISpec_To_kops_CSISpec(a.(*CSISpec), b.(*kops.CSISpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.CSISpec)(nil), (*CSISpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_CSISpec_To_v1alpha2_CSISpec(a.(*kops.CSISpec), b.(*CSISpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CalicoNetworkingSpec)(nil), (*kops.CalicoNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CalicoNetworkingSpec_To_kops_CalicoNetworkingSpec(a.(*CalicoNetworkingSpec), b.(*kops.CalicoNetworkingSpec), scope)
 	}); err != nil {
@@ -153,6 +203,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ChartSpec)(nil), (*kops.ChartSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ChartSpec_To_kops_ChartSpec(a.(*ChartSpec), b.(*kops.ChartSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ChartSpec)(nil), (*ChartSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ChartSpec_To_v1alpha2_ChartSpec(a.(*kops.ChartSpec), b.(*ChartSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*CiliumNetworkingSpec)(nil), (*kops.CiliumNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CiliumNetworkingSpec_To_kops_CiliumNetworkingSpec(a.(*CiliumNetworkingSpec), b.(*kops.CiliumNetworkingSpec), scope)
 	}); err != nil {
@@ -203,6 +263,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ClusterAutoscalerConfig)(nil), (*kops.ClusterAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(a.(*ClusterAutoscalerConfig), b.(*kops.ClusterAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ClusterAutoscalerConfig)(nil), (*ClusterAutoscalerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ClusterAutoscalerConfig_To_v1alpha2_ClusterAutoscalerConfig(a.(*kops.ClusterAutoscalerConfig), b.(*ClusterAutoscalerConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ClusterList)(nil), (*kops.ClusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ClusterList_To_kops_ClusterList(a.(*ClusterList), b.(*kops.ClusterList), scope)
 	}); err != nil {
@@ -273,6 +343,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EncryptionAtRestSpec)(nil), (*kops.EncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(a.(*EncryptionAtRestSpec), b.(*kops.EncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EncryptionAtRestSpec)(nil), (*EncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(a.(*kops.EncryptionAtRestSpec), b.(*EncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EtcdBackupSpec)(nil), (*kops.EtcdBackupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_EtcdBackupSpec_To_kops_EtcdBackupSpec(a.(*EtcdBackupSpec), b.(*kops.EtcdBackupSpec), scope)
 	}); err != nil {
@@ -373,6 +453,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GCPKMSSecretEncryptionSpec)(nil), (*kops.GCPKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(a.(*GCPKMSSecretEncryptionSpec), b.(*kops.GCPKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.GCPKMSSecretEncryptionSpec)(nil), (*GCPKMSSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha2_GCPKMSSecretEncryptionSpec(a.(*kops.GCPKMSSecretEncryptionSpec), b.(*GCPKMSSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HTTPProxy)(nil), (*kops.HTTPProxy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_HTTPProxy_To_kops_HTTPProxy(a.(*HTTPProxy), b.(*kops.HTTPProxy), scope)
 	}); err != nil {
@@ -443,6 +533,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KMSEncryptionAtRestSpec)(nil), (*kops.KMSEncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(a.(*KMSEncryptionAtRestSpec), b.(*kops.KMSEncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KMSEncryptionAtRestSpec)(nil), (*KMSEncryptionAtRestSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha2_KMSEncryptionAtRestSpec(a.(*kops.KMSEncryptionAtRestSpec), b.(*KMSEncryptionAtRestSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KernelModuleSpec)(nil), (*kops.KernelModuleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KernelModuleSpec_To_kops_KernelModuleSpec(a.(*KernelModuleSpec), b.(*kops.KernelModuleSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KernelModuleSpec)(nil), (*KernelModuleSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KernelModuleSpec_To_v1alpha2_KernelModuleSpec(a.(*kops.KernelModuleSpec), b.(*KernelModuleSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Keyset)(nil), (*kops.Keyset)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Keyset_To_kops_Keyset(a.(*Keyset), b.(*kops.Keyset), scope)
 	}); err != nil {
@@ -613,6 +723,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LocalSecretEncryptionSpec)(nil), (*kops.LocalSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(a.(*LocalSecretEncryptionSpec), b.(*kops.LocalSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.LocalSecretEncryptionSpec)(nil), (*LocalSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_LocalSecretEncryptionSpec_To_v1alpha2_LocalSecretEncryptionSpec(a.(*kops.LocalSecretEncryptionSpec), b.(*LocalSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LyftVPCNetworkingSpec)(nil), (*kops.LyftVPCNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_LyftVPCNetworkingSpec_To_kops_LyftVPCNetworkingSpec(a.(*LyftVPCNetworkingSpec), b.(*kops.LyftVPCNetworkingSpec), scope)
 	}); err != nil {
@@ -663,6 +783,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeHardeningSpec)(nil), (*kops.NodeHardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeHardeningSpec_To_kops_NodeHardeningSpec(a.(*NodeHardeningSpec), b.(*kops.NodeHardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeHardeningSpec)(nil), (*NodeHardeningSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeHardeningSpec_To_v1alpha2_NodeHardeningSpec(a.(*kops.NodeHardeningSpec), b.(*NodeHardeningSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeReconcileSpec)(nil), (*kops.NodeReconcileSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_NodeReconcileSpec_To_kops_NodeReconcileSpec(a.(*NodeReconcileSpec), b.(*kops.NodeReconcileSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeReconcileSpec)(nil), (*NodeReconcileSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeReconcileSpec_To_v1alpha2_NodeReconcileSpec(a.(*kops.NodeReconcileSpec), b.(*NodeReconcileSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OpenstackBlockStorageConfig)(nil), (*kops.OpenstackBlockStorageConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(a.(*OpenstackBlockStorageConfig), b.(*kops.OpenstackBlockStorageConfig), scope)
 	}); err != nil {
@@ -763,6 +903,86 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPlugin)(nil), (*kops.SchedulerPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SchedulerPlugin_To_kops_SchedulerPlugin(a.(*SchedulerPlugin), b.(*kops.SchedulerPlugin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPlugin)(nil), (*SchedulerPlugin)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPlugin_To_v1alpha2_SchedulerPlugin(a.(*kops.SchedulerPlugin), b.(*SchedulerPlugin), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPluginConfig)(nil), (*kops.SchedulerPluginConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(a.(*SchedulerPluginConfig), b.(*kops.SchedulerPluginConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPluginConfig)(nil), (*SchedulerPluginConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPluginConfig_To_v1alpha2_SchedulerPluginConfig(a.(*kops.SchedulerPluginConfig), b.(*SchedulerPluginConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPluginSet)(nil), (*kops.SchedulerPluginSet)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(a.(*SchedulerPluginSet), b.(*kops.SchedulerPluginSet), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPluginSet)(nil), (*SchedulerPluginSet)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(a.(*kops.SchedulerPluginSet), b.(*SchedulerPluginSet), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerPlugins)(nil), (*kops.SchedulerPlugins)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SchedulerPlugins_To_kops_SchedulerPlugins(a.(*SchedulerPlugins), b.(*kops.SchedulerPlugins), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerPlugins)(nil), (*SchedulerPlugins)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerPlugins_To_v1alpha2_SchedulerPlugins(a.(*kops.SchedulerPlugins), b.(*SchedulerPlugins), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerProfile)(nil), (*kops.SchedulerProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SchedulerProfile_To_kops_SchedulerProfile(a.(*SchedulerProfile), b.(*kops.SchedulerProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SchedulerProfile)(nil), (*SchedulerProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SchedulerProfile_To_v1alpha2_SchedulerProfile(a.(*kops.SchedulerProfile), b.(*SchedulerProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecretEncryptionSpec)(nil), (*kops.SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(a.(*SecretEncryptionSpec), b.(*kops.SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SecretEncryptionSpec)(nil), (*SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(a.(*kops.SecretEncryptionSpec), b.(*SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StorageSpec)(nil), (*kops.StorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_StorageSpec_To_kops_StorageSpec(a.(*StorageSpec), b.(*kops.StorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.StorageSpec)(nil), (*StorageSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_StorageSpec_To_v1alpha2_StorageSpec(a.(*kops.StorageSpec), b.(*StorageSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SystemdUnitSpec)(nil), (*kops.SystemdUnitSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(a.(*SystemdUnitSpec), b.(*kops.SystemdUnitSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SystemdUnitSpec)(nil), (*SystemdUnitSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(a.(*kops.SystemdUnitSpec), b.(*SystemdUnitSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -803,6 +1023,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*VaultSpec)(nil), (*kops.VaultSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_VaultSpec_To_kops_VaultSpec(a.(*VaultSpec), b.(*kops.VaultSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.VaultSpec)(nil), (*VaultSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_VaultSpec_To_v1alpha2_VaultSpec(a.(*kops.VaultSpec), b.(*VaultSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VaultTransitSecretEncryptionSpec)(nil), (*kops.VaultTransitSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(a.(*VaultTransitSecretEncryptionSpec), b.(*kops.VaultTransitSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.VaultTransitSecretEncryptionSpec)(nil), (*VaultTransitSecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha2_VaultTransitSecretEncryptionSpec(a.(*kops.VaultTransitSecretEncryptionSpec), b.(*VaultTransitSecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -836,6 +1076,28 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha2_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(in *AWSKMSSecretEncryptionSpec, out *kops.AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_v1alpha2_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(in *AWSKMSSecretEncryptionSpec, out *kops.AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha2_AWSKMSSecretEncryptionSpec(in *kops.AWSKMSSecretEncryptionSpec, out *AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	return nil
+}
+
+// Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha2_AWSKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha2_AWSKMSSecretEncryptionSpec(in *kops.AWSKMSSecretEncryptionSpec, out *AWSKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha2_AWSKMSSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AccessSpec_To_kops_AccessSpec(in *AccessSpec, out *kops.AccessSpec, s conversion.Scope) error {
 	if in.DNS != nil {
 		in, out := &in.DNS, &out.DNS
@@ -890,8 +1152,48 @@ func Convert_kops_AccessSpec_To_v1alpha2_AccessSpec(in *kops.AccessSpec, out *Ac
 	return autoConvert_kops_AccessSpec_To_v1alpha2_AccessSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_AddonOverrideSpec_To_kops_AddonOverrideSpec(in *AddonOverrideSpec, out *kops.AddonOverrideSpec, s conversion.Scope) error {
+	out.Addon = in.Addon
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.Type = in.Type
+	out.Patch = in.Patch
+	return nil
+}
+
+// Convert_v1alpha2_AddonOverrideSpec_To_kops_AddonOverrideSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AddonOverrideSpec_To_kops_AddonOverrideSpec(in *AddonOverrideSpec, out *kops.AddonOverrideSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AddonOverrideSpec_To_kops_AddonOverrideSpec(in, out, s)
+}
+
+func autoConvert_kops_AddonOverrideSpec_To_v1alpha2_AddonOverrideSpec(in *kops.AddonOverrideSpec, out *AddonOverrideSpec, s conversion.Scope) error {
+	out.Addon = in.Addon
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.Type = in.Type
+	out.Patch = in.Patch
+	return nil
+}
+
+// Convert_kops_AddonOverrideSpec_To_v1alpha2_AddonOverrideSpec is an autogenerated conversion function.
+func Convert_kops_AddonOverrideSpec_To_v1alpha2_AddonOverrideSpec(in *kops.AddonOverrideSpec, out *AddonOverrideSpec, s conversion.Scope) error {
+	return autoConvert_kops_AddonOverrideSpec_To_v1alpha2_AddonOverrideSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	out.Name = in.Name
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(kops.ChartSpec)
+		if err := Convert_v1alpha2_ChartSpec_To_kops_ChartSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -902,6 +1204,16 @@ func Convert_v1alpha2_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.Addon
 
 func autoConvert_kops_AddonSpec_To_v1alpha2_AddonSpec(in *kops.AddonSpec, out *AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	out.Name = in.Name
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(ChartSpec)
+		if err := Convert_kops_ChartSpec_To_v1alpha2_ChartSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -952,6 +1264,8 @@ func autoConvert_v1alpha2_Assets_To_kops_Assets(in *Assets, out *kops.Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.FileSignatureKey = in.FileSignatureKey
 	return nil
 }
 
@@ -964,6 +1278,8 @@ func autoConvert_kops_Assets_To_v1alpha2_Assets(in *kops.Assets, out *Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	out.PinImageDigests = in.PinImageDigests
+	out.FileSignatureKey = in.FileSignatureKey
 	return nil
 }
 
@@ -972,6 +1288,30 @@ func Convert_kops_Assets_To_v1alpha2_Assets(in *kops.Assets, out *Assets, s conv
 	return autoConvert_kops_Assets_To_v1alpha2_Assets(in, out, s)
 }
 
+func autoConvert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(in *AuditWebhookSpec, out *kops.AuditWebhookSpec, s conversion.Scope) error {
+	out.Server = in.Server
+	out.CACertificate = in.CACertificate
+	out.TokenSecret = in.TokenSecret
+	return nil
+}
+
+// Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec is an autogenerated conversion function.
+func Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(in *AuditWebhookSpec, out *kops.AuditWebhookSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(in, out, s)
+}
+
+func autoConvert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(in *kops.AuditWebhookSpec, out *AuditWebhookSpec, s conversion.Scope) error {
+	out.Server = in.Server
+	out.CACertificate = in.CACertificate
+	out.TokenSecret = in.TokenSecret
+	return nil
+}
+
+// Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec is an autogenerated conversion function.
+func Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(in *kops.AuditWebhookSpec, out *AuditWebhookSpec, s conversion.Scope) error {
+	return autoConvert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_AuthenticationSpec_To_kops_AuthenticationSpec(in *AuthenticationSpec, out *kops.AuthenticationSpec, s conversion.Scope) error {
 	if in.Kopeio != nil {
 		in, out := &in.Kopeio, &out.Kopeio
@@ -1108,6 +1448,34 @@ func Convert_kops_AwsAuthenticationSpec_To_v1alpha2_AwsAuthenticationSpec(in *ko
 	return autoConvert_kops_AwsAuthenticationSpec_To_v1alpha2_AwsAuthenticationSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_AzureConfiguration_To_kops_AzureConfiguration(in *AzureConfiguration, out *kops.AzureConfiguration, s conversion.Scope) error {
+	out.SubscriptionID = in.SubscriptionID
+	out.TenantID = in.TenantID
+	out.ResourceGroupName = in.ResourceGroupName
+	out.RouteTableName = in.RouteTableName
+	out.AdminUser = in.AdminUser
+	return nil
+}
+
+// Convert_v1alpha2_AzureConfiguration_To_kops_AzureConfiguration is an autogenerated conversion function.
+func Convert_v1alpha2_AzureConfiguration_To_kops_AzureConfiguration(in *AzureConfiguration, out *kops.AzureConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha2_AzureConfiguration_To_kops_AzureConfiguration(in, out, s)
+}
+
+func autoConvert_kops_AzureConfiguration_To_v1alpha2_AzureConfiguration(in *kops.AzureConfiguration, out *AzureConfiguration, s conversion.Scope) error {
+	out.SubscriptionID = in.SubscriptionID
+	out.TenantID = in.TenantID
+	out.ResourceGroupName = in.ResourceGroupName
+	out.RouteTableName = in.RouteTableName
+	out.AdminUser = in.AdminUser
+	return nil
+}
+
+// Convert_kops_AzureConfiguration_To_v1alpha2_AzureConfiguration is an autogenerated conversion function.
+func Convert_kops_AzureConfiguration_To_v1alpha2_AzureConfiguration(in *kops.AzureConfiguration, out *AzureConfiguration, s conversion.Scope) error {
+	return autoConvert_kops_AzureConfiguration_To_v1alpha2_AzureConfiguration(in, out, s)
+}
+
 func autoConvert_v1alpha2_BastionSpec_To_kops_BastionSpec(in *BastionSpec, out *kops.BastionSpec, s conversion.Scope) error {
 	out.BastionPublicName = in.BastionPublicName
 	out.IdleTimeoutSeconds = in.IdleTimeoutSeconds
@@ -1150,6 +1518,28 @@ func Convert_kops_CNINetworkingSpec_To_v1alpha2_CNINetworkingSpec(in *kops.CNINe
 	return autoConvert_kops_CNINetworkingSpec_To_v1alpha2_CNINetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_CSISpec_To_kops_CSISpec(in *CSISpec, out *kops.CSISpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1alpha2_CSISpec_To_kops_CSISpec is an autogenerated conversion function.
+func Convert_v1alpha2_CSISpec_To_kops_CSISpec(in *CSISpec, out *kops.CSISpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CSISpec_To_kops_CSISpec(in, out, s)
+}
+
+func autoConvert_kops_CSISpec_To_v1alpha2_CSISpec(in *kops.CSISpec, out *CSISpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_kops_CSISpec_To_v1alpha2_CSISpec is an autogenerated conversion function.
+func Convert_kops_CSISpec_To_v1alpha2_CSISpec(in *kops.CSISpec, out *CSISpec, s conversion.Scope) error {
+	return autoConvert_kops_CSISpec_To_v1alpha2_CSISpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_CalicoNetworkingSpec_To_kops_CalicoNetworkingSpec(in *CalicoNetworkingSpec, out *kops.CalicoNetworkingSpec, s conversion.Scope) error {
 	out.CrossSubnet = in.CrossSubnet
 	out.LogSeverityScreen = in.LogSeverityScreen
@@ -1220,6 +1610,36 @@ func Convert_kops_CanalNetworkingSpec_To_v1alpha2_CanalNetworkingSpec(in *kops.C
 	return autoConvert_kops_CanalNetworkingSpec_To_v1alpha2_CanalNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ChartSpec_To_kops_ChartSpec(in *ChartSpec, out *kops.ChartSpec, s conversion.Scope) error {
+	out.Repository = in.Repository
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Namespace = in.Namespace
+	out.ReleaseName = in.ReleaseName
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_v1alpha2_ChartSpec_To_kops_ChartSpec is an autogenerated conversion function.
+func Convert_v1alpha2_ChartSpec_To_kops_ChartSpec(in *ChartSpec, out *kops.ChartSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ChartSpec_To_kops_ChartSpec(in, out, s)
+}
+
+func autoConvert_kops_ChartSpec_To_v1alpha2_ChartSpec(in *kops.ChartSpec, out *ChartSpec, s conversion.Scope) error {
+	out.Repository = in.Repository
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Namespace = in.Namespace
+	out.ReleaseName = in.ReleaseName
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_kops_ChartSpec_To_v1alpha2_ChartSpec is an autogenerated conversion function.
+func Convert_kops_ChartSpec_To_v1alpha2_ChartSpec(in *kops.ChartSpec, out *ChartSpec, s conversion.Scope) error {
+	return autoConvert_kops_ChartSpec_To_v1alpha2_ChartSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_CiliumNetworkingSpec_To_kops_CiliumNetworkingSpec(in *CiliumNetworkingSpec, out *kops.CiliumNetworkingSpec, s conversion.Scope) error {
 	out.Version = in.Version
 	out.AccessLog = in.AccessLog
@@ -1378,6 +1798,15 @@ func autoConvert_v1alpha2_CloudConfiguration_To_kops_CloudConfiguration(in *Clou
 	} else {
 		out.Openstack = nil
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(kops.AzureConfiguration)
+		if err := Convert_v1alpha2_AzureConfiguration_To_kops_AzureConfiguration(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Azure = nil
+	}
 	return nil
 }
 
@@ -1410,6 +1839,15 @@ func autoConvert_kops_CloudConfiguration_To_v1alpha2_CloudConfiguration(in *kops
 	} else {
 		out.Openstack = nil
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureConfiguration)
+		if err := Convert_kops_AzureConfiguration_To_v1alpha2_AzureConfiguration(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Azure = nil
+	}
 	return nil
 }
 
@@ -1500,6 +1938,48 @@ func Convert_kops_Cluster_To_v1alpha2_Cluster(in *kops.Cluster, out *Cluster, s
 	return autoConvert_kops_Cluster_To_v1alpha2_Cluster(in, out, s)
 }
 
+func autoConvert_v1alpha2_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(in *ClusterAutoscalerConfig, out *kops.ClusterAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Expander = in.Expander
+	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	out.ScaleDownEnabled = in.ScaleDownEnabled
+	out.ScaleDownUtilizationThreshold = in.ScaleDownUtilizationThreshold
+	out.ScaleDownUnneededTime = in.ScaleDownUnneededTime
+	out.ScaleDownDelayAfterAdd = in.ScaleDownDelayAfterAdd
+	out.SkipNodesWithLocalStorage = in.SkipNodesWithLocalStorage
+	out.SkipNodesWithSystemPods = in.SkipNodesWithSystemPods
+	out.Image = in.Image
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	return nil
+}
+
+// Convert_v1alpha2_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(in *ClusterAutoscalerConfig, out *kops.ClusterAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(in, out, s)
+}
+
+func autoConvert_kops_ClusterAutoscalerConfig_To_v1alpha2_ClusterAutoscalerConfig(in *kops.ClusterAutoscalerConfig, out *ClusterAutoscalerConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Expander = in.Expander
+	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	out.ScaleDownEnabled = in.ScaleDownEnabled
+	out.ScaleDownUtilizationThreshold = in.ScaleDownUtilizationThreshold
+	out.ScaleDownUnneededTime = in.ScaleDownUnneededTime
+	out.ScaleDownDelayAfterAdd = in.ScaleDownDelayAfterAdd
+	out.SkipNodesWithLocalStorage = in.SkipNodesWithLocalStorage
+	out.SkipNodesWithSystemPods = in.SkipNodesWithSystemPods
+	out.Image = in.Image
+	out.MemoryRequest = in.MemoryRequest
+	out.CPURequest = in.CPURequest
+	return nil
+}
+
+// Convert_kops_ClusterAutoscalerConfig_To_v1alpha2_ClusterAutoscalerConfig is an autogenerated conversion function.
+func Convert_kops_ClusterAutoscalerConfig_To_v1alpha2_ClusterAutoscalerConfig(in *kops.ClusterAutoscalerConfig, out *ClusterAutoscalerConfig, s conversion.Scope) error {
+	return autoConvert_kops_ClusterAutoscalerConfig_To_v1alpha2_ClusterAutoscalerConfig(in, out, s)
+}
+
 func autoConvert_v1alpha2_ClusterList_To_kops_ClusterList(in *ClusterList, out *kops.ClusterList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -1555,8 +2035,19 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Addons = nil
 	}
-	out.ConfigBase = in.ConfigBase
-	out.CloudProvider = in.CloudProvider
+	if in.AddonOverrides != nil {
+		in, out := &in.AddonOverrides, &out.AddonOverrides
+		*out = make([]kops.AddonOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_AddonOverrideSpec_To_kops_AddonOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AddonOverrides = nil
+	}
+	out.ConfigBase = in.ConfigBase
+	out.CloudProvider = in.CloudProvider
 	out.KubernetesVersion = in.KubernetesVersion
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
@@ -1586,6 +2077,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(kops.VaultSpec)
+		if err := Convert_v1alpha2_VaultSpec_To_kops_VaultSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	out.AdditionalSANs = in.AdditionalSANs
@@ -1731,6 +2231,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.ExternalDNS = nil
 	}
+	if in.ClusterAutoscaler != nil {
+		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
+		*out = new(kops.ClusterAutoscalerConfig)
+		if err := Convert_v1alpha2_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClusterAutoscaler = nil
+	}
 	if in.Networking != nil {
 		in, out := &in.Networking, &out.Networking
 		*out = new(kops.NetworkingSpec)
@@ -1740,6 +2249,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Networking = nil
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(kops.StorageSpec)
+		if err := Convert_v1alpha2_StorageSpec_To_kops_StorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Storage = nil
+	}
 	if in.API != nil {
 		in, out := &in.API, &out.API
 		*out = new(kops.AccessSpec)
@@ -1776,6 +2294,24 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.NodeAuthorization = nil
 	}
+	if in.NodeReconcile != nil {
+		in, out := &in.NodeReconcile, &out.NodeReconcile
+		*out = new(kops.NodeReconcileSpec)
+		if err := Convert_v1alpha2_NodeReconcileSpec_To_kops_NodeReconcileSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconcile = nil
+	}
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(kops.NodeHardeningSpec)
+		if err := Convert_v1alpha2_NodeHardeningSpec_To_kops_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -1788,6 +2324,29 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]kops.KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KernelModuleSpec_To_kops_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]kops.SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(kops.Assets)
@@ -1807,6 +2366,24 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(kops.EncryptionAtRestSpec)
+		if err := Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EncryptionAtRest = nil
+	}
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(kops.SecretEncryptionSpec)
+		if err := Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.DisableSubnetTags = in.DisableSubnetTags
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
@@ -1838,6 +2415,17 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Addons = nil
 	}
+	if in.AddonOverrides != nil {
+		in, out := &in.AddonOverrides, &out.AddonOverrides
+		*out = make([]AddonOverrideSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_AddonOverrideSpec_To_v1alpha2_AddonOverrideSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AddonOverrides = nil
+	}
 	out.ConfigBase = in.ConfigBase
 	out.CloudProvider = in.CloudProvider
 	out.KubernetesVersion = in.KubernetesVersion
@@ -1869,6 +2457,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(VaultSpec)
+		if err := Convert_kops_VaultSpec_To_v1alpha2_VaultSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Vault = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	out.AdditionalSANs = in.AdditionalSANs
@@ -2014,6 +2611,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.ExternalDNS = nil
 	}
+	if in.ClusterAutoscaler != nil {
+		in, out := &in.ClusterAutoscaler, &out.ClusterAutoscaler
+		*out = new(ClusterAutoscalerConfig)
+		if err := Convert_kops_ClusterAutoscalerConfig_To_v1alpha2_ClusterAutoscalerConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.ClusterAutoscaler = nil
+	}
 	if in.Networking != nil {
 		in, out := &in.Networking, &out.Networking
 		*out = new(NetworkingSpec)
@@ -2023,6 +2629,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Networking = nil
 	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(StorageSpec)
+		if err := Convert_kops_StorageSpec_To_v1alpha2_StorageSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Storage = nil
+	}
 	if in.API != nil {
 		in, out := &in.API, &out.API
 		*out = new(AccessSpec)
@@ -2059,6 +2674,24 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.NodeAuthorization = nil
 	}
+	if in.NodeReconcile != nil {
+		in, out := &in.NodeReconcile, &out.NodeReconcile
+		*out = new(NodeReconcileSpec)
+		if err := Convert_kops_NodeReconcileSpec_To_v1alpha2_NodeReconcileSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeReconcile = nil
+	}
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(NodeHardeningSpec)
+		if err := Convert_kops_NodeHardeningSpec_To_v1alpha2_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -2071,6 +2704,29 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KernelModuleSpec_To_v1alpha2_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	if in.Assets != nil {
 		in, out := &in.Assets, &out.Assets
 		*out = new(Assets)
@@ -2090,6 +2746,24 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 		out.IAM = nil
 	}
 	out.EncryptionConfig = in.EncryptionConfig
+	if in.EncryptionAtRest != nil {
+		in, out := &in.EncryptionAtRest, &out.EncryptionAtRest
+		*out = new(EncryptionAtRestSpec)
+		if err := Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.EncryptionAtRest = nil
+	}
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(SecretEncryptionSpec)
+		if err := Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.DisableSubnetTags = in.DisableSubnetTags
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
@@ -2270,6 +2944,46 @@ func Convert_kops_EgressProxySpec_To_v1alpha2_EgressProxySpec(in *kops.EgressPro
 	return autoConvert_kops_EgressProxySpec_To_v1alpha2_EgressProxySpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in *EncryptionAtRestSpec, out *kops.EncryptionAtRestSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.Resources = in.Resources
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(kops.KMSEncryptionAtRestSpec)
+		if err := Convert_v1alpha2_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KMS = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in *EncryptionAtRestSpec, out *kops.EncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EncryptionAtRestSpec_To_kops_EncryptionAtRestSpec(in, out, s)
+}
+
+func autoConvert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(in *kops.EncryptionAtRestSpec, out *EncryptionAtRestSpec, s conversion.Scope) error {
+	out.Provider = in.Provider
+	out.Resources = in.Resources
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(KMSEncryptionAtRestSpec)
+		if err := Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha2_KMSEncryptionAtRestSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KMS = nil
+	}
+	return nil
+}
+
+// Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(in *kops.EncryptionAtRestSpec, out *EncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_kops_EncryptionAtRestSpec_To_v1alpha2_EncryptionAtRestSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_EtcdBackupSpec_To_kops_EtcdBackupSpec(in *EtcdBackupSpec, out *kops.EtcdBackupSpec, s conversion.Scope) error {
 	out.BackupStore = in.BackupStore
 	out.Image = in.Image
@@ -2592,6 +3306,26 @@ func Convert_kops_GCENetworkingSpec_To_v1alpha2_GCENetworkingSpec(in *kops.GCENe
 	return autoConvert_kops_GCENetworkingSpec_To_v1alpha2_GCENetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(in *GCPKMSSecretEncryptionSpec, out *kops.GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_v1alpha2_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(in *GCPKMSSecretEncryptionSpec, out *kops.GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha2_GCPKMSSecretEncryptionSpec(in *kops.GCPKMSSecretEncryptionSpec, out *GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha2_GCPKMSSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha2_GCPKMSSecretEncryptionSpec(in *kops.GCPKMSSecretEncryptionSpec, out *GCPKMSSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha2_GCPKMSSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_HTTPProxy_To_kops_HTTPProxy(in *HTTPProxy, out *kops.HTTPProxy, s conversion.Scope) error {
 	out.Host = in.Host
 	out.Port = in.Port
@@ -2837,6 +3571,29 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]kops.KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_KernelModuleSpec_To_kops_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]kops.SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.AssociatePublicIP = in.AssociatePublicIP
 	out.AdditionalSecurityGroups = in.AdditionalSecurityGroups
@@ -2884,6 +3641,16 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.BootstrapFormat = in.BootstrapFormat
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(kops.NodeHardeningSpec)
+		if err := Convert_v1alpha2_NodeHardeningSpec_To_kops_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
@@ -2963,6 +3730,29 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.Hooks = nil
 	}
+	out.SysctlParameters = in.SysctlParameters
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]KernelModuleSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_KernelModuleSpec_To_v1alpha2_KernelModuleSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.KernelModules = nil
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = make([]SystemdUnitSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.SystemdUnits = nil
+	}
 	out.MaxPrice = in.MaxPrice
 	out.AssociatePublicIP = in.AssociatePublicIP
 	out.AdditionalSecurityGroups = in.AdditionalSecurityGroups
@@ -3010,6 +3800,16 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.BootstrapFormat = in.BootstrapFormat
+	if in.NodeHardening != nil {
+		in, out := &in.NodeHardening, &out.NodeHardening
+		*out = new(NodeHardeningSpec)
+		if err := Convert_kops_NodeHardeningSpec_To_v1alpha2_NodeHardeningSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeHardening = nil
+	}
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
@@ -3042,6 +3842,72 @@ func Convert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.Insta
 	return autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(in *KMSEncryptionAtRestSpec, out *kops.KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	out.Image = in.Image
+	out.CacheSize = in.CacheSize
+	return nil
+}
+
+// Convert_v1alpha2_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_v1alpha2_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(in *KMSEncryptionAtRestSpec, out *kops.KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KMSEncryptionAtRestSpec_To_kops_KMSEncryptionAtRestSpec(in, out, s)
+}
+
+func autoConvert_kops_KMSEncryptionAtRestSpec_To_v1alpha2_KMSEncryptionAtRestSpec(in *kops.KMSEncryptionAtRestSpec, out *KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Region = in.Region
+	out.Image = in.Image
+	out.CacheSize = in.CacheSize
+	return nil
+}
+
+// Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha2_KMSEncryptionAtRestSpec is an autogenerated conversion function.
+func Convert_kops_KMSEncryptionAtRestSpec_To_v1alpha2_KMSEncryptionAtRestSpec(in *kops.KMSEncryptionAtRestSpec, out *KMSEncryptionAtRestSpec, s conversion.Scope) error {
+	return autoConvert_kops_KMSEncryptionAtRestSpec_To_v1alpha2_KMSEncryptionAtRestSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_KernelModuleSpec_To_kops_KernelModuleSpec(in *KernelModuleSpec, out *kops.KernelModuleSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]kops.InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Parameters = in.Parameters
+	return nil
+}
+
+// Convert_v1alpha2_KernelModuleSpec_To_kops_KernelModuleSpec is an autogenerated conversion function.
+func Convert_v1alpha2_KernelModuleSpec_To_kops_KernelModuleSpec(in *KernelModuleSpec, out *kops.KernelModuleSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KernelModuleSpec_To_kops_KernelModuleSpec(in, out, s)
+}
+
+func autoConvert_kops_KernelModuleSpec_To_v1alpha2_KernelModuleSpec(in *kops.KernelModuleSpec, out *KernelModuleSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Parameters = in.Parameters
+	return nil
+}
+
+// Convert_kops_KernelModuleSpec_To_v1alpha2_KernelModuleSpec is an autogenerated conversion function.
+func Convert_kops_KernelModuleSpec_To_v1alpha2_KernelModuleSpec(in *kops.KernelModuleSpec, out *KernelModuleSpec, s conversion.Scope) error {
+	return autoConvert_kops_KernelModuleSpec_To_v1alpha2_KernelModuleSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_Keyset_To_kops_Keyset(in *Keyset, out *kops.Keyset, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_KeysetSpec_To_kops_KeysetSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -3284,6 +4150,16 @@ func autoConvert_v1alpha2_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(in *Ku
 	out.AuditWebhookConfigFile = in.AuditWebhookConfigFile
 	out.AuditWebhookInitialBackoff = in.AuditWebhookInitialBackoff
 	out.AuditWebhookMode = in.AuditWebhookMode
+	out.AuditPolicy = in.AuditPolicy
+	if in.AuditWebhook != nil {
+		in, out := &in.AuditWebhook, &out.AuditWebhook
+		*out = new(kops.AuditWebhookSpec)
+		if err := Convert_v1alpha2_AuditWebhookSpec_To_kops_AuditWebhookSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AuditWebhook = nil
+	}
 	out.AuthenticationTokenWebhookConfigFile = in.AuthenticationTokenWebhookConfigFile
 	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
 	out.AuthorizationMode = in.AuthorizationMode
@@ -3292,6 +4168,7 @@ func autoConvert_v1alpha2_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(in *Ku
 	out.AuthorizationWebhookCacheUnauthorizedTTL = in.AuthorizationWebhookCacheUnauthorizedTTL
 	out.AuthorizationRBACSuperUser = in.AuthorizationRBACSuperUser
 	out.ExperimentalEncryptionProviderConfig = in.ExperimentalEncryptionProviderConfig
+	out.EncryptionProviderConfig = in.EncryptionProviderConfig
 	out.RequestheaderUsernameHeaders = in.RequestheaderUsernameHeaders
 	out.RequestheaderGroupHeaders = in.RequestheaderGroupHeaders
 	out.RequestheaderExtraHeaderPrefixes = in.RequestheaderExtraHeaderPrefixes
@@ -3378,6 +4255,16 @@ func autoConvert_kops_KubeAPIServerConfig_To_v1alpha2_KubeAPIServerConfig(in *ko
 	out.AuditWebhookConfigFile = in.AuditWebhookConfigFile
 	out.AuditWebhookInitialBackoff = in.AuditWebhookInitialBackoff
 	out.AuditWebhookMode = in.AuditWebhookMode
+	out.AuditPolicy = in.AuditPolicy
+	if in.AuditWebhook != nil {
+		in, out := &in.AuditWebhook, &out.AuditWebhook
+		*out = new(AuditWebhookSpec)
+		if err := Convert_kops_AuditWebhookSpec_To_v1alpha2_AuditWebhookSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AuditWebhook = nil
+	}
 	out.AuthenticationTokenWebhookConfigFile = in.AuthenticationTokenWebhookConfigFile
 	out.AuthenticationTokenWebhookCacheTTL = in.AuthenticationTokenWebhookCacheTTL
 	out.AuthorizationMode = in.AuthorizationMode
@@ -3386,6 +4273,7 @@ func autoConvert_kops_KubeAPIServerConfig_To_v1alpha2_KubeAPIServerConfig(in *ko
 	out.AuthorizationWebhookCacheUnauthorizedTTL = in.AuthorizationWebhookCacheUnauthorizedTTL
 	out.AuthorizationRBACSuperUser = in.AuthorizationRBACSuperUser
 	out.ExperimentalEncryptionProviderConfig = in.ExperimentalEncryptionProviderConfig
+	out.EncryptionProviderConfig = in.EncryptionProviderConfig
 	out.RequestheaderUsernameHeaders = in.RequestheaderUsernameHeaders
 	out.RequestheaderGroupHeaders = in.RequestheaderGroupHeaders
 	out.RequestheaderExtraHeaderPrefixes = in.RequestheaderExtraHeaderPrefixes
@@ -3569,6 +4457,11 @@ func autoConvert_v1alpha2_KubeProxyConfig_To_kops_KubeProxyConfig(in *KubeProxyC
 	out.FeatureGates = in.FeatureGates
 	out.ConntrackMaxPerCore = in.ConntrackMaxPerCore
 	out.ConntrackMin = in.ConntrackMin
+	out.IPVSStrictARP = in.IPVSStrictARP
+	out.IPVSTCPTimeout = in.IPVSTCPTimeout
+	out.IPVSTCPFinTimeout = in.IPVSTCPFinTimeout
+	out.IPVSUDPTimeout = in.IPVSUDPTimeout
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3598,6 +4491,11 @@ func autoConvert_kops_KubeProxyConfig_To_v1alpha2_KubeProxyConfig(in *kops.KubeP
 	out.FeatureGates = in.FeatureGates
 	out.ConntrackMaxPerCore = in.ConntrackMaxPerCore
 	out.ConntrackMin = in.ConntrackMin
+	out.IPVSStrictARP = in.IPVSStrictARP
+	out.IPVSTCPTimeout = in.IPVSTCPTimeout
+	out.IPVSTCPFinTimeout = in.IPVSTCPFinTimeout
+	out.IPVSUDPTimeout = in.IPVSUDPTimeout
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3621,6 +4519,18 @@ func autoConvert_v1alpha2_KubeSchedulerConfig_To_kops_KubeSchedulerConfig(in *Ku
 	}
 	out.UsePolicyConfigMap = in.UsePolicyConfigMap
 	out.FeatureGates = in.FeatureGates
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]kops.SchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SchedulerProfile_To_kops_SchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3644,6 +4554,18 @@ func autoConvert_kops_KubeSchedulerConfig_To_v1alpha2_KubeSchedulerConfig(in *ko
 	}
 	out.UsePolicyConfigMap = in.UsePolicyConfigMap
 	out.FeatureGates = in.FeatureGates
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]SchedulerProfile, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerProfile_To_v1alpha2_SchedulerProfile(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Profiles = nil
+	}
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3728,6 +4650,7 @@ func autoConvert_v1alpha2_KubeletConfigSpec_To_kops_KubeletConfigSpec(in *Kubele
 	out.CpuManagerPolicy = in.CpuManagerPolicy
 	out.RegistryPullQPS = in.RegistryPullQPS
 	out.RegistryBurst = in.RegistryBurst
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3812,6 +4735,7 @@ func autoConvert_kops_KubeletConfigSpec_To_v1alpha2_KubeletConfigSpec(in *kops.K
 	out.CpuManagerPolicy = in.CpuManagerPolicy
 	out.RegistryPullQPS = in.RegistryPullQPS
 	out.RegistryBurst = in.RegistryBurst
+	out.ConfigOverrides = in.ConfigOverrides
 	return nil
 }
 
@@ -3930,6 +4854,24 @@ func Convert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(in *
 	return autoConvert_kops_LoadBalancerAccessSpec_To_v1alpha2_LoadBalancerAccessSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(in *LocalSecretEncryptionSpec, out *kops.LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_v1alpha2_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(in *LocalSecretEncryptionSpec, out *kops.LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_LocalSecretEncryptionSpec_To_v1alpha2_LocalSecretEncryptionSpec(in *kops.LocalSecretEncryptionSpec, out *LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return nil
+}
+
+// Convert_kops_LocalSecretEncryptionSpec_To_v1alpha2_LocalSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_LocalSecretEncryptionSpec_To_v1alpha2_LocalSecretEncryptionSpec(in *kops.LocalSecretEncryptionSpec, out *LocalSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_LocalSecretEncryptionSpec_To_v1alpha2_LocalSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_LyftVPCNetworkingSpec_To_kops_LyftVPCNetworkingSpec(in *LyftVPCNetworkingSpec, out *kops.LyftVPCNetworkingSpec, s conversion.Scope) error {
 	out.SubnetTags = in.SubnetTags
 	return nil
@@ -4338,6 +5280,64 @@ func Convert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in *kops.Nod
 	return autoConvert_kops_NodeAuthorizerSpec_To_v1alpha2_NodeAuthorizerSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_NodeHardeningSpec_To_kops_NodeHardeningSpec(in *NodeHardeningSpec, out *kops.NodeHardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	out.Sysctls = in.Sysctls
+	out.BlacklistedModules = in.BlacklistedModules
+	out.AllowedModules = in.AllowedModules
+	out.AuditRules = in.AuditRules
+	out.SSHD = in.SSHD
+	out.DisabledServices = in.DisabledServices
+	out.EnabledServices = in.EnabledServices
+	out.FileModes = in.FileModes
+	return nil
+}
+
+// Convert_v1alpha2_NodeHardeningSpec_To_kops_NodeHardeningSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeHardeningSpec_To_kops_NodeHardeningSpec(in *NodeHardeningSpec, out *kops.NodeHardeningSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeHardeningSpec_To_kops_NodeHardeningSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeHardeningSpec_To_v1alpha2_NodeHardeningSpec(in *kops.NodeHardeningSpec, out *NodeHardeningSpec, s conversion.Scope) error {
+	out.Profile = in.Profile
+	out.Sysctls = in.Sysctls
+	out.BlacklistedModules = in.BlacklistedModules
+	out.AllowedModules = in.AllowedModules
+	out.AuditRules = in.AuditRules
+	out.SSHD = in.SSHD
+	out.DisabledServices = in.DisabledServices
+	out.EnabledServices = in.EnabledServices
+	out.FileModes = in.FileModes
+	return nil
+}
+
+// Convert_kops_NodeHardeningSpec_To_v1alpha2_NodeHardeningSpec is an autogenerated conversion function.
+func Convert_kops_NodeHardeningSpec_To_v1alpha2_NodeHardeningSpec(in *kops.NodeHardeningSpec, out *NodeHardeningSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeHardeningSpec_To_v1alpha2_NodeHardeningSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_NodeReconcileSpec_To_kops_NodeReconcileSpec(in *NodeReconcileSpec, out *kops.NodeReconcileSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_v1alpha2_NodeReconcileSpec_To_kops_NodeReconcileSpec is an autogenerated conversion function.
+func Convert_v1alpha2_NodeReconcileSpec_To_kops_NodeReconcileSpec(in *NodeReconcileSpec, out *kops.NodeReconcileSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_NodeReconcileSpec_To_kops_NodeReconcileSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeReconcileSpec_To_v1alpha2_NodeReconcileSpec(in *kops.NodeReconcileSpec, out *NodeReconcileSpec, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Interval = in.Interval
+	return nil
+}
+
+// Convert_kops_NodeReconcileSpec_To_v1alpha2_NodeReconcileSpec is an autogenerated conversion function.
+func Convert_kops_NodeReconcileSpec_To_v1alpha2_NodeReconcileSpec(in *kops.NodeReconcileSpec, out *NodeReconcileSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeReconcileSpec_To_v1alpha2_NodeReconcileSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_OpenstackBlockStorageConfig_To_kops_OpenstackBlockStorageConfig(in *OpenstackBlockStorageConfig, out *kops.OpenstackBlockStorageConfig, s conversion.Scope) error {
 	out.Version = in.Version
 	out.IgnoreAZ = in.IgnoreAZ
@@ -4662,6 +5662,602 @@ func Convert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in *kops.SSHCr
 	return autoConvert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_SchedulerPlugin_To_kops_SchedulerPlugin(in *SchedulerPlugin, out *kops.SchedulerPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_v1alpha2_SchedulerPlugin_To_kops_SchedulerPlugin is an autogenerated conversion function.
+func Convert_v1alpha2_SchedulerPlugin_To_kops_SchedulerPlugin(in *SchedulerPlugin, out *kops.SchedulerPlugin, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SchedulerPlugin_To_kops_SchedulerPlugin(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPlugin_To_v1alpha2_SchedulerPlugin(in *kops.SchedulerPlugin, out *SchedulerPlugin, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_kops_SchedulerPlugin_To_v1alpha2_SchedulerPlugin is an autogenerated conversion function.
+func Convert_kops_SchedulerPlugin_To_v1alpha2_SchedulerPlugin(in *kops.SchedulerPlugin, out *SchedulerPlugin, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPlugin_To_v1alpha2_SchedulerPlugin(in, out, s)
+}
+
+func autoConvert_v1alpha2_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(in *SchedulerPluginConfig, out *kops.SchedulerPluginConfig, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_v1alpha2_SchedulerPluginConfig_To_kops_SchedulerPluginConfig is an autogenerated conversion function.
+func Convert_v1alpha2_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(in *SchedulerPluginConfig, out *kops.SchedulerPluginConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPluginConfig_To_v1alpha2_SchedulerPluginConfig(in *kops.SchedulerPluginConfig, out *SchedulerPluginConfig, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Args = in.Args
+	return nil
+}
+
+// Convert_kops_SchedulerPluginConfig_To_v1alpha2_SchedulerPluginConfig is an autogenerated conversion function.
+func Convert_kops_SchedulerPluginConfig_To_v1alpha2_SchedulerPluginConfig(in *kops.SchedulerPluginConfig, out *SchedulerPluginConfig, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPluginConfig_To_v1alpha2_SchedulerPluginConfig(in, out, s)
+}
+
+func autoConvert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(in *SchedulerPluginSet, out *kops.SchedulerPluginSet, s conversion.Scope) error {
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]kops.SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SchedulerPlugin_To_kops_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Enabled = nil
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]kops.SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SchedulerPlugin_To_kops_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Disabled = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet is an autogenerated conversion function.
+func Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(in *SchedulerPluginSet, out *kops.SchedulerPluginSet, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(in *kops.SchedulerPluginSet, out *SchedulerPluginSet, s conversion.Scope) error {
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerPlugin_To_v1alpha2_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Enabled = nil
+	}
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]SchedulerPlugin, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerPlugin_To_v1alpha2_SchedulerPlugin(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Disabled = nil
+	}
+	return nil
+}
+
+// Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet is an autogenerated conversion function.
+func Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(in *kops.SchedulerPluginSet, out *SchedulerPluginSet, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(in, out, s)
+}
+
+func autoConvert_v1alpha2_SchedulerPlugins_To_kops_SchedulerPlugins(in *SchedulerPlugins, out *kops.SchedulerPlugins, s conversion.Scope) error {
+	if in.QueueSort != nil {
+		in, out := &in.QueueSort, &out.QueueSort
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.QueueSort = nil
+	}
+	if in.PreFilter != nil {
+		in, out := &in.PreFilter, &out.PreFilter
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreFilter = nil
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Filter = nil
+	}
+	if in.PostFilter != nil {
+		in, out := &in.PostFilter, &out.PostFilter
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostFilter = nil
+	}
+	if in.PreScore != nil {
+		in, out := &in.PreScore, &out.PreScore
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreScore = nil
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Score = nil
+	}
+	if in.Reserve != nil {
+		in, out := &in.Reserve, &out.Reserve
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reserve = nil
+	}
+	if in.Permit != nil {
+		in, out := &in.Permit, &out.Permit
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Permit = nil
+	}
+	if in.PreBind != nil {
+		in, out := &in.PreBind, &out.PreBind
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreBind = nil
+	}
+	if in.Bind != nil {
+		in, out := &in.Bind, &out.Bind
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bind = nil
+	}
+	if in.PostBind != nil {
+		in, out := &in.PostBind, &out.PostBind
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostBind = nil
+	}
+	if in.Unreserve != nil {
+		in, out := &in.Unreserve, &out.Unreserve
+		*out = new(kops.SchedulerPluginSet)
+		if err := Convert_v1alpha2_SchedulerPluginSet_To_kops_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Unreserve = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_SchedulerPlugins_To_kops_SchedulerPlugins is an autogenerated conversion function.
+func Convert_v1alpha2_SchedulerPlugins_To_kops_SchedulerPlugins(in *SchedulerPlugins, out *kops.SchedulerPlugins, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SchedulerPlugins_To_kops_SchedulerPlugins(in, out, s)
+}
+
+func autoConvert_kops_SchedulerPlugins_To_v1alpha2_SchedulerPlugins(in *kops.SchedulerPlugins, out *SchedulerPlugins, s conversion.Scope) error {
+	if in.QueueSort != nil {
+		in, out := &in.QueueSort, &out.QueueSort
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.QueueSort = nil
+	}
+	if in.PreFilter != nil {
+		in, out := &in.PreFilter, &out.PreFilter
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreFilter = nil
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Filter = nil
+	}
+	if in.PostFilter != nil {
+		in, out := &in.PostFilter, &out.PostFilter
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostFilter = nil
+	}
+	if in.PreScore != nil {
+		in, out := &in.PreScore, &out.PreScore
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreScore = nil
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Score = nil
+	}
+	if in.Reserve != nil {
+		in, out := &in.Reserve, &out.Reserve
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Reserve = nil
+	}
+	if in.Permit != nil {
+		in, out := &in.Permit, &out.Permit
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Permit = nil
+	}
+	if in.PreBind != nil {
+		in, out := &in.PreBind, &out.PreBind
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PreBind = nil
+	}
+	if in.Bind != nil {
+		in, out := &in.Bind, &out.Bind
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bind = nil
+	}
+	if in.PostBind != nil {
+		in, out := &in.PostBind, &out.PostBind
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.PostBind = nil
+	}
+	if in.Unreserve != nil {
+		in, out := &in.Unreserve, &out.Unreserve
+		*out = new(SchedulerPluginSet)
+		if err := Convert_kops_SchedulerPluginSet_To_v1alpha2_SchedulerPluginSet(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Unreserve = nil
+	}
+	return nil
+}
+
+// Convert_kops_SchedulerPlugins_To_v1alpha2_SchedulerPlugins is an autogenerated conversion function.
+func Convert_kops_SchedulerPlugins_To_v1alpha2_SchedulerPlugins(in *kops.SchedulerPlugins, out *SchedulerPlugins, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerPlugins_To_v1alpha2_SchedulerPlugins(in, out, s)
+}
+
+func autoConvert_v1alpha2_SchedulerProfile_To_kops_SchedulerProfile(in *SchedulerProfile, out *kops.SchedulerProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(kops.SchedulerPlugins)
+		if err := Convert_v1alpha2_SchedulerPlugins_To_kops_SchedulerPlugins(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugins = nil
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = make([]kops.SchedulerPluginConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SchedulerPluginConfig_To_kops_SchedulerPluginConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PluginConfig = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_SchedulerProfile_To_kops_SchedulerProfile is an autogenerated conversion function.
+func Convert_v1alpha2_SchedulerProfile_To_kops_SchedulerProfile(in *SchedulerProfile, out *kops.SchedulerProfile, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SchedulerProfile_To_kops_SchedulerProfile(in, out, s)
+}
+
+func autoConvert_kops_SchedulerProfile_To_v1alpha2_SchedulerProfile(in *kops.SchedulerProfile, out *SchedulerProfile, s conversion.Scope) error {
+	out.SchedulerName = in.SchedulerName
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = new(SchedulerPlugins)
+		if err := Convert_kops_SchedulerPlugins_To_v1alpha2_SchedulerPlugins(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Plugins = nil
+	}
+	if in.PluginConfig != nil {
+		in, out := &in.PluginConfig, &out.PluginConfig
+		*out = make([]SchedulerPluginConfig, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SchedulerPluginConfig_To_v1alpha2_SchedulerPluginConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PluginConfig = nil
+	}
+	return nil
+}
+
+// Convert_kops_SchedulerProfile_To_v1alpha2_SchedulerProfile is an autogenerated conversion function.
+func Convert_kops_SchedulerProfile_To_v1alpha2_SchedulerProfile(in *kops.SchedulerProfile, out *SchedulerProfile, s conversion.Scope) error {
+	return autoConvert_kops_SchedulerProfile_To_v1alpha2_SchedulerProfile(in, out, s)
+}
+
+func autoConvert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	if in.AWSKMS != nil {
+		in, out := &in.AWSKMS, &out.AWSKMS
+		*out = new(kops.AWSKMSSecretEncryptionSpec)
+		if err := Convert_v1alpha2_AWSKMSSecretEncryptionSpec_To_kops_AWSKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSKMS = nil
+	}
+	if in.GCPKMS != nil {
+		in, out := &in.GCPKMS, &out.GCPKMS
+		*out = new(kops.GCPKMSSecretEncryptionSpec)
+		if err := Convert_v1alpha2_GCPKMSSecretEncryptionSpec_To_kops_GCPKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCPKMS = nil
+	}
+	if in.VaultTransit != nil {
+		in, out := &in.VaultTransit, &out.VaultTransit
+		*out = new(kops.VaultTransitSecretEncryptionSpec)
+		if err := Convert_v1alpha2_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.VaultTransit = nil
+	}
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(kops.LocalSecretEncryptionSpec)
+		if err := Convert_v1alpha2_LocalSecretEncryptionSpec_To_kops_LocalSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Local = nil
+	}
+	if in.PreviousKeys != nil {
+		in, out := &in.PreviousKeys, &out.PreviousKeys
+		*out = make([]kops.SecretEncryptionSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreviousKeys = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	if in.AWSKMS != nil {
+		in, out := &in.AWSKMS, &out.AWSKMS
+		*out = new(AWSKMSSecretEncryptionSpec)
+		if err := Convert_kops_AWSKMSSecretEncryptionSpec_To_v1alpha2_AWSKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AWSKMS = nil
+	}
+	if in.GCPKMS != nil {
+		in, out := &in.GCPKMS, &out.GCPKMS
+		*out = new(GCPKMSSecretEncryptionSpec)
+		if err := Convert_kops_GCPKMSSecretEncryptionSpec_To_v1alpha2_GCPKMSSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GCPKMS = nil
+	}
+	if in.VaultTransit != nil {
+		in, out := &in.VaultTransit, &out.VaultTransit
+		*out = new(VaultTransitSecretEncryptionSpec)
+		if err := Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha2_VaultTransitSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.VaultTransit = nil
+	}
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalSecretEncryptionSpec)
+		if err := Convert_kops_LocalSecretEncryptionSpec_To_v1alpha2_LocalSecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Local = nil
+	}
+	if in.PreviousKeys != nil {
+		in, out := &in.PreviousKeys, &out.PreviousKeys
+		*out = make([]SecretEncryptionSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.PreviousKeys = nil
+	}
+	return nil
+}
+
+// Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_StorageSpec_To_kops_StorageSpec(in *StorageSpec, out *kops.StorageSpec, s conversion.Scope) error {
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(kops.CSISpec)
+		if err := Convert_v1alpha2_CSISpec_To_kops_CSISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CSI = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_StorageSpec_To_kops_StorageSpec is an autogenerated conversion function.
+func Convert_v1alpha2_StorageSpec_To_kops_StorageSpec(in *StorageSpec, out *kops.StorageSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_StorageSpec_To_kops_StorageSpec(in, out, s)
+}
+
+func autoConvert_kops_StorageSpec_To_v1alpha2_StorageSpec(in *kops.StorageSpec, out *StorageSpec, s conversion.Scope) error {
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(CSISpec)
+		if err := Convert_kops_CSISpec_To_v1alpha2_CSISpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.CSI = nil
+	}
+	return nil
+}
+
+// Convert_kops_StorageSpec_To_v1alpha2_StorageSpec is an autogenerated conversion function.
+func Convert_kops_StorageSpec_To_v1alpha2_StorageSpec(in *kops.StorageSpec, out *StorageSpec, s conversion.Scope) error {
+	return autoConvert_kops_StorageSpec_To_v1alpha2_StorageSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(in *SystemdUnitSpec, out *kops.SystemdUnitSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]kops.InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = kops.InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Requires = in.Requires
+	out.After = in.After
+	out.Before = in.Before
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(in *SystemdUnitSpec, out *kops.SystemdUnitSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SystemdUnitSpec_To_kops_SystemdUnitSpec(in, out, s)
+}
+
+func autoConvert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(in *kops.SystemdUnitSpec, out *SystemdUnitSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Disabled = in.Disabled
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]InstanceGroupRole, len(*in))
+		for i := range *in {
+			(*out)[i] = InstanceGroupRole((*in)[i])
+		}
+	} else {
+		out.Roles = nil
+	}
+	out.Requires = in.Requires
+	out.After = in.After
+	out.Before = in.Before
+	out.Manifest = in.Manifest
+	return nil
+}
+
+// Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec is an autogenerated conversion function.
+func Convert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(in *kops.SystemdUnitSpec, out *SystemdUnitSpec, s conversion.Scope) error {
+	return autoConvert_kops_SystemdUnitSpec_To_v1alpha2_SystemdUnitSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -4800,6 +6396,62 @@ func Convert_kops_UserData_To_v1alpha2_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha2_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha2_VaultSpec_To_kops_VaultSpec(in *VaultSpec, out *kops.VaultSpec, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.AuthMethod = in.AuthMethod
+	out.AuthMount = in.AuthMount
+	out.MasterRole = in.MasterRole
+	out.NodeRole = in.NodeRole
+	out.PKIMount = in.PKIMount
+	out.NodeCertificateRole = in.NodeCertificateRole
+	return nil
+}
+
+// Convert_v1alpha2_VaultSpec_To_kops_VaultSpec is an autogenerated conversion function.
+func Convert_v1alpha2_VaultSpec_To_kops_VaultSpec(in *VaultSpec, out *kops.VaultSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_VaultSpec_To_kops_VaultSpec(in, out, s)
+}
+
+func autoConvert_kops_VaultSpec_To_v1alpha2_VaultSpec(in *kops.VaultSpec, out *VaultSpec, s conversion.Scope) error {
+	out.Namespace = in.Namespace
+	out.AuthMethod = in.AuthMethod
+	out.AuthMount = in.AuthMount
+	out.MasterRole = in.MasterRole
+	out.NodeRole = in.NodeRole
+	out.PKIMount = in.PKIMount
+	out.NodeCertificateRole = in.NodeCertificateRole
+	return nil
+}
+
+// Convert_kops_VaultSpec_To_v1alpha2_VaultSpec is an autogenerated conversion function.
+func Convert_kops_VaultSpec_To_v1alpha2_VaultSpec(in *kops.VaultSpec, out *VaultSpec, s conversion.Scope) error {
+	return autoConvert_kops_VaultSpec_To_v1alpha2_VaultSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(in *VaultTransitSecretEncryptionSpec, out *kops.VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	out.Address = in.Address
+	out.MountPath = in.MountPath
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_v1alpha2_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(in *VaultTransitSecretEncryptionSpec, out *kops.VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_VaultTransitSecretEncryptionSpec_To_kops_VaultTransitSecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha2_VaultTransitSecretEncryptionSpec(in *kops.VaultTransitSecretEncryptionSpec, out *VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	out.Address = in.Address
+	out.MountPath = in.MountPath
+	out.KeyName = in.KeyName
+	return nil
+}
+
+// Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha2_VaultTransitSecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha2_VaultTransitSecretEncryptionSpec(in *kops.VaultTransitSecretEncryptionSpec, out *VaultTransitSecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_VaultTransitSecretEncryptionSpec_To_v1alpha2_VaultTransitSecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -17,6 +17,7 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -61,6 +62,12 @@ func ValidateInstanceGroup(g *kops.InstanceGroup) error {
 		return field.Invalid(field.NewPath("RootVolumeIops"), g.Spec.RootVolumeIops, "RootVolumeIops must be greater than 0")
 	}
 
+	if g.Spec.Kubelet != nil {
+		if errs := validateKubelet(g.Spec.Kubelet, field.NewPath("kubelet")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+	}
+
 	// @check all the hooks are valid in this instancegroup
 	for i := range g.Spec.Hooks {
 		if errs := validateHookSpec(&g.Spec.Hooks[i], field.NewPath("hooks").Index(i)); len(errs) > 0 {
@@ -75,6 +82,14 @@ func ValidateInstanceGroup(g *kops.InstanceGroup) error {
 		}
 	}
 
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateSysctlParameters(g.Spec.SysctlParameters, field.NewPath("sysctlParameters"))...)
+	allErrs = append(allErrs, validateKernelModules(g.Spec.KernelModules, field.NewPath("kernelModules"))...)
+	allErrs = append(allErrs, validateSystemdUnits(g.Spec.SystemdUnits, field.NewPath("systemdUnits"))...)
+	if len(allErrs) > 0 {
+		return allErrs.ToAggregate()
+	}
+
 	if g.IsMaster() {
 		if len(g.Spec.Subnets) == 0 {
 			return fmt.Errorf("Master InstanceGroup %s did not specify any Subnets", g.ObjectMeta.Name)
@@ -87,9 +102,21 @@ func ValidateInstanceGroup(g *kops.InstanceGroup) error {
 		}
 	}
 
+	switch g.Spec.BootstrapFormat {
+	case "", kops.BootstrapFormatScript, kops.BootstrapFormatIgnition:
+	default:
+		return field.NotSupported(field.NewPath("BootstrapFormat"), g.Spec.BootstrapFormat, []string{kops.BootstrapFormatScript, kops.BootstrapFormatIgnition})
+	}
+
+	if g.Spec.NodeHardening != nil {
+		if errs := validateNodeHardening(g.Spec.NodeHardening, field.NewPath("nodeHardening")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+	}
+
 	if len(g.Spec.AdditionalUserData) > 0 {
 		for _, UserDataInfo := range g.Spec.AdditionalUserData {
-			err := validateExtraUserData(&UserDataInfo)
+			err := validateExtraUserData(&UserDataInfo, g.Spec.BootstrapFormat)
 			if err != nil {
 				return err
 			}
@@ -238,6 +265,14 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, st
 	allErrs := field.ErrorList{}
 	fieldPath := field.NewPath("InstanceGroup")
 
+	if g.Spec.BootstrapFormat == kops.BootstrapFormatIgnition {
+		switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
+		case kops.CloudProviderAWS, kops.CloudProviderAzure, kops.CloudProviderDO, kops.CloudProviderGCE, kops.CloudProviderOpenstack:
+		default:
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("Spec", "BootstrapFormat"), g.Spec.BootstrapFormat, fmt.Sprintf("bootstrapFormat ignition is not supported on %s", cluster.Spec.CloudProvider)))
+		}
+	}
+
 	if k8sVersion.Major == 1 && k8sVersion.Minor <= 5 {
 		if len(g.Spec.Taints) > 0 {
 			if !(g.IsMaster() && g.Spec.Taints[0] == kops.TaintNoScheduleMaster15 && len(g.Spec.Taints) == 1) {
@@ -253,7 +288,7 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, st
 	return nil
 }
 
-func validateExtraUserData(userData *kops.UserData) error {
+func validateExtraUserData(userData *kops.UserData, bootstrapFormat string) error {
 	fieldPath := field.NewPath("AdditionalUserData")
 
 	if userData.Name == "" {
@@ -264,6 +299,20 @@ func validateExtraUserData(userData *kops.UserData) error {
 		return field.Required(fieldPath.Child("Content"), "field must be set")
 	}
 
+	// Only shell scripts and Ignition configs have an Ignition equivalent
+	if bootstrapFormat == kops.BootstrapFormatIgnition {
+		switch userData.Type {
+		case "text/x-shellscript":
+		case kops.UserDataTypeIgnition:
+			if !json.Valid([]byte(userData.Content)) {
+				return field.Invalid(fieldPath.Child("Content"), userData.Name, "Ignition config must be valid JSON")
+			}
+		default:
+			return field.NotSupported(fieldPath.Child("Type"), userData.Type, []string{"text/x-shellscript", kops.UserDataTypeIgnition})
+		}
+		return nil
+	}
+
 	switch userData.Type {
 	case "text/x-include-once-url":
 	case "text/x-include-url":
@@ -137,3 +137,72 @@ func TestValidateInstanceProfile(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateBootstrapFormat(t *testing.T) {
+	grid := []struct {
+		CloudProvider      string
+		BootstrapFormat    string
+		AdditionalUserData []kops.UserData
+		ExpectedError      string
+	}{
+		{
+			CloudProvider:   "aws",
+			BootstrapFormat: kops.BootstrapFormatScript,
+		},
+		{
+			CloudProvider:   "aws",
+			BootstrapFormat: kops.BootstrapFormatIgnition,
+			AdditionalUserData: []kops.UserData{
+				{Name: "myscript.sh", Type: "text/x-shellscript", Content: "#!/bin/sh"},
+				{Name: "extra.ign", Type: kops.UserDataTypeIgnition, Content: `{"ignition":{"version":"2.2.0"}}`},
+			},
+		},
+		{
+			CloudProvider:   "aws",
+			BootstrapFormat: "cloud-init",
+			ExpectedError:   "Unsupported value: \"cloud-init\"",
+		},
+		{
+			CloudProvider:   "aws",
+			BootstrapFormat: kops.BootstrapFormatIgnition,
+			AdditionalUserData: []kops.UserData{
+				{Name: "cloud-config.txt", Type: "text/cloud-config", Content: "#cloud-config"},
+			},
+			ExpectedError: "Unsupported value: \"text/cloud-config\"",
+		},
+		{
+			CloudProvider:   "aws",
+			BootstrapFormat: kops.BootstrapFormatIgnition,
+			AdditionalUserData: []kops.UserData{
+				{Name: "extra.ign", Type: kops.UserDataTypeIgnition, Content: "{"},
+			},
+			ExpectedError: "Ignition config must be valid JSON",
+		},
+		{
+			CloudProvider:   "vsphere",
+			BootstrapFormat: kops.BootstrapFormatIgnition,
+			ExpectedError:   "bootstrapFormat ignition is not supported on vsphere",
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{Spec: kops.ClusterSpec{KubernetesVersion: "1.15.0", CloudProvider: g.CloudProvider}}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: kops.InstanceGroupSpec{
+				Role:               kops.InstanceGroupRoleNode,
+				BootstrapFormat:    g.BootstrapFormat,
+				AdditionalUserData: g.AdditionalUserData,
+			},
+		}
+
+		err := CrossValidateInstanceGroup(ig, cluster, false)
+		if g.ExpectedError == "" {
+			if err != nil {
+				t.Errorf("unexpected error validating %v: %v", g, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+			t.Errorf("expected error containing %q validating %v, got %v", g.ExpectedError, g, err)
+		}
+	}
+}
@@ -26,7 +26,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/encryptionatrest"
 	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/pkg/kubeletconfig"
+	"k8s.io/kops/pkg/kubeproxyconfig"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/util/subnet"
 	"k8s.io/kops/upup/pkg/fi"
@@ -113,6 +116,7 @@ func ValidateCluster(c *kops.Cluster, strict bool) *field.Error {
 		requiresSubnetCIDR = false
 		requiresNetworkCIDR = false
 	case kops.CloudProviderAWS:
+	case kops.CloudProviderAzure:
 	case kops.CloudProviderVSphere:
 	case kops.CloudProviderOpenstack:
 		requiresNetworkCIDR = false
@@ -139,6 +143,62 @@ func ValidateCluster(c *kops.Cluster, strict bool) *field.Error {
 	if kubernetesRelease.LT(semver.MustParse("1.7.0")) && c.Spec.ExternalCloudControllerManager != nil {
 		return field.Invalid(fieldSpec.Child("ExternalCloudControllerManager"), c.Spec.ExternalCloudControllerManager, "ExternalCloudControllerManager is not supported in version 1.6.0 or lower")
 	}
+	if c.Spec.ClusterAutoscaler != nil && fi.BoolValue(c.Spec.ClusterAutoscaler.Enabled) && kubernetesRelease.LT(semver.MustParse("1.12.0")) {
+		return field.Forbidden(fieldSpec.Child("ClusterAutoscaler", "Enabled"), "the cluster-autoscaler addon requires kubernetes 1.12 or later")
+	}
+	if c.Spec.ExternalCloudControllerManager != nil && kops.CloudProviderID(c.Spec.CloudProvider) == kops.CloudProviderAWS && kubernetesRelease.LT(semver.MustParse("1.14.0")) {
+		return field.Forbidden(fieldSpec.Child("ExternalCloudControllerManager"), "the AWS cloud-controller-manager addon requires kubernetes 1.14 or later")
+	}
+	if c.Spec.Storage != nil && c.Spec.Storage.CSI != nil && fi.BoolValue(c.Spec.Storage.CSI.Enabled) && kubernetesRelease.LT(semver.MustParse("1.14.0")) {
+		return field.Forbidden(fieldSpec.Child("Storage", "CSI", "Enabled"), "the CSI driver addon requires kubernetes 1.14 or later")
+	}
+	for _, kubelet := range []struct {
+		name string
+		spec *kops.KubeletConfigSpec
+	}{{"Kubelet", c.Spec.Kubelet}, {"MasterKubelet", c.Spec.MasterKubelet}} {
+		if kubelet.spec == nil {
+			continue
+		}
+		if kubernetesRelease.LT(semver.MustParse("1.10.0")) {
+			if kubelet.spec.ConfigOverrides != nil {
+				return field.Forbidden(fieldSpec.Child(kubelet.name, "ConfigOverrides"), "the kubelet config file requires kubernetes 1.10 or later")
+			}
+		} else if _, _, err := kubeletconfig.BuildConfig(kubelet.spec); err != nil {
+			return field.Invalid(fieldSpec.Child(kubelet.name), kubelet.spec, err.Error())
+		}
+	}
+	if proxy := c.Spec.KubeProxy; proxy != nil {
+		if kubernetesRelease.LT(semver.MustParse("1.12.0")) {
+			if proxy.ConfigOverrides != nil {
+				return field.Forbidden(fieldSpec.Child("KubeProxy", "ConfigOverrides"), "the kube-proxy config file requires kubernetes 1.12 or later")
+			}
+		} else if _, _, err := kubeproxyconfig.BuildConfig(proxy); err != nil {
+			return field.Invalid(fieldSpec.Child("KubeProxy"), proxy, err.Error())
+		}
+		if proxy.IPVSStrictARP != nil && kubernetesRelease.LT(semver.MustParse("1.14.0")) {
+			return field.Forbidden(fieldSpec.Child("KubeProxy", "IPVSStrictARP"), "ipvsStrictArp requires kubernetes 1.14 or later")
+		}
+		if (proxy.IPVSTCPTimeout != nil || proxy.IPVSTCPFinTimeout != nil || proxy.IPVSUDPTimeout != nil) && kubernetesRelease.LT(semver.MustParse("1.18.0")) {
+			return field.Forbidden(fieldSpec.Child("KubeProxy"), "the ipvs timeouts require kubernetes 1.18 or later")
+		}
+	}
+	if scheduler := c.Spec.KubeScheduler; scheduler != nil {
+		if scheduler.ConfigOverrides != nil && kubernetesRelease.LT(semver.MustParse("1.12.0")) {
+			return field.Forbidden(fieldSpec.Child("KubeScheduler", "ConfigOverrides"), "the kube-scheduler config file requires kubernetes 1.12 or later")
+		}
+		if len(scheduler.Profiles) != 0 && kubernetesRelease.LT(semver.MustParse("1.15.0")) {
+			return field.Forbidden(fieldSpec.Child("KubeScheduler", "Profiles"), "scheduler profiles require kubernetes 1.15 or later")
+		}
+		if len(scheduler.Profiles) > 1 && kubernetesRelease.LT(semver.MustParse("1.18.0")) {
+			return field.Forbidden(fieldSpec.Child("KubeScheduler", "Profiles"), "multiple scheduler profiles require kubernetes 1.18 or later")
+		}
+	}
+	if c.Spec.EncryptionAtRest != nil && kubernetesRelease.LT(semver.MustParse("1.7.0")) {
+		return field.Forbidden(fieldSpec.Child("EncryptionAtRest"), "encryption at rest requires kubernetes 1.7 or later")
+	}
+	if c.Spec.EncryptionAtRest != nil && c.Spec.EncryptionAtRest.Provider == encryptionatrest.ProviderKMS && kubernetesRelease.LT(semver.MustParse("1.10.0")) {
+		return field.Forbidden(fieldSpec.Child("EncryptionAtRest", "Provider"), "the kms encryption provider requires kubernetes 1.10 or later")
+	}
 	if strict && c.Spec.KubeDNS == nil {
 		return field.Required(fieldSpec.Child("KubeDNS"), "KubeDNS not configured")
 	}
@@ -352,6 +412,8 @@ func ValidateCluster(c *kops.Cluster, strict bool) *field.Error {
 			k8sCloudProvider = "openstack"
 		case kops.CloudProviderALI:
 			k8sCloudProvider = "alicloud"
+		case kops.CloudProviderAzure:
+			k8sCloudProvider = "azure"
 		default:
 			return field.Invalid(fieldSpec.Child("CloudProvider"), c.Spec.CloudProvider, "unknown cloudprovider")
 		}
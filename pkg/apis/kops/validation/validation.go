@@ -19,17 +19,33 @@ package validation
 import (
 	"fmt"
 	"net"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
 
 	"k8s.io/apimachinery/pkg/api/validation"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/audit"
+	"k8s.io/kops/pkg/encryptionatrest"
+	"k8s.io/kops/pkg/hardening"
+	"k8s.io/kops/pkg/kubeletconfig"
+	"k8s.io/kops/pkg/kubeproxyconfig"
+	"k8s.io/kops/pkg/kubeschedulerconfig"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/pkg/vault"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 var validDockerConfigStorageValues = []string{"aufs", "btrfs", "devicemapper", "overlay", "overlay2", "zfs"}
@@ -85,6 +101,19 @@ func validateClusterSpec(spec *kops.ClusterSpec, fieldPath *field.Path) field.Er
 		allErrs = append(allErrs, validateHookSpec(&spec.Hooks[i], fieldPath.Child("hooks").Index(i))...)
 	}
 
+	allErrs = append(allErrs, validateSysctlParameters(spec.SysctlParameters, fieldPath.Child("sysctlParameters"))...)
+	allErrs = append(allErrs, validateKernelModules(spec.KernelModules, fieldPath.Child("kernelModules"))...)
+	allErrs = append(allErrs, validateSystemdUnits(spec.SystemdUnits, fieldPath.Child("systemdUnits"))...)
+
+	// Addons
+	for i := range spec.Addons {
+		allErrs = append(allErrs, validateAddonSpec(&spec.Addons[i], fieldPath.Child("addons").Index(i))...)
+	}
+
+	for i := range spec.AddonOverrides {
+		allErrs = append(allErrs, validateAddonOverride(&spec.AddonOverrides[i], fieldPath.Child("addonOverrides").Index(i))...)
+	}
+
 	if spec.FileAssets != nil {
 		for i, x := range spec.FileAssets {
 			allErrs = append(allErrs, validateFileAssetSpec(&x, fieldPath.Child("fileAssets").Index(i))...)
@@ -95,6 +124,22 @@ func validateClusterSpec(spec *kops.ClusterSpec, fieldPath *field.Path) field.Er
 		allErrs = append(allErrs, validateKubeAPIServer(spec.KubeAPIServer, fieldPath.Child("kubeAPIServer"))...)
 	}
 
+	if spec.Kubelet != nil {
+		allErrs = append(allErrs, validateKubelet(spec.Kubelet, fieldPath.Child("kubelet"))...)
+	}
+
+	if spec.MasterKubelet != nil {
+		allErrs = append(allErrs, validateKubelet(spec.MasterKubelet, fieldPath.Child("masterKubelet"))...)
+	}
+
+	if spec.KubeProxy != nil {
+		allErrs = append(allErrs, kubeproxyconfig.ValidateOverrides(spec.KubeProxy.ConfigOverrides, fieldPath.Child("kubeProxy", "configOverrides"))...)
+	}
+
+	if spec.KubeScheduler != nil {
+		allErrs = append(allErrs, validateKubeScheduler(spec.KubeScheduler, fieldPath.Child("kubeScheduler"))...)
+	}
+
 	if spec.Networking != nil {
 		allErrs = append(allErrs, validateNetworking(spec, spec.Networking, fieldPath.Child("networking"))...)
 		if spec.Networking.Calico != nil {
@@ -116,6 +161,235 @@ func validateClusterSpec(spec *kops.ClusterSpec, fieldPath *field.Path) field.Er
 		}
 	}
 
+	if spec.ClusterAutoscaler != nil {
+		allErrs = append(allErrs, validateClusterAutoscaler(spec, spec.ClusterAutoscaler, fieldPath.Child("clusterAutoscaler"))...)
+	}
+
+	if spec.Storage != nil {
+		allErrs = append(allErrs, validateStorage(spec, spec.Storage, fieldPath.Child("storage"))...)
+	}
+
+	if spec.EncryptionAtRest != nil {
+		allErrs = append(allErrs, validateEncryptionAtRest(spec, spec.EncryptionAtRest, fieldPath.Child("encryptionAtRest"))...)
+	}
+
+	if spec.SecretEncryption != nil {
+		allErrs = append(allErrs, validateSecretEncryption(spec.SecretEncryption, fieldPath.Child("secretEncryption"))...)
+	}
+
+	allErrs = append(allErrs, validateVault(spec, fieldPath)...)
+
+	if spec.NodeReconcile != nil && spec.NodeReconcile.Interval != nil && spec.NodeReconcile.Interval.Duration < time.Minute {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("nodeReconcile", "interval"), spec.NodeReconcile.Interval.Duration.String(), "must be at least 1m"))
+	}
+
+	if spec.NodeHardening != nil {
+		allErrs = append(allErrs, validateNodeHardening(spec.NodeHardening, fieldPath.Child("nodeHardening"))...)
+	}
+
+	if spec.Assets != nil {
+		allErrs = append(allErrs, validateAssets(spec, spec.Assets, fieldPath.Child("assets"))...)
+	}
+
+	return allErrs
+}
+
+func validateAssets(spec *kops.ClusterSpec, v *kops.Assets, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// The images of a kubernetesVersion base URL are loaded from tarballs (checked against their hashes) under a tag
+	// which has no digest in any registry, so nodes would refuse to run them
+	if fi.BoolValue(v.PinImageDigests) && components.IsBaseURL(spec.KubernetesVersion) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("pinImageDigests"), "pinImageDigests cannot be used when kubernetesVersion is a URL"))
+	}
+
+	if v.FileSignatureKey != nil {
+		if _, err := assets.ParseSignatureKey(*v.FileSignatureKey); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("fileSignatureKey"), "<public key>", err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateClusterAutoscaler(spec *kops.ClusterSpec, c *kops.ClusterAutoscalerConfig, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !fi.BoolValue(c.Enabled) {
+		return allErrs
+	}
+
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("enabled"), "the cluster-autoscaler addon is only supported on AWS"))
+	}
+
+	if c.Expander != nil {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("expander"), c.Expander, []string{"random", "most-pods", "least-waste", "priority"})...)
+	}
+
+	if c.ScaleDownUtilizationThreshold != nil {
+		threshold, err := strconv.ParseFloat(*c.ScaleDownUtilizationThreshold, 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("scaleDownUtilizationThreshold"), *c.ScaleDownUtilizationThreshold, "must be a number between 0 and 1"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateStorage(spec *kops.ClusterSpec, s *kops.StorageSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if s.CSI != nil && fi.BoolValue(s.CSI.Enabled) {
+		if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("csi", "enabled"), "the CSI driver addon is only supported on AWS"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateEncryptionAtRest(spec *kops.ClusterSpec, e *kops.EncryptionAtRestSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if fi.BoolValue(spec.EncryptionConfig) {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "encryptionAtRest cannot be used together with encryptionConfig"))
+	}
+
+	if e.Provider != "" {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("provider"), &e.Provider, encryptionatrest.Providers)...)
+	}
+
+	for i, resource := range e.Resources {
+		if resource == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("resources").Index(i), "resource must not be empty"))
+		}
+	}
+
+	if e.Provider != encryptionatrest.ProviderKMS {
+		if e.KMS != nil {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("kms"), "kms can only be set when provider is kms"))
+		}
+		return allErrs
+	}
+
+	if kops.CloudProviderID(spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("provider"), "the kms encryption provider is only supported on AWS"))
+	}
+	if e.KMS == nil {
+		return append(allErrs, field.Required(fieldPath.Child("kms"), "kms must be set when provider is kms"))
+	}
+	if !strings.HasPrefix(e.KMS.KeyID, "arn:") || !strings.Contains(e.KMS.KeyID, ":key/") {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("kms", "keyID"), e.KMS.KeyID, "keyID must be the ARN of a KMS key"))
+	}
+	if e.KMS.Image == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("kms", "image"), "image of the aws-encryption-provider must be set"))
+	}
+	if e.KMS.CacheSize != nil && *e.KMS.CacheSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("kms", "cacheSize"), *e.KMS.CacheSize, "cacheSize must not be negative"))
+	}
+
+	return allErrs
+}
+
+func validateSecretEncryption(v *kops.SecretEncryptionSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	providers := 0
+	if v.AWSKMS != nil {
+		providers++
+		if v.AWSKMS.KeyID == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("awsKMS", "keyID"), "keyID must be set"))
+		} else if v.AWSKMS.Region == "" && !strings.HasPrefix(v.AWSKMS.KeyID, "arn:") {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("awsKMS", "region"), "region must be set unless keyID is an ARN"))
+		}
+	}
+	if v.GCPKMS != nil {
+		providers++
+		if !strings.HasPrefix(v.GCPKMS.KeyName, "projects/") || !strings.Contains(v.GCPKMS.KeyName, "/cryptoKeys/") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("gcpKMS", "keyName"), v.GCPKMS.KeyName, "keyName must be of the form projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>"))
+		}
+	}
+	if v.VaultTransit != nil {
+		providers++
+		if v.VaultTransit.Address == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("vaultTransit", "address"), "address must be set"))
+		}
+		if v.VaultTransit.KeyName == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("vaultTransit", "keyName"), "keyName must be set"))
+		}
+	}
+	if v.Local != nil {
+		providers++
+	}
+
+	// With no provider and only previous keys, secrets are decrypted with the previous keys and written in plaintext
+	if providers > 1 || (providers == 0 && len(v.PreviousKeys) == 0) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, providers, "exactly one of awsKMS, gcpKMS, vaultTransit or local must be set"))
+	}
+
+	for i := range v.PreviousKeys {
+		previous := &v.PreviousKeys[i]
+		previousPath := fieldPath.Child("previousKeys").Index(i)
+		if len(previous.PreviousKeys) != 0 {
+			allErrs = append(allErrs, field.Forbidden(previousPath.Child("previousKeys"), "previous keys cannot themselves have previousKeys"))
+			continue
+		}
+		allErrs = append(allErrs, validateSecretEncryption(previous, previousPath)...)
+	}
+
+	return allErrs
+}
+
+func validateVault(spec *kops.ClusterSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	usesVault := false
+	for _, store := range []struct {
+		name string
+		path string
+	}{
+		{name: "secretStore", path: spec.SecretStore},
+		{name: "keyStore", path: spec.KeyStore},
+	} {
+		if !vault.IsVaultPath(store.path) {
+			continue
+		}
+		usesVault = true
+		if _, err := vault.ParseStorePath(store.path); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child(store.name), store.path, err.Error()))
+		}
+	}
+
+	v := spec.Vault
+	if v == nil {
+		if usesVault {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("vault"), "vault must be configured when the secret or key store is in vault"))
+		}
+		return allErrs
+	}
+
+	vaultPath := fieldPath.Child("vault")
+	switch v.AuthMethod {
+	case "aws", "gcp":
+		if v.MasterRole == "" {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("masterRole"), "masterRole must be set"))
+		}
+		if v.NodeRole == "" {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("nodeRole"), "nodeRole must be set"))
+		}
+	case "":
+		if usesVault {
+			allErrs = append(allErrs, field.Required(vaultPath.Child("authMethod"), "authMethod must be set for instances to log in to vault"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(vaultPath.Child("authMethod"), v.AuthMethod, []string{"aws", "gcp"}))
+	}
+
+	if v.NodeCertificateRole != "" && v.PKIMount == "" {
+		allErrs = append(allErrs, field.Required(vaultPath.Child("pkiMount"), "pkiMount must be set to issue node certificates"))
+	}
+
 	return allErrs
 }
 
@@ -188,6 +462,96 @@ func validateSubnet(subnet *kops.ClusterSubnetSpec, fieldPath *field.Path) field
 	return allErrs
 }
 
+// validateAddonSpec is responsible for checking an AddonSpec is ok
+func validateAddonSpec(v *kops.AddonSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.Chart == nil {
+		if v.Manifest == "" {
+			allErrs = append(allErrs, field.Required(fieldPath, "you must set either manifest or chart for an addon"))
+		}
+		return allErrs
+	}
+
+	if v.Manifest != "" {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("manifest"), "manifest and chart cannot both be set"))
+	}
+	if v.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), "name must be set for a chart addon"))
+	} else {
+		for _, msg := range validation.NameIsDNSSubdomain(v.Name, false) {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("name"), v.Name, msg))
+		}
+	}
+
+	chartPath := fieldPath.Child("chart")
+	if v.Chart.Name == "" {
+		allErrs = append(allErrs, field.Required(chartPath.Child("name"), "name must be set"))
+	}
+	if v.Chart.Repository != "" && v.Chart.Version == "" {
+		allErrs = append(allErrs, field.Required(chartPath.Child("version"), "version must be set for a chart from a repository"))
+	}
+	if v.Chart.Version != "" {
+		if _, err := semver.ParseTolerant(v.Chart.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(chartPath.Child("version"), v.Chart.Version, "version must be a semver version"))
+		}
+	}
+	if v.Chart.Values != "" {
+		values := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(v.Chart.Values), &values); err != nil {
+			allErrs = append(allErrs, field.Invalid(chartPath.Child("values"), v.Chart.Values, fmt.Sprintf("values must be a YAML map: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateAddonOverride checks an AddonOverrideSpec identifies an object and holds a valid patch
+func validateAddonOverride(v *kops.AddonOverrideSpec, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.Addon == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("addon"), ""))
+	}
+	if v.Kind == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("kind"), ""))
+	}
+	if v.Name == "" {
+		allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
+	}
+
+	patchPath := fieldPath.Child("patch")
+	if v.Patch == "" {
+		allErrs = append(allErrs, field.Required(patchPath, ""))
+		return allErrs
+	}
+
+	switch v.Type {
+	case "", "strategic":
+		patch := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(v.Patch), &patch); err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, v.Patch, fmt.Sprintf("a strategic merge patch must be a YAML map: %v", err)))
+		}
+	case "json":
+		var ops []map[string]interface{}
+		if err := yaml.Unmarshal([]byte(v.Patch), &ops); err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, v.Patch, fmt.Sprintf("a json patch must be a list of operations: %v", err)))
+		}
+		for i, op := range ops {
+			if _, found := op["op"]; !found {
+				allErrs = append(allErrs, field.Required(patchPath.Index(i).Child("op"), ""))
+			}
+			if _, found := op["path"]; !found {
+				allErrs = append(allErrs, field.Required(patchPath.Index(i).Child("path"), ""))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fieldPath.Child("type"), v.Type, []string{"strategic", "json"}))
+	}
+
+	return allErrs
+}
+
 // validateFileAssetSpec is responsible for checking a FileAssetSpec is ok
 func validateFileAssetSpec(v *kops.FileAssetSpec, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -237,6 +601,90 @@ func validateHookSpec(v *kops.HookSpec, fieldPath *field.Path) field.ErrorList {
 	return allErrs
 }
 
+func validateSysctlParameters(params []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, param := range params {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || !sysctlNameRegexp.MatchString(strings.TrimSpace(kv[0])) || strings.Contains(param, "\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), param, "must be a kernel parameter and its value, e.g. net.ipv4.tcp_keepalive_time=200"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateKernelModules(modules []kops.KernelModuleSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, module := range modules {
+		path := fldPath.Index(i)
+		if !moduleNameRegexp.MatchString(module.Name) {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), module.Name, "invalid kernel module name"))
+		} else if names.Has(module.Name) {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), module.Name))
+		}
+		names.Insert(module.Name)
+
+		for j, param := range module.Parameters {
+			if !strings.Contains(param, "=") || strings.ContainsAny(param, " \t\n") {
+				allErrs = append(allErrs, field.Invalid(path.Child("parameters").Index(j), param, "must be a module parameter and its value, e.g. hashsize=32768"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// systemdUnitTypes are the types of the units that can be defined in systemdUnits, which have a section named after them
+var systemdUnitTypes = []string{"automount", "mount", "path", "service", "slice", "socket", "swap", "timer"}
+
+func validateSystemdUnits(units []kops.SystemdUnitSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, unit := range units {
+		path := fldPath.Index(i)
+
+		name := unit.Name
+		if !systemd.UnitFileExtensionValid(name) {
+			name += ".service"
+		}
+		unitType := name[strings.LastIndex(name, ".")+1:]
+		if !systemdUnitNameRegexp.MatchString(unit.Name) {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), unit.Name, "invalid systemd unit name"))
+		} else if !sets.NewString(systemdUnitTypes...).Has(unitType) {
+			allErrs = append(allErrs, field.NotSupported(path.Child("name"), unitType, systemdUnitTypes))
+		} else if names.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), unit.Name))
+		}
+		names.Insert(name)
+
+		if unit.Disabled {
+			continue
+		}
+
+		if strings.TrimSpace(unit.Manifest) == "" {
+			allErrs = append(allErrs, field.Required(path.Child("manifest"), "you must set the manifest of the unit"))
+		}
+		for _, deps := range []struct {
+			field string
+			units []string
+		}{{"requires", unit.Requires}, {"after", unit.After}, {"before", unit.Before}} {
+			for j, dep := range deps.units {
+				if !systemdUnitNameRegexp.MatchString(dep) {
+					allErrs = append(allErrs, field.Invalid(path.Child(deps.field).Index(j), dep, "invalid systemd unit name"))
+				} else if dep == unit.Name || dep == name {
+					allErrs = append(allErrs, field.Invalid(path.Child(deps.field).Index(j), dep, "a unit cannot depend on itself"))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
+
 func validateExecContainerAction(v *kops.ExecContainerAction, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -247,6 +695,65 @@ func validateExecContainerAction(v *kops.ExecContainerAction, fldPath *field.Pat
 	return allErrs
 }
 
+func validateKubeScheduler(c *kops.KubeSchedulerConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, profile := range c.Profiles {
+		profilePath := fldPath.Child("profiles").Index(i)
+		schedulerName := profile.SchedulerName
+		if schedulerName == "" {
+			schedulerName = "default-scheduler"
+		}
+		if names.Has(schedulerName) {
+			allErrs = append(allErrs, field.Duplicate(profilePath.Child("schedulerName"), schedulerName))
+		}
+		names.Insert(schedulerName)
+
+		if p := profile.Plugins; p != nil {
+			extensionPoints := []struct {
+				name string
+				set  *kops.SchedulerPluginSet
+			}{
+				{"queueSort", p.QueueSort}, {"preFilter", p.PreFilter}, {"filter", p.Filter}, {"postFilter", p.PostFilter},
+				{"preScore", p.PreScore}, {"score", p.Score}, {"reserve", p.Reserve}, {"permit", p.Permit},
+				{"preBind", p.PreBind}, {"bind", p.Bind}, {"postBind", p.PostBind}, {"unreserve", p.Unreserve},
+			}
+			for _, extensionPoint := range extensionPoints {
+				set := extensionPoint.set
+				if set == nil {
+					continue
+				}
+				setPath := profilePath.Child("plugins", extensionPoint.name)
+				for j, plugin := range set.Enabled {
+					if plugin.Name == "" {
+						allErrs = append(allErrs, field.Required(setPath.Child("enabled").Index(j).Child("name"), ""))
+					}
+				}
+				for j, plugin := range set.Disabled {
+					if plugin.Name == "" {
+						allErrs = append(allErrs, field.Required(setPath.Child("disabled").Index(j).Child("name"), ""))
+					}
+				}
+			}
+		}
+
+		for j, config := range profile.PluginConfig {
+			if config.Name == "" {
+				allErrs = append(allErrs, field.Required(profilePath.Child("pluginConfig").Index(j).Child("name"), ""))
+			}
+		}
+	}
+
+	allErrs = append(allErrs, kubeschedulerconfig.ValidateOverrides(c.ConfigOverrides, fldPath.Child("configOverrides"))...)
+
+	return allErrs
+}
+
+func validateKubelet(k *kops.KubeletConfigSpec, fldPath *field.Path) field.ErrorList {
+	return kubeletconfig.ValidateOverrides(k.ConfigOverrides, fldPath.Child("configOverrides"))
+}
+
 func validateKubeAPIServer(v *kops.KubeAPIServerConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -273,6 +780,51 @@ func validateKubeAPIServer(v *kops.KubeAPIServerConfig, fldPath *field.Path) fie
 		}
 	}
 
+	if v.AuditPolicy != nil {
+		if v.AuditPolicyFile != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("auditPolicyFile"), "auditPolicyFile cannot be set together with auditPolicy"))
+		}
+		allErrs = append(allErrs, audit.ValidatePolicy(v.AuditPolicy, fldPath.Child("auditPolicy"))...)
+	}
+
+	if v.AuditWebhook != nil {
+		allErrs = append(allErrs, validateAuditWebhook(v, fldPath)...)
+	}
+
+	return allErrs
+}
+
+func validateAuditWebhook(c *kops.KubeAPIServerConfig, parentPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	v := c.AuditWebhook
+	fldPath := parentPath.Child("auditWebhook")
+
+	if c.AuditWebhookConfigFile != "" {
+		allErrs = append(allErrs, field.Forbidden(parentPath.Child("auditWebhookConfigFile"), "auditWebhookConfigFile cannot be set together with auditWebhook"))
+	}
+	if c.AuditPolicy == nil && c.AuditPolicyFile == "" {
+		allErrs = append(allErrs, field.Required(parentPath.Child("auditPolicy"), "the audit webhook requires an audit policy"))
+	}
+
+	if v.Server == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("server"), "server must be set"))
+	} else if u, err := url.Parse(v.Server); err != nil || u.Scheme != "https" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("server"), v.Server, "server must be an https URL"))
+	}
+
+	if v.CACertificate != "" {
+		if _, err := pki.ParsePEMCertificate([]byte(v.CACertificate)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("caCertificate"), v.CACertificate, fmt.Sprintf("not a valid PEM certificate: %v", err)))
+		}
+	}
+
+	if v.TokenSecret != "" {
+		for _, msg := range validation.NameIsDNSSubdomain(v.TokenSecret, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("tokenSecret"), v.TokenSecret, msg))
+		}
+	}
+
 	return allErrs
 }
 
@@ -410,3 +962,85 @@ func validateNetworkingCalico(v *kops.CalicoNetworkingSpec, e *kops.EtcdClusterS
 
 	return allErrs
 }
+
+var (
+	sysctlNameRegexp      = regexp.MustCompile(`^[a-z0-9_]+([./][a-zA-Z0-9_-]+)*$`)
+	moduleNameRegexp      = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	sshdKeywordRegexp     = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	auditRuleRegexp       = regexp.MustCompile(`^-[a-zA-Z] `)
+	auditRuleKeyRegexp    = regexp.MustCompile(`-k\s+[a-zA-Z0-9_-]+(\s|$)`)
+	systemdUnitNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9:_.@\\-]+$`)
+	systemdUnitRegexp     = regexp.MustCompile(`^[a-zA-Z0-9:_.@-]+\.(service|socket|timer|path|mount)$`)
+)
+
+func validateNodeHardening(spec *kops.NodeHardeningSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Profile != "" {
+		profiles := hardening.Profiles()
+		if !sets.NewString(profiles...).Has(spec.Profile) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("profile"), spec.Profile, profiles))
+		}
+	}
+
+	for k, v := range spec.Sysctls {
+		if !sysctlNameRegexp.MatchString(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sysctls").Key(k), k, "invalid kernel parameter name"))
+		}
+		if strings.Contains(v, "\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sysctls").Key(k), v, "value cannot span multiple lines"))
+		}
+	}
+
+	for i, m := range spec.BlacklistedModules {
+		if !moduleNameRegexp.MatchString(m) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("blacklistedModules").Index(i), m, "invalid kernel module name"))
+		}
+	}
+	for i, m := range spec.AllowedModules {
+		if !moduleNameRegexp.MatchString(m) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedModules").Index(i), m, "invalid kernel module name"))
+		}
+	}
+
+	for i, rule := range spec.AuditRules {
+		if strings.Contains(rule, "\n") || !auditRuleRegexp.MatchString(rule) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("auditRules").Index(i), rule, "must be a single auditctl rule"))
+		} else if !auditRuleKeyRegexp.MatchString(rule) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("auditRules").Index(i), rule, "must set a key with -k, so that the rule can be checked"))
+		}
+	}
+
+	for k, v := range spec.SSHD {
+		if !sshdKeywordRegexp.MatchString(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sshd").Key(k), k, "invalid sshd_config keyword"))
+		}
+		if strings.Contains(v, "\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sshd").Key(k), v, "value cannot span multiple lines"))
+		}
+	}
+
+	for i, u := range spec.DisabledServices {
+		if !systemdUnitRegexp.MatchString(u) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("disabledServices").Index(i), u, "must be the name of a systemd unit, e.g. rpcbind.service"))
+		}
+	}
+	for i, u := range spec.EnabledServices {
+		if !systemdUnitRegexp.MatchString(u) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("enabledServices").Index(i), u, "must be the name of a systemd unit, e.g. rpcbind.service"))
+		}
+	}
+
+	for k, v := range spec.FileModes {
+		if !strings.HasPrefix(k, "/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("fileModes").Key(k), k, "path must be absolute"))
+		}
+		if v != "" {
+			if _, err := hardening.ParseMode(v); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("fileModes").Key(k), v, err.Error()))
+			}
+		}
+	}
+
+	return allErrs
+}
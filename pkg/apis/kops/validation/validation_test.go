@@ -19,10 +19,12 @@ package validation
 import (
 	"testing"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 func Test_Validate_DNS(t *testing.T) {
@@ -147,6 +149,7 @@ func TestValidateSubnets(t *testing.T) {
 func TestValidateKubeAPIServer(t *testing.T) {
 	str := "foobar"
 	authzMode := "RBAC,Webhook"
+	auditPolicy := &runtime.RawExtension{Raw: []byte(`{"rules":[{"level":"Metadata"}]}`)}
 
 	grid := []struct {
 		Input          kops.KubeAPIServerConfig
@@ -188,6 +191,38 @@ func TestValidateKubeAPIServer(t *testing.T) {
 			},
 			ExpectedDetail: "Authorization mode Webhook requires AuthorizationWebhookConfigFile to be specified",
 		},
+		{
+			Input: kops.KubeAPIServerConfig{
+				AuditPolicy: auditPolicy,
+				AuditWebhook: &kops.AuditWebhookSpec{
+					Server:      "https://audit.example.com/events",
+					TokenSecret: "auditwebhooktoken",
+				},
+			},
+		},
+		{
+			Input: kops.KubeAPIServerConfig{
+				AuditPolicy:     &runtime.RawExtension{Raw: []byte(`{"rules":[{"level":"Everything"}]}`)},
+				AuditPolicyFile: "/srv/kubernetes/audit.yaml",
+			},
+			ExpectedErrors: []string{
+				"Forbidden::KubeAPIServer.auditPolicyFile",
+				"Unsupported value::KubeAPIServer.auditPolicy.rules[0].level",
+			},
+		},
+		{
+			Input: kops.KubeAPIServerConfig{
+				AuditWebhook: &kops.AuditWebhookSpec{
+					Server:        "http://audit.example.com/events",
+					CACertificate: "not a certificate",
+				},
+			},
+			ExpectedErrors: []string{
+				"Required value::KubeAPIServer.auditPolicy",
+				"Invalid value::KubeAPIServer.auditWebhook.server",
+				"Invalid value::KubeAPIServer.auditWebhook.caCertificate",
+			},
+		},
 	}
 	for _, g := range grid {
 		errs := validateKubeAPIServer(&g.Input, field.NewPath("KubeAPIServer"))
@@ -324,6 +359,475 @@ func Test_Validate_AdditionalPolicies(t *testing.T) {
 	}
 }
 
+func Test_Validate_ClusterAutoscaler(t *testing.T) {
+	grid := []struct {
+		CloudProvider  string
+		Input          kops.ClusterAutoscalerConfig
+		ExpectedErrors []string
+	}{
+		{
+			CloudProvider: "aws",
+			Input: kops.ClusterAutoscalerConfig{
+				Enabled:                       fi.Bool(true),
+				Expander:                      fi.String("least-waste"),
+				ScaleDownUtilizationThreshold: fi.String("0.6"),
+			},
+		},
+		{
+			CloudProvider: "gce",
+			Input: kops.ClusterAutoscalerConfig{
+				Enabled: fi.Bool(false),
+			},
+		},
+		{
+			CloudProvider: "gce",
+			Input: kops.ClusterAutoscalerConfig{
+				Enabled: fi.Bool(true),
+			},
+			ExpectedErrors: []string{"Forbidden::spec.clusterAutoscaler.enabled"},
+		},
+		{
+			CloudProvider: "aws",
+			Input: kops.ClusterAutoscalerConfig{
+				Enabled:                       fi.Bool(true),
+				Expander:                      fi.String("cheapest"),
+				ScaleDownUtilizationThreshold: fi.String("50%"),
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.clusterAutoscaler.expander", "Invalid value::spec.clusterAutoscaler.scaleDownUtilizationThreshold"},
+		},
+	}
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{CloudProvider: g.CloudProvider}
+		errs := validateClusterAutoscaler(spec, &g.Input, field.NewPath("spec", "clusterAutoscaler"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Storage(t *testing.T) {
+	grid := []struct {
+		CloudProvider  string
+		Input          kops.StorageSpec
+		ExpectedErrors []string
+	}{
+		{
+			CloudProvider: "aws",
+			Input: kops.StorageSpec{
+				CSI: &kops.CSISpec{Enabled: fi.Bool(true)},
+			},
+		},
+		{
+			CloudProvider: "gce",
+			Input: kops.StorageSpec{
+				CSI: &kops.CSISpec{Enabled: fi.Bool(false)},
+			},
+		},
+		{
+			CloudProvider: "gce",
+			Input: kops.StorageSpec{
+				CSI: &kops.CSISpec{Enabled: fi.Bool(true)},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.storage.csi.enabled"},
+		},
+	}
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{CloudProvider: g.CloudProvider}
+		errs := validateStorage(spec, &g.Input, field.NewPath("spec", "storage"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_EncryptionAtRest(t *testing.T) {
+	keyID := "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	grid := []struct {
+		CloudProvider    string
+		EncryptionConfig *bool
+		Input            kops.EncryptionAtRestSpec
+		ExpectedErrors   []string
+	}{
+		{
+			CloudProvider: "gce",
+			Input:         kops.EncryptionAtRestSpec{},
+		},
+		{
+			CloudProvider: "gce",
+			Input:         kops.EncryptionAtRestSpec{Provider: "secretbox", Resources: []string{"secrets", "configmaps"}},
+		},
+		{
+			CloudProvider:    "aws",
+			EncryptionConfig: fi.Bool(true),
+			Input:            kops.EncryptionAtRestSpec{Provider: "aescbc"},
+			ExpectedErrors:   []string{"Forbidden::spec.encryptionAtRest"},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.EncryptionAtRestSpec{Provider: "aesgcm", Resources: []string{""}},
+			ExpectedErrors: []string{"Unsupported value::spec.encryptionAtRest.provider", "Required value::spec.encryptionAtRest.resources[0]"},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.EncryptionAtRestSpec{Provider: "aescbc", KMS: &kops.KMSEncryptionAtRestSpec{KeyID: keyID}},
+			ExpectedErrors: []string{"Forbidden::spec.encryptionAtRest.kms"},
+		},
+		{
+			CloudProvider: "aws",
+			Input:         kops.EncryptionAtRestSpec{Provider: "kms", KMS: &kops.KMSEncryptionAtRestSpec{KeyID: keyID, Image: "aws-encryption-provider:v0.1.0"}},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.EncryptionAtRestSpec{Provider: "kms"},
+			ExpectedErrors: []string{"Required value::spec.encryptionAtRest.kms"},
+		},
+		{
+			CloudProvider:  "aws",
+			Input:          kops.EncryptionAtRestSpec{Provider: "kms", KMS: &kops.KMSEncryptionAtRestSpec{KeyID: "alias/kops", CacheSize: fi.Int32(-1)}},
+			ExpectedErrors: []string{"Invalid value::spec.encryptionAtRest.kms.keyID", "Required value::spec.encryptionAtRest.kms.image", "Invalid value::spec.encryptionAtRest.kms.cacheSize"},
+		},
+		{
+			CloudProvider:  "gce",
+			Input:          kops.EncryptionAtRestSpec{Provider: "kms", KMS: &kops.KMSEncryptionAtRestSpec{KeyID: keyID, Image: "aws-encryption-provider:v0.1.0"}},
+			ExpectedErrors: []string{"Forbidden::spec.encryptionAtRest.provider"},
+		},
+	}
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{CloudProvider: g.CloudProvider, EncryptionConfig: g.EncryptionConfig}
+		errs := validateEncryptionAtRest(spec, &g.Input, field.NewPath("spec", "encryptionAtRest"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Kubelet(t *testing.T) {
+	grid := []struct {
+		Input          kops.KubeletConfigSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.KubeletConfigSpec{},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ConfigOverrides: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"kubelet.config.k8s.io/v1beta1","kind":"KubeletConfiguration","containerLogMaxSize":"50Mi"}`)},
+			},
+		},
+		{
+			Input: kops.KubeletConfigSpec{
+				ConfigOverrides: &runtime.RawExtension{Raw: []byte(`{"containerLogMaxSize":"50Mi","notAField":true}`)},
+			},
+			ExpectedErrors: []string{"Invalid value::kubelet.configOverrides"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateKubelet(&g.Input, field.NewPath("kubelet"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_KubeScheduler(t *testing.T) {
+	grid := []struct {
+		Input          kops.KubeSchedulerConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.SchedulerProfile{
+					{},
+					{
+						SchedulerName: "bin-packing",
+						Plugins: &kops.SchedulerPlugins{
+							Score: &kops.SchedulerPluginSet{Enabled: []kops.SchedulerPlugin{{Name: "NodeResourcesMostAllocated"}}},
+						},
+					},
+				},
+				ConfigOverrides: &runtime.RawExtension{Raw: []byte(`{"percentageOfNodesToScore":50}`)},
+			},
+		},
+		{
+			Input: kops.KubeSchedulerConfig{
+				Profiles: []kops.SchedulerProfile{
+					{
+						Plugins: &kops.SchedulerPlugins{
+							Score: &kops.SchedulerPluginSet{Disabled: []kops.SchedulerPlugin{{}}},
+						},
+						PluginConfig: []kops.SchedulerPluginConfig{{}},
+					},
+					{SchedulerName: "default-scheduler"},
+				},
+				ConfigOverrides: &runtime.RawExtension{Raw: []byte(`{"percentageOfNodes":50}`)},
+			},
+			ExpectedErrors: []string{
+				"Required value::kubeScheduler.profiles[0].plugins.score.disabled[0].name",
+				"Required value::kubeScheduler.profiles[0].pluginConfig[0].name",
+				"Duplicate value::kubeScheduler.profiles[1].schedulerName",
+				"Invalid value::kubeScheduler.configOverrides",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateKubeScheduler(&g.Input, field.NewPath("kubeScheduler"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_SecretEncryption(t *testing.T) {
+	grid := []struct {
+		Input          kops.SecretEncryptionSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.SecretEncryptionSpec{
+				AWSKMS: &kops.AWSKMSSecretEncryptionSpec{KeyID: "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+			},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				AWSKMS: &kops.AWSKMSSecretEncryptionSpec{KeyID: "alias/kops"},
+			},
+			ExpectedErrors: []string{"Required value::spec.secretEncryption.awsKMS.region"},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				GCPKMS: &kops.GCPKMSSecretEncryptionSpec{KeyName: "projects/p/locations/global/keyRings/kops/cryptoKeys/state"},
+			},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				VaultTransit: &kops.VaultTransitSecretEncryptionSpec{Address: "https://vault:8200"},
+			},
+			ExpectedErrors: []string{"Required value::spec.secretEncryption.vaultTransit.keyName"},
+		},
+		{
+			Input:          kops.SecretEncryptionSpec{},
+			ExpectedErrors: []string{"Invalid value::spec.secretEncryption"},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				GCPKMS: &kops.GCPKMSSecretEncryptionSpec{KeyName: "projects/p/locations/global/keyRings/kops/cryptoKeys/state"},
+				Local:  &kops.LocalSecretEncryptionSpec{},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.secretEncryption"},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				PreviousKeys: []kops.SecretEncryptionSpec{
+					{Local: &kops.LocalSecretEncryptionSpec{}},
+				},
+			},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				Local: &kops.LocalSecretEncryptionSpec{},
+				PreviousKeys: []kops.SecretEncryptionSpec{
+					{VaultTransit: &kops.VaultTransitSecretEncryptionSpec{Address: "https://vault:8200"}},
+				},
+			},
+			ExpectedErrors: []string{"Required value::spec.secretEncryption.previousKeys[0].vaultTransit.keyName"},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				Local: &kops.LocalSecretEncryptionSpec{},
+				PreviousKeys: []kops.SecretEncryptionSpec{
+					{},
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.secretEncryption.previousKeys[0]"},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{
+				Local: &kops.LocalSecretEncryptionSpec{},
+				PreviousKeys: []kops.SecretEncryptionSpec{
+					{
+						Local:        &kops.LocalSecretEncryptionSpec{},
+						PreviousKeys: []kops.SecretEncryptionSpec{{Local: &kops.LocalSecretEncryptionSpec{}}},
+					},
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.secretEncryption.previousKeys[0].previousKeys"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateSecretEncryption(&g.Input, field.NewPath("spec", "secretEncryption"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Vault(t *testing.T) {
+	grid := []struct {
+		Input          kops.ClusterSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ClusterSpec{
+				SecretStore: "s3://bucket/cluster/secrets",
+				KeyStore:    "s3://bucket/cluster/pki",
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				SecretStore: "vault://vault.example.com:8200/secret/kops/cluster/secrets",
+				KeyStore:    "vault://vault.example.com:8200/secret/kops/cluster/pki",
+				Vault: &kops.VaultSpec{
+					AuthMethod:          "aws",
+					MasterRole:          "kops-master",
+					NodeRole:            "kops-node",
+					PKIMount:            "pki",
+					NodeCertificateRole: "kubelet",
+				},
+			},
+		},
+		{
+			Input: kops.ClusterSpec{
+				SecretStore: "vault://vault.example.com:8200/secret/kops/cluster/secrets",
+			},
+			ExpectedErrors: []string{"Required value::spec.vault"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				KeyStore: "vault://vault.example.com:8200/secret",
+				Vault:    &kops.VaultSpec{},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.keyStore", "Required value::spec.vault.authMethod"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				SecretStore: "vault://vault.example.com:8200/secret/kops/cluster/secrets",
+				Vault: &kops.VaultSpec{
+					AuthMethod:          "gcp",
+					MasterRole:          "kops-master",
+					NodeCertificateRole: "kubelet",
+				},
+			},
+			ExpectedErrors: []string{"Required value::spec.vault.nodeRole", "Required value::spec.vault.pkiMount"},
+		},
+		{
+			Input: kops.ClusterSpec{
+				SecretStore: "vault://vault.example.com:8200/secret/kops/cluster/secrets",
+				Vault: &kops.VaultSpec{
+					AuthMethod: "kubernetes",
+				},
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.vault.authMethod"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateVault(&g.Input, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Addon(t *testing.T) {
+	grid := []struct {
+		Input          kops.AddonSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AddonSpec{
+				Manifest: "s3://bucket/addons/addon.yaml",
+			},
+		},
+		{
+			Input: kops.AddonSpec{
+				Name: "example.addons.k8s.io",
+				Chart: &kops.ChartSpec{
+					Repository: "https://charts.example.com/stable",
+					Name:       "example",
+					Version:    "1.2.3",
+					Values:     "replicas: 2\n",
+				},
+			},
+		},
+		{
+			Input:          kops.AddonSpec{},
+			ExpectedErrors: []string{"Required value::spec.addons[0]"},
+		},
+		{
+			Input: kops.AddonSpec{
+				Manifest: "s3://bucket/addons/addon.yaml",
+				Chart: &kops.ChartSpec{
+					Repository: "https://charts.example.com/stable",
+					Name:       "example",
+				},
+			},
+			ExpectedErrors: []string{"Forbidden::spec.addons[0].manifest", "Required value::spec.addons[0].name", "Required value::spec.addons[0].chart.version"},
+		},
+		{
+			Input: kops.AddonSpec{
+				Name: "Example",
+				Chart: &kops.ChartSpec{
+					Name:    "https://charts.example.com/example-1.0.0.tgz",
+					Version: "latest",
+					Values:  "- not a map",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.addons[0].name", "Invalid value::spec.addons[0].chart.version", "Invalid value::spec.addons[0].chart.values"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAddonSpec(&g.Input, field.NewPath("spec", "addons").Index(0))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_AddonOverride(t *testing.T) {
+	grid := []struct {
+		Input          kops.AddonOverrideSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AddonOverrideSpec{
+				Addon: "dns-controller.addons.k8s.io",
+				Kind:  "Deployment",
+				Name:  "dns-controller",
+				Patch: "spec:\n  replicas: 2\n",
+			},
+		},
+		{
+			Input: kops.AddonOverrideSpec{
+				Addon: "coredns.addons.k8s.io",
+				Kind:  "ConfigMap",
+				Name:  "coredns",
+				Type:  "json",
+				Patch: "- op: add\n  path: /data/extra\n  value: example\n",
+			},
+		},
+		{
+			Input:          kops.AddonOverrideSpec{},
+			ExpectedErrors: []string{"Required value::spec.addonOverrides[0].addon", "Required value::spec.addonOverrides[0].kind", "Required value::spec.addonOverrides[0].name", "Required value::spec.addonOverrides[0].patch"},
+		},
+		{
+			Input: kops.AddonOverrideSpec{
+				Addon: "coredns.addons.k8s.io",
+				Kind:  "ConfigMap",
+				Name:  "coredns",
+				Patch: "- not a map",
+			},
+			ExpectedErrors: []string{"Invalid value::spec.addonOverrides[0].patch"},
+		},
+		{
+			Input: kops.AddonOverrideSpec{
+				Addon: "coredns.addons.k8s.io",
+				Kind:  "ConfigMap",
+				Name:  "coredns",
+				Type:  "json",
+				Patch: "- value: example",
+			},
+			ExpectedErrors: []string{"Required value::spec.addonOverrides[0].patch[0].op", "Required value::spec.addonOverrides[0].patch[0].path"},
+		},
+		{
+			Input: kops.AddonOverrideSpec{
+				Addon: "coredns.addons.k8s.io",
+				Kind:  "ConfigMap",
+				Name:  "coredns",
+				Type:  "merge",
+				Patch: "data: {}",
+			},
+			ExpectedErrors: []string{"Unsupported value::spec.addonOverrides[0].type"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAddonOverride(&g.Input, field.NewPath("spec", "addonOverrides").Index(0))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 type caliInput struct {
 	Calico *kops.CalicoNetworkingSpec
 	Etcd   *kops.EtcdClusterSpec
@@ -367,3 +871,173 @@ func Test_Validate_Calico(t *testing.T) {
 		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_NodeHardening(t *testing.T) {
+	grid := []struct {
+		Input          kops.NodeHardeningSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.NodeHardeningSpec{
+				Profile:            "cis-level2",
+				Sysctls:            map[string]string{"net.ipv4.conf.eth0/1.rp_filter": "1", "fs.suid_dumpable": ""},
+				BlacklistedModules: []string{"usb-storage"},
+				AuditRules:         []string{"-w /var/lib/etcd -p wa -k etcd"},
+				SSHD:               map[string]string{"PermitRootLogin": "no"},
+				DisabledServices:   []string{"rpcbind.socket"},
+				FileModes:          map[string]string{"/etc/kubernetes": "0640", "/srv/kubernetes": ""},
+			},
+		},
+		{
+			Input: kops.NodeHardeningSpec{
+				Profile: "strict",
+			},
+			ExpectedErrors: []string{"Unsupported value::nodeHardening.profile"},
+		},
+		{
+			Input: kops.NodeHardeningSpec{
+				Sysctls:            map[string]string{"kernel.panic": "1\nkernel.panic_on_oops = 1"},
+				BlacklistedModules: []string{"usb storage"},
+				AuditRules:         []string{"-w /etc/passwd -p wa", "-e 2\n-w /etc -k etc"},
+				SSHD:               map[string]string{"Match User": "admin"},
+				EnabledServices:    []string{"cups"},
+				FileModes:          map[string]string{"etc/kubernetes": "0800"},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::nodeHardening.sysctls[kernel.panic]",
+				"Invalid value::nodeHardening.blacklistedModules[0]",
+				"Invalid value::nodeHardening.auditRules[0]",
+				"Invalid value::nodeHardening.auditRules[1]",
+				"Invalid value::nodeHardening.sshd[Match User]",
+				"Invalid value::nodeHardening.enabledServices[0]",
+				"Invalid value::nodeHardening.fileModes[etc/kubernetes]",
+				"Invalid value::nodeHardening.fileModes[etc/kubernetes]",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodeHardening(&g.Input, field.NewPath("nodeHardening"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_SysctlParameters(t *testing.T) {
+	grid := []struct {
+		Input          []string
+		ExpectedErrors []string
+	}{
+		{
+			Input: []string{"net.ipv4.tcp_keepalive_time=200", "net.core.somaxconn = 1024"},
+		},
+		{
+			Input:          []string{"net.core.somaxconn", "=1", "vm.swappiness=1\nvm.overcommit_memory=1"},
+			ExpectedErrors: []string{"Invalid value::sysctlParameters[0]", "Invalid value::sysctlParameters[1]", "Invalid value::sysctlParameters[2]"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateSysctlParameters(g.Input, field.NewPath("sysctlParameters"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_KernelModules(t *testing.T) {
+	grid := []struct {
+		Input          []kops.KernelModuleSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.KernelModuleSpec{{Name: "br_netfilter"}, {Name: "nf_conntrack", Parameters: []string{"hashsize=32768"}}},
+		},
+		{
+			Input: []kops.KernelModuleSpec{{Name: "ip vs"}, {Name: "nf_conntrack", Parameters: []string{"hashsize", "a=1 b=2"}}, {Name: "nf_conntrack"}},
+			ExpectedErrors: []string{
+				"Invalid value::kernelModules[0].name",
+				"Invalid value::kernelModules[1].parameters[0]",
+				"Invalid value::kernelModules[1].parameters[1]",
+				"Duplicate value::kernelModules[2].name",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateKernelModules(g.Input, field.NewPath("kernelModules"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_SystemdUnits(t *testing.T) {
+	grid := []struct {
+		Input          []kops.SystemdUnitSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.SystemdUnitSpec{
+				{Name: "log-shipper.service", After: []string{"docker.service"}, Manifest: "ExecStart=/bin/true"},
+				{Name: "node-agent", Before: []string{"kubelet"}, Manifest: "ExecStart=/bin/true"},
+				{Name: "cleanup.timer", Disabled: true},
+			},
+		},
+		{
+			Input: []kops.SystemdUnitSpec{
+				{Name: "log shipper.service", Manifest: "ExecStart=/bin/true"},
+				{Name: "multi-user.target", Manifest: "Wants=log-shipper.service"},
+				{Name: "node-agent", Requires: []string{"node-agent.service"}},
+				{Name: "node-agent.service", Manifest: "ExecStart=/bin/true"},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::systemdUnits[0].name",
+				"Unsupported value::systemdUnits[1].name",
+				"Required value::systemdUnits[2].manifest",
+				"Invalid value::systemdUnits[2].requires[0]",
+				"Duplicate value::systemdUnits[3].name",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateSystemdUnits(g.Input, field.NewPath("systemdUnits"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Assets(t *testing.T) {
+	grid := []struct {
+		KubernetesVersion string
+		Input             kops.Assets
+		ExpectedErrors    []string
+	}{
+		{
+			KubernetesVersion: "1.15.0",
+			Input: kops.Assets{
+				PinImageDigests: fi.Bool(true),
+				FileSignatureKey: fi.String(`-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEk+l1ClW9wOIwWLVeKgcDdejsNWAL
+GzBIU6oHIta4+xRNgik7x6eVfn6gcXuLR1mjl8J1JdFEqc4BpZUeget7Hg==
+-----END PUBLIC KEY-----`),
+			},
+		},
+		{
+			KubernetesVersion: "1.15.0",
+			Input: kops.Assets{
+				FileSignatureKey: fi.String("not a key"),
+			},
+			ExpectedErrors: []string{"Invalid value::assets.fileSignatureKey"},
+		},
+		{
+			KubernetesVersion: "https://storage.googleapis.com/kubernetes-release-dev/ci/v1.15.0-beta.1",
+			Input: kops.Assets{
+				PinImageDigests: fi.Bool(true),
+			},
+			ExpectedErrors: []string{"Forbidden::assets.pinImageDigests"},
+		},
+		{
+			KubernetesVersion: "https://storage.googleapis.com/kubernetes-release-dev/ci/v1.15.0-beta.1",
+			Input: kops.Assets{
+				PinImageDigests: fi.Bool(false),
+			},
+		},
+	}
+	for _, g := range grid {
+		spec := &kops.ClusterSpec{KubernetesVersion: g.KubernetesVersion, Assets: &g.Input}
+		errs := validateAssets(spec, &g.Input, field.NewPath("assets"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -40,6 +40,13 @@ type Config struct {
 
 	// Manifests for running etcd
 	EtcdManifests []string `json:"etcdManifests,omitempty"`
+
+	// ImageDigests maps the images of the cluster spec which it does not pin itself, such as those of hooks, to their pinned form
+	ImageDigests map[string]string `json:"imageDigests,omitempty"`
+	// RequireImageDigests makes nodeup refuse to run images which are not pinned to a digest
+	RequireImageDigests bool `json:"requireImageDigests,omitempty"`
+	// FileSignatureKey is the PEM encoded public key the detached signatures of file assets are verified against
+	FileSignatureKey string `json:"fileSignatureKey,omitempty"`
 }
 
 // Image is a docker image we should pre-load
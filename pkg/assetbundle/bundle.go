@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package assetbundle reads and writes asset bundles, tar archives holding the files, container images and
+// manifests a cluster needs, so that they can be carried into an environment without network access.
+//
+// A bundle has the layout:
+//
+//   index.yaml          the Index of the bundle
+//   files/<path>        the file assets, at the path they have in a file repository
+//   images/             the container images, as an OCI image layout
+//   manifests/<path>    the channel and the addon manifests of the cluster
+package assetbundle
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// IndexPath is the path of the Index within a bundle
+	IndexPath = "index.yaml"
+
+	// FilesDir is the directory of the file assets within a bundle
+	FilesDir = "files"
+	// ImagesDir is the directory of the OCI image layout within a bundle
+	ImagesDir = "images"
+	// ManifestsDir is the directory of the channel and addon manifests within a bundle
+	ManifestsDir = "manifests"
+)
+
+// Index lists the contents of a bundle
+type Index struct {
+	// KopsVersion is the version of kops which exported the bundle
+	KopsVersion string `json:"kopsVersion,omitempty"`
+	// ClusterName is the name of the cluster the bundle was exported for
+	ClusterName string `json:"clusterName,omitempty"`
+	// KubernetesVersion is the kubernetes version of the cluster the bundle was exported for
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Channel is the path of the channel, relative to the manifests of the bundle
+	Channel string `json:"channel,omitempty"`
+
+	Files     []*File     `json:"files,omitempty"`
+	Images    []*Image    `json:"images,omitempty"`
+	Manifests []*Manifest `json:"manifests,omitempty"`
+}
+
+// File is a file asset in a bundle
+type File struct {
+	// Path is the path of the file, relative both to the files of the bundle and to a file repository
+	Path string `json:"path"`
+	// CanonicalURL is the location the file was exported from
+	CanonicalURL string `json:"canonicalURL,omitempty"`
+	// SHA is the hex encoded hash of the file, sha1 or sha256
+	SHA string `json:"sha"`
+	// Signature is the detached signature published alongside the file, if the cluster sets fileSignatureKey
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Image is a container image in a bundle
+type Image struct {
+	// Name is the name of the image relative to a container registry, as assets.containerRegistry names it
+	Name string `json:"name"`
+	// CanonicalImage is the image the bundle was exported from
+	CanonicalImage string `json:"canonicalImage,omitempty"`
+	// Digest is the digest of the image manifest in the OCI image layout of the bundle
+	Digest string `json:"digest"`
+}
+
+// Manifest is a channel or addon manifest in a bundle
+type Manifest struct {
+	// Path is the path of the manifest, relative both to the manifests of the bundle and to a file repository
+	Path string `json:"path"`
+}
+
+// ImageName returns the name an image has within a container registry, matching the remapping
+// AssetBuilder.RemapImage applies when assets.containerRegistry is set.
+func ImageName(image string) string {
+	name := image
+	if strings.HasPrefix(name, "gcr.io/google_containers/") {
+		name = strings.TrimPrefix(name, "gcr.io/google_containers/")
+	} else {
+		name = strings.TrimPrefix(name, "k8s.gcr.io/")
+	}
+	return strings.Replace(name, "/", "-", -1)
+}
+
+// cleanPath validates a path found in a bundle, returning it relative to the bundle root
+func cleanPath(p string) (string, error) {
+	cleaned := path.Clean("/" + p)
+	if cleaned == "/" || strings.Contains(p, "..") {
+		return "", fmt.Errorf("invalid path %q in bundle", p)
+	}
+	return strings.TrimPrefix(cleaned, "/"), nil
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assetbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestImageName(t *testing.T) {
+	grid := map[string]string{
+		"k8s.gcr.io/kube-apiserver:v1.15.0":             "kube-apiserver:v1.15.0",
+		"gcr.io/google_containers/kube-proxy:v1.9.3":    "kube-proxy:v1.9.3",
+		"kope/dns-controller:1.15.0":                    "kope-dns-controller:1.15.0",
+		"quay.io/coreos/flannel:v0.11.0-amd64":          "quay.io-coreos-flannel:v0.11.0-amd64",
+		"docker.io/library/busybox@sha256:0123456789ab": "docker.io-library-busybox@sha256:0123456789ab",
+	}
+	for image, expected := range grid {
+		if actual := ImageName(image); actual != expected {
+			t.Errorf("ImageName(%q) was %q, expected %q", image, actual, expected)
+		}
+	}
+}
+
+func TestSplitImageName(t *testing.T) {
+	grid := []struct {
+		Name       string
+		Repository string
+		Reference  string
+	}{
+		{"kube-apiserver:v1.15.0", "kube-apiserver", "v1.15.0"},
+		{"busybox", "busybox", "latest"},
+		{"busybox@sha256:0123", "busybox", "sha256:0123"},
+		{"localhost:5000/busybox", "localhost:5000/busybox", "latest"},
+	}
+	for _, g := range grid {
+		repository, reference := splitImageName(g.Name)
+		if repository != g.Repository || reference != g.Reference {
+			t.Errorf("splitImageName(%q) was %q, %q, expected %q, %q", g.Name, repository, reference, g.Repository, g.Reference)
+		}
+	}
+}
+
+func TestParseRegistry(t *testing.T) {
+	r, err := ParseRegistry("http://registry.example.com:5000/kops/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Host != "registry.example.com:5000" || r.Prefix != "kops" || !r.Insecure {
+		t.Errorf("unexpected registry %+v", r)
+	}
+	if r.Location() != "registry.example.com:5000/kops" {
+		t.Errorf("unexpected location %q", r.Location())
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "assetbundle")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archivePath := filepath.Join(tmpDir, "image.tar")
+	writeDockerArchive(t, archivePath)
+
+	kubelet := []byte("kubelet binary")
+	kubeletSHA := fmt.Sprintf("%x", sha1.Sum(kubelet))
+
+	var bundle bytes.Buffer
+	w := NewWriter(&bundle)
+	w.Index.ClusterName = "minimal.example.com"
+	if err := w.AddFile("/kubernetes-release/release/v1.15.0/bin/linux/amd64/kubelet", "https://storage.googleapis.com/kubernetes-release/release/v1.15.0/bin/linux/amd64/kubelet", kubeletSHA, kubelet, []byte("kubelet signature")); err != nil {
+		t.Fatalf("error adding file: %v", err)
+	}
+	if err := w.AddFile("/other", "https://example.com/other", kubeletSHA, []byte("tampered"), nil); err == nil {
+		t.Errorf("expected error adding file with mismatched hash")
+	}
+	if err := w.AddManifest("addons/bootstrap-channel.yaml", []byte("kind: Addons\n")); err != nil {
+		t.Fatalf("error adding manifest: %v", err)
+	}
+	if err := w.AddDockerArchive("k8s.gcr.io/pause:3.1", archivePath); err != nil {
+		t.Fatalf("error adding image: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing bundle: %v", err)
+	}
+
+	extractDir := filepath.Join(tmpDir, "extract")
+	index, err := Extract(&bundle, extractDir)
+	if err != nil {
+		t.Fatalf("error extracting bundle: %v", err)
+	}
+	if index.ClusterName != "minimal.example.com" || len(index.Files) != 1 || len(index.Images) != 1 || len(index.Manifests) != 1 {
+		t.Fatalf("unexpected index %+v", index)
+	}
+	if index.Images[0].Name != "pause:3.1" {
+		t.Errorf("unexpected image name %q", index.Images[0].Name)
+	}
+
+	manifest, _, err := readImageManifest(filepath.Join(extractDir, ImagesDir), index.Images[0].Digest)
+	if err != nil {
+		t.Fatalf("error reading image manifest: %v", err)
+	}
+	if len(manifest.Layers) != 2 || manifest.Layers[0].Digest != manifest.Layers[1].Digest {
+		t.Errorf("expected the linked layer to share the digest of its target: %+v", manifest.Layers)
+	}
+
+	registry := newFakeRegistry()
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	r, err := ParseRegistry(server.URL + "/mirror")
+	if err != nil {
+		t.Fatalf("error parsing registry: %v", err)
+	}
+
+	fileRepository := vfs.NewFSPath(filepath.Join(tmpDir, "repository"))
+	importer := &Importer{
+		Dir:            extractDir,
+		Index:          index,
+		Registry:       r,
+		FileRepository: fileRepository,
+	}
+	if err := importer.Import(); err != nil {
+		t.Fatalf("error importing bundle: %v", err)
+	}
+
+	if _, found := registry.manifests["mirror/pause:3.1"]; !found {
+		t.Errorf("image not pushed, manifests: %v", registry.manifests)
+	}
+	if len(registry.blobs) != 2 {
+		t.Errorf("expected config and layer blobs to be pushed, got %d blobs", len(registry.blobs))
+	}
+
+	data, err := fileRepository.Join("kubernetes-release/release/v1.15.0/bin/linux/amd64/kubelet.sha1").ReadFile()
+	if err != nil {
+		t.Fatalf("error reading hash file: %v", err)
+	}
+	if string(data) != kubeletSHA {
+		t.Errorf("unexpected hash file %q", data)
+	}
+	data, err = fileRepository.Join("kubernetes-release/release/v1.15.0/bin/linux/amd64/kubelet.sig").ReadFile()
+	if err != nil {
+		t.Fatalf("error reading signature file: %v", err)
+	}
+	if string(data) != "kubelet signature" {
+		t.Errorf("unexpected signature file %q", data)
+	}
+	if _, err := fileRepository.Join("addons/bootstrap-channel.yaml").ReadFile(); err != nil {
+		t.Errorf("manifest not imported: %v", err)
+	}
+}
+
+// writeDockerArchive writes an archive as docker save does, with the second layer linking to the first
+func writeDockerArchive(t *testing.T, archivePath string) {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("error creating archive: %v", err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	add := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+	}
+
+	manifest, _ := json.Marshal([]dockerArchiveManifest{{
+		Config:   "abc.json",
+		RepoTags: []string{"k8s.gcr.io/pause:3.1"},
+		Layers:   []string{"layer1/layer.tar", "layer2/layer.tar"},
+	}})
+	add("abc.json", []byte(`{"architecture":"amd64","os":"linux"}`))
+	add("layer1/layer.tar", []byte("layer contents"))
+	if err := tw.WriteHeader(&tar.Header{Name: "layer2/layer.tar", Linkname: "../layer1/layer.tar", Typeflag: tar.TypeSymlink}); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+	add("manifest.json", manifest)
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+}
+
+// fakeRegistry implements the parts of the registry HTTP API V2 used to push images
+type fakeRegistry struct {
+	mutex     sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		blobs:     make(map[string][]byte),
+		manifests: make(map[string][]byte),
+	}
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	p := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case req.Method == http.MethodHead && strings.Contains(p, "/blobs/"):
+		digest := p[strings.LastIndex(p, "/")+1:]
+		if _, found := f.blobs[digest]; !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+
+	case req.Method == http.MethodPost && strings.HasSuffix(p, "/blobs/uploads/"):
+		w.Header().Set("Location", "/v2/"+p+"upload-1")
+		w.WriteHeader(http.StatusAccepted)
+
+	case req.Method == http.MethodPut && strings.Contains(p, "/blobs/uploads/"):
+		data, _ := ioutil.ReadAll(req.Body)
+		digest := req.URL.Query().Get("digest")
+		if digestOf(data) != digest {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.blobs[digest] = data
+		w.WriteHeader(http.StatusCreated)
+
+	case req.Method == http.MethodPut && strings.Contains(p, "/manifests/"):
+		tokens := strings.SplitN(p, "/manifests/", 2)
+		data, _ := ioutil.ReadAll(req.Body)
+		f.manifests[tokens[0]+":"+tokens[1]] = data
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
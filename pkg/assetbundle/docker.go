@@ -0,0 +1,39 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assetbundle
+
+import (
+	"fmt"
+	"os/exec"
+
+	"k8s.io/klog"
+)
+
+// SaveDockerImage pulls an image and writes it to archivePath with docker save, shelling out to the docker CLI
+func SaveDockerImage(image string, archivePath string) error {
+	klog.V(4).Infof("docker pull for image %q", image)
+	if out, err := exec.Command("docker", "pull", image).CombinedOutput(); err != nil {
+		return fmt.Errorf("error pulling image %q: %v: %s", image, err, out)
+	}
+
+	klog.V(4).Infof("docker save for image %q", image)
+	if out, err := exec.Command("docker", "save", "-o", archivePath, image).CombinedOutput(); err != nil {
+		return fmt.Errorf("error saving image %q: %v: %s", image, err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assetbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Extract unpacks a bundle into dir, returning its Index
+func Extract(r io.Reader, dir string) (*Index, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading bundle: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		p, err := cleanPath(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, fmt.Errorf("error creating directory for %q: %v", target, err)
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %q: %v", target, err)
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error writing %q: %v", target, err)
+		}
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, IndexPath))
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle index: %v", err)
+	}
+	index := &Index{}
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("error parsing bundle index: %v", err)
+	}
+	return index, nil
+}
+
+// Importer pushes the contents of an extracted bundle to a container registry and a file repository
+type Importer struct {
+	// Dir is the directory the bundle was extracted to
+	Dir string
+	// Index is the index of the bundle
+	Index *Index
+
+	// Registry receives the images of the bundle; images are skipped if nil
+	Registry *Registry
+	// FileRepository is the vfs location receiving the files and manifests of the bundle; they are skipped if nil
+	FileRepository vfs.Path
+}
+
+// Import pushes the images, files and manifests of the bundle
+func (i *Importer) Import() error {
+	if i.Registry != nil {
+		layoutDir := filepath.Join(i.Dir, ImagesDir)
+		for _, image := range i.Index.Images {
+			if err := i.Registry.PushImage(layoutDir, image.Name, image.Digest); err != nil {
+				return err
+			}
+		}
+	}
+
+	if i.FileRepository != nil {
+		for _, file := range i.Index.Files {
+			if err := i.importFile(file); err != nil {
+				return err
+			}
+		}
+
+		for _, manifest := range i.Index.Manifests {
+			p, err := cleanPath(manifest.Path)
+			if err != nil {
+				return err
+			}
+			data, err := ioutil.ReadFile(filepath.Join(i.Dir, ManifestsDir, filepath.FromSlash(p)))
+			if err != nil {
+				return fmt.Errorf("error reading manifest %q: %v", p, err)
+			}
+			if err := i.FileRepository.Join(p).WriteFile(bytes.NewReader(data), nil); err != nil {
+				return fmt.Errorf("error writing manifest %q: %v", p, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// importFile writes a file to the file repository with its hash and signature files, as the assets phase of kops update cluster does
+func (i *Importer) importFile(file *File) error {
+	p, err := cleanPath(file.Path)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(i.Dir, FilesDir, filepath.FromSlash(p)))
+	if err != nil {
+		return fmt.Errorf("error reading file %q: %v", p, err)
+	}
+
+	expected, err := hashing.FromString(file.SHA)
+	if err != nil {
+		return fmt.Errorf("error parsing hash of %q: %v", p, err)
+	}
+	actual, err := expected.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if !actual.Equal(expected) {
+		return fmt.Errorf("hash of %q in bundle was %s, expected %s", p, actual.Hex(), expected.Hex())
+	}
+
+	target := i.FileRepository.Join(p)
+	klog.Infof("uploading %q to %q", p, target)
+	if err := target.WriteFile(bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing %q: %v", target, err)
+	}
+
+	if len(file.Signature) != 0 {
+		signaturePath := i.FileRepository.Join(p + assets.SignatureSuffix)
+		if err := signaturePath.WriteFile(bytes.NewReader(file.Signature), nil); err != nil {
+			return fmt.Errorf("error writing %q: %v", signaturePath, err)
+		}
+	}
+
+	hashPath := i.FileRepository.Join(p + ".sha1")
+	if err := hashPath.WriteFile(bytes.NewReader([]byte(expected.Hex())), nil); err != nil {
+		return fmt.Errorf("error writing %q: %v", hashPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assetbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Media types of the OCI image specification
+const (
+	MediaTypeImageManifest = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeImageConfig   = "application/vnd.oci.image.config.v1+json"
+	MediaTypeLayer         = "application/vnd.oci.image.layer.v1.tar"
+	MediaTypeLayerGzip     = "application/vnd.oci.image.layer.v1.tar+gzip"
+)
+
+// annotationRefName is the annotation naming an image in the index of an OCI image layout
+const annotationRefName = "org.opencontainers.image.ref.name"
+
+// Descriptor describes a blob of an OCI image
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ImageManifest is an OCI image manifest
+type ImageManifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        Descriptor   `json:"config"`
+	Layers        []Descriptor `json:"layers"`
+}
+
+// imageIndex is the index.json of an OCI image layout
+type imageIndex struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Manifests     []Descriptor `json:"manifests"`
+}
+
+// imageLayout is the oci-layout file of an OCI image layout
+type imageLayout struct {
+	ImageLayoutVersion string `json:"imageLayoutVersion"`
+}
+
+// dockerArchiveManifest is an entry of the manifest.json written by docker save
+type dockerArchiveManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// blobPath returns the path of a blob within an OCI image layout
+func blobPath(digest string) (string, error) {
+	tokens := strings.SplitN(digest, ":", 2)
+	if len(tokens) != 2 || tokens[0] != "sha256" || len(tokens[1]) != sha256.Size*2 {
+		return "", fmt.Errorf("unsupported digest %q", digest)
+	}
+	if _, err := hex.DecodeString(tokens[1]); err != nil {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return path.Join("blobs", tokens[0], tokens[1]), nil
+}
+
+// digestOf returns the sha256 digest of data
+func digestOf(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+// archiveEntry is the digest of an entry of a docker archive
+type archiveEntry struct {
+	digest string
+	size   int64
+	gzip   bool
+}
+
+// scanDockerArchive reads the manifest of a docker archive, and computes the digests of its entries
+func scanDockerArchive(archivePath string) (*dockerArchiveManifest, map[string]*archiveEntry, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening %q: %v", archivePath, err)
+	}
+	defer f.Close()
+
+	var manifests []dockerArchiveManifest
+	entries := make(map[string]*archiveEntry)
+	links := make(map[string]string)
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading %q: %v", archivePath, err)
+		}
+
+		name := path.Clean(hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeSymlink:
+			// docker save links identical layers to a single copy
+			links[name] = path.Join(path.Dir(name), hdr.Linkname)
+			continue
+		case tar.TypeReg, tar.TypeRegA:
+		default:
+			continue
+		}
+
+		if name == "manifest.json" {
+			if err := json.NewDecoder(tr).Decode(&manifests); err != nil {
+				return nil, nil, fmt.Errorf("error parsing manifest.json in %q: %v", archivePath, err)
+			}
+			continue
+		}
+
+		h := sha256.New()
+		var magic bytes.Buffer
+		n, err := io.Copy(h, io.TeeReader(tr, &limitedBuffer{buf: &magic, limit: 2}))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading %q in %q: %v", name, archivePath, err)
+		}
+		entries[name] = &archiveEntry{
+			digest: fmt.Sprintf("sha256:%x", h.Sum(nil)),
+			size:   n,
+			gzip:   bytes.Equal(magic.Bytes(), []byte{0x1f, 0x8b}),
+		}
+	}
+
+	for name, target := range links {
+		if e := entries[target]; e != nil {
+			entries[name] = e
+		}
+	}
+
+	if len(manifests) != 1 {
+		return nil, nil, fmt.Errorf("expected a single image in %q, found %d", archivePath, len(manifests))
+	}
+	return &manifests[0], entries, nil
+}
+
+// limitedBuffer records the first bytes written to it
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining > 0 {
+		if len(p) < remaining {
+			remaining = len(p)
+		}
+		b.buf.Write(p[:remaining])
+	}
+	return len(p), nil
+}
+
+// readImageManifest reads the manifest of an image from an OCI image layout on disk
+func readImageManifest(layoutDir string, digest string) (*ImageManifest, []byte, error) {
+	p, err := blobPath(digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(layoutDir, filepath.FromSlash(p)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading manifest %s: %v", digest, err)
+	}
+	if digestOf(data) != digest {
+		return nil, nil, fmt.Errorf("manifest %s does not match its digest", digest)
+	}
+
+	manifest := &ImageManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, nil, fmt.Errorf("error parsing manifest %s: %v", digest, err)
+	}
+	return manifest, data, nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assetbundle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+// Registry pushes images to a container registry with the docker registry HTTP API V2
+type Registry struct {
+	// Host is the host, and optionally port, of the registry
+	Host string
+	// Prefix is the repository path the images are pushed under, if any
+	Prefix string
+	// Insecure is true if the registry is served over plain http
+	Insecure bool
+
+	// Username and Password are used for basic authentication, if set
+	Username string
+	Password string
+
+	Client *http.Client
+}
+
+// ParseRegistry builds a Registry from a location such as registry.example.com:5000/kops,
+// the form assets.containerRegistry takes
+func ParseRegistry(location string) (*Registry, error) {
+	location = strings.TrimSuffix(location, "/")
+	r := &Registry{}
+	switch {
+	case strings.HasPrefix(location, "http://"):
+		r.Insecure = true
+		location = strings.TrimPrefix(location, "http://")
+	case strings.HasPrefix(location, "https://"):
+		location = strings.TrimPrefix(location, "https://")
+	}
+
+	tokens := strings.SplitN(location, "/", 2)
+	r.Host = tokens[0]
+	if len(tokens) == 2 {
+		r.Prefix = tokens[1]
+	}
+	if r.Host == "" {
+		return nil, fmt.Errorf("invalid registry %q", location)
+	}
+	return r, nil
+}
+
+// Location returns the registry in the form assets.containerRegistry takes
+func (r *Registry) Location() string {
+	if r.Prefix == "" {
+		return r.Host
+	}
+	return r.Host + "/" + r.Prefix
+}
+
+// PushImage pushes an image of an OCI image layout on disk, tagging it with name
+func (r *Registry) PushImage(layoutDir string, name string, digest string) error {
+	repository, reference := splitImageName(name)
+	if r.Prefix != "" {
+		repository = r.Prefix + "/" + repository
+	}
+
+	manifest, manifestData, err := readImageManifest(layoutDir, digest)
+	if err != nil {
+		return err
+	}
+
+	blobs := append([]Descriptor{manifest.Config}, manifest.Layers...)
+	for _, blob := range blobs {
+		if err := r.pushBlob(layoutDir, repository, blob); err != nil {
+			return fmt.Errorf("error pushing %s of %q: %v", blob.Digest, name, err)
+		}
+	}
+
+	mediaType := manifest.MediaType
+	if mediaType == "" {
+		mediaType = MediaTypeImageManifest
+	}
+	req, err := http.NewRequest(http.MethodPut, r.url("/v2/"+repository+"/manifests/"+reference), bytes.NewReader(manifestData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mediaType)
+	if _, err := r.do(req, http.StatusCreated); err != nil {
+		return fmt.Errorf("error pushing manifest of %q: %v", name, err)
+	}
+
+	klog.Infof("pushed %q to %s/%s", name, r.Host, repository)
+	return nil
+}
+
+// pushBlob uploads a blob in a single request, unless the registry already has it
+func (r *Registry) pushBlob(layoutDir string, repository string, blob Descriptor) error {
+	req, err := http.NewRequest(http.MethodHead, r.url("/v2/"+repository+"/blobs/"+blob.Digest), nil)
+	if err != nil {
+		return err
+	}
+	if _, err := r.do(req, http.StatusOK); err == nil {
+		klog.V(4).Infof("blob %s already exists in %s", blob.Digest, repository)
+		return nil
+	}
+
+	req, err = http.NewRequest(http.MethodPost, r.url("/v2/"+repository+"/blobs/uploads/"), nil)
+	if err != nil {
+		return err
+	}
+	response, err := r.do(req, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid upload location %q: %v", response.Header.Get("Location"), err)
+	}
+	base, err := url.Parse(r.url("/"))
+	if err != nil {
+		return err
+	}
+	upload := base.ResolveReference(location)
+	query := upload.Query()
+	query.Set("digest", blob.Digest)
+	upload.RawQuery = query.Encode()
+
+	p, err := blobPath(blob.Digest)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Join(layoutDir, filepath.FromSlash(p)))
+	if err != nil {
+		return fmt.Errorf("error opening blob: %v", err)
+	}
+	defer f.Close()
+
+	req, err = http.NewRequest(http.MethodPut, upload.String(), f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = blob.Size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if _, err := r.do(req, http.StatusCreated); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Registry) url(p string) string {
+	scheme := "https"
+	if r.Insecure {
+		scheme = "http"
+	}
+	return scheme + "://" + r.Host + p
+}
+
+func (r *Registry) do(req *http.Request, expected int) (*http.Response, error) {
+	if r.Username != "" {
+		req.SetBasicAuth(r.Username, r.Password)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != expected {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return nil, fmt.Errorf("unexpected status %q from %s %s: %s", response.Status, req.Method, req.URL, body)
+	}
+	return response, nil
+}
+
+// splitImageName splits an image name into its repository and its tag or digest
+func splitImageName(name string) (string, string) {
+	if i := strings.Index(name, "@"); i != -1 {
+		return name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i != -1 && !strings.Contains(name[i:], "/") {
+		return name[:i], name[i+1:]
+	}
+	return name, "latest"
+}
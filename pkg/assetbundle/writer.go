@@ -0,0 +1,298 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assetbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/klog"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+// Writer writes a bundle as a tar stream
+type Writer struct {
+	// Index is written to the bundle on Close; the files, images and manifests added are recorded in it
+	Index Index
+
+	tw      *tar.Writer
+	modTime time.Time
+
+	paths         map[string]bool
+	blobs         map[string]bool
+	imageManifest []Descriptor
+}
+
+// NewWriter builds a Writer writing to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		tw:      tar.NewWriter(w),
+		modTime: time.Now(),
+		paths:   make(map[string]bool),
+		blobs:   make(map[string]bool),
+	}
+}
+
+// AddFile adds a file asset, verifying that it matches its sha1 or sha256 hash.
+// The signature is the detached signature of the file, or nil if it is not signed.
+func (w *Writer) AddFile(p string, canonicalURL string, sha string, data []byte, signature []byte) error {
+	p, err := cleanPath(p)
+	if err != nil {
+		return err
+	}
+	if w.paths[path.Join(FilesDir, p)] {
+		return nil
+	}
+
+	expected, err := hashing.FromString(sha)
+	if err != nil {
+		return fmt.Errorf("error parsing hash of %q: %v", canonicalURL, err)
+	}
+	actual, err := expected.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if !actual.Equal(expected) {
+		return fmt.Errorf("hash of %q was %s, expected %s", canonicalURL, actual.Hex(), expected.Hex())
+	}
+
+	if err := w.writeEntry(path.Join(FilesDir, p), data); err != nil {
+		return err
+	}
+
+	w.Index.Files = append(w.Index.Files, &File{
+		Path:         p,
+		CanonicalURL: canonicalURL,
+		SHA:          expected.Hex(),
+		Signature:    signature,
+	})
+	return nil
+}
+
+// AddManifest adds a channel or addon manifest
+func (w *Writer) AddManifest(p string, data []byte) error {
+	p, err := cleanPath(p)
+	if err != nil {
+		return err
+	}
+	if w.paths[path.Join(ManifestsDir, p)] {
+		return nil
+	}
+
+	if err := w.writeEntry(path.Join(ManifestsDir, p), data); err != nil {
+		return err
+	}
+
+	w.Index.Manifests = append(w.Index.Manifests, &Manifest{Path: p})
+	return nil
+}
+
+// AddDockerArchive adds an image from an archive written by docker save, converting it to the OCI image layout
+func (w *Writer) AddDockerArchive(canonicalImage string, archivePath string) error {
+	name := ImageName(canonicalImage)
+	for _, image := range w.Index.Images {
+		if image.Name == name {
+			return nil
+		}
+	}
+
+	dockerManifest, entries, err := scanDockerArchive(archivePath)
+	if err != nil {
+		return err
+	}
+
+	config := entries[path.Clean(dockerManifest.Config)]
+	if config == nil {
+		return fmt.Errorf("config %q of %q not found in archive", dockerManifest.Config, canonicalImage)
+	}
+
+	manifest := &ImageManifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config: Descriptor{
+			MediaType: MediaTypeImageConfig,
+			Digest:    config.digest,
+			Size:      config.size,
+		},
+	}
+
+	needed := map[string]bool{config.digest: true}
+	for _, layer := range dockerManifest.Layers {
+		e := entries[path.Clean(layer)]
+		if e == nil {
+			return fmt.Errorf("layer %q of %q not found in archive", layer, canonicalImage)
+		}
+		mediaType := MediaTypeLayer
+		if e.gzip {
+			mediaType = MediaTypeLayerGzip
+		}
+		manifest.Layers = append(manifest.Layers, Descriptor{
+			MediaType: mediaType,
+			Digest:    e.digest,
+			Size:      e.size,
+		})
+		needed[e.digest] = true
+	}
+
+	if err := w.copyArchiveBlobs(archivePath, entries, needed); err != nil {
+		return err
+	}
+
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("error serializing manifest of %q: %v", canonicalImage, err)
+	}
+	manifestDigest := digestOf(manifestData)
+	if err := w.writeBlob(manifestDigest, manifestData); err != nil {
+		return err
+	}
+
+	w.imageManifest = append(w.imageManifest, Descriptor{
+		MediaType:   MediaTypeImageManifest,
+		Digest:      manifestDigest,
+		Size:        int64(len(manifestData)),
+		Annotations: map[string]string{annotationRefName: name},
+	})
+	w.Index.Images = append(w.Index.Images, &Image{
+		Name:           name,
+		CanonicalImage: canonicalImage,
+		Digest:         manifestDigest,
+	})
+
+	klog.V(2).Infof("added image %q as %s", canonicalImage, manifestDigest)
+	return nil
+}
+
+// copyArchiveBlobs copies the needed entries of a docker archive to the blobs of the bundle
+func (w *Writer) copyArchiveBlobs(archivePath string, entries map[string]*archiveEntry, needed map[string]bool) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %v", archivePath, err)
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading %q: %v", archivePath, err)
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		e := entries[path.Clean(hdr.Name)]
+		if e == nil || !needed[e.digest] || w.blobs[e.digest] {
+			continue
+		}
+
+		p, err := blobPath(e.digest)
+		if err != nil {
+			return err
+		}
+		if err := w.tw.WriteHeader(w.header(path.Join(ImagesDir, p), e.size)); err != nil {
+			return fmt.Errorf("error writing bundle: %v", err)
+		}
+		if _, err := io.Copy(w.tw, tr); err != nil {
+			return fmt.Errorf("error writing bundle: %v", err)
+		}
+		w.blobs[e.digest] = true
+	}
+
+	for digest := range needed {
+		if !w.blobs[digest] {
+			return fmt.Errorf("blob %s not found in %q", digest, archivePath)
+		}
+	}
+	return nil
+}
+
+// writeBlob writes a blob of the OCI image layout, unless it was already written
+func (w *Writer) writeBlob(digest string, data []byte) error {
+	if w.blobs[digest] {
+		return nil
+	}
+	p, err := blobPath(digest)
+	if err != nil {
+		return err
+	}
+	if err := w.writeEntry(path.Join(ImagesDir, p), data); err != nil {
+		return err
+	}
+	w.blobs[digest] = true
+	return nil
+}
+
+// Close writes the OCI image layout index and the bundle index, and flushes the tar stream
+func (w *Writer) Close() error {
+	layout, err := json.Marshal(&imageLayout{ImageLayoutVersion: "1.0.0"})
+	if err != nil {
+		return err
+	}
+	if err := w.writeEntry(path.Join(ImagesDir, "oci-layout"), layout); err != nil {
+		return err
+	}
+
+	index, err := json.Marshal(&imageIndex{SchemaVersion: 2, Manifests: w.imageManifest})
+	if err != nil {
+		return err
+	}
+	if err := w.writeEntry(path.Join(ImagesDir, "index.json"), index); err != nil {
+		return err
+	}
+
+	y, err := yaml.Marshal(&w.Index)
+	if err != nil {
+		return fmt.Errorf("error serializing bundle index: %v", err)
+	}
+	if err := w.writeEntry(IndexPath, y); err != nil {
+		return err
+	}
+
+	return w.tw.Close()
+}
+
+func (w *Writer) writeEntry(p string, data []byte) error {
+	if err := w.tw.WriteHeader(w.header(p, int64(len(data)))); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %v", p, err)
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return fmt.Errorf("error writing %q to bundle: %v", p, err)
+	}
+	w.paths[p] = true
+	return nil
+}
+
+func (w *Writer) header(p string, size int64) *tar.Header {
+	return &tar.Header{
+		Name:     p,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  w.modTime,
+		Typeflag: tar.TypeReg,
+	}
+}
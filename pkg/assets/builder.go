@@ -51,6 +51,11 @@ type AssetBuilder struct {
 
 	// KubernetesVersion is the version of kubernetes we are installing
 	KubernetesVersion semver.Version
+
+	// ImageDigests records the images we pinned to a digest, keyed by the image before pinning
+	ImageDigests map[string]string
+	// ResolveDigest resolves an image to its digest; if nil, images are not pinned
+	ResolveDigest func(image string) (string, error)
 }
 
 // ContainerAsset models a container's location.
@@ -77,6 +82,7 @@ func NewAssetBuilder(cluster *kops.Cluster, phase string) *AssetBuilder {
 	a := &AssetBuilder{
 		AssetsLocation: cluster.Spec.Assets,
 		Phase:          phase,
+		ImageDigests:   make(map[string]string),
 	}
 
 	version, err := util.ParseKubernetesVersion(cluster.Spec.KubernetesVersion)
@@ -197,9 +203,47 @@ func (a *AssetBuilder) RemapImage(image string) (string, error) {
 	}
 
 	a.ContainerAssets = append(a.ContainerAssets, asset)
+
+	// We pin the image we run, not the one we copy, so the copy tasks still push by tag.
+	// During the assets phase the images are not yet in the containerRegistry, so we cannot pin them.
+	if a.Phase != "assets" {
+		return a.PinImage(image)
+	}
 	return image, nil
 }
 
+// PinImage returns the image pinned to its digest, if AssetsLocation enables PinImageDigests.
+// Images which are already pinned are returned unchanged.
+func (a *AssetBuilder) PinImage(image string) (string, error) {
+	if a.AssetsLocation == nil || !values.BoolValue(a.AssetsLocation.PinImageDigests) {
+		return image, nil
+	}
+
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+	if pinned, found := a.ImageDigests[image]; found {
+		return pinned, nil
+	}
+	if a.ResolveDigest == nil {
+		return image, nil
+	}
+
+	digest, err := a.ResolveDigest(image)
+	if err != nil {
+		return "", fmt.Errorf("unable to pin image %q to a digest: %v", image, err)
+	}
+	pinned := image + "@" + digest
+
+	if a.ImageDigests == nil {
+		a.ImageDigests = make(map[string]string)
+	}
+	a.ImageDigests[image] = pinned
+	klog.V(4).Infof("pinned image %q to %q", image, pinned)
+
+	return pinned, nil
+}
+
 // RemapFileAndSHA returns a remapped url for the file, if AssetsLocation is defined.
 // It also returns the SHA hash of the file.
 func (a *AssetBuilder) RemapFileAndSHA(fileURL *url.URL) (*url.URL, *hashing.Hash, error) {
@@ -266,6 +310,53 @@ func (a *AssetBuilder) RemapFileAndSHAValue(fileURL *url.URL, shaValue string) (
 	return fileAsset.DownloadURL, nil
 }
 
+// ReadFileAsset reads a file which is not published with a hash file, such as a chart archive, returning its contents.
+// If AssetsLocation defines a FileRepository, the file is read from there (or from its canonical location during the
+// assets phase) and is registered to be copied there.
+func (a *AssetBuilder) ReadFileAsset(fileURL *url.URL) ([]byte, error) {
+	if fileURL == nil {
+		return nil, fmt.Errorf("unable to read a nil URL")
+	}
+
+	fileAsset := &FileAsset{
+		DownloadURL: fileURL,
+	}
+
+	if a.AssetsLocation != nil && a.AssetsLocation.FileRepository != nil {
+		fileAsset.CanonicalURL = fileURL
+
+		normalizedFileURL, err := a.remapURL(fileURL)
+		if err != nil {
+			return nil, err
+		}
+
+		fileAsset.DownloadURL = normalizedFileURL
+
+		klog.V(4).Infof("adding remapped file: %+v", fileAsset)
+	}
+
+	u := fileAsset.DownloadURL
+	if a.Phase == "assets" && fileAsset.CanonicalURL != nil {
+		u = fileAsset.CanonicalURL
+	}
+
+	data, err := vfs.Context.ReadFile(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %v", u, err)
+	}
+
+	h, err := hashing.HashAlgorithmSHA1.Hash(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	fileAsset.SHAValue = h.Hex()
+
+	a.FileAssets = append(a.FileAssets, fileAsset)
+	klog.V(8).Infof("adding file: %+v", fileAsset)
+
+	return data, nil
+}
+
 // FindHash returns the hash value of a FileAsset.
 func (a *AssetBuilder) findHash(file *FileAsset) (*hashing.Hash, error) {
 
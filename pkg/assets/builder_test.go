@@ -18,10 +18,12 @@ package assets
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/values"
 )
 
 func buildAssetBuilder(t *testing.T) *AssetBuilder {
@@ -158,3 +160,55 @@ func TestValidate_RemapImage_ContainerRegistry_MappingMultipleTimesConverges(t *
 	}
 
 }
+
+func TestRemapImage_PinImageDigests(t *testing.T) {
+	builder := buildAssetBuilder(t)
+	builder.AssetsLocation.PinImageDigests = values.Bool(true)
+
+	resolved := 0
+	builder.ResolveDigest = func(image string) (string, error) {
+		resolved++
+		if image == "missing/image:1.0" {
+			return "", errors.New("manifest unknown")
+		}
+		return "sha256:0123456789abcdef", nil
+	}
+
+	grid := map[string]string{
+		"quay.io/coreos/flannel:v0.11.0":                   "quay.io/coreos/flannel:v0.11.0@sha256:0123456789abcdef",
+		"quay.io/coreos/flannel:v0.11.0@sha256:fedcba9876": "quay.io/coreos/flannel:v0.11.0@sha256:fedcba9876",
+	}
+	for image, expected := range grid {
+		for i := 0; i < 2; i++ {
+			pinned, err := builder.RemapImage(image)
+			if err != nil {
+				t.Fatalf("unexpected error pinning %q: %v", image, err)
+			}
+			if pinned != expected {
+				t.Errorf("RemapImage(%q) was %q, expected %q", image, pinned, expected)
+			}
+		}
+	}
+	if resolved != 1 {
+		t.Errorf("expected the digest to be resolved once, was resolved %d times", resolved)
+	}
+
+	for _, a := range builder.ContainerAssets {
+		if strings.HasSuffix(a.DockerImage, "@sha256:0123456789abcdef") {
+			t.Errorf("expected the asset to be copied by its unpinned name, was %q", a.DockerImage)
+		}
+	}
+
+	if _, err := builder.RemapImage("missing/image:1.0"); err == nil {
+		t.Errorf("expected error pinning an image which cannot be resolved")
+	}
+
+	builder.Phase = "assets"
+	image, err := builder.RemapImage("kope/dns-controller:1.15.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image != "kope/dns-controller:1.15.0" {
+		t.Errorf("expected images not to be pinned during the assets phase, was %q", image)
+	}
+}
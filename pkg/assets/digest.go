@@ -0,0 +1,272 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/credentialprovider"
+)
+
+// manifestMediaTypes are the manifest types we accept when resolving a digest; manifest lists come first,
+// so that the digest of a multi-architecture image stays valid on every architecture
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// ImageReference is a parsed container image name
+type ImageReference struct {
+	// Registry is the host of the registry, such as k8s.gcr.io or registry-1.docker.io
+	Registry string
+	// Repository is the name of the image within the registry
+	Repository string
+	// Tag is the tag of the image, if any
+	Tag string
+	// Digest is the digest of the image, if any
+	Digest string
+}
+
+// ParseImageReference parses an image name, applying the docker hub defaults
+func ParseImageReference(image string) (*ImageReference, error) {
+	ref := &ImageReference{}
+
+	name := image
+	if i := strings.Index(name, "@"); i != -1 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i != -1 && !strings.Contains(name[i:], "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+	if name == "" {
+		return nil, fmt.Errorf("invalid image %q", image)
+	}
+
+	tokens := strings.SplitN(name, "/", 2)
+	if len(tokens) == 2 && (strings.ContainsAny(tokens[0], ".:") || tokens[0] == "localhost") {
+		ref.Registry = tokens[0]
+		ref.Repository = tokens[1]
+	} else {
+		ref.Registry = "docker.io"
+		ref.Repository = name
+	}
+	if ref.Registry == "docker.io" || ref.Registry == "index.docker.io" {
+		ref.Registry = "registry-1.docker.io"
+		if !strings.Contains(ref.Repository, "/") {
+			ref.Repository = "library/" + ref.Repository
+		}
+	}
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// DigestResolver resolves image tags to digests with the docker registry HTTP API V2.
+// Registries requiring authentication are queried with the credentials the Keyring finds for them, or anonymously.
+type DigestResolver struct {
+	Client *http.Client
+	// Keyring provides the credentials of private registries, such as those docker login writes to the docker config
+	Keyring credentialprovider.DockerKeyring
+}
+
+// ResolveImageDigest returns the sha256 digest of the manifest an image currently refers to
+func (r *DigestResolver) ResolveImageDigest(image string) (string, error) {
+	ref, err := ParseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+
+	u := "https://" + ref.Registry + "/v2/" + ref.Repository + "/manifests/" + ref.Tag
+	response, err := r.getManifest(u, "")
+	if err != nil {
+		return "", fmt.Errorf("error resolving digest of %q: %v", image, err)
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		response.Body.Close()
+
+		authorization, err := r.authorize(imageName(image, ref), challenge)
+		if err != nil {
+			return "", fmt.Errorf("error authenticating to resolve digest of %q: %v", image, err)
+		}
+		response, err = r.getManifest(u, authorization)
+		if err != nil {
+			return "", fmt.Errorf("error resolving digest of %q: %v", image, err)
+		}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		return "", fmt.Errorf("error resolving digest of %q: unexpected status %q, log in to the registry with docker login", image, response.Status)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error resolving digest of %q: unexpected status %q", image, response.Status)
+	}
+
+	// The registry reports the digest, but we hash the manifest ourselves so we never pin a digest we did not see
+	h := sha256.New()
+	if _, err := io.Copy(h, response.Body); err != nil {
+		return "", fmt.Errorf("error reading manifest of %q: %v", image, err)
+	}
+	digest := fmt.Sprintf("sha256:%x", h.Sum(nil))
+	if reported := response.Header.Get("Docker-Content-Digest"); reported != "" && reported != digest {
+		return "", fmt.Errorf("registry reported digest %s for %q, but the manifest hashed to %s", reported, image, digest)
+	}
+
+	klog.V(2).Infof("resolved %q to %s", image, digest)
+	return digest, nil
+}
+
+func (r *DigestResolver) getManifest(u string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return r.client().Do(req)
+}
+
+// imageName returns the name of an image without its tag and digest, as the keyring looks credentials up by
+func imageName(image string, ref *ImageReference) string {
+	name := image
+	if i := strings.Index(name, "@"); i != -1 {
+		name = name[:i]
+	}
+	if ref.Tag != "" {
+		name = strings.TrimSuffix(name, ":"+ref.Tag)
+	}
+	return name
+}
+
+// authorize answers the WWW-Authenticate challenge of a registry, returning the Authorization header to retry with
+func (r *DigestResolver) authorize(name string, challenge string) (string, error) {
+	var username, password string
+	if r.Keyring != nil {
+		if creds, found := r.Keyring.Lookup(name); found && len(creds) != 0 {
+			auth := credentialprovider.LazyProvide(creds[0])
+			if auth.RegistryToken != "" {
+				return "Bearer " + auth.RegistryToken, nil
+			}
+			username, password = auth.Username, auth.Password
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(challenge, "Basic "):
+		if username == "" {
+			return "", fmt.Errorf("registry requires credentials, but none were found for %q; log in with docker login (credential helpers are not supported)", name)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	case strings.HasPrefix(challenge, "Bearer "):
+		token, err := r.fetchToken(challenge, username, password)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+// fetchToken requests a bearer token, as described by the WWW-Authenticate challenge of a registry.
+// The token is requested anonymously if username is empty.
+func (r *DigestResolver) fetchToken(challenge string, username string, password string) (string, error) {
+	params := make(map[string]string)
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		tokens := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(tokens) == 2 {
+			params[tokens[0]] = strings.Trim(tokens[1], `"`)
+		}
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("no realm in authentication challenge %q", challenge)
+	}
+
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid realm %q: %v", params["realm"], err)
+	}
+	query := u.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			query.Set(k, params[k])
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	response, err := r.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %q from %s", response.Status, u.Host)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", fmt.Errorf("error parsing token response: %v", err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	return tokenResponse.AccessToken, nil
+}
+
+func (r *DigestResolver) client() *http.Client {
+	if r.Client != nil {
+		return r.Client
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/credentialprovider"
+)
+
+func TestParseImageReference(t *testing.T) {
+	grid := map[string]ImageReference{
+		"k8s.gcr.io/kube-apiserver:v1.15.0":          {Registry: "k8s.gcr.io", Repository: "kube-apiserver", Tag: "v1.15.0"},
+		"kope/dns-controller:1.15.0":                 {Registry: "registry-1.docker.io", Repository: "kope/dns-controller", Tag: "1.15.0"},
+		"busybox":                                    {Registry: "registry-1.docker.io", Repository: "library/busybox", Tag: "latest"},
+		"localhost:5000/pause":                       {Registry: "localhost:5000", Repository: "pause", Tag: "latest"},
+		"quay.io/coreos/flannel:v0.11.0@sha256:0123": {Registry: "quay.io", Repository: "coreos/flannel", Tag: "v0.11.0", Digest: "sha256:0123"},
+	}
+	for image, expected := range grid {
+		ref, err := ParseImageReference(image)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", image, err)
+			continue
+		}
+		if *ref != expected {
+			t.Errorf("ParseImageReference(%q) was %+v, expected %+v", image, *ref, expected)
+		}
+	}
+}
+
+func TestResolveImageDigest(t *testing.T) {
+	manifest := `{"schemaVersion":2}`
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(manifest)))
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/token":
+			if req.URL.Query().Get("scope") != "repository:kube-apiserver:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token":"anonymous"}`)
+		case "/v2/kube-apiserver/manifests/v1.15.0":
+			if req.Header.Get("Authorization") != "Bearer anonymous" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry",scope="repository:kube-apiserver:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if !strings.Contains(req.Header.Get("Accept"), "manifest.list.v2+json") {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+			fmt.Fprint(w, manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := &DigestResolver{Client: server.Client()}
+	registry := strings.TrimPrefix(server.URL, "https://")
+
+	actual, err := resolver.ResolveImageDigest(registry + "/kube-apiserver:v1.15.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != digest {
+		t.Errorf("resolved digest %q, expected %q", actual, digest)
+	}
+
+	if _, err := resolver.ResolveImageDigest(registry + "/kube-scheduler:v1.15.0"); err == nil {
+		t.Errorf("expected error resolving a missing image")
+	}
+}
+
+func TestResolveImageDigestWithCredentials(t *testing.T) {
+	manifest := `{"schemaVersion":2}`
+	digest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(manifest)))
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if username, password, ok := req.BasicAuth(); !ok || username != "kops" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.URL.Path != "/v2/private/pause/manifests/3.1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, manifest)
+	}))
+	defer server.Close()
+
+	registry := strings.TrimPrefix(server.URL, "https://")
+	image := registry + "/private/pause:3.1"
+
+	anonymous := &DigestResolver{Client: server.Client()}
+	if _, err := anonymous.ResolveImageDigest(image); err == nil {
+		t.Errorf("expected error resolving a private image without credentials")
+	}
+
+	keyring := &credentialprovider.BasicDockerKeyring{}
+	keyring.Add(credentialprovider.DockerConfig{
+		registry: credentialprovider.DockerConfigEntry{Username: "kops", Password: "secret"},
+	})
+	resolver := &DigestResolver{Client: server.Client(), Keyring: keyring}
+	actual, err := resolver.ResolveImageDigest(image)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != digest {
+		t.Errorf("resolved digest %q, expected %q", actual, digest)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+)
+
+// SignatureSuffix is appended to the URL of a file asset to find its detached signature
+const SignatureSuffix = ".sig"
+
+// ParseSignatureKey parses the PEM encoded public key which file asset signatures are verified against
+func ParseSignatureKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in signature key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signature key: %v", err)
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature key type %T, expected RSA or ECDSA", key)
+	}
+}
+
+// VerifyFileSignature verifies a detached signature of the file at p, made over its sha256 hash with RSA PKCS #1 v1.5
+// or ECDSA, as `openssl dgst -sha256 -sign` does. The signature may be raw or base64 encoded.
+func VerifyFileSignature(key crypto.PublicKey, p string, signature []byte) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %v", p, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("error reading %q: %v", p, err)
+	}
+
+	return verifySignature(key, h.Sum(nil), signature)
+}
+
+// VerifySignature verifies a detached signature of data, as VerifyFileSignature does for a file
+func VerifySignature(key crypto.PublicKey, data []byte, signature []byte) error {
+	digest := sha256.Sum256(data)
+	return verifySignature(key, digest[:], signature)
+}
+
+// ecdsaSignature is the ASN.1 encoding of an ECDSA signature
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+func verifySignature(key crypto.PublicKey, digest []byte, signature []byte) error {
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature))); err == nil {
+		signature = decoded
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature); err != nil {
+			return fmt.Errorf("signature verification failed: %v", err)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		var sig ecdsaSignature
+		if rest, err := asn1.Unmarshal(signature, &sig); err != nil || len(rest) != 0 {
+			return fmt.Errorf("error parsing ECDSA signature")
+		}
+		if !ecdsa.Verify(key, digest, sig.R, sig.S) {
+			return fmt.Errorf("signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported signature key type %T", key)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testSignatureKey and testSignature were made with
+// openssl dgst -sha256 -sign key.pem file | base64
+const testSignatureKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEk+l1ClW9wOIwWLVeKgcDdejsNWAL
+GzBIU6oHIta4+xRNgik7x6eVfn6gcXuLR1mjl8J1JdFEqc4BpZUeget7Hg==
+-----END PUBLIC KEY-----
+`
+
+const testSignature = "MEUCICfXDA8cO1uaGIKy3xdqhBOBi1mYdjIQsWIDad1UhnyFAiEAgqz2qGHy2P/P+5+cq+Ic3WlacGxNt+GvUpjjjsdQ+kM=\n"
+
+func TestVerifyFileSignature(t *testing.T) {
+	key, err := ParseSignatureKey(testSignatureKey)
+	if err != nil {
+		t.Fatalf("unexpected error parsing key: %v", err)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "signature")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	p := filepath.Join(tmpDir, "kubelet")
+	if err := ioutil.WriteFile(p, []byte("kubelet binary"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	if err := VerifyFileSignature(key, p, []byte(testSignature)); err != nil {
+		t.Errorf("unexpected error verifying signature: %v", err)
+	}
+
+	if err := ioutil.WriteFile(p, []byte("tampered binary"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	if err := VerifyFileSignature(key, p, []byte(testSignature)); err == nil {
+		t.Errorf("expected error verifying the signature of a tampered file")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, err := ParseSignatureKey(testSignatureKey)
+	if err != nil {
+		t.Fatalf("unexpected error parsing key: %v", err)
+	}
+
+	if err := VerifySignature(key, []byte("kubelet binary"), []byte(testSignature)); err != nil {
+		t.Errorf("unexpected error verifying signature: %v", err)
+	}
+	if err := VerifySignature(key, []byte("tampered binary"), []byte(testSignature)); err == nil {
+		t.Errorf("expected error verifying the signature of tampered data")
+	}
+}
+
+func TestParseSignatureKey(t *testing.T) {
+	if _, err := ParseSignatureKey("not a key"); err == nil {
+		t.Errorf("expected error parsing an invalid key")
+	}
+}
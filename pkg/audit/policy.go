@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/apis/audit"
+	"k8s.io/apiserver/pkg/apis/audit/install"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	auditv1beta1 "k8s.io/apiserver/pkg/apis/audit/v1beta1"
+	auditvalidation "k8s.io/apiserver/pkg/apis/audit/validation"
+	"k8s.io/kops/pkg/apis/kops/util"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	install.Install(scheme)
+}
+
+// DefaultAPIVersion returns the apiVersion of audit policies understood by the version of kubernetes
+func DefaultAPIVersion(kubernetesVersion semver.Version) string {
+	if util.IsKubernetesGTE("1.12", kubernetesVersion) {
+		return auditv1.SchemeGroupVersion.String()
+	}
+	return auditv1beta1.SchemeGroupVersion.String()
+}
+
+// PolicyYAML returns the policy as the contents of an audit policy file,
+// setting its apiVersion and kind if they were omitted
+func PolicyYAML(policy *runtime.RawExtension, defaultAPIVersion string) ([]byte, error) {
+	obj, err := withTypeMeta(policy, defaultAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling audit policy: %v", err)
+	}
+	return data, nil
+}
+
+// ValidatePolicy checks the policy against the audit API types, rejecting unknown fields,
+// and then applies the validation of the kube-apiserver
+func ValidatePolicy(policy *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	obj, err := withTypeMeta(policy, auditv1.SchemeGroupVersion.String())
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath, string(policy.Raw), err.Error()))
+	}
+
+	var versioned runtime.Object
+	switch obj["apiVersion"] {
+	case auditv1.SchemeGroupVersion.String():
+		versioned = &auditv1.Policy{}
+	case auditv1beta1.SchemeGroupVersion.String():
+		versioned = &auditv1beta1.Policy{}
+	default:
+		return append(allErrs, field.NotSupported(fldPath.Child("apiVersion"), obj["apiVersion"], []string{auditv1.SchemeGroupVersion.String(), auditv1beta1.SchemeGroupVersion.String()}))
+	}
+	if obj["kind"] != "Policy" {
+		return append(allErrs, field.NotSupported(fldPath.Child("kind"), obj["kind"], []string{"Policy"}))
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(versioned); err != nil {
+		return append(allErrs, field.Invalid(fldPath, string(policy.Raw), fmt.Sprintf("not a valid audit policy: %v", err)))
+	}
+
+	internal := &audit.Policy{}
+	if err := scheme.Convert(versioned, internal, nil); err != nil {
+		return append(allErrs, field.InternalError(fldPath, err))
+	}
+
+	for _, e := range auditvalidation.ValidatePolicy(internal) {
+		e.Field = fldPath.String() + "." + e.Field
+		allErrs = append(allErrs, e)
+	}
+	return allErrs
+}
+
+// withTypeMeta parses the policy, setting its apiVersion and kind if they were omitted
+func withTypeMeta(policy *runtime.RawExtension, defaultAPIVersion string) (map[string]interface{}, error) {
+	if policy == nil || len(policy.Raw) == 0 {
+		return nil, fmt.Errorf("audit policy is empty")
+	}
+
+	obj := make(map[string]interface{})
+	if err := json.Unmarshal(policy.Raw, &obj); err != nil {
+		return nil, fmt.Errorf("error parsing audit policy: %v", err)
+	}
+	if _, found := obj["apiVersion"]; !found {
+		obj["apiVersion"] = defaultAPIVersion
+	}
+	if _, found := obj["kind"]; !found {
+		obj["kind"] = "Policy"
+	}
+	return obj, nil
+}
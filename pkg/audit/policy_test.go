@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func parsePolicy(t *testing.T, s string) *runtime.RawExtension {
+	policy := &runtime.RawExtension{}
+	if err := yaml.Unmarshal([]byte(s), policy); err != nil {
+		t.Fatalf("error parsing policy: %v", err)
+	}
+	return policy
+}
+
+func TestPolicyYAML(t *testing.T) {
+	policy := parsePolicy(t, `
+omitStages:
+- RequestReceived
+rules:
+- level: Metadata
+`)
+
+	actual, err := PolicyYAML(policy, "audit.k8s.io/v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: Metadata
+`
+	if string(actual) != expected {
+		t.Errorf("unexpected policy\nexpected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestValidatePolicy(t *testing.T) {
+	grid := []struct {
+		Policy         string
+		ExpectedErrors []string
+	}{
+		{
+			Policy: `
+rules:
+- level: RequestResponse
+  resources:
+  - group: ""
+    resources: ["secrets"]
+`,
+		},
+		{
+			Policy: `
+apiVersion: audit.k8s.io/v1beta1
+kind: Policy
+rules:
+- level: None
+  nonResourceURLs: ["/healthz*"]
+`,
+		},
+		{
+			Policy: `
+rules:
+- level: Everything
+`,
+			ExpectedErrors: []string{"spec.auditPolicy.rules[0].level"},
+		},
+		{
+			Policy: `
+rules:
+- level: Metadata
+  resource: ["secrets"]
+`,
+			ExpectedErrors: []string{"unknown field"},
+		},
+		{
+			Policy: `
+apiVersion: audit.k8s.io/v2
+rules:
+- level: Metadata
+`,
+			ExpectedErrors: []string{"spec.auditPolicy.apiVersion"},
+		},
+	}
+
+	for _, g := range grid {
+		errs := ValidatePolicy(parsePolicy(t, g.Policy), field.NewPath("spec", "auditPolicy"))
+		if len(errs) != len(g.ExpectedErrors) {
+			t.Errorf("policy %s: expected errors %v, got %v", g.Policy, g.ExpectedErrors, errs)
+			continue
+		}
+		for i, e := range errs {
+			if !strings.Contains(e.Error(), g.ExpectedErrors[i]) {
+				t.Errorf("policy %s: expected error containing %q, got %v", g.Policy, g.ExpectedErrors[i], e)
+			}
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/registry"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
 	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/vault"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/util/pkg/vfs"
@@ -75,6 +76,14 @@ func (c *VFSClientset) InstanceGroupsFor(cluster *kops.Cluster) kopsinternalvers
 }
 
 func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
+	if vault.IsVaultPath(cluster.Spec.SecretStore) {
+		client, p, err := vault.NewStoreClient(cluster, cluster.Spec.SecretStore)
+		if err != nil {
+			return nil, err
+		}
+		return secrets.NewVaultSecretStore(cluster, client, p), nil
+	}
+
 	configBase, err := registry.ConfigBase(cluster)
 	if err != nil {
 		return nil, err
@@ -84,6 +93,14 @@ func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error
 }
 
 func (c *VFSClientset) KeyStore(cluster *kops.Cluster) (fi.CAStore, error) {
+	if vault.IsVaultPath(cluster.Spec.KeyStore) {
+		client, p, err := vault.NewStoreClient(cluster, cluster.Spec.KeyStore)
+		if err != nil {
+			return nil, err
+		}
+		return fi.NewVaultCAStore(cluster, client, p), nil
+	}
+
 	configBase, err := registry.ConfigBase(cluster)
 	if err != nil {
 		return nil, err
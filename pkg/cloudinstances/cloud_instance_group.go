@@ -75,6 +75,21 @@ func (c *CloudInstanceGroup) NewCloudInstanceGroupMember(instanceId string, newG
 	return nil
 }
 
+// MarkNodesRequiringReplacement moves the ready members whose node has been annotated by nodeup
+// as requiring replacement to NeedUpdate
+func (c *CloudInstanceGroup) MarkNodesRequiringReplacement() {
+	var ready []*CloudInstanceGroupMember
+	for _, cm := range c.Ready {
+		if cm.Node != nil && cm.Node.Annotations[api.NodeAnnotationRequiresReplacement] != "" {
+			klog.V(2).Infof("node %q requires replacement: %s", cm.Node.Name, cm.Node.Annotations[api.NodeAnnotationRequiresReplacement])
+			c.NeedUpdate = append(c.NeedUpdate, cm)
+		} else {
+			ready = append(ready, cm)
+		}
+	}
+	c.Ready = ready
+}
+
 // Status returns a human-readable Status indicating whether an update is needed
 func (c *CloudInstanceGroup) Status() string {
 	if len(c.NeedUpdate) == 0 {
@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package componentconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Marshal renders a component config file as yaml, merging the overrides into the generated config.
+// The apiVersion and kind of the overrides are ignored, the generated config determines them.
+func Marshal(config interface{}, overrides *runtime.RawExtension) ([]byte, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling config: %v", err)
+	}
+
+	if overrides != nil && len(overrides.Raw) != 0 {
+		patch := make(map[string]interface{})
+		if err := json.Unmarshal(overrides.Raw, &patch); err != nil {
+			return nil, fmt.Errorf("error parsing configOverrides: %v", err)
+		}
+		delete(patch, "apiVersion")
+		delete(patch, "kind")
+
+		patchData, err := json.Marshal(patch)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling configOverrides: %v", err)
+		}
+		data, err = jsonpatch.MergePatch(data, patchData)
+		if err != nil {
+			return nil, fmt.Errorf("error merging configOverrides: %v", err)
+		}
+	}
+
+	manifest, err := yaml.JSONToYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("error converting config to yaml: %v", err)
+	}
+	return manifest, nil
+}
+
+// ValidateOverrides checks that the overrides only hold fields of the config type of into, with values of the right type.
+// If set, the apiVersion must be one of apiVersions and the kind must match kind.
+func ValidateOverrides(overrides *runtime.RawExtension, fldPath *field.Path, apiVersions []string, kind string, into interface{}) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if overrides == nil || len(overrides.Raw) == 0 {
+		return allErrs
+	}
+
+	obj := make(map[string]interface{})
+	if err := json.Unmarshal(overrides.Raw, &obj); err != nil {
+		return append(allErrs, field.Invalid(fldPath, string(overrides.Raw), fmt.Sprintf("must be an object: %v", err)))
+	}
+	if v, found := obj["apiVersion"]; found && !contains(apiVersions, v) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("apiVersion"), v, apiVersions))
+	}
+	if v, found := obj["kind"]; found && v != kind {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("kind"), v, []string{kind}))
+	}
+
+	if err := DecodeStrict(overrides.Raw, into); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, string(overrides.Raw), fmt.Sprintf("not a valid %s: %v", kind, err)))
+	}
+	return allErrs
+}
+
+// DecodeStrict decodes json into out, failing on fields that out does not have
+func DecodeStrict(data []byte, out interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(out)
+}
+
+func contains(values []string, v interface{}) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package componentconfig
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMarshal(t *testing.T) {
+	config := map[string]interface{}{
+		"apiVersion": "example.config.k8s.io/v1",
+		"kind":       "ExampleConfiguration",
+		"port":       10255,
+		"nested": map[string]interface{}{
+			"enabled": false,
+			"mode":    "AlwaysAllow",
+		},
+	}
+
+	grid := []struct {
+		overrides string
+		expected  string
+	}{
+		{
+			expected: "apiVersion: example.config.k8s.io/v1\nkind: ExampleConfiguration\nnested:\n  enabled: false\n  mode: AlwaysAllow\nport: 10255\n",
+		},
+		{
+			overrides: `{"apiVersion":"example.config.k8s.io/v2","kind":"Other","nested":{"enabled":true},"port":null,"extra":"value"}`,
+			expected:  "apiVersion: example.config.k8s.io/v1\nextra: value\nkind: ExampleConfiguration\nnested:\n  enabled: true\n  mode: AlwaysAllow\n",
+		},
+	}
+	for _, g := range grid {
+		var overrides *runtime.RawExtension
+		if g.overrides != "" {
+			overrides = &runtime.RawExtension{Raw: []byte(g.overrides)}
+		}
+		actual, err := Marshal(config, overrides)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", g.overrides, err)
+			continue
+		}
+		if string(actual) != g.expected {
+			t.Errorf("unexpected config for %q\nexpected:\n%s\ngot:\n%s", g.overrides, g.expected, actual)
+		}
+	}
+}
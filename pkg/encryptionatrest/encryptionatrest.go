@@ -0,0 +1,301 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionatrest
+
+import (
+	crypto_rand "crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/ghodss/yaml"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// SecretName is the name of the secret holding the encryption keys
+	SecretName = "encryptionatrest"
+
+	ProviderAESCBC    = "aescbc"
+	ProviderSecretbox = "secretbox"
+	ProviderKMS       = "kms"
+
+	// KMSPluginName is the name of the KMS plugin in the encryption provider config
+	KMSPluginName = "aws-encryption-provider"
+	// KMSPluginSocketDir is the directory holding the socket the KMS plugin listens on
+	KMSPluginSocketDir = "/var/run/kmsplugin"
+	// KMSPluginSocket is the socket the KMS plugin listens on
+	KMSPluginSocket = KMSPluginSocketDir + "/socket.sock"
+
+	keyNamePrefix = "key"
+	keyLength     = 32
+)
+
+// The phases of a key rotation, each naming the last step completed, so an interrupted rotation can be resumed
+const (
+	// RotationKeyAdded is the phase once a new key was added, which the masters may not decrypt with yet
+	RotationKeyAdded = "KeyAdded"
+	// RotationKeyPromoted is the phase once the new key was made the primary key, which the masters may not encrypt with yet
+	RotationKeyPromoted = "KeyPromoted"
+	// RotationMastersUpdated is the phase once every master encrypts with the new key
+	RotationMastersUpdated = "MastersUpdated"
+	// RotationOldKeysDropped is the phase once the old keys were dropped, which the masters may still hold
+	RotationOldKeysDropped = "OldKeysDropped"
+)
+
+// Providers are the supported encryption providers
+var Providers = []string{ProviderAESCBC, ProviderSecretbox, ProviderKMS}
+
+// KeySet is the list of encryption keys stored in the secret store.
+// The first key encrypts new writes; all keys are used to decrypt.
+type KeySet struct {
+	Keys []Key `json:"keys"`
+	// Rotation is the phase of the key rotation in progress, if any
+	Rotation string `json:"rotation,omitempty"`
+}
+
+// Key is a named base64 encoded encryption key
+type Key struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+// NewKeySet returns a key set holding a single new key
+func NewKeySet() (*KeySet, error) {
+	k := &KeySet{}
+	if _, err := k.AddKey(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// ParseKeySet parses the contents of the encryption key secret
+func ParseKeySet(data []byte) (*KeySet, error) {
+	k := &KeySet{}
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, fmt.Errorf("error parsing encryption keys: %v", err)
+	}
+	if len(k.Keys) == 0 {
+		return nil, fmt.Errorf("encryption key secret %q holds no keys", SecretName)
+	}
+	return k, nil
+}
+
+// Encode returns the contents of the encryption key secret
+func (k *KeySet) Encode() ([]byte, error) {
+	data, err := json.Marshal(k)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding encryption keys: %v", err)
+	}
+	return data, nil
+}
+
+// Primary returns the key used to encrypt new writes
+func (k *KeySet) Primary() *Key {
+	if len(k.Keys) == 0 {
+		return nil
+	}
+	return &k.Keys[0]
+}
+
+// Newest returns the most recently added key
+func (k *KeySet) Newest() *Key {
+	var newest *Key
+	for i := range k.Keys {
+		if newest == nil || keySequence(k.Keys[i].Name) > keySequence(newest.Name) {
+			newest = &k.Keys[i]
+		}
+	}
+	return newest
+}
+
+// AddKey generates a new key and appends it to the key set, so it is only used for decryption until it is promoted
+func (k *KeySet) AddKey() (*Key, error) {
+	data := make([]byte, keyLength)
+	if _, err := crypto_rand.Read(data); err != nil {
+		return nil, fmt.Errorf("error reading crypto_rand: %v", err)
+	}
+
+	sequence := 0
+	for _, key := range k.Keys {
+		if s := keySequence(key.Name); s > sequence {
+			sequence = s
+		}
+	}
+
+	k.Keys = append(k.Keys, Key{
+		Name:   keyNamePrefix + strconv.Itoa(sequence+1),
+		Secret: base64.StdEncoding.EncodeToString(data),
+	})
+	return &k.Keys[len(k.Keys)-1], nil
+}
+
+// Promote makes the named key the primary key
+func (k *KeySet) Promote(name string) error {
+	for i, key := range k.Keys {
+		if key.Name == name {
+			k.Keys = append([]Key{key}, append(k.Keys[:i:i], k.Keys[i+1:]...)...)
+			return nil
+		}
+	}
+	return fmt.Errorf("encryption key %q not found", name)
+}
+
+// Retain drops all keys but the named one
+func (k *KeySet) Retain(name string) error {
+	for _, key := range k.Keys {
+		if key.Name == name {
+			k.Keys = []Key{key}
+			return nil
+		}
+	}
+	return fmt.Errorf("encryption key %q not found", name)
+}
+
+// RotationPhase returns the phase of the key rotation in progress, or "" if there is none.
+// Key sets written before the phase was recorded are assumed to be at the earliest phase their keys allow.
+func (k *KeySet) RotationPhase() string {
+	if k.Rotation != "" {
+		return k.Rotation
+	}
+	if len(k.Keys) > 1 {
+		if k.Primary().Name != k.Newest().Name {
+			return RotationKeyAdded
+		}
+		return RotationKeyPromoted
+	}
+	return ""
+}
+
+// keySequence returns the number in the name of a key, or 0 if the name wasn't generated by kops
+func keySequence(name string) int {
+	s, err := strconv.Atoi(strings.TrimPrefix(name, keyNamePrefix))
+	if err != nil {
+		return 0
+	}
+	return s
+}
+
+// LoadKeySet reads the encryption keys from the secret store, returning nil if they don't exist yet
+func LoadKeySet(secretStore fi.SecretStore) (*KeySet, error) {
+	secret, err := secretStore.FindSecret(SecretName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret %q: %v", SecretName, err)
+	}
+	if secret == nil {
+		return nil, nil
+	}
+	return ParseKeySet(secret.Data)
+}
+
+// SaveKeySet writes the encryption keys to the secret store, replacing the existing keys
+func SaveKeySet(secretStore fi.SecretStore, k *KeySet) error {
+	data, err := k.Encode()
+	if err != nil {
+		return err
+	}
+	if _, err := secretStore.ReplaceSecret(SecretName, &fi.Secret{Data: data}); err != nil {
+		return fmt.Errorf("error writing secret %q: %v", SecretName, err)
+	}
+	return nil
+}
+
+type encryptionConfig struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Resources  []resourceConfig `json:"resources"`
+}
+
+type resourceConfig struct {
+	Resources []string         `json:"resources"`
+	Providers []providerConfig `json:"providers"`
+}
+
+type providerConfig struct {
+	AESCBC    *keysConfig `json:"aescbc,omitempty"`
+	Secretbox *keysConfig `json:"secretbox,omitempty"`
+	KMS       *kmsConfig  `json:"kms,omitempty"`
+	Identity  *struct{}   `json:"identity,omitempty"`
+}
+
+type keysConfig struct {
+	Keys []Key `json:"keys"`
+}
+
+type kmsConfig struct {
+	Name      string `json:"name"`
+	Endpoint  string `json:"endpoint"`
+	CacheSize int32  `json:"cachesize,omitempty"`
+	Timeout   string `json:"timeout,omitempty"`
+}
+
+// BuildEncryptionConfig returns the encryption provider config of the kube-apiserver.
+// The identity provider is listed last, so resources written before encryption was enabled can still be read.
+func BuildEncryptionConfig(spec *kops.EncryptionAtRestSpec, keys *KeySet, kubernetesVersion semver.Version) ([]byte, error) {
+	var provider providerConfig
+	switch spec.Provider {
+	case ProviderAESCBC, ProviderSecretbox:
+		if keys == nil || len(keys.Keys) == 0 {
+			return nil, fmt.Errorf("no encryption keys found in secret %q", SecretName)
+		}
+		if spec.Provider == ProviderAESCBC {
+			provider.AESCBC = &keysConfig{Keys: keys.Keys}
+		} else {
+			provider.Secretbox = &keysConfig{Keys: keys.Keys}
+		}
+	case ProviderKMS:
+		if spec.KMS == nil {
+			return nil, fmt.Errorf("encryptionAtRest provider %q requires the kms settings", ProviderKMS)
+		}
+		provider.KMS = &kmsConfig{
+			Name:     KMSPluginName,
+			Endpoint: "unix://" + KMSPluginSocket,
+			Timeout:  "3s",
+		}
+		if spec.KMS.CacheSize != nil {
+			provider.KMS.CacheSize = *spec.KMS.CacheSize
+		}
+	default:
+		return nil, fmt.Errorf("unknown encryptionAtRest provider %q", spec.Provider)
+	}
+
+	config := encryptionConfig{
+		APIVersion: "v1",
+		Kind:       "EncryptionConfig",
+		Resources: []resourceConfig{
+			{
+				Resources: spec.Resources,
+				Providers: []providerConfig{provider, {Identity: &struct{}{}}},
+			},
+		},
+	}
+	if util.IsKubernetesGTE("1.13", kubernetesVersion) {
+		config.APIVersion = "apiserver.config.k8s.io/v1"
+		config.Kind = "EncryptionConfiguration"
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling encryption provider config: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryptionatrest
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func keyNames(k *KeySet) string {
+	var names []string
+	for _, key := range k.Keys {
+		names = append(names, key.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestKeySetRotation(t *testing.T) {
+	keys, err := NewKeySet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret, err := base64.StdEncoding.DecodeString(keys.Primary().Secret)
+	if err != nil {
+		t.Fatalf("key is not base64 encoded: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("expected a 32 byte key, got %d bytes", len(secret))
+	}
+
+	added, err := keys.AddKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added.Name != "key2" || keyNames(keys) != "key1,key2" {
+		t.Errorf("unexpected keys after adding %q: %s", added.Name, keyNames(keys))
+	}
+	if keys.Newest().Name != "key2" {
+		t.Errorf("expected key2 to be the newest key, got %q", keys.Newest().Name)
+	}
+
+	if err := keys.Promote("key2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keyNames(keys) != "key2,key1" {
+		t.Errorf("unexpected keys after promoting: %s", keyNames(keys))
+	}
+	if keys.Newest().Name != "key2" {
+		t.Errorf("expected key2 to be the newest key, got %q", keys.Newest().Name)
+	}
+
+	if err := keys.Retain("key2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keyNames(keys) != "key2" {
+		t.Errorf("unexpected keys after dropping the old key: %s", keyNames(keys))
+	}
+
+	if err := keys.Promote("key9"); err == nil {
+		t.Errorf("expected an error promoting an unknown key")
+	}
+}
+
+func TestLoadSaveKeySet(t *testing.T) {
+	store := &fakeSecretStore{secrets: make(map[string]*fi.Secret)}
+
+	keys, err := LoadKeySet(store)
+	if err != nil || keys != nil {
+		t.Fatalf("expected no keys, got %v, %v", keys, err)
+	}
+
+	keys, err = NewKeySet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := SaveKeySet(store, keys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := LoadKeySet(store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Primary().Secret != keys.Primary().Secret {
+		t.Errorf("loaded keys don't match saved keys")
+	}
+}
+
+func TestBuildEncryptionConfig(t *testing.T) {
+	keys := &KeySet{Keys: []Key{{Name: "key2", Secret: "c2Vjb25k"}, {Name: "key1", Secret: "Zmlyc3Q="}}}
+
+	grid := []struct {
+		spec     kops.EncryptionAtRestSpec
+		version  string
+		expected string
+	}{
+		{
+			spec:    kops.EncryptionAtRestSpec{Provider: ProviderAESCBC, Resources: []string{"secrets"}},
+			version: "1.14.0",
+			expected: `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- providers:
+  - aescbc:
+      keys:
+      - name: key2
+        secret: c2Vjb25k
+      - name: key1
+        secret: Zmlyc3Q=
+  - identity: {}
+  resources:
+  - secrets
+`,
+		},
+		{
+			spec:    kops.EncryptionAtRestSpec{Provider: ProviderSecretbox, Resources: []string{"secrets", "configmaps"}},
+			version: "1.12.0",
+			expected: `apiVersion: v1
+kind: EncryptionConfig
+resources:
+- providers:
+  - secretbox:
+      keys:
+      - name: key2
+        secret: c2Vjb25k
+      - name: key1
+        secret: Zmlyc3Q=
+  - identity: {}
+  resources:
+  - secrets
+  - configmaps
+`,
+		},
+		{
+			spec: kops.EncryptionAtRestSpec{
+				Provider:  ProviderKMS,
+				Resources: []string{"secrets"},
+				KMS:       &kops.KMSEncryptionAtRestSpec{KeyID: "arn:aws:kms:us-east-1:123456789012:key/abcd", CacheSize: fi.Int32(1000)},
+			},
+			version: "1.14.0",
+			expected: `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- providers:
+  - kms:
+      cachesize: 1000
+      endpoint: unix:///var/run/kmsplugin/socket.sock
+      name: aws-encryption-provider
+      timeout: 3s
+  - identity: {}
+  resources:
+  - secrets
+`,
+		},
+	}
+
+	for _, g := range grid {
+		data, err := BuildEncryptionConfig(&g.spec, keys, semver.MustParse(g.version))
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", g.spec, err)
+			continue
+		}
+		if string(data) != g.expected {
+			t.Errorf("unexpected config for %v:\n%s\nexpected:\n%s", g.spec, data, g.expected)
+		}
+	}
+
+	if _, err := BuildEncryptionConfig(&kops.EncryptionAtRestSpec{Provider: ProviderAESCBC}, nil, semver.MustParse("1.14.0")); err == nil {
+		t.Errorf("expected an error building an aescbc config without keys")
+	}
+}
+
+type fakeSecretStore struct {
+	fi.SecretStore
+	secrets map[string]*fi.Secret
+}
+
+func (s *fakeSecretStore) FindSecret(id string) (*fi.Secret, error) {
+	return s.secrets[id], nil
+}
+
+func (s *fakeSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret, error) {
+	s.secrets[id] = secret
+	return secret, nil
+}
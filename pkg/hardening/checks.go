@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hardening
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Check is a verification of the hardening state of a running instance
+type Check struct {
+	// Name identifies the setting being checked
+	Name string
+	// Command is the shell command, run as root on the instance, whose output is evaluated
+	Command string
+	// Evaluate reports whether the output of the command shows the setting is applied, and describes what was found
+	Evaluate func(output string) (bool, string)
+}
+
+var auditKeyRegexp = regexp.MustCompile(`-k\s+(\S+)`)
+
+// Checks returns the checks verifying that the settings are applied
+func (s *Settings) Checks() []*Check {
+	var checks []*Check
+
+	for _, k := range sortedMapKeys(s.Sysctls) {
+		checks = append(checks, &Check{
+			Name:     "sysctl " + k,
+			Command:  "sysctl -n " + shellQuote(k),
+			Evaluate: expectValue(s.Sysctls[k], false),
+		})
+	}
+
+	for _, m := range s.BlacklistedModules {
+		// lsmod reports modules with underscores
+		loaded := strings.Replace(m, "-", "_", -1)
+		checks = append(checks, &Check{
+			Name: "module " + m,
+			Command: fmt.Sprintf("modprobe -n -v %s 2>&1 | grep -q '^install /bin/true' || echo loadable; lsmod | grep -q '^%s ' && echo loaded; true",
+				shellQuote(m), loaded),
+			Evaluate: func(output string) (bool, string) {
+				output = strings.Join(strings.Fields(output), ", ")
+				if output == "" {
+					return true, "blacklisted"
+				}
+				return false, output
+			},
+		})
+	}
+
+	if len(s.AuditRules) != 0 {
+		checks = append(checks, &Check{
+			Name:     "service auditd",
+			Command:  "systemctl is-active auditd.service; true",
+			Evaluate: expectValue("active", false),
+		})
+
+		var keys []string
+		for _, rule := range s.AuditRules {
+			match := auditKeyRegexp.FindStringSubmatch(rule)
+			if match != nil && !contains(keys, match[1]) {
+				keys = append(keys, match[1])
+			}
+		}
+		for _, key := range keys {
+			checks = append(checks, &Check{
+				Name:    "audit rules " + key,
+				Command: fmt.Sprintf("auditctl -l | grep -cE -- '(-k |key=)%s( |$)'; true", key),
+				Evaluate: func(output string) (bool, string) {
+					output = strings.TrimSpace(output)
+					if output == "" || output == "0" {
+						return false, "not loaded"
+					}
+					return true, output + " loaded"
+				},
+			})
+		}
+	}
+
+	for _, k := range sortedMapKeys(s.SSHD) {
+		checks = append(checks, &Check{
+			Name:     "sshd " + k,
+			Command:  fmt.Sprintf("/usr/sbin/sshd -T | awk 'tolower($1) == \"%s\" { $1 = \"\"; print substr($0, 2) }'", strings.ToLower(k)),
+			Evaluate: expectValue(s.SSHD[k], true),
+		})
+	}
+
+	for _, u := range s.DisabledServices {
+		checks = append(checks, &Check{
+			Name:     "service " + u,
+			Command:  fmt.Sprintf("if systemctl is-enabled --quiet %s || systemctl is-active --quiet %s; then echo enabled; else echo disabled; fi", shellQuote(u), shellQuote(u)),
+			Evaluate: expectValue("disabled", false),
+		})
+	}
+
+	if len(s.FileModes) != 0 {
+		checks = append(checks, &Check{
+			Name: "file modes",
+			// Prints the mode, the type and the path of each file recorded by nodeup
+			Command:  fmt.Sprintf("while read -r f; do stat -c '%%a %%F %%n' \"$f\"; done < %s", FileListPath),
+			Evaluate: s.evaluateFileModes,
+		})
+	}
+
+	return checks
+}
+
+// evaluateFileModes parses the stat output of the files recorded by nodeup, and lists those that are too permissive
+func (s *Settings) evaluateFileModes(output string) (bool, string) {
+	var failures []string
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		count++
+		mode, err := ParseMode(fields[0])
+		if err != nil {
+			failures = append(failures, line)
+			continue
+		}
+		p := fields[len(fields)-1]
+		executable := strings.Contains(line, "directory") || mode&0111 != 0
+		limit, found := s.FileModeLimit(p, executable)
+		if found && mode&^limit != 0 {
+			failures = append(failures, fmt.Sprintf("%s is %04o", p, mode))
+		}
+	}
+	if count == 0 {
+		return false, "no files recorded in " + FileListPath
+	}
+	if len(failures) != 0 {
+		return false, strings.Join(failures, ", ")
+	}
+	return true, fmt.Sprintf("%d files", count)
+}
+
+// expectValue compares the output with the expected value, ignoring differences in whitespace
+func expectValue(expected string, ignoreCase bool) func(string) (bool, string) {
+	return func(output string) (bool, string) {
+		actual := strings.Join(strings.Fields(output), " ")
+		if actual == "" {
+			return false, "not set"
+		}
+		want := strings.Join(strings.Fields(expected), " ")
+		if actual == want || (ignoreCase && strings.EqualFold(actual, want)) {
+			return true, actual
+		}
+		return false, fmt.Sprintf("%s, expected %s", actual, want)
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func sortedMapKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hardening
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// FileListPath is where nodeup records the files it has written below the paths of Settings.FileModes
+const FileListPath = "/var/lib/kops-hardening/files"
+
+// Settings is the effective hardening of an instance, once the profile and the overrides are resolved
+type Settings struct {
+	// Sysctls are the kernel parameters to set
+	Sysctls map[string]string
+	// BlacklistedModules are the kernel modules prevented from loading, sorted
+	BlacklistedModules []string
+	// AuditRules are the auditd rules to load, in order
+	AuditRules []string
+	// SSHD are the sshd_config settings
+	SSHD map[string]string
+	// DisabledServices are the systemd units to stop and disable, sorted
+	DisabledServices []string
+	// FileModes are the most permissive modes of the files below a path
+	FileModes map[string]os.FileMode
+}
+
+// Build resolves the hardening of an instance from the cluster and instance group specs; later specs override earlier ones.
+// It returns nil if no spec is set.
+func Build(specs ...*kops.NodeHardeningSpec) (*Settings, error) {
+	profileName := ""
+	found := false
+	for _, spec := range specs {
+		if spec == nil {
+			continue
+		}
+		found = true
+		if spec.Profile != "" {
+			profileName = spec.Profile
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	s := &Settings{
+		Sysctls:   make(map[string]string),
+		SSHD:      make(map[string]string),
+		FileModes: make(map[string]os.FileMode),
+	}
+	modules := make(map[string]bool)
+	services := make(map[string]bool)
+
+	if profileName != "" {
+		p := profiles[profileName]
+		if p == nil {
+			return nil, fmt.Errorf("unknown hardening profile %q", profileName)
+		}
+		for k, v := range p.sysctls {
+			s.Sysctls[k] = v
+		}
+		for _, m := range p.blacklistedModules {
+			modules[m] = true
+		}
+		s.AuditRules = append(s.AuditRules, p.auditRules...)
+		for k, v := range p.sshd {
+			s.SSHD[k] = v
+		}
+		for _, u := range p.disabledServices {
+			services[u] = true
+		}
+		for k, v := range p.fileModes {
+			s.FileModes[k] = v
+		}
+	}
+
+	for _, spec := range specs {
+		if spec == nil {
+			continue
+		}
+		for k, v := range spec.Sysctls {
+			if v == "" {
+				delete(s.Sysctls, k)
+			} else {
+				s.Sysctls[k] = v
+			}
+		}
+		for _, m := range spec.BlacklistedModules {
+			modules[m] = true
+		}
+		for _, m := range spec.AllowedModules {
+			delete(modules, m)
+		}
+		for _, rule := range spec.AuditRules {
+			if !contains(s.AuditRules, rule) {
+				s.AuditRules = append(s.AuditRules, rule)
+			}
+		}
+		for k, v := range spec.SSHD {
+			// sshd keywords are case-insensitive
+			for existing := range s.SSHD {
+				if strings.EqualFold(existing, k) {
+					delete(s.SSHD, existing)
+				}
+			}
+			if v != "" {
+				s.SSHD[k] = v
+			}
+		}
+		for _, u := range spec.DisabledServices {
+			services[u] = true
+		}
+		for _, u := range spec.EnabledServices {
+			delete(services, u)
+		}
+		for k, v := range spec.FileModes {
+			k = path.Clean(k)
+			if v == "" {
+				delete(s.FileModes, k)
+				continue
+			}
+			mode, err := ParseMode(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file mode for %q: %v", k, err)
+			}
+			s.FileModes[k] = mode
+		}
+	}
+
+	s.BlacklistedModules = sortedKeys(modules)
+	s.DisabledServices = sortedKeys(services)
+
+	return s, nil
+}
+
+// ParseMode parses an octal permission mode, such as "0600"
+func ParseMode(s string) (os.FileMode, error) {
+	v, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q as an octal mode", s)
+	}
+	if v&^uint64(os.ModePerm) != 0 {
+		return 0, fmt.Errorf("mode %q has bits other than permissions", s)
+	}
+	return os.FileMode(v), nil
+}
+
+// FileModeLimit returns the most permissive mode allowed for p, and false if p is not below any path of FileModes.
+// The deepest path wins.  Directories and executables may also have the execute bits matching the allowed read bits.
+func (s *Settings) FileModeLimit(p string, executable bool) (os.FileMode, bool) {
+	p = path.Clean(p)
+	best := ""
+	for prefix := range s.FileModes {
+		if p == prefix || strings.HasPrefix(p, strings.TrimSuffix(prefix, "/")+"/") {
+			if len(prefix) > len(best) {
+				best = prefix
+			}
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+
+	limit := s.FileModes[best]
+	if executable {
+		limit |= (limit & 0444) >> 2
+	}
+	return limit, true
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
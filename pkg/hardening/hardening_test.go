@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hardening
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestBuildNotConfigured(t *testing.T) {
+	settings, err := Build(nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings != nil {
+		t.Errorf("expected no settings, got %v", settings)
+	}
+}
+
+func TestBuildUnknownProfile(t *testing.T) {
+	_, err := Build(&kops.NodeHardeningSpec{Profile: "cis-level3"})
+	if err == nil {
+		t.Errorf("expected error for unknown profile")
+	}
+}
+
+func TestBuildOverrides(t *testing.T) {
+	cluster := &kops.NodeHardeningSpec{
+		Profile:          ProfileCISLevel1,
+		AllowedModules:   []string{"udf"},
+		DisabledServices: []string{"rpcbind.service"},
+		SSHD: map[string]string{
+			"permitrootlogin": "prohibit-password",
+		},
+	}
+	ig := &kops.NodeHardeningSpec{
+		Profile:            ProfileCISLevel2,
+		BlacklistedModules: []string{"udf"},
+		EnabledServices:    []string{"cups.service", "cups.socket"},
+		Sysctls: map[string]string{
+			"fs.suid_dumpable":      "",
+			"kernel.dmesg_restrict": "1",
+		},
+		FileModes: map[string]string{
+			"/var/lib/kubelet/":   "0640",
+			"/srv/kubernetes":     "",
+			"/etc/kubernetes/pki": "0400",
+		},
+	}
+
+	settings, err := Build(cluster, ig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedModules := []string{"cramfs", "dccp", "freevxfs", "hfs", "hfsplus", "jffs2", "rds", "tipc", "udf", "usb-storage"}
+	if !reflect.DeepEqual(settings.BlacklistedModules, expectedModules) {
+		t.Errorf("unexpected modules: %v", settings.BlacklistedModules)
+	}
+	if len(settings.AuditRules) == 0 {
+		t.Errorf("expected the audit rules of %s", ProfileCISLevel2)
+	}
+	if _, found := settings.Sysctls["fs.suid_dumpable"]; found {
+		t.Errorf("expected fs.suid_dumpable to be removed")
+	}
+	if settings.Sysctls["kernel.dmesg_restrict"] != "1" {
+		t.Errorf("expected kernel.dmesg_restrict to be set")
+	}
+	if _, found := settings.SSHD["PermitRootLogin"]; found || settings.SSHD["permitrootlogin"] != "prohibit-password" {
+		t.Errorf("expected PermitRootLogin to be overridden, got %v", settings.SSHD)
+	}
+	if settings.SSHD["PasswordAuthentication"] != "no" {
+		t.Errorf("expected PasswordAuthentication to be disabled, got %v", settings.SSHD)
+	}
+	for _, u := range settings.DisabledServices {
+		if u == "cups.service" || u == "cups.socket" {
+			t.Errorf("expected %s to be enabled", u)
+		}
+	}
+	if !contains(settings.DisabledServices, "rpcbind.service") {
+		t.Errorf("expected rpcbind.service to be disabled")
+	}
+	expectedModes := map[string]os.FileMode{
+		"/etc/kubernetes":     0600,
+		"/etc/kubernetes/pki": 0400,
+		"/var/lib/kube-proxy": 0600,
+		"/var/lib/kubelet":    0640,
+	}
+	if !reflect.DeepEqual(settings.FileModes, expectedModes) {
+		t.Errorf("unexpected file modes: %v", settings.FileModes)
+	}
+}
+
+func TestFileModeLimit(t *testing.T) {
+	settings := &Settings{
+		FileModes: map[string]os.FileMode{
+			"/etc/kubernetes":     0640,
+			"/etc/kubernetes/pki": 0400,
+		},
+	}
+
+	grid := []struct {
+		Path       string
+		Executable bool
+		Limit      os.FileMode
+		Found      bool
+	}{
+		{Path: "/etc/kubernetes", Executable: true, Limit: 0750, Found: true},
+		{Path: "/etc/kubernetes/manifests/etcd.manifest", Limit: 0640, Found: true},
+		{Path: "/etc/kubernetes/pki/ca.key", Limit: 0400, Found: true},
+		{Path: "/etc/kubernetes/pki", Executable: true, Limit: 0500, Found: true},
+		{Path: "/etc/kubernetes-other/file"},
+		{Path: "/usr/local/bin/kubelet", Executable: true},
+	}
+	for _, g := range grid {
+		limit, found := settings.FileModeLimit(g.Path, g.Executable)
+		if limit != g.Limit || found != g.Found {
+			t.Errorf("%s: expected %04o/%v, got %04o/%v", g.Path, g.Limit, g.Found, limit, found)
+		}
+	}
+}
+
+func TestEvaluateFileModes(t *testing.T) {
+	settings := &Settings{
+		FileModes: map[string]os.FileMode{
+			"/etc/kubernetes": 0600,
+		},
+	}
+
+	passed, detail := settings.evaluateFileModes("700 directory /etc/kubernetes/manifests\n600 regular file /etc/kubernetes/manifests/etcd.manifest\n")
+	if !passed {
+		t.Errorf("expected check to pass, got %s", detail)
+	}
+
+	passed, detail = settings.evaluateFileModes("755 directory /etc/kubernetes/manifests\n644 regular file /etc/kubernetes/manifests/etcd.manifest\n")
+	if passed {
+		t.Errorf("expected check to fail")
+	}
+	if detail != "/etc/kubernetes/manifests is 0755, /etc/kubernetes/manifests/etcd.manifest is 0644" {
+		t.Errorf("unexpected detail: %s", detail)
+	}
+
+	if passed, _ := settings.evaluateFileModes(""); passed {
+		t.Errorf("expected check to fail when no files are recorded")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hardening
+
+import (
+	"os"
+	"sort"
+)
+
+const (
+	// ProfileCISLevel1 follows the level 1 recommendations of the CIS distribution benchmarks,
+	// leaving out those that conflict with running Kubernetes nodes
+	ProfileCISLevel1 = "cis-level1"
+	// ProfileCISLevel2 extends ProfileCISLevel1 with the level 2 recommendations, including auditd rules
+	ProfileCISLevel2 = "cis-level2"
+)
+
+// profile holds the settings of a named hardening profile
+type profile struct {
+	sysctls            map[string]string
+	blacklistedModules []string
+	auditRules         []string
+	sshd               map[string]string
+	disabledServices   []string
+	fileModes          map[string]os.FileMode
+}
+
+var profiles = map[string]*profile{
+	ProfileCISLevel1: cisLevel1(),
+	ProfileCISLevel2: cisLevel2(),
+}
+
+// Profiles returns the names of the known profiles
+func Profiles() []string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func cisLevel1() *profile {
+	return &profile{
+		// net.ipv4.ip_forward and rp_filter are left alone: Kubernetes networking depends on them
+		sysctls: map[string]string{
+			"fs.suid_dumpable":                           "0",
+			"kernel.randomize_va_space":                  "2",
+			"net.ipv4.conf.all.accept_redirects":         "0",
+			"net.ipv4.conf.all.accept_source_route":      "0",
+			"net.ipv4.conf.all.log_martians":             "1",
+			"net.ipv4.conf.all.secure_redirects":         "0",
+			"net.ipv4.conf.all.send_redirects":           "0",
+			"net.ipv4.conf.default.accept_redirects":     "0",
+			"net.ipv4.conf.default.accept_source_route":  "0",
+			"net.ipv4.conf.default.log_martians":         "1",
+			"net.ipv4.conf.default.secure_redirects":     "0",
+			"net.ipv4.conf.default.send_redirects":       "0",
+			"net.ipv4.icmp_echo_ignore_broadcasts":       "1",
+			"net.ipv4.icmp_ignore_bogus_error_responses": "1",
+			"net.ipv4.tcp_syncookies":                    "1",
+			"net.ipv6.conf.all.accept_ra":                "0",
+			"net.ipv6.conf.all.accept_redirects":         "0",
+			"net.ipv6.conf.default.accept_ra":            "0",
+			"net.ipv6.conf.default.accept_redirects":     "0",
+		},
+		blacklistedModules: []string{
+			"cramfs",
+			"freevxfs",
+			"hfs",
+			"hfsplus",
+			"jffs2",
+			"udf",
+			"usb-storage",
+		},
+		sshd: map[string]string{
+			"ClientAliveCountMax":     "3",
+			"ClientAliveInterval":     "300",
+			"HostbasedAuthentication": "no",
+			"IgnoreRhosts":            "yes",
+			"LogLevel":                "INFO",
+			"LoginGraceTime":          "60",
+			"MaxAuthTries":            "4",
+			"MaxSessions":             "10",
+			"MaxStartups":             "10:30:60",
+			"PasswordAuthentication":  "no",
+			"PermitEmptyPasswords":    "no",
+			"PermitRootLogin":         "no",
+			"PermitUserEnvironment":   "no",
+			"X11Forwarding":           "no",
+		},
+		// rpcbind is left alone: NFS volumes depend on it
+		disabledServices: []string{
+			"avahi-daemon.service",
+			"avahi-daemon.socket",
+			"cups.service",
+			"cups.socket",
+			"dovecot.service",
+			"isc-dhcp-server.service",
+			"named.service",
+			"nfs-server.service",
+			"slapd.service",
+			"smbd.service",
+			"snmpd.service",
+			"squid.service",
+			"vsftpd.service",
+		},
+		fileModes: map[string]os.FileMode{
+			"/etc/kubernetes":     0600,
+			"/srv/kubernetes":     0600,
+			"/var/lib/kube-proxy": 0600,
+			"/var/lib/kubelet":    0600,
+		},
+	}
+}
+
+func cisLevel2() *profile {
+	p := cisLevel1()
+
+	p.blacklistedModules = append(p.blacklistedModules, "dccp", "rds", "tipc")
+
+	// The rules are not made immutable (-e 2), so that they can be updated without a reboot
+	p.auditRules = []string{
+		"-w /var/log/faillog -p wa -k logins",
+		"-w /var/log/lastlog -p wa -k logins",
+		"-w /etc/group -p wa -k identity",
+		"-w /etc/passwd -p wa -k identity",
+		"-w /etc/gshadow -p wa -k identity",
+		"-w /etc/shadow -p wa -k identity",
+		"-w /etc/security/opasswd -p wa -k identity",
+		"-w /etc/sudoers -p wa -k scope",
+		"-w /etc/sudoers.d/ -p wa -k scope",
+		"-a always,exit -F arch=b64 -S adjtimex -S settimeofday -k time-change",
+		"-a always,exit -F arch=b64 -S clock_settime -k time-change",
+		"-w /etc/localtime -p wa -k time-change",
+		"-a always,exit -F arch=b64 -S sethostname -S setdomainname -k system-locale",
+		"-w /etc/hosts -p wa -k system-locale",
+		"-w /sbin/insmod -p x -k modules",
+		"-w /sbin/rmmod -p x -k modules",
+		"-w /sbin/modprobe -p x -k modules",
+		"-a always,exit -F arch=b64 -S init_module -S delete_module -k modules",
+		"-w /etc/kubernetes -p wa -k kubernetes",
+		"-w /etc/docker -p wa -k docker",
+	}
+
+	p.sshd["AllowTcpForwarding"] = "no"
+	p.sshd["MaxAuthTries"] = "3"
+
+	return p
+}
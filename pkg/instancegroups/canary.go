@@ -0,0 +1,202 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/pkg/validation"
+)
+
+// CanaryCheck is a user-defined check which must keep passing while a canary instance soaks
+type CanaryCheck interface {
+	// Name is a human-readable description of the check
+	Name() string
+	// Check returns an error if the check did not pass
+	Check(cluster *api.Cluster) error
+}
+
+// CommandCanaryCheck is a CanaryCheck which runs a shell command; the check fails if the command exits non-zero
+type CommandCanaryCheck struct {
+	Command string
+}
+
+var _ CanaryCheck = &CommandCanaryCheck{}
+
+// Name returns the command being run
+func (c *CommandCanaryCheck) Name() string {
+	return c.Command
+}
+
+// Check runs the command, with KOPS_CLUSTER_NAME set to the name of the cluster
+func (c *CommandCanaryCheck) Check(cluster *api.Cluster) error {
+	cmd := exec.Command("/bin/sh", "-c", c.Command)
+	cmd.Env = append(os.Environ(), "KOPS_CLUSTER_NAME="+cluster.ObjectMeta.Name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("canary check %q failed: %v: %s", c.Command, err, string(output))
+	}
+	return nil
+}
+
+// canaryRollbackCloud is implemented by clouds which can point a cloud group back at the launch
+// specification its out-of-date instances were created from
+type canaryRollbackCloud interface {
+	RollbackCloudGroup(g *cloudinstances.CloudInstanceGroup) error
+}
+
+// rollCanary replaces a single instance and soaks it.  If the canary fails, the group is reverted to its
+// previous launch specification, the canary is replaced again, and an error is returned so the rolling-update stops.
+func (r *RollingUpdateInstanceGroup) rollCanary(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList, canary *cloudinstances.CloudInstanceGroupMember, sleepAfterTerminate time.Duration, validationTimeout time.Duration) error {
+	group := r.CloudGroup.InstanceGroup.ObjectMeta.Name
+
+	klog.Infof("Replacing canary instance %q in group %q.", canary.ID, group)
+	if err := r.drainTerminateAndWait(canary, rollingUpdateData, false, sleepAfterTerminate); err != nil {
+		return err
+	}
+
+	err := r.soakCanary(rollingUpdateData, cluster, instanceGroupList, validationTimeout)
+	if err == nil {
+		klog.Infof("Canary in group %q passed its soak period, continuing rolling-update.", group)
+		return nil
+	}
+
+	klog.Errorf("Canary in group %q failed, rolling back: %v", group, err)
+	if rollbackErr := r.rollbackCanary(rollingUpdateData, cluster, instanceGroupList, sleepAfterTerminate, validationTimeout); rollbackErr != nil {
+		return fmt.Errorf("canary in group %q failed (%v), and rollback failed: %v", group, err, rollbackErr)
+	}
+
+	klog.Warningf("Group %q was rolled back; it will be updated again by the next %q unless the instance group spec is reverted.", group, "kops update cluster")
+	return fmt.Errorf("canary in group %q failed and was rolled back: %v", group, err)
+}
+
+// soakCanary waits for the cluster to validate with the canary, then keeps validating the cluster and running
+// the canary checks until the soak period has elapsed
+func (r *RollingUpdateInstanceGroup) soakCanary(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList, validationTimeout time.Duration) error {
+	if r.shouldValidate(rollingUpdateData) {
+		klog.Info("Validating the cluster with the canary.")
+		if err := r.ValidateClusterWithDuration(rollingUpdateData, cluster, instanceGroupList, validationTimeout); err != nil {
+			return fmt.Errorf("error validating cluster after replacing canary: %v", err)
+		}
+	}
+
+	interval := rollingUpdateData.CanaryCheckInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	klog.Infof("Soaking canary for %v.", rollingUpdateData.CanarySoakPeriod)
+	deadline := time.Now().Add(rollingUpdateData.CanarySoakPeriod)
+	for {
+		if err := r.checkCanary(rollingUpdateData, cluster, instanceGroupList); err != nil {
+			return err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		if remaining > interval {
+			remaining = interval
+		}
+		time.Sleep(remaining)
+	}
+}
+
+// checkCanary validates the cluster once and runs each of the canary checks
+func (r *RollingUpdateInstanceGroup) checkCanary(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList) error {
+	if r.shouldValidate(rollingUpdateData) {
+		result, err := validation.ValidateCluster(cluster, instanceGroupList, rollingUpdateData.K8sClient)
+		if err != nil {
+			return fmt.Errorf("cluster did not validate during canary soak: %v", err)
+		}
+		if len(result.Failures) > 0 {
+			return fmt.Errorf("cluster did not pass validation during canary soak: %v", result.Failures[0].Message)
+		}
+	}
+
+	for _, check := range rollingUpdateData.CanaryChecks {
+		klog.V(2).Infof("Running canary check %q", check.Name())
+		if err := check.Check(cluster); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollbackCanary reverts the cloud group to its previous launch specification, and replaces any instances
+// which were launched from the new specification
+func (r *RollingUpdateInstanceGroup) rollbackCanary(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList, sleepAfterTerminate time.Duration, validationTimeout time.Duration) error {
+	cloud, ok := r.Cloud.(canaryRollbackCloud)
+	if !ok {
+		return fmt.Errorf("cloud provider %q does not support rolling back instance groups", r.Cloud.ProviderID())
+	}
+
+	if err := cloud.RollbackCloudGroup(r.CloudGroup); err != nil {
+		return err
+	}
+
+	var nodes []corev1.Node
+	if !rollingUpdateData.CloudOnly {
+		nodeList, err := rollingUpdateData.K8sClient.CoreV1().Nodes().List(metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing nodes in cluster: %v", err)
+		}
+		nodes = nodeList.Items
+	}
+
+	ig := r.CloudGroup.InstanceGroup
+	groups, err := r.Cloud.GetCloudGroups(cluster, []*api.InstanceGroup{ig}, false, nodes)
+	if err != nil {
+		return err
+	}
+	group := groups[ig.ObjectMeta.Name]
+	if group == nil {
+		return fmt.Errorf("unable to find cloud group for instance group %q", ig.ObjectMeta.Name)
+	}
+
+	// Now that the group has been reverted, only instances launched from the canary specification need an update
+	for _, u := range group.NeedUpdate {
+		klog.Infof("Replacing canary instance %q with the previous configuration.", u.ID)
+		if err := r.drainTerminateAndWait(u, rollingUpdateData, false, sleepAfterTerminate); err != nil {
+			return err
+		}
+	}
+
+	if r.shouldValidate(rollingUpdateData) {
+		if err := r.ValidateClusterWithDuration(rollingUpdateData, cluster, instanceGroupList, validationTimeout); err != nil {
+			return fmt.Errorf("error validating cluster after rolling back canary: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// shouldValidate returns true if we should validate the cluster during a rolling-update
+func (r *RollingUpdateInstanceGroup) shouldValidate(rollingUpdateData *RollingUpdateCluster) bool {
+	return !rollingUpdateData.CloudOnly && featureflag.DrainAndValidateRollingUpdate.Enabled()
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+type fakeCanaryCheck struct {
+	err   error
+	calls int
+}
+
+func (c *fakeCanaryCheck) Name() string {
+	return "fake"
+}
+
+func (c *fakeCanaryCheck) Check(cluster *kopsapi.Cluster) error {
+	c.calls++
+	return c.err
+}
+
+// setUpCanaryCloud creates a node group whose launch configuration has been updated to "nodes-new",
+// with three instances still running "nodes-old"
+func setUpCanaryCloud(t *testing.T) (*awsup.MockAWSCloud, *kopsapi.Cluster, []*kopsapi.InstanceGroup) {
+	mockcloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	mockAutoscaling := &mockautoscaling.MockAutoscaling{}
+	mockcloud.MockAutoscaling = mockAutoscaling
+
+	cluster := &kopsapi.Cluster{}
+	cluster.Name = "test.k8s.local"
+
+	_, err := mockAutoscaling.CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName:    aws.String("nodes.test.k8s.local"),
+		LaunchConfigurationName: aws.String("nodes-new"),
+		MinSize:                 aws.Int64(3),
+		MaxSize:                 aws.Int64(3),
+		Tags: []*autoscaling.Tag{
+			{
+				Key:          aws.String("KubernetesCluster"),
+				Value:        aws.String("test.k8s.local"),
+				ResourceId:   aws.String("nodes.test.k8s.local"),
+				ResourceType: aws.String("auto-scaling-group"),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating autoscaling group: %v", err)
+	}
+
+	g := mockAutoscaling.Groups["nodes.test.k8s.local"]
+	for _, id := range []string{"node-a", "node-b", "node-c"} {
+		g.Instances = append(g.Instances, &autoscaling.Instance{
+			InstanceId:              aws.String(id),
+			LaunchConfigurationName: aws.String("nodes-old"),
+		})
+	}
+
+	instanceGroups := []*kopsapi.InstanceGroup{
+		{
+			ObjectMeta: v1meta.ObjectMeta{
+				Name: "nodes",
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role: kopsapi.InstanceGroupRoleNode,
+			},
+		},
+	}
+
+	return mockcloud, cluster, instanceGroups
+}
+
+func TestRollingUpdateCanarySucceeds(t *testing.T) {
+	mockcloud, cluster, instanceGroups := setUpCanaryCloud(t)
+
+	check := &fakeCanaryCheck{}
+	c := &RollingUpdateCluster{
+		Cloud:               mockcloud,
+		NodeInterval:        1 * time.Millisecond,
+		CloudOnly:           true,
+		Canary:              true,
+		CanarySoakPeriod:    5 * time.Millisecond,
+		CanaryCheckInterval: 1 * time.Millisecond,
+		CanaryChecks:        []CanaryCheck{check},
+	}
+
+	groups, err := mockcloud.GetCloudGroups(cluster, instanceGroups, false, nil)
+	if err != nil {
+		t.Fatalf("error getting cloud groups: %v", err)
+	}
+	if len(groups["nodes"].NeedUpdate) != 3 {
+		t.Fatalf("expected 3 instances needing update, got %d", len(groups["nodes"].NeedUpdate))
+	}
+
+	if err := c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{}); err != nil {
+		t.Fatalf("unexpected error from rolling update: %v", err)
+	}
+
+	if check.calls < 2 {
+		t.Errorf("expected canary check to be run repeatedly during soak, was run %d times", check.calls)
+	}
+
+	g := mockcloud.MockAutoscaling.(*mockautoscaling.MockAutoscaling).Groups["nodes.test.k8s.local"]
+	if len(g.Instances) != 0 {
+		t.Errorf("expected all instances to be replaced, %d remain", len(g.Instances))
+	}
+	if lc := aws.StringValue(g.LaunchConfigurationName); lc != "nodes-new" {
+		t.Errorf("expected launch configuration to remain %q, was %q", "nodes-new", lc)
+	}
+}
+
+func TestRollingUpdateCanaryRollsBack(t *testing.T) {
+	mockcloud, cluster, instanceGroups := setUpCanaryCloud(t)
+
+	check := &fakeCanaryCheck{err: fmt.Errorf("smoke test failed")}
+	c := &RollingUpdateCluster{
+		Cloud:               mockcloud,
+		NodeInterval:        1 * time.Millisecond,
+		CloudOnly:           true,
+		Canary:              true,
+		CanarySoakPeriod:    5 * time.Millisecond,
+		CanaryCheckInterval: 1 * time.Millisecond,
+		CanaryChecks:        []CanaryCheck{check},
+	}
+
+	groups, err := mockcloud.GetCloudGroups(cluster, instanceGroups, false, nil)
+	if err != nil {
+		t.Fatalf("error getting cloud groups: %v", err)
+	}
+
+	if err := c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{}); err == nil {
+		t.Fatalf("expected error from rolling update with failing canary")
+	}
+
+	g := mockcloud.MockAutoscaling.(*mockautoscaling.MockAutoscaling).Groups["nodes.test.k8s.local"]
+	if lc := aws.StringValue(g.LaunchConfigurationName); lc != "nodes-old" {
+		t.Errorf("expected launch configuration to be rolled back to %q, was %q", "nodes-old", lc)
+	}
+	if len(g.Instances) != 2 {
+		t.Errorf("expected only the canary to be replaced, %d of 3 instances remain", len(g.Instances))
+	}
+}
+
+func TestRollingUpdateCanarySkippedWhenForcedAndUpToDate(t *testing.T) {
+	mockcloud, cluster, instanceGroups := setUpCanaryCloud(t)
+
+	g := mockcloud.MockAutoscaling.(*mockautoscaling.MockAutoscaling).Groups["nodes.test.k8s.local"]
+	for _, i := range g.Instances {
+		i.LaunchConfigurationName = aws.String("nodes-new")
+	}
+
+	// A canary which was up to date would prove nothing, so the failing check must not be run
+	check := &fakeCanaryCheck{err: fmt.Errorf("smoke test failed")}
+	c := &RollingUpdateCluster{
+		Cloud:               mockcloud,
+		NodeInterval:        1 * time.Millisecond,
+		CloudOnly:           true,
+		Force:               true,
+		Canary:              true,
+		CanarySoakPeriod:    5 * time.Millisecond,
+		CanaryCheckInterval: 1 * time.Millisecond,
+		CanaryChecks:        []CanaryCheck{check},
+	}
+
+	groups, err := mockcloud.GetCloudGroups(cluster, instanceGroups, false, nil)
+	if err != nil {
+		t.Fatalf("error getting cloud groups: %v", err)
+	}
+	if len(groups["nodes"].NeedUpdate) != 0 {
+		t.Fatalf("expected no instances needing update, got %d", len(groups["nodes"].NeedUpdate))
+	}
+
+	if err := c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{}); err != nil {
+		t.Fatalf("unexpected error from rolling update: %v", err)
+	}
+
+	if check.calls != 0 {
+		t.Errorf("expected no canary to be soaked, check was run %d times", check.calls)
+	}
+	if len(g.Instances) != 0 {
+		t.Errorf("expected all instances to be replaced, %d remain", len(g.Instances))
+	}
+	if lc := aws.StringValue(g.LaunchConfigurationName); lc != "nodes-new" {
+		t.Errorf("expected launch configuration to remain %q, was %q", "nodes-new", lc)
+	}
+}
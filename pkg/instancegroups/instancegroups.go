@@ -145,6 +145,27 @@ func (r *RollingUpdateInstanceGroup) RollingUpdate(rollingUpdateData *RollingUpd
 		}
 	}
 
+	if rollingUpdateData.Canary && !isBastion {
+		// Only an instance which actually needs updating proves anything about the new configuration;
+		// with --force we may otherwise pick an instance which is already up to date
+		if len(r.CloudGroup.NeedUpdate) == 0 {
+			klog.Infof("No instance in group %q needs updating, skipping the canary.", r.CloudGroup.InstanceGroup.ObjectMeta.Name)
+		} else {
+			canary := r.CloudGroup.NeedUpdate[0]
+			if err = r.rollCanary(rollingUpdateData, cluster, instanceGroupList, canary, sleepAfterTerminate, validationTimeout); err != nil {
+				return err
+			}
+
+			var remaining []*cloudinstances.CloudInstanceGroupMember
+			for _, u := range update {
+				if u != canary {
+					remaining = append(remaining, u)
+				}
+			}
+			update = remaining
+		}
+	}
+
 	for _, u := range update {
 		instanceId := u.ID
 
@@ -153,51 +174,10 @@ func (r *RollingUpdateInstanceGroup) RollingUpdate(rollingUpdateData *RollingUpd
 			nodeName = u.Node.Name
 		}
 
-		if isBastion {
-			// We don't want to validate for bastions - they aren't part of the cluster
-		} else if rollingUpdateData.CloudOnly {
-
-			klog.Warning("Not draining cluster nodes as 'cloudonly' flag is set.")
-
-		} else if featureflag.DrainAndValidateRollingUpdate.Enabled() {
-
-			if u.Node != nil {
-				klog.Infof("Draining the node: %q.", nodeName)
-
-				if err = r.DrainNode(u, rollingUpdateData); err != nil {
-					if rollingUpdateData.FailOnDrainError {
-						return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
-					} else {
-						klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
-					}
-				}
-			} else {
-				klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceId)
-			}
-		}
-
-		// We unregister the node before deleting it; if the replacement comes up with the same name it would otherwise still be cordoned
-		// (It often seems like GCE tries to re-use names)
-		if !isBastion && !rollingUpdateData.CloudOnly {
-			if u.Node == nil {
-				klog.Warningf("no kubernetes Node associated with %s, skipping node deletion", instanceId)
-			} else {
-				klog.Infof("deleting node %q from kubernetes", nodeName)
-				if err := r.deleteNode(u.Node, rollingUpdateData); err != nil {
-					return fmt.Errorf("error deleting node %q: %v", nodeName, err)
-				}
-			}
-		}
-
-		if err = r.DeleteInstance(u); err != nil {
-			klog.Errorf("error deleting instance %q, node %q: %v", instanceId, nodeName, err)
+		if err = r.drainTerminateAndWait(u, rollingUpdateData, isBastion, sleepAfterTerminate); err != nil {
 			return err
 		}
 
-		// Wait for the minimum interval
-		klog.Infof("waiting for %v after terminating instance", sleepAfterTerminate)
-		time.Sleep(sleepAfterTerminate)
-
 		if isBastion {
 			klog.Infof("Deleted a bastion instance, %s, and continuing with rolling-update.", instanceId)
 
@@ -234,6 +214,64 @@ func (r *RollingUpdateInstanceGroup) RollingUpdate(rollingUpdateData *RollingUpd
 	return nil
 }
 
+// drainTerminateAndWait drains and deregisters the node backing an instance, terminates the instance,
+// and then waits for sleepAfterTerminate.
+func (r *RollingUpdateInstanceGroup) drainTerminateAndWait(u *cloudinstances.CloudInstanceGroupMember, rollingUpdateData *RollingUpdateCluster, isBastion bool, sleepAfterTerminate time.Duration) error {
+	instanceId := u.ID
+
+	nodeName := ""
+	if u.Node != nil {
+		nodeName = u.Node.Name
+	}
+
+	if isBastion {
+		// We don't want to validate for bastions - they aren't part of the cluster
+	} else if rollingUpdateData.CloudOnly {
+
+		klog.Warning("Not draining cluster nodes as 'cloudonly' flag is set.")
+
+	} else if featureflag.DrainAndValidateRollingUpdate.Enabled() {
+
+		if u.Node != nil {
+			klog.Infof("Draining the node: %q.", nodeName)
+
+			if err := r.DrainNode(u, rollingUpdateData); err != nil {
+				if rollingUpdateData.FailOnDrainError {
+					return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
+				} else {
+					klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
+				}
+			}
+		} else {
+			klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceId)
+		}
+	}
+
+	// We unregister the node before deleting it; if the replacement comes up with the same name it would otherwise still be cordoned
+	// (It often seems like GCE tries to re-use names)
+	if !isBastion && !rollingUpdateData.CloudOnly {
+		if u.Node == nil {
+			klog.Warningf("no kubernetes Node associated with %s, skipping node deletion", instanceId)
+		} else {
+			klog.Infof("deleting node %q from kubernetes", nodeName)
+			if err := r.deleteNode(u.Node, rollingUpdateData); err != nil {
+				return fmt.Errorf("error deleting node %q: %v", nodeName, err)
+			}
+		}
+	}
+
+	if err := r.DeleteInstance(u); err != nil {
+		klog.Errorf("error deleting instance %q, node %q: %v", instanceId, nodeName, err)
+		return err
+	}
+
+	// Wait for the minimum interval
+	klog.Infof("waiting for %v after terminating instance", sleepAfterTerminate)
+	time.Sleep(sleepAfterTerminate)
+
+	return nil
+}
+
 // ValidateClusterWithDuration runs validation.ValidateCluster until either we get positive result or the timeout expires
 func (r *RollingUpdateInstanceGroup) ValidateClusterWithDuration(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList, duration time.Duration) error {
 	// TODO should we expose this to the UI?
@@ -56,6 +56,16 @@ type RollingUpdateCluster struct {
 
 	// ValidationTimeout is the maximum time to wait for the cluster to validate, once we start validation
 	ValidationTimeout time.Duration
+
+	// Canary replaces a single instance of each master and node group first, and soaks it before rolling the rest
+	// of the group.  If the canary fails, the group is rolled back to its previous launch configuration.
+	Canary bool
+	// CanarySoakPeriod is how long the cluster must keep validating, and the CanaryChecks keep passing, with the canary
+	CanarySoakPeriod time.Duration
+	// CanaryCheckInterval is how often the cluster is validated and the CanaryChecks are run during the soak period
+	CanaryCheckInterval time.Duration
+	// CanaryChecks are additional user-defined checks which must pass during the soak period
+	CanaryChecks []CanaryCheck
 }
 
 // RollingUpdate performs a rolling update on a K8s Cluster.
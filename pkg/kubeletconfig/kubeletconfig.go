@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/componentconfig"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// APIVersion is the apiVersion of the kubelet config file
+	APIVersion = "kubelet.config.k8s.io/v1beta1"
+	// Kind is the kind of the kubelet config file
+	Kind = "KubeletConfiguration"
+)
+
+// BuildConfig splits the kubelet spec into a KubeletConfiguration and the remaining spec,
+// which holds the settings that can only be passed as flags.
+func BuildConfig(spec *kops.KubeletConfigSpec) (*KubeletConfiguration, *kops.KubeletConfigSpec, error) {
+	flags := *spec
+	config := &KubeletConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+		},
+	}
+
+	// The config file defaults of authentication, authorization and the read-only port are stricter than those of the flags.
+	// We keep the flag defaults, so that moving a setting to the config file doesn't change it.
+	config.Authentication = &Authentication{
+		Anonymous: &AnonymousAuthentication{Enabled: fi.Bool(true)},
+		Webhook:   &WebhookAuthentication{Enabled: fi.Bool(false)},
+	}
+	config.Authorization = &Authorization{Mode: "AlwaysAllow"}
+	config.ReadOnlyPort = fi.Int32(10255)
+
+	if spec.AnonymousAuth != nil {
+		config.Authentication.Anonymous.Enabled = spec.AnonymousAuth
+		flags.AnonymousAuth = nil
+	}
+	if spec.AuthenticationTokenWebhook != nil {
+		config.Authentication.Webhook.Enabled = spec.AuthenticationTokenWebhook
+		flags.AuthenticationTokenWebhook = nil
+	}
+	if spec.AuthenticationTokenWebhookCacheTTL != nil {
+		config.Authentication.Webhook.CacheTTL = spec.AuthenticationTokenWebhookCacheTTL
+		flags.AuthenticationTokenWebhookCacheTTL = nil
+	}
+	if spec.ClientCAFile != "" {
+		config.Authentication.X509 = &X509Authentication{ClientCAFile: spec.ClientCAFile}
+		flags.ClientCAFile = ""
+	}
+	if spec.AuthorizationMode != "" {
+		config.Authorization.Mode = spec.AuthorizationMode
+		flags.AuthorizationMode = ""
+	}
+	if spec.ReadOnlyPort != nil {
+		config.ReadOnlyPort = spec.ReadOnlyPort
+		flags.ReadOnlyPort = nil
+	}
+
+	config.TLSCertFile, flags.TLSCertFile = spec.TLSCertFile, ""
+	config.TLSPrivateKeyFile, flags.TLSPrivateKeyFile = spec.TLSPrivateKeyFile, ""
+	config.TLSCipherSuites, flags.TLSCipherSuites = spec.TLSCipherSuites, nil
+	config.TLSMinVersion, flags.TLSMinVersion = spec.TLSMinVersion, ""
+	config.StaticPodPath, flags.PodManifestPath = spec.PodManifestPath, ""
+	config.EnableDebuggingHandlers, flags.EnableDebuggingHandlers = spec.EnableDebuggingHandlers, nil
+	config.NodeStatusUpdateFrequency, flags.NodeStatusUpdateFrequency = spec.NodeStatusUpdateFrequency, nil
+	config.ClusterDomain, flags.ClusterDomain = spec.ClusterDomain, ""
+	config.KubeletCgroups, flags.KubeletCgroups = spec.KubeletCgroups, ""
+	config.SystemCgroups, flags.SystemCgroups = spec.SystemCgroups, ""
+	config.CgroupRoot, flags.CgroupRoot = spec.CgroupRoot, ""
+	config.HairpinMode, flags.HairpinMode = spec.HairpinMode, ""
+	config.MaxPods, flags.MaxPods = spec.MaxPods, nil
+	config.PodCIDR, flags.PodCIDR = spec.PodCIDR, ""
+	config.SerializeImagePulls, flags.SerializeImagePulls = spec.SerializeImagePulls, nil
+	config.ImageGCHighThresholdPercent, flags.ImageGCHighThresholdPercent = spec.ImageGCHighThresholdPercent, nil
+	config.ImageGCLowThresholdPercent, flags.ImageGCLowThresholdPercent = spec.ImageGCLowThresholdPercent, nil
+	config.EvictionPressureTransitionPeriod, flags.EvictionPressureTransitionPeriod = spec.EvictionPressureTransitionPeriod, nil
+	config.KubeReserved, flags.KubeReserved = spec.KubeReserved, nil
+	config.KubeReservedCgroup, flags.KubeReservedCgroup = spec.KubeReservedCgroup, ""
+	config.SystemReserved, flags.SystemReserved = spec.SystemReserved, nil
+	config.SystemReservedCgroup, flags.SystemReservedCgroup = spec.SystemReservedCgroup, ""
+	config.RuntimeRequestTimeout, flags.RuntimeRequestTimeout = spec.RuntimeRequestTimeout, nil
+	config.VolumeStatsAggPeriod, flags.VolumeStatsAggPeriod = spec.VolumeStatsAggPeriod, nil
+	config.FailSwapOn, flags.FailSwapOn = spec.FailSwapOn, nil
+	config.AllowedUnsafeSysctls, flags.AllowedUnsafeSysctls = spec.AllowedUnsafeSysctls, nil
+	config.StreamingConnectionIdleTimeout, flags.StreamingConnectionIdleTimeout = spec.StreamingConnectionIdleTimeout, nil
+	config.CPUCFSQuota, flags.CPUCFSQuota = spec.CPUCFSQuota, nil
+	config.CPUCFSQuotaPeriod, flags.CPUCFSQuotaPeriod = spec.CPUCFSQuotaPeriod, nil
+	config.CPUManagerPolicy, flags.CpuManagerPolicy = spec.CpuManagerPolicy, ""
+	config.RegistryPullQPS, flags.RegistryPullQPS = spec.RegistryPullQPS, nil
+	config.RegistryBurst, flags.RegistryBurst = spec.RegistryBurst, nil
+
+	if spec.EvictionMaxPodGracePeriod != 0 {
+		config.EvictionMaxPodGracePeriod = fi.Int32(spec.EvictionMaxPodGracePeriod)
+		flags.EvictionMaxPodGracePeriod = 0
+	}
+	if spec.ClusterDNS != "" {
+		config.ClusterDNS = splitList(spec.ClusterDNS)
+		flags.ClusterDNS = ""
+	}
+	if spec.EnforceNodeAllocatable != "" {
+		config.EnforceNodeAllocatable = splitList(spec.EnforceNodeAllocatable)
+		flags.EnforceNodeAllocatable = ""
+	}
+
+	var err error
+	if spec.EvictionHard != nil {
+		// An empty value disables hard eviction, so it is written as an empty map rather than left to the kubelet defaults
+		evictionHard, err := parseMap(*spec.EvictionHard, "<")
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing evictionHard: %v", err)
+		}
+		config.EvictionHard = &evictionHard
+		flags.EvictionHard = nil
+	}
+	if spec.EvictionSoft != "" {
+		if config.EvictionSoft, err = parseMap(spec.EvictionSoft, "<"); err != nil {
+			return nil, nil, fmt.Errorf("error parsing evictionSoft: %v", err)
+		}
+		flags.EvictionSoft = ""
+	}
+	if spec.EvictionSoftGracePeriod != "" {
+		if config.EvictionSoftGracePeriod, err = parseMap(spec.EvictionSoftGracePeriod, "="); err != nil {
+			return nil, nil, fmt.Errorf("error parsing evictionSoftGracePeriod: %v", err)
+		}
+		flags.EvictionSoftGracePeriod = ""
+	}
+	if spec.EvictionMinimumReclaim != "" {
+		if config.EvictionMinimumReclaim, err = parseMap(spec.EvictionMinimumReclaim, "="); err != nil {
+			return nil, nil, fmt.Errorf("error parsing evictionMinimumReclaim: %v", err)
+		}
+		flags.EvictionMinimumReclaim = ""
+	}
+
+	if len(spec.FeatureGates) != 0 {
+		config.FeatureGates = make(map[string]bool)
+		for k, v := range spec.FeatureGates {
+			enabled, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, nil, fmt.Errorf("feature gate %q must be true or false, was %q", k, v)
+			}
+			config.FeatureGates[k] = enabled
+		}
+		flags.FeatureGates = nil
+	}
+
+	flags.ConfigOverrides = nil
+
+	return config, &flags, nil
+}
+
+// Marshal renders the config file, merging the overrides into the generated config
+func Marshal(config *KubeletConfiguration, overrides *runtime.RawExtension) ([]byte, error) {
+	return componentconfig.Marshal(config, overrides)
+}
+
+// ValidateOverrides checks that the overrides only hold fields of the KubeletConfiguration, with values of the right type
+func ValidateOverrides(overrides *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	return componentconfig.ValidateOverrides(overrides, fldPath, []string{APIVersion}, Kind, &KubeletConfiguration{})
+}
+
+// splitList splits a comma separated flag value
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseMap parses a comma separated flag value of key<sep>value pairs, such as memory.available<100Mi
+func parseMap(s string, sep string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, v := range splitList(s) {
+		tokens := strings.SplitN(v, sep, 2)
+		if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+			return nil, fmt.Errorf("expected key%svalue, got %q", sep, v)
+		}
+		m[tokens[0]] = tokens[1]
+	}
+	return m, nil
+}
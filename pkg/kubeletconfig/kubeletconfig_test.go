@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBuildConfig(t *testing.T) {
+	spec := &kops.KubeletConfigSpec{
+		AnonymousAuth:             fi.Bool(false),
+		ClientCAFile:              "/srv/kubernetes/ca.crt",
+		PodManifestPath:           "/etc/kubernetes/manifests",
+		ClusterDNS:                "100.64.0.10",
+		ClusterDomain:             "cluster.local",
+		CloudProvider:             "aws",
+		EvictionHard:              fi.String("memory.available<100Mi,nodefs.available<10%"),
+		EvictionSoftGracePeriod:   "memory.available=30s",
+		EnforceNodeAllocatable:    "pods,kube-reserved",
+		FeatureGates:              map[string]string{"ExperimentalCriticalPodAnnotation": "true"},
+		KubeReserved:              map[string]string{"cpu": "100m"},
+		MaxPods:                   fi.Int32(50),
+		NodeLabels:                map[string]string{"kubernetes.io/role": "node"},
+		NodeStatusUpdateFrequency: &metav1.Duration{Duration: 20 * time.Second},
+		NetworkPluginName:         "cni",
+		LogLevel:                  fi.Int32(2),
+		ConfigOverrides:           &runtime.RawExtension{Raw: []byte(`{"podPidsLimit":1024}`)},
+	}
+
+	config, remaining, err := BuildConfig(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flags, err := flagbuilder.BuildFlags(remaining)
+	if err != nil {
+		t.Fatalf("error building flags: %v", err)
+	}
+	expectedFlags := "--cloud-provider=aws --network-plugin=cni --node-labels=kubernetes.io/role=node --v=2"
+	if flags != expectedFlags {
+		t.Errorf("unexpected flags\nexpected: %s\ngot:      %s", expectedFlags, flags)
+	}
+
+	manifest, err := Marshal(config, spec.ConfigOverrides)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: kubelet.config.k8s.io/v1beta1
+authentication:
+  anonymous:
+    enabled: false
+  webhook:
+    enabled: false
+  x509:
+    clientCAFile: /srv/kubernetes/ca.crt
+authorization:
+  mode: AlwaysAllow
+clusterDNS:
+- 100.64.0.10
+clusterDomain: cluster.local
+enforceNodeAllocatable:
+- pods
+- kube-reserved
+evictionHard:
+  memory.available: 100Mi
+  nodefs.available: 10%
+evictionSoftGracePeriod:
+  memory.available: 30s
+featureGates:
+  ExperimentalCriticalPodAnnotation: true
+kind: KubeletConfiguration
+kubeReserved:
+  cpu: 100m
+maxPods: 50
+nodeStatusUpdateFrequency: 20s
+podPidsLimit: 1024
+readOnlyPort: 10255
+staticPodPath: /etc/kubernetes/manifests
+`
+	if string(manifest) != expected {
+		t.Errorf("unexpected config\nexpected:\n%s\ngot:\n%s", expected, manifest)
+	}
+}
+
+func TestBuildConfigEmptyEvictionHard(t *testing.T) {
+	spec := &kops.KubeletConfigSpec{
+		EvictionHard: fi.String(""),
+	}
+
+	config, remaining, err := BuildConfig(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remaining.EvictionHard != nil {
+		t.Errorf("evictionHard was left as a flag: %q", *remaining.EvictionHard)
+	}
+
+	manifest, err := Marshal(config, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// An empty map disables hard eviction; omitting it would give the kubelet defaults
+	if !strings.Contains(string(manifest), "\nevictionHard: {}\n") {
+		t.Errorf("expected an empty evictionHard in the config, got:\n%s", manifest)
+	}
+}
+
+func TestBuildConfigErrors(t *testing.T) {
+	grid := []*kops.KubeletConfigSpec{
+		{FeatureGates: map[string]string{"Foo": "yes please"}},
+		{EvictionHard: fi.String("memory.available=100Mi")},
+		{EvictionMinimumReclaim: "imagefs.available"},
+	}
+	for _, spec := range grid {
+		if _, _, err := BuildConfig(spec); err == nil {
+			t.Errorf("expected an error building %+v", spec)
+		}
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	grid := []struct {
+		overrides string
+		expected  []string
+	}{
+		{overrides: `{"podPidsLimit":1024,"authentication":{"webhook":{"cacheTTL":"30s"}}}`},
+		{overrides: `{"apiVersion":"kubelet.config.k8s.io/v1beta1","kind":"KubeletConfiguration","containerLogMaxSize":"50Mi"}`},
+		{overrides: `{"podPidLimit":1024}`, expected: []string{"Invalid value::spec.kubelet.configOverrides"}},
+		{overrides: `{"maxPods":"many"}`, expected: []string{"Invalid value::spec.kubelet.configOverrides"}},
+		{overrides: `{"apiVersion":"kubelet.config.k8s.io/v1alpha1"}`, expected: []string{"Unsupported value::spec.kubelet.configOverrides.apiVersion"}},
+		{overrides: `[1]`, expected: []string{"Invalid value::spec.kubelet.configOverrides"}},
+	}
+	for _, g := range grid {
+		errs := ValidateOverrides(&runtime.RawExtension{Raw: []byte(g.overrides)}, field.NewPath("spec", "kubelet", "configOverrides"))
+		var actual []string
+		for _, err := range errs {
+			actual = append(actual, err.Type.String()+"::"+err.Field)
+		}
+		if strings.Join(actual, ",") != strings.Join(g.expected, ",") {
+			t.Errorf("unexpected errors for %s: %v, expected %v", g.overrides, errs, g.expected)
+		}
+	}
+}
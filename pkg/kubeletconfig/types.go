@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeletconfig
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubeletConfiguration mirrors kubelet.config.k8s.io/v1beta1 KubeletConfiguration as of kubernetes 1.15.
+// The upstream types live in k8s.io/kubelet, which is not vendored; the field names and types here must match them.
+// Scalars are pointers so that unset fields are omitted and the kubelet applies its own defaults.
+// EvictionHard is also a pointer, as an empty map disables hard eviction rather than giving the defaults.
+type KubeletConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	StaticPodPath                             string              `json:"staticPodPath,omitempty"`
+	SyncFrequency                             *metav1.Duration    `json:"syncFrequency,omitempty"`
+	FileCheckFrequency                        *metav1.Duration    `json:"fileCheckFrequency,omitempty"`
+	HTTPCheckFrequency                        *metav1.Duration    `json:"httpCheckFrequency,omitempty"`
+	StaticPodURL                              string              `json:"staticPodURL,omitempty"`
+	StaticPodURLHeader                        map[string][]string `json:"staticPodURLHeader,omitempty"`
+	Address                                   string              `json:"address,omitempty"`
+	Port                                      *int32              `json:"port,omitempty"`
+	ReadOnlyPort                              *int32              `json:"readOnlyPort,omitempty"`
+	TLSCertFile                               string              `json:"tlsCertFile,omitempty"`
+	TLSPrivateKeyFile                         string              `json:"tlsPrivateKeyFile,omitempty"`
+	TLSCipherSuites                           []string            `json:"tlsCipherSuites,omitempty"`
+	TLSMinVersion                             string              `json:"tlsMinVersion,omitempty"`
+	RotateCertificates                        *bool               `json:"rotateCertificates,omitempty"`
+	ServerTLSBootstrap                        *bool               `json:"serverTLSBootstrap,omitempty"`
+	Authentication                            *Authentication     `json:"authentication,omitempty"`
+	Authorization                             *Authorization      `json:"authorization,omitempty"`
+	RegistryPullQPS                           *int32              `json:"registryPullQPS,omitempty"`
+	RegistryBurst                             *int32              `json:"registryBurst,omitempty"`
+	EventRecordQPS                            *int32              `json:"eventRecordQPS,omitempty"`
+	EventBurst                                *int32              `json:"eventBurst,omitempty"`
+	EnableDebuggingHandlers                   *bool               `json:"enableDebuggingHandlers,omitempty"`
+	EnableContentionProfiling                 *bool               `json:"enableContentionProfiling,omitempty"`
+	HealthzPort                               *int32              `json:"healthzPort,omitempty"`
+	HealthzBindAddress                        string              `json:"healthzBindAddress,omitempty"`
+	OOMScoreAdj                               *int32              `json:"oomScoreAdj,omitempty"`
+	ClusterDomain                             string              `json:"clusterDomain,omitempty"`
+	ClusterDNS                                []string            `json:"clusterDNS,omitempty"`
+	StreamingConnectionIdleTimeout            *metav1.Duration    `json:"streamingConnectionIdleTimeout,omitempty"`
+	NodeStatusUpdateFrequency                 *metav1.Duration    `json:"nodeStatusUpdateFrequency,omitempty"`
+	NodeStatusReportFrequency                 *metav1.Duration    `json:"nodeStatusReportFrequency,omitempty"`
+	NodeLeaseDurationSeconds                  *int32              `json:"nodeLeaseDurationSeconds,omitempty"`
+	ImageMinimumGCAge                         *metav1.Duration    `json:"imageMinimumGCAge,omitempty"`
+	ImageGCHighThresholdPercent               *int32              `json:"imageGCHighThresholdPercent,omitempty"`
+	ImageGCLowThresholdPercent                *int32              `json:"imageGCLowThresholdPercent,omitempty"`
+	VolumeStatsAggPeriod                      *metav1.Duration    `json:"volumeStatsAggPeriod,omitempty"`
+	KubeletCgroups                            string              `json:"kubeletCgroups,omitempty"`
+	SystemCgroups                             string              `json:"systemCgroups,omitempty"`
+	CgroupRoot                                string              `json:"cgroupRoot,omitempty"`
+	CgroupsPerQOS                             *bool               `json:"cgroupsPerQOS,omitempty"`
+	CgroupDriver                              string              `json:"cgroupDriver,omitempty"`
+	CPUManagerPolicy                          string              `json:"cpuManagerPolicy,omitempty"`
+	CPUManagerReconcilePeriod                 *metav1.Duration    `json:"cpuManagerReconcilePeriod,omitempty"`
+	QOSReserved                               map[string]string   `json:"qosReserved,omitempty"`
+	RuntimeRequestTimeout                     *metav1.Duration    `json:"runtimeRequestTimeout,omitempty"`
+	HairpinMode                               string              `json:"hairpinMode,omitempty"`
+	MaxPods                                   *int32              `json:"maxPods,omitempty"`
+	PodCIDR                                   string              `json:"podCIDR,omitempty"`
+	PodPidsLimit                              *int64              `json:"podPidsLimit,omitempty"`
+	ResolverConfig                            string              `json:"resolvConf,omitempty"`
+	CPUCFSQuota                               *bool               `json:"cpuCFSQuota,omitempty"`
+	CPUCFSQuotaPeriod                         *metav1.Duration    `json:"cpuCFSQuotaPeriod,omitempty"`
+	MaxOpenFiles                              *int64              `json:"maxOpenFiles,omitempty"`
+	ContentType                               string              `json:"contentType,omitempty"`
+	KubeAPIQPS                                *int32              `json:"kubeAPIQPS,omitempty"`
+	KubeAPIBurst                              *int32              `json:"kubeAPIBurst,omitempty"`
+	SerializeImagePulls                       *bool               `json:"serializeImagePulls,omitempty"`
+	EvictionHard                              *map[string]string  `json:"evictionHard,omitempty"`
+	EvictionSoft                              map[string]string   `json:"evictionSoft,omitempty"`
+	EvictionSoftGracePeriod                   map[string]string   `json:"evictionSoftGracePeriod,omitempty"`
+	EvictionPressureTransitionPeriod          *metav1.Duration    `json:"evictionPressureTransitionPeriod,omitempty"`
+	EvictionMaxPodGracePeriod                 *int32              `json:"evictionMaxPodGracePeriod,omitempty"`
+	EvictionMinimumReclaim                    map[string]string   `json:"evictionMinimumReclaim,omitempty"`
+	PodsPerCore                               *int32              `json:"podsPerCore,omitempty"`
+	EnableControllerAttachDetach              *bool               `json:"enableControllerAttachDetach,omitempty"`
+	ProtectKernelDefaults                     *bool               `json:"protectKernelDefaults,omitempty"`
+	MakeIPTablesUtilChains                    *bool               `json:"makeIPTablesUtilChains,omitempty"`
+	IPTablesMasqueradeBit                     *int32              `json:"iptablesMasqueradeBit,omitempty"`
+	IPTablesDropBit                           *int32              `json:"iptablesDropBit,omitempty"`
+	FeatureGates                              map[string]bool     `json:"featureGates,omitempty"`
+	FailSwapOn                                *bool               `json:"failSwapOn,omitempty"`
+	ContainerLogMaxSize                       string              `json:"containerLogMaxSize,omitempty"`
+	ContainerLogMaxFiles                      *int32              `json:"containerLogMaxFiles,omitempty"`
+	ConfigMapAndSecretChangeDetectionStrategy string              `json:"configMapAndSecretChangeDetectionStrategy,omitempty"`
+	SystemReserved                            map[string]string   `json:"systemReserved,omitempty"`
+	KubeReserved                              map[string]string   `json:"kubeReserved,omitempty"`
+	SystemReservedCgroup                      string              `json:"systemReservedCgroup,omitempty"`
+	KubeReservedCgroup                        string              `json:"kubeReservedCgroup,omitempty"`
+	EnforceNodeAllocatable                    []string            `json:"enforceNodeAllocatable,omitempty"`
+	AllowedUnsafeSysctls                      []string            `json:"allowedUnsafeSysctls,omitempty"`
+}
+
+// Authentication mirrors KubeletAuthentication
+type Authentication struct {
+	X509      *X509Authentication      `json:"x509,omitempty"`
+	Webhook   *WebhookAuthentication   `json:"webhook,omitempty"`
+	Anonymous *AnonymousAuthentication `json:"anonymous,omitempty"`
+}
+
+// X509Authentication mirrors KubeletX509Authentication
+type X509Authentication struct {
+	ClientCAFile string `json:"clientCAFile,omitempty"`
+}
+
+// WebhookAuthentication mirrors KubeletWebhookAuthentication
+type WebhookAuthentication struct {
+	Enabled  *bool            `json:"enabled,omitempty"`
+	CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
+}
+
+// AnonymousAuthentication mirrors KubeletAnonymousAuthentication
+type AnonymousAuthentication struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// Authorization mirrors KubeletAuthorization
+type Authorization struct {
+	Mode    string                `json:"mode,omitempty"`
+	Webhook *WebhookAuthorization `json:"webhook,omitempty"`
+}
+
+// WebhookAuthorization mirrors KubeletWebhookAuthorization
+type WebhookAuthorization struct {
+	CacheAuthorizedTTL   *metav1.Duration `json:"cacheAuthorizedTTL,omitempty"`
+	CacheUnauthorizedTTL *metav1.Duration `json:"cacheUnauthorizedTTL,omitempty"`
+}
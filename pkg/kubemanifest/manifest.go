@@ -17,9 +17,11 @@ limitations under the License.
 package kubemanifest
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/klog"
 	"k8s.io/kops/util/pkg/text"
 )
@@ -59,6 +61,26 @@ func (m *Manifest) ToYAML() ([]byte, error) {
 	return b, nil
 }
 
+// ToObject converts the manifest to an unstructured kubernetes object.
+// Returns nil if the manifest is empty, for example a section holding only comments.
+func (m *Manifest) ToObject() (*unstructured.Unstructured, error) {
+	if len(m.data) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(m.data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling manifest to json: %v", err)
+	}
+
+	// Round-trip through the unstructured decoder, so that numbers are represented as the apimachinery helpers expect
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(b); err != nil {
+		return nil, fmt.Errorf("error parsing manifest as kubernetes object: %v", err)
+	}
+	return u, nil
+}
+
 func (m *Manifest) accept(visitor Visitor) error {
 	err := visit(visitor, m.data, []string{}, func(v interface{}) {
 		klog.Fatal("cannot mutate top-level data")
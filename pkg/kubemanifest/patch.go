@@ -0,0 +1,138 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemanifest
+
+import (
+	"bytes"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kops/util/pkg/text"
+)
+
+// PatchType is the type of a patch
+type PatchType string
+
+const (
+	// PatchTypeStrategic is a strategic merge patch, as used by kubectl patch.
+	// Kinds that are not known to kops are patched with a JSON merge patch.
+	PatchTypeStrategic PatchType = "strategic"
+	// PatchTypeJSON is a JSON patch, as defined in RFC 6902
+	PatchTypeJSON PatchType = "json"
+)
+
+// Patch is a patch to an object in a manifest
+type Patch struct {
+	// Kind is the kind of the object to patch
+	Kind string
+	// Namespace is the namespace of the object to patch; if empty the object is matched in any namespace
+	Namespace string
+	// Name is the name of the object to patch
+	Name string
+
+	// Type is the type of the patch
+	Type PatchType
+	// Patch is the patch, in YAML or JSON
+	Patch []byte
+}
+
+// String returns a description of the object the patch applies to
+func (p *Patch) String() string {
+	if p.Namespace != "" {
+		return p.Kind + "/" + p.Namespace + "/" + p.Name
+	}
+	return p.Kind + "/" + p.Name
+}
+
+// ApplyPatch applies the patch to the matching objects in a multi-document manifest.
+// Documents that are not patched are returned unchanged; patched documents are reformatted.
+// It returns false if no object matched the patch.
+func ApplyPatch(contents []byte, patch *Patch) ([]byte, bool, error) {
+	patchJSON, err := yaml.YAMLToJSON(patch.Patch)
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing patch for %s: %v", patch, err)
+	}
+
+	matched := false
+	sections := text.SplitContentToSections(contents)
+	for i, section := range sections {
+		data := make(map[string]interface{})
+		if err := yaml.Unmarshal(section, &data); err != nil {
+			return nil, false, fmt.Errorf("error parsing yaml: %v", err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		manifest := &Manifest{data: data}
+		u, err := manifest.ToObject()
+		if err != nil {
+			return nil, false, err
+		}
+		if u.GetKind() != patch.Kind || u.GetName() != patch.Name {
+			continue
+		}
+		if patch.Namespace != "" && u.GetNamespace() != patch.Namespace {
+			continue
+		}
+		matched = true
+
+		original, err := u.MarshalJSON()
+		if err != nil {
+			return nil, false, fmt.Errorf("error serializing %s: %v", patch, err)
+		}
+
+		var patched []byte
+		switch patch.Type {
+		case PatchTypeStrategic, "":
+			obj, err := scheme.Scheme.New(u.GroupVersionKind())
+			if err != nil {
+				// Not a kind we know the schema of, so we cannot do a strategic merge
+				patched, err = jsonpatch.MergePatch(original, patchJSON)
+			} else {
+				patched, err = strategicpatch.StrategicMergePatch(original, patchJSON, obj)
+			}
+			if err != nil {
+				return nil, false, fmt.Errorf("error applying patch to %s: %v", patch, err)
+			}
+
+		case PatchTypeJSON:
+			ops, err := jsonpatch.DecodePatch(patchJSON)
+			if err != nil {
+				return nil, false, fmt.Errorf("error parsing json patch for %s: %v", patch, err)
+			}
+			patched, err = ops.Apply(original)
+			if err != nil {
+				return nil, false, fmt.Errorf("error applying patch to %s: %v", patch, err)
+			}
+
+		default:
+			return nil, false, fmt.Errorf("unknown patch type %q", patch.Type)
+		}
+
+		patchedYAML, err := yaml.JSONToYAML(patched)
+		if err != nil {
+			return nil, false, fmt.Errorf("error converting %s to yaml: %v", patch, err)
+		}
+		sections[i] = bytes.TrimSuffix(patchedYAML, []byte("\n"))
+	}
+
+	return bytes.Join(sections, []byte("\n---\n")), matched, nil
+}
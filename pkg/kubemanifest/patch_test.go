@@ -0,0 +1,193 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemanifest
+
+import (
+	"strings"
+	"testing"
+)
+
+const testPatchManifest = `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: kube-proxy
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: kube-proxy
+        image: k8s.gcr.io/kube-proxy:v1.15.0
+      - name: sidecar
+        image: busybox
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: kube-system
+spec:
+  size: 1
+  items:
+  - name: a
+  - name: b
+`
+
+func TestApplyPatch(t *testing.T) {
+	// document is the index of the document the patch applies to, and expected is that document once patched
+	grid := []struct {
+		name      string
+		patch     Patch
+		document  int
+		expected  string
+		unmatched bool
+		expectErr bool
+	}{
+		{
+			name: "strategic merge of a known kind merges containers by name",
+			patch: Patch{
+				Kind:  "DaemonSet",
+				Name:  "kube-proxy",
+				Type:  PatchTypeStrategic,
+				Patch: []byte("spec:\n  template:\n    spec:\n      containers:\n      - name: sidecar\n        image: busybox:1.31\n"),
+			},
+			expected: `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: kube-proxy
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - image: k8s.gcr.io/kube-proxy:v1.15.0
+        name: kube-proxy
+      - image: busybox:1.31
+        name: sidecar`,
+		},
+		{
+			name: "unknown kind falls back to a JSON merge patch",
+			patch: Patch{
+				Kind:  "Widget",
+				Name:  "widget",
+				Patch: []byte(`{"spec": {"items": [{"name": "c"}]}}`),
+			},
+			document: 1,
+			expected: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: kube-system
+spec:
+  items:
+  - name: c
+  size: 1`,
+		},
+		{
+			name: "json patch",
+			patch: Patch{
+				Kind:  "Widget",
+				Name:  "widget",
+				Type:  PatchTypeJSON,
+				Patch: []byte(`[{"op": "replace", "path": "/spec/size", "value": 3}]`),
+			},
+			document: 1,
+			expected: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: kube-system
+spec:
+  items:
+  - name: a
+  - name: b
+  size: 3`,
+		},
+		{
+			name: "object in another namespace is not patched",
+			patch: Patch{
+				Kind:      "Widget",
+				Namespace: "default",
+				Name:      "widget",
+				Patch:     []byte(`{"spec": {"size": 3}}`),
+			},
+			unmatched: true,
+		},
+		{
+			name: "invalid patch",
+			patch: Patch{
+				Kind:  "DaemonSet",
+				Name:  "kube-proxy",
+				Patch: []byte("spec: [unterminated"),
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid json patch operation",
+			patch: Patch{
+				Kind:  "Widget",
+				Name:  "widget",
+				Type:  PatchTypeJSON,
+				Patch: []byte(`[{"op": "remove", "path": "/spec/missing"}]`),
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown patch type",
+			patch: Patch{
+				Kind:  "Widget",
+				Name:  "widget",
+				Type:  "unknown",
+				Patch: []byte(`{}`),
+			},
+			expectErr: true,
+		},
+	}
+
+	sections := strings.Split(testPatchManifest, "\n---\n")
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			actual, matched, err := ApplyPatch([]byte(testPatchManifest), &g.patch)
+			if g.expectErr {
+				if err == nil {
+					t.Errorf("expected error applying patch")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error applying patch: %v", err)
+			}
+			if matched == g.unmatched {
+				t.Errorf("patch matched was %v, expected %v", matched, !g.unmatched)
+			}
+
+			actualSections := strings.Split(string(actual), "\n---\n")
+			if len(actualSections) != len(sections) {
+				t.Fatalf("expected %d documents, got %d:\n%s", len(sections), len(actualSections), actual)
+			}
+			for i, section := range actualSections {
+				expected := strings.TrimSuffix(sections[i], "\n")
+				if i == g.document && g.expected != "" {
+					expected = g.expected
+				}
+				if strings.TrimSuffix(section, "\n") != expected {
+					t.Errorf("unexpected document %d, expected:\n%s\nactual:\n%s", i, expected, section)
+				}
+			}
+		})
+	}
+}
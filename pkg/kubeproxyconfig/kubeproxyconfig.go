@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxyconfig
+
+import (
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/componentconfig"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// APIVersion is the apiVersion of the kube-proxy config file
+	APIVersion = "kubeproxy.config.k8s.io/v1alpha1"
+	// Kind is the kind of the kube-proxy config file
+	Kind = "KubeProxyConfiguration"
+)
+
+// BuildConfig splits the kube-proxy spec into a KubeProxyConfiguration and the remaining spec,
+// which holds the settings that can only be passed as flags.
+// kube-proxy ignores flags that have a config file equivalent once --config is set.
+func BuildConfig(spec *kops.KubeProxyConfig) (*KubeProxyConfiguration, *kops.KubeProxyConfig, error) {
+	flags := *spec
+	config := &KubeProxyConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       Kind,
+		},
+	}
+
+	config.BindAddress, flags.BindAddress = spec.BindAddress, ""
+	config.ClusterCIDR, flags.ClusterCIDR = spec.ClusterCIDR, ""
+	config.HostnameOverride, flags.HostnameOverride = spec.HostnameOverride, ""
+	config.MetricsBindAddress, flags.MetricsBindAddress = fi.StringValue(spec.MetricsBindAddress), nil
+	config.Mode, flags.ProxyMode = spec.ProxyMode, ""
+
+	if spec.ConntrackMaxPerCore != nil || spec.ConntrackMin != nil {
+		config.Conntrack = &KubeProxyConntrackConfiguration{
+			MaxPerCore: spec.ConntrackMaxPerCore,
+			Min:        spec.ConntrackMin,
+		}
+		flags.ConntrackMaxPerCore = nil
+		flags.ConntrackMin = nil
+	}
+
+	if spec.IPVSSyncPeriod != nil || spec.IPVSMinSyncPeriod != nil || spec.IPVSScheduler != nil || len(spec.IPVSExcludeCIDRS) != 0 ||
+		spec.IPVSStrictARP != nil || spec.IPVSTCPTimeout != nil || spec.IPVSTCPFinTimeout != nil || spec.IPVSUDPTimeout != nil {
+		config.IPVS = &KubeProxyIPVSConfiguration{
+			SyncPeriod:    spec.IPVSSyncPeriod,
+			MinSyncPeriod: spec.IPVSMinSyncPeriod,
+			Scheduler:     fi.StringValue(spec.IPVSScheduler),
+			ExcludeCIDRs:  spec.IPVSExcludeCIDRS,
+			StrictARP:     spec.IPVSStrictARP,
+			TCPTimeout:    spec.IPVSTCPTimeout,
+			TCPFinTimeout: spec.IPVSTCPFinTimeout,
+			UDPTimeout:    spec.IPVSUDPTimeout,
+		}
+	}
+	flags.IPVSSyncPeriod = nil
+	flags.IPVSMinSyncPeriod = nil
+	flags.IPVSScheduler = nil
+	flags.IPVSExcludeCIDRS = nil
+	flags.IPVSStrictARP = nil
+	flags.IPVSTCPTimeout = nil
+	flags.IPVSTCPFinTimeout = nil
+	flags.IPVSUDPTimeout = nil
+
+	if len(spec.FeatureGates) != 0 {
+		config.FeatureGates = make(map[string]bool)
+		for k, v := range spec.FeatureGates {
+			enabled, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, nil, fmt.Errorf("feature gate %q must be true or false, was %q", k, v)
+			}
+			config.FeatureGates[k] = enabled
+		}
+		flags.FeatureGates = nil
+	}
+
+	flags.ConfigOverrides = nil
+
+	return config, &flags, nil
+}
+
+// Marshal renders the config file, merging the overrides into the generated config
+func Marshal(config *KubeProxyConfiguration, overrides *runtime.RawExtension) ([]byte, error) {
+	return componentconfig.Marshal(config, overrides)
+}
+
+// ValidateOverrides checks that the overrides only hold fields of the KubeProxyConfiguration, with values of the right type
+func ValidateOverrides(overrides *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	return componentconfig.ValidateOverrides(overrides, fldPath, []string{APIVersion}, Kind, &KubeProxyConfiguration{})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxyconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBuildConfig(t *testing.T) {
+	spec := &kops.KubeProxyConfig{
+		Image:               "k8s.gcr.io/kube-proxy:v1.15.0",
+		CPURequest:          "100m",
+		LogLevel:            2,
+		ClusterCIDR:         "100.96.0.0/11",
+		HostnameOverride:    "ip-172-20-32-10.ec2.internal",
+		Master:              "https://api.internal.minimal.example.com",
+		MetricsBindAddress:  fi.String("0.0.0.0"),
+		ProxyMode:           "ipvs",
+		IPVSScheduler:       fi.String("lc"),
+		IPVSExcludeCIDRS:    []string{"10.0.0.0/8"},
+		IPVSStrictARP:       fi.Bool(true),
+		IPVSTCPTimeout:      &metav1.Duration{Duration: 15 * time.Minute},
+		FeatureGates:        map[string]string{"SupportIPVSProxyMode": "true"},
+		ConntrackMaxPerCore: fi.Int32(131072),
+		ConfigOverrides:     &runtime.RawExtension{Raw: []byte(`{"ipvs":{"syncPeriod":"60s"},"udpIdleTimeout":"500ms"}`)},
+	}
+
+	config, remaining, err := BuildConfig(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flags, err := flagbuilder.BuildFlags(remaining)
+	if err != nil {
+		t.Fatalf("error building flags: %v", err)
+	}
+	expectedFlags := "--master=https://api.internal.minimal.example.com --v=2"
+	if flags != expectedFlags {
+		t.Errorf("unexpected flags\nexpected: %s\ngot:      %s", expectedFlags, flags)
+	}
+
+	manifest, err := Marshal(config, spec.ConfigOverrides)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: kubeproxy.config.k8s.io/v1alpha1
+clusterCIDR: 100.96.0.0/11
+conntrack:
+  maxPerCore: 131072
+featureGates:
+  SupportIPVSProxyMode: true
+hostnameOverride: ip-172-20-32-10.ec2.internal
+ipvs:
+  excludeCIDRs:
+  - 10.0.0.0/8
+  scheduler: lc
+  strictARP: true
+  syncPeriod: 60s
+  tcpTimeout: 15m0s
+kind: KubeProxyConfiguration
+metricsBindAddress: 0.0.0.0
+mode: ipvs
+udpIdleTimeout: 500ms
+`
+	if string(manifest) != expected {
+		t.Errorf("unexpected config\nexpected:\n%s\ngot:\n%s", expected, manifest)
+	}
+}
+
+func TestBuildConfigErrors(t *testing.T) {
+	spec := &kops.KubeProxyConfig{FeatureGates: map[string]string{"Foo": "yes please"}}
+	if _, _, err := BuildConfig(spec); err == nil {
+		t.Errorf("expected an error building %+v", spec)
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	grid := []struct {
+		overrides string
+		expected  []string
+	}{
+		{overrides: `{"ipvs":{"udpTimeout":"30s"},"nodePortAddresses":["10.0.0.0/8"]}`},
+		{overrides: `{"apiVersion":"kubeproxy.config.k8s.io/v1alpha1","kind":"KubeProxyConfiguration","mode":"iptables"}`},
+		{overrides: `{"ipvs":{"arpMode":"strict"}}`, expected: []string{"Invalid value::spec.kubeProxy.configOverrides"}},
+		{overrides: `{"kind":"KubeletConfiguration"}`, expected: []string{"Unsupported value::spec.kubeProxy.configOverrides.kind"}},
+	}
+	for _, g := range grid {
+		errs := ValidateOverrides(&runtime.RawExtension{Raw: []byte(g.overrides)}, field.NewPath("spec", "kubeProxy", "configOverrides"))
+		var actual []string
+		for _, err := range errs {
+			actual = append(actual, err.Type.String()+"::"+err.Field)
+		}
+		if strings.Join(actual, ",") != strings.Join(g.expected, ",") {
+			t.Errorf("unexpected errors for %s: %v, expected %v", g.overrides, errs, g.expected)
+		}
+	}
+}
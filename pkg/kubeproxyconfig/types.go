@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeproxyconfig
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KubeProxyConfiguration mirrors kubeproxy.config.k8s.io/v1alpha1 KubeProxyConfiguration as of kubernetes 1.18.
+// The upstream types live in k8s.io/kube-proxy, which is not vendored; the field names and types here must match them.
+// Scalars are pointers so that unset fields are omitted and kube-proxy applies its own defaults.
+type KubeProxyConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	FeatureGates                map[string]bool                  `json:"featureGates,omitempty"`
+	BindAddress                 string                           `json:"bindAddress,omitempty"`
+	HealthzBindAddress          string                           `json:"healthzBindAddress,omitempty"`
+	MetricsBindAddress          string                           `json:"metricsBindAddress,omitempty"`
+	BindAddressHardFail         *bool                            `json:"bindAddressHardFail,omitempty"`
+	EnableProfiling             *bool                            `json:"enableProfiling,omitempty"`
+	ClusterCIDR                 string                           `json:"clusterCIDR,omitempty"`
+	HostnameOverride            string                           `json:"hostnameOverride,omitempty"`
+	ClientConnection            *ClientConnectionConfiguration   `json:"clientConnection,omitempty"`
+	IPTables                    *KubeProxyIPTablesConfiguration  `json:"iptables,omitempty"`
+	IPVS                        *KubeProxyIPVSConfiguration      `json:"ipvs,omitempty"`
+	OOMScoreAdj                 *int32                           `json:"oomScoreAdj,omitempty"`
+	Mode                        string                           `json:"mode,omitempty"`
+	PortRange                   string                           `json:"portRange,omitempty"`
+	ResourceContainer           *string                          `json:"resourceContainer,omitempty"`
+	UDPIdleTimeout              *metav1.Duration                 `json:"udpIdleTimeout,omitempty"`
+	Conntrack                   *KubeProxyConntrackConfiguration `json:"conntrack,omitempty"`
+	ConfigSyncPeriod            *metav1.Duration                 `json:"configSyncPeriod,omitempty"`
+	NodePortAddresses           []string                         `json:"nodePortAddresses,omitempty"`
+	Winkernel                   *KubeProxyWinkernelConfiguration `json:"winkernel,omitempty"`
+	ShowHiddenMetricsForVersion string                           `json:"showHiddenMetricsForVersion,omitempty"`
+	DetectLocalMode             string                           `json:"detectLocalMode,omitempty"`
+}
+
+// ClientConnectionConfiguration mirrors the client connection settings of kube-proxy
+type ClientConnectionConfiguration struct {
+	Kubeconfig         string   `json:"kubeconfig,omitempty"`
+	AcceptContentTypes string   `json:"acceptContentTypes,omitempty"`
+	ContentType        string   `json:"contentType,omitempty"`
+	QPS                *float32 `json:"qps,omitempty"`
+	Burst              *int32   `json:"burst,omitempty"`
+}
+
+// KubeProxyIPTablesConfiguration mirrors the iptables settings of kube-proxy
+type KubeProxyIPTablesConfiguration struct {
+	MasqueradeBit *int32           `json:"masqueradeBit,omitempty"`
+	MasqueradeAll *bool            `json:"masqueradeAll,omitempty"`
+	SyncPeriod    *metav1.Duration `json:"syncPeriod,omitempty"`
+	MinSyncPeriod *metav1.Duration `json:"minSyncPeriod,omitempty"`
+}
+
+// KubeProxyIPVSConfiguration mirrors the ipvs settings of kube-proxy
+type KubeProxyIPVSConfiguration struct {
+	SyncPeriod    *metav1.Duration `json:"syncPeriod,omitempty"`
+	MinSyncPeriod *metav1.Duration `json:"minSyncPeriod,omitempty"`
+	Scheduler     string           `json:"scheduler,omitempty"`
+	ExcludeCIDRs  []string         `json:"excludeCIDRs,omitempty"`
+	StrictARP     *bool            `json:"strictARP,omitempty"`
+	TCPTimeout    *metav1.Duration `json:"tcpTimeout,omitempty"`
+	TCPFinTimeout *metav1.Duration `json:"tcpFinTimeout,omitempty"`
+	UDPTimeout    *metav1.Duration `json:"udpTimeout,omitempty"`
+}
+
+// KubeProxyConntrackConfiguration mirrors the conntrack settings of kube-proxy
+type KubeProxyConntrackConfiguration struct {
+	MaxPerCore            *int32           `json:"maxPerCore,omitempty"`
+	Min                   *int32           `json:"min,omitempty"`
+	TCPEstablishedTimeout *metav1.Duration `json:"tcpEstablishedTimeout,omitempty"`
+	TCPCloseWaitTimeout   *metav1.Duration `json:"tcpCloseWaitTimeout,omitempty"`
+}
+
+// KubeProxyWinkernelConfiguration mirrors the windows kernel settings of kube-proxy
+type KubeProxyWinkernelConfiguration struct {
+	NetworkName string `json:"networkName,omitempty"`
+	SourceVip   string `json:"sourceVip,omitempty"`
+	EnableDSR   *bool  `json:"enableDSR,omitempty"`
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeschedulerconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/blang/semver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/componentconfig"
+)
+
+const (
+	// APIVersionV1Alpha1 is the apiVersion of the scheduler config file from kubernetes 1.12 to 1.17
+	APIVersionV1Alpha1 = "kubescheduler.config.k8s.io/v1alpha1"
+	// APIVersionV1Alpha2 is the apiVersion of the scheduler config file in kubernetes 1.18
+	APIVersionV1Alpha2 = "kubescheduler.config.k8s.io/v1alpha2"
+	// APIVersionV1Beta1 is the apiVersion of the scheduler config file from kubernetes 1.19
+	APIVersionV1Beta1 = "kubescheduler.config.k8s.io/v1beta1"
+	// Kind is the kind of the scheduler config file
+	Kind = "KubeSchedulerConfiguration"
+
+	// PolicyConfigMapName is the name of the ConfigMap holding the scheduler policy, when UsePolicyConfigMap is set
+	PolicyConfigMapName = "scheduler-policy"
+	// PolicyConfigMapNamespace is the namespace of the ConfigMap holding the scheduler policy
+	PolicyConfigMapNamespace = "kube-system"
+)
+
+// APIVersion returns the apiVersion of the scheduler config file for the kubernetes version
+func APIVersion(k8sVersion semver.Version) string {
+	if util.IsKubernetesGTE("1.19", k8sVersion) {
+		return APIVersionV1Beta1
+	}
+	if util.IsKubernetesGTE("1.18", k8sVersion) {
+		return APIVersionV1Alpha2
+	}
+	return APIVersionV1Alpha1
+}
+
+// BuildConfig splits the scheduler spec into a KubeSchedulerConfiguration for the kubernetes version and the remaining spec,
+// which holds the settings that can only be passed as flags.
+// The scheduler ignores its deprecated flags, such as --leader-elect, once --config is set.
+func BuildConfig(spec *kops.KubeSchedulerConfig, k8sVersion semver.Version) (*KubeSchedulerConfiguration, *kops.KubeSchedulerConfig, error) {
+	flags := *spec
+	config := &KubeSchedulerConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion(k8sVersion),
+			Kind:       Kind,
+		},
+	}
+
+	if spec.LeaderElection != nil && spec.LeaderElection.LeaderElect != nil {
+		config.LeaderElection = &LeaderElectionConfiguration{LeaderElect: spec.LeaderElection.LeaderElect}
+	}
+	flags.LeaderElection = nil
+
+	var profiles []KubeSchedulerProfile
+	if len(spec.Profiles) != 0 {
+		data, err := json.Marshal(spec.Profiles)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error marshaling scheduler profiles: %v", err)
+		}
+		if err := componentconfig.DecodeStrict(data, &profiles); err != nil {
+			return nil, nil, fmt.Errorf("error converting scheduler profiles: %v", err)
+		}
+	}
+
+	if config.APIVersion == APIVersionV1Alpha1 {
+		// v1alpha1 holds the policy source and a single profile at the top level.
+		// From v1alpha2 the policy can only be set with flags.
+		if spec.UsePolicyConfigMap != nil {
+			config.AlgorithmSource = &SchedulerAlgorithmSource{
+				Policy: &SchedulerPolicySource{
+					ConfigMap: &SchedulerPolicyConfigMapSource{
+						Namespace: PolicyConfigMapNamespace,
+						Name:      PolicyConfigMapName,
+					},
+				},
+			}
+			flags.UsePolicyConfigMap = nil
+		}
+
+		if len(profiles) > 1 {
+			return nil, nil, fmt.Errorf("kubernetes versions before 1.18 support a single scheduler profile, found %d", len(profiles))
+		}
+		if len(profiles) == 1 {
+			config.SchedulerName = profiles[0].SchedulerName
+			config.Plugins = profiles[0].Plugins
+			config.PluginConfig = profiles[0].PluginConfig
+		}
+	} else {
+		config.Profiles = profiles
+	}
+	flags.Profiles = nil
+
+	flags.ConfigOverrides = nil
+
+	return config, &flags, nil
+}
+
+// Marshal renders the config file, merging the overrides into the generated config
+func Marshal(config *KubeSchedulerConfiguration, overrides *runtime.RawExtension) ([]byte, error) {
+	return componentconfig.Marshal(config, overrides)
+}
+
+// ValidateOverrides checks that the overrides only hold fields of the KubeSchedulerConfiguration, with values of the right type
+func ValidateOverrides(overrides *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	apiVersions := []string{APIVersionV1Alpha1, APIVersionV1Alpha2, APIVersionV1Beta1}
+	return componentconfig.ValidateOverrides(overrides, fldPath, apiVersions, Kind, &KubeSchedulerConfiguration{})
+}
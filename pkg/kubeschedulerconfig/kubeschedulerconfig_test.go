@@ -0,0 +1,186 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeschedulerconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBuildConfig(t *testing.T) {
+	binPacking := kops.SchedulerProfile{
+		SchedulerName: "bin-packing",
+		Plugins: &kops.SchedulerPlugins{
+			Score: &kops.SchedulerPluginSet{
+				Enabled:  []kops.SchedulerPlugin{{Name: "NodeResourcesMostAllocated", Weight: fi.Int32(2)}},
+				Disabled: []kops.SchedulerPlugin{{Name: "NodeResourcesLeastAllocated"}},
+			},
+		},
+		PluginConfig: []kops.SchedulerPluginConfig{
+			{Name: "NodeResourcesMostAllocated", Args: &runtime.RawExtension{Raw: []byte(`{"resources":[{"name":"cpu","weight":1}]}`)}},
+		},
+	}
+
+	grid := []struct {
+		version       string
+		spec          kops.KubeSchedulerConfig
+		expectedFlags string
+		policyFlags   bool
+		expected      string
+	}{
+		{
+			version: "1.12.0",
+			spec: kops.KubeSchedulerConfig{
+				Image:              "k8s.gcr.io/kube-scheduler:v1.12.0",
+				LogLevel:           2,
+				LeaderElection:     &kops.LeaderElectionConfiguration{LeaderElect: fi.Bool(true)},
+				UsePolicyConfigMap: fi.Bool(true),
+				FeatureGates:       map[string]string{"PodPriority": "true"},
+			},
+			expectedFlags: "--feature-gates=PodPriority=true --v=2",
+			expected: `algorithmSource:
+  policy:
+    configMap:
+      name: scheduler-policy
+      namespace: kube-system
+apiVersion: kubescheduler.config.k8s.io/v1alpha1
+kind: KubeSchedulerConfiguration
+leaderElection:
+  leaderElect: true
+`,
+		},
+		{
+			version: "1.15.3",
+			spec: kops.KubeSchedulerConfig{
+				Profiles:        []kops.SchedulerProfile{binPacking},
+				ConfigOverrides: &runtime.RawExtension{Raw: []byte(`{"percentageOfNodesToScore":50}`)},
+			},
+			expectedFlags: "--v=0",
+			expected: `apiVersion: kubescheduler.config.k8s.io/v1alpha1
+kind: KubeSchedulerConfiguration
+percentageOfNodesToScore: 50
+pluginConfig:
+- args:
+    resources:
+    - name: cpu
+      weight: 1
+  name: NodeResourcesMostAllocated
+plugins:
+  score:
+    disabled:
+    - name: NodeResourcesLeastAllocated
+    enabled:
+    - name: NodeResourcesMostAllocated
+      weight: 2
+schedulerName: bin-packing
+`,
+		},
+		{
+			version: "1.18.0",
+			spec: kops.KubeSchedulerConfig{
+				UsePolicyConfigMap: fi.Bool(true),
+				Profiles:           []kops.SchedulerProfile{{SchedulerName: "default-scheduler"}, binPacking},
+			},
+			expectedFlags: "--v=0",
+			policyFlags:   true,
+			expected: `apiVersion: kubescheduler.config.k8s.io/v1alpha2
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: default-scheduler
+- pluginConfig:
+  - args:
+      resources:
+      - name: cpu
+        weight: 1
+    name: NodeResourcesMostAllocated
+  plugins:
+    score:
+      disabled:
+      - name: NodeResourcesLeastAllocated
+      enabled:
+      - name: NodeResourcesMostAllocated
+        weight: 2
+  schedulerName: bin-packing
+`,
+		},
+	}
+	for _, g := range grid {
+		config, remaining, err := BuildConfig(&g.spec, semver.MustParse(g.version))
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", g.version, err)
+			continue
+		}
+
+		flags, err := flagbuilder.BuildFlags(remaining)
+		if err != nil {
+			t.Errorf("error building flags for %s: %v", g.version, err)
+			continue
+		}
+		if flags != g.expectedFlags {
+			t.Errorf("unexpected flags for %s\nexpected: %s\ngot:      %s", g.version, g.expectedFlags, flags)
+		}
+		if (remaining.UsePolicyConfigMap != nil) != g.policyFlags {
+			t.Errorf("unexpected UsePolicyConfigMap for %s: %v", g.version, remaining.UsePolicyConfigMap)
+		}
+
+		manifest, err := Marshal(config, g.spec.ConfigOverrides)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", g.version, err)
+			continue
+		}
+		if string(manifest) != g.expected {
+			t.Errorf("unexpected config for %s\nexpected:\n%s\ngot:\n%s", g.version, g.expected, manifest)
+		}
+	}
+}
+
+func TestBuildConfigErrors(t *testing.T) {
+	spec := &kops.KubeSchedulerConfig{
+		Profiles: []kops.SchedulerProfile{{SchedulerName: "default-scheduler"}, {SchedulerName: "bin-packing"}},
+	}
+	if _, _, err := BuildConfig(spec, semver.MustParse("1.17.0")); err == nil {
+		t.Errorf("expected an error building multiple profiles for 1.17")
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	grid := []struct {
+		overrides string
+		expected  []string
+	}{
+		{overrides: `{"apiVersion":"kubescheduler.config.k8s.io/v1alpha2","percentageOfNodesToScore":50,"extenders":[{"urlPrefix":"http://127.0.0.1:8888","filterVerb":"filter"}]}`},
+		{overrides: `{"apiVersion":"componentconfig/v1alpha1"}`, expected: []string{"Unsupported value::spec.kubeScheduler.configOverrides.apiVersion"}},
+		{overrides: `{"percentageOfNodesToScore":"half"}`, expected: []string{"Invalid value::spec.kubeScheduler.configOverrides"}},
+	}
+	for _, g := range grid {
+		errs := ValidateOverrides(&runtime.RawExtension{Raw: []byte(g.overrides)}, field.NewPath("spec", "kubeScheduler", "configOverrides"))
+		var actual []string
+		for _, err := range errs {
+			actual = append(actual, err.Type.String()+"::"+err.Field)
+		}
+		if strings.Join(actual, ",") != strings.Join(g.expected, ",") {
+			t.Errorf("unexpected errors for %s: %v, expected %v", g.overrides, errs, g.expected)
+		}
+	}
+}
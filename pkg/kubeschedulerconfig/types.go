@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeschedulerconfig
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// KubeSchedulerConfiguration mirrors kubescheduler.config.k8s.io KubeSchedulerConfiguration,
+// covering v1alpha1 (kubernetes 1.12 to 1.17), v1alpha2 (1.18) and v1beta1 (1.19).
+// The upstream types live in k8s.io/kube-scheduler, which is not vendored; the field names and types here must match them.
+// SchedulerName, AlgorithmSource, HardPodAffinitySymmetricWeight, Plugins and PluginConfig only exist in v1alpha1,
+// Profiles only exists from v1alpha2.
+type KubeSchedulerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	SchedulerName                  string                         `json:"schedulerName,omitempty"`
+	AlgorithmSource                *SchedulerAlgorithmSource      `json:"algorithmSource,omitempty"`
+	HardPodAffinitySymmetricWeight *int32                         `json:"hardPodAffinitySymmetricWeight,omitempty"`
+	LeaderElection                 *LeaderElectionConfiguration   `json:"leaderElection,omitempty"`
+	ClientConnection               *ClientConnectionConfiguration `json:"clientConnection,omitempty"`
+	HealthzBindAddress             string                         `json:"healthzBindAddress,omitempty"`
+	MetricsBindAddress             string                         `json:"metricsBindAddress,omitempty"`
+	EnableProfiling                *bool                          `json:"enableProfiling,omitempty"`
+	EnableContentionProfiling      *bool                          `json:"enableContentionProfiling,omitempty"`
+	DisablePreemption              *bool                          `json:"disablePreemption,omitempty"`
+	PercentageOfNodesToScore       *int32                         `json:"percentageOfNodesToScore,omitempty"`
+	BindTimeoutSeconds             *int64                         `json:"bindTimeoutSeconds,omitempty"`
+	PodInitialBackoffSeconds       *int64                         `json:"podInitialBackoffSeconds,omitempty"`
+	PodMaxBackoffSeconds           *int64                         `json:"podMaxBackoffSeconds,omitempty"`
+	Plugins                        *Plugins                       `json:"plugins,omitempty"`
+	PluginConfig                   []PluginConfig                 `json:"pluginConfig,omitempty"`
+	Profiles                       []KubeSchedulerProfile         `json:"profiles,omitempty"`
+	Extenders                      []Extender                     `json:"extenders,omitempty"`
+}
+
+// SchedulerAlgorithmSource mirrors the source of the scheduling algorithm
+type SchedulerAlgorithmSource struct {
+	Policy   *SchedulerPolicySource `json:"policy,omitempty"`
+	Provider *string                `json:"provider,omitempty"`
+}
+
+// SchedulerPolicySource mirrors the source of a scheduler policy
+type SchedulerPolicySource struct {
+	File      *SchedulerPolicyFileSource      `json:"file,omitempty"`
+	ConfigMap *SchedulerPolicyConfigMapSource `json:"configMap,omitempty"`
+}
+
+// SchedulerPolicyFileSource mirrors a scheduler policy read from a file
+type SchedulerPolicyFileSource struct {
+	Path string `json:"path"`
+}
+
+// SchedulerPolicyConfigMapSource mirrors a scheduler policy read from a ConfigMap
+type SchedulerPolicyConfigMapSource struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// LeaderElectionConfiguration mirrors the leader election settings of the scheduler
+type LeaderElectionConfiguration struct {
+	LeaderElect         *bool            `json:"leaderElect,omitempty"`
+	LeaseDuration       *metav1.Duration `json:"leaseDuration,omitempty"`
+	RenewDeadline       *metav1.Duration `json:"renewDeadline,omitempty"`
+	RetryPeriod         *metav1.Duration `json:"retryPeriod,omitempty"`
+	ResourceLock        string           `json:"resourceLock,omitempty"`
+	ResourceName        string           `json:"resourceName,omitempty"`
+	ResourceNamespace   string           `json:"resourceNamespace,omitempty"`
+	LockObjectNamespace string           `json:"lockObjectNamespace,omitempty"`
+	LockObjectName      string           `json:"lockObjectName,omitempty"`
+}
+
+// ClientConnectionConfiguration mirrors the client connection settings of the scheduler
+type ClientConnectionConfiguration struct {
+	Kubeconfig         string   `json:"kubeconfig,omitempty"`
+	AcceptContentTypes string   `json:"acceptContentTypes,omitempty"`
+	ContentType        string   `json:"contentType,omitempty"`
+	QPS                *float32 `json:"qps,omitempty"`
+	Burst              *int32   `json:"burst,omitempty"`
+}
+
+// KubeSchedulerProfile mirrors a scheduling profile
+type KubeSchedulerProfile struct {
+	SchedulerName string         `json:"schedulerName,omitempty"`
+	Plugins       *Plugins       `json:"plugins,omitempty"`
+	PluginConfig  []PluginConfig `json:"pluginConfig,omitempty"`
+}
+
+// Plugins mirrors the plugins of each extension point of the scheduling framework
+type Plugins struct {
+	QueueSort  *PluginSet `json:"queueSort,omitempty"`
+	PreFilter  *PluginSet `json:"preFilter,omitempty"`
+	Filter     *PluginSet `json:"filter,omitempty"`
+	PostFilter *PluginSet `json:"postFilter,omitempty"`
+	PreScore   *PluginSet `json:"preScore,omitempty"`
+	Score      *PluginSet `json:"score,omitempty"`
+	Reserve    *PluginSet `json:"reserve,omitempty"`
+	Permit     *PluginSet `json:"permit,omitempty"`
+	PreBind    *PluginSet `json:"preBind,omitempty"`
+	Bind       *PluginSet `json:"bind,omitempty"`
+	PostBind   *PluginSet `json:"postBind,omitempty"`
+	Unreserve  *PluginSet `json:"unreserve,omitempty"`
+}
+
+// PluginSet mirrors the plugins enabled and disabled at an extension point
+type PluginSet struct {
+	Enabled  []Plugin `json:"enabled,omitempty"`
+	Disabled []Plugin `json:"disabled,omitempty"`
+}
+
+// Plugin mirrors a scheduler plugin
+type Plugin struct {
+	Name   string `json:"name"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// PluginConfig mirrors the arguments of a scheduler plugin
+type PluginConfig struct {
+	Name string                `json:"name"`
+	Args *runtime.RawExtension `json:"args,omitempty"`
+}
+
+// Extender mirrors a scheduler extender
+type Extender struct {
+	URLPrefix        string                    `json:"urlPrefix"`
+	FilterVerb       string                    `json:"filterVerb,omitempty"`
+	PreemptVerb      string                    `json:"preemptVerb,omitempty"`
+	PrioritizeVerb   string                    `json:"prioritizeVerb,omitempty"`
+	Weight           int64                     `json:"weight,omitempty"`
+	BindVerb         string                    `json:"bindVerb,omitempty"`
+	EnableHTTPS      bool                      `json:"enableHTTPS,omitempty"`
+	TLSConfig        *ExtenderTLSConfig        `json:"tlsConfig,omitempty"`
+	HTTPTimeout      *metav1.Duration          `json:"httpTimeout,omitempty"`
+	NodeCacheCapable bool                      `json:"nodeCacheCapable,omitempty"`
+	ManagedResources []ExtenderManagedResource `json:"managedResources,omitempty"`
+	Ignorable        bool                      `json:"ignorable,omitempty"`
+}
+
+// ExtenderTLSConfig mirrors the TLS settings of a scheduler extender
+type ExtenderTLSConfig struct {
+	Insecure   bool   `json:"insecure,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
+	CertFile   string `json:"certFile,omitempty"`
+	KeyFile    string `json:"keyFile,omitempty"`
+	CAFile     string `json:"caFile,omitempty"`
+	CertData   []byte `json:"certData,omitempty"`
+	KeyData    []byte `json:"keyData,omitempty"`
+	CAData     []byte `json:"caData,omitempty"`
+}
+
+// ExtenderManagedResource mirrors an extended resource managed by a scheduler extender
+type ExtenderManagedResource struct {
+	Name               string `json:"name"`
+	IgnoredByScheduler bool   `json:"ignoredByScheduler,omitempty"`
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuremodel
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+type AzureModelContext struct {
+	*model.KopsModelContext
+}
+
+// NameForResourceGroup returns the name of the resource group the cluster resources are created in
+func (c *AzureModelContext) NameForResourceGroup() string {
+	return azureup.FindResourceGroupName(c.Cluster)
+}
+
+// LinkToResourceGroup returns the resource group the cluster resources are created in
+func (c *AzureModelContext) LinkToResourceGroup() *azuretasks.ResourceGroup {
+	return &azuretasks.ResourceGroup{Name: s(c.NameForResourceGroup())}
+}
+
+// LinkToVirtualNetwork returns the virtual network the cluster is located in
+func (c *AzureModelContext) LinkToVirtualNetwork() *azuretasks.VirtualNetwork {
+	return &azuretasks.VirtualNetwork{Name: s(azureup.VirtualNetworkName(c.Cluster))}
+}
+
+// LinkToSubnet returns the Azure subnet for a cluster subnet
+func (c *AzureModelContext) LinkToSubnet(subnet *kops.ClusterSubnetSpec) *azuretasks.Subnet {
+	return &azuretasks.Subnet{Name: s(azureup.SubnetName(c.Cluster, subnet))}
+}
+
+// LinkToNetworkSecurityGroup returns the network security group of the cluster subnets
+func (c *AzureModelContext) LinkToNetworkSecurityGroup() *azuretasks.NetworkSecurityGroup {
+	return &azuretasks.NetworkSecurityGroup{Name: s(azureup.NetworkSecurityGroupName(c.Cluster))}
+}
+
+// LinkToRouteTable returns the route table for pod routes
+func (c *AzureModelContext) LinkToRouteTable() *azuretasks.RouteTable {
+	return &azuretasks.RouteTable{Name: s(azureup.RouteTableName(c.Cluster))}
+}
+
+// NameForAPILoadBalancer returns the name of the load balancer in front of the API servers
+func (c *AzureModelContext) NameForAPILoadBalancer() string {
+	return "api." + c.ClusterName()
+}
+
+// LinkToAPILoadBalancer returns the load balancer in front of the API servers
+func (c *AzureModelContext) LinkToAPILoadBalancer() *azuretasks.LoadBalancer {
+	return &azuretasks.LoadBalancer{Name: s(c.NameForAPILoadBalancer())}
+}
+
+// NameForVMScaleSet returns the name of the VM scale set for an instance group
+func (c *AzureModelContext) NameForVMScaleSet(ig *kops.InstanceGroup) string {
+	return ig.ObjectMeta.Name + "." + c.ClusterName()
+}
+
+// CloudTagsForInstanceGroup computes the tags to apply to the VMs of an instance group.
+// Azure does not allow '/' in tag names, so we replace it with '_'.
+func (c *AzureModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (map[string]string, error) {
+	tags, err := c.KopsModelContext.CloudTagsForInstanceGroup(ig)
+	if err != nil {
+		return nil, err
+	}
+	return azureup.SafeTags(tags), nil
+}
+
+// subnetForInstanceGroup returns the single subnet of an instance group; a VM scale set can only be in one subnet
+func (c *AzureModelContext) subnetForInstanceGroup(ig *kops.InstanceGroup) (*kops.ClusterSubnetSpec, error) {
+	subnets, err := c.GatherSubnets(ig)
+	if err != nil {
+		return nil, err
+	}
+	if len(subnets) != 1 {
+		return nil, fmt.Errorf("instance group %q must have exactly one subnet on Azure, found %d", ig.ObjectMeta.Name, len(subnets))
+	}
+	return subnets[0], nil
+}
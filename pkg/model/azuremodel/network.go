@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuremodel
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+const (
+	// Each group of security rules gets a range of 100 priorities
+	sshRulePriority      = 1000
+	apiRulePriority      = 1100
+	nodePortRulePriority = 1200
+	maxRulesPerGroup     = 100
+)
+
+// NetworkModelBuilder configures the resource group, virtual network, subnets and network security group
+type NetworkModelBuilder struct {
+	*AzureModelContext
+	Lifecycle *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &NetworkModelBuilder{}
+
+func (b *NetworkModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	sharedResourceGroup := b.Cluster.Spec.CloudConfig != nil && b.Cluster.Spec.CloudConfig.Azure != nil && b.Cluster.Spec.CloudConfig.Azure.ResourceGroupName != ""
+	rg := &azuretasks.ResourceGroup{
+		Name:      s(b.NameForResourceGroup()),
+		Lifecycle: b.Lifecycle,
+		Tags:      map[string]string{},
+		Shared:    fi.Bool(sharedResourceGroup),
+	}
+	c.AddTask(rg)
+
+	sharedVirtualNetwork := b.Cluster.Spec.NetworkID != ""
+	vnet := &azuretasks.VirtualNetwork{
+		Name:          s(azureup.VirtualNetworkName(b.Cluster)),
+		Lifecycle:     b.Lifecycle,
+		ResourceGroup: rg,
+		Tags:          map[string]string{},
+		Shared:        fi.Bool(sharedVirtualNetwork),
+	}
+	if !sharedVirtualNetwork {
+		vnet.CIDR = s(b.Cluster.Spec.NetworkCIDR)
+	}
+	c.AddTask(vnet)
+
+	// The routes are maintained by the cloud provider in kube-controller-manager
+	routeTable := &azuretasks.RouteTable{
+		Name:          s(azureup.RouteTableName(b.Cluster)),
+		Lifecycle:     b.Lifecycle,
+		ResourceGroup: rg,
+		Tags:          map[string]string{},
+	}
+	c.AddTask(routeTable)
+
+	nsg := &azuretasks.NetworkSecurityGroup{
+		Name:          s(azureup.NetworkSecurityGroupName(b.Cluster)),
+		Lifecycle:     b.Lifecycle,
+		ResourceGroup: rg,
+		Tags:          map[string]string{},
+	}
+	// The default rules of a network security group allow traffic within the virtual network and from
+	// Azure load balancer probes, so we only need to open the ports exposed outside the cluster
+	{
+		rules, err := securityRules("ssh", sshRulePriority, b.Cluster.Spec.SSHAccess, "22")
+		if err != nil {
+			return err
+		}
+		nsg.SecurityRules = append(nsg.SecurityRules, rules...)
+	}
+	{
+		rules, err := securityRules("https-api", apiRulePriority, b.Cluster.Spec.KubernetesAPIAccess, "443")
+		if err != nil {
+			return err
+		}
+		nsg.SecurityRules = append(nsg.SecurityRules, rules...)
+	}
+	{
+		nodePortRange, err := b.NodePortRange()
+		if err != nil {
+			return err
+		}
+		portRange := fmt.Sprintf("%d-%d", nodePortRange.Base, nodePortRange.Base+nodePortRange.Size-1)
+		rules, err := securityRules("nodeport", nodePortRulePriority, b.Cluster.Spec.NodePortAccess, portRange)
+		if err != nil {
+			return err
+		}
+		nsg.SecurityRules = append(nsg.SecurityRules, rules...)
+	}
+	c.AddTask(nsg)
+
+	for i := range b.Cluster.Spec.Subnets {
+		subnetSpec := &b.Cluster.Spec.Subnets[i]
+
+		subnet := &azuretasks.Subnet{
+			Name:           s(azureup.SubnetName(b.Cluster, subnetSpec)),
+			Lifecycle:      b.Lifecycle,
+			ResourceGroup:  rg,
+			VirtualNetwork: vnet,
+			Shared:         fi.Bool(subnetSpec.ProviderID != ""),
+		}
+		if subnetSpec.ProviderID == "" {
+			subnet.CIDR = s(subnetSpec.CIDR)
+			subnet.NetworkSecurityGroup = nsg
+			subnet.RouteTable = routeTable
+		}
+		c.AddTask(subnet)
+	}
+
+	return nil
+}
+
+// securityRules builds the rules allowing inbound TCP traffic to a port range from a list of CIDRs
+func securityRules(name string, priority int32, cidrs []string, portRange string) ([]*azuretasks.NetworkSecurityRule, error) {
+	if len(cidrs) > maxRulesPerGroup {
+		return nil, fmt.Errorf("at most %d CIDRs are supported for %s access, found %d", maxRulesPerGroup, name, len(cidrs))
+	}
+
+	var rules []*azuretasks.NetworkSecurityRule
+	for i, cidr := range cidrs {
+		if cidr == "0.0.0.0/0" {
+			cidr = "*"
+		}
+		rules = append(rules, &azuretasks.NetworkSecurityRule{
+			Name:                     s(name + "-" + strconv.Itoa(i)),
+			Priority:                 i32(priority + int32(i)),
+			Direction:                s("Inbound"),
+			Access:                   s("Allow"),
+			Protocol:                 s("Tcp"),
+			SourceAddressPrefix:      s(cidr),
+			SourcePortRange:          s("*"),
+			DestinationAddressPrefix: s("VirtualNetwork"),
+			DestinationPortRange:     s(portRange),
+		})
+	}
+	return rules, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuremodel
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/pkg/model/defaults"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+const (
+	// DefaultAdminUser is the user created on the VMs, and who can log in with the cluster SSH key
+	DefaultAdminUser = "kops"
+
+	// Built-in Azure role definitions granted to the managed identities of the VMs
+	roleContributor           = "b24988ac-6180-42a0-ab88-20f7382dd24c"
+	roleReader                = "acdd72a7-3385-48ef-bd42-f606fba81ae7"
+	roleStorageBlobDataReader = "2a2b9908-6ea1-4ae2-8e65-a410df84e7d1"
+)
+
+// VMScaleSetModelBuilder configures a VM scale set for each instance group
+type VMScaleSetModelBuilder struct {
+	*AzureModelContext
+
+	BootstrapScript *model.BootstrapScript
+	Lifecycle       *fi.Lifecycle
+}
+
+var _ fi.ModelBuilder = &VMScaleSetModelBuilder{}
+
+func (b *VMScaleSetModelBuilder) Build(c *fi.ModelBuilderContext) error {
+	for _, ig := range b.InstanceGroups {
+		vmss, err := b.buildVMScaleSet(ig)
+		if err != nil {
+			return err
+		}
+		c.AddTask(vmss)
+
+		// The masters manage routes, load balancers and disks; the nodes only need to read their own configuration.
+		// Reading the state store requires the storage account to be in the cluster resource group.
+		roles := map[string]string{
+			"blob-reader": roleStorageBlobDataReader,
+		}
+		if ig.Spec.Role == kops.InstanceGroupRoleMaster {
+			roles["contributor"] = roleContributor
+		} else {
+			roles["reader"] = roleReader
+		}
+		for name, roleDefinitionID := range roles {
+			c.AddTask(&azuretasks.RoleAssignment{
+				Name:             s(fi.StringValue(vmss.Name) + "-" + name),
+				Lifecycle:        b.Lifecycle,
+				ResourceGroup:    b.LinkToResourceGroup(),
+				VMScaleSet:       vmss,
+				RoleDefinitionID: s(roleDefinitionID),
+			})
+		}
+	}
+	return nil
+}
+
+func (b *VMScaleSetModelBuilder) buildVMScaleSet(ig *kops.InstanceGroup) (*azuretasks.VMScaleSet, error) {
+	name := b.NameForVMScaleSet(ig)
+
+	subnet, err := b.subnetForInstanceGroup(ig)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := b.FindZonesForInstanceGroup(ig)
+	if err != nil {
+		return nil, err
+	}
+	var azs []string
+	for _, zone := range zones {
+		_, az, err := azureup.ParseZone(zone)
+		if err != nil {
+			return nil, err
+		}
+		if az != "" {
+			azs = append(azs, az)
+		}
+	}
+
+	// TODO: Duplicated from aws - move to defaults?
+	minSize := int32(1)
+	if ig.Spec.MinSize != nil {
+		minSize = fi.Int32Value(ig.Spec.MinSize)
+	} else if ig.Spec.Role == kops.InstanceGroupRoleNode {
+		minSize = 2
+	}
+
+	volumeSize := fi.Int32Value(ig.Spec.RootVolumeSize)
+	if volumeSize == 0 {
+		volumeSize, err = defaults.DefaultInstanceGroupVolumeSize(ig.Spec.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	adminUser := DefaultAdminUser
+	if b.Cluster.Spec.CloudConfig != nil && b.Cluster.Spec.CloudConfig.Azure != nil && b.Cluster.Spec.CloudConfig.Azure.AdminUser != "" {
+		adminUser = b.Cluster.Spec.CloudConfig.Azure.AdminUser
+	}
+
+	customData, err := b.BootstrapScript.ResourceNodeUp(ig, b.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := b.CloudTagsForInstanceGroup(ig)
+	if err != nil {
+		return nil, fmt.Errorf("error building cloud tags: %v", err)
+	}
+	tags[azureup.TagNameInstanceGroup] = ig.ObjectMeta.Name
+
+	t := &azuretasks.VMScaleSet{
+		Name:      s(name),
+		Lifecycle: b.Lifecycle,
+
+		ResourceGroup: b.LinkToResourceGroup(),
+		Tags:          tags,
+
+		Zones:    azs,
+		SKUName:  s(ig.Spec.MachineType),
+		Capacity: fi.Int64(int64(minSize)),
+
+		// Computer names are used as node names, and may not contain periods or underscores
+		ComputerNamePrefix: s(strings.NewReplacer(".", "-", "_", "-").Replace(ig.ObjectMeta.Name)),
+		AdminUser:          s(adminUser),
+		CustomData:         customData,
+
+		ImageURN:     s(ig.Spec.Image),
+		OSDiskSizeGB: i32(volumeSize),
+
+		Subnet: b.LinkToSubnet(subnet),
+	}
+
+	if len(b.SSHPublicKeys) > 0 {
+		t.SSHPublicKey = s(string(b.SSHPublicKeys[0]))
+	}
+
+	associatePublicIP := subnet.Type == kops.SubnetTypePublic || subnet.Type == kops.SubnetTypeUtility
+	if ig.Spec.AssociatePublicIP != nil {
+		associatePublicIP = fi.BoolValue(ig.Spec.AssociatePublicIP)
+	}
+
+	if ig.Spec.Role == kops.InstanceGroupRoleMaster && b.UseLoadBalancerForAPI() && b.Cluster.Spec.API.LoadBalancer != nil {
+		t.LoadBalancer = b.LinkToAPILoadBalancer()
+		// VMs behind a standard load balancer cannot have basic public IP addresses, which is what VM scale sets allocate,
+		// and they get outbound connectivity through the load balancer anyway
+		associatePublicIP = false
+	}
+	t.RequirePublicIPAddress = fi.Bool(associatePublicIP)
+
+	return t, nil
+}
@@ -67,6 +67,11 @@ func (b *BootstrapScript) buildEnvironmentVariables(cluster *kops.Cluster) (map[
 
 	if os.Getenv("S3_ENDPOINT") != "" {
 		env["S3_ENDPOINT"] = os.Getenv("S3_ENDPOINT")
+	}
+	if os.Getenv("S3_BUCKET_ENDPOINTS") != "" {
+		env["S3_BUCKET_ENDPOINTS"] = os.Getenv("S3_BUCKET_ENDPOINTS")
+	}
+	if os.Getenv("S3_ENDPOINT") != "" || os.Getenv("S3_BUCKET_ENDPOINTS") != "" {
 		env["S3_REGION"] = os.Getenv("S3_REGION")
 		env["S3_ACCESS_KEY_ID"] = os.Getenv("S3_ACCESS_KEY_ID")
 		env["S3_SECRET_ACCESS_KEY"] = os.Getenv("S3_SECRET_ACCESS_KEY")
@@ -123,6 +128,15 @@ func (b *BootstrapScript) buildEnvironmentVariables(cluster *kops.Cluster) (map[
 		}
 	}
 
+	// Azure VMs authenticate with their managed identity, so no credentials are passed
+	if kops.CloudProviderID(cluster.Spec.CloudProvider) == kops.CloudProviderAzure {
+		for _, envVar := range []string{"AZURE_ENVIRONMENT", "AZURE_SUBSCRIPTION_ID"} {
+			if os.Getenv(envVar) != "" {
+				env[envVar] = os.Getenv(envVar)
+			}
+		}
+	}
+
 	return env, nil
 }
 
@@ -134,6 +148,14 @@ func (b *BootstrapScript) ResourceNodeUp(ig *kops.InstanceGroup, cluster *kops.C
 		return nil, nil
 	}
 
+	if ig.Spec.BootstrapFormat == kops.BootstrapFormatIgnition {
+		return fi.WrapResource(&ignitionResource{
+			bootstrapScript: b,
+			ig:              ig,
+			cluster:         cluster,
+		}), nil
+	}
+
 	functions := template.FuncMap{
 		"NodeUpSource": func() string {
 			return b.NodeUpSource
@@ -162,112 +184,150 @@ func (b *BootstrapScript) ResourceNodeUp(ig *kops.InstanceGroup, cluster *kops.C
 		},
 
 		"ClusterSpec": func() (string, error) {
-			cs := cluster.Spec
+			return b.clusterSpec(ig, cluster)
+		},
 
-			spec := make(map[string]interface{})
-			spec["cloudConfig"] = cs.CloudConfig
-			spec["docker"] = cs.Docker
-			spec["kubeProxy"] = cs.KubeProxy
-			spec["kubelet"] = cs.Kubelet
+		"IGSpec": func() (string, error) {
+			return b.igSpec(ig)
+		},
+	}
 
-			if cs.NodeAuthorization != nil {
-				spec["nodeAuthorization"] = cs.NodeAuthorization
-			}
-			if cs.KubeAPIServer != nil && cs.KubeAPIServer.EnableBootstrapAuthToken != nil {
-				spec["kubeAPIServer"] = map[string]interface{}{
-					"enableBootstrapAuthToken": cs.KubeAPIServer.EnableBootstrapAuthToken,
-				}
-			}
+	awsNodeUpTemplate, err := resources.AWSNodeUpTemplate(ig)
+	if err != nil {
+		return nil, err
+	}
 
-			if ig.IsMaster() {
-				spec["encryptionConfig"] = cs.EncryptionConfig
-				spec["etcdClusters"] = make(map[string]kops.EtcdClusterSpec, 0)
-				spec["kubeAPIServer"] = cs.KubeAPIServer
-				spec["kubeControllerManager"] = cs.KubeControllerManager
-				spec["kubeScheduler"] = cs.KubeScheduler
-				spec["masterKubelet"] = cs.MasterKubelet
-
-				for _, etcdCluster := range cs.EtcdClusters {
-					c := kops.EtcdClusterSpec{
-						Image:   etcdCluster.Image,
-						Version: etcdCluster.Version,
-					}
-					// if the user has not specified memory or cpu allotments for etcd, do not
-					// apply one.  Described in PR #6313.
-					if etcdCluster.CPURequest != nil {
-						c.CPURequest = etcdCluster.CPURequest
-					}
-					if etcdCluster.MemoryRequest != nil {
-						c.MemoryRequest = etcdCluster.MemoryRequest
-					}
-					spec["etcdClusters"].(map[string]kops.EtcdClusterSpec)[etcdCluster.Name] = c
-				}
-			}
+	templateResource, err := NewTemplateResource("nodeup", awsNodeUpTemplate, functions, nil)
+	if err != nil {
+		return nil, err
+	}
 
-			hooks, err := b.getRelevantHooks(cs.Hooks, ig.Spec.Role)
-			if err != nil {
-				return "", err
-			}
-			if len(hooks) > 0 {
-				spec["hooks"] = hooks
-			}
+	return fi.WrapResource(templateResource), nil
+}
 
-			fileAssets, err := b.getRelevantFileAssets(cs.FileAssets, ig.Spec.Role)
-			if err != nil {
-				return "", err
-			}
-			if len(fileAssets) > 0 {
-				spec["fileAssets"] = fileAssets
-			}
+// clusterSpec returns the parts of the cluster spec that are relevant to the instance group, so that changing them
+// changes the user-data and replaces the instances
+func (b *BootstrapScript) clusterSpec(ig *kops.InstanceGroup, cluster *kops.Cluster) (string, error) {
+	cs := cluster.Spec
 
-			content, err := yaml.Marshal(spec)
-			if err != nil {
-				return "", fmt.Errorf("error converting cluster spec to yaml for inclusion within bootstrap script: %v", err)
-			}
-			return string(content), nil
-		},
+	spec := make(map[string]interface{})
+	spec["cloudConfig"] = cs.CloudConfig
+	spec["docker"] = cs.Docker
+	spec["kubeProxy"] = cs.KubeProxy
+	spec["kubelet"] = cs.Kubelet
 
-		"IGSpec": func() (string, error) {
-			spec := make(map[string]interface{})
-			spec["kubelet"] = ig.Spec.Kubelet
-			spec["nodeLabels"] = ig.Spec.NodeLabels
-			spec["taints"] = ig.Spec.Taints
+	if cs.NodeAuthorization != nil {
+		spec["nodeAuthorization"] = cs.NodeAuthorization
+	}
+	if cs.NodeHardening != nil {
+		spec["nodeHardening"] = cs.NodeHardening
+	}
+	if len(cs.SysctlParameters) != 0 {
+		spec["sysctlParameters"] = cs.SysctlParameters
+	}
+	if len(cs.KernelModules) != 0 {
+		spec["kernelModules"] = cs.KernelModules
+	}
+	if len(cs.SystemdUnits) != 0 {
+		spec["systemdUnits"] = cs.SystemdUnits
+	}
+	if cs.KubeAPIServer != nil && cs.KubeAPIServer.EnableBootstrapAuthToken != nil {
+		spec["kubeAPIServer"] = map[string]interface{}{
+			"enableBootstrapAuthToken": cs.KubeAPIServer.EnableBootstrapAuthToken,
+		}
+	}
 
-			hooks, err := b.getRelevantHooks(ig.Spec.Hooks, ig.Spec.Role)
-			if err != nil {
-				return "", err
-			}
-			if len(hooks) > 0 {
-				spec["hooks"] = hooks
+	if ig.IsMaster() {
+		spec["encryptionConfig"] = cs.EncryptionConfig
+		if cs.EncryptionAtRest != nil {
+			spec["encryptionAtRest"] = cs.EncryptionAtRest
+		}
+		spec["etcdClusters"] = make(map[string]kops.EtcdClusterSpec, 0)
+		spec["kubeAPIServer"] = cs.KubeAPIServer
+		spec["kubeControllerManager"] = cs.KubeControllerManager
+		spec["kubeScheduler"] = cs.KubeScheduler
+		spec["masterKubelet"] = cs.MasterKubelet
+
+		for _, etcdCluster := range cs.EtcdClusters {
+			c := kops.EtcdClusterSpec{
+				Image:   etcdCluster.Image,
+				Version: etcdCluster.Version,
 			}
-
-			fileAssets, err := b.getRelevantFileAssets(ig.Spec.FileAssets, ig.Spec.Role)
-			if err != nil {
-				return "", err
+			// if the user has not specified memory or cpu allotments for etcd, do not
+			// apply one.  Described in PR #6313.
+			if etcdCluster.CPURequest != nil {
+				c.CPURequest = etcdCluster.CPURequest
 			}
-			if len(fileAssets) > 0 {
-				spec["fileAssets"] = fileAssets
+			if etcdCluster.MemoryRequest != nil {
+				c.MemoryRequest = etcdCluster.MemoryRequest
 			}
+			spec["etcdClusters"].(map[string]kops.EtcdClusterSpec)[etcdCluster.Name] = c
+		}
+	}
 
-			content, err := yaml.Marshal(spec)
-			if err != nil {
-				return "", fmt.Errorf("error converting instancegroup spec to yaml for inclusion within bootstrap script: %v", err)
-			}
-			return string(content), nil
-		},
+	hooks, err := b.getRelevantHooks(cs.Hooks, ig.Spec.Role)
+	if err != nil {
+		return "", err
+	}
+	if len(hooks) > 0 {
+		spec["hooks"] = hooks
 	}
 
-	awsNodeUpTemplate, err := resources.AWSNodeUpTemplate(ig)
+	fileAssets, err := b.getRelevantFileAssets(cs.FileAssets, ig.Spec.Role)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if len(fileAssets) > 0 {
+		spec["fileAssets"] = fileAssets
 	}
 
-	templateResource, err := NewTemplateResource("nodeup", awsNodeUpTemplate, functions, nil)
+	content, err := yaml.Marshal(spec)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("error converting cluster spec to yaml for inclusion within bootstrap script: %v", err)
+	}
+	return string(content), nil
+}
+
+// igSpec returns the parts of the instance group spec that are relevant to nodeup
+func (b *BootstrapScript) igSpec(ig *kops.InstanceGroup) (string, error) {
+	spec := make(map[string]interface{})
+	spec["kubelet"] = ig.Spec.Kubelet
+	spec["nodeLabels"] = ig.Spec.NodeLabels
+	spec["taints"] = ig.Spec.Taints
+	if ig.Spec.NodeHardening != nil {
+		spec["nodeHardening"] = ig.Spec.NodeHardening
+	}
+	if len(ig.Spec.SysctlParameters) != 0 {
+		spec["sysctlParameters"] = ig.Spec.SysctlParameters
+	}
+	if len(ig.Spec.KernelModules) != 0 {
+		spec["kernelModules"] = ig.Spec.KernelModules
+	}
+	if len(ig.Spec.SystemdUnits) != 0 {
+		spec["systemdUnits"] = ig.Spec.SystemdUnits
 	}
 
-	return fi.WrapResource(templateResource), nil
+	hooks, err := b.getRelevantHooks(ig.Spec.Hooks, ig.Spec.Role)
+	if err != nil {
+		return "", err
+	}
+	if len(hooks) > 0 {
+		spec["hooks"] = hooks
+	}
+
+	fileAssets, err := b.getRelevantFileAssets(ig.Spec.FileAssets, ig.Spec.Role)
+	if err != nil {
+		return "", err
+	}
+	if len(fileAssets) > 0 {
+		spec["fileAssets"] = fileAssets
+	}
+
+	content, err := yaml.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("error converting instancegroup spec to yaml for inclusion within bootstrap script: %v", err)
+	}
+	return string(content), nil
 }
 
 // getRelevantHooks returns a list of hooks to be applied to the instance group,
@@ -371,19 +431,7 @@ func (b *BootstrapScript) createProxyEnv(ps *kops.EgressProxySpec) string {
 	var buffer bytes.Buffer
 
 	if ps != nil && ps.HTTPProxy.Host != "" {
-		var httpProxyURL string
-
-		// TODO double check that all the code does this
-		// TODO move this into a validate so we can enforce the string syntax
-		if !strings.HasPrefix(ps.HTTPProxy.Host, "http://") {
-			httpProxyURL = "http://"
-		}
-
-		if ps.HTTPProxy.Port != 0 {
-			httpProxyURL += ps.HTTPProxy.Host + ":" + strconv.Itoa(ps.HTTPProxy.Port)
-		} else {
-			httpProxyURL += ps.HTTPProxy.Host
-		}
+		httpProxyURL := b.proxyURL(ps)
 
 		// Set env variables for base environment
 		buffer.WriteString(`echo "http_proxy=` + httpProxyURL + `" >> /etc/environment` + "\n")
@@ -416,3 +464,22 @@ func (b *BootstrapScript) createProxyEnv(ps *kops.EgressProxySpec) string {
 	}
 	return buffer.String()
 }
+
+// proxyURL returns the URL of the egress proxy
+func (b *BootstrapScript) proxyURL(ps *kops.EgressProxySpec) string {
+	var httpProxyURL string
+
+	// TODO double check that all the code does this
+	// TODO move this into a validate so we can enforce the string syntax
+	if !strings.HasPrefix(ps.HTTPProxy.Host, "http://") {
+		httpProxyURL = "http://"
+	}
+
+	if ps.HTTPProxy.Port != 0 {
+		httpProxyURL += ps.HTTPProxy.Host + ":" + strconv.Itoa(ps.HTTPProxy.Port)
+	} else {
+		httpProxyURL += ps.HTTPProxy.Host
+	}
+
+	return httpProxyURL
+}
@@ -145,6 +145,78 @@ func TestBootstrapUserData(t *testing.T) {
 	}
 }
 
+func TestBootstrapIgnition(t *testing.T) {
+	cs := []struct {
+		Role               kops.InstanceGroupRole
+		ExpectedFilePath   string
+		AdditionalUserData []kops.UserData
+	}{
+		{
+			Role:             "Master",
+			ExpectedFilePath: "tests/data/bootstrapignition_0.json",
+		},
+		{
+			Role:             "Node",
+			ExpectedFilePath: "tests/data/bootstrapignition_1.json",
+			AdditionalUserData: []kops.UserData{
+				{
+					Name:    "myscript.sh",
+					Type:    "text/x-shellscript",
+					Content: "#!/bin/sh\necho 'hello world'\n",
+				},
+				{
+					Name:    "extra.ign",
+					Type:    kops.UserDataTypeIgnition,
+					Content: `{"ignition":{"version":"2.2.0"}}`,
+				},
+			},
+		},
+		{
+			Role:             "Bastion",
+			ExpectedFilePath: "tests/data/bootstrapignition_2.json",
+			AdditionalUserData: []kops.UserData{
+				{
+					Name:    "myscript.sh",
+					Type:    "text/x-shellscript",
+					Content: "#!/bin/sh\necho 'hello world'\n",
+				},
+			},
+		},
+	}
+
+	for i, x := range cs {
+		roles := []kops.InstanceGroupRole{""}
+		cluster := makeTestCluster(roles, roles)
+		group := makeTestInstanceGroup(x.Role, roles, roles)
+		group.Spec.BootstrapFormat = kops.BootstrapFormatIgnition
+		group.Spec.AdditionalUserData = x.AdditionalUserData
+
+		renderNodeUpConfig := func(ig *kops.InstanceGroup) (*nodeup.Config, error) {
+			return &nodeup.Config{}, nil
+		}
+
+		bs := &BootstrapScript{
+			NodeUpSource:        "NUSource",
+			NodeUpSourceHash:    "NUSHash",
+			NodeUpConfigBuilder: renderNodeUpConfig,
+		}
+
+		res, err := bs.ResourceNodeUp(group, cluster)
+		if err != nil {
+			t.Errorf("case %d failed to create nodeup resource. error: %s", i, err)
+			continue
+		}
+
+		actual, err := res.AsString()
+		if err != nil {
+			t.Errorf("case %d failed to render nodeup resource. error: %s", i, err)
+			continue
+		}
+
+		testutils.AssertMatchesFile(t, actual, x.ExpectedFilePath)
+	}
+}
+
 func makeTestCluster(hookSpecRoles []kops.InstanceGroupRole, fileAssetSpecRoles []kops.InstanceGroupRole) *kops.Cluster {
 	return &kops.Cluster{
 		Spec: kops.ClusterSpec{
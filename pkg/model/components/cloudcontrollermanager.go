@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// CloudControllerManagerOptionsBuilder adds options for the out-of-tree cloud-controller-manager addon
+type CloudControllerManagerOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &CloudControllerManagerOptionsBuilder{}
+
+// BuildOptions fills in the defaults of the cloud-controller-manager, for the cloudproviders where kops ships one.
+// It runs after the KubeControllerManagerOptionsBuilder, because the route controller moves from the
+// kube-controller-manager to the cloud-controller-manager, and so do its settings.
+func (b *CloudControllerManagerOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	ccm := clusterSpec.ExternalCloudControllerManager
+	if ccm == nil {
+		return nil
+	}
+
+	if kops.CloudProviderID(clusterSpec.CloudProvider) != kops.CloudProviderAWS {
+		// The other cloudproviders expect the image and flags to be specified in full
+		return nil
+	}
+
+	kcm := clusterSpec.KubeControllerManager
+	if kcm == nil {
+		return fmt.Errorf("KubeControllerManager not set")
+	}
+
+	if ccm.CloudProvider == "" {
+		ccm.CloudProvider = "aws"
+	}
+	if ccm.ClusterName == "" {
+		ccm.ClusterName = b.ClusterName
+	}
+	if ccm.ClusterCIDR == "" {
+		ccm.ClusterCIDR = kcm.ClusterCIDR
+	}
+	if ccm.AllocateNodeCIDRs == nil {
+		ccm.AllocateNodeCIDRs = kcm.AllocateNodeCIDRs
+	}
+	if ccm.ConfigureCloudRoutes == nil {
+		ccm.ConfigureCloudRoutes = kcm.ConfigureCloudRoutes
+	}
+	if ccm.LeaderElection == nil {
+		ccm.LeaderElection = &kops.LeaderElectionConfiguration{LeaderElect: fi.Bool(true)}
+	}
+	if ccm.UseServiceAccountCredentials == nil {
+		ccm.UseServiceAccountCredentials = fi.Bool(true)
+	}
+	if ccm.LogLevel == 0 {
+		ccm.LogLevel = 2
+	}
+	if ccm.Image == "" {
+		ccm.Image = b.awsCloudControllerManagerImage()
+	}
+
+	return nil
+}
+
+// awsCloudControllerManagerImage returns the release of the AWS cloud-controller-manager for the version of kubernetes
+func (b *CloudControllerManagerOptionsBuilder) awsCloudControllerManagerImage() string {
+	switch {
+	case b.IsKubernetesGTE("1.15"):
+		return "gcr.io/k8s-staging-provider-aws/cloud-controller-manager:v1.15.0"
+	default:
+		return "gcr.io/k8s-staging-provider-aws/cloud-controller-manager:v1.14.0"
+	}
+}
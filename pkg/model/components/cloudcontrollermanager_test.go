@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/flagbuilder"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_Build_CloudControllerManager_AWS(t *testing.T) {
+	c := buildCluster()
+	c.Spec.KubernetesVersion = "v1.15.3"
+	c.Spec.ExternalCloudControllerManager = &api.CloudControllerManagerConfig{}
+	c.Spec.KubeControllerManager = &api.KubeControllerManagerConfig{
+		ClusterCIDR:          "100.96.0.0/11",
+		AllocateNodeCIDRs:    fi.Bool(true),
+		ConfigureCloudRoutes: fi.Bool(true),
+	}
+
+	b := &CloudControllerManagerOptionsBuilder{
+		OptionsContext: &OptionsContext{
+			ClusterName:       "example.k8s.local",
+			KubernetesVersion: semver.MustParse("1.15.3"),
+		},
+	}
+	if err := b.BuildOptions(&c.Spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	ccm := c.Spec.ExternalCloudControllerManager
+	if ccm.Image != "gcr.io/k8s-staging-provider-aws/cloud-controller-manager:v1.15.0" {
+		t.Errorf("unexpected image %q", ccm.Image)
+	}
+
+	flags, err := flagbuilder.BuildFlagsList(ccm)
+	if err != nil {
+		t.Fatalf("unexpected error building flags: %v", err)
+	}
+	expected := []string{
+		"--allocate-node-cidrs=true",
+		"--cloud-provider=aws",
+		"--cluster-cidr=100.96.0.0/11",
+		"--cluster-name=example.k8s.local",
+		"--configure-cloud-routes=true",
+		"--leader-elect=true",
+		"--use-service-account-credentials=true",
+		"--v=2",
+	}
+	if !reflect.DeepEqual(flags, expected) {
+		t.Errorf("unexpected flags\nexpected %v\ngot      %v", expected, flags)
+	}
+}
+
+func Test_Build_CloudControllerManager_OtherCloud(t *testing.T) {
+	c := buildCluster()
+	c.Spec.CloudProvider = "openstack"
+	c.Spec.ExternalCloudControllerManager = &api.CloudControllerManagerConfig{
+		Image: "docker.io/k8scloudprovider/openstack-cloud-controller-manager:v1.15.0",
+	}
+
+	b := &CloudControllerManagerOptionsBuilder{OptionsContext: &OptionsContext{}}
+	if err := b.BuildOptions(&c.Spec); err != nil {
+		t.Fatalf("unexpected error from BuildOptions: %v", err)
+	}
+
+	if c.Spec.ExternalCloudControllerManager.CloudProvider != "" || c.Spec.ExternalCloudControllerManager.LeaderElection != nil {
+		t.Errorf("expected the cloud-controller-manager of other cloudproviders to be left as specified, got %+v", c.Spec.ExternalCloudControllerManager)
+	}
+}
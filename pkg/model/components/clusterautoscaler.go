@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// ClusterAutoscalerOptionsBuilder adds options for the cluster-autoscaler addon
+type ClusterAutoscalerOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &ClusterAutoscalerOptionsBuilder{}
+
+// BuildOptions fills in the defaults of the cluster-autoscaler, if it is enabled
+func (b *ClusterAutoscalerOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	config := clusterSpec.ClusterAutoscaler
+	if config == nil || !fi.BoolValue(config.Enabled) {
+		return nil
+	}
+
+	if config.Image == nil {
+		config.Image = fi.String(b.clusterAutoscalerImage())
+	}
+	if config.Expander == nil {
+		config.Expander = fi.String("random")
+	}
+	if config.BalanceSimilarNodeGroups == nil {
+		config.BalanceSimilarNodeGroups = fi.Bool(false)
+	}
+	if config.ScaleDownEnabled == nil {
+		config.ScaleDownEnabled = fi.Bool(true)
+	}
+	if config.ScaleDownUtilizationThreshold == nil {
+		config.ScaleDownUtilizationThreshold = fi.String("0.5")
+	}
+	if config.ScaleDownUnneededTime == nil {
+		config.ScaleDownUnneededTime = &metav1.Duration{Duration: 10 * time.Minute}
+	}
+	if config.ScaleDownDelayAfterAdd == nil {
+		config.ScaleDownDelayAfterAdd = &metav1.Duration{Duration: 10 * time.Minute}
+	}
+	if config.SkipNodesWithLocalStorage == nil {
+		config.SkipNodesWithLocalStorage = fi.Bool(true)
+	}
+	if config.SkipNodesWithSystemPods == nil {
+		config.SkipNodesWithSystemPods = fi.Bool(true)
+	}
+	if config.MemoryRequest == nil || config.MemoryRequest.IsZero() {
+		defaultMemoryRequest := resource.MustParse("300Mi")
+		config.MemoryRequest = &defaultMemoryRequest
+	}
+	if config.CPURequest == nil || config.CPURequest.IsZero() {
+		defaultCPURequest := resource.MustParse("100m")
+		config.CPURequest = &defaultCPURequest
+	}
+
+	return nil
+}
+
+// clusterAutoscalerImage returns the cluster-autoscaler release that supports the version of kubernetes.
+// Validation requires kubernetes 1.12 or later when the cluster autoscaler is enabled.
+func (b *ClusterAutoscalerOptionsBuilder) clusterAutoscalerImage() string {
+	switch {
+	case b.IsKubernetesGTE("1.15"):
+		return "k8s.gcr.io/cluster-autoscaler:v1.15.1"
+	case b.IsKubernetesGTE("1.14"):
+		return "k8s.gcr.io/cluster-autoscaler:v1.14.5"
+	case b.IsKubernetesGTE("1.13"):
+		return "k8s.gcr.io/cluster-autoscaler:v1.13.7"
+	default:
+		return "k8s.gcr.io/cluster-autoscaler:v1.12.8"
+	}
+}
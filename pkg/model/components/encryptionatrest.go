@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/encryptionatrest"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// EncryptionAtRestOptionsBuilder adds options for encryption at rest
+type EncryptionAtRestOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &EncryptionAtRestOptionsBuilder{}
+
+// BuildOptions fills in the defaults of encryption at rest, if it is enabled
+func (b *EncryptionAtRestOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	if clusterSpec.EncryptionAtRest == nil {
+		return nil
+	}
+	e := clusterSpec.EncryptionAtRest
+
+	if e.Provider == "" {
+		e.Provider = encryptionatrest.ProviderAESCBC
+	}
+
+	if len(e.Resources) == 0 {
+		e.Resources = []string{"secrets"}
+	}
+
+	if e.KMS != nil && e.KMS.CacheSize == nil {
+		e.KMS.CacheSize = fi.Int32(1000)
+	}
+
+	return nil
+}
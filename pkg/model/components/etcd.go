@@ -50,7 +50,10 @@ func (b *EtcdOptionsBuilder) BuildOptions(o interface{}) error {
 
 	for _, c := range spec.EtcdClusters {
 		if c.Provider == "" {
-			if b.IsKubernetesGTE("1.12") {
+			if kops.CloudProviderID(spec.CloudProvider) == kops.CloudProviderAzure {
+				// etcd-manager cannot find volumes on Azure; protokube mounts the etcd disks instead
+				c.Provider = kops.EtcdProviderTypeLegacy
+			} else if b.IsKubernetesGTE("1.12") {
 				c.Provider = kops.EtcdProviderTypeManager
 			} else if c.Manager != nil {
 				c.Provider = kops.EtcdProviderTypeManager
@@ -215,6 +215,10 @@ func (b *KubeletOptionsBuilder) BuildOptions(o interface{}) error {
 		clusterSpec.Kubelet.CloudProvider = "alicloud"
 	}
 
+	if cloudProvider == kops.CloudProviderAzure {
+		clusterSpec.Kubelet.CloudProvider = "azure"
+	}
+
 	if clusterSpec.ExternalCloudControllerManager != nil {
 		clusterSpec.Kubelet.CloudProvider = "external"
 	}
@@ -0,0 +1,45 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package components
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/loader"
+)
+
+// StorageOptionsBuilder adds options for the storage addons
+type StorageOptionsBuilder struct {
+	*OptionsContext
+}
+
+var _ loader.OptionsBuilder = &StorageOptionsBuilder{}
+
+// BuildOptions fills in the defaults of the CSI driver, if it is enabled
+func (b *StorageOptionsBuilder) BuildOptions(o interface{}) error {
+	clusterSpec := o.(*kops.ClusterSpec)
+	if clusterSpec.Storage == nil || clusterSpec.Storage.CSI == nil || !fi.BoolValue(clusterSpec.Storage.CSI.Enabled) {
+		return nil
+	}
+	csi := clusterSpec.Storage.CSI
+
+	if csi.Version == nil && kops.CloudProviderID(clusterSpec.CloudProvider) == kops.CloudProviderAWS {
+		csi.Version = fi.String("v0.4.0")
+	}
+
+	return nil
+}
@@ -40,6 +40,11 @@ import (
 const (
 	clusterAutoscalerNodeTemplateLabel = "k8s.io/cluster-autoscaler/node-template/label/"
 	clusterAutoscalerNodeTemplateTaint = "k8s.io/cluster-autoscaler/node-template/taint/"
+
+	// clusterAutoscalerAutoDiscoveryEnabled and clusterAutoscalerAutoDiscoveryPrefix are the tags used by
+	// the cluster-autoscaler --node-group-auto-discovery flag, to find the node groups of a cluster
+	clusterAutoscalerAutoDiscoveryEnabled = "k8s.io/cluster-autoscaler/enabled"
+	clusterAutoscalerAutoDiscoveryPrefix  = "k8s.io/cluster-autoscaler/"
 )
 
 var UseLegacyELBName = featureflag.New("UseLegacyELBName", featureflag.Bool(false))
@@ -180,6 +185,20 @@ func (m *KopsModelContext) NodeInstanceGroups() []*kops.InstanceGroup {
 	return groups
 }
 
+// ClusterAutoscalerEnabled returns true if the cluster-autoscaler addon is enabled
+func (m *KopsModelContext) ClusterAutoscalerEnabled() bool {
+	return m.Cluster.Spec.ClusterAutoscaler != nil && fi.BoolValue(m.Cluster.Spec.ClusterAutoscaler.Enabled)
+}
+
+// IsAutoscaledInstanceGroup returns true if the cluster-autoscaler manages the size of the InstanceGroup,
+// which it does for node InstanceGroups whose MinSize and MaxSize differ
+func (m *KopsModelContext) IsAutoscaledInstanceGroup(ig *kops.InstanceGroup) bool {
+	if !m.ClusterAutoscalerEnabled() || ig.Spec.Role != kops.InstanceGroupRoleNode {
+		return false
+	}
+	return fi.Int32Value(ig.Spec.MinSize) != fi.Int32Value(ig.Spec.MaxSize)
+}
+
 // CloudTagsForInstanceGroup computes the tags to apply to instances in the specified InstanceGroup
 func (m *KopsModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (map[string]string, error) {
 	labels := make(map[string]string)
@@ -221,6 +240,11 @@ func (m *KopsModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (ma
 		labels[awstasks.CloudTagInstanceGroupRolePrefix+strings.ToLower(string(kops.InstanceGroupRoleBastion))] = "1"
 	}
 
+	if m.IsAutoscaledInstanceGroup(ig) {
+		labels[clusterAutoscalerAutoDiscoveryEnabled] = "true"
+		labels[clusterAutoscalerAutoDiscoveryPrefix+m.ClusterName()] = "owned"
+	}
+
 	return labels, nil
 }
 
@@ -96,6 +96,12 @@ func (b *AutoscalingGroupModelBuilder) Build(c *fi.ModelBuilderContext) error {
 				},
 			}
 
+			// Ignition reads its config from the user-data metadata key
+			if ig.Spec.BootstrapFormat == kops.BootstrapFormatIgnition {
+				delete(t.Metadata, "startup-script")
+				t.Metadata["user-data"] = startupScript
+			}
+
 			storagePaths, err := iam.WriteableVFSPaths(b.Cluster, ig.Spec.Role)
 			if err != nil {
 				return err
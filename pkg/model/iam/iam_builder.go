@@ -38,6 +38,7 @@ import (
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/util/stringorslice"
+	"k8s.io/kops/pkg/vault"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 	"k8s.io/kops/util/pkg/vfs"
@@ -168,6 +169,16 @@ func (b *PolicyBuilder) BuildAWSPolicyMaster() (*Policy, error) {
 		addKMSIAMPolicies(p, stringorslice.Slice(b.KMSKeys), b.Cluster.Spec.IAM.Legacy)
 	}
 
+	for _, key := range secretEncryptionKeys(b.Cluster.Spec.SecretEncryption) {
+		if key.AWSKMS != nil {
+			addSecretEncryptionKMSPolicies(p, key.AWSKMS, b.IAMPrefix())
+		}
+	}
+
+	if b.Cluster.Spec.EncryptionAtRest != nil && b.Cluster.Spec.EncryptionAtRest.KMS != nil {
+		addEncryptionAtRestKMSPolicies(p, b.Cluster.Spec.EncryptionAtRest.KMS)
+	}
+
 	if b.HostedZoneID != "" {
 		addRoute53Permissions(p, b.HostedZoneID)
 	}
@@ -180,6 +191,14 @@ func (b *PolicyBuilder) BuildAWSPolicyMaster() (*Policy, error) {
 		addECRPermissions(p)
 	}
 
+	if b.Cluster.Spec.ClusterAutoscaler != nil && fi.BoolValue(b.Cluster.Spec.ClusterAutoscaler.Enabled) {
+		addClusterAutoscalerPermissions(p, resource, b.Cluster.Spec.IAM.Legacy)
+	}
+
+	if b.Cluster.Spec.Storage != nil && b.Cluster.Spec.Storage.CSI != nil && fi.BoolValue(b.Cluster.Spec.Storage.CSI.Enabled) {
+		addEBSCSIDriverPermissions(p, resource, b.Cluster.Spec.IAM.Legacy)
+	}
+
 	if b.Cluster.Spec.Networking != nil && b.Cluster.Spec.Networking.Romana != nil {
 		addRomanaCNIPermissions(p, resource, b.Cluster.Spec.IAM.Legacy, b.Cluster.GetName())
 	}
@@ -210,6 +229,12 @@ func (b *PolicyBuilder) BuildAWSPolicyNode() (*Policy, error) {
 		return nil, fmt.Errorf("failed to generate AWS IAM S3 access statements: %v", err)
 	}
 
+	for _, key := range secretEncryptionKeys(b.Cluster.Spec.SecretEncryption) {
+		if key.AWSKMS != nil {
+			addSecretEncryptionKMSPolicies(p, key.AWSKMS, b.IAMPrefix())
+		}
+	}
+
 	if b.Cluster.Spec.IAM.Legacy {
 		if b.HostedZoneID != "" {
 			addRoute53Permissions(p, b.HostedZoneID)
@@ -280,7 +305,7 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) (*Policy, error) {
 			b.Cluster.Spec.SecretStore,
 			b.Cluster.Spec.ConfigStore,
 		} {
-			if p == "" {
+			if p == "" || vault.IsVaultPath(p) {
 				continue
 			}
 
@@ -591,6 +616,45 @@ func addKMSIAMPolicies(p *Policy, resource stringorslice.StringOrSlice, legacyIA
 	})
 }
 
+// secretEncryptionKeys returns the current and previous secret encryption keys; secrets are read with either
+func secretEncryptionKeys(spec *kops.SecretEncryptionSpec) []kops.SecretEncryptionSpec {
+	if spec == nil {
+		return nil
+	}
+	return append([]kops.SecretEncryptionSpec{*spec}, spec.PreviousKeys...)
+}
+
+// addSecretEncryptionKMSPolicies allows nodeup to decrypt the secrets and keys it reads from the state store
+func addSecretEncryptionKMSPolicies(p *Policy, spec *kops.AWSKMSSecretEncryptionSpec, iamPrefix string) {
+	// Decrypt is authorized against the key, so aliases are widened to all keys in the region
+	resource := spec.KeyID
+	if !strings.HasPrefix(resource, "arn:") {
+		if strings.HasPrefix(resource, "alias/") {
+			resource = iamPrefix + ":kms:" + spec.Region + ":*:key/*"
+		} else {
+			resource = iamPrefix + ":kms:" + spec.Region + ":*:key/" + resource
+		}
+	} else if strings.Contains(resource, ":alias/") {
+		tokens := strings.Split(resource, ":")
+		resource = strings.Join(tokens[:4], ":") + ":*:key/*"
+	}
+
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Slice([]string{"kms:Decrypt"}),
+		Resource: stringorslice.Slice([]string{resource}),
+	})
+}
+
+// addEncryptionAtRestKMSPolicies allows the KMS plugin on the masters to wrap and unwrap the data encryption keys of the kube-apiserver
+func addEncryptionAtRestKMSPolicies(p *Policy, spec *kops.KMSEncryptionAtRestSpec) {
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Of("kms:Decrypt", "kms:DescribeKey", "kms:Encrypt"),
+		Resource: stringorslice.Slice([]string{spec.KeyID}),
+	})
+}
+
 func addNodeEC2Policies(p *Policy, resource stringorslice.StringOrSlice) {
 	// Protokube makes a DescribeInstances call, DescribeRegions when finding S3 State Bucket
 	p.Statement = append(p.Statement, &Statement{
@@ -775,6 +839,39 @@ func addMasterASPolicies(p *Policy, resource stringorslice.StringOrSlice, legacy
 	}
 }
 
+// addClusterAutoscalerPermissions adds the permissions the cluster-autoscaler needs beyond those in addMasterASPolicies
+func addClusterAutoscalerPermissions(p *Policy, resource stringorslice.StringOrSlice, legacyIAM bool) {
+	if legacyIAM {
+		// The legacy policy already allows everything the cluster-autoscaler needs
+		return
+	}
+	p.Statement = append(p.Statement, &Statement{
+		Effect: StatementEffectAllow,
+		Action: stringorslice.Of(
+			"autoscaling:DescribeAutoScalingInstances", // aws_manager.go
+		),
+		Resource: resource,
+	})
+}
+
+// addEBSCSIDriverPermissions adds the permissions the EBS CSI controller needs beyond those in addMasterEC2Policies.
+// The driver tags the volumes it creates with KubernetesCluster, so the existing conditions on attaching,
+// detaching and deleting volumes also apply to them.
+func addEBSCSIDriverPermissions(p *Policy, resource stringorslice.StringOrSlice, legacyIAM bool) {
+	if legacyIAM {
+		// The legacy policy already allows everything the EBS CSI driver needs
+		return
+	}
+	p.Statement = append(p.Statement, &Statement{
+		Effect: StatementEffectAllow,
+		Action: stringorslice.Of(
+			"ec2:DescribeAvailabilityZones", // cloud.go
+			"ec2:DescribeSnapshots",         // cloud.go
+		),
+		Resource: resource,
+	})
+}
+
 func addCertIAMPolicies(p *Policy, resource stringorslice.StringOrSlice) {
 	// TODO: Make optional only if using IAM SSL Certs on ELBs
 	p.Statement = append(p.Statement, &Statement{
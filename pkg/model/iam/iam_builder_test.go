@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/pkg/util/stringorslice"
+	"k8s.io/kops/upup/pkg/fi"
 )
 
 func TestRoundTrip(t *testing.T) {
@@ -79,6 +80,8 @@ func TestPolicyGeneration(t *testing.T) {
 		Role                   kops.InstanceGroupRole
 		LegacyIAM              bool
 		AllowContainerRegistry bool
+		ClusterAutoscaler      bool
+		EBSCSIDriver           bool
 		Policy                 string
 	}{
 		{
@@ -99,6 +102,20 @@ func TestPolicyGeneration(t *testing.T) {
 			AllowContainerRegistry: true,
 			Policy:                 "tests/iam_builder_master_strict_ecr.json",
 		},
+		{
+			Role:                   "Master",
+			LegacyIAM:              false,
+			AllowContainerRegistry: false,
+			ClusterAutoscaler:      true,
+			Policy:                 "tests/iam_builder_master_strict_autoscaler.json",
+		},
+		{
+			Role:                   "Master",
+			LegacyIAM:              false,
+			AllowContainerRegistry: false,
+			EBSCSIDriver:           true,
+			Policy:                 "tests/iam_builder_master_strict_csi.json",
+		},
 		{
 			Role:                   "Node",
 			LegacyIAM:              true,
@@ -173,6 +190,12 @@ func TestPolicyGeneration(t *testing.T) {
 			Role: x.Role,
 		}
 		b.Cluster.SetName("iam-builder-test.k8s.local")
+		if x.ClusterAutoscaler {
+			b.Cluster.Spec.ClusterAutoscaler = &kops.ClusterAutoscalerConfig{Enabled: fi.Bool(true)}
+		}
+		if x.EBSCSIDriver {
+			b.Cluster.Spec.Storage = &kops.StorageSpec{CSI: &kops.CSISpec{Enabled: fi.Bool(true)}}
+		}
 
 		p, err := b.BuildAWSPolicy()
 		if err != nil {
@@ -201,3 +224,54 @@ func TestPolicyGeneration(t *testing.T) {
 		}
 	}
 }
+
+func TestSecretEncryptionKMSPolicies(t *testing.T) {
+	grid := []struct {
+		Spec     kops.AWSKMSSecretEncryptionSpec
+		Resource string
+	}{
+		{
+			Spec:     kops.AWSKMSSecretEncryptionSpec{KeyID: "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+			Resource: "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		},
+		{
+			Spec:     kops.AWSKMSSecretEncryptionSpec{KeyID: "arn:aws:kms:us-east-1:123456789012:alias/kops"},
+			Resource: "arn:aws:kms:us-east-1:*:key/*",
+		},
+		{
+			Spec:     kops.AWSKMSSecretEncryptionSpec{KeyID: "alias/kops", Region: "eu-west-1"},
+			Resource: "arn:aws:kms:eu-west-1:*:key/*",
+		},
+		{
+			Spec:     kops.AWSKMSSecretEncryptionSpec{KeyID: "1234abcd-12ab-34cd-56ef-1234567890ab", Region: "eu-west-1"},
+			Resource: "arn:aws:kms:eu-west-1:*:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		},
+	}
+	for _, g := range grid {
+		p := &Policy{}
+		addSecretEncryptionKMSPolicies(p, &g.Spec, "arn:aws")
+		if len(p.Statement) != 1 {
+			t.Fatalf("expected one statement, got %v", p.Statement)
+		}
+		actual := p.Statement[0].Resource.Value()
+		if len(actual) != 1 || actual[0] != g.Resource {
+			t.Errorf("unexpected resource %v for %q, expected %q", actual, g.Spec.KeyID, g.Resource)
+		}
+	}
+}
+
+func TestEncryptionAtRestKMSPolicies(t *testing.T) {
+	keyID := "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	p := &Policy{}
+	addEncryptionAtRestKMSPolicies(p, &kops.KMSEncryptionAtRestSpec{KeyID: keyID})
+	if len(p.Statement) != 1 {
+		t.Fatalf("expected one statement, got %v", p.Statement)
+	}
+	if actual := p.Statement[0].Resource.Value(); len(actual) != 1 || actual[0] != keyID {
+		t.Errorf("unexpected resource %v, expected %q", actual, keyID)
+	}
+	if actual := p.Statement[0].Action.Value(); strings.Join(actual, ",") != "kms:Decrypt,kms:DescribeKey,kms:Encrypt" {
+		t.Errorf("unexpected actions %v", actual)
+	}
+}
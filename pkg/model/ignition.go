@@ -0,0 +1,312 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model/resources"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// ignitionSpecVersion is the version of the Ignition config spec we generate, supported by CoreOS Container Linux and Flatcar
+	ignitionSpecVersion = "2.2.0"
+	// ignitionInstallDir is the directory holding nodeup and its configuration, as with the bootstrap script
+	ignitionInstallDir = "/var/cache/kubernetes-install"
+	// ignitionUserDataDir is the directory holding the additional user-data scripts
+	ignitionUserDataDir = ignitionInstallDir + "/userdata"
+)
+
+// ignitionConfig is the subset of an Ignition config (spec version 2.2.0) that we generate
+type ignitionConfig struct {
+	Ignition ignitionMeta     `json:"ignition"`
+	Storage  *ignitionStorage `json:"storage,omitempty"`
+	Systemd  *ignitionSystemd `json:"systemd,omitempty"`
+}
+
+type ignitionMeta struct {
+	Version string                `json:"version"`
+	Config  *ignitionConfigAppend `json:"config,omitempty"`
+}
+
+type ignitionConfigAppend struct {
+	Append []ignitionSource `json:"append,omitempty"`
+}
+
+type ignitionSource struct {
+	Source string `json:"source"`
+}
+
+type ignitionStorage struct {
+	Files []ignitionFile `json:"files,omitempty"`
+}
+
+type ignitionFile struct {
+	Filesystem string         `json:"filesystem"`
+	Path       string         `json:"path"`
+	Mode       int            `json:"mode"`
+	Append     bool           `json:"append,omitempty"`
+	Contents   ignitionSource `json:"contents"`
+}
+
+type ignitionSystemd struct {
+	Units []ignitionUnit `json:"units,omitempty"`
+}
+
+type ignitionUnit struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled,omitempty"`
+	Contents string `json:"contents,omitempty"`
+}
+
+// ignitionResource renders the Ignition config of an instance group
+type ignitionResource struct {
+	bootstrapScript *BootstrapScript
+	ig              *kops.InstanceGroup
+	cluster         *kops.Cluster
+}
+
+var _ fi.Resource = &ignitionResource{}
+
+// Open implements fi.Resource
+func (r *ignitionResource) Open() (io.Reader, error) {
+	config, err := r.bootstrapScript.buildIgnitionConfig(r.ig, r.cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing ignition config: %v", err)
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// buildIgnitionConfig builds the Ignition config of an instance group.  It carries the same files as the bootstrap script,
+// and runs nodeup from the kops-configuration service; AdditionalUserData scripts become oneshot services.
+func (b *BootstrapScript) buildIgnitionConfig(ig *kops.InstanceGroup, cluster *kops.Cluster) (*ignitionConfig, error) {
+	config := &ignitionConfig{
+		Ignition: ignitionMeta{Version: ignitionSpecVersion},
+	}
+
+	var files []ignitionFile
+	var units []ignitionUnit
+
+	if ps := cluster.Spec.EgressProxy; ps != nil && ps.HTTPProxy.Host != "" {
+		httpProxyURL := b.proxyURL(ps)
+
+		var environment bytes.Buffer
+		environment.WriteString("http_proxy=" + httpProxyURL + "\n")
+		environment.WriteString("https_proxy=" + httpProxyURL + "\n")
+		environment.WriteString("no_proxy=" + ps.ProxyExcludes + "\n")
+		environment.WriteString("NO_PROXY=" + ps.ProxyExcludes + "\n")
+		files = append(files, ignitionFile{
+			Filesystem: "root",
+			Path:       "/etc/environment",
+			Mode:       0644,
+			Append:     true,
+			Contents:   ignitionData(environment.String()),
+		})
+
+		manager := &systemd.Manifest{}
+		manager.Set("Manager", "DefaultEnvironment", fmt.Sprintf("\"http_proxy=%s\" \"https_proxy=%s\" \"NO_PROXY=%s\" \"no_proxy=%s\"", httpProxyURL, httpProxyURL, ps.ProxyExcludes, ps.ProxyExcludes))
+		files = append(files, ignitionFile{
+			Filesystem: "root",
+			Path:       "/etc/systemd/system.conf.d/kops-proxy.conf",
+			Mode:       0644,
+			Contents:   ignitionData(manager.Render()),
+		})
+	}
+
+	if !ig.IsBastion() {
+		clusterSpec, err := b.clusterSpec(ig, cluster)
+		if err != nil {
+			return nil, err
+		}
+		igSpec, err := b.igSpec(ig)
+		if err != nil {
+			return nil, err
+		}
+		kubeEnv, err := b.KubeEnv(ig)
+		if err != nil {
+			return nil, err
+		}
+		downloadScript, err := b.nodeUpDownloadScript()
+		if err != nil {
+			return nil, err
+		}
+
+		env, err := b.buildEnvironmentVariables(cluster)
+		if err != nil {
+			return nil, err
+		}
+		var keys []string
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var environment bytes.Buffer
+		for _, k := range keys {
+			environment.WriteString(k + "=" + env[k] + "\n")
+		}
+
+		files = append(files,
+			ignitionFile{
+				Filesystem: "root",
+				Path:       path.Join(ignitionInstallDir, "cluster_spec.yaml"),
+				Mode:       0644,
+				Contents:   ignitionData(clusterSpec),
+			},
+			ignitionFile{
+				Filesystem: "root",
+				Path:       path.Join(ignitionInstallDir, "ig_spec.yaml"),
+				Mode:       0644,
+				Contents:   ignitionData(igSpec),
+			},
+			ignitionFile{
+				Filesystem: "root",
+				Path:       path.Join(ignitionInstallDir, "kube_env.yaml"),
+				Mode:       0644,
+				Contents:   ignitionData(kubeEnv),
+			},
+			ignitionFile{
+				Filesystem: "root",
+				Path:       path.Join(ignitionInstallDir, "environment"),
+				Mode:       0600,
+				Contents:   ignitionData(environment.String()),
+			},
+			ignitionFile{
+				Filesystem: "root",
+				Path:       path.Join(ignitionInstallDir, "download-nodeup.sh"),
+				Mode:       0755,
+				Contents:   ignitionData(downloadScript),
+			},
+		)
+
+		manifest := &systemd.Manifest{}
+		manifest.Set("Unit", "Description", "Run kops bootstrap (nodeup)")
+		manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+		manifest.Set("Unit", "Wants", "network-online.target")
+		manifest.Set("Unit", "After", "network-online.target")
+		manifest.Set("Service", "EnvironmentFile", path.Join(ignitionInstallDir, "environment"))
+		manifest.Set("Service", "EnvironmentFile", "-/etc/environment")
+		manifest.Set("Service", "ExecStartPre", path.Join(ignitionInstallDir, "download-nodeup.sh"))
+		manifest.Set("Service", "ExecStart", path.Join(ignitionInstallDir, "nodeup")+" --conf="+path.Join(ignitionInstallDir, "kube_env.yaml")+" --v=8")
+		manifest.Set("Service", "Type", "oneshot")
+		manifest.Set("Install", "WantedBy", "multi-user.target")
+
+		units = append(units, ignitionUnit{
+			Name:     "kops-configuration.service",
+			Enabled:  true,
+			Contents: manifest.Render(),
+		})
+	}
+
+	for _, d := range ig.Spec.AdditionalUserData {
+		switch d.Type {
+		case "text/x-shellscript":
+			scriptPath := path.Join(ignitionUserDataDir, d.Name)
+			files = append(files, ignitionFile{
+				Filesystem: "root",
+				Path:       scriptPath,
+				Mode:       0755,
+				Contents:   ignitionData(d.Content),
+			})
+
+			manifest := &systemd.Manifest{}
+			manifest.Set("Unit", "Description", "Run user-data "+d.Name)
+			manifest.Set("Unit", "Wants", "network-online.target")
+			manifest.Set("Unit", "After", "network-online.target")
+			if !ig.IsBastion() {
+				manifest.Set("Unit", "Before", "kops-configuration.service")
+			}
+			manifest.Set("Service", "Type", "oneshot")
+			manifest.Set("Service", "RemainAfterExit", "yes")
+			manifest.Set("Service", "ExecStart", scriptPath)
+			manifest.Set("Install", "WantedBy", "multi-user.target")
+
+			units = append(units, ignitionUnit{
+				Name:     "kops-userdata-" + ignitionUnitName(d.Name) + ".service",
+				Enabled:  true,
+				Contents: manifest.Render(),
+			})
+
+		case kops.UserDataTypeIgnition:
+			if config.Ignition.Config == nil {
+				config.Ignition.Config = &ignitionConfigAppend{}
+			}
+			config.Ignition.Config.Append = append(config.Ignition.Config.Append, ignitionData(d.Content))
+
+		default:
+			return nil, fmt.Errorf("user-data %q of type %q is not supported with bootstrapFormat %s", d.Name, d.Type, kops.BootstrapFormatIgnition)
+		}
+	}
+
+	if len(files) != 0 {
+		config.Storage = &ignitionStorage{Files: files}
+	}
+	if len(units) != 0 {
+		config.Systemd = &ignitionSystemd{Units: units}
+	}
+
+	return config, nil
+}
+
+// nodeUpDownloadScript renders the script that downloads nodeup
+func (b *BootstrapScript) nodeUpDownloadScript() (string, error) {
+	functions := template.FuncMap{
+		"NodeUpSource": func() string {
+			return b.NodeUpSource
+		},
+		"NodeUpSourceHash": func() string {
+			return b.NodeUpSourceHash
+		},
+	}
+
+	templateResource, err := NewTemplateResource("download-nodeup", resources.NodeUpDownloadTemplate, functions, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return fi.ResourceAsString(templateResource)
+}
+
+// ignitionData returns a data URL with the contents of a file
+func ignitionData(contents string) ignitionSource {
+	return ignitionSource{
+		Source: "data:text/plain;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(contents)),
+	}
+}
+
+var ignitionUnitNameInvalid = regexp.MustCompile("[^a-zA-Z0-9_-]+")
+
+// ignitionUnitName turns the name of an AdditionalUserData into a valid systemd unit name
+func ignitionUnitName(name string) string {
+	return ignitionUnitNameInvalid.ReplaceAllString(name, "-")
+}
@@ -29,6 +29,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/aliup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/dotasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gcetasks"
@@ -37,11 +39,12 @@ import (
 )
 
 const (
-	DefaultEtcdVolumeSize    = 20
-	DefaultAWSEtcdVolumeType = "gp2"
-	DefaultAWSEtcdVolumeIops = 100
-	DefaultGCEEtcdVolumeType = "pd-ssd"
-	DefaultALIEtcdVolumeType = "cloud_ssd"
+	DefaultEtcdVolumeSize      = 20
+	DefaultAWSEtcdVolumeType   = "gp2"
+	DefaultAWSEtcdVolumeIops   = 100
+	DefaultGCEEtcdVolumeType   = "pd-ssd"
+	DefaultALIEtcdVolumeType   = "cloud_ssd"
+	DefaultAzureEtcdVolumeType = "Premium_LRS"
 )
 
 // MasterVolumeBuilder builds master EBS volumes
@@ -111,6 +114,11 @@ func (b *MasterVolumeBuilder) Build(c *fi.ModelBuilderContext) error {
 				}
 			case kops.CloudProviderALI:
 				b.addALIVolume(c, name, volumeSize, zone, etcd, m, allMembers)
+			case kops.CloudProviderAzure:
+				err = b.addAzureVolume(c, name, volumeSize, zone, etcd, m, allMembers)
+				if err != nil {
+					return err
+				}
 			default:
 				return fmt.Errorf("unknown cloudprovider %q", b.Cluster.Spec.CloudProvider)
 			}
@@ -314,3 +322,46 @@ func (b *MasterVolumeBuilder) addALIVolume(c *fi.ModelBuilderContext, name strin
 
 	c.AddTask(t)
 }
+
+func (b *MasterVolumeBuilder) addAzureVolume(c *fi.ModelBuilderContext, name string, volumeSize int32, zone string, etcd *kops.EtcdClusterSpec, m *kops.EtcdMemberSpec, allMembers []string) error {
+	volumeType := fi.StringValue(m.VolumeType)
+	if volumeType == "" {
+		volumeType = DefaultAzureEtcdVolumeType
+	}
+
+	_, az, err := azureup.ParseZone(zone)
+	if err != nil {
+		return err
+	}
+	var zones []string
+	if az != "" {
+		zones = []string{az}
+	}
+
+	// The tags are how protokube knows to attach the disk and use it for etcd
+	tags := make(map[string]string)
+
+	// Apply all user defined labels on the volumes
+	for k, v := range b.Cluster.Spec.CloudLabels {
+		tags[k] = v
+	}
+
+	// This is the configuration of the etcd cluster
+	tags[azureup.TagNameEtcdClusterPrefix+etcd.Name] = m.Name + "/" + strings.Join(allMembers, ",")
+	// This says "only mount on a master"
+	tags[azureup.TagNameRolePrefix+azureup.TagRoleMaster] = "1"
+
+	t := &azuretasks.Disk{
+		Name:      s(name),
+		Lifecycle: b.Lifecycle,
+
+		ResourceGroup: &azuretasks.ResourceGroup{Name: s(azureup.FindResourceGroupName(b.Cluster))},
+		SizeGB:        fi.Int32(volumeSize),
+		VolumeType:    s(volumeType),
+		Zones:         zones,
+		Tags:          tags,
+	}
+	c.AddTask(t)
+
+	return nil
+}
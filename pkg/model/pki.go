@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/kops/pkg/encryptionatrest"
 	"k8s.io/kops/pkg/tokens"
+	"k8s.io/kops/pkg/vault"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 	"k8s.io/kops/util/pkg/vfs"
@@ -354,7 +356,13 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		c.AddTask(&fitasks.Secret{Name: fi.String(x), Lifecycle: b.Lifecycle})
 	}
 
-	{
+	// Create the keys the kube-apiserver encrypts resources in etcd with
+	if b.Cluster.Spec.EncryptionAtRest != nil && b.Cluster.Spec.EncryptionAtRest.Provider != encryptionatrest.ProviderKMS {
+		c.AddTask(&fitasks.EncryptionKeySet{Name: fi.String(encryptionatrest.SecretName), Lifecycle: b.Lifecycle})
+	}
+
+	// Stores in vault are read directly by the nodes, so are not mirrored
+	if !vault.IsVaultPath(b.Cluster.Spec.SecretStore) {
 		mirrorPath, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.SecretStore)
 		if err != nil {
 			return err
@@ -367,7 +375,7 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 		c.AddTask(t)
 	}
 
-	{
+	if !vault.IsVaultPath(b.Cluster.Spec.KeyStore) {
 		mirrorPath, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.KeyStore)
 		if err != nil {
 			return err
@@ -26,43 +26,8 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 )
 
-var NodeUpTemplate = `#!/bin/bash
-# Copyright 2016 The Kubernetes Authors All rights reserved.
-#
-# Licensed under the Apache License, Version 2.0 (the "License");
-# you may not use this file except in compliance with the License.
-# You may obtain a copy of the License at
-#
-#     http://www.apache.org/licenses/LICENSE-2.0
-#
-# Unless required by applicable law or agreed to in writing, software
-# distributed under the License is distributed on an "AS IS" BASIS,
-# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-# See the License for the specific language governing permissions and
-# limitations under the License.
-
-set -o errexit
-set -o nounset
-set -o pipefail
-
-NODEUP_URL={{ NodeUpSource }}
-NODEUP_HASH={{ NodeUpSourceHash }}
-
-{{ EnvironmentVariables }}
-
-{{ ProxyEnv }}
-
-function ensure-install-dir() {
-  INSTALL_DIR="/var/cache/kubernetes-install"
-  # On ContainerOS, we install to /var/lib/toolbox install (because of noexec)
-  if [[ -d /var/lib/toolbox ]]; then
-    INSTALL_DIR="/var/lib/toolbox/kubernetes-install"
-  fi
-  mkdir -p ${INSTALL_DIR}
-  cd ${INSTALL_DIR}
-}
-
-# Retry a download until we get it. Takes a hash and a set of URLs.
+// nodeUpDownloadFunctions are the bash functions that download nodeup and verify its hash
+const nodeUpDownloadFunctions = `# Retry a download until we get it. Takes a hash and a set of URLs.
 #
 # $1 is the sha1 of the URL. Can be "" if the sha1 is unknown.
 # $2+ are the URLs to download.
@@ -147,7 +112,45 @@ function try-download-release() {
 
   chmod +x nodeup
 }
+`
 
+var NodeUpTemplate = `#!/bin/bash
+# Copyright 2016 The Kubernetes Authors All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+NODEUP_URL={{ NodeUpSource }}
+NODEUP_HASH={{ NodeUpSourceHash }}
+
+{{ EnvironmentVariables }}
+
+{{ ProxyEnv }}
+
+function ensure-install-dir() {
+  INSTALL_DIR="/var/cache/kubernetes-install"
+  # On ContainerOS, we install to /var/lib/toolbox install (because of noexec)
+  if [[ -d /var/lib/toolbox ]]; then
+    INSTALL_DIR="/var/lib/toolbox/kubernetes-install"
+  fi
+  mkdir -p ${INSTALL_DIR}
+  cd ${INSTALL_DIR}
+}
+
+` + nodeUpDownloadFunctions + `
 function download-release() {
   # In case of failure checking integrity of release, retry.
   until try-download-release; do
@@ -183,6 +186,41 @@ download-release
 echo "== nodeup node config done =="
 `
 
+// NodeUpDownloadTemplate is a script that downloads nodeup to the install directory, for instances
+// bootstrapped with Ignition, where the kops-configuration service runs nodeup directly
+var NodeUpDownloadTemplate = `#!/bin/bash
+# Copyright 2016 The Kubernetes Authors All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+NODEUP_URL={{ NodeUpSource }}
+NODEUP_HASH={{ NodeUpSourceHash }}
+
+` + nodeUpDownloadFunctions + `
+mkdir -p /var/cache/kubernetes-install
+cd /var/cache/kubernetes-install
+
+# In case of failure checking integrity of release, retry.
+until try-download-release; do
+  sleep 15
+  echo "Couldn't download release. Retrying..."
+done
+`
+
 // AWSNodeUpTemplate returns a MIME Multi Part Archive containing the nodeup (bootstrap) script
 // and any additional User Data passed to using AdditionalUserData in the IG Spec
 func AWSNodeUpTemplate(ig *kops.InstanceGroup) (string, error) {
@@ -0,0 +1,404 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/kops/pkg/resources"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+const (
+	typeResourceGroup        = "ResourceGroup"
+	typeVirtualNetwork       = "VirtualNetwork"
+	typeSubnet               = "Subnet"
+	typeNetworkSecurityGroup = "NetworkSecurityGroup"
+	typeRouteTable           = "RouteTable"
+	typePublicIPAddress      = "PublicIPAddress"
+	typeLoadBalancer         = "LoadBalancer"
+	typeVMScaleSet           = "VMScaleSet"
+	typeDisk                 = "Disk"
+	typeRoleAssignment       = "RoleAssignment"
+)
+
+type azureListFn func() ([]*resources.Resource, error)
+
+type clusterDiscoveryAzure struct {
+	cloud         azureup.AzureCloud
+	clusterName   string
+	resourceGroup string
+
+	// principalIDs are the managed identities of the VM scale sets of the cluster
+	principalIDs map[string]bool
+	// disks are the names of the etcd disks of the cluster
+	disks []string
+}
+
+// ListResourcesAzure lists the Azure resources kops manages, which are those tagged with the cluster name
+func ListResourcesAzure(cloud azureup.AzureCloud, clusterName string) (map[string]*resources.Resource, error) {
+	resources := make(map[string]*resources.Resource)
+
+	d := &clusterDiscoveryAzure{
+		cloud:         cloud,
+		clusterName:   clusterName,
+		resourceGroup: cloud.ResourceGroupName(),
+		principalIDs:  make(map[string]bool),
+	}
+
+	rgs, err := d.listResourceGroups()
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, rg := range rgs {
+		if rg.Name == d.resourceGroup {
+			found = true
+		}
+		resources[rg.Type+":"+rg.ID] = rg
+	}
+	if !found {
+		// The resource group does not exist yet, or was already deleted
+		return resources, nil
+	}
+
+	// VM scale sets must be deleted before the disks attached to them, and role assignments are found
+	// from the identities of the VM scale sets, so the order matters
+	listFunctions := []azureListFn{
+		d.listDisks,
+		d.listVMScaleSets,
+		d.listRoleAssignments,
+		d.listLoadBalancers,
+		d.listPublicIPAddresses,
+		d.listVirtualNetworks,
+		d.listNetworkSecurityGroups,
+		d.listRouteTables,
+	}
+	for _, fn := range listFunctions {
+		resourceTrackers, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resourceTrackers {
+			resources[t.Type+":"+t.ID] = t
+		}
+	}
+	return resources, nil
+}
+
+func (d *clusterDiscoveryAzure) isOwned(tags map[string]string) bool {
+	return tags[azureup.TagClusterName] == d.clusterName
+}
+
+// newResource builds a tracker for a resource in the resource group; it must be deleted before the resource group
+func (d *clusterDiscoveryAzure) newResource(resourceType, name string, deleter func(cloud fi.Cloud, r *resources.Resource) error) *resources.Resource {
+	return &resources.Resource{
+		Name:    name,
+		ID:      name,
+		Type:    resourceType,
+		Deleter: deleter,
+		Blocks:  []string{typeResourceGroup + ":" + d.resourceGroup},
+	}
+}
+
+func (d *clusterDiscoveryAzure) listResourceGroups() ([]*resources.Resource, error) {
+	rgs, err := d.cloud.ResourceGroup().List(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, rg := range rgs {
+		if rg.Name != d.resourceGroup {
+			continue
+		}
+		trackers = append(trackers, &resources.Resource{
+			Name: rg.Name,
+			ID:   rg.Name,
+			Type: typeResourceGroup,
+			// A resource group we did not create is left in place
+			Shared: !d.isOwned(rg.Tags),
+			Deleter: func(cloud fi.Cloud, r *resources.Resource) error {
+				return cloud.(azureup.AzureCloud).ResourceGroup().Delete(context.TODO(), r.ID)
+			},
+			Obj: rg,
+		})
+	}
+	return trackers, nil
+}
+
+func (d *clusterDiscoveryAzure) listVMScaleSets() ([]*resources.Resource, error) {
+	l, err := d.cloud.VMScaleSet().List(context.TODO(), d.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, vmss := range l {
+		if !d.isOwned(vmss.Tags) {
+			continue
+		}
+		if vmss.Identity != nil && vmss.Identity.PrincipalID != "" {
+			d.principalIDs[vmss.Identity.PrincipalID] = true
+		}
+
+		t := d.newResource(typeVMScaleSet, vmss.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+			return cloud.(azureup.AzureCloud).VMScaleSet().Delete(context.TODO(), d.resourceGroup, r.ID)
+		})
+		t.Obj = vmss
+
+		// The VMs hold references to the network resources and the etcd disks
+		if profile := vmss.Properties.VirtualMachineProfile; profile != nil && profile.NetworkProfile != nil {
+			for _, nic := range profile.NetworkProfile.NetworkInterfaceConfigurations {
+				for _, ipConfig := range nic.Properties.IPConfigurations {
+					if ipConfig.Properties.Subnet != nil {
+						t.Blocks = append(t.Blocks, subnetKey(ipConfig.Properties.Subnet.ID))
+						t.Blocks = append(t.Blocks, typeVirtualNetwork+":"+virtualNetworkName(ipConfig.Properties.Subnet.ID))
+					}
+					for _, pool := range ipConfig.Properties.LoadBalancerBackendAddressPools {
+						t.Blocks = append(t.Blocks, typeLoadBalancer+":"+resourceName(pool.ID, "loadBalancers"))
+					}
+				}
+			}
+		}
+		for _, disk := range d.disks {
+			t.Blocks = append(t.Blocks, typeDisk+":"+disk)
+		}
+		trackers = append(trackers, t)
+	}
+	return trackers, nil
+}
+
+func (d *clusterDiscoveryAzure) listRoleAssignments() ([]*resources.Resource, error) {
+	if len(d.principalIDs) == 0 {
+		return nil, nil
+	}
+
+	scope := azureup.ResourceGroupID(d.cloud.SubscriptionID(), d.resourceGroup)
+	l, err := d.cloud.RoleAssignment().List(context.TODO(), scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, ra := range l {
+		if !d.principalIDs[ra.Properties.PrincipalID] {
+			continue
+		}
+		raScope := ra.Properties.Scope
+		t := d.newResource(typeRoleAssignment, ra.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+			return cloud.(azureup.AzureCloud).RoleAssignment().Delete(context.TODO(), raScope, r.ID)
+		})
+		t.Obj = ra
+		trackers = append(trackers, t)
+	}
+	return trackers, nil
+}
+
+func (d *clusterDiscoveryAzure) listLoadBalancers() ([]*resources.Resource, error) {
+	l, err := d.cloud.LoadBalancer().List(context.TODO(), d.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, lb := range l {
+		if !d.isOwned(lb.Tags) {
+			continue
+		}
+		t := d.newResource(typeLoadBalancer, lb.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+			return cloud.(azureup.AzureCloud).LoadBalancer().Delete(context.TODO(), d.resourceGroup, r.ID)
+		})
+		t.Obj = lb
+		for _, fe := range lb.Properties.FrontendIPConfigurations {
+			if fe.Properties.PublicIPAddress != nil {
+				t.Blocks = append(t.Blocks, typePublicIPAddress+":"+lastComponent(fe.Properties.PublicIPAddress.ID))
+			}
+			if fe.Properties.Subnet != nil {
+				t.Blocks = append(t.Blocks, subnetKey(fe.Properties.Subnet.ID))
+				t.Blocks = append(t.Blocks, typeVirtualNetwork+":"+virtualNetworkName(fe.Properties.Subnet.ID))
+			}
+		}
+		trackers = append(trackers, t)
+	}
+	return trackers, nil
+}
+
+func (d *clusterDiscoveryAzure) listPublicIPAddresses() ([]*resources.Resource, error) {
+	l, err := d.cloud.PublicIPAddress().List(context.TODO(), d.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, pip := range l {
+		if !d.isOwned(pip.Tags) {
+			continue
+		}
+		t := d.newResource(typePublicIPAddress, pip.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+			return cloud.(azureup.AzureCloud).PublicIPAddress().Delete(context.TODO(), d.resourceGroup, r.ID)
+		})
+		t.Obj = pip
+		trackers = append(trackers, t)
+	}
+	return trackers, nil
+}
+
+func (d *clusterDiscoveryAzure) listVirtualNetworks() ([]*resources.Resource, error) {
+	l, err := d.cloud.VirtualNetwork().List(context.TODO(), d.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, vnet := range l {
+		subnets, err := d.cloud.Subnet().List(context.TODO(), d.resourceGroup, vnet.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if d.isOwned(vnet.Tags) {
+			// Deleting the virtual network deletes its subnets
+			t := d.newResource(typeVirtualNetwork, vnet.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+				return cloud.(azureup.AzureCloud).VirtualNetwork().Delete(context.TODO(), d.resourceGroup, r.ID)
+			})
+			t.Obj = vnet
+			t.Blocks = append(t.Blocks, subnetDependencies(subnets)...)
+			trackers = append(trackers, t)
+			continue
+		}
+
+		// Subnets are not tagged, so in a shared virtual network we recognize ours by name
+		for _, subnet := range subnets {
+			if !strings.HasSuffix(subnet.Name, "."+d.clusterName) {
+				continue
+			}
+			vnetName := vnet.Name
+			t := d.newResource(typeSubnet, vnetName+"/"+subnet.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+				return cloud.(azureup.AzureCloud).Subnet().Delete(context.TODO(), d.resourceGroup, vnetName, r.Name)
+			})
+			t.Name = subnet.Name
+			t.Obj = subnet
+			t.Blocks = append(t.Blocks, subnetDependencies([]*azureup.Subnet{subnet})...)
+			trackers = append(trackers, t)
+		}
+	}
+	return trackers, nil
+}
+
+// subnetDependencies returns the keys of the resources the subnets reference, which must be deleted after them
+func subnetDependencies(subnets []*azureup.Subnet) []string {
+	var blocks []string
+	for _, subnet := range subnets {
+		if subnet.Properties.NetworkSecurityGroup != nil {
+			blocks = append(blocks, typeNetworkSecurityGroup+":"+lastComponent(subnet.Properties.NetworkSecurityGroup.ID))
+		}
+		if subnet.Properties.RouteTable != nil {
+			blocks = append(blocks, typeRouteTable+":"+lastComponent(subnet.Properties.RouteTable.ID))
+		}
+	}
+	return blocks
+}
+
+func (d *clusterDiscoveryAzure) listNetworkSecurityGroups() ([]*resources.Resource, error) {
+	l, err := d.cloud.NetworkSecurityGroup().List(context.TODO(), d.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, nsg := range l {
+		if !d.isOwned(nsg.Tags) {
+			continue
+		}
+		t := d.newResource(typeNetworkSecurityGroup, nsg.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+			return cloud.(azureup.AzureCloud).NetworkSecurityGroup().Delete(context.TODO(), d.resourceGroup, r.ID)
+		})
+		t.Obj = nsg
+		trackers = append(trackers, t)
+	}
+	return trackers, nil
+}
+
+func (d *clusterDiscoveryAzure) listRouteTables() ([]*resources.Resource, error) {
+	l, err := d.cloud.RouteTable().List(context.TODO(), d.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, rt := range l {
+		if !d.isOwned(rt.Tags) {
+			continue
+		}
+		t := d.newResource(typeRouteTable, rt.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+			return cloud.(azureup.AzureCloud).RouteTable().Delete(context.TODO(), d.resourceGroup, r.ID)
+		})
+		t.Obj = rt
+		trackers = append(trackers, t)
+	}
+	return trackers, nil
+}
+
+func (d *clusterDiscoveryAzure) listDisks() ([]*resources.Resource, error) {
+	l, err := d.cloud.Disk().List(context.TODO(), d.resourceGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackers []*resources.Resource
+	for _, disk := range l {
+		if !d.isOwned(disk.Tags) {
+			continue
+		}
+		t := d.newResource(typeDisk, disk.Name, func(cloud fi.Cloud, r *resources.Resource) error {
+			return cloud.(azureup.AzureCloud).Disk().Delete(context.TODO(), d.resourceGroup, r.ID)
+		})
+		t.Obj = disk
+		trackers = append(trackers, t)
+		d.disks = append(d.disks, disk.Name)
+	}
+	return trackers, nil
+}
+
+// lastComponent returns the last component of a resource ID, which is the name of the resource
+func lastComponent(id string) string {
+	tokens := strings.Split(id, "/")
+	return tokens[len(tokens)-1]
+}
+
+// resourceName returns the name following the resource type in a resource ID
+func resourceName(id, resourceType string) string {
+	tokens := strings.Split(id, "/")
+	for i := 0; i+1 < len(tokens); i++ {
+		if strings.EqualFold(tokens[i], resourceType) {
+			return tokens[i+1]
+		}
+	}
+	return ""
+}
+
+func virtualNetworkName(subnetID string) string {
+	return resourceName(subnetID, "virtualNetworks")
+}
+
+// subnetKey returns the key of the tracker of a subnet in a shared virtual network
+func subnetKey(subnetID string) string {
+	return typeSubnet + ":" + virtualNetworkName(subnetID) + "/" + lastComponent(subnetID)
+}
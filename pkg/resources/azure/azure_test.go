@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/kops/cloudmock/azure/mockazure"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+func TestListResourcesAzure(t *testing.T) {
+	ctx := context.TODO()
+	clusterName := "cluster1.k8s.local"
+	ownedTags := map[string]string{azureup.TagClusterName: clusterName}
+	otherTags := map[string]string{azureup.TagClusterName: "cluster2.k8s.local"}
+
+	cloud := mockazure.BuildMockAzureCloud("eastus", "rg1", ownedTags)
+	if err := cloud.ResourceGroup().CreateOrUpdate(ctx, "rg1", azureup.ResourceGroup{Location: "eastus", Tags: ownedTags}); err != nil {
+		t.Fatalf("error creating resource group: %v", err)
+	}
+	if err := cloud.VirtualNetwork().CreateOrUpdate(ctx, "rg1", "vnet1", azureup.VirtualNetwork{Tags: ownedTags}); err != nil {
+		t.Fatalf("error creating virtual network: %v", err)
+	}
+	if err := cloud.Disk().CreateOrUpdate(ctx, "rg1", "disk1", azureup.Disk{Tags: ownedTags}); err != nil {
+		t.Fatalf("error creating disk: %v", err)
+	}
+	if err := cloud.Disk().CreateOrUpdate(ctx, "rg1", "disk2", azureup.Disk{Tags: otherTags}); err != nil {
+		t.Fatalf("error creating disk: %v", err)
+	}
+
+	resources, err := ListResourcesAzure(cloud, clusterName)
+	if err != nil {
+		t.Fatalf("error listing resources: %v", err)
+	}
+
+	var keys []string
+	for k, r := range resources {
+		keys = append(keys, k)
+		if r.Shared {
+			t.Errorf("unexpected shared resource %q", k)
+		}
+	}
+	sort.Strings(keys)
+	expected := []string{"Disk:disk1", "ResourceGroup:rg1", "VirtualNetwork:vnet1"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("unexpected resources: %v, expected %v", keys, expected)
+	}
+
+	for _, k := range []string{"Disk:disk1", "VirtualNetwork:vnet1"} {
+		if !reflect.DeepEqual(resources[k].Blocks, []string{"ResourceGroup:rg1"}) {
+			t.Errorf("unexpected blocks for %q: %v", k, resources[k].Blocks)
+		}
+	}
+}
+
+func TestListResourcesAzureSharedResourceGroup(t *testing.T) {
+	ctx := context.TODO()
+	clusterName := "cluster1.k8s.local"
+
+	cloud := mockazure.BuildMockAzureCloud("eastus", "existing", map[string]string{azureup.TagClusterName: clusterName})
+	if err := cloud.ResourceGroup().CreateOrUpdate(ctx, "existing", azureup.ResourceGroup{Location: "eastus"}); err != nil {
+		t.Fatalf("error creating resource group: %v", err)
+	}
+
+	resources, err := ListResourcesAzure(cloud, clusterName)
+	if err != nil {
+		t.Fatalf("error listing resources: %v", err)
+	}
+	rg := resources["ResourceGroup:existing"]
+	if rg == nil {
+		t.Fatalf("resource group not found in %v", resources)
+	}
+	if !rg.Shared {
+		t.Fatalf("resource group we did not create should be shared")
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretencryption
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// awsKMSKeyWrapper wraps data keys with an AWS KMS key
+type awsKMSKeyWrapper struct {
+	keyID  string
+	region string
+}
+
+var _ keyWrapper = &awsKMSKeyWrapper{}
+
+func newAWSKMSKeyWrapper(spec *kops.AWSKMSSecretEncryptionSpec) (*awsKMSKeyWrapper, error) {
+	if spec.KeyID == "" {
+		return nil, fmt.Errorf("AWS KMS secret encryption requires keyID")
+	}
+
+	region := spec.Region
+	if region == "" && strings.HasPrefix(spec.KeyID, "arn:") {
+		a, err := arn.Parse(spec.KeyID)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing KMS key ARN %q: %v", spec.KeyID, err)
+		}
+		region = a.Region
+	}
+	if region == "" {
+		return nil, fmt.Errorf("AWS KMS secret encryption requires region unless keyID is an ARN")
+	}
+
+	return &awsKMSKeyWrapper{
+		keyID:  spec.KeyID,
+		region: region,
+	}, nil
+}
+
+func (w *awsKMSKeyWrapper) client() (*kms.KMS, error) {
+	config := aws.NewConfig().WithRegion(w.region)
+	config = config.WithCredentialsChainVerboseErrors(true)
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("error starting new AWS session: %v", err)
+	}
+	return kms.New(sess, config), nil
+}
+
+func (w *awsKMSKeyWrapper) wrapKey(key []byte) ([]byte, error) {
+	client, err := w.client()
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(w.keyID),
+		Plaintext: key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting with KMS key %q: %v", w.keyID, err)
+	}
+	return response.CiphertextBlob, nil
+}
+
+func (w *awsKMSKeyWrapper) unwrapKey(wrapped []byte) ([]byte, error) {
+	client, err := w.client()
+	if err != nil {
+		return nil, err
+	}
+	// The ciphertext blob identifies the key it was encrypted with
+	response, err := client.Decrypt(&kms.DecryptInput{
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting with KMS key %q: %v", w.keyID, err)
+	}
+	return response.Plaintext, nil
+}
@@ -0,0 +1,352 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretencryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// envelopePrefix marks data encrypted by kops; data without it is read as plaintext, so stores can be migrated in place
+const envelopePrefix = "kops-encrypted:v1\n"
+
+// envelope is the serialized form of encrypted data.  The data is encrypted with AES-GCM under a random data key,
+// and the data key is wrapped by the key provider.  The provider is recorded by its ID and configuration, but the
+// data key is only ever unwrapped with a provider configured in the cluster spec: the state store is not trusted
+// to choose where key material and credentials such as VAULT_TOKEN are sent.
+type envelope struct {
+	KeyID      string                    `json:"keyID"`
+	Key        kops.SecretEncryptionSpec `json:"key"`
+	WrappedKey []byte                    `json:"wrappedKey"`
+	Nonce      []byte                    `json:"nonce"`
+	Ciphertext []byte                    `json:"ciphertext"`
+}
+
+// keyWrapper wraps and unwraps data keys with a key management service
+type keyWrapper interface {
+	wrapKey(key []byte) ([]byte, error)
+	unwrapKey(wrapped []byte) ([]byte, error)
+}
+
+// Encrypter encrypts data for the state store.  A nil Encrypter leaves data unencrypted.
+type Encrypter struct {
+	id   string
+	spec kops.SecretEncryptionSpec
+
+	mutex      sync.Mutex
+	dataKey    []byte
+	wrappedKey []byte
+}
+
+var (
+	cacheMutex sync.Mutex
+	// encrypters caches the Encrypter for each key, so we only wrap one data key per key per process
+	encrypters = make(map[string]*Encrypter)
+	// dataKeys caches unwrapped data keys by key ID and wrapped key, so we only call the key provider once per data key
+	dataKeys = make(map[string][]byte)
+)
+
+// ForCluster returns the Encrypter for the secret encryption configured on the cluster, or nil if it is not enabled
+func ForCluster(cluster *kops.Cluster) (*Encrypter, error) {
+	if cluster == nil || cluster.Spec.SecretEncryption == nil {
+		return nil, nil
+	}
+	current := providerSpec(cluster.Spec.SecretEncryption)
+	if !hasProvider(current) {
+		// Only previous keys are configured: encryption is being disabled
+		return nil, nil
+	}
+	return New(current)
+}
+
+// New returns the Encrypter for the key; any previous keys in spec are ignored
+func New(spec *kops.SecretEncryptionSpec) (*Encrypter, error) {
+	spec = providerSpec(spec)
+	if _, err := buildKeyWrapper(spec); err != nil {
+		return nil, err
+	}
+
+	id, err := keyID(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	e := encrypters[id]
+	if e == nil {
+		e = &Encrypter{id: id, spec: *spec}
+		encrypters[id] = e
+	}
+	return e, nil
+}
+
+// configuredKeys returns the key providers configured for the cluster: the current provider and the previous keys
+func configuredKeys(cluster *kops.Cluster) []*kops.SecretEncryptionSpec {
+	if cluster == nil || cluster.Spec.SecretEncryption == nil {
+		return nil
+	}
+
+	var keys []*kops.SecretEncryptionSpec
+	if current := providerSpec(cluster.Spec.SecretEncryption); hasProvider(current) {
+		keys = append(keys, current)
+	}
+	for i := range cluster.Spec.SecretEncryption.PreviousKeys {
+		keys = append(keys, providerSpec(&cluster.Spec.SecretEncryption.PreviousKeys[i]))
+	}
+	return keys
+}
+
+// providerSpec returns the key provider configuration of spec, without the previous keys
+func providerSpec(spec *kops.SecretEncryptionSpec) *kops.SecretEncryptionSpec {
+	p := *spec
+	p.PreviousKeys = nil
+	return &p
+}
+
+func hasProvider(spec *kops.SecretEncryptionSpec) bool {
+	return spec.AWSKMS != nil || spec.GCPKMS != nil || spec.VaultTransit != nil || spec.Local != nil
+}
+
+// keyID returns the ID of a key provider, the hash of its configuration
+func keyID(spec *kops.SecretEncryptionSpec) (string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("error serializing secret encryption spec: %v", err)
+	}
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Encrypt encrypts the data; if e is nil the data is returned unchanged
+func (e *Encrypter) Encrypt(data []byte) ([]byte, error) {
+	if e == nil {
+		return data, nil
+	}
+
+	dataKey, wrappedKey, err := e.getDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+
+	o := &envelope{
+		KeyID:      e.id,
+		Key:        e.spec,
+		WrappedKey: wrappedKey,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, data, nil),
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing encrypted data: %v", err)
+	}
+	return append([]byte(envelopePrefix), b...), nil
+}
+
+// getDataKey returns the data key, and the data key wrapped by the key provider, generating them on first use
+func (e *Encrypter) getDataKey() ([]byte, []byte, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.dataKey == nil {
+		wrapper, err := buildKeyWrapper(&e.spec)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		dataKey := make([]byte, 32)
+		if _, err := rand.Read(dataKey); err != nil {
+			return nil, nil, fmt.Errorf("error generating data key: %v", err)
+		}
+		wrappedKey, err := wrapper.wrapKey(dataKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error wrapping data key: %v", err)
+		}
+		e.dataKey = dataKey
+		e.wrappedKey = wrappedKey
+	}
+	return e.dataKey, e.wrappedKey, nil
+}
+
+// IsEncrypted returns true if the data was encrypted by an Encrypter
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(envelopePrefix))
+}
+
+// IsCurrent returns true if the data is encrypted with the key of e, or if e is nil and the data is not encrypted
+func (e *Encrypter) IsCurrent(data []byte) bool {
+	if !IsEncrypted(data) {
+		return e == nil
+	}
+	if e == nil {
+		return false
+	}
+
+	o := &envelope{}
+	if err := json.Unmarshal(data[len(envelopePrefix):], o); err != nil {
+		return false
+	}
+	return o.KeyID == e.id && reflect.DeepEqual(o.Key, e.spec)
+}
+
+// Decrypt decrypts data encrypted by an Encrypter, with the key provider of the cluster that the data records.
+// The provider must be the current key or one of the previous keys in the secretEncryption of the cluster.
+// Data that is not encrypted is returned unchanged.
+func Decrypt(cluster *kops.Cluster, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	o := &envelope{}
+	if err := json.Unmarshal(data[len(envelopePrefix):], o); err != nil {
+		return nil, fmt.Errorf("error parsing encrypted data: %v", err)
+	}
+
+	spec, err := findKey(cluster, o)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := unwrapDataKey(spec, o)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted data has invalid nonce")
+	}
+	plaintext, err := gcm.Open(nil, o.Nonce, o.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting data: %v", err)
+	}
+	return plaintext, nil
+}
+
+// findKey returns the key provider configured for the cluster that the data was encrypted with
+func findKey(cluster *kops.Cluster, o *envelope) (*kops.SecretEncryptionSpec, error) {
+	keys := configuredKeys(cluster)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("data is encrypted, but secretEncryption is not configured for the cluster")
+	}
+
+	for _, spec := range keys {
+		id, err := keyID(spec)
+		if err != nil {
+			return nil, err
+		}
+		if id != o.KeyID {
+			continue
+		}
+		if !reflect.DeepEqual(o.Key, *spec) {
+			return nil, fmt.Errorf("key recorded with the encrypted data does not match key %s of the cluster", id)
+		}
+		return spec, nil
+	}
+	return nil, fmt.Errorf("data is encrypted with key %q, which is not configured in secretEncryption or its previousKeys", o.KeyID)
+}
+
+func unwrapDataKey(spec *kops.SecretEncryptionSpec, o *envelope) ([]byte, error) {
+	cacheKey := o.KeyID + "/" + string(o.WrappedKey)
+
+	cacheMutex.Lock()
+	dataKey := dataKeys[cacheKey]
+	cacheMutex.Unlock()
+	if dataKey != nil {
+		return dataKey, nil
+	}
+
+	wrapper, err := buildKeyWrapper(spec)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err = wrapper.unwrapKey(o.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key: %v", err)
+	}
+
+	cacheMutex.Lock()
+	dataKeys[cacheKey] = dataKey
+	cacheMutex.Unlock()
+
+	return dataKey, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// buildKeyWrapper returns the keyWrapper for the provider configured in spec
+func buildKeyWrapper(spec *kops.SecretEncryptionSpec) (keyWrapper, error) {
+	var wrappers []keyWrapper
+	if spec.AWSKMS != nil {
+		w, err := newAWSKMSKeyWrapper(spec.AWSKMS)
+		if err != nil {
+			return nil, err
+		}
+		wrappers = append(wrappers, w)
+	}
+	if spec.GCPKMS != nil {
+		w, err := newGCPKMSKeyWrapper(spec.GCPKMS)
+		if err != nil {
+			return nil, err
+		}
+		wrappers = append(wrappers, w)
+	}
+	if spec.VaultTransit != nil {
+		w, err := newVaultTransitKeyWrapper(spec.VaultTransit)
+		if err != nil {
+			return nil, err
+		}
+		wrappers = append(wrappers, w)
+	}
+	if spec.Local != nil {
+		wrappers = append(wrappers, &localKeyWrapper{})
+	}
+
+	if len(wrappers) != 1 {
+		return nil, fmt.Errorf("secret encryption must configure exactly one key provider")
+	}
+	return wrappers[0], nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretencryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+const (
+	// gcpKMSEndpoint is the base URL of the Cloud KMS REST API
+	gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+	// gcpKMSScope is the OAuth scope for Cloud KMS
+	gcpKMSScope = "https://www.googleapis.com/auth/cloudkms"
+)
+
+// gcpKMSKeyWrapper wraps data keys with a Google Cloud KMS key
+type gcpKMSKeyWrapper struct {
+	keyName string
+
+	// endpoint and httpClient can be replaced for testing purposes
+	endpoint   string
+	httpClient *http.Client
+}
+
+var _ keyWrapper = &gcpKMSKeyWrapper{}
+
+func newGCPKMSKeyWrapper(spec *kops.GCPKMSSecretEncryptionSpec) (*gcpKMSKeyWrapper, error) {
+	if !strings.HasPrefix(spec.KeyName, "projects/") || !strings.Contains(spec.KeyName, "/cryptoKeys/") {
+		return nil, fmt.Errorf("GCP KMS secret encryption requires keyName of the form projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>, was %q", spec.KeyName)
+	}
+	return &gcpKMSKeyWrapper{
+		keyName:  spec.KeyName,
+		endpoint: gcpKMSEndpoint,
+	}, nil
+}
+
+func (w *gcpKMSKeyWrapper) wrapKey(key []byte) ([]byte, error) {
+	request := map[string][]byte{"plaintext": key}
+	response := &struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}{}
+	if err := w.call("encrypt", request, response); err != nil {
+		return nil, err
+	}
+	return response.Ciphertext, nil
+}
+
+func (w *gcpKMSKeyWrapper) unwrapKey(wrapped []byte) ([]byte, error) {
+	request := map[string][]byte{"ciphertext": wrapped}
+	response := &struct {
+		Plaintext []byte `json:"plaintext"`
+	}{}
+	if err := w.call("decrypt", request, response); err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
+
+// call invokes the encrypt or decrypt method of the key; byte slices are base64 encoded in the JSON, as the API expects
+func (w *gcpKMSKeyWrapper) call(method string, request interface{}, response interface{}) error {
+	httpClient := w.httpClient
+	if httpClient == nil {
+		var err error
+		httpClient, err = google.DefaultClient(context.Background(), gcpKMSScope)
+		if err != nil {
+			return fmt.Errorf("error building GCP client: %v", err)
+		}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Post(w.endpoint+w.keyName+":"+method, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error calling %s on KMS key %q: %v", method, w.keyName, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading KMS response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error calling %s on KMS key %q: %s: %s", method, w.keyName, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("error parsing KMS response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretencryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// localKeyFileEnv is the environment variable naming the file holding the local key
+const localKeyFileEnv = "KOPS_SECRET_ENCRYPTION_KEY_FILE"
+
+// localKeyWrapper wraps data keys with a symmetric key read from a local file, using NaCl secretbox
+type localKeyWrapper struct {
+}
+
+var _ keyWrapper = &localKeyWrapper{}
+
+func (w *localKeyWrapper) readKey() (*[32]byte, error) {
+	p := os.Getenv(localKeyFileEnv)
+	if p == "" {
+		return nil, fmt.Errorf("%s must be set to use local secret encryption", localKeyFileEnv)
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret encryption key %q: %v", p, err)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(b) != 32 {
+		return nil, fmt.Errorf("secret encryption key %q must be 32 base64 encoded bytes", p)
+	}
+	key := &[32]byte{}
+	copy(key[:], b)
+	return key, nil
+}
+
+func (w *localKeyWrapper) wrapKey(key []byte) ([]byte, error) {
+	localKey, err := w.readKey()
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+	return secretbox.Seal(nonce[:], key, &nonce, localKey), nil
+}
+
+func (w *localKeyWrapper) unwrapKey(wrapped []byte) ([]byte, error) {
+	localKey, err := w.readKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < 24 {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	var nonce [24]byte
+	copy(nonce[:], wrapped[:24])
+	key, ok := secretbox.Open(nil, wrapped[24:], &nonce, localKey)
+	if !ok {
+		return nil, fmt.Errorf("unable to unwrap data key with %s; the key may not match", os.Getenv(localKeyFileEnv))
+	}
+	return key, nil
+}
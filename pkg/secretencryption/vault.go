@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretencryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// vaultTransitKeyWrapper wraps data keys with a key of the Vault transit secrets engine
+type vaultTransitKeyWrapper struct {
+	address   string
+	mountPath string
+	keyName   string
+
+	// httpClient can be replaced for testing purposes
+	httpClient *http.Client
+}
+
+var _ keyWrapper = &vaultTransitKeyWrapper{}
+
+func newVaultTransitKeyWrapper(spec *kops.VaultTransitSecretEncryptionSpec) (*vaultTransitKeyWrapper, error) {
+	if spec.Address == "" {
+		return nil, fmt.Errorf("vault transit secret encryption requires address")
+	}
+	if spec.KeyName == "" {
+		return nil, fmt.Errorf("vault transit secret encryption requires keyName")
+	}
+	mountPath := strings.Trim(spec.MountPath, "/")
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+	return &vaultTransitKeyWrapper{
+		address:    strings.TrimSuffix(spec.Address, "/"),
+		mountPath:  mountPath,
+		keyName:    spec.KeyName,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+func (w *vaultTransitKeyWrapper) wrapKey(key []byte) ([]byte, error) {
+	request := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key)}
+	response := &struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}{}
+	if err := w.call("encrypt", request, response); err != nil {
+		return nil, err
+	}
+	if response.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("vault returned no ciphertext for key %q", w.keyName)
+	}
+	return []byte(response.Data.Ciphertext), nil
+}
+
+func (w *vaultTransitKeyWrapper) unwrapKey(wrapped []byte) ([]byte, error) {
+	request := map[string]string{"ciphertext": string(wrapped)}
+	response := &struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}{}
+	if err := w.call("decrypt", request, response); err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding plaintext from vault: %v", err)
+	}
+	return key, nil
+}
+
+// call invokes the encrypt or decrypt endpoint of the transit key, authenticating with VAULT_TOKEN
+func (w *vaultTransitKeyWrapper) call(method string, request interface{}, response interface{}) error {
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return fmt.Errorf("VAULT_TOKEN must be set to use vault transit key %q", w.keyName)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	url := w.address + "/v1/" + w.mountPath + "/" + method + "/" + w.keyName
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling vault %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading vault response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error calling vault %s: %s: %s", url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("error parsing vault response: %v", err)
+	}
+	return nil
+}
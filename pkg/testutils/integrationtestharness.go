@@ -34,9 +34,11 @@ import (
 	"k8s.io/kops/cloudmock/aws/mockelbv2"
 	"k8s.io/kops/cloudmock/aws/mockiam"
 	"k8s.io/kops/cloudmock/aws/mockroute53"
+	"k8s.io/kops/cloudmock/azure/mockazure"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -108,6 +110,7 @@ func (h *IntegrationTestHarness) Close() {
 	if h.originalPKIDefaultPrivateKeySize != 0 {
 		pki.DefaultPrivateKeySize = h.originalPKIDefaultPrivateKeySize
 	}
+	azureup.ResetMockAzureClouds()
 }
 
 func (h *IntegrationTestHarness) SetupMockAWS() *awsup.MockAWSCloud {
@@ -231,6 +234,13 @@ func (h *IntegrationTestHarness) SetupMockGCE() {
 	gce.InstallMockGCECloud("us-test1", "testproject")
 }
 
+// SetupMockAzure configures a mock Azure cloud provider, with the default resource group of the cluster
+func (h *IntegrationTestHarness) SetupMockAzure(clusterName string) *azureup.MockAzureCloud {
+	cloud := mockazure.BuildMockAzureCloud("eastus", clusterName, map[string]string{azureup.TagClusterName: clusterName})
+	azureup.InstallMockAzureCloud(cloud)
+	return cloud
+}
+
 // MockKopsVersion will set the kops version to the specified value, until Close is called
 func (h *IntegrationTestHarness) MockKopsVersion(version string) {
 	if h.originalKopsVersion != "" {
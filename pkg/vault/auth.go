@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"k8s.io/klog"
+)
+
+// loginDataBuilders build the login request for each supported cloud auth method; can be replaced for testing purposes
+var loginDataBuilders = map[string]func(role string) (map[string]interface{}, error){
+	"aws": buildAWSLoginData,
+	"gcp": buildGCPLoginData,
+}
+
+// Login authenticates to vault with a cloud auth method, using the identity of the instance, and sets the client token.
+// If mount is empty the auth method is assumed to be mounted at its default path.
+func (c *Client) Login(method string, mount string, role string) error {
+	builder := loginDataBuilders[method]
+	if builder == nil {
+		return fmt.Errorf("unsupported vault auth method %q", method)
+	}
+	if role == "" {
+		return fmt.Errorf("vault auth method %q requires a role", method)
+	}
+	if mount == "" {
+		mount = method
+	}
+
+	data, err := builder(role)
+	if err != nil {
+		return fmt.Errorf("error building vault %s login request: %v", method, err)
+	}
+
+	response := &struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}{}
+	if err := c.do("POST", "auth/"+mount+"/login", data, response); err != nil {
+		if err == errNotFound {
+			return fmt.Errorf("error logging in to vault: auth method %q not found", mount)
+		}
+		return fmt.Errorf("error logging in to vault with role %q: %v", role, err)
+	}
+	if response.Auth.ClientToken == "" {
+		return fmt.Errorf("vault login with role %q did not return a token", role)
+	}
+
+	klog.V(2).Infof("Logged in to vault with auth method %q and role %q", mount, role)
+	c.token = response.Auth.ClientToken
+	return nil
+}
+
+// buildAWSLoginData builds a login request for the aws auth method (iam type), by signing a sts:GetCallerIdentity request
+func buildAWSLoginData(role string) (map[string]interface{}, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("us-east-1")})
+	if err != nil {
+		return nil, err
+	}
+	req, _ := sts.New(sess).GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+	if err := req.Sign(); err != nil {
+		return nil, fmt.Errorf("error signing sts:GetCallerIdentity request: %v", err)
+	}
+
+	headers, err := json.Marshal(req.HTTPRequest.Header)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(req.HTTPRequest.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"role":                    role,
+		"iam_http_request_method": req.HTTPRequest.Method,
+		"iam_request_url":         base64.StdEncoding.EncodeToString([]byte(req.HTTPRequest.URL.String())),
+		"iam_request_headers":     base64.StdEncoding.EncodeToString(headers),
+		"iam_request_body":        base64.StdEncoding.EncodeToString(body),
+	}, nil
+}
+
+// buildGCPLoginData builds a login request for the gcp auth method (gce type), from the instance identity token
+func buildGCPLoginData(role string) (map[string]interface{}, error) {
+	jwt, err := metadata.Get("instance/service-accounts/default/identity?audience=vault/" + role + "&format=full")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching instance identity token: %v", err)
+	}
+	return map[string]interface{}{
+		"role": role,
+		"jwt":  jwt,
+	}, nil
+}
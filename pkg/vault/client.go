@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+// Client is a minimal client for the HashiCorp Vault HTTP API
+type Client struct {
+	address   string
+	namespace string
+	token     string
+
+	// httpClient can be replaced for testing purposes
+	httpClient *http.Client
+}
+
+// NewClient builds a Client for the Vault server at address, authenticating with VAULT_TOKEN if it is set
+func NewClient(address string, namespace string) *Client {
+	return &Client{
+		address:    strings.TrimSuffix(address, "/"),
+		namespace:  namespace,
+		token:      os.Getenv("VAULT_TOKEN"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+// Address returns the address of the Vault server
+func (c *Client) Address() string {
+	return c.address
+}
+
+// HasToken returns true if the client has a token with which to authenticate
+func (c *Client) HasToken() bool {
+	return c.token != ""
+}
+
+// SetToken sets the token the client authenticates with
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// errNotFound is returned by do when vault responds with a 404
+var errNotFound = fmt.Errorf("not found")
+
+// responseError is returned by do when vault responds with an error status
+type responseError struct {
+	method     string
+	url        string
+	status     string
+	statusCode int
+	body       string
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("error calling vault %s %s: %s: %s", e.method, e.url, e.status, e.body)
+}
+
+// do performs a request against the vault API, decoding the JSON response into response if it is not nil
+func (c *Client) do(method string, path string, request interface{}, response interface{}) error {
+	var body []byte
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
+	url := c.address + "/v1/" + strings.TrimPrefix(path, "/")
+	klog.V(4).Infof("Performing vault request: %s %s", method, url)
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling vault %s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading vault response: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if response != nil {
+			if err := json.Unmarshal(data, response); err != nil {
+				return fmt.Errorf("error parsing vault response from %s: %v", url, err)
+			}
+		}
+		return nil
+	case http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return errNotFound
+	default:
+		return &responseError{
+			method:     method,
+			url:        url,
+			status:     resp.Status,
+			statusCode: resp.StatusCode,
+			body:       strings.TrimSpace(string(data)),
+		}
+	}
+}
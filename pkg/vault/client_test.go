@@ -0,0 +1,215 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kops/cloudmock/vault/mockvault"
+)
+
+func TestParseStorePath(t *testing.T) {
+	defer os.Setenv("VAULT_ADDR", os.Getenv("VAULT_ADDR"))
+	os.Setenv("VAULT_ADDR", "http://127.0.0.1:8200")
+
+	grid := []struct {
+		Path     string
+		Expected *StorePath
+		Error    bool
+	}{
+		{
+			Path:     "vault://vault.example.com:8200/secret/kops/cluster.example.com/pki",
+			Expected: &StorePath{Address: "https://vault.example.com:8200", Mount: "secret", Prefix: "kops/cluster.example.com/pki"},
+		},
+		{
+			Path:     "vault://127.0.0.1:8200/kv/secrets/",
+			Expected: &StorePath{Address: "http://127.0.0.1:8200", Mount: "kv", Prefix: "secrets"},
+		},
+		{
+			Path:  "vault://vault.example.com/secret",
+			Error: true,
+		},
+		{
+			Path:  "vault:///secret/secrets",
+			Error: true,
+		},
+		{
+			Path:  "s3://bucket/secrets",
+			Error: true,
+		},
+	}
+	for _, g := range grid {
+		actual, err := ParseStorePath(g.Path)
+		if g.Error {
+			if err == nil {
+				t.Errorf("expected error parsing %q", g.Path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %v", g.Path, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("unexpected result parsing %q: %+v", g.Path, actual)
+		}
+	}
+}
+
+func TestKV(t *testing.T) {
+	server := httptest.NewServer(mockvault.NewMockVault("root"))
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	c.SetToken("root")
+
+	if data, err := c.ReadKV("secret", "kops/missing"); err != nil || data != nil {
+		t.Fatalf("expected missing entry to return nil, got %v %v", data, err)
+	}
+
+	if err := c.WriteKV("secret", "kops/a", map[string]interface{}{"value": "1"}, 0); err != nil {
+		t.Fatalf("error writing entry: %v", err)
+	}
+	if err := c.WriteKV("secret", "kops/a", map[string]interface{}{"value": "2"}, 0); err != ErrCheckAndSetFailed {
+		t.Fatalf("expected check-and-set write of existing entry to fail, got %v", err)
+	}
+	if err := c.WriteKV("secret", "kops/dir/b", map[string]interface{}{"value": "3"}, -1); err != nil {
+		t.Fatalf("error writing entry: %v", err)
+	}
+
+	data, err := c.ReadKV("secret", "kops/a")
+	if err != nil {
+		t.Fatalf("error reading entry: %v", err)
+	}
+	if data["value"] != "1" {
+		t.Errorf("unexpected data: %v", data)
+	}
+
+	keys, err := c.ListKV("secret", "kops")
+	if err != nil {
+		t.Fatalf("error listing entries: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "dir/"}) {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+
+	if err := c.DeleteKV("secret", "kops/a"); err != nil {
+		t.Fatalf("error deleting entry: %v", err)
+	}
+	if data, err := c.ReadKV("secret", "kops/a"); err != nil || data != nil {
+		t.Fatalf("expected deleted entry to return nil, got %v %v", data, err)
+	}
+
+	c.SetToken("wrong")
+	if _, err := c.ReadKV("secret", "kops/dir/b"); err == nil {
+		t.Fatalf("expected error reading with an invalid token")
+	}
+}
+
+func TestIssueCertificate(t *testing.T) {
+	mock := mockvault.NewMockVault("root")
+	mock.PKIRoles["pki/kubelet"] = []string{"system:nodes"}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	c := NewClient(server.URL, "")
+	c.SetToken("root")
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("error creating CA: %v", err)
+	}
+	bundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(caKey)}))
+
+	if _, err := c.IssueCertificate("pki", "kubelet", "system:node:a", ""); err == nil {
+		t.Fatalf("expected error issuing before the CA is configured")
+	}
+	if err := c.ConfigureCA("pki", bundle); err != nil {
+		t.Fatalf("error configuring CA: %v", err)
+	}
+
+	issued, err := c.IssueCertificate("pki", "kubelet", "system:node:a", "1h")
+	if err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+	block, _ := pem.Decode([]byte(issued.Certificate))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("error parsing issued certificate: %v", err)
+	}
+	if cert.Subject.CommonName != "system:node:a" || !reflect.DeepEqual(cert.Subject.Organization, []string{"system:nodes"}) {
+		t.Errorf("unexpected subject: %v", cert.Subject)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	mock := mockvault.NewMockVault("root")
+	mock.LoginRoles["aws/kops-node"] = "node-token"
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	defer func(builder func(string) (map[string]interface{}, error)) {
+		loginDataBuilders["aws"] = builder
+	}(loginDataBuilders["aws"])
+	loginDataBuilders["aws"] = func(role string) (map[string]interface{}, error) {
+		return map[string]interface{}{"role": role, "iam_http_request_method": "POST"}, nil
+	}
+
+	c := NewClient(server.URL, "")
+	c.SetToken("")
+
+	if err := c.Login("aws", "", "unknown"); err == nil {
+		t.Fatalf("expected error logging in with an unknown role")
+	}
+	if err := c.Login("azure", "", "kops-node"); err == nil {
+		t.Fatalf("expected error logging in with an unsupported method")
+	}
+	if err := c.Login("aws", "", "kops-node"); err != nil {
+		t.Fatalf("error logging in: %v", err)
+	}
+	if c.token != "node-token" {
+		t.Errorf("unexpected token %q", c.token)
+	}
+
+	if err := c.WriteKV("secret", "kops/a", map[string]interface{}{"value": "1"}, -1); err != nil {
+		t.Errorf("error writing with login token: %v", err)
+	}
+}
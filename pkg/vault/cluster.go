@@ -0,0 +1,36 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// NewStoreClient parses a vault:// secret or key store path, and builds a client for it using the vault configuration of the cluster.
+// The client authenticates with VAULT_TOKEN; instances should call Login to authenticate with their cloud identity.
+func NewStoreClient(cluster *kops.Cluster, storePath string) (*Client, *StorePath, error) {
+	p, err := ParseStorePath(storePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	namespace := ""
+	if cluster.Spec.Vault != nil {
+		namespace = cluster.Spec.Vault.Namespace
+	}
+	return NewClient(p.Address, namespace), p, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrCheckAndSetFailed is returned by WriteKV when a check-and-set write finds the entry has been modified
+var ErrCheckAndSetFailed = fmt.Errorf("vault entry was modified concurrently (check-and-set failed)")
+
+// ReadKV reads the current version of an entry from a KV version 2 secrets engine.
+// Returns (nil, nil) if the entry does not exist, or its latest version is deleted.
+func (c *Client) ReadKV(mount string, key string) (map[string]interface{}, error) {
+	data, _, err := c.ReadKVVersion(mount, key)
+	return data, err
+}
+
+// ReadKVVersion reads the current version of an entry, also returning the version number for use in a check-and-set write.
+// Returns a nil map and version 0 if the entry does not exist.
+func (c *Client) ReadKVVersion(mount string, key string) (map[string]interface{}, int, error) {
+	response := &struct {
+		Data struct {
+			Data     map[string]interface{} `json:"data"`
+			Metadata struct {
+				Version int `json:"version"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}{}
+	err := c.do("GET", mount+"/data/"+key, nil, response)
+	if err == errNotFound {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return response.Data.Data, response.Data.Metadata.Version, nil
+}
+
+// WriteKV writes a new version of an entry to a KV version 2 secrets engine.
+// If cas is non-negative the write is a check-and-set; cas=0 only succeeds if the entry does not yet exist.
+func (c *Client) WriteKV(mount string, key string, data map[string]interface{}, cas int) error {
+	request := map[string]interface{}{
+		"data": data,
+	}
+	if cas >= 0 {
+		request["options"] = map[string]interface{}{"cas": cas}
+	}
+	if err := c.do("POST", mount+"/data/"+key, request, nil); err != nil {
+		if err == errNotFound {
+			return fmt.Errorf("error writing vault entry %s/%s: secrets engine not found", mount, key)
+		}
+		if e, ok := err.(*responseError); ok && cas >= 0 && e.statusCode == http.StatusBadRequest && strings.Contains(e.body, "check-and-set") {
+			return ErrCheckAndSetFailed
+		}
+		return err
+	}
+	return nil
+}
+
+// ListKV lists the entries under a prefix in a KV version 2 secrets engine.
+// Sub-directories are returned with a trailing slash.
+func (c *Client) ListKV(mount string, prefix string) ([]string, error) {
+	response := &struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}{}
+	err := c.do("LIST", mount+"/metadata/"+strings.TrimSuffix(prefix, "/"), nil, response)
+	if err == errNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Data.Keys, nil
+}
+
+// DeleteKV permanently deletes an entry, and all its versions, from a KV version 2 secrets engine
+func (c *Client) DeleteKV(mount string, key string) error {
+	err := c.do("DELETE", mount+"/metadata/"+key, nil, nil)
+	if err == errNotFound {
+		return nil
+	}
+	return err
+}
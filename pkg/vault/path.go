@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// IsVaultPath returns true if the store path refers to HashiCorp Vault
+func IsVaultPath(p string) bool {
+	return strings.HasPrefix(p, "vault://")
+}
+
+// StorePath is a parsed vault:// store path, of the form vault://<host>[:<port>]/<kv mount>/<prefix>
+type StorePath struct {
+	// Address is the URL of the vault server
+	Address string
+	// Mount is the path of the KV version 2 secrets engine
+	Mount string
+	// Prefix is the path within the secrets engine under which entries are stored
+	Prefix string
+}
+
+// ParseStorePath parses a vault:// store path.
+// The server is addressed over https, unless VAULT_ADDR names the same host, in which case VAULT_ADDR is used;
+// that allows e.g. a dev-mode server on http://127.0.0.1:8200.
+func ParseStorePath(p string) (*StorePath, error) {
+	u, err := url.Parse(p)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse vault path %q: %v", p, err)
+	}
+	if u.Scheme != "vault" {
+		return nil, fmt.Errorf("vault path %q must start with vault://", p)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("vault path %q must include the vault server", p)
+	}
+
+	tokens := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)
+	if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+		return nil, fmt.Errorf("vault path %q must be of the form vault://<server>/<kv mount>/<path>", p)
+	}
+
+	address := "https://" + u.Host
+	if env := os.Getenv("VAULT_ADDR"); env != "" {
+		if envURL, err := url.Parse(env); err == nil && envURL.Host == u.Host {
+			address = strings.TrimSuffix(env, "/")
+		}
+	}
+
+	return &StorePath{
+		Address: address,
+		Mount:   tokens[0],
+		Prefix:  strings.TrimSuffix(tokens[1], "/"),
+	}, nil
+}
+
+// Join returns the path of the named entry under the prefix
+func (p *StorePath) Join(name string) string {
+	return p.Prefix + "/" + name
+}
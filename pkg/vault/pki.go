@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"fmt"
+)
+
+// IssuedCertificate is a certificate issued by a PKI secrets engine
+type IssuedCertificate struct {
+	Certificate  string `json:"certificate"`
+	PrivateKey   string `json:"private_key"`
+	IssuingCA    string `json:"issuing_ca"`
+	SerialNumber string `json:"serial_number"`
+}
+
+// ConfigureCA loads a CA certificate and private key, as a PEM bundle, into a PKI secrets engine
+func (c *Client) ConfigureCA(mount string, pemBundle string) error {
+	request := map[string]interface{}{
+		"pem_bundle": pemBundle,
+	}
+	if err := c.do("POST", mount+"/config/ca", request, nil); err != nil {
+		if err == errNotFound {
+			return fmt.Errorf("error configuring CA in vault: PKI secrets engine %q not found", mount)
+		}
+		return err
+	}
+	return nil
+}
+
+// IssueCertificate issues a certificate and private key from a role of a PKI secrets engine
+func (c *Client) IssueCertificate(mount string, role string, commonName string, ttl string) (*IssuedCertificate, error) {
+	request := map[string]interface{}{
+		"common_name": commonName,
+	}
+	if ttl != "" {
+		request["ttl"] = ttl
+	}
+	response := &struct {
+		Data IssuedCertificate `json:"data"`
+	}{}
+	if err := c.do("POST", mount+"/issue/"+role, request, response); err != nil {
+		if err == errNotFound {
+			return nil, fmt.Errorf("error issuing certificate from vault: PKI role %s/%s not found", mount, role)
+		}
+		return nil, err
+	}
+	if response.Data.Certificate == "" || response.Data.PrivateKey == "" {
+		return nil, fmt.Errorf("vault PKI role %s/%s returned no certificate", mount, role)
+	}
+	return &response.Data, nil
+}
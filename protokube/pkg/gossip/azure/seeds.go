@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/protokube/pkg/gossip"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// SeedProvider discovers gossip seeds from the network interfaces of the VM scale sets of the cluster
+type SeedProvider struct {
+	cloud         azureup.AzureCloud
+	resourceGroup string
+	clusterTag    string
+}
+
+var _ gossip.SeedProvider = &SeedProvider{}
+
+func (p *SeedProvider) GetSeeds() ([]string, error) {
+	ctx := context.TODO()
+
+	vmScaleSets, err := p.cloud.VMScaleSet().List(ctx, p.resourceGroup)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VM scale sets: %v", err)
+	}
+
+	var seeds []string
+	for _, vmss := range vmScaleSets {
+		if vmss.Tags[azureup.TagClusterName] != p.clusterTag {
+			continue
+		}
+
+		nics, err := p.cloud.NetworkInterface().ListScaleSetNetworkInterfaces(ctx, p.resourceGroup, vmss.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error listing network interfaces of VM scale set %q: %v", vmss.Name, err)
+		}
+		for _, nic := range nics {
+			for _, ipConfig := range nic.Properties.IPConfigurations {
+				if ipConfig.Properties.PrivateIPAddress != "" {
+					seeds = append(seeds, ipConfig.Properties.PrivateIPAddress)
+				}
+			}
+		}
+	}
+
+	klog.V(4).Infof("Found gossip seeds: %v", seeds)
+	return seeds, nil
+}
+
+func NewSeedProvider(cloud azureup.AzureCloud, resourceGroup string, clusterTag string) (*SeedProvider, error) {
+	return &SeedProvider{
+		cloud:         cloud,
+		resourceGroup: resourceGroup,
+		clusterTag:    clusterTag,
+	}, nil
+}
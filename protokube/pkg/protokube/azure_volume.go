@@ -0,0 +1,332 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protokube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/klog"
+	"k8s.io/kops/protokube/pkg/etcd"
+	"k8s.io/kops/protokube/pkg/gossip"
+	gossipazure "k8s.io/kops/protokube/pkg/gossip/azure"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+const (
+	// azureMetadataURL is the endpoint of the Azure Instance Metadata Service
+	azureMetadataURL = "http://169.254.169.254/metadata/instance?api-version=2019-03-11"
+
+	// azureDataDiskDevicePrefix is where the Azure udev rules link data disks, by LUN
+	azureDataDiskDevicePrefix = "/dev/disk/azure/scsi1/lun"
+)
+
+// azureInstanceMetadata is the subset of the instance metadata we use
+type azureInstanceMetadata struct {
+	Compute struct {
+		Location          string `json:"location"`
+		Name              string `json:"name"`
+		ResourceGroupName string `json:"resourceGroupName"`
+		SubscriptionID    string `json:"subscriptionId"`
+		VMScaleSetName    string `json:"vmScaleSetName"`
+		Zone              string `json:"zone"`
+		// Tags is a semicolon-separated list of name:value pairs
+		Tags string `json:"tags"`
+	} `json:"compute"`
+	Network struct {
+		Interface []struct {
+			IPv4 struct {
+				IPAddress []struct {
+					PrivateIPAddress string `json:"privateIpAddress"`
+				} `json:"ipAddress"`
+			} `json:"ipv4"`
+		} `json:"interface"`
+	} `json:"network"`
+}
+
+// AzureVolumes is the Volumes implementation for Azure managed disks
+type AzureVolumes struct {
+	cloud azureup.AzureCloud
+
+	clusterTag    string
+	resourceGroup string
+	zone          string
+	vmScaleSet    string
+	instanceID    string
+	vmID          string
+	internalIP    net.IP
+}
+
+var _ Volumes = &AzureVolumes{}
+
+// NewAzureVolumes builds an AzureVolumes, discovering the VM from the instance metadata
+func NewAzureVolumes() (*AzureVolumes, error) {
+	metadata, err := queryAzureInstanceMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	a := &AzureVolumes{
+		resourceGroup: metadata.Compute.ResourceGroupName,
+		zone:          metadata.Compute.Zone,
+		vmScaleSet:    metadata.Compute.VMScaleSetName,
+	}
+
+	if a.vmScaleSet == "" {
+		return nil, fmt.Errorf("instance %q is not part of a VM scale set", metadata.Compute.Name)
+	}
+	// VMs in a scale set are named <scaleset>_<instanceid>
+	a.instanceID = strings.TrimPrefix(metadata.Compute.Name, a.vmScaleSet+"_")
+	a.vmID = azureup.ResourceID(metadata.Compute.SubscriptionID, a.resourceGroup, "Microsoft.Compute/virtualMachineScaleSets", a.vmScaleSet, "virtualMachines", a.instanceID)
+	klog.Infof("Found VM %q", a.vmID)
+
+	for _, tag := range strings.Split(metadata.Compute.Tags, ";") {
+		tokens := strings.SplitN(tag, ":", 2)
+		if len(tokens) == 2 && tokens[0] == azureup.TagClusterName {
+			a.clusterTag = tokens[1]
+		}
+	}
+	if a.clusterTag == "" {
+		return nil, fmt.Errorf("cluster tag %q not found on instance", azureup.TagClusterName)
+	}
+
+	for _, iface := range metadata.Network.Interface {
+		for _, address := range iface.IPv4.IPAddress {
+			if a.internalIP == nil && address.PrivateIPAddress != "" {
+				a.internalIP = net.ParseIP(address.PrivateIPAddress)
+			}
+		}
+	}
+	if a.internalIP == nil {
+		return nil, fmt.Errorf("private IP address not found in instance metadata")
+	}
+	klog.Infof("Found internalIP=%q", a.internalIP)
+
+	tags := map[string]string{azureup.TagClusterName: a.clusterTag}
+	cloud, err := azureup.NewAzureCloud(metadata.Compute.SubscriptionID, a.resourceGroup, metadata.Compute.Location, tags)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing azure cloud: %v", err)
+	}
+	a.cloud = cloud
+
+	return a, nil
+}
+
+func queryAzureInstanceMetadata() (*azureInstanceMetadata, error) {
+	req, err := http.NewRequest(http.MethodGet, azureMetadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying azure instance metadata: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error querying azure instance metadata: %s", response.Status)
+	}
+
+	metadata := &azureInstanceMetadata{}
+	if err := json.NewDecoder(response.Body).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("error parsing azure instance metadata: %v", err)
+	}
+	return metadata, nil
+}
+
+// ClusterID implements Volumes ClusterID
+func (a *AzureVolumes) ClusterID() string {
+	return a.clusterTag
+}
+
+// InstanceName returns the name of the VM, which is used as the gossip name
+func (a *AzureVolumes) InstanceName() string {
+	return a.vmScaleSet + "_" + a.instanceID
+}
+
+// InternalIP implements Volumes InternalIP
+func (a *AzureVolumes) InternalIP() net.IP {
+	return a.internalIP
+}
+
+// findDataDiskLUN returns the LUN at which the disk is attached to this VM, or -1 if it is not attached
+func findDataDiskLUN(vm *azureup.VirtualMachineScaleSetVM, diskID string) int32 {
+	if vm.Properties.StorageProfile == nil {
+		return -1
+	}
+	for _, dataDisk := range vm.Properties.StorageProfile.DataDisks {
+		if dataDisk.ManagedDisk != nil && strings.EqualFold(dataDisk.ManagedDisk.ID, diskID) {
+			return dataDisk.Lun
+		}
+	}
+	return -1
+}
+
+func dataDiskDevice(lun int32) string {
+	return fmt.Sprintf("%s%d", azureDataDiskDevicePrefix, lun)
+}
+
+func (a *AzureVolumes) FindVolumes() ([]*Volume, error) {
+	ctx := context.TODO()
+
+	klog.V(2).Infof("Listing Azure disks in %s", a.resourceGroup)
+	disks, err := a.cloud.Disk().List(ctx, a.resourceGroup)
+	if err != nil {
+		return nil, fmt.Errorf("error listing disks: %v", err)
+	}
+
+	vm, err := a.cloud.VMScaleSetVM().Get(ctx, a.resourceGroup, a.vmScaleSet, a.instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting VM %q: %v", a.vmID, err)
+	}
+	if vm == nil {
+		return nil, fmt.Errorf("VM %q not found", a.vmID)
+	}
+
+	var volumes []*Volume
+	for _, disk := range disks {
+		if disk.Tags[azureup.TagClusterName] != a.clusterTag {
+			continue
+		}
+		if disk.Tags[azureup.TagNameRolePrefix+azureup.TagRoleMaster] != "1" {
+			continue
+		}
+		// Zonal disks can only be attached to VMs in the same zone
+		if len(disk.Zones) != 0 && a.zone != "" && disk.Zones[0] != a.zone {
+			continue
+		}
+
+		volume := &Volume{
+			ID: disk.ID,
+			Info: VolumeInfo{
+				Description: disk.Name,
+			},
+			Status:     disk.Properties.DiskState,
+			AttachedTo: disk.ManagedBy,
+		}
+		if strings.EqualFold(volume.AttachedTo, a.vmID) {
+			if lun := findDataDiskLUN(vm, disk.ID); lun >= 0 {
+				volume.LocalDevice = dataDiskDevice(lun)
+			}
+		}
+
+		skipVolume := false
+		for k, v := range disk.Tags {
+			if strings.HasPrefix(k, azureup.TagNameEtcdClusterPrefix) {
+				etcdClusterName := strings.TrimPrefix(k, azureup.TagNameEtcdClusterPrefix)
+				spec, err := etcd.ParseEtcdClusterSpec(etcdClusterName, v)
+				if err != nil {
+					// Fail safe
+					klog.Warningf("error parsing etcd cluster tag %q on volume %q; skipping volume: %v", v, volume.ID, err)
+					skipVolume = true
+				}
+				volume.Info.EtcdClusters = append(volume.Info.EtcdClusters, spec)
+			}
+		}
+		if !skipVolume {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes, nil
+}
+
+// AttachVolume attaches the specified volume to this instance, at the first free LUN
+func (a *AzureVolumes) AttachVolume(volume *Volume) error {
+	ctx := context.TODO()
+
+	if volume.AttachedTo != "" && !strings.EqualFold(volume.AttachedTo, a.vmID) {
+		return fmt.Errorf("cannot reattach an attached disk without detaching it first")
+	}
+	if volume.LocalDevice != "" {
+		return nil
+	}
+
+	vm, err := a.cloud.VMScaleSetVM().Get(ctx, a.resourceGroup, a.vmScaleSet, a.instanceID)
+	if err != nil {
+		return fmt.Errorf("error getting VM %q: %v", a.vmID, err)
+	}
+	if vm == nil {
+		return fmt.Errorf("VM %q not found", a.vmID)
+	}
+	if vm.Properties.StorageProfile == nil {
+		vm.Properties.StorageProfile = &azureup.StorageProfile{}
+	}
+
+	lun := findDataDiskLUN(vm, volume.ID)
+	if lun < 0 {
+		used := make(map[int32]bool)
+		for _, dataDisk := range vm.Properties.StorageProfile.DataDisks {
+			used[dataDisk.Lun] = true
+		}
+		lun = 0
+		for used[lun] {
+			lun++
+		}
+
+		klog.Infof("Attaching disk %q to VM %q at LUN %d", volume.ID, a.vmID, lun)
+		vm.Properties.StorageProfile.DataDisks = append(vm.Properties.StorageProfile.DataDisks, &azureup.DataDisk{
+			Lun:          lun,
+			CreateOption: "Attach",
+			ManagedDisk: &azureup.ManagedDiskParameters{
+				ID: volume.ID,
+			},
+		})
+		// We only send the data disks, the other profiles are part of the scale set model
+		update := azureup.VirtualMachineScaleSetVM{
+			Location: vm.Location,
+			Zones:    vm.Zones,
+			Properties: azureup.VirtualMachineScaleSetVMProperties{
+				StorageProfile: &azureup.StorageProfile{
+					DataDisks: vm.Properties.StorageProfile.DataDisks,
+				},
+			},
+		}
+		if err := a.cloud.VMScaleSetVM().Update(ctx, a.resourceGroup, a.vmScaleSet, a.instanceID, update); err != nil {
+			return fmt.Errorf("error attaching disk %q: %v", volume.ID, err)
+		}
+	}
+
+	volume.LocalDevice = dataDiskDevice(lun)
+	volume.AttachedTo = a.vmID
+	return nil
+}
+
+// FindMountedVolume implements Volumes::FindMountedVolume
+func (a *AzureVolumes) FindMountedVolume(volume *Volume) (string, error) {
+	device := volume.LocalDevice
+
+	_, err := os.Stat(pathFor(device))
+	if err == nil {
+		return device, nil
+	}
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return "", fmt.Errorf("error checking for device %q: %v", device, err)
+}
+
+func (a *AzureVolumes) GossipSeeds() (gossip.SeedProvider, error) {
+	return gossipazure.NewSeedProvider(a.cloud, a.resourceGroup, a.clusterTag)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package fi
 
 import (
+	"crypto"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,8 +30,10 @@ import (
 	"time"
 
 	"k8s.io/klog"
+	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 type asset struct {
@@ -90,6 +93,9 @@ func (r *assetResource) GetSource() *Source {
 type AssetStore struct {
 	cacheDir string
 	assets   []*asset
+
+	// SignatureKey, if set, is the key the detached signature of every downloaded asset is verified against
+	SignatureKey crypto.PublicKey
 }
 
 func NewAssetStore(cacheDir string) *AssetStore {
@@ -202,9 +208,15 @@ func (a *AssetStore) addURLs(urls []string, hash *hashing.Hash) error {
 		if err != nil {
 			klog.Warningf("error downloading url %q: %v", url, err)
 			continue
-		} else {
-			break
 		}
+		if a.SignatureKey != nil {
+			err = VerifyDownloadSignature(a.SignatureKey, url, localFile)
+			if err != nil {
+				klog.Warningf("error verifying signature of url %q: %v", url, err)
+				continue
+			}
+		}
+		break
 	}
 	if err != nil {
 		return err
@@ -345,3 +357,13 @@ func (a *AssetStore) addArchive(archiveSource *Source, archiveFile string) error
 	return nil
 
 }
+
+// VerifyDownloadSignature verifies a file downloaded from url against the detached signature published alongside it
+func VerifyDownloadSignature(key crypto.PublicKey, url string, localFile string) error {
+	signatureURL := url + assets.SignatureSuffix
+	signature, err := vfs.Context.ReadFile(signatureURL)
+	if err != nil {
+		return fmt.Errorf("error reading signature %q: %v", signatureURL, err)
+	}
+	return assets.VerifyFileSignature(key, localFile, signature)
+}
@@ -18,6 +18,7 @@ package assettasks
 
 import (
 	"bytes"
+	"crypto"
 	"fmt"
 	"net/url"
 	"os"
@@ -25,6 +26,7 @@ import (
 
 	"k8s.io/klog"
 	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/hashing"
 	"k8s.io/kops/util/pkg/vfs"
@@ -38,7 +40,10 @@ type CopyFile struct {
 	SourceFile *string
 	TargetFile *string
 	SHA        *string
-	Lifecycle  *fi.Lifecycle
+	// SignatureKey is the PEM encoded key the detached signature of the file is verified against.
+	// When set, the signature is copied to the target repository alongside the file.
+	SignatureKey *string
+	Lifecycle    *fi.Lifecycle
 }
 
 var _ fi.CompareWithID = &CopyFile{}
@@ -67,12 +72,21 @@ func (e *CopyFile) Find(c *fi.Context) (*CopyFile, error) {
 
 	targetSHA := string(targetSHABytes)
 	if strings.TrimSpace(targetSHA) == strings.TrimSpace(fi.StringValue(e.SHA)) {
+		if e.SignatureKey != nil {
+			targetSignatureFile := fi.StringValue(e.TargetFile) + assets.SignatureSuffix
+			if _, err := vfs.Context.ReadFile(targetSignatureFile); err != nil {
+				klog.V(4).Infof("unable to download: %q, assuming target signature is not present: %v", targetSignatureFile, err)
+				return nil, nil
+			}
+		}
+
 		actual := &CopyFile{
-			Name:       e.Name,
-			TargetFile: e.TargetFile,
-			SHA:        e.SHA,
-			SourceFile: e.SourceFile,
-			Lifecycle:  e.Lifecycle,
+			Name:         e.Name,
+			TargetFile:   e.TargetFile,
+			SHA:          e.SHA,
+			SourceFile:   e.SourceFile,
+			SignatureKey: e.SignatureKey,
+			Lifecycle:    e.Lifecycle,
 		}
 		klog.V(8).Infof("found matching target sha1 for file: %q", fi.StringValue(e.TargetFile))
 		return actual, nil
@@ -109,7 +123,16 @@ func (_ *CopyFile) Render(c *fi.Context, a, e, changes *CopyFile) error {
 
 	klog.V(2).Infof("copying bits from %q to %q", source, target)
 
-	if err := transferFile(c, source, target, sourceSha); err != nil {
+	var signatureKey crypto.PublicKey
+	if e.SignatureKey != nil {
+		key, err := assets.ParseSignatureKey(fi.StringValue(e.SignatureKey))
+		if err != nil {
+			return err
+		}
+		signatureKey = key
+	}
+
+	if err := transferFile(c, source, target, sourceSha, signatureKey); err != nil {
 		return fmt.Errorf("unable to transfer %q to %q: %v", source, target, err)
 	}
 
@@ -117,8 +140,9 @@ func (_ *CopyFile) Render(c *fi.Context, a, e, changes *CopyFile) error {
 }
 
 // transferFile downloads a file from the source location, validates the file matches the SHA,
-// and uploads the file to the target location.
-func transferFile(c *fi.Context, source string, target string, sha string) error {
+// and uploads the file to the target location.  If signatureKey is not nil, the detached signature
+// of the file is verified against it and uploaded alongside the file.
+func transferFile(c *fi.Context, source string, target string, sha string, signatureKey crypto.PublicKey) error {
 
 	// TODO drop file to disk, as vfs reads file into memory.  We load kubelet into memory for instance.
 	// TODO in s3 can we do a copy file ... would need to test
@@ -163,11 +187,34 @@ func transferFile(c *fi.Context, source string, target string, sha string) error
 		return fmt.Errorf("the sha value in %q does not match %q calculated value %q", shaTarget, source, dataHash.String())
 	}
 
+	var signature []byte
+	if signatureKey != nil {
+		signatureSource := source + assets.SignatureSuffix
+		signature, err = vfs.Context.ReadFile(signatureSource)
+		if err != nil {
+			return fmt.Errorf("error downloading signature %q, fileSignatureKey requires signatures to be published alongside the files: %v", signatureSource, err)
+		}
+		if err := assets.VerifySignature(signatureKey, data, signature); err != nil {
+			return fmt.Errorf("error verifying signature %q: %v", signatureSource, err)
+		}
+	}
+
 	klog.Infof("uploading %q to %q", source, objectStore)
 	if err := writeFile(c, uploadVFS, data); err != nil {
 		return err
 	}
 
+	if signature != nil {
+		signatureTarget := objectStore + assets.SignatureSuffix
+		signatureVFS, err := vfs.Context.BuildVfsPath(signatureTarget)
+		if err != nil {
+			return fmt.Errorf("error building path %q: %v", signatureTarget, err)
+		}
+		if err := writeFile(c, signatureVFS, signature); err != nil {
+			return err
+		}
+	}
+
 	b := []byte(shaHash.Hex())
 	if err := writeFile(c, shaVFS, b); err != nil {
 		return err
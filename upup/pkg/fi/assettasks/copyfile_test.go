@@ -17,9 +17,25 @@ limitations under the License.
 package assettasks
 
 import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
 	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
+// testSignatureKey and testSignature are a signature of "kubelet binary", made with
+// openssl dgst -sha256 -sign key.pem file | base64
+const testSignatureKey = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEk+l1ClW9wOIwWLVeKgcDdejsNWAL
+GzBIU6oHIta4+xRNgik7x6eVfn6gcXuLR1mjl8J1JdFEqc4BpZUeget7Hg==
+-----END PUBLIC KEY-----
+`
+
+const testSignature = "MEUCICfXDA8cO1uaGIKy3xdqhBOBi1mYdjIQsWIDad1UhnyFAiEAgqz2qGHy2P/P+5+cq+Ic3WlacGxNt+GvUpjjjsdQ+kM=\n"
+
 func Test_BuildVFSPath(t *testing.T) {
 
 	grid := []struct {
@@ -82,3 +98,76 @@ func Test_BuildVFSPath(t *testing.T) {
 	}
 
 }
+
+func TestCopyFileSignature(t *testing.T) {
+	kubelet := []byte("kubelet binary")
+
+	grid := []struct {
+		name      string
+		signature string
+		expectErr bool
+	}{
+		{name: "signed", signature: testSignature},
+		{name: "unsigned", expectErr: true},
+		{name: "bad signature", signature: "MEUCIQ==", expectErr: true},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			vfs.Context.ResetMemfsContext(true)
+
+			write := func(location string, data []byte) {
+				p, err := vfs.Context.BuildVfsPath(location)
+				if err != nil {
+					t.Fatalf("error building path %q: %v", location, err)
+				}
+				if err := p.WriteFile(bytes.NewReader(data), nil); err != nil {
+					t.Fatalf("error writing %q: %v", location, err)
+				}
+			}
+			write("memfs://source/kubelet", kubelet)
+			if g.signature != "" {
+				write("memfs://source/kubelet.sig", []byte(g.signature))
+			}
+
+			e := &CopyFile{
+				Name:         fi.String("kubelet"),
+				SourceFile:   fi.String("memfs://source/kubelet"),
+				TargetFile:   fi.String("memfs://target/kubelet"),
+				SHA:          fi.String(fmt.Sprintf("%x", sha1.Sum(kubelet))),
+				SignatureKey: fi.String(testSignatureKey),
+			}
+			c := &fi.Context{}
+
+			err := e.Render(c, nil, e, nil)
+			if g.expectErr {
+				if err == nil {
+					t.Fatalf("expected error copying the file")
+				}
+				if _, err := vfs.Context.ReadFile("memfs://target/kubelet.sha1"); err == nil {
+					t.Errorf("hash file written although the signature was not copied")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error copying the file: %v", err)
+			}
+
+			signature, err := vfs.Context.ReadFile("memfs://target/kubelet.sig")
+			if err != nil {
+				t.Fatalf("signature not copied: %v", err)
+			}
+			if string(signature) != g.signature {
+				t.Errorf("unexpected signature %q", signature)
+			}
+
+			actual, err := e.Find(c)
+			if err != nil {
+				t.Fatalf("unexpected error finding the file: %v", err)
+			}
+			if actual == nil {
+				t.Errorf("expected the copied file to be found")
+			}
+		})
+	}
+}
@@ -65,6 +65,12 @@ type Keystore interface {
 	MirrorTo(basedir vfs.Path) error
 }
 
+// NodeCertificateIssuer is implemented by keystores that can issue a certificate for a node, without exposing a CA private key to it
+type NodeCertificateIssuer interface {
+	// IssueNodeCertificate issues a client certificate and private key with the given common name
+	IssueNodeCertificate(commonName string) (*pki.Certificate, *pki.PrivateKey, error)
+}
+
 // HasVFSPath is implemented by keystore & other stores that use a VFS path as their backing store
 type HasVFSPath interface {
 	VFSPath() vfs.Path
@@ -292,6 +292,46 @@ var zonesToCloud = map[string]kops.CloudProviderID{
 	"cn-hongkong-a": kops.CloudProviderALI,
 	"cn-hongkong-b": kops.CloudProviderALI,
 	"cn-hongkong-c": kops.CloudProviderALI,
+
+	"centralus-1": kops.CloudProviderAzure,
+	"centralus-2": kops.CloudProviderAzure,
+	"centralus-3": kops.CloudProviderAzure,
+
+	"eastus-1": kops.CloudProviderAzure,
+	"eastus-2": kops.CloudProviderAzure,
+	"eastus-3": kops.CloudProviderAzure,
+
+	"eastus2-1": kops.CloudProviderAzure,
+	"eastus2-2": kops.CloudProviderAzure,
+	"eastus2-3": kops.CloudProviderAzure,
+
+	"westus2-1": kops.CloudProviderAzure,
+	"westus2-2": kops.CloudProviderAzure,
+	"westus2-3": kops.CloudProviderAzure,
+
+	"francecentral-1": kops.CloudProviderAzure,
+	"francecentral-2": kops.CloudProviderAzure,
+	"francecentral-3": kops.CloudProviderAzure,
+
+	"northeurope-1": kops.CloudProviderAzure,
+	"northeurope-2": kops.CloudProviderAzure,
+	"northeurope-3": kops.CloudProviderAzure,
+
+	"uksouth-1": kops.CloudProviderAzure,
+	"uksouth-2": kops.CloudProviderAzure,
+	"uksouth-3": kops.CloudProviderAzure,
+
+	"westeurope-1": kops.CloudProviderAzure,
+	"westeurope-2": kops.CloudProviderAzure,
+	"westeurope-3": kops.CloudProviderAzure,
+
+	"japaneast-1": kops.CloudProviderAzure,
+	"japaneast-2": kops.CloudProviderAzure,
+	"japaneast-3": kops.CloudProviderAzure,
+
+	"southeastasia-1": kops.CloudProviderAzure,
+	"southeastasia-2": kops.CloudProviderAzure,
+	"southeastasia-3": kops.CloudProviderAzure,
 }
 
 // GuessCloudForZone tries to infer the cloudprovider from the zone name
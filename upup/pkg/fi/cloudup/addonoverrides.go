@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+// addonOverrides applies the cluster's addonOverrides to the manifests of the built-in addons,
+// keeping track of which overrides were applied
+type addonOverrides struct {
+	overrides []kops.AddonOverrideSpec
+	matched   []bool
+}
+
+func newAddonOverrides(overrides []kops.AddonOverrideSpec) *addonOverrides {
+	return &addonOverrides{
+		overrides: overrides,
+		matched:   make([]bool, len(overrides)),
+	}
+}
+
+// apply applies the overrides for the named addon to one of its manifests
+func (o *addonOverrides) apply(addon string, manifest []byte) ([]byte, error) {
+	for i := range o.overrides {
+		override := &o.overrides[i]
+		if override.Addon != addon {
+			continue
+		}
+
+		patch := &kubemanifest.Patch{
+			Kind:      override.Kind,
+			Namespace: override.Namespace,
+			Name:      override.Name,
+			Type:      kubemanifest.PatchType(override.Type),
+			Patch:     []byte(override.Patch),
+		}
+
+		patched, matched, err := kubemanifest.ApplyPatch(manifest, patch)
+		if err != nil {
+			return nil, fmt.Errorf("error applying addonOverrides to %s: %v", addon, err)
+		}
+		if matched {
+			o.matched[i] = true
+		}
+		manifest = patched
+	}
+	return manifest, nil
+}
+
+// verify returns an error if any override did not match an object in the built-in addons
+func (o *addonOverrides) verify(addons map[string]bool) error {
+	for i := range o.overrides {
+		override := &o.overrides[i]
+		if !addons[override.Addon] {
+			return fmt.Errorf("addonOverrides: addon %q is not a built-in addon of this cluster", override.Addon)
+		}
+		if !o.matched[i] {
+			return fmt.Errorf("addonOverrides: no object %s/%s found in addon %q", override.Kind, override.Name, override.Addon)
+		}
+	}
+	return nil
+}
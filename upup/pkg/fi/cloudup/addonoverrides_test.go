@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+const testAddonManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: coredns
+  namespace: kube-system
+data:
+  Corefile: |
+    .:53 {
+    }
+---
+# The deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: coredns
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: coredns
+        image: k8s.gcr.io/coredns:1.3.1
+        resources:
+          limits:
+            memory: 170Mi
+      tolerations:
+      - key: CriticalAddonsOnly
+        operator: Exists`
+
+func TestAddonOverrides(t *testing.T) {
+	overrides := newAddonOverrides([]kops.AddonOverrideSpec{
+		{
+			Addon: "coredns.addons.k8s.io",
+			Kind:  "Deployment",
+			Name:  "coredns",
+			Patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: coredns
+        resources:
+          limits:
+            memory: 256Mi
+      tolerations:
+      - key: dedicated
+        operator: Exists
+`,
+		},
+		{
+			Addon:     "coredns.addons.k8s.io",
+			Kind:      "ConfigMap",
+			Namespace: "kube-system",
+			Name:      "coredns",
+			Type:      "json",
+			Patch:     `[{"op": "add", "path": "/data/extra", "value": "example"}]`,
+		},
+		{
+			Addon: "other.addons.k8s.io",
+			Kind:  "ConfigMap",
+			Name:  "other",
+			Patch: "data:\n  a: b\n",
+		},
+	})
+
+	patched, err := overrides.apply("coredns.addons.k8s.io", []byte(testAddonManifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := string(patched)
+
+	sections := strings.Split(s, "\n---\n")
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d:\n%s", len(sections), s)
+	}
+	if !strings.Contains(sections[0], "extra: example") || !strings.Contains(sections[0], ".:53 {") {
+		t.Errorf("json patch not applied to ConfigMap:\n%s", sections[0])
+	}
+	// Containers are merged by name, but tolerations have no merge key so the list is replaced
+	for _, expected := range []string{"memory: 256Mi", "image: k8s.gcr.io/coredns:1.3.1", "key: dedicated"} {
+		if !strings.Contains(sections[1], expected) {
+			t.Errorf("expected %q in patched Deployment:\n%s", expected, sections[1])
+		}
+	}
+	if strings.Contains(sections[1], "CriticalAddonsOnly") {
+		t.Errorf("expected tolerations to be replaced:\n%s", sections[1])
+	}
+
+	unchanged, err := overrides.apply("kube-dns.addons.k8s.io", []byte(testAddonManifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(unchanged) != testAddonManifest {
+		t.Errorf("manifest of another addon was changed:\n%s", unchanged)
+	}
+
+	err = overrides.verify(map[string]bool{"coredns.addons.k8s.io": true, "kube-dns.addons.k8s.io": true})
+	if err == nil || !strings.Contains(err.Error(), `"other.addons.k8s.io" is not a built-in addon`) {
+		t.Errorf("expected error for unknown addon, got %v", err)
+	}
+	err = overrides.verify(map[string]bool{"coredns.addons.k8s.io": true, "other.addons.k8s.io": true})
+	if err == nil || !strings.Contains(err.Error(), "no object ConfigMap/other found") {
+		t.Errorf("expected error for unmatched override, got %v", err)
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/model/alimodel"
 	"k8s.io/kops/pkg/model/awsmodel"
+	"k8s.io/kops/pkg/model/azuremodel"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/components/etcdmanager"
 	"k8s.io/kops/pkg/model/domodel"
@@ -57,6 +58,8 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/aliup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azuretasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/baremetal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
@@ -72,6 +75,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/fitasks"
 	"k8s.io/kops/util/pkg/hashing"
 	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubernetes/pkg/credentialprovider"
 )
 
 const (
@@ -91,6 +95,8 @@ var (
 	AlphaAllowVsphere = featureflag.New("AlphaAllowVsphere", featureflag.Bool(false))
 	// AlphaAllowALI is a feature flag that gates aliyun support while it is alpha
 	AlphaAllowALI = featureflag.New("AlphaAllowALI", featureflag.Bool(false))
+	// AlphaAllowAzure is a feature flag that gates azure support while it is alpha
+	AlphaAllowAzure = featureflag.New("AlphaAllowAzure", featureflag.Bool(false))
 	// CloudupModels a list of supported models
 	CloudupModels = []string{"proto", "cloudup"}
 )
@@ -145,6 +151,22 @@ type ApplyClusterCmd struct {
 
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.Task
+
+	// GetAssets is true if we only want to discover the assets of the cluster, without running any tasks
+	GetAssets bool
+
+	// ImageAssets are the container images discovered when GetAssets is set (output)
+	ImageAssets []*assets.ContainerAsset
+
+	// FileAssets are the files discovered when GetAssets is set (output)
+	FileAssets []*assets.FileAsset
+
+	// FindTerraformImports is true if we only want to find the resources of the cluster which already exist,
+	// at the addresses the terraform target renders them to, without running any tasks
+	FindTerraformImports bool
+
+	// TerraformImports are the resources found when FindTerraformImports is set (output)
+	TerraformImports []*terraform.Import
 }
 
 func (c *ApplyClusterCmd) Run() error {
@@ -220,6 +242,10 @@ func (c *ApplyClusterCmd) Run() error {
 	// go dependency.
 	phase := string(c.Phase)
 	assetBuilder := assets.NewAssetBuilder(c.Cluster, phase)
+	if c.Cluster.Spec.Assets != nil && fi.BoolValue(c.Cluster.Spec.Assets.PinImageDigests) {
+		resolver := &assets.DigestResolver{Keyring: credentialprovider.NewDockerKeyring()}
+		assetBuilder.ResolveDigest = resolver.ResolveImageDigest
+	}
 	err = c.upgradeSpecs(assetBuilder)
 	if err != nil {
 		return err
@@ -491,6 +517,38 @@ func (c *ApplyClusterCmd) Run() error {
 			}
 		}
 
+	case kops.CloudProviderAzure:
+		{
+			if !AlphaAllowAzure.Enabled() {
+				return fmt.Errorf("Azure support is currently alpha, and is feature-gated.  export KOPS_FEATURE_FLAGS=AlphaAllowAzure")
+			}
+
+			azureCloud := cloud.(azureup.AzureCloud)
+			region = azureCloud.Region()
+			l.AddTypes(map[string]interface{}{
+				"ResourceGroup":        &azuretasks.ResourceGroup{},
+				"VirtualNetwork":       &azuretasks.VirtualNetwork{},
+				"Subnet":               &azuretasks.Subnet{},
+				"NetworkSecurityGroup": &azuretasks.NetworkSecurityGroup{},
+				"RouteTable":           &azuretasks.RouteTable{},
+				"PublicIPAddress":      &azuretasks.PublicIPAddress{},
+				"LoadBalancer":         &azuretasks.LoadBalancer{},
+				"VMScaleSet":           &azuretasks.VMScaleSet{},
+				"Disk":                 &azuretasks.Disk{},
+				"RoleAssignment":       &azuretasks.RoleAssignment{},
+			})
+
+			if len(sshPublicKeys) == 0 {
+				return fmt.Errorf("SSH public key must be specified when running with Azure (create with `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub`)", cluster.ObjectMeta.Name)
+			}
+
+			modelContext.SSHPublicKeys = sshPublicKeys
+
+			if len(sshPublicKeys) != 1 {
+				return fmt.Errorf("Exactly one 'admin' SSH public key can be specified when running with Azure; please delete a key using `kops delete secret`")
+			}
+		}
+
 	case kops.CloudProviderVSphere:
 		{
 			if !AlphaAllowVsphere.Enabled() {
@@ -684,6 +742,16 @@ func (c *ApplyClusterCmd) Run() error {
 					&alimodel.ExternalAccessModelBuilder{ALIModelContext: aliModelContext, Lifecycle: &clusterLifecycle},
 				)
 
+			case kops.CloudProviderAzure:
+				azureModelContext := &azuremodel.AzureModelContext{
+					KopsModelContext: modelContext,
+				}
+				l.Builders = append(l.Builders,
+					&model.MasterVolumeBuilder{KopsModelContext: modelContext, Lifecycle: &clusterLifecycle},
+					&azuremodel.APILoadBalancerModelBuilder{AzureModelContext: azureModelContext, Lifecycle: &clusterLifecycle},
+					&azuremodel.NetworkModelBuilder{AzureModelContext: azureModelContext, Lifecycle: &networkLifecycle},
+				)
+
 			case kops.CloudProviderVSphere:
 				// No special settings (yet!)
 
@@ -783,6 +851,19 @@ func (c *ApplyClusterCmd) Run() error {
 			})
 		}
 
+	case kops.CloudProviderAzure:
+		{
+			azureModelContext := &azuremodel.AzureModelContext{
+				KopsModelContext: modelContext,
+			}
+
+			l.Builders = append(l.Builders, &azuremodel.VMScaleSetModelBuilder{
+				AzureModelContext: azureModelContext,
+				BootstrapScript:   bootstrapScriptBuilder,
+				Lifecycle:         &clusterLifecycle,
+			})
+		}
+
 	case kops.CloudProviderVSphere:
 		{
 			vsphereModelContext := &vspheremodel.VSphereModelContext{
@@ -828,6 +909,30 @@ func (c *ApplyClusterCmd) Run() error {
 
 	c.TaskMap = taskMap
 
+	if c.GetAssets {
+		c.ImageAssets = assetBuilder.ContainerAssets
+		c.FileAssets = assetBuilder.FileAssets
+		return nil
+	}
+
+	if c.FindTerraformImports {
+		tf := terraform.NewTerraformTarget(cloud, region, project, c.OutDir, cluster.Spec.Target)
+		tf.ClusterName = cluster.ObjectMeta.Name
+
+		context, err := fi.NewContext(tf, cluster, cloud, keyStore, secretStore, configBase, true, taskMap)
+		if err != nil {
+			return fmt.Errorf("error building context: %v", err)
+		}
+		defer context.Close()
+
+		imports, err := terraform.FindImports(context)
+		if err != nil {
+			return fmt.Errorf("error finding existing resources: %v", err)
+		}
+		c.TerraformImports = imports
+		return nil
+	}
+
 	var target fi.Target
 	dryRun := false
 	shouldPrecreateDNS := true
@@ -849,14 +954,18 @@ func (c *ApplyClusterCmd) Run() error {
 			target = openstack.NewOpenstackAPITarget(cloud.(openstack.OpenstackCloud))
 		case kops.CloudProviderALI:
 			target = aliup.NewALIAPITarget(cloud.(aliup.ALICloud))
+		case kops.CloudProviderAzure:
+			target = azureup.NewAzureAPITarget(cloud.(azureup.AzureCloud))
 		default:
 			return fmt.Errorf("direct configuration not supported with CloudProvider:%q", cluster.Spec.CloudProvider)
 		}
 
-	case TargetTerraform:
+	case TargetTerraform, TargetTerraformModule:
 		checkExisting = false
 		outDir := c.OutDir
 		tf := terraform.NewTerraformTarget(cloud, region, project, outDir, cluster.Spec.Target)
+		tf.ClusterName = cluster.ObjectMeta.Name
+		tf.Module = c.TargetName == TargetTerraformModule
 
 		// We include a few "util" variables in the TF output
 		if err := tf.AddOutputVariable("region", terraform.LiteralFromStringValue(region)); err != nil {
@@ -1262,7 +1371,10 @@ func (c *ApplyClusterCmd) BuildNodeUpConfig(assetBuilder *assets.AssetBuilder, i
 	}
 
 	for i := range c.Cluster.Spec.Addons {
-		channels = append(channels, c.Cluster.Spec.Addons[i].Manifest)
+		// Chart addons are included in the bootstrap channel
+		if c.Cluster.Spec.Addons[i].Manifest != "" {
+			channels = append(channels, c.Cluster.Spec.Addons[i].Manifest)
+		}
 	}
 
 	role := ig.Spec.Role
@@ -1344,5 +1456,42 @@ func (c *ApplyClusterCmd) BuildNodeUpConfig(assetBuilder *assets.AssetBuilder, i
 	config.Images = images
 	config.Channels = channels
 
+	if cluster.Spec.Assets != nil {
+		if fi.BoolValue(cluster.Spec.Assets.PinImageDigests) {
+			// The images of the cluster spec are pinned when it is populated, but nodeup also runs
+			// images the spec holds verbatim; we record the digests of those in the nodeup config
+			var images []string
+			for _, hooks := range [][]kops.HookSpec{cluster.Spec.Hooks, ig.Spec.Hooks} {
+				for _, hook := range hooks {
+					if hook.ExecContainer != nil {
+						images = append(images, hook.ExecContainer.Image)
+					}
+				}
+			}
+			if cluster.Spec.NodeAuthorization != nil && cluster.Spec.NodeAuthorization.NodeAuthorizer != nil {
+				images = append(images, cluster.Spec.NodeAuthorization.NodeAuthorizer.Image)
+			}
+			if cluster.Spec.EncryptionAtRest != nil && cluster.Spec.EncryptionAtRest.KMS != nil {
+				images = append(images, cluster.Spec.EncryptionAtRest.KMS.Image)
+			}
+			for _, image := range images {
+				image = strings.TrimSpace(image)
+				if image == "" {
+					continue
+				}
+				pinned, err := assetBuilder.PinImage(image)
+				if err != nil {
+					return nil, err
+				}
+				if config.ImageDigests == nil {
+					config.ImageDigests = make(map[string]string)
+				}
+				config.ImageDigests[image] = pinned
+			}
+			config.RequireImageDigests = true
+		}
+		config.FileSignatureKey = fi.StringValue(cluster.Spec.Assets.FileSignatureKey)
+	}
+
 	return config, nil
 }
@@ -755,6 +755,12 @@ func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 	return t.RenderResource("aws_autoscaling_group", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &AutoscalingGroup{}
+
+func (e *AutoscalingGroup) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.Name)
+}
+
 // TerraformLink fills in the property
 func (e *AutoscalingGroup) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_autoscaling_group", fi.StringValue(e.Name), "id")
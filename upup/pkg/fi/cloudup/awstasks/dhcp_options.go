@@ -187,6 +187,12 @@ func (_ *DHCPOptions) RenderTerraform(t *terraform.TerraformTarget, a, e, change
 	return t.RenderResource("aws_vpc_dhcp_options", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &DHCPOptions{}
+
+func (e *DHCPOptions) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *DHCPOptions) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_vpc_dhcp_options", *e.Name, "id")
 }
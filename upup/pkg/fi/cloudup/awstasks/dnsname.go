@@ -221,6 +221,13 @@ func (_ *DNSName) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *D
 	return t.RenderResource("aws_route53_record", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &DNSName{}
+
+func (e *DNSName) TerraformImportID(t *terraform.TerraformTarget) string {
+	// Records are imported as zone_name_type
+	return fi.StringValue(e.Zone.ZoneID) + "_" + fi.StringValue(e.Name) + "_" + fi.StringValue(e.ResourceType)
+}
+
 func (e *DNSName) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("aws_route53_record", *e.Name)
 }
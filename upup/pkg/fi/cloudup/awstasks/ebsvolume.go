@@ -220,6 +220,12 @@ func (_ *EBSVolume) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_ebs_volume", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &EBSVolume{}
+
+func (e *EBSVolume) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *EBSVolume) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("aws_ebs_volume", *e.Name)
 }
@@ -292,6 +292,12 @@ func (_ *ElasticIP) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_eip", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &ElasticIP{}
+
+func (e *ElasticIP) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *ElasticIP) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_eip", *e.Name, "id")
 }
@@ -125,6 +125,12 @@ func (_ *IAMInstanceProfileRole) RenderTerraform(t *terraform.TerraformTarget, a
 	return t.RenderResource("aws_iam_instance_profile", *e.InstanceProfile.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &IAMInstanceProfileRole{}
+
+func (e *IAMInstanceProfileRole) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.InstanceProfile.Name)
+}
+
 type cloudformationIAMInstanceProfile struct {
 	//Path  *string              `json:"name"`
 	Roles []*cloudformation.Literal `json:"Roles"`
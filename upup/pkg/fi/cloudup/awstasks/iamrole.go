@@ -213,6 +213,12 @@ func (_ *IAMRole) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *I
 	return t.RenderResource("aws_iam_role", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &IAMRole{}
+
+func (e *IAMRole) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.Name)
+}
+
 func (e *IAMRole) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_iam_role", *e.Name, "name")
 }
@@ -222,6 +222,13 @@ func (_ *IAMRolePolicy) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_iam_role_policy", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &IAMRolePolicy{}
+
+func (e *IAMRolePolicy) TerraformImportID(t *terraform.TerraformTarget) string {
+	// Role policies are imported as role:policy
+	return fi.StringValue(e.Role.Name) + ":" + fi.StringValue(e.Name)
+}
+
 func (e *IAMRolePolicy) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("aws_iam_role_policy", *e.Name)
 }
@@ -216,6 +216,12 @@ func (_ *InternetGateway) RenderTerraform(t *terraform.TerraformTarget, a, e, ch
 	return t.RenderResource("aws_internet_gateway", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &InternetGateway{}
+
+func (e *InternetGateway) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *InternetGateway) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
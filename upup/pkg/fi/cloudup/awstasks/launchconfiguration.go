@@ -48,6 +48,48 @@ func RetainLaunchConfigurationCount() int {
 	return defaultRetainLaunchConfigurationCount
 }
 
+// findInUseLaunchConfigurations returns the launch configurations and launch templates referenced by the autoscaling
+// group or any of its instances. These are never garbage collected, so that a group can be rolled back to the
+// configuration its existing instances were launched from.
+func findInUseLaunchConfigurations(c *fi.Context, groupName string) (sets.String, error) {
+	cloud := c.Cloud.(awsup.AWSCloud)
+
+	inUse := sets.NewString()
+
+	request := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(groupName)},
+	}
+	err := cloud.Autoscaling().DescribeAutoScalingGroupsPages(request, func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
+		for _, g := range page.AutoScalingGroups {
+			if name := aws.StringValue(g.LaunchConfigurationName); name != "" {
+				inUse.Insert(name)
+			}
+			if g.LaunchTemplate != nil {
+				inUse.Insert(aws.StringValue(g.LaunchTemplate.LaunchTemplateName))
+			}
+			if g.MixedInstancesPolicy != nil && g.MixedInstancesPolicy.LaunchTemplate != nil {
+				if spec := g.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification; spec != nil {
+					inUse.Insert(aws.StringValue(spec.LaunchTemplateName))
+				}
+			}
+			for _, i := range g.Instances {
+				if name := aws.StringValue(i.LaunchConfigurationName); name != "" {
+					inUse.Insert(name)
+				}
+				if i.LaunchTemplate != nil {
+					inUse.Insert(aws.StringValue(i.LaunchTemplate.LaunchTemplateName))
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing autoscaling group %q: %v", groupName, err)
+	}
+
+	return inUse, nil
+}
+
 // LaunchConfiguration is the specification for a launch configuration
 type LaunchConfiguration struct {
 	// Name is the name of the configuration
@@ -546,6 +588,12 @@ func (_ *LaunchConfiguration) RenderTerraform(t *terraform.TerraformTarget, a, e
 	return t.RenderResource("aws_launch_configuration", fi.StringValue(e.Name), tf)
 }
 
+var _ terraform.HasTerraformImportID = &LaunchConfiguration{}
+
+func (e *LaunchConfiguration) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 // TerraformLink returns the terraform reference
 func (e *LaunchConfiguration) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_launch_configuration", fi.StringValue(e.Name), "id")
@@ -764,7 +812,16 @@ func (e *LaunchConfiguration) FindDeletions(c *fi.Context) ([]fi.Deletion, error
 
 	configurations = configurations[:len(configurations)-RetainLaunchConfigurationCount()]
 
+	inUse, err := findInUseLaunchConfigurations(c, fi.StringValue(e.Name))
+	if err != nil {
+		return nil, err
+	}
+
 	for _, configuration := range configurations {
+		if inUse.Has(aws.StringValue(configuration.LaunchConfigurationName)) {
+			klog.V(2).Infof("retaining launch configuration %q as it is still in use", aws.StringValue(configuration.LaunchConfigurationName))
+			continue
+		}
 		removals = append(removals, &deleteLaunchConfiguration{lc: configuration})
 	}
 
@@ -17,6 +17,7 @@ limitations under the License.
 package awstasks
 
 import (
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
@@ -116,3 +118,48 @@ func TestLaunchConfigurationGarbageCollection(t *testing.T) {
 		}
 	}
 }
+
+func TestFindInUseLaunchConfigurations(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	cloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{
+		Groups: map[string]*autoscaling.Group{
+			"nodes": {
+				AutoScalingGroupName: aws.String("nodes"),
+				MixedInstancesPolicy: &autoscaling.MixedInstancesPolicy{
+					LaunchTemplate: &autoscaling.LaunchTemplate{
+						LaunchTemplateSpecification: &autoscaling.LaunchTemplateSpecification{
+							LaunchTemplateName: aws.String("nodes-2"),
+						},
+					},
+				},
+				Instances: []*autoscaling.Instance{
+					{
+						InstanceId: aws.String("i-1"),
+						LaunchTemplate: &autoscaling.LaunchTemplateSpecification{
+							LaunchTemplateName: aws.String("nodes-1"),
+						},
+					},
+					{
+						InstanceId:              aws.String("i-2"),
+						LaunchConfigurationName: aws.String("nodes-0"),
+					},
+				},
+			},
+		},
+	}
+
+	context, err := fi.NewContext(&awsup.AWSAPITarget{Cloud: cloud}, nil, cloud, nil, nil, nil, true, nil)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+
+	inUse, err := findInUseLaunchConfigurations(context, "nodes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"nodes-0", "nodes-1", "nodes-2"}
+	if !reflect.DeepEqual(inUse.List(), expected) {
+		t.Errorf("in use was %v, expected %v", inUse.List(), expected)
+	}
+}
@@ -154,7 +154,16 @@ func (t *LaunchTemplate) FindDeletions(c *fi.Context) ([]fi.Deletion, error) {
 
 	configurations = configurations[:len(configurations)-RetainLaunchConfigurationCount()]
 
+	inUse, err := findInUseLaunchConfigurations(c, fi.StringValue(t.Name))
+	if err != nil {
+		return nil, err
+	}
+
 	for _, configuration := range configurations {
+		if inUse.Has(aws.StringValue(configuration.LaunchTemplateName)) {
+			klog.V(2).Infof("retaining launch template %q as it is still in use", aws.StringValue(configuration.LaunchTemplateName))
+			continue
+		}
 		removals = append(removals, &deleteLaunchTemplate{lc: configuration})
 	}
 
@@ -773,6 +773,12 @@ func (_ *LoadBalancer) RenderTerraform(t *terraform.TerraformTarget, a, e, chang
 	return t.RenderResource("aws_elb", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &LoadBalancer{}
+
+func (e *LoadBalancer) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.LoadBalancerName)
+}
+
 func (e *LoadBalancer) TerraformLink(params ...string) *terraform.Literal {
 	prop := "id"
 	if len(params) > 0 {
@@ -249,6 +249,13 @@ func (_ *Route) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Rou
 	return t.RenderResource("aws_route", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &Route{}
+
+func (e *Route) TerraformImportID(t *terraform.TerraformTarget) string {
+	// Routes are imported as routetable_destination
+	return fi.StringValue(e.RouteTable.ID) + "_" + fi.StringValue(e.CIDR)
+}
+
 type cloudformationRoute struct {
 	RouteTableID      *cloudformation.Literal `json:"RouteTableId"`
 	CIDR              *string                 `json:"DestinationCidrBlock,omitempty"`
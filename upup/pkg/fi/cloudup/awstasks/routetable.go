@@ -211,6 +211,12 @@ func (_ *RouteTable) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("aws_route_table", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &RouteTable{}
+
+func (e *RouteTable) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *RouteTable) TerraformLink() *terraform.Literal {
 	return terraform.LiteralProperty("aws_route_table", *e.Name, "id")
 }
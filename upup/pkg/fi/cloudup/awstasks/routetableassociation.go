@@ -200,6 +200,13 @@ func (_ *RouteTableAssociation) RenderTerraform(t *terraform.TerraformTarget, a,
 	return t.RenderResource("aws_route_table_association", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &RouteTableAssociation{}
+
+func (e *RouteTableAssociation) TerraformImportID(t *terraform.TerraformTarget) string {
+	// Route table associations are imported as subnet/routetable
+	return fi.StringValue(e.Subnet.ID) + "/" + fi.StringValue(e.RouteTable.ID)
+}
+
 func (e *RouteTableAssociation) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("aws_route_table_association", *e.Name)
 }
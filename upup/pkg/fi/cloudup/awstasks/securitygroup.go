@@ -206,6 +206,12 @@ func (_ *SecurityGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, chan
 	return t.RenderResource("aws_security_group", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &SecurityGroup{}
+
+func (e *SecurityGroup) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *SecurityGroup) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
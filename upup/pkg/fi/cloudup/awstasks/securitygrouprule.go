@@ -339,6 +339,38 @@ func (_ *SecurityGroupRule) RenderTerraform(t *terraform.TerraformTarget, a, e,
 	return t.RenderResource("aws_security_group_rule", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &SecurityGroupRule{}
+
+func (e *SecurityGroupRule) TerraformImportID(t *terraform.TerraformTarget) string {
+	// Rules are imported as group_type_protocol_from_to_source
+	ruleType := "ingress"
+	if fi.BoolValue(e.Egress) {
+		ruleType = "egress"
+	}
+
+	protocol := fi.StringValue(e.Protocol)
+	fromPort := int64(0)
+	toPort := int64(65535)
+	if protocol == "" || protocol == "-1" {
+		protocol = "all"
+		toPort = 65536
+	} else {
+		if e.FromPort != nil {
+			fromPort = *e.FromPort
+		}
+		if e.ToPort != nil {
+			toPort = *e.ToPort
+		}
+	}
+
+	source := fi.StringValue(e.CIDR)
+	if e.SourceGroup != nil {
+		source = fi.StringValue(e.SourceGroup.ID)
+	}
+
+	return fmt.Sprintf("%s_%s_%s_%d_%d_%s", fi.StringValue(e.SecurityGroup.ID), ruleType, protocol, fromPort, toPort, source)
+}
+
 type cloudformationSecurityGroupIngress struct {
 	SecurityGroup *cloudformation.Literal `json:"GroupId,omitempty"`
 	SourceGroup   *cloudformation.Literal `json:"SourceSecurityGroupId,omitempty"`
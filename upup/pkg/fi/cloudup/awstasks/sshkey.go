@@ -189,6 +189,12 @@ func (_ *SSHKey) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *SS
 	return t.RenderResource("aws_key_pair", tfName, tf)
 }
 
+var _ terraform.HasTerraformImportID = &SSHKey{}
+
+func (e *SSHKey) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.Name)
+}
+
 // IsExistingKey will be true if the task has been initialized without using a public key
 // this is when we want to use a key that is already present in AWS.
 func (e *SSHKey) IsExistingKey() bool {
@@ -249,6 +249,12 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 	return t.RenderResource("aws_subnet", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &Subnet{}
+
+func (e *Subnet) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *Subnet) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -227,6 +227,12 @@ func (_ *VPC) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *VPC)
 	return t.RenderResource("aws_vpc", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &VPC{}
+
+func (e *VPC) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
 func (e *VPC) TerraformLink() *terraform.Literal {
 	shared := fi.BoolValue(e.Shared)
 	if shared {
@@ -152,6 +152,10 @@ type AWSCloud interface {
 
 	// FindClusterStatus gets the status of the cluster as it exists in AWS, inferred from volumes
 	FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error)
+
+	// RollbackCloudGroup points the autoscaling group back at the launch configuration (or launch template version)
+	// that its out-of-date instances were launched from
+	RollbackCloudGroup(g *cloudinstances.CloudInstanceGroup) error
 }
 
 type awsCloudImplementation struct {
@@ -400,6 +404,94 @@ func deleteGroup(c AWSCloud, g *cloudinstances.CloudInstanceGroup) error {
 	return nil
 }
 
+// RollbackCloudGroup reverts an aws autoscaling group to its previous launch configuration or template
+func (c *awsCloudImplementation) RollbackCloudGroup(g *cloudinstances.CloudInstanceGroup) error {
+	if c.spotinst != nil {
+		return fmt.Errorf("rollback of instance group %q is not supported with spotinst", g.HumanName)
+	}
+
+	return rollbackCloudGroup(c, g)
+}
+
+func rollbackCloudGroup(c AWSCloud, g *cloudinstances.CloudInstanceGroup) error {
+	asg, ok := g.Raw.(*autoscaling.Group)
+	if !ok || asg == nil {
+		return fmt.Errorf("cloud group %q is not backed by an autoscaling group", g.HumanName)
+	}
+	name := aws.StringValue(asg.AutoScalingGroupName)
+
+	current, err := findAutoscalingGroupLaunchConfiguration(asg)
+	if err != nil {
+		return err
+	}
+
+	// The previous specification is whatever the instances still needing an update were launched from
+	needUpdate := make(map[string]bool)
+	for _, m := range g.NeedUpdate {
+		needUpdate[m.ID] = true
+	}
+	previous := ""
+	for _, i := range asg.Instances {
+		if !needUpdate[aws.StringValue(i.InstanceId)] {
+			continue
+		}
+		if spec := findInstanceLaunchConfiguration(i); spec != "" && spec != current {
+			previous = spec
+			break
+		}
+	}
+	if previous == "" {
+		return fmt.Errorf("unable to determine previous launch configuration for autoscaling group %q", name)
+	}
+
+	request := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: asg.AutoScalingGroupName,
+	}
+
+	switch {
+	case aws.StringValue(asg.LaunchConfigurationName) != "":
+		request.LaunchConfigurationName = aws.String(previous)
+	case asg.LaunchTemplate != nil:
+		templateName, version := splitLaunchTemplate(previous)
+		request.LaunchTemplate = &autoscaling.LaunchTemplateSpecification{
+			LaunchTemplateName: aws.String(templateName),
+			Version:            version,
+		}
+	case asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil:
+		templateName, version := splitLaunchTemplate(previous)
+		request.MixedInstancesPolicy = &autoscaling.MixedInstancesPolicy{
+			LaunchTemplate: &autoscaling.LaunchTemplate{
+				LaunchTemplateSpecification: &autoscaling.LaunchTemplateSpecification{
+					LaunchTemplateName: aws.String(templateName),
+					Version:            version,
+				},
+				Overrides: asg.MixedInstancesPolicy.LaunchTemplate.Overrides,
+			},
+		}
+	default:
+		return fmt.Errorf("unable to determine launch configuration type for autoscaling group %q", name)
+	}
+
+	klog.Infof("Rolling back autoscaling group %q from %q to %q", name, current, previous)
+	if _, err := c.Autoscaling().UpdateAutoScalingGroup(request); err != nil {
+		return fmt.Errorf("error rolling back autoscaling group %q: %v", name, err)
+	}
+
+	return nil
+}
+
+// splitLaunchTemplate splits a name:version reference as produced by findInstanceLaunchConfiguration
+func splitLaunchTemplate(spec string) (string, *string) {
+	i := strings.LastIndex(spec, ":")
+	if i == -1 {
+		return spec, nil
+	}
+	if spec[i+1:] == "" {
+		return spec[:i], nil
+	}
+	return spec[:i], aws.String(spec[i+1:])
+}
+
 // DeleteInstance deletes an aws instance
 func (c *awsCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstanceGroupMember) error {
 	if c.spotinst != nil {
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// Disk is a managed disk, used for etcd volumes
+//go:generate fitask -type=Disk
+type Disk struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup *ResourceGroup
+	Tags          map[string]string
+
+	SizeGB *int32
+	// VolumeType is the storage SKU, e.g. Premium_LRS
+	VolumeType *string
+	// Zones holds the availability zone of a zonal disk
+	Zones []string
+}
+
+var _ fi.CompareWithID = &Disk{}
+
+func (e *Disk) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *Disk) Find(c *fi.Context) (*Disk, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	disks, err := cloud.Disk().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing disks: %v", err)
+	}
+
+	for _, disk := range disks {
+		if disk.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		actual := &Disk{
+			Name:          fi.String(disk.Name),
+			Lifecycle:     e.Lifecycle,
+			ResourceGroup: &ResourceGroup{Name: e.ResourceGroup.Name},
+			Tags:          disk.Tags,
+			SizeGB:        fi.Int32(disk.Properties.DiskSizeGB),
+			Zones:         disk.Zones,
+		}
+		if disk.Sku != nil {
+			actual.VolumeType = fi.String(disk.Sku.Name)
+		}
+		klog.V(4).Infof("found matching disk %q", disk.Name)
+		return actual, nil
+	}
+	return nil, nil
+}
+
+func (e *Disk) Run(c *fi.Context) error {
+	c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *Disk) CheckChanges(a, e, changes *Disk) error {
+	if e.Name == nil {
+		return fi.RequiredField("Name")
+	}
+	if e.SizeGB == nil {
+		return fi.RequiredField("SizeGB")
+	}
+	if a != nil {
+		if changes.SizeGB != nil {
+			return fi.CannotChangeField("SizeGB")
+		}
+		if changes.VolumeType != nil {
+			return fi.CannotChangeField("VolumeType")
+		}
+		if changes.Zones != nil {
+			return fi.CannotChangeField("Zones")
+		}
+	}
+	return nil
+}
+
+func (_ *Disk) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *Disk) error {
+	if a == nil {
+		klog.V(2).Infof("Creating disk %q", fi.StringValue(e.Name))
+	} else {
+		klog.V(2).Infof("Updating disk %q", fi.StringValue(e.Name))
+	}
+
+	disk := azureup.Disk{
+		Location: t.Cloud.Region(),
+		Tags:     e.Tags,
+		Zones:    e.Zones,
+		Properties: azureup.DiskProperties{
+			DiskSizeGB: fi.Int32Value(e.SizeGB),
+			CreationData: &azureup.CreationData{
+				CreateOption: "Empty",
+			},
+		},
+	}
+	if e.VolumeType != nil {
+		disk.Sku = &azureup.Sku{Name: fi.StringValue(e.VolumeType)}
+	}
+	return t.Cloud.Disk().CreateOrUpdate(context.TODO(), fi.StringValue(e.ResourceGroup.Name), fi.StringValue(e.Name), disk)
+}
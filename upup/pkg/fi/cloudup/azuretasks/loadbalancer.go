@@ -0,0 +1,198 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+const (
+	// LoadBalancerFrontendName is the name of the frontend IP configuration of load balancers we create
+	LoadBalancerFrontendName = "frontend"
+	// LoadBalancerBackendPoolName is the name of the backend address pool of load balancers we create
+	LoadBalancerBackendPoolName = "backend"
+	// loadBalancerProbeName is the name of the health probe of load balancers we create
+	loadBalancerProbeName = "probe"
+)
+
+// LoadBalancer is a TCP load balancer with a single frontend, forwarding a single port to a backend address pool
+//go:generate fitask -type=LoadBalancer
+type LoadBalancer struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup *ResourceGroup
+	Tags          map[string]string
+
+	// PublicIPAddress is the frontend of an internet-facing load balancer
+	PublicIPAddress *PublicIPAddress
+	// Subnet is the subnet of the frontend of an internal load balancer
+	Subnet *Subnet
+	// Port is forwarded to the same port on the backends
+	Port *int32
+}
+
+var _ fi.CompareWithID = &LoadBalancer{}
+
+func (e *LoadBalancer) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *LoadBalancer) Find(c *fi.Context) (*LoadBalancer, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	lbs, err := cloud.LoadBalancer().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing load balancers: %v", err)
+	}
+
+	for _, lb := range lbs {
+		if lb.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		actual := &LoadBalancer{
+			Name:          fi.String(lb.Name),
+			Lifecycle:     e.Lifecycle,
+			ResourceGroup: &ResourceGroup{Name: e.ResourceGroup.Name},
+			Tags:          lb.Tags,
+		}
+		for _, fe := range lb.Properties.FrontendIPConfigurations {
+			if pip := fe.Properties.PublicIPAddress; pip != nil {
+				actual.PublicIPAddress = &PublicIPAddress{Name: fi.String(lastComponent(pip.ID))}
+			}
+			if subnet := fe.Properties.Subnet; subnet != nil {
+				actual.Subnet = &Subnet{Name: fi.String(lastComponent(subnet.ID))}
+			}
+		}
+		for _, rule := range lb.Properties.LoadBalancingRules {
+			actual.Port = fi.Int32(rule.Properties.FrontendPort)
+		}
+		klog.V(4).Infof("found matching load balancer %q", lb.Name)
+		return actual, nil
+	}
+	return nil, nil
+}
+
+var _ fi.HasAddress = &LoadBalancer{}
+
+// FindIPAddress returns the private frontend address of an internal load balancer, so that it can be included in certificates
+func (e *LoadBalancer) FindIPAddress(c *fi.Context) (*string, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	lbs, err := cloud.LoadBalancer().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing load balancers: %v", err)
+	}
+	for _, lb := range lbs {
+		if lb.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		for _, fe := range lb.Properties.FrontendIPConfigurations {
+			if fe.Properties.PrivateIPAddress != "" {
+				return fi.String(fe.Properties.PrivateIPAddress), nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (e *LoadBalancer) Run(c *fi.Context) error {
+	c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *LoadBalancer) CheckChanges(a, e, changes *LoadBalancer) error {
+	if e.Name == nil {
+		return fi.RequiredField("Name")
+	}
+	if e.Port == nil {
+		return fi.RequiredField("Port")
+	}
+	if (e.PublicIPAddress == nil) == (e.Subnet == nil) {
+		return fmt.Errorf("exactly one of PublicIPAddress and Subnet must be set for load balancer %q", fi.StringValue(e.Name))
+	}
+	return nil
+}
+
+func (_ *LoadBalancer) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *LoadBalancer) error {
+	if a == nil {
+		klog.V(2).Infof("Creating load balancer %q", fi.StringValue(e.Name))
+	} else {
+		klog.V(2).Infof("Updating load balancer %q", fi.StringValue(e.Name))
+	}
+
+	rg := fi.StringValue(e.ResourceGroup.Name)
+	id := azureup.ResourceID(t.Cloud.SubscriptionID(), rg, "Microsoft.Network/loadBalancers", fi.StringValue(e.Name))
+	port := fi.Int32Value(e.Port)
+
+	frontend := &azureup.FrontendIPConfiguration{
+		Name: LoadBalancerFrontendName,
+	}
+	if e.PublicIPAddress != nil {
+		frontend.Properties.PublicIPAddress = &azureup.SubResource{
+			ID: azureup.ResourceID(t.Cloud.SubscriptionID(), rg, "Microsoft.Network/publicIPAddresses", fi.StringValue(e.PublicIPAddress.Name)),
+		}
+	} else {
+		frontend.Properties.PrivateIPAllocationMethod = "Dynamic"
+		frontend.Properties.Subnet = &azureup.SubResource{
+			ID: azureup.ResourceID(t.Cloud.SubscriptionID(), rg, "Microsoft.Network/virtualNetworks", fi.StringValue(e.Subnet.VirtualNetwork.Name), "subnets", fi.StringValue(e.Subnet.Name)),
+		}
+	}
+
+	lb := azureup.LoadBalancer{
+		Location: t.Cloud.Region(),
+		Tags:     e.Tags,
+		Sku:      &azureup.Sku{Name: "Standard"},
+		Properties: azureup.LoadBalancerProperties{
+			FrontendIPConfigurations: []*azureup.FrontendIPConfiguration{frontend},
+			BackendAddressPools: []*azureup.BackendAddressPool{
+				{Name: LoadBalancerBackendPoolName},
+			},
+			Probes: []*azureup.Probe{
+				{
+					Name: loadBalancerProbeName,
+					Properties: azureup.ProbeProperties{
+						Protocol:          "Tcp",
+						Port:              port,
+						IntervalInSeconds: 15,
+						NumberOfProbes:    4,
+					},
+				},
+			},
+			LoadBalancingRules: []*azureup.LoadBalancingRule{
+				{
+					Name: fmt.Sprintf("tcp-%d", port),
+					Properties: azureup.LoadBalancingRuleProperties{
+						Protocol:                "Tcp",
+						FrontendPort:            port,
+						BackendPort:             port,
+						IdleTimeoutInMinutes:    30,
+						FrontendIPConfiguration: &azureup.SubResource{ID: id + "/frontendIPConfigurations/" + LoadBalancerFrontendName},
+						BackendAddressPool:      &azureup.SubResource{ID: id + "/backendAddressPools/" + LoadBalancerBackendPoolName},
+						Probe:                   &azureup.SubResource{ID: id + "/probes/" + loadBalancerProbeName},
+					},
+				},
+			},
+		},
+	}
+	return t.Cloud.LoadBalancer().CreateOrUpdate(context.TODO(), rg, fi.StringValue(e.Name), lb)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+//go:generate fitask -type=NetworkSecurityGroup
+type NetworkSecurityGroup struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup *ResourceGroup
+	Tags          map[string]string
+	SecurityRules []*NetworkSecurityRule
+}
+
+// NetworkSecurityRule is a rule in a NetworkSecurityGroup
+type NetworkSecurityRule struct {
+	Name *string
+	// Priority orders the rules; it must be unique, between 100 and 4096
+	Priority  *int32
+	Direction *string
+	Access    *string
+	// Protocol is one of Tcp, Udp or *
+	Protocol                 *string
+	SourceAddressPrefix      *string
+	SourcePortRange          *string
+	DestinationAddressPrefix *string
+	DestinationPortRange     *string
+}
+
+var _ fi.HasDependencies = &NetworkSecurityRule{}
+
+// GetDependencies implements fi.HasDependencies; a rule does not reference other tasks
+func (e *NetworkSecurityRule) GetDependencies(tasks map[string]fi.Task) []fi.Task {
+	return nil
+}
+
+var _ fi.CompareWithID = &NetworkSecurityGroup{}
+
+func (e *NetworkSecurityGroup) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *NetworkSecurityGroup) Find(c *fi.Context) (*NetworkSecurityGroup, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	nsgs, err := cloud.NetworkSecurityGroup().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing network security groups: %v", err)
+	}
+
+	for _, nsg := range nsgs {
+		if nsg.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		actual := &NetworkSecurityGroup{
+			Name:          fi.String(nsg.Name),
+			Lifecycle:     e.Lifecycle,
+			ResourceGroup: &ResourceGroup{Name: e.ResourceGroup.Name},
+			Tags:          nsg.Tags,
+		}
+		for _, rule := range nsg.Properties.SecurityRules {
+			p := rule.Properties
+			actual.SecurityRules = append(actual.SecurityRules, &NetworkSecurityRule{
+				Name:                     fi.String(rule.Name),
+				Priority:                 fi.Int32(p.Priority),
+				Direction:                fi.String(p.Direction),
+				Access:                   fi.String(p.Access),
+				Protocol:                 fi.String(p.Protocol),
+				SourceAddressPrefix:      fi.String(p.SourceAddressPrefix),
+				SourcePortRange:          fi.String(p.SourcePortRange),
+				DestinationAddressPrefix: fi.String(p.DestinationAddressPrefix),
+				DestinationPortRange:     fi.String(p.DestinationPortRange),
+			})
+		}
+		sortSecurityRules(actual.SecurityRules)
+		klog.V(4).Infof("found matching network security group %q", nsg.Name)
+		return actual, nil
+	}
+	return nil, nil
+}
+
+func (e *NetworkSecurityGroup) Run(c *fi.Context) error {
+	sortSecurityRules(e.SecurityRules)
+	c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *NetworkSecurityGroup) CheckChanges(a, e, changes *NetworkSecurityGroup) error {
+	if e.Name == nil {
+		return fi.RequiredField("Name")
+	}
+	priorities := make(map[string]int32)
+	for _, rule := range e.SecurityRules {
+		if rule.Name == nil {
+			return fi.RequiredField("SecurityRules.Name")
+		}
+		if rule.Priority == nil {
+			return fi.RequiredField("SecurityRules.Priority")
+		}
+		key := fi.StringValue(rule.Direction) + "/" + fmt.Sprintf("%d", *rule.Priority)
+		if _, found := priorities[key]; found {
+			return fmt.Errorf("network security group %q has multiple %s rules with priority %d", fi.StringValue(e.Name), fi.StringValue(rule.Direction), *rule.Priority)
+		}
+		priorities[key] = *rule.Priority
+	}
+	return nil
+}
+
+func (_ *NetworkSecurityGroup) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *NetworkSecurityGroup) error {
+	if a == nil {
+		klog.V(2).Infof("Creating network security group %q", fi.StringValue(e.Name))
+	} else {
+		klog.V(2).Infof("Updating network security group %q", fi.StringValue(e.Name))
+	}
+
+	nsg := azureup.NetworkSecurityGroup{
+		Location: t.Cloud.Region(),
+		Tags:     e.Tags,
+	}
+	for _, rule := range e.SecurityRules {
+		nsg.Properties.SecurityRules = append(nsg.Properties.SecurityRules, &azureup.SecurityRule{
+			Name: fi.StringValue(rule.Name),
+			Properties: azureup.SecurityRuleProperties{
+				Priority:                 fi.Int32Value(rule.Priority),
+				Direction:                fi.StringValue(rule.Direction),
+				Access:                   fi.StringValue(rule.Access),
+				Protocol:                 fi.StringValue(rule.Protocol),
+				SourceAddressPrefix:      fi.StringValue(rule.SourceAddressPrefix),
+				SourcePortRange:          fi.StringValue(rule.SourcePortRange),
+				DestinationAddressPrefix: fi.StringValue(rule.DestinationAddressPrefix),
+				DestinationPortRange:     fi.StringValue(rule.DestinationPortRange),
+			},
+		})
+	}
+	return t.Cloud.NetworkSecurityGroup().CreateOrUpdate(context.TODO(), fi.StringValue(e.ResourceGroup.Name), fi.StringValue(e.Name), nsg)
+}
+
+// sortSecurityRules sorts rules by name, so that we can compare the rules we find with the rules we expect
+func sortSecurityRules(rules []*NetworkSecurityRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		return fi.StringValue(rules[i].Name) < fi.StringValue(rules[j].Name)
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+//go:generate fitask -type=PublicIPAddress
+type PublicIPAddress struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup *ResourceGroup
+	Tags          map[string]string
+
+	// IPAddress is the allocated address; it is populated by Find
+	IPAddress *string
+}
+
+var _ fi.CompareWithID = &PublicIPAddress{}
+
+func (e *PublicIPAddress) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *PublicIPAddress) Find(c *fi.Context) (*PublicIPAddress, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	pips, err := cloud.PublicIPAddress().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing public IP addresses: %v", err)
+	}
+
+	for _, pip := range pips {
+		if pip.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		actual := &PublicIPAddress{
+			Name:          fi.String(pip.Name),
+			Lifecycle:     e.Lifecycle,
+			ResourceGroup: &ResourceGroup{Name: e.ResourceGroup.Name},
+			Tags:          pip.Tags,
+		}
+		if pip.Properties.IPAddress != "" {
+			actual.IPAddress = fi.String(pip.Properties.IPAddress)
+		}
+		// IPAddress is an output field
+		e.IPAddress = actual.IPAddress
+		klog.V(4).Infof("found matching public IP address %q", pip.Name)
+		return actual, nil
+	}
+	return nil, nil
+}
+
+var _ fi.HasAddress = &PublicIPAddress{}
+
+// FindIPAddress returns the allocated address, so that it can be included in certificates
+func (e *PublicIPAddress) FindIPAddress(c *fi.Context) (*string, error) {
+	actual, err := e.Find(c)
+	if err != nil {
+		return nil, fmt.Errorf("error querying for public IP address: %v", err)
+	}
+	if actual == nil {
+		return nil, nil
+	}
+	return actual.IPAddress, nil
+}
+
+func (e *PublicIPAddress) Run(c *fi.Context) error {
+	c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *PublicIPAddress) CheckChanges(a, e, changes *PublicIPAddress) error {
+	if e.Name == nil {
+		return fi.RequiredField("Name")
+	}
+	return nil
+}
+
+func (_ *PublicIPAddress) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *PublicIPAddress) error {
+	if a == nil {
+		klog.V(2).Infof("Creating public IP address %q", fi.StringValue(e.Name))
+	} else {
+		klog.V(2).Infof("Updating public IP address %q", fi.StringValue(e.Name))
+	}
+
+	// Standard SKU load balancers require standard SKU (and hence static) public IPs
+	pip := azureup.PublicIPAddress{
+		Location: t.Cloud.Region(),
+		Tags:     e.Tags,
+		Sku:      &azureup.Sku{Name: "Standard"},
+		Properties: azureup.PublicIPAddressProperties{
+			PublicIPAllocationMethod: "Static",
+		},
+	}
+	return t.Cloud.PublicIPAddress().CreateOrUpdate(context.TODO(), fi.StringValue(e.ResourceGroup.Name), fi.StringValue(e.Name), pip)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+//go:generate fitask -type=ResourceGroup
+type ResourceGroup struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	Tags map[string]string
+	// Shared is set if the resource group is not owned by the cluster
+	Shared *bool
+}
+
+var _ fi.CompareWithID = &ResourceGroup{}
+
+func (e *ResourceGroup) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *ResourceGroup) Find(c *fi.Context) (*ResourceGroup, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	rgs, err := cloud.ResourceGroup().List(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("error listing resource groups: %v", err)
+	}
+
+	for _, rg := range rgs {
+		if rg.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		actual := &ResourceGroup{
+			Name:      fi.String(rg.Name),
+			Lifecycle: e.Lifecycle,
+			Tags:      rg.Tags,
+			Shared:    e.Shared,
+		}
+		if fi.BoolValue(e.Shared) {
+			e.Tags = actual.Tags
+		}
+		klog.V(4).Infof("found matching resource group %q", rg.Name)
+		return actual, nil
+	}
+	return nil, nil
+}
+
+func (e *ResourceGroup) Run(c *fi.Context) error {
+	if !fi.BoolValue(e.Shared) {
+		c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	}
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *ResourceGroup) CheckChanges(a, e, changes *ResourceGroup) error {
+	if e.Name == nil {
+		return fi.RequiredField("Name")
+	}
+	return nil
+}
+
+func (_ *ResourceGroup) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *ResourceGroup) error {
+	if fi.BoolValue(e.Shared) {
+		if a == nil {
+			return fmt.Errorf("resource group %q not found", fi.StringValue(e.Name))
+		}
+		// We don't tag shared resource groups
+		return nil
+	}
+
+	if a == nil {
+		klog.V(2).Infof("Creating resource group %q", fi.StringValue(e.Name))
+	} else {
+		klog.V(2).Infof("Updating resource group %q", fi.StringValue(e.Name))
+	}
+
+	rg := azureup.ResourceGroup{
+		Location: t.Cloud.Region(),
+		Tags:     e.Tags,
+	}
+	return t.Cloud.ResourceGroup().CreateOrUpdate(context.TODO(), fi.StringValue(e.Name), rg)
+}
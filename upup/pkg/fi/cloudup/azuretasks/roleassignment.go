@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pborman/uuid"
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// RoleAssignment grants a role over a resource group to the managed identity of a VM scale set
+//go:generate fitask -type=RoleAssignment
+type RoleAssignment struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	// ResourceGroup is the scope of the role assignment
+	ResourceGroup *ResourceGroup
+	VMScaleSet    *VMScaleSet
+	// RoleDefinitionID is the GUID of the role, e.g. b24988ac-6180-42a0-ab88-20f7382dd24c for Contributor
+	RoleDefinitionID *string
+}
+
+func (e *RoleAssignment) Find(c *fi.Context) (*RoleAssignment, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	// The principal only exists once the VM scale set has been created
+	principalID := fi.StringValue(e.VMScaleSet.PrincipalID)
+	if principalID == "" {
+		return nil, nil
+	}
+
+	scope := azureup.ResourceGroupID(cloud.SubscriptionID(), fi.StringValue(e.ResourceGroup.Name))
+	l, err := cloud.RoleAssignment().List(context.TODO(), scope)
+	if err != nil {
+		return nil, fmt.Errorf("error listing role assignments: %v", err)
+	}
+
+	for _, ra := range l {
+		if ra.Properties.PrincipalID != principalID || lastComponent(ra.Properties.RoleDefinitionID) != fi.StringValue(e.RoleDefinitionID) {
+			continue
+		}
+		klog.V(4).Infof("found matching role assignment %q", ra.Name)
+		return &RoleAssignment{
+			Name:             e.Name,
+			Lifecycle:        e.Lifecycle,
+			ResourceGroup:    &ResourceGroup{Name: e.ResourceGroup.Name},
+			VMScaleSet:       &VMScaleSet{Name: e.VMScaleSet.Name},
+			RoleDefinitionID: e.RoleDefinitionID,
+		}, nil
+	}
+	return nil, nil
+}
+
+func (e *RoleAssignment) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *RoleAssignment) CheckChanges(a, e, changes *RoleAssignment) error {
+	if e.RoleDefinitionID == nil {
+		return fi.RequiredField("RoleDefinitionID")
+	}
+	return nil
+}
+
+func (_ *RoleAssignment) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *RoleAssignment) error {
+	principalID := fi.StringValue(e.VMScaleSet.PrincipalID)
+	if principalID == "" {
+		return fmt.Errorf("VM scale set %q has no managed identity", fi.StringValue(e.VMScaleSet.Name))
+	}
+
+	klog.V(2).Infof("Creating role assignment %q", fi.StringValue(e.Name))
+
+	scope := azureup.ResourceGroupID(t.Cloud.SubscriptionID(), fi.StringValue(e.ResourceGroup.Name))
+	roleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", t.Cloud.SubscriptionID(), fi.StringValue(e.RoleDefinitionID))
+
+	// Role assignments are named by a GUID; we derive it so that retries are idempotent
+	name := uuid.NewSHA1(uuid.NameSpace_URL, []byte(scope+"|"+principalID+"|"+roleDefinitionID)).String()
+
+	ra := azureup.RoleAssignment{
+		Properties: azureup.RoleAssignmentProperties{
+			RoleDefinitionID: roleDefinitionID,
+			PrincipalID:      principalID,
+		},
+	}
+	return t.Cloud.RoleAssignment().Create(context.TODO(), scope, name, ra)
+}
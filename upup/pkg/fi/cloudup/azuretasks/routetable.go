@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// RouteTable is a route table for pod traffic; the routes themselves are managed by the Kubernetes cloud provider
+//go:generate fitask -type=RouteTable
+type RouteTable struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup *ResourceGroup
+	Tags          map[string]string
+}
+
+var _ fi.CompareWithID = &RouteTable{}
+
+func (e *RouteTable) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *RouteTable) Find(c *fi.Context) (*RouteTable, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	rt, err := findRouteTable(cloud, fi.StringValue(e.ResourceGroup.Name), fi.StringValue(e.Name))
+	if err != nil || rt == nil {
+		return nil, err
+	}
+
+	klog.V(4).Infof("found matching route table %q", rt.Name)
+	return &RouteTable{
+		Name:          fi.String(rt.Name),
+		Lifecycle:     e.Lifecycle,
+		ResourceGroup: &ResourceGroup{Name: e.ResourceGroup.Name},
+		Tags:          rt.Tags,
+	}, nil
+}
+
+func findRouteTable(cloud azureup.AzureCloud, resourceGroupName, name string) (*azureup.RouteTable, error) {
+	rts, err := cloud.RouteTable().List(context.TODO(), resourceGroupName)
+	if err != nil {
+		return nil, fmt.Errorf("error listing route tables: %v", err)
+	}
+	for _, rt := range rts {
+		if rt.Name == name {
+			return rt, nil
+		}
+	}
+	return nil, nil
+}
+
+func (e *RouteTable) Run(c *fi.Context) error {
+	c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *RouteTable) CheckChanges(a, e, changes *RouteTable) error {
+	if e.Name == nil {
+		return fi.RequiredField("Name")
+	}
+	return nil
+}
+
+func (_ *RouteTable) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *RouteTable) error {
+	rg := fi.StringValue(e.ResourceGroup.Name)
+
+	rt := azureup.RouteTable{}
+	if a == nil {
+		klog.V(2).Infof("Creating route table %q", fi.StringValue(e.Name))
+	} else {
+		klog.V(2).Infof("Updating route table %q", fi.StringValue(e.Name))
+
+		// A PUT replaces the routes, so we must preserve those created by the cloud provider
+		existing, err := findRouteTable(t.Cloud, rg, fi.StringValue(e.Name))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			rt.Properties.Routes = existing.Properties.Routes
+		}
+	}
+	rt.Location = t.Cloud.Region()
+	rt.Tags = e.Tags
+
+	return t.Cloud.RouteTable().CreateOrUpdate(context.TODO(), rg, fi.StringValue(e.Name), rt)
+}
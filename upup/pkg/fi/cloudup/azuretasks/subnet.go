@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+//go:generate fitask -type=Subnet
+type Subnet struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup        *ResourceGroup
+	VirtualNetwork       *VirtualNetwork
+	NetworkSecurityGroup *NetworkSecurityGroup
+	RouteTable           *RouteTable
+	CIDR                 *string
+	// Shared is set if the subnet is not owned by the cluster
+	Shared *bool
+}
+
+var _ fi.CompareWithID = &Subnet{}
+
+func (e *Subnet) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *Subnet) Find(c *fi.Context) (*Subnet, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	subnets, err := cloud.Subnet().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name), fi.StringValue(e.VirtualNetwork.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing subnets: %v", err)
+	}
+
+	for _, subnet := range subnets {
+		if subnet.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		actual := &Subnet{
+			Name:           fi.String(subnet.Name),
+			Lifecycle:      e.Lifecycle,
+			ResourceGroup:  &ResourceGroup{Name: e.ResourceGroup.Name},
+			VirtualNetwork: &VirtualNetwork{Name: e.VirtualNetwork.Name},
+			CIDR:           fi.String(subnet.Properties.AddressPrefix),
+			Shared:         e.Shared,
+		}
+		if nsg := subnet.Properties.NetworkSecurityGroup; nsg != nil {
+			actual.NetworkSecurityGroup = &NetworkSecurityGroup{Name: fi.String(lastComponent(nsg.ID))}
+		}
+		if rt := subnet.Properties.RouteTable; rt != nil {
+			actual.RouteTable = &RouteTable{Name: fi.String(lastComponent(rt.ID))}
+		}
+		if fi.BoolValue(e.Shared) {
+			e.CIDR = actual.CIDR
+			e.NetworkSecurityGroup = actual.NetworkSecurityGroup
+			e.RouteTable = actual.RouteTable
+		}
+		klog.V(4).Infof("found matching subnet %q", subnet.Name)
+		return actual, nil
+	}
+	return nil, nil
+}
+
+func (e *Subnet) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *Subnet) CheckChanges(a, e, changes *Subnet) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.CIDR == nil && !fi.BoolValue(e.Shared) {
+			return fi.RequiredField("CIDR")
+		}
+	} else {
+		if changes.CIDR != nil {
+			return fi.CannotChangeField("CIDR")
+		}
+	}
+	return nil
+}
+
+func (_ *Subnet) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *Subnet) error {
+	if fi.BoolValue(e.Shared) {
+		if a == nil {
+			return fmt.Errorf("subnet %q not found", fi.StringValue(e.Name))
+		}
+		return nil
+	}
+
+	if a == nil {
+		klog.V(2).Infof("Creating subnet %q with CIDR %q", fi.StringValue(e.Name), fi.StringValue(e.CIDR))
+	} else {
+		klog.V(2).Infof("Updating subnet %q", fi.StringValue(e.Name))
+	}
+
+	rg := fi.StringValue(e.ResourceGroup.Name)
+	subnet := azureup.Subnet{
+		Properties: azureup.SubnetProperties{
+			AddressPrefix: fi.StringValue(e.CIDR),
+		},
+	}
+	if e.NetworkSecurityGroup != nil {
+		subnet.Properties.NetworkSecurityGroup = &azureup.SubResource{
+			ID: azureup.ResourceID(t.Cloud.SubscriptionID(), rg, "Microsoft.Network/networkSecurityGroups", fi.StringValue(e.NetworkSecurityGroup.Name)),
+		}
+	}
+	if e.RouteTable != nil {
+		subnet.Properties.RouteTable = &azureup.SubResource{
+			ID: azureup.ResourceID(t.Cloud.SubscriptionID(), rg, "Microsoft.Network/routeTables", fi.StringValue(e.RouteTable.Name)),
+		}
+	}
+	return t.Cloud.Subnet().CreateOrUpdate(context.TODO(), rg, fi.StringValue(e.VirtualNetwork.Name), fi.StringValue(e.Name), subnet)
+}
+
+// lastComponent returns the last component of a resource ID, which is the name of the resource
+func lastComponent(id string) string {
+	tokens := strings.Split(id, "/")
+	return tokens[len(tokens)-1]
+}
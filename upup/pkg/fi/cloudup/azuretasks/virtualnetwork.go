@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+//go:generate fitask -type=VirtualNetwork
+type VirtualNetwork struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup *ResourceGroup
+	CIDR          *string
+	Tags          map[string]string
+	// Shared is set if the virtual network is not owned by the cluster
+	Shared *bool
+}
+
+var _ fi.CompareWithID = &VirtualNetwork{}
+
+func (e *VirtualNetwork) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *VirtualNetwork) Find(c *fi.Context) (*VirtualNetwork, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	vnets, err := cloud.VirtualNetwork().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing virtual networks: %v", err)
+	}
+
+	for _, vnet := range vnets {
+		if vnet.Name != fi.StringValue(e.Name) {
+			continue
+		}
+		actual := &VirtualNetwork{
+			Name:          fi.String(vnet.Name),
+			Lifecycle:     e.Lifecycle,
+			ResourceGroup: &ResourceGroup{Name: e.ResourceGroup.Name},
+			Tags:          vnet.Tags,
+			Shared:        e.Shared,
+		}
+		if prefixes := vnet.Properties.AddressSpace.AddressPrefixes; len(prefixes) != 0 {
+			actual.CIDR = fi.String(prefixes[0])
+		}
+		if fi.BoolValue(e.Shared) {
+			// We don't manage shared networks, so we adopt their settings
+			e.CIDR = actual.CIDR
+			e.Tags = actual.Tags
+		}
+		klog.V(4).Infof("found matching virtual network %q", vnet.Name)
+		return actual, nil
+	}
+	return nil, nil
+}
+
+func (e *VirtualNetwork) Run(c *fi.Context) error {
+	if !fi.BoolValue(e.Shared) {
+		c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	}
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *VirtualNetwork) CheckChanges(a, e, changes *VirtualNetwork) error {
+	if a == nil {
+		if e.Name == nil {
+			return fi.RequiredField("Name")
+		}
+		if e.CIDR == nil && !fi.BoolValue(e.Shared) {
+			return fi.RequiredField("CIDR")
+		}
+	} else {
+		if changes.CIDR != nil {
+			return fi.CannotChangeField("CIDR")
+		}
+	}
+	return nil
+}
+
+func (_ *VirtualNetwork) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *VirtualNetwork) error {
+	if fi.BoolValue(e.Shared) {
+		if a == nil {
+			return fmt.Errorf("virtual network %q not found", fi.StringValue(e.Name))
+		}
+		return nil
+	}
+
+	if a == nil {
+		klog.V(2).Infof("Creating virtual network %q with CIDR %q", fi.StringValue(e.Name), fi.StringValue(e.CIDR))
+	} else {
+		klog.V(2).Infof("Updating virtual network %q", fi.StringValue(e.Name))
+	}
+
+	vnet := azureup.VirtualNetwork{
+		Location: t.Cloud.Region(),
+		Tags:     e.Tags,
+		Properties: azureup.VirtualNetworkProperties{
+			AddressSpace: azureup.AddressSpace{
+				AddressPrefixes: []string{fi.StringValue(e.CIDR)},
+			},
+		},
+	}
+	return t.Cloud.VirtualNetwork().CreateOrUpdate(context.TODO(), fi.StringValue(e.ResourceGroup.Name), fi.StringValue(e.Name), vnet)
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+// tagCustomDataHash records the hash of the custom data of a VM scale set, because Azure never returns custom data
+const tagCustomDataHash = "kops.k8s.io_customdata-hash"
+
+// VMScaleSet is a virtual machine scale set, with a system-assigned managed identity
+//go:generate fitask -type=VMScaleSet
+type VMScaleSet struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+
+	ResourceGroup *ResourceGroup
+	Tags          map[string]string
+
+	// Zones are the availability zones (e.g. "1") the VMs are spread across
+	Zones []string
+	// SKUName is the VM size, e.g. Standard_D2s_v3
+	SKUName  *string
+	Capacity *int64
+
+	ComputerNamePrefix *string
+	AdminUser          *string
+	SSHPublicKey       *string
+	CustomData         *fi.ResourceHolder
+
+	// ImageURN is either publisher:offer:sku:version, or the resource ID of an image
+	ImageURN     *string
+	OSDiskSizeGB *int32
+
+	Subnet *Subnet
+	// LoadBalancer is set if the VMs should be added to the backend pool of a load balancer
+	LoadBalancer *LoadBalancer
+	// RequirePublicIPAddress is set if each VM should get a public IP address
+	RequirePublicIPAddress *bool
+
+	// PrincipalID is the ID of the managed identity of the VMs; it is populated by Find
+	PrincipalID *string
+}
+
+var _ fi.CompareWithID = &VMScaleSet{}
+
+func (e *VMScaleSet) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *VMScaleSet) Find(c *fi.Context) (*VMScaleSet, error) {
+	cloud := c.Cloud.(azureup.AzureCloud)
+
+	l, err := cloud.VMScaleSet().List(context.TODO(), fi.StringValue(e.ResourceGroup.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing VM scale sets: %v", err)
+	}
+
+	var vmss *azureup.VirtualMachineScaleSet
+	for _, v := range l {
+		if v.Name == fi.StringValue(e.Name) {
+			vmss = v
+			break
+		}
+	}
+	if vmss == nil {
+		return nil, nil
+	}
+
+	actual := &VMScaleSet{
+		Name:          fi.String(vmss.Name),
+		Lifecycle:     e.Lifecycle,
+		ResourceGroup: &ResourceGroup{Name: e.ResourceGroup.Name},
+		Tags:          make(map[string]string),
+		Zones:         vmss.Zones,
+	}
+	for k, v := range vmss.Tags {
+		if k != tagCustomDataHash {
+			actual.Tags[k] = v
+		}
+	}
+	if vmss.Sku != nil {
+		actual.SKUName = fi.String(vmss.Sku.Name)
+		actual.Capacity = vmss.Sku.Capacity
+	}
+	if vmss.Identity != nil && vmss.Identity.PrincipalID != "" {
+		actual.PrincipalID = fi.String(vmss.Identity.PrincipalID)
+	}
+
+	if profile := vmss.Properties.VirtualMachineProfile; profile != nil {
+		if os := profile.OsProfile; os != nil {
+			actual.ComputerNamePrefix = fi.String(os.ComputerNamePrefix)
+			actual.AdminUser = fi.String(os.AdminUsername)
+			if os.LinuxConfiguration != nil && os.LinuxConfiguration.SSH != nil {
+				for _, key := range os.LinuxConfiguration.SSH.PublicKeys {
+					actual.SSHPublicKey = fi.String(key.KeyData)
+				}
+			}
+		}
+		if storage := profile.StorageProfile; storage != nil {
+			if image := storage.ImageReference; image != nil {
+				if image.ID != "" {
+					actual.ImageURN = fi.String(image.ID)
+				} else {
+					actual.ImageURN = fi.String(strings.Join([]string{image.Publisher, image.Offer, image.Sku, image.Version}, ":"))
+				}
+			}
+			if storage.OsDisk != nil && storage.OsDisk.DiskSizeGB != 0 {
+				actual.OSDiskSizeGB = fi.Int32(storage.OsDisk.DiskSizeGB)
+			}
+		}
+		if network := profile.NetworkProfile; network != nil {
+			for _, nic := range network.NetworkInterfaceConfigurations {
+				for _, ipConfig := range nic.Properties.IPConfigurations {
+					if ipConfig.Properties.Subnet != nil {
+						actual.Subnet = &Subnet{Name: fi.String(lastComponent(ipConfig.Properties.Subnet.ID))}
+					}
+					for _, pool := range ipConfig.Properties.LoadBalancerBackendAddressPools {
+						actual.LoadBalancer = &LoadBalancer{Name: fi.String(loadBalancerName(pool.ID))}
+					}
+					actual.RequirePublicIPAddress = fi.Bool(ipConfig.Properties.PublicIPAddressConfiguration != nil)
+				}
+			}
+		}
+	}
+
+	// Custom data is write-only, so we compare the hash we recorded when we last set it
+	if e.CustomData != nil {
+		hash, err := customDataHash(e.CustomData)
+		if err != nil {
+			return nil, err
+		}
+		if vmss.Tags[tagCustomDataHash] == hash {
+			actual.CustomData = e.CustomData
+		}
+	}
+
+	// PrincipalID is an output field
+	e.PrincipalID = actual.PrincipalID
+
+	klog.V(4).Infof("found matching VM scale set %q", vmss.Name)
+	return actual, nil
+}
+
+// loadBalancerName returns the name of the load balancer from the resource ID of one of its backend pools
+func loadBalancerName(poolID string) string {
+	tokens := strings.Split(poolID, "/")
+	for i := 0; i+1 < len(tokens); i++ {
+		if strings.EqualFold(tokens[i], "loadBalancers") {
+			return tokens[i+1]
+		}
+	}
+	return ""
+}
+
+func customDataHash(customData *fi.ResourceHolder) (string, error) {
+	d, err := customData.AsBytes()
+	if err != nil {
+		return "", fmt.Errorf("error rendering VM scale set custom data: %v", err)
+	}
+	hash := sha256.Sum256(d)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (e *VMScaleSet) Run(c *fi.Context) error {
+	c.Cloud.(azureup.AzureCloud).AddClusterTags(e.Tags)
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (_ *VMScaleSet) CheckChanges(a, e, changes *VMScaleSet) error {
+	if e.Name == nil {
+		return fi.RequiredField("Name")
+	}
+	if e.SKUName == nil {
+		return fi.RequiredField("SKUName")
+	}
+	if e.Capacity == nil {
+		return fi.RequiredField("Capacity")
+	}
+	if e.ImageURN == nil {
+		return fi.RequiredField("ImageURN")
+	}
+	if e.Subnet == nil {
+		return fi.RequiredField("Subnet")
+	}
+	if a != nil {
+		if changes.Zones != nil {
+			return fi.CannotChangeField("Zones")
+		}
+		if changes.ComputerNamePrefix != nil {
+			return fi.CannotChangeField("ComputerNamePrefix")
+		}
+		if changes.AdminUser != nil {
+			return fi.CannotChangeField("AdminUser")
+		}
+	}
+	return nil
+}
+
+func (_ *VMScaleSet) RenderAzure(t *azureup.AzureAPITarget, a, e, changes *VMScaleSet) error {
+	if a == nil {
+		klog.V(2).Infof("Creating VM scale set %q", fi.StringValue(e.Name))
+	} else {
+		klog.V(2).Infof("Updating VM scale set %q", fi.StringValue(e.Name))
+	}
+
+	rg := fi.StringValue(e.ResourceGroup.Name)
+	subscriptionID := t.Cloud.SubscriptionID()
+
+	tags := make(map[string]string)
+	for k, v := range e.Tags {
+		tags[k] = v
+	}
+
+	osProfile := &azureup.OSProfile{
+		ComputerNamePrefix: fi.StringValue(e.ComputerNamePrefix),
+		AdminUsername:      fi.StringValue(e.AdminUser),
+		LinuxConfiguration: &azureup.LinuxConfiguration{
+			DisablePasswordAuthentication: true,
+		},
+	}
+	if e.SSHPublicKey != nil {
+		osProfile.LinuxConfiguration.SSH = &azureup.SSHConfiguration{
+			PublicKeys: []*azureup.SSHPublicKey{
+				{
+					Path:    fmt.Sprintf("/home/%s/.ssh/authorized_keys", fi.StringValue(e.AdminUser)),
+					KeyData: fi.StringValue(e.SSHPublicKey),
+				},
+			},
+		}
+	}
+	if e.CustomData != nil {
+		d, err := e.CustomData.AsBytes()
+		if err != nil {
+			return fmt.Errorf("error rendering VM scale set custom data: %v", err)
+		}
+		osProfile.CustomData = base64.StdEncoding.EncodeToString(d)
+
+		hash, err := customDataHash(e.CustomData)
+		if err != nil {
+			return err
+		}
+		tags[tagCustomDataHash] = hash
+	}
+
+	image := &azureup.ImageReference{}
+	imageURN := fi.StringValue(e.ImageURN)
+	if strings.HasPrefix(imageURN, "/") {
+		image.ID = imageURN
+	} else {
+		tokens := strings.Split(imageURN, ":")
+		if len(tokens) != 4 {
+			return fmt.Errorf("image %q should be of the form publisher:offer:sku:version", imageURN)
+		}
+		image.Publisher = tokens[0]
+		image.Offer = tokens[1]
+		image.Sku = tokens[2]
+		image.Version = tokens[3]
+	}
+
+	ipConfig := &azureup.IPConfiguration{
+		Name: fi.StringValue(e.Name),
+		Properties: azureup.IPConfigurationProperties{
+			Primary: true,
+			Subnet: &azureup.SubResource{
+				ID: azureup.ResourceID(subscriptionID, rg, "Microsoft.Network/virtualNetworks", fi.StringValue(e.Subnet.VirtualNetwork.Name), "subnets", fi.StringValue(e.Subnet.Name)),
+			},
+		},
+	}
+	if e.LoadBalancer != nil {
+		ipConfig.Properties.LoadBalancerBackendAddressPools = []*azureup.SubResource{
+			{
+				ID: azureup.ResourceID(subscriptionID, rg, "Microsoft.Network/loadBalancers", fi.StringValue(e.LoadBalancer.Name), "backendAddressPools", LoadBalancerBackendPoolName),
+			},
+		}
+	}
+	if fi.BoolValue(e.RequirePublicIPAddress) {
+		ipConfig.Properties.PublicIPAddressConfiguration = &azureup.PublicIPAddressConfiguration{
+			Name: fi.StringValue(e.Name) + "-publicip",
+		}
+	}
+
+	vmss := azureup.VirtualMachineScaleSet{
+		Location: t.Cloud.Region(),
+		Tags:     tags,
+		Zones:    e.Zones,
+		Sku: &azureup.Sku{
+			Name:     fi.StringValue(e.SKUName),
+			Capacity: e.Capacity,
+		},
+		Identity: &azureup.ManagedIdentity{
+			Type: "SystemAssigned",
+		},
+		Properties: azureup.VirtualMachineScaleSetProperties{
+			// kops performs rolling updates itself
+			UpgradePolicy: &azureup.UpgradePolicy{Mode: "Manual"},
+			Overprovision: fi.Bool(false),
+			VirtualMachineProfile: &azureup.VirtualMachineScaleSetVMProfile{
+				OsProfile: osProfile,
+				StorageProfile: &azureup.StorageProfile{
+					ImageReference: image,
+					OsDisk: &azureup.OSDisk{
+						CreateOption: "FromImage",
+						DiskSizeGB:   fi.Int32Value(e.OSDiskSizeGB),
+						ManagedDisk: &azureup.ManagedDiskParameters{
+							StorageAccountType: "Premium_LRS",
+						},
+					},
+				},
+				NetworkProfile: &azureup.NetworkProfile{
+					NetworkInterfaceConfigurations: []*azureup.NetworkInterfaceConfiguration{
+						{
+							Name: fi.StringValue(e.Name),
+							Properties: azureup.NetworkInterfaceConfigurationProperties{
+								Primary: true,
+								// Pod traffic is routed to the nodes
+								EnableIPForwarding: true,
+								IPConfigurations:   []*azureup.IPConfiguration{ipConfig},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := t.Cloud.VMScaleSet().CreateOrUpdate(context.TODO(), rg, fi.StringValue(e.Name), vmss); err != nil {
+		return err
+	}
+
+	// Dependent tasks need the principal of the managed identity, which is assigned on creation
+	l, err := t.Cloud.VMScaleSet().List(context.TODO(), rg)
+	if err != nil {
+		return fmt.Errorf("error listing VM scale sets: %v", err)
+	}
+	for _, v := range l {
+		if v.Name == fi.StringValue(e.Name) && v.Identity != nil && v.Identity.PrincipalID != "" {
+			e.PrincipalID = fi.String(v.Identity.PrincipalID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuretasks
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"k8s.io/kops/cloudmock/azure/mockazure"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
+)
+
+var testRunTasksOptions = fi.RunTasksOptions{
+	MaxTaskDuration:         2 * time.Second,
+	WaitAfterAllTasksFailed: 500 * time.Millisecond,
+}
+
+func TestVMScaleSetCreate(t *testing.T) {
+	cloud := mockazure.BuildMockAzureCloud("eastus", "rg1", map[string]string{azureup.TagClusterName: "cluster1"})
+
+	customData := "#!/bin/bash\necho hello\n"
+
+	// We define a function so we can rebuild the tasks, because we modify in-place when running
+	buildTasks := func() map[string]fi.Task {
+		rg1 := &ResourceGroup{
+			Name: fi.String("rg1"),
+			Tags: map[string]string{},
+		}
+		vnet1 := &VirtualNetwork{
+			Name:          fi.String("vnet1"),
+			ResourceGroup: rg1,
+			CIDR:          fi.String("10.0.0.0/16"),
+			Tags:          map[string]string{},
+		}
+		subnet1 := &Subnet{
+			Name:           fi.String("subnet1"),
+			ResourceGroup:  rg1,
+			VirtualNetwork: vnet1,
+			CIDR:           fi.String("10.0.1.0/24"),
+		}
+		pip1 := &PublicIPAddress{
+			Name:          fi.String("pip1"),
+			ResourceGroup: rg1,
+			Tags:          map[string]string{},
+		}
+		lb1 := &LoadBalancer{
+			Name:            fi.String("lb1"),
+			ResourceGroup:   rg1,
+			Tags:            map[string]string{},
+			PublicIPAddress: pip1,
+			Port:            fi.Int32(443),
+		}
+		vmss1 := &VMScaleSet{
+			Name:                   fi.String("vmss1"),
+			ResourceGroup:          rg1,
+			Tags:                   map[string]string{"Name": "vmss1"},
+			Zones:                  []string{"1", "2"},
+			SKUName:                fi.String("Standard_D2s_v3"),
+			Capacity:               fi.Int64(2),
+			ComputerNamePrefix:     fi.String("vmss1"),
+			AdminUser:              fi.String("admin"),
+			SSHPublicKey:           fi.String("ssh-rsa AAAA"),
+			CustomData:             fi.WrapResource(fi.NewStringResource(customData)),
+			ImageURN:               fi.String("Canonical:UbuntuServer:18.04-LTS:latest"),
+			OSDiskSizeGB:           fi.Int32(64),
+			Subnet:                 subnet1,
+			LoadBalancer:           lb1,
+			RequirePublicIPAddress: fi.Bool(false),
+		}
+		ra1 := &RoleAssignment{
+			Name:             fi.String("vmss1"),
+			ResourceGroup:    rg1,
+			VMScaleSet:       vmss1,
+			RoleDefinitionID: fi.String("b24988ac-6180-42a0-ab88-20f7382dd24c"),
+		}
+
+		return map[string]fi.Task{
+			"rg1":     rg1,
+			"vnet1":   vnet1,
+			"subnet1": subnet1,
+			"pip1":    pip1,
+			"lb1":     lb1,
+			"vmss1":   vmss1,
+			"ra1":     ra1,
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+		vmss1 := allTasks["vmss1"].(*VMScaleSet)
+
+		target := azureup.NewAzureAPITarget(cloud)
+		context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+		if err != nil {
+			t.Fatalf("error building context: %v", err)
+		}
+
+		if err := context.RunTasks(testRunTasksOptions); err != nil {
+			t.Fatalf("unexpected error during Run: %v", err)
+		}
+
+		if fi.StringValue(vmss1.PrincipalID) == "" {
+			t.Fatalf("PrincipalID not set after create")
+		}
+
+		scaleSets := cloud.MockVMScaleSets.(*mockazure.MockVMScaleSets)
+		if len(scaleSets.VMScaleSets) != 1 {
+			t.Fatalf("expected exactly one VM scale set; found %v", scaleSets.VMScaleSets)
+		}
+		for _, vmss := range scaleSets.VMScaleSets {
+			if vmss.Tags[azureup.TagClusterName] != "cluster1" {
+				t.Errorf("cluster tag not set on VM scale set: %v", vmss.Tags)
+			}
+			ipConfig := vmss.Properties.VirtualMachineProfile.NetworkProfile.NetworkInterfaceConfigurations[0].Properties.IPConfigurations[0]
+			if len(ipConfig.Properties.LoadBalancerBackendAddressPools) != 1 {
+				t.Errorf("VM scale set not added to load balancer: %v", ipConfig.Properties.LoadBalancerBackendAddressPools)
+			}
+		}
+		if len(scaleSets.VMs["rg1/vmss1"]) != 2 {
+			t.Errorf("expected 2 VMs, found %v", scaleSets.VMs)
+		}
+
+		roleAssignments := cloud.MockRoleAssignments.(*mockazure.MockRoleAssignments)
+		if len(roleAssignments.RoleAssignments) != 1 {
+			t.Fatalf("expected exactly one role assignment; found %v", roleAssignments.RoleAssignments)
+		}
+		for _, ra := range roleAssignments.RoleAssignments {
+			if ra.Properties.PrincipalID != fi.StringValue(vmss1.PrincipalID) {
+				t.Errorf("role assignment has principal %q, expected %q", ra.Properties.PrincipalID, fi.StringValue(vmss1.PrincipalID))
+			}
+		}
+	}
+
+	{
+		allTasks := buildTasks()
+		checkNoChanges(t, cloud, allTasks)
+	}
+
+	{
+		customData = "#!/bin/bash\necho goodbye\n"
+		allTasks := buildTasks()
+		if !hasChanges(t, cloud, allTasks) {
+			t.Fatalf("expected a change to the custom data to be detected")
+		}
+	}
+}
+
+func checkNoChanges(t *testing.T, cloud fi.Cloud, allTasks map[string]fi.Task) {
+	if hasChanges(t, cloud, allTasks) {
+		t.Fatalf("Target had changes after executing")
+	}
+}
+
+func hasChanges(t *testing.T, cloud fi.Cloud, allTasks map[string]fi.Task) bool {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			KubernetesVersion: "v1.14.0",
+		},
+	}
+	assetBuilder := assets.NewAssetBuilder(cluster, "")
+	target := fi.NewDryRunTarget(assetBuilder, os.Stderr)
+	context, err := fi.NewContext(target, nil, cloud, nil, nil, nil, true, allTasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+
+	if err := context.RunTasks(testRunTasksOptions); err != nil {
+		t.Fatalf("unexpected error during Run: %v", err)
+	}
+
+	if target.HasChanges() {
+		var b bytes.Buffer
+		if err := target.PrintReport(allTasks, &b); err != nil {
+			t.Fatalf("error building report: %v", err)
+		}
+		t.Logf("changes: %s", b.String())
+		return true
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// armClient performs requests against the Azure Resource Manager REST API
+type armClient struct {
+	client  autorest.Client
+	baseURI string
+}
+
+// armListResult is a page of a list response
+type armListResult struct {
+	Value    []json.RawMessage `json:"value"`
+	NextLink string            `json:"nextLink,omitempty"`
+}
+
+func newARMClient(baseURI string, authorizer autorest.Authorizer) *armClient {
+	client := autorest.NewClientWithUserAgent(KubernetesKopsIdentity)
+	client.Authorizer = authorizer
+	return &armClient{
+		client:  client,
+		baseURI: strings.TrimSuffix(baseURI, "/"),
+	}
+}
+
+// get fetches a single resource; it returns false if the resource does not exist
+func (c *armClient) get(ctx context.Context, path string, apiVersion string, into interface{}) (bool, error) {
+	req, err := autorest.Prepare((&http.Request{}).WithContext(ctx),
+		autorest.AsGet(),
+		autorest.WithBaseURL(c.baseURI),
+		autorest.WithPath(path),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": apiVersion}))
+	if err != nil {
+		return false, fmt.Errorf("error building request for %s: %v", path, err)
+	}
+
+	resp, err := autorest.SendWithSender(c.client, req, autorest.DoRetryForStatusCodes(c.client.RetryAttempts, c.client.RetryDuration, autorest.StatusCodesForRetry...))
+	if err != nil {
+		return false, fmt.Errorf("error getting %s: %v", path, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		autorest.Respond(resp, autorest.ByDiscardingBody(), autorest.ByClosing())
+		return false, nil
+	}
+	err = autorest.Respond(resp,
+		c.client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK),
+		autorest.ByUnmarshallingJSON(into),
+		autorest.ByClosing())
+	if err != nil {
+		return false, fmt.Errorf("error getting %s: %v", path, err)
+	}
+	return true, nil
+}
+
+// list fetches all pages of a collection, calling fn for each resource
+func (c *armClient) list(ctx context.Context, path string, apiVersion string, fn func(raw json.RawMessage) error) error {
+	req, err := autorest.Prepare((&http.Request{}).WithContext(ctx),
+		autorest.AsGet(),
+		autorest.WithBaseURL(c.baseURI),
+		autorest.WithPath(path),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": apiVersion}))
+	if err != nil {
+		return fmt.Errorf("error building request for %s: %v", path, err)
+	}
+
+	for req != nil {
+		resp, err := autorest.SendWithSender(c.client, req, autorest.DoRetryForStatusCodes(c.client.RetryAttempts, c.client.RetryDuration, autorest.StatusCodesForRetry...))
+		if err != nil {
+			return fmt.Errorf("error listing %s: %v", path, err)
+		}
+
+		page := &armListResult{}
+		err = autorest.Respond(resp,
+			c.client.ByInspecting(),
+			azure.WithErrorUnlessStatusCode(http.StatusOK),
+			autorest.ByUnmarshallingJSON(page),
+			autorest.ByClosing())
+		if err != nil {
+			return fmt.Errorf("error listing %s: %v", path, err)
+		}
+
+		for _, raw := range page.Value {
+			if err := fn(raw); err != nil {
+				return err
+			}
+		}
+
+		req = nil
+		if page.NextLink != "" {
+			req, err = autorest.Prepare((&http.Request{}).WithContext(ctx),
+				autorest.AsGet(),
+				autorest.WithBaseURL(page.NextLink))
+			if err != nil {
+				return fmt.Errorf("error building request for %s: %v", page.NextLink, err)
+			}
+		}
+	}
+	return nil
+}
+
+// createOrUpdate puts a resource and waits for the operation to complete
+func (c *armClient) createOrUpdate(ctx context.Context, path string, apiVersion string, parameters interface{}) error {
+	req, err := autorest.Prepare((&http.Request{}).WithContext(ctx),
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPut(),
+		autorest.WithBaseURL(c.baseURI),
+		autorest.WithPath(path),
+		autorest.WithJSON(parameters),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": apiVersion}))
+	if err != nil {
+		return fmt.Errorf("error building request for %s: %v", path, err)
+	}
+	return c.sendAndWait(ctx, req, http.StatusOK, http.StatusCreated, http.StatusAccepted)
+}
+
+// post invokes an action on a resource and waits for the operation to complete
+func (c *armClient) post(ctx context.Context, path string, apiVersion string, parameters interface{}) error {
+	decorators := []autorest.PrepareDecorator{
+		autorest.AsPost(),
+		autorest.WithBaseURL(c.baseURI),
+		autorest.WithPath(path),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": apiVersion}),
+	}
+	if parameters != nil {
+		decorators = append(decorators, autorest.AsContentType("application/json; charset=utf-8"), autorest.WithJSON(parameters))
+	}
+	req, err := autorest.Prepare((&http.Request{}).WithContext(ctx), decorators...)
+	if err != nil {
+		return fmt.Errorf("error building request for %s: %v", path, err)
+	}
+	return c.sendAndWait(ctx, req, http.StatusOK, http.StatusAccepted)
+}
+
+// delete removes a resource and waits for the operation to complete; deleting a resource which does not exist is not an error
+func (c *armClient) delete(ctx context.Context, path string, apiVersion string) error {
+	req, err := autorest.Prepare((&http.Request{}).WithContext(ctx),
+		autorest.AsDelete(),
+		autorest.WithBaseURL(c.baseURI),
+		autorest.WithPath(path),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": apiVersion}))
+	if err != nil {
+		return fmt.Errorf("error building request for %s: %v", path, err)
+	}
+	return c.sendAndWait(ctx, req, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+}
+
+// put puts a resource which is created synchronously (i.e. is not a long-running operation)
+func (c *armClient) put(ctx context.Context, path string, apiVersion string, parameters interface{}) error {
+	req, err := autorest.Prepare((&http.Request{}).WithContext(ctx),
+		autorest.AsContentType("application/json; charset=utf-8"),
+		autorest.AsPut(),
+		autorest.WithBaseURL(c.baseURI),
+		autorest.WithPath(path),
+		autorest.WithJSON(parameters),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": apiVersion}))
+	if err != nil {
+		return fmt.Errorf("error building request for %s: %v", path, err)
+	}
+
+	resp, err := autorest.SendWithSender(c.client, req, autorest.DoRetryForStatusCodes(c.client.RetryAttempts, c.client.RetryDuration, autorest.StatusCodesForRetry...))
+	if err != nil {
+		return fmt.Errorf("error sending %s %s: %v", req.Method, path, err)
+	}
+	err = autorest.Respond(resp,
+		c.client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
+		autorest.ByClosing())
+	if err != nil {
+		return fmt.Errorf("error from %s %s: %v", req.Method, path, err)
+	}
+	return nil
+}
+
+func (c *armClient) sendAndWait(ctx context.Context, req *http.Request, codes ...int) error {
+	path := req.URL.Path
+
+	resp, err := autorest.SendWithSender(c.client, req, azure.DoRetryWithRegistration(c.client))
+	if err != nil {
+		return fmt.Errorf("error sending %s %s: %v", req.Method, path, err)
+	}
+	if req.Method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		autorest.Respond(resp, autorest.ByDiscardingBody(), autorest.ByClosing())
+		return nil
+	}
+	err = autorest.Respond(resp, c.client.ByInspecting(), azure.WithErrorUnlessStatusCode(codes...))
+	if err != nil {
+		autorest.Respond(resp, autorest.ByClosing())
+		return fmt.Errorf("error from %s %s: %v", req.Method, path, err)
+	}
+
+	future, err := azure.NewFutureFromResponse(resp)
+	if err != nil {
+		return fmt.Errorf("error from %s %s: %v", req.Method, path, err)
+	}
+	if err := future.WaitForCompletionRef(ctx, c.client); err != nil {
+		return fmt.Errorf("error waiting for %s %s: %v", req.Method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+
+	prj "k8s.io/kops"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// Azure tag names may not contain '/', so we use '_' where other clouds use '/'
+	TagClusterName           = "KubernetesCluster"
+	TagNameRolePrefix        = "k8s.io_role_"
+	TagNameEtcdClusterPrefix = "k8s.io_etcd_"
+	TagNameInstanceGroup     = "kops.k8s.io_instancegroup"
+	TagRoleMaster            = "master"
+)
+
+// KubernetesKopsIdentity is added to the User-Agent of requests to Azure
+var KubernetesKopsIdentity = fmt.Sprintf("Kubernetes.Kops/%s", prj.Version)
+
+// AzureCloud is the interface to the Azure cloud
+type AzureCloud interface {
+	fi.Cloud
+
+	Region() string
+	SubscriptionID() string
+	ResourceGroupName() string
+
+	// Tags returns the tags which are added to all resources of the cluster
+	Tags() map[string]string
+	// AddClusterTags adds the cluster tags to the specified tags
+	AddClusterTags(tags map[string]string)
+
+	ResourceGroup() ResourceGroupsClient
+	VirtualNetwork() VirtualNetworksClient
+	Subnet() SubnetsClient
+	NetworkSecurityGroup() NetworkSecurityGroupsClient
+	RouteTable() RouteTablesClient
+	PublicIPAddress() PublicIPAddressesClient
+	LoadBalancer() LoadBalancersClient
+	VMScaleSet() VMScaleSetsClient
+	VMScaleSetVM() VMScaleSetVMsClient
+	NetworkInterface() NetworkInterfacesClient
+	Disk() DisksClient
+	RoleAssignment() RoleAssignmentsClient
+
+	FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error)
+	GetApiIngressStatus(cluster *kops.Cluster) ([]kops.ApiIngressStatus, error)
+}
+
+type azureCloudImplementation struct {
+	subscriptionID    string
+	resourceGroupName string
+	location          string
+	tags              map[string]string
+
+	resourceGroupsClient        ResourceGroupsClient
+	virtualNetworksClient       VirtualNetworksClient
+	subnetsClient               SubnetsClient
+	networkSecurityGroupsClient NetworkSecurityGroupsClient
+	routeTablesClient           RouteTablesClient
+	publicIPAddressesClient     PublicIPAddressesClient
+	loadBalancersClient         LoadBalancersClient
+	vmScaleSetsClient           VMScaleSetsClient
+	vmScaleSetVMsClient         VMScaleSetVMsClient
+	networkInterfacesClient     NetworkInterfacesClient
+	disksClient                 DisksClient
+	roleAssignmentsClient       RoleAssignmentsClient
+}
+
+var _ fi.Cloud = &azureCloudImplementation{}
+
+// azureCloudInstances holds clouds installed by tests, by location
+var azureCloudInstances = make(map[string]AzureCloud)
+
+// NewAzureCloud returns a Cloud, authenticating with the service principal in AZURE_TENANT_ID, AZURE_CLIENT_ID
+// and AZURE_CLIENT_SECRET if set, and otherwise with the managed identity of the VM we are running on.
+func NewAzureCloud(subscriptionID, resourceGroupName, location string, tags map[string]string) (AzureCloud, error) {
+	if c := azureCloudInstances[location]; c != nil {
+		return c, nil
+	}
+
+	if subscriptionID == "" {
+		return nil, errors.New("azure subscription ID is required (set spec.cloudConfig.azure.subscriptionId or AZURE_SUBSCRIPTION_ID)")
+	}
+
+	env, err := findEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newServicePrincipalToken(env, env.ResourceManagerEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	arm := newARMClient(env.ResourceManagerEndpoint, autorest.NewBearerAuthorizer(token))
+	provider := func(resourceType, apiVersion string) providerResourcesClient {
+		return providerResourcesClient{
+			arm:            arm,
+			subscriptionID: subscriptionID,
+			resourceType:   resourceType,
+			apiVersion:     apiVersion,
+		}
+	}
+
+	c := &azureCloudImplementation{
+		subscriptionID:    subscriptionID,
+		resourceGroupName: resourceGroupName,
+		location:          location,
+		tags:              tags,
+
+		resourceGroupsClient:        &resourceGroupsClientImpl{arm: arm, subscriptionID: subscriptionID},
+		virtualNetworksClient:       &virtualNetworksClientImpl{provider("Microsoft.Network/virtualNetworks", networkAPIVersion)},
+		subnetsClient:               &subnetsClientImpl{provider("Microsoft.Network/virtualNetworks", networkAPIVersion)},
+		networkSecurityGroupsClient: &networkSecurityGroupsClientImpl{provider("Microsoft.Network/networkSecurityGroups", networkAPIVersion)},
+		routeTablesClient:           &routeTablesClientImpl{provider("Microsoft.Network/routeTables", networkAPIVersion)},
+		publicIPAddressesClient:     &publicIPAddressesClientImpl{provider("Microsoft.Network/publicIPAddresses", networkAPIVersion)},
+		loadBalancersClient:         &loadBalancersClientImpl{provider("Microsoft.Network/loadBalancers", networkAPIVersion)},
+		vmScaleSetsClient:           &vmScaleSetsClientImpl{provider("Microsoft.Compute/virtualMachineScaleSets", computeAPIVersion)},
+		vmScaleSetVMsClient:         &vmScaleSetVMsClientImpl{provider("Microsoft.Compute/virtualMachineScaleSets", computeAPIVersion)},
+		networkInterfacesClient:     &networkInterfacesClientImpl{provider("Microsoft.Compute/virtualMachineScaleSets", "2018-10-01")},
+		disksClient:                 &disksClientImpl{provider("Microsoft.Compute/disks", computeAPIVersion)},
+		roleAssignmentsClient:       &roleAssignmentsClientImpl{arm: arm},
+	}
+	return c, nil
+}
+
+// findEnvironment returns the Azure environment named by AZURE_ENVIRONMENT, defaulting to the public cloud
+func findEnvironment() (azure.Environment, error) {
+	name := os.Getenv("AZURE_ENVIRONMENT")
+	if name == "" {
+		return azure.PublicCloud, nil
+	}
+	env, err := azure.EnvironmentFromName(name)
+	if err != nil {
+		return env, fmt.Errorf("error parsing AZURE_ENVIRONMENT %q: %v", name, err)
+	}
+	return env, nil
+}
+
+// newServicePrincipalToken builds a token for the specified resource
+func newServicePrincipalToken(env azure.Environment, resource string) (*adal.ServicePrincipalToken, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
+
+	if tenantID != "" && clientID != "" && clientSecret != "" {
+		oauthConfig, err := adal.NewOAuthConfig(env.ActiveDirectoryEndpoint, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("error building azure OAuth configuration: %v", err)
+		}
+		token, err := adal.NewServicePrincipalToken(*oauthConfig, clientID, clientSecret, resource)
+		if err != nil {
+			return nil, fmt.Errorf("error building azure service principal token: %v", err)
+		}
+		return token, nil
+	}
+
+	klog.V(2).Infof("AZURE_TENANT_ID, AZURE_CLIENT_ID or AZURE_CLIENT_SECRET not set; using managed identity")
+	msiEndpoint, err := adal.GetMSIVMEndpoint()
+	if err != nil {
+		return nil, fmt.Errorf("error getting azure managed identity endpoint: %v", err)
+	}
+	token, err := adal.NewServicePrincipalTokenFromMSI(msiEndpoint, resource)
+	if err != nil {
+		return nil, fmt.Errorf("error building azure managed identity token: %v", err)
+	}
+	return token, nil
+}
+
+func (c *azureCloudImplementation) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderAzure
+}
+
+func (c *azureCloudImplementation) Region() string {
+	return c.location
+}
+
+func (c *azureCloudImplementation) SubscriptionID() string {
+	return c.subscriptionID
+}
+
+func (c *azureCloudImplementation) ResourceGroupName() string {
+	return c.resourceGroupName
+}
+
+func (c *azureCloudImplementation) Tags() map[string]string {
+	return c.tags
+}
+
+func (c *azureCloudImplementation) AddClusterTags(tags map[string]string) {
+	addClusterTags(c.tags, tags)
+}
+
+func addClusterTags(clusterTags map[string]string, tags map[string]string) {
+	for k, v := range clusterTags {
+		tags[k] = v
+	}
+}
+
+func (c *azureCloudImplementation) ResourceGroup() ResourceGroupsClient {
+	return c.resourceGroupsClient
+}
+
+func (c *azureCloudImplementation) VirtualNetwork() VirtualNetworksClient {
+	return c.virtualNetworksClient
+}
+
+func (c *azureCloudImplementation) Subnet() SubnetsClient {
+	return c.subnetsClient
+}
+
+func (c *azureCloudImplementation) NetworkSecurityGroup() NetworkSecurityGroupsClient {
+	return c.networkSecurityGroupsClient
+}
+
+func (c *azureCloudImplementation) RouteTable() RouteTablesClient {
+	return c.routeTablesClient
+}
+
+func (c *azureCloudImplementation) PublicIPAddress() PublicIPAddressesClient {
+	return c.publicIPAddressesClient
+}
+
+func (c *azureCloudImplementation) LoadBalancer() LoadBalancersClient {
+	return c.loadBalancersClient
+}
+
+func (c *azureCloudImplementation) VMScaleSet() VMScaleSetsClient {
+	return c.vmScaleSetsClient
+}
+
+func (c *azureCloudImplementation) VMScaleSetVM() VMScaleSetVMsClient {
+	return c.vmScaleSetVMsClient
+}
+
+func (c *azureCloudImplementation) NetworkInterface() NetworkInterfacesClient {
+	return c.networkInterfacesClient
+}
+
+func (c *azureCloudImplementation) Disk() DisksClient {
+	return c.disksClient
+}
+
+func (c *azureCloudImplementation) RoleAssignment() RoleAssignmentsClient {
+	return c.roleAssignmentsClient
+}
+
+func (c *azureCloudImplementation) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS not implemented on azure; use gossip (a cluster name ending in .k8s.local)")
+}
+
+func (c *azureCloudImplementation) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return findVPCInfo(c, id)
+}
+
+func (c *azureCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return deleteGroup(c, g)
+}
+
+func (c *azureCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstanceGroupMember) error {
+	return deleteInstance(c, i)
+}
+
+func (c *azureCloudImplementation) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	return getCloudGroups(c, cluster, instancegroups, warnUnmatched, nodes)
+}
+
+func (c *azureCloudImplementation) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	return findClusterStatus(c, cluster)
+}
+
+func (c *azureCloudImplementation) GetApiIngressStatus(cluster *kops.Cluster) ([]kops.ApiIngressStatus, error) {
+	return getApiIngressStatus(c, cluster)
+}
+
+// findVPCInfo describes the virtual network with the specified name in the resource group of the cluster
+func findVPCInfo(c AzureCloud, id string) (*fi.VPCInfo, error) {
+	ctx := context.TODO()
+	vnets, err := c.VirtualNetwork().List(ctx, c.ResourceGroupName())
+	if err != nil {
+		return nil, fmt.Errorf("error listing virtual networks: %v", err)
+	}
+	for _, vnet := range vnets {
+		if vnet.Name != id && !strings.EqualFold(vnet.ID, id) {
+			continue
+		}
+
+		vpcInfo := &fi.VPCInfo{}
+		if len(vnet.Properties.AddressSpace.AddressPrefixes) != 0 {
+			vpcInfo.CIDR = vnet.Properties.AddressSpace.AddressPrefixes[0]
+		}
+
+		subnets, err := c.Subnet().List(ctx, c.ResourceGroupName(), vnet.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error listing subnets in virtual network %q: %v", vnet.Name, err)
+		}
+		for _, subnet := range subnets {
+			// Azure subnets are not zonal
+			vpcInfo.Subnets = append(vpcInfo.Subnets, &fi.SubnetInfo{
+				ID:   subnet.Name,
+				CIDR: subnet.Properties.AddressPrefix,
+			})
+		}
+		return vpcInfo, nil
+	}
+	return nil, nil
+}
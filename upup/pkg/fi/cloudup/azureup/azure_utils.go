@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// FindRegion determines the region from the zones specified in the cluster.
+// Zones are either a region (e.g. westus) or a region and an availability zone (e.g. eastus-1).
+func FindRegion(cluster *kops.Cluster) (string, error) {
+	region := ""
+	for _, subnet := range cluster.Spec.Subnets {
+		zoneRegion, _, err := ParseZone(subnet.Zone)
+		if err != nil {
+			return "", err
+		}
+		if region != "" && zoneRegion != region {
+			return "", fmt.Errorf("clusters cannot span multiple regions (found zone %q, but region is %q)", subnet.Zone, region)
+		}
+		region = zoneRegion
+	}
+	return region, nil
+}
+
+// ParseZone splits a kops zone into the Azure location and availability zone (which may be empty)
+func ParseZone(zone string) (string, string, error) {
+	if zone == "" {
+		return "", "", fmt.Errorf("zone must be specified")
+	}
+	tokens := strings.Split(zone, "-")
+	switch len(tokens) {
+	case 1:
+		return tokens[0], "", nil
+	case 2:
+		if tokens[0] == "" || tokens[1] == "" {
+			return "", "", fmt.Errorf("invalid Azure zone: %q", zone)
+		}
+		return tokens[0], tokens[1], nil
+	default:
+		return "", "", fmt.Errorf("invalid Azure zone: %q", zone)
+	}
+}
+
+// FindSubscriptionID returns the subscription configured for the cluster, falling back to AZURE_SUBSCRIPTION_ID
+func FindSubscriptionID(cluster *kops.Cluster) string {
+	if cluster.Spec.CloudConfig != nil && cluster.Spec.CloudConfig.Azure != nil && cluster.Spec.CloudConfig.Azure.SubscriptionID != "" {
+		return cluster.Spec.CloudConfig.Azure.SubscriptionID
+	}
+	return os.Getenv("AZURE_SUBSCRIPTION_ID")
+}
+
+// FindResourceGroupName returns the resource group configured for the cluster, defaulting to the cluster name
+func FindResourceGroupName(cluster *kops.Cluster) string {
+	if cluster.Spec.CloudConfig != nil && cluster.Spec.CloudConfig.Azure != nil && cluster.Spec.CloudConfig.Azure.ResourceGroupName != "" {
+		return cluster.Spec.CloudConfig.Azure.ResourceGroupName
+	}
+	return cluster.ObjectMeta.Name
+}
+
+// VirtualNetworkName returns the name of the virtual network of the cluster, which is either
+// the existing network named by NetworkID or a network named after the cluster
+func VirtualNetworkName(cluster *kops.Cluster) string {
+	if cluster.Spec.NetworkID != "" {
+		return cluster.Spec.NetworkID
+	}
+	return cluster.ObjectMeta.Name
+}
+
+// SubnetName returns the name of the Azure subnet for a cluster subnet
+func SubnetName(cluster *kops.Cluster, subnet *kops.ClusterSubnetSpec) string {
+	if subnet.ProviderID != "" {
+		return subnet.ProviderID
+	}
+	return subnet.Name + "." + cluster.ObjectMeta.Name
+}
+
+// NetworkSecurityGroupName returns the name of the network security group of the cluster subnets
+func NetworkSecurityGroupName(cluster *kops.Cluster) string {
+	return cluster.ObjectMeta.Name
+}
+
+// RouteTableName returns the name of the route table the cloud provider maintains pod routes in
+func RouteTableName(cluster *kops.Cluster) string {
+	if cluster.Spec.CloudConfig != nil && cluster.Spec.CloudConfig.Azure != nil && cluster.Spec.CloudConfig.Azure.RouteTableName != "" {
+		return cluster.Spec.CloudConfig.Azure.RouteTableName
+	}
+	return cluster.ObjectMeta.Name
+}
+
+// SafeTags replaces the characters that are not allowed in Azure tag names
+func SafeTags(tags map[string]string) map[string]string {
+	safe := make(map[string]string)
+	for k, v := range tags {
+		safe[strings.Replace(k, "/", "_", -1)] = v
+	}
+	return safe
+}
+
+// ParseVMScaleSetVMID parses the resource ID of a VM in a VM scale set, returning the resource group, scale set name and instance ID
+func ParseVMScaleSetVMID(id string) (string, string, string, error) {
+	// /subscriptions/<sub>/resourceGroups/<rg>/providers/Microsoft.Compute/virtualMachineScaleSets/<vmss>/virtualMachines/<instance>
+	tokens := strings.Split(strings.TrimPrefix(id, "/"), "/")
+	if len(tokens) != 10 ||
+		!strings.EqualFold(tokens[0], "subscriptions") ||
+		!strings.EqualFold(tokens[2], "resourceGroups") ||
+		!strings.EqualFold(tokens[4], "providers") ||
+		!strings.EqualFold(tokens[5], "Microsoft.Compute") ||
+		!strings.EqualFold(tokens[6], "virtualMachineScaleSets") ||
+		!strings.EqualFold(tokens[8], "virtualMachines") {
+		return "", "", "", fmt.Errorf("unexpected VM scale set VM ID %q", id)
+	}
+	return tokens[3], tokens[7], tokens[9], nil
+}
+
+// ResourceGroupID builds the ID of a resource group, which is also the scope of role assignments over the group
+func ResourceGroupID(subscriptionID, resourceGroupName string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionID, resourceGroupName)
+}
+
+// ResourceID builds the ID of a resource in a resource group, e.g. ResourceID(sub, rg, "Microsoft.Network/virtualNetworks", "vnet", "subnets", "subnet")
+func ResourceID(subscriptionID, resourceGroupName, resourceType string, names ...string) string {
+	id := ResourceGroupID(subscriptionID, resourceGroupName) + "/providers/" + resourceType
+	for _, name := range names {
+		id += "/" + name
+	}
+	return id
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func TestParseZone(t *testing.T) {
+	grid := []struct {
+		Zone     string
+		Location string
+		AZ       string
+		Err      bool
+	}{
+		{Zone: "eastus-1", Location: "eastus", AZ: "1"},
+		{Zone: "westus", Location: "westus", AZ: ""},
+		{Zone: "", Err: true},
+		{Zone: "eastus-", Err: true},
+		{Zone: "eastus-1-2", Err: true},
+	}
+	for _, g := range grid {
+		location, az, err := ParseZone(g.Zone)
+		if g.Err {
+			if err == nil {
+				t.Errorf("expected error parsing zone %q", g.Zone)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error parsing zone %q: %v", g.Zone, err)
+			continue
+		}
+		if location != g.Location || az != g.AZ {
+			t.Errorf("unexpected result parsing zone %q: %q, %q", g.Zone, location, az)
+		}
+	}
+}
+
+func TestFindRegion(t *testing.T) {
+	c := &kops.Cluster{}
+	c.Spec.Subnets = append(c.Spec.Subnets, kops.ClusterSubnetSpec{Name: "a", Zone: "eastus-1"})
+	c.Spec.Subnets = append(c.Spec.Subnets, kops.ClusterSubnetSpec{Name: "b", Zone: "eastus-2"})
+
+	region, err := FindRegion(c)
+	if err != nil {
+		t.Fatalf("unexpected error finding region: %v", err)
+	}
+	if region != "eastus" {
+		t.Fatalf("unexpected region: %q", region)
+	}
+
+	c.Spec.Subnets = append(c.Spec.Subnets, kops.ClusterSubnetSpec{Name: "c", Zone: "westus2-1"})
+	if _, err := FindRegion(c); err == nil {
+		t.Fatalf("expected error for cluster spanning regions")
+	}
+}
+
+func TestParseVMScaleSetVMID(t *testing.T) {
+	id := ResourceID("sub", "rg1", "Microsoft.Compute/virtualMachineScaleSets", "vmss1", "virtualMachines", "3")
+	resourceGroup, vmss, instanceID, err := ParseVMScaleSetVMID(id)
+	if err != nil {
+		t.Fatalf("unexpected error parsing %q: %v", id, err)
+	}
+	if resourceGroup != "rg1" || vmss != "vmss1" || instanceID != "3" {
+		t.Fatalf("unexpected result parsing %q: %q, %q, %q", id, resourceGroup, vmss, instanceID)
+	}
+
+	if _, _, _, err := ParseVMScaleSetVMID(ResourceID("sub", "rg1", "Microsoft.Compute/disks", "disk1")); err == nil {
+		t.Fatalf("expected error parsing disk ID")
+	}
+}
@@ -0,0 +1,432 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	resourcesAPIVersion     = "2019-05-01"
+	networkAPIVersion       = "2019-06-01"
+	computeAPIVersion       = "2019-03-01"
+	authorizationAPIVersion = "2015-07-01"
+)
+
+// ResourceGroupsClient is a client for managing resource groups
+type ResourceGroupsClient interface {
+	CreateOrUpdate(ctx context.Context, name string, parameters ResourceGroup) error
+	List(ctx context.Context) ([]*ResourceGroup, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// VirtualNetworksClient is a client for managing virtual networks
+type VirtualNetworksClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters VirtualNetwork) error
+	List(ctx context.Context, resourceGroupName string) ([]*VirtualNetwork, error)
+	Delete(ctx context.Context, resourceGroupName, name string) error
+}
+
+// SubnetsClient is a client for managing subnets
+type SubnetsClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, virtualNetworkName, name string, parameters Subnet) error
+	List(ctx context.Context, resourceGroupName, virtualNetworkName string) ([]*Subnet, error)
+	Delete(ctx context.Context, resourceGroupName, virtualNetworkName, name string) error
+}
+
+// NetworkSecurityGroupsClient is a client for managing network security groups
+type NetworkSecurityGroupsClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters NetworkSecurityGroup) error
+	List(ctx context.Context, resourceGroupName string) ([]*NetworkSecurityGroup, error)
+	Delete(ctx context.Context, resourceGroupName, name string) error
+}
+
+// RouteTablesClient is a client for managing route tables
+type RouteTablesClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters RouteTable) error
+	List(ctx context.Context, resourceGroupName string) ([]*RouteTable, error)
+	Delete(ctx context.Context, resourceGroupName, name string) error
+}
+
+// PublicIPAddressesClient is a client for managing public IP addresses
+type PublicIPAddressesClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters PublicIPAddress) error
+	List(ctx context.Context, resourceGroupName string) ([]*PublicIPAddress, error)
+	Delete(ctx context.Context, resourceGroupName, name string) error
+}
+
+// LoadBalancersClient is a client for managing load balancers
+type LoadBalancersClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters LoadBalancer) error
+	List(ctx context.Context, resourceGroupName string) ([]*LoadBalancer, error)
+	Delete(ctx context.Context, resourceGroupName, name string) error
+}
+
+// VMScaleSetsClient is a client for managing VM scale sets
+type VMScaleSetsClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters VirtualMachineScaleSet) error
+	List(ctx context.Context, resourceGroupName string) ([]*VirtualMachineScaleSet, error)
+	Delete(ctx context.Context, resourceGroupName, name string) error
+}
+
+// VMScaleSetVMsClient is a client for managing the VMs in a VM scale set
+type VMScaleSetVMsClient interface {
+	Get(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string) (*VirtualMachineScaleSetVM, error)
+	Update(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string, parameters VirtualMachineScaleSetVM) error
+	List(ctx context.Context, resourceGroupName, vmScaleSetName string) ([]*VirtualMachineScaleSetVM, error)
+	Delete(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string) error
+}
+
+// NetworkInterfacesClient is a client for querying the network interfaces of VMs in a VM scale set
+type NetworkInterfacesClient interface {
+	ListScaleSetNetworkInterfaces(ctx context.Context, resourceGroupName, vmScaleSetName string) ([]*NetworkInterface, error)
+}
+
+// DisksClient is a client for managing managed disks
+type DisksClient interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters Disk) error
+	List(ctx context.Context, resourceGroupName string) ([]*Disk, error)
+	Delete(ctx context.Context, resourceGroupName, name string) error
+}
+
+// RoleAssignmentsClient is a client for managing role assignments
+type RoleAssignmentsClient interface {
+	Create(ctx context.Context, scope, name string, parameters RoleAssignment) error
+	List(ctx context.Context, scope string) ([]*RoleAssignment, error)
+	Delete(ctx context.Context, scope, name string) error
+}
+
+type resourceGroupsClientImpl struct {
+	arm            *armClient
+	subscriptionID string
+}
+
+var _ ResourceGroupsClient = &resourceGroupsClientImpl{}
+
+func (c *resourceGroupsClientImpl) CreateOrUpdate(ctx context.Context, name string, parameters ResourceGroup) error {
+	return c.arm.createOrUpdate(ctx, ResourceGroupID(c.subscriptionID, name), resourcesAPIVersion, parameters)
+}
+
+func (c *resourceGroupsClientImpl) List(ctx context.Context) ([]*ResourceGroup, error) {
+	var l []*ResourceGroup
+	err := c.arm.list(ctx, fmt.Sprintf("/subscriptions/%s/resourceGroups", c.subscriptionID), resourcesAPIVersion, func(raw json.RawMessage) error {
+		o := &ResourceGroup{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *resourceGroupsClientImpl) Delete(ctx context.Context, name string) error {
+	return c.arm.delete(ctx, ResourceGroupID(c.subscriptionID, name), resourcesAPIVersion)
+}
+
+// providerResourcesClient manages a top-level resource type within a resource group
+type providerResourcesClient struct {
+	arm            *armClient
+	subscriptionID string
+	// resourceType is the provider namespace and type, e.g. Microsoft.Network/virtualNetworks
+	resourceType string
+	apiVersion   string
+}
+
+func (c *providerResourcesClient) path(resourceGroupName string, names ...string) string {
+	p := ResourceGroupID(c.subscriptionID, resourceGroupName) + "/providers/" + c.resourceType
+	for _, name := range names {
+		p += "/" + name
+	}
+	return p
+}
+
+func (c *providerResourcesClient) createOrUpdate(ctx context.Context, resourceGroupName, name string, parameters interface{}) error {
+	return c.arm.createOrUpdate(ctx, c.path(resourceGroupName, name), c.apiVersion, parameters)
+}
+
+func (c *providerResourcesClient) list(ctx context.Context, resourceGroupName string, fn func(raw json.RawMessage) error) error {
+	return c.arm.list(ctx, c.path(resourceGroupName), c.apiVersion, fn)
+}
+
+func (c *providerResourcesClient) delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.arm.delete(ctx, c.path(resourceGroupName, name), c.apiVersion)
+}
+
+type virtualNetworksClientImpl struct {
+	providerResourcesClient
+}
+
+var _ VirtualNetworksClient = &virtualNetworksClientImpl{}
+
+func (c *virtualNetworksClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters VirtualNetwork) error {
+	return c.createOrUpdate(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *virtualNetworksClientImpl) List(ctx context.Context, resourceGroupName string) ([]*VirtualNetwork, error) {
+	var l []*VirtualNetwork
+	err := c.list(ctx, resourceGroupName, func(raw json.RawMessage) error {
+		o := &VirtualNetwork{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *virtualNetworksClientImpl) Delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.delete(ctx, resourceGroupName, name)
+}
+
+type subnetsClientImpl struct {
+	providerResourcesClient
+}
+
+var _ SubnetsClient = &subnetsClientImpl{}
+
+func (c *subnetsClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, virtualNetworkName, name string, parameters Subnet) error {
+	return c.arm.createOrUpdate(ctx, c.path(resourceGroupName, virtualNetworkName, "subnets", name), c.apiVersion, parameters)
+}
+
+func (c *subnetsClientImpl) List(ctx context.Context, resourceGroupName, virtualNetworkName string) ([]*Subnet, error) {
+	var l []*Subnet
+	err := c.arm.list(ctx, c.path(resourceGroupName, virtualNetworkName, "subnets"), c.apiVersion, func(raw json.RawMessage) error {
+		o := &Subnet{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *subnetsClientImpl) Delete(ctx context.Context, resourceGroupName, virtualNetworkName, name string) error {
+	return c.arm.delete(ctx, c.path(resourceGroupName, virtualNetworkName, "subnets", name), c.apiVersion)
+}
+
+type networkSecurityGroupsClientImpl struct {
+	providerResourcesClient
+}
+
+var _ NetworkSecurityGroupsClient = &networkSecurityGroupsClientImpl{}
+
+func (c *networkSecurityGroupsClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters NetworkSecurityGroup) error {
+	return c.createOrUpdate(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *networkSecurityGroupsClientImpl) List(ctx context.Context, resourceGroupName string) ([]*NetworkSecurityGroup, error) {
+	var l []*NetworkSecurityGroup
+	err := c.list(ctx, resourceGroupName, func(raw json.RawMessage) error {
+		o := &NetworkSecurityGroup{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *networkSecurityGroupsClientImpl) Delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.delete(ctx, resourceGroupName, name)
+}
+
+type routeTablesClientImpl struct {
+	providerResourcesClient
+}
+
+var _ RouteTablesClient = &routeTablesClientImpl{}
+
+func (c *routeTablesClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters RouteTable) error {
+	return c.createOrUpdate(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *routeTablesClientImpl) List(ctx context.Context, resourceGroupName string) ([]*RouteTable, error) {
+	var l []*RouteTable
+	err := c.list(ctx, resourceGroupName, func(raw json.RawMessage) error {
+		o := &RouteTable{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *routeTablesClientImpl) Delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.delete(ctx, resourceGroupName, name)
+}
+
+type publicIPAddressesClientImpl struct {
+	providerResourcesClient
+}
+
+var _ PublicIPAddressesClient = &publicIPAddressesClientImpl{}
+
+func (c *publicIPAddressesClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters PublicIPAddress) error {
+	return c.createOrUpdate(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *publicIPAddressesClientImpl) List(ctx context.Context, resourceGroupName string) ([]*PublicIPAddress, error) {
+	var l []*PublicIPAddress
+	err := c.list(ctx, resourceGroupName, func(raw json.RawMessage) error {
+		o := &PublicIPAddress{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *publicIPAddressesClientImpl) Delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.delete(ctx, resourceGroupName, name)
+}
+
+type loadBalancersClientImpl struct {
+	providerResourcesClient
+}
+
+var _ LoadBalancersClient = &loadBalancersClientImpl{}
+
+func (c *loadBalancersClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters LoadBalancer) error {
+	return c.createOrUpdate(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *loadBalancersClientImpl) List(ctx context.Context, resourceGroupName string) ([]*LoadBalancer, error) {
+	var l []*LoadBalancer
+	err := c.list(ctx, resourceGroupName, func(raw json.RawMessage) error {
+		o := &LoadBalancer{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *loadBalancersClientImpl) Delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.delete(ctx, resourceGroupName, name)
+}
+
+type vmScaleSetsClientImpl struct {
+	providerResourcesClient
+}
+
+var _ VMScaleSetsClient = &vmScaleSetsClientImpl{}
+
+func (c *vmScaleSetsClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters VirtualMachineScaleSet) error {
+	return c.createOrUpdate(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *vmScaleSetsClientImpl) List(ctx context.Context, resourceGroupName string) ([]*VirtualMachineScaleSet, error) {
+	var l []*VirtualMachineScaleSet
+	err := c.list(ctx, resourceGroupName, func(raw json.RawMessage) error {
+		o := &VirtualMachineScaleSet{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *vmScaleSetsClientImpl) Delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.delete(ctx, resourceGroupName, name)
+}
+
+type vmScaleSetVMsClientImpl struct {
+	providerResourcesClient
+}
+
+var _ VMScaleSetVMsClient = &vmScaleSetVMsClientImpl{}
+
+func (c *vmScaleSetVMsClientImpl) Get(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string) (*VirtualMachineScaleSetVM, error) {
+	o := &VirtualMachineScaleSetVM{}
+	found, err := c.arm.get(ctx, c.path(resourceGroupName, vmScaleSetName, "virtualMachines", instanceID), c.apiVersion, o)
+	if err != nil || !found {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (c *vmScaleSetVMsClientImpl) Update(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string, parameters VirtualMachineScaleSetVM) error {
+	return c.arm.createOrUpdate(ctx, c.path(resourceGroupName, vmScaleSetName, "virtualMachines", instanceID), c.apiVersion, parameters)
+}
+
+func (c *vmScaleSetVMsClientImpl) List(ctx context.Context, resourceGroupName, vmScaleSetName string) ([]*VirtualMachineScaleSetVM, error) {
+	var l []*VirtualMachineScaleSetVM
+	err := c.arm.list(ctx, c.path(resourceGroupName, vmScaleSetName, "virtualMachines"), c.apiVersion, func(raw json.RawMessage) error {
+		o := &VirtualMachineScaleSetVM{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *vmScaleSetVMsClientImpl) Delete(ctx context.Context, resourceGroupName, vmScaleSetName, instanceID string) error {
+	return c.arm.delete(ctx, c.path(resourceGroupName, vmScaleSetName, "virtualMachines", instanceID), c.apiVersion)
+}
+
+type networkInterfacesClientImpl struct {
+	providerResourcesClient
+}
+
+var _ NetworkInterfacesClient = &networkInterfacesClientImpl{}
+
+func (c *networkInterfacesClientImpl) ListScaleSetNetworkInterfaces(ctx context.Context, resourceGroupName, vmScaleSetName string) ([]*NetworkInterface, error) {
+	var l []*NetworkInterface
+	err := c.arm.list(ctx, c.path(resourceGroupName, vmScaleSetName, "networkInterfaces"), c.apiVersion, func(raw json.RawMessage) error {
+		o := &NetworkInterface{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+type disksClientImpl struct {
+	providerResourcesClient
+}
+
+var _ DisksClient = &disksClientImpl{}
+
+func (c *disksClientImpl) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, parameters Disk) error {
+	return c.createOrUpdate(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *disksClientImpl) List(ctx context.Context, resourceGroupName string) ([]*Disk, error) {
+	var l []*Disk
+	err := c.list(ctx, resourceGroupName, func(raw json.RawMessage) error {
+		o := &Disk{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *disksClientImpl) Delete(ctx context.Context, resourceGroupName, name string) error {
+	return c.delete(ctx, resourceGroupName, name)
+}
+
+type roleAssignmentsClientImpl struct {
+	arm *armClient
+}
+
+var _ RoleAssignmentsClient = &roleAssignmentsClientImpl{}
+
+func (c *roleAssignmentsClientImpl) Create(ctx context.Context, scope, name string, parameters RoleAssignment) error {
+	return c.arm.put(ctx, scope+"/providers/Microsoft.Authorization/roleAssignments/"+name, authorizationAPIVersion, parameters)
+}
+
+func (c *roleAssignmentsClientImpl) List(ctx context.Context, scope string) ([]*RoleAssignment, error) {
+	var l []*RoleAssignment
+	err := c.arm.list(ctx, scope+"/providers/Microsoft.Authorization/roleAssignments", authorizationAPIVersion, func(raw json.RawMessage) error {
+		o := &RoleAssignment{}
+		l = append(l, o)
+		return json.Unmarshal(raw, o)
+	})
+	return l, err
+}
+
+func (c *roleAssignmentsClientImpl) Delete(ctx context.Context, scope, name string) error {
+	return c.arm.delete(ctx, scope+"/providers/Microsoft.Authorization/roleAssignments/"+name, authorizationAPIVersion)
+}
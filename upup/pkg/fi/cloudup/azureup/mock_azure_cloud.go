@@ -0,0 +1,197 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+import (
+	"errors"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+	"k8s.io/kops/dnsprovider/pkg/dnsprovider"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// MockAzureCloud is an AzureCloud backed by mock clients, for use in tests
+type MockAzureCloud struct {
+	Location     string
+	Subscription string
+	GroupName    string
+	ClusterTags  map[string]string
+
+	MockResourceGroups        ResourceGroupsClient
+	MockVirtualNetworks       VirtualNetworksClient
+	MockSubnets               SubnetsClient
+	MockNetworkSecurityGroups NetworkSecurityGroupsClient
+	MockRouteTables           RouteTablesClient
+	MockPublicIPAddresses     PublicIPAddressesClient
+	MockLoadBalancers         LoadBalancersClient
+	MockVMScaleSets           VMScaleSetsClient
+	MockVMScaleSetVMs         VMScaleSetVMsClient
+	MockNetworkInterfaces     NetworkInterfacesClient
+	MockDisks                 DisksClient
+	MockRoleAssignments       RoleAssignmentsClient
+}
+
+var _ AzureCloud = &MockAzureCloud{}
+
+// InstallMockAzureCloud registers the mock cloud, so that it is returned by NewAzureCloud for its location
+func InstallMockAzureCloud(c *MockAzureCloud) {
+	azureCloudInstances[c.Location] = c
+}
+
+// ResetMockAzureClouds removes the installed mock clouds, so that NewAzureCloud connects to Azure again
+func ResetMockAzureClouds() {
+	azureCloudInstances = make(map[string]AzureCloud)
+}
+
+func (c *MockAzureCloud) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderAzure
+}
+
+func (c *MockAzureCloud) Region() string {
+	return c.Location
+}
+
+func (c *MockAzureCloud) SubscriptionID() string {
+	return c.Subscription
+}
+
+func (c *MockAzureCloud) ResourceGroupName() string {
+	return c.GroupName
+}
+
+func (c *MockAzureCloud) Tags() map[string]string {
+	return c.ClusterTags
+}
+
+func (c *MockAzureCloud) AddClusterTags(tags map[string]string) {
+	addClusterTags(c.ClusterTags, tags)
+}
+
+func (c *MockAzureCloud) ResourceGroup() ResourceGroupsClient {
+	if c.MockResourceGroups == nil {
+		klog.Fatalf("MockAzureCloud MockResourceGroups not set")
+	}
+	return c.MockResourceGroups
+}
+
+func (c *MockAzureCloud) VirtualNetwork() VirtualNetworksClient {
+	if c.MockVirtualNetworks == nil {
+		klog.Fatalf("MockAzureCloud MockVirtualNetworks not set")
+	}
+	return c.MockVirtualNetworks
+}
+
+func (c *MockAzureCloud) Subnet() SubnetsClient {
+	if c.MockSubnets == nil {
+		klog.Fatalf("MockAzureCloud MockSubnets not set")
+	}
+	return c.MockSubnets
+}
+
+func (c *MockAzureCloud) NetworkSecurityGroup() NetworkSecurityGroupsClient {
+	if c.MockNetworkSecurityGroups == nil {
+		klog.Fatalf("MockAzureCloud MockNetworkSecurityGroups not set")
+	}
+	return c.MockNetworkSecurityGroups
+}
+
+func (c *MockAzureCloud) RouteTable() RouteTablesClient {
+	if c.MockRouteTables == nil {
+		klog.Fatalf("MockAzureCloud MockRouteTables not set")
+	}
+	return c.MockRouteTables
+}
+
+func (c *MockAzureCloud) PublicIPAddress() PublicIPAddressesClient {
+	if c.MockPublicIPAddresses == nil {
+		klog.Fatalf("MockAzureCloud MockPublicIPAddresses not set")
+	}
+	return c.MockPublicIPAddresses
+}
+
+func (c *MockAzureCloud) LoadBalancer() LoadBalancersClient {
+	if c.MockLoadBalancers == nil {
+		klog.Fatalf("MockAzureCloud MockLoadBalancers not set")
+	}
+	return c.MockLoadBalancers
+}
+
+func (c *MockAzureCloud) VMScaleSet() VMScaleSetsClient {
+	if c.MockVMScaleSets == nil {
+		klog.Fatalf("MockAzureCloud MockVMScaleSets not set")
+	}
+	return c.MockVMScaleSets
+}
+
+func (c *MockAzureCloud) VMScaleSetVM() VMScaleSetVMsClient {
+	if c.MockVMScaleSetVMs == nil {
+		klog.Fatalf("MockAzureCloud MockVMScaleSetVMs not set")
+	}
+	return c.MockVMScaleSetVMs
+}
+
+func (c *MockAzureCloud) NetworkInterface() NetworkInterfacesClient {
+	if c.MockNetworkInterfaces == nil {
+		klog.Fatalf("MockAzureCloud MockNetworkInterfaces not set")
+	}
+	return c.MockNetworkInterfaces
+}
+
+func (c *MockAzureCloud) Disk() DisksClient {
+	if c.MockDisks == nil {
+		klog.Fatalf("MockAzureCloud MockDisks not set")
+	}
+	return c.MockDisks
+}
+
+func (c *MockAzureCloud) RoleAssignment() RoleAssignmentsClient {
+	if c.MockRoleAssignments == nil {
+		klog.Fatalf("MockAzureCloud MockRoleAssignments not set")
+	}
+	return c.MockRoleAssignments
+}
+
+func (c *MockAzureCloud) DNS() (dnsprovider.Interface, error) {
+	return nil, errors.New("DNS not implemented on azure")
+}
+
+func (c *MockAzureCloud) FindVPCInfo(id string) (*fi.VPCInfo, error) {
+	return findVPCInfo(c, id)
+}
+
+func (c *MockAzureCloud) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
+	return deleteGroup(c, g)
+}
+
+func (c *MockAzureCloud) DeleteInstance(i *cloudinstances.CloudInstanceGroupMember) error {
+	return deleteInstance(c, i)
+}
+
+func (c *MockAzureCloud) GetCloudGroups(cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	return getCloudGroups(c, cluster, instancegroups, warnUnmatched, nodes)
+}
+
+func (c *MockAzureCloud) FindClusterStatus(cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	return findClusterStatus(c, cluster)
+}
+
+func (c *MockAzureCloud) GetApiIngressStatus(cluster *kops.Cluster) ([]kops.ApiIngressStatus, error) {
+	return getApiIngressStatus(c, cluster)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/protokube/pkg/etcd"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// findClusterStatus discovers the status of the cluster, by looking for the tagged etcd volumes
+func findClusterStatus(c AzureCloud, cluster *kops.Cluster) (*kops.ClusterStatus, error) {
+	etcdStatus, err := findEtcdStatus(c, cluster)
+	if err != nil {
+		return nil, err
+	}
+	status := &kops.ClusterStatus{
+		EtcdClusters: etcdStatus,
+	}
+	klog.V(2).Infof("Cluster status (from cloud): %v", fi.DebugAsJsonString(status))
+	return status, nil
+}
+
+// findEtcdStatus discovers the status of etcd, by looking for the tagged etcd volumes
+func findEtcdStatus(c AzureCloud, cluster *kops.Cluster) ([]kops.EtcdClusterStatus, error) {
+	klog.V(2).Infof("Querying Azure for etcd volumes")
+	statusMap := make(map[string]*kops.EtcdClusterStatus)
+
+	disks, err := c.Disk().List(context.TODO(), c.ResourceGroupName())
+	if err != nil {
+		return nil, fmt.Errorf("error listing disks: %v", err)
+	}
+
+	for _, disk := range disks {
+		if disk.Tags[TagClusterName] != cluster.ObjectMeta.Name {
+			continue
+		}
+
+		etcdClusterName := ""
+		var etcdClusterSpec *etcd.EtcdClusterSpec
+		master := false
+		for k, v := range disk.Tags {
+			if strings.HasPrefix(k, TagNameEtcdClusterPrefix) {
+				etcdClusterName = strings.TrimPrefix(k, TagNameEtcdClusterPrefix)
+				etcdClusterSpec, err = etcd.ParseEtcdClusterSpec(etcdClusterName, v)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing etcd cluster tag %q on volume %q: %v", v, disk.Name, err)
+				}
+			} else if k == TagNameRolePrefix+TagRoleMaster {
+				master = true
+			}
+		}
+		if etcdClusterName == "" || etcdClusterSpec == nil || !master {
+			continue
+		}
+
+		status := statusMap[etcdClusterName]
+		if status == nil {
+			status = &kops.EtcdClusterStatus{
+				Name: etcdClusterName,
+			}
+			statusMap[etcdClusterName] = status
+		}
+
+		status.Members = append(status.Members, &kops.EtcdMemberStatus{
+			Name:     etcdClusterSpec.NodeName,
+			VolumeId: disk.ID,
+		})
+	}
+
+	var status []kops.EtcdClusterStatus
+	for _, v := range statusMap {
+		status = append(status, *v)
+	}
+	return status, nil
+}
+
+// getApiIngressStatus returns the address of the API load balancer, if there is one
+func getApiIngressStatus(c AzureCloud, cluster *kops.Cluster) ([]kops.ApiIngressStatus, error) {
+	ctx := context.TODO()
+
+	lbs, err := c.LoadBalancer().List(ctx, c.ResourceGroupName())
+	if err != nil {
+		return nil, fmt.Errorf("error listing load balancers: %v", err)
+	}
+
+	var ingresses []kops.ApiIngressStatus
+	for _, lb := range lbs {
+		if lb.Name != "api."+cluster.ObjectMeta.Name || lb.Tags[TagClusterName] != cluster.ObjectMeta.Name {
+			continue
+		}
+
+		for _, fe := range lb.Properties.FrontendIPConfigurations {
+			if fe.Properties.PrivateIPAddress != "" {
+				ingresses = append(ingresses, kops.ApiIngressStatus{IP: fe.Properties.PrivateIPAddress})
+			}
+			if fe.Properties.PublicIPAddress == nil {
+				continue
+			}
+			pips, err := c.PublicIPAddress().List(ctx, c.ResourceGroupName())
+			if err != nil {
+				return nil, fmt.Errorf("error listing public IP addresses: %v", err)
+			}
+			for _, pip := range pips {
+				if strings.EqualFold(pip.ID, fe.Properties.PublicIPAddress.ID) && pip.Properties.IPAddress != "" {
+					ingresses = append(ingresses, kops.ApiIngressStatus{IP: pip.Properties.IPAddress})
+				}
+			}
+		}
+	}
+	return ingresses, nil
+}
+
+// getCloudGroups returns the cloud groups backing the instance groups, matching VM scale sets by their tags
+func getCloudGroups(c AzureCloud, cluster *kops.Cluster, instancegroups []*kops.InstanceGroup, warnUnmatched bool, nodes []v1.Node) (map[string]*cloudinstances.CloudInstanceGroup, error) {
+	ctx := context.TODO()
+
+	nodeMap := make(map[string]*v1.Node)
+	for i := range nodes {
+		node := &nodes[i]
+		id := strings.ToLower(strings.TrimPrefix(node.Spec.ProviderID, "azure://"))
+		nodeMap[id] = node
+	}
+
+	vmsses, err := c.VMScaleSet().List(ctx, c.ResourceGroupName())
+	if err != nil {
+		return nil, fmt.Errorf("error listing VM scale sets: %v", err)
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	for _, vmss := range vmsses {
+		if vmss.Tags[TagClusterName] != cluster.ObjectMeta.Name {
+			continue
+		}
+
+		var instancegroup *kops.InstanceGroup
+		for _, ig := range instancegroups {
+			if ig.ObjectMeta.Name == vmss.Tags[TagNameInstanceGroup] {
+				instancegroup = ig
+				break
+			}
+		}
+		if instancegroup == nil {
+			if warnUnmatched {
+				klog.Warningf("Found VM scale set with no corresponding instance group %q", vmss.Name)
+			}
+			continue
+		}
+
+		g, err := buildCloudInstanceGroup(c, instancegroup, vmss, nodeMap)
+		if err != nil {
+			return nil, fmt.Errorf("error getting cloud instance group %q: %v", instancegroup.ObjectMeta.Name, err)
+		}
+		groups[instancegroup.ObjectMeta.Name] = g
+	}
+
+	return groups, nil
+}
+
+func buildCloudInstanceGroup(c AzureCloud, ig *kops.InstanceGroup, vmss *VirtualMachineScaleSet, nodeMap map[string]*v1.Node) (*cloudinstances.CloudInstanceGroup, error) {
+	g := &cloudinstances.CloudInstanceGroup{
+		HumanName:     vmss.Name,
+		InstanceGroup: ig,
+		MinSize:       int(fi.Int32Value(ig.Spec.MinSize)),
+		MaxSize:       int(fi.Int32Value(ig.Spec.MaxSize)),
+		Raw:           vmss,
+	}
+
+	vms, err := c.VMScaleSetVM().List(context.TODO(), c.ResourceGroupName(), vmss.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error listing VMs in VM scale set %q: %v", vmss.Name, err)
+	}
+
+	for _, vm := range vms {
+		cm := &cloudinstances.CloudInstanceGroupMember{
+			ID:                 vm.ID,
+			Node:               nodeMap[strings.ToLower(vm.ID)],
+			CloudInstanceGroup: g,
+		}
+		if vm.Properties.LatestModelApplied != nil && !*vm.Properties.LatestModelApplied {
+			g.NeedUpdate = append(g.NeedUpdate, cm)
+		} else {
+			g.Ready = append(g.Ready, cm)
+		}
+	}
+
+	return g, nil
+}
+
+// deleteGroup deletes the VM scale set backing the cloud group, including its VMs
+func deleteGroup(c AzureCloud, g *cloudinstances.CloudInstanceGroup) error {
+	vmss, ok := g.Raw.(*VirtualMachineScaleSet)
+	if !ok || vmss == nil {
+		return fmt.Errorf("unexpected raw object for cloud group %q: %T", g.HumanName, g.Raw)
+	}
+
+	klog.V(2).Infof("Deleting VM scale set %q", vmss.Name)
+	if err := c.VMScaleSet().Delete(context.TODO(), c.ResourceGroupName(), vmss.Name); err != nil {
+		return fmt.Errorf("error deleting VM scale set %q: %v", vmss.Name, err)
+	}
+	return nil
+}
+
+// deleteInstance deletes a single VM from its VM scale set
+func deleteInstance(c AzureCloud, i *cloudinstances.CloudInstanceGroupMember) error {
+	resourceGroupName, vmScaleSetName, instanceID, err := ParseVMScaleSetVMID(i.ID)
+	if err != nil {
+		return err
+	}
+
+	klog.V(2).Infof("Deleting VM %q", i.ID)
+	if err := c.VMScaleSetVM().Delete(context.TODO(), resourceGroupName, vmScaleSetName, instanceID); err != nil {
+		return fmt.Errorf("error deleting VM %q: %v", i.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,442 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azureup
+
+// This file contains the subset of the Azure Resource Manager resource representations used by kops.
+// Field names and JSON tags follow the ARM REST API.
+
+// SubResource is a reference to another resource by ID
+type SubResource struct {
+	ID string `json:"id,omitempty"`
+}
+
+// Sku describes the pricing tier of a resource
+type Sku struct {
+	Name     string `json:"name,omitempty"`
+	Tier     string `json:"tier,omitempty"`
+	Capacity *int64 `json:"capacity,omitempty"`
+}
+
+// ResourceGroup is a container for related resources
+type ResourceGroup struct {
+	ID       string            `json:"id,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Location string            `json:"location,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// VirtualNetwork is an Azure virtual network
+type VirtualNetwork struct {
+	ID         string                   `json:"id,omitempty"`
+	Name       string                   `json:"name,omitempty"`
+	Location   string                   `json:"location,omitempty"`
+	Tags       map[string]string        `json:"tags,omitempty"`
+	Properties VirtualNetworkProperties `json:"properties"`
+}
+
+// VirtualNetworkProperties are the properties of a VirtualNetwork
+type VirtualNetworkProperties struct {
+	AddressSpace      AddressSpace `json:"addressSpace"`
+	ProvisioningState string       `json:"provisioningState,omitempty"`
+}
+
+// AddressSpace is the set of CIDRs of a VirtualNetwork
+type AddressSpace struct {
+	AddressPrefixes []string `json:"addressPrefixes,omitempty"`
+}
+
+// Subnet is a subnet of a VirtualNetwork
+type Subnet struct {
+	ID         string           `json:"id,omitempty"`
+	Name       string           `json:"name,omitempty"`
+	Properties SubnetProperties `json:"properties"`
+}
+
+// SubnetProperties are the properties of a Subnet
+type SubnetProperties struct {
+	AddressPrefix        string       `json:"addressPrefix,omitempty"`
+	NetworkSecurityGroup *SubResource `json:"networkSecurityGroup,omitempty"`
+	RouteTable           *SubResource `json:"routeTable,omitempty"`
+	ProvisioningState    string       `json:"provisioningState,omitempty"`
+}
+
+// NetworkSecurityGroup is a set of firewall rules
+type NetworkSecurityGroup struct {
+	ID         string                         `json:"id,omitempty"`
+	Name       string                         `json:"name,omitempty"`
+	Location   string                         `json:"location,omitempty"`
+	Tags       map[string]string              `json:"tags,omitempty"`
+	Properties NetworkSecurityGroupProperties `json:"properties"`
+}
+
+// NetworkSecurityGroupProperties are the properties of a NetworkSecurityGroup
+type NetworkSecurityGroupProperties struct {
+	SecurityRules     []*SecurityRule `json:"securityRules,omitempty"`
+	ProvisioningState string          `json:"provisioningState,omitempty"`
+}
+
+// SecurityRule is a single rule in a NetworkSecurityGroup
+type SecurityRule struct {
+	ID         string                 `json:"id,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Properties SecurityRuleProperties `json:"properties"`
+}
+
+// SecurityRuleProperties are the properties of a SecurityRule
+type SecurityRuleProperties struct {
+	Priority                 int32  `json:"priority"`
+	Direction                string `json:"direction"`
+	Access                   string `json:"access"`
+	Protocol                 string `json:"protocol"`
+	SourceAddressPrefix      string `json:"sourceAddressPrefix,omitempty"`
+	SourcePortRange          string `json:"sourcePortRange,omitempty"`
+	DestinationAddressPrefix string `json:"destinationAddressPrefix,omitempty"`
+	DestinationPortRange     string `json:"destinationPortRange,omitempty"`
+}
+
+// RouteTable is a route table; its routes are managed by the Kubernetes cloud provider
+type RouteTable struct {
+	ID         string               `json:"id,omitempty"`
+	Name       string               `json:"name,omitempty"`
+	Location   string               `json:"location,omitempty"`
+	Tags       map[string]string    `json:"tags,omitempty"`
+	Properties RouteTableProperties `json:"properties"`
+}
+
+// RouteTableProperties are the properties of a RouteTable
+type RouteTableProperties struct {
+	Routes            []*Route `json:"routes,omitempty"`
+	ProvisioningState string   `json:"provisioningState,omitempty"`
+}
+
+// Route is a route in a RouteTable
+type Route struct {
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Properties RouteProperties `json:"properties"`
+}
+
+// RouteProperties are the properties of a Route
+type RouteProperties struct {
+	AddressPrefix    string `json:"addressPrefix,omitempty"`
+	NextHopType      string `json:"nextHopType,omitempty"`
+	NextHopIPAddress string `json:"nextHopIpAddress,omitempty"`
+}
+
+// PublicIPAddress is a public IP address
+type PublicIPAddress struct {
+	ID         string                    `json:"id,omitempty"`
+	Name       string                    `json:"name,omitempty"`
+	Location   string                    `json:"location,omitempty"`
+	Tags       map[string]string         `json:"tags,omitempty"`
+	Sku        *Sku                      `json:"sku,omitempty"`
+	Properties PublicIPAddressProperties `json:"properties"`
+}
+
+// PublicIPAddressProperties are the properties of a PublicIPAddress
+type PublicIPAddressProperties struct {
+	PublicIPAllocationMethod string `json:"publicIPAllocationMethod,omitempty"`
+	IPAddress                string `json:"ipAddress,omitempty"`
+	ProvisioningState        string `json:"provisioningState,omitempty"`
+}
+
+// LoadBalancer is an Azure load balancer
+type LoadBalancer struct {
+	ID         string                 `json:"id,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Location   string                 `json:"location,omitempty"`
+	Tags       map[string]string      `json:"tags,omitempty"`
+	Sku        *Sku                   `json:"sku,omitempty"`
+	Properties LoadBalancerProperties `json:"properties"`
+}
+
+// LoadBalancerProperties are the properties of a LoadBalancer
+type LoadBalancerProperties struct {
+	FrontendIPConfigurations []*FrontendIPConfiguration `json:"frontendIPConfigurations,omitempty"`
+	BackendAddressPools      []*BackendAddressPool      `json:"backendAddressPools,omitempty"`
+	LoadBalancingRules       []*LoadBalancingRule       `json:"loadBalancingRules,omitempty"`
+	Probes                   []*Probe                   `json:"probes,omitempty"`
+	ProvisioningState        string                     `json:"provisioningState,omitempty"`
+}
+
+// FrontendIPConfiguration is the address a LoadBalancer listens on
+type FrontendIPConfiguration struct {
+	ID         string                            `json:"id,omitempty"`
+	Name       string                            `json:"name,omitempty"`
+	Properties FrontendIPConfigurationProperties `json:"properties"`
+}
+
+// FrontendIPConfigurationProperties are the properties of a FrontendIPConfiguration
+type FrontendIPConfigurationProperties struct {
+	PrivateIPAddress          string       `json:"privateIPAddress,omitempty"`
+	PrivateIPAllocationMethod string       `json:"privateIPAllocationMethod,omitempty"`
+	Subnet                    *SubResource `json:"subnet,omitempty"`
+	PublicIPAddress           *SubResource `json:"publicIPAddress,omitempty"`
+}
+
+// BackendAddressPool is a pool of backends of a LoadBalancer
+type BackendAddressPool struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// LoadBalancingRule forwards traffic from a frontend to a backend pool
+type LoadBalancingRule struct {
+	ID         string                      `json:"id,omitempty"`
+	Name       string                      `json:"name,omitempty"`
+	Properties LoadBalancingRuleProperties `json:"properties"`
+}
+
+// LoadBalancingRuleProperties are the properties of a LoadBalancingRule
+type LoadBalancingRuleProperties struct {
+	Protocol                string       `json:"protocol"`
+	FrontendPort            int32        `json:"frontendPort"`
+	BackendPort             int32        `json:"backendPort"`
+	IdleTimeoutInMinutes    int32        `json:"idleTimeoutInMinutes,omitempty"`
+	FrontendIPConfiguration *SubResource `json:"frontendIPConfiguration,omitempty"`
+	BackendAddressPool      *SubResource `json:"backendAddressPool,omitempty"`
+	Probe                   *SubResource `json:"probe,omitempty"`
+}
+
+// Probe is a health check of a LoadBalancer
+type Probe struct {
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Properties ProbeProperties `json:"properties"`
+}
+
+// ProbeProperties are the properties of a Probe
+type ProbeProperties struct {
+	Protocol          string `json:"protocol"`
+	Port              int32  `json:"port"`
+	IntervalInSeconds int32  `json:"intervalInSeconds,omitempty"`
+	NumberOfProbes    int32  `json:"numberOfProbes,omitempty"`
+}
+
+// VirtualMachineScaleSet is a group of identical VMs
+type VirtualMachineScaleSet struct {
+	ID         string                           `json:"id,omitempty"`
+	Name       string                           `json:"name,omitempty"`
+	Location   string                           `json:"location,omitempty"`
+	Tags       map[string]string                `json:"tags,omitempty"`
+	Sku        *Sku                             `json:"sku,omitempty"`
+	Zones      []string                         `json:"zones,omitempty"`
+	Identity   *ManagedIdentity                 `json:"identity,omitempty"`
+	Properties VirtualMachineScaleSetProperties `json:"properties"`
+}
+
+// ManagedIdentity is the identity assigned to a resource
+type ManagedIdentity struct {
+	Type        string `json:"type,omitempty"`
+	PrincipalID string `json:"principalId,omitempty"`
+	TenantID    string `json:"tenantId,omitempty"`
+}
+
+// VirtualMachineScaleSetProperties are the properties of a VirtualMachineScaleSet
+type VirtualMachineScaleSetProperties struct {
+	UpgradePolicy         *UpgradePolicy                   `json:"upgradePolicy,omitempty"`
+	VirtualMachineProfile *VirtualMachineScaleSetVMProfile `json:"virtualMachineProfile,omitempty"`
+	Overprovision         *bool                            `json:"overprovision,omitempty"`
+	ProvisioningState     string                           `json:"provisioningState,omitempty"`
+}
+
+// UpgradePolicy controls how changes to the model are applied to existing VMs
+type UpgradePolicy struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+// VirtualMachineScaleSetVMProfile is the template for VMs in a scale set
+type VirtualMachineScaleSetVMProfile struct {
+	OsProfile      *OSProfile      `json:"osProfile,omitempty"`
+	StorageProfile *StorageProfile `json:"storageProfile,omitempty"`
+	NetworkProfile *NetworkProfile `json:"networkProfile,omitempty"`
+}
+
+// OSProfile configures the operating system of a VM
+type OSProfile struct {
+	ComputerNamePrefix string              `json:"computerNamePrefix,omitempty"`
+	ComputerName       string              `json:"computerName,omitempty"`
+	AdminUsername      string              `json:"adminUsername,omitempty"`
+	CustomData         string              `json:"customData,omitempty"`
+	LinuxConfiguration *LinuxConfiguration `json:"linuxConfiguration,omitempty"`
+}
+
+// LinuxConfiguration configures Linux specific settings of a VM
+type LinuxConfiguration struct {
+	DisablePasswordAuthentication bool              `json:"disablePasswordAuthentication"`
+	SSH                           *SSHConfiguration `json:"ssh,omitempty"`
+}
+
+// SSHConfiguration configures the SSH keys of a VM
+type SSHConfiguration struct {
+	PublicKeys []*SSHPublicKey `json:"publicKeys,omitempty"`
+}
+
+// SSHPublicKey is an SSH key installed for the admin user
+type SSHPublicKey struct {
+	Path    string `json:"path,omitempty"`
+	KeyData string `json:"keyData,omitempty"`
+}
+
+// StorageProfile configures the disks of a VM
+type StorageProfile struct {
+	ImageReference *ImageReference `json:"imageReference,omitempty"`
+	OsDisk         *OSDisk         `json:"osDisk,omitempty"`
+	DataDisks      []*DataDisk     `json:"dataDisks,omitempty"`
+}
+
+// ImageReference identifies the image a VM is booted from
+type ImageReference struct {
+	ID        string `json:"id,omitempty"`
+	Publisher string `json:"publisher,omitempty"`
+	Offer     string `json:"offer,omitempty"`
+	Sku       string `json:"sku,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+// OSDisk is the boot disk of a VM
+type OSDisk struct {
+	CreateOption string                 `json:"createOption,omitempty"`
+	DiskSizeGB   int32                  `json:"diskSizeGB,omitempty"`
+	ManagedDisk  *ManagedDiskParameters `json:"managedDisk,omitempty"`
+}
+
+// DataDisk is a disk attached to a VM
+type DataDisk struct {
+	Lun          int32                  `json:"lun"`
+	Name         string                 `json:"name,omitempty"`
+	CreateOption string                 `json:"createOption,omitempty"`
+	Caching      string                 `json:"caching,omitempty"`
+	DiskSizeGB   int32                  `json:"diskSizeGB,omitempty"`
+	ManagedDisk  *ManagedDiskParameters `json:"managedDisk,omitempty"`
+}
+
+// ManagedDiskParameters references or configures a managed disk
+type ManagedDiskParameters struct {
+	ID                 string `json:"id,omitempty"`
+	StorageAccountType string `json:"storageAccountType,omitempty"`
+}
+
+// NetworkProfile configures the network interfaces of VMs in a scale set
+type NetworkProfile struct {
+	NetworkInterfaceConfigurations []*NetworkInterfaceConfiguration `json:"networkInterfaceConfigurations,omitempty"`
+}
+
+// NetworkInterfaceConfiguration is the template for a network interface in a scale set
+type NetworkInterfaceConfiguration struct {
+	Name       string                                  `json:"name,omitempty"`
+	Properties NetworkInterfaceConfigurationProperties `json:"properties"`
+}
+
+// NetworkInterfaceConfigurationProperties are the properties of a NetworkInterfaceConfiguration
+type NetworkInterfaceConfigurationProperties struct {
+	Primary            bool               `json:"primary"`
+	EnableIPForwarding bool               `json:"enableIPForwarding"`
+	IPConfigurations   []*IPConfiguration `json:"ipConfigurations,omitempty"`
+}
+
+// IPConfiguration is the template for an IP address of a network interface in a scale set
+type IPConfiguration struct {
+	Name       string                    `json:"name,omitempty"`
+	Properties IPConfigurationProperties `json:"properties"`
+}
+
+// IPConfigurationProperties are the properties of an IPConfiguration
+type IPConfigurationProperties struct {
+	Primary                         bool                          `json:"primary"`
+	Subnet                          *SubResource                  `json:"subnet,omitempty"`
+	PrivateIPAddress                string                        `json:"privateIPAddress,omitempty"`
+	LoadBalancerBackendAddressPools []*SubResource                `json:"loadBalancerBackendAddressPools,omitempty"`
+	PublicIPAddressConfiguration    *PublicIPAddressConfiguration `json:"publicIPAddressConfiguration,omitempty"`
+}
+
+// PublicIPAddressConfiguration requests a public IP address for each VM in a scale set
+type PublicIPAddressConfiguration struct {
+	Name string `json:"name,omitempty"`
+}
+
+// VirtualMachineScaleSetVM is a VM in a scale set
+type VirtualMachineScaleSetVM struct {
+	ID         string                             `json:"id,omitempty"`
+	Name       string                             `json:"name,omitempty"`
+	InstanceID string                             `json:"instanceId,omitempty"`
+	Location   string                             `json:"location,omitempty"`
+	Tags       map[string]string                  `json:"tags,omitempty"`
+	Zones      []string                           `json:"zones,omitempty"`
+	Properties VirtualMachineScaleSetVMProperties `json:"properties"`
+}
+
+// VirtualMachineScaleSetVMProperties are the properties of a VirtualMachineScaleSetVM
+type VirtualMachineScaleSetVMProperties struct {
+	LatestModelApplied *bool           `json:"latestModelApplied,omitempty"`
+	OsProfile          *OSProfile      `json:"osProfile,omitempty"`
+	StorageProfile     *StorageProfile `json:"storageProfile,omitempty"`
+	ProvisioningState  string          `json:"provisioningState,omitempty"`
+}
+
+// NetworkInterface is a network interface of a VM
+type NetworkInterface struct {
+	ID         string                     `json:"id,omitempty"`
+	Name       string                     `json:"name,omitempty"`
+	Properties NetworkInterfaceProperties `json:"properties"`
+}
+
+// NetworkInterfaceProperties are the properties of a NetworkInterface
+type NetworkInterfaceProperties struct {
+	Primary          *bool              `json:"primary,omitempty"`
+	IPConfigurations []*IPConfiguration `json:"ipConfigurations,omitempty"`
+	VirtualMachine   *SubResource       `json:"virtualMachine,omitempty"`
+}
+
+// Disk is a managed disk
+type Disk struct {
+	ID         string            `json:"id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Location   string            `json:"location,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Zones      []string          `json:"zones,omitempty"`
+	Sku        *Sku              `json:"sku,omitempty"`
+	ManagedBy  string            `json:"managedBy,omitempty"`
+	Properties DiskProperties    `json:"properties"`
+}
+
+// DiskProperties are the properties of a Disk
+type DiskProperties struct {
+	DiskSizeGB        int32         `json:"diskSizeGB,omitempty"`
+	CreationData      *CreationData `json:"creationData,omitempty"`
+	DiskState         string        `json:"diskState,omitempty"`
+	ProvisioningState string        `json:"provisioningState,omitempty"`
+}
+
+// CreationData describes how a Disk was created
+type CreationData struct {
+	CreateOption string `json:"createOption,omitempty"`
+}
+
+// RoleAssignment grants a role to a principal over a scope
+type RoleAssignment struct {
+	ID         string                   `json:"id,omitempty"`
+	Name       string                   `json:"name,omitempty"`
+	Properties RoleAssignmentProperties `json:"properties"`
+}
+
+// RoleAssignmentProperties are the properties of a RoleAssignment
+type RoleAssignmentProperties struct {
+	RoleDefinitionID string `json:"roleDefinitionId,omitempty"`
+	PrincipalID      string `json:"principalId,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+}
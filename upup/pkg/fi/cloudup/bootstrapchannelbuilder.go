@@ -46,6 +46,9 @@ func (b *BootstrapChannelBuilder) Build(c *fi.ModelBuilderContext) error {
 	addons := b.buildAddons()
 	tasks := c.Tasks
 
+	overrides := newAddonOverrides(b.cluster.Spec.AddonOverrides)
+	builtin := make(map[string]bool)
+
 	for _, a := range addons.Spec.Addons {
 		key := *a.Name
 		if a.Id != "" {
@@ -69,6 +72,13 @@ func (b *BootstrapChannelBuilder) Build(c *fi.ModelBuilderContext) error {
 			return fmt.Errorf("error remapping manifest %s: %v", manifestPath, err)
 		}
 
+		// Patches are applied before hashing, so that a change to the overrides updates the addon
+		builtin[*a.Name] = true
+		manifestBytes, err = overrides.apply(*a.Name, manifestBytes)
+		if err != nil {
+			return err
+		}
+
 		// Trim whitespace
 		manifestBytes = []byte(strings.TrimSpace(string(manifestBytes)))
 
@@ -91,6 +101,14 @@ func (b *BootstrapChannelBuilder) Build(c *fi.ModelBuilderContext) error {
 
 	}
 
+	if err := overrides.verify(builtin); err != nil {
+		return err
+	}
+
+	if err := b.buildChartAddons(c, addons); err != nil {
+		return err
+	}
+
 	addonsYAML, err := utils.YamlMarshal(addons)
 	if err != nil {
 		return fmt.Errorf("error serializing addons yaml: %v", err)
@@ -517,6 +535,44 @@ func (b *BootstrapChannelBuilder) buildAddons() *channelsapi.Addons {
 		}
 	}
 
+	if kops.CloudProviderID(b.cluster.Spec.CloudProvider) == kops.CloudProviderAWS && b.cluster.Spec.Storage != nil && b.cluster.Spec.Storage.CSI != nil && fi.BoolValue(b.cluster.Spec.Storage.CSI.Enabled) {
+		key := "aws-ebs-csi-driver.addons.k8s.io"
+		version := "0.4.0"
+
+		{
+			id := "k8s-1.14"
+			location := key + "/" + id + ".yaml"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:              fi.String(key),
+				Version:           fi.String(version),
+				Selector:          map[string]string{"k8s-addon": key},
+				Manifest:          fi.String(location),
+				KubernetesVersion: ">=1.14.0",
+				Id:                id,
+			})
+		}
+	}
+
+	if b.cluster.Spec.ClusterAutoscaler != nil && fi.BoolValue(b.cluster.Spec.ClusterAutoscaler.Enabled) {
+		key := "cluster-autoscaler.addons.k8s.io"
+		version := "1.15.0"
+
+		{
+			id := "k8s-1.12"
+			location := key + "/" + id + ".yaml"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:              fi.String(key),
+				Version:           fi.String(version),
+				Selector:          map[string]string{"k8s-addon": key},
+				Manifest:          fi.String(location),
+				KubernetesVersion: ">=1.12.0",
+				Id:                id,
+			})
+		}
+	}
+
 	if kops.CloudProviderID(b.cluster.Spec.CloudProvider) == kops.CloudProviderDO {
 		key := "digitalocean-cloud-controller.addons.k8s.io"
 		version := "1.8"
@@ -1186,7 +1242,25 @@ func (b *BootstrapChannelBuilder) buildAddons() *channelsapi.Addons {
 		}
 	}
 
-	if featureflag.EnableExternalCloudController.Enabled() && b.cluster.Spec.ExternalCloudControllerManager != nil {
+	if kops.CloudProviderID(b.cluster.Spec.CloudProvider) == kops.CloudProviderAWS && b.cluster.Spec.ExternalCloudControllerManager != nil {
+		// the out-of-tree AWS cloud-controller-manager is selected by the cluster spec alone
+		key := "aws-cloud-controller.addons.k8s.io"
+		version := "1.15.0"
+
+		{
+			id := "k8s-1.14"
+			location := key + "/" + id + ".yaml"
+
+			addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+				Name:              fi.String(key),
+				Version:           fi.String(version),
+				Selector:          map[string]string{"k8s-addon": key},
+				Manifest:          fi.String(location),
+				KubernetesVersion: ">=1.14.0",
+				Id:                id,
+			})
+		}
+	} else if featureflag.EnableExternalCloudController.Enabled() && b.cluster.Spec.ExternalCloudControllerManager != nil {
 		// cloudprovider specific out-of-tree controller
 		if kops.CloudProviderID(b.cluster.Spec.CloudProvider) == kops.CloudProviderOpenstack {
 			{
@@ -1255,5 +1329,87 @@ func (b *BootstrapChannelBuilder) buildAddons() *channelsapi.Addons {
 		})
 	}
 
+	// Until the RBAC addon binds the kubelets, no node can register, not even the masters, so nothing can be
+	// scheduled; the addons below are applied after it.
+	var rbacAddons []string
+	for _, a := range addons.Spec.Addons {
+		if *a.Name == "rbac.addons.k8s.io" {
+			rbacAddons = []string{*a.Name}
+		}
+	}
+
+	// The networking addon is ready once its DaemonSet has rolled out
+	var networkingAddons []string
+	for _, a := range addons.Spec.Addons {
+		if a.Selector["role.kubernetes.io/networking"] != "1" {
+			continue
+		}
+		if !containsString(networkingAddons, *a.Name) {
+			networkingAddons = append(networkingAddons, *a.Name)
+		}
+		if daemonSet := networkingDaemonSets[*a.Name]; daemonSet != "" {
+			a.Readiness = []*channelsapi.ReadinessGate{
+				{Kind: "DaemonSet", Namespace: "kube-system", Name: daemonSet},
+			}
+		}
+		if len(rbacAddons) != 0 {
+			a.DependsOn = rbacAddons
+		}
+	}
+
+	// dns-controller is ready once its Deployment has rolled out.  It cannot be scheduled until the pod network is
+	// available, as the masters are tainted not-ready until then, so apply the networking addon first.
+	var dnsControllerAddons []string
+	for _, a := range addons.Spec.Addons {
+		if *a.Name != "dns-controller.addons.k8s.io" {
+			continue
+		}
+		dnsControllerAddons = []string{*a.Name}
+		a.Readiness = []*channelsapi.ReadinessGate{
+			{Kind: "Deployment", Namespace: "kube-system", Name: "dns-controller"},
+		}
+		a.DependsOn = appendDependencies(rbacAddons, networkingAddons)
+	}
+
+	// DNS runs on the nodes rather than the masters, so it is applied after the addons above but not waited for:
+	// the nodes may join long after the masters, and waiting would hold back every addon applied after it.
+	for _, a := range addons.Spec.Addons {
+		if *a.Name != "kube-dns.addons.k8s.io" && *a.Name != "coredns.addons.k8s.io" {
+			continue
+		}
+		a.DependsOn = appendDependencies(rbacAddons, networkingAddons, dnsControllerAddons)
+	}
+
 	return addons
 }
+
+// networkingDaemonSets holds the name of the DaemonSet in kube-system that runs each networking addon
+var networkingDaemonSets = map[string]string{
+	"networking.amazon-vpc-routed-eni":   "aws-node",
+	"networking.cilium.io":               "cilium",
+	"networking.flannel":                 "kube-flannel-ds",
+	"networking.kope.io":                 "kopeio-networking-agent",
+	"networking.kuberouter":              "kube-router",
+	"networking.projectcalico.org":       "calico-node",
+	"networking.projectcalico.org.canal": "canal",
+	"networking.romana":                  "romana-agent",
+	"networking.weave":                   "weave-net",
+}
+
+// appendDependencies returns the names of the addons in all the lists, or nil if there are none
+func appendDependencies(lists ...[]string) []string {
+	var dependencies []string
+	for _, list := range lists {
+		dependencies = append(dependencies, list...)
+	}
+	return dependencies
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -21,7 +21,9 @@ import (
 	"path"
 	"testing"
 
+	"k8s.io/kops/channels/pkg/channels"
 	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/client/simple/vfsclientset"
 	"k8s.io/kops/pkg/kopscodecs"
@@ -44,11 +46,104 @@ func TestBootstrapChannelBuilder_BuildTasks(t *testing.T) {
 	runChannelBuilderTest(t, "kopeio-vxlan")
 	runChannelBuilderTest(t, "weave")
 	runChannelBuilderTest(t, "cilium")
+	runChannelBuilderTest(t, "aws-addons")
+	runChannelBuilderTest(t, "coredns")
+	runChannelBuilderTest(t, "default")
 }
 
 func runChannelBuilderTest(t *testing.T, key string) {
 	basedir := path.Join("tests/bootstrapchannelbuilder/", key)
+	bcb := buildChannelBuilder(t, basedir)
+	cluster := bcb.cluster
 
+	context := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+	err := bcb.Build(context)
+	if err != nil {
+		t.Fatalf("error from BootstrapChannelBuilder Build: %v", err)
+	}
+
+	name := cluster.ObjectMeta.Name + "-addons-bootstrap"
+	manifestTask := context.Tasks[name]
+	if manifestTask == nil {
+		t.Fatalf("manifest task not found (%q)", name)
+	}
+
+	manifestFileTask := manifestTask.(*fitasks.ManagedFile)
+	actualManifest, err := manifestFileTask.Contents.AsString()
+	if err != nil {
+		t.Fatalf("error getting manifest as string: %v", err)
+	}
+
+	expectedManifestPath := path.Join(basedir, "manifest.yaml")
+
+	testutils.AssertMatchesFile(t, actualManifest, expectedManifestPath)
+}
+
+func TestBootstrapChannelBuilder_AddonOrder(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	grid := map[string][]string{
+		// the default AWS cluster, with kubenet
+		"default": {
+			"rbac.addons.k8s.io",
+			"dns-controller.addons.k8s.io",
+			"kube-dns.addons.k8s.io",
+		},
+		"coredns": {
+			"rbac.addons.k8s.io",
+			"networking.projectcalico.org",
+			"dns-controller.addons.k8s.io",
+			"coredns.addons.k8s.io",
+		},
+	}
+	for key, expected := range grid {
+		bcb := buildChannelBuilder(t, path.Join("tests/bootstrapchannelbuilder/", key))
+
+		kubernetesVersion, err := util.ParseKubernetesVersion(bcb.cluster.Spec.KubernetesVersion)
+		if err != nil {
+			t.Fatalf("error parsing kubernetes version: %v", err)
+		}
+		channel := &channels.Addons{APIObject: bcb.buildAddons()}
+		menu, err := channel.GetCurrent(*kubernetesVersion)
+		if err != nil {
+			t.Fatalf("error getting current addons: %v", err)
+		}
+		var addons []*channels.Addon
+		for _, addon := range menu.Addons {
+			addons = append(addons, addon)
+		}
+		sorted, err := channels.SortByDependencies(addons)
+		if err != nil {
+			t.Fatalf("error sorting addons: %v", err)
+		}
+
+		position := make(map[string]int)
+		for i, addon := range sorted {
+			position[addon.Name] = i
+
+			// the DNS addons run on the nodes, which may join long after the masters
+			if (addon.Name == "kube-dns.addons.k8s.io" || addon.Name == "coredns.addons.k8s.io") && len(addon.Spec.Readiness) != 0 {
+				t.Errorf("%s: expected no readiness gate on %s", key, addon.Name)
+			}
+		}
+		for i, name := range expected {
+			if _, found := position[name]; !found {
+				t.Errorf("%s: addon %s not found", key, name)
+				continue
+			}
+			if i != 0 && position[name] < position[expected[i-1]] {
+				t.Errorf("%s: expected %s to be applied after %s", key, name, expected[i-1])
+			}
+		}
+	}
+}
+
+func buildChannelBuilder(t *testing.T, basedir string) *BootstrapChannelBuilder {
 	clusterYamlPath := path.Join(basedir, "cluster.yaml")
 	clusterYaml, err := ioutil.ReadFile(clusterYamlPath)
 	if err != nil {
@@ -88,36 +183,12 @@ func runChannelBuilderTest(t *testing.T, key string) {
 		t.Error(err)
 	}
 
-	tf := &TemplateFunctions{cluster: cluster, modelContext: &model.KopsModelContext{Cluster: cluster}}
+	tf := &TemplateFunctions{cluster: cluster, modelContext: &model.KopsModelContext{Cluster: cluster}, region: "us-test-1"}
 	tf.AddTo(templates.TemplateFunctions, secretStore)
 
-	bcb := BootstrapChannelBuilder{
+	return &BootstrapChannelBuilder{
 		cluster:      cluster,
 		templates:    templates,
 		assetBuilder: assets.NewAssetBuilder(cluster, ""),
 	}
-
-	context := &fi.ModelBuilderContext{
-		Tasks: make(map[string]fi.Task),
-	}
-	err = bcb.Build(context)
-	if err != nil {
-		t.Fatalf("error from BootstrapChannelBuilder Build: %v", err)
-	}
-
-	name := cluster.ObjectMeta.Name + "-addons-bootstrap"
-	manifestTask := context.Tasks[name]
-	if manifestTask == nil {
-		t.Fatalf("manifest task not found (%q)", name)
-	}
-
-	manifestFileTask := manifestTask.(*fitasks.ManagedFile)
-	actualManifest, err := manifestFileTask.Contents.AsString()
-	if err != nil {
-		t.Fatalf("error getting manifest as string: %v", err)
-	}
-
-	expectedManifestPath := path.Join(basedir, "manifest.yaml")
-
-	testutils.AssertMatchesFile(t, actualManifest, expectedManifestPath)
 }
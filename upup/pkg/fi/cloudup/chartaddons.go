@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ghodss/yaml"
+	channelsapi "k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/channels/pkg/chart"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// buildChartAddons adds the chart addons of the cluster spec to the bootstrap channel.
+// The chart archives are copied alongside the channel, so that the masters need not have access to the chart repositories.
+func (b *BootstrapChannelBuilder) buildChartAddons(c *fi.ModelBuilderContext, addons *channelsapi.Addons) error {
+	for i := range b.cluster.Spec.Addons {
+		spec := &b.cluster.Spec.Addons[i]
+		if spec.Chart == nil {
+			continue
+		}
+
+		archive, err := b.fetchChart(spec.Chart)
+		if err != nil {
+			return fmt.Errorf("error fetching chart for addon %q: %v", spec.Name, err)
+		}
+
+		loaded, err := chart.LoadArchive(archive)
+		if err != nil {
+			return fmt.Errorf("error loading chart for addon %q: %v", spec.Name, err)
+		}
+
+		values := make(map[string]interface{})
+		if spec.Chart.Values != "" {
+			if err := yaml.Unmarshal([]byte(spec.Chart.Values), &values); err != nil {
+				return fmt.Errorf("error parsing values for addon %q: %v", spec.Name, err)
+			}
+		}
+
+		version := loaded.Metadata.Version
+		location := spec.Name + "/" + loaded.Metadata.Name + "-" + version + ".tgz"
+
+		name := b.cluster.ObjectMeta.Name + "-addons-" + spec.Name + "-chart"
+		c.Tasks[name] = &fitasks.ManagedFile{
+			Contents:  fi.WrapResource(fi.NewBytesResource(archive)),
+			Lifecycle: b.Lifecycle,
+			Location:  fi.String("addons/" + location),
+			Name:      fi.String(name),
+		}
+
+		addons.Spec.Addons = append(addons.Spec.Addons, &channelsapi.AddonSpec{
+			Name:     fi.String(spec.Name),
+			Version:  fi.String(version),
+			Selector: map[string]string{"k8s-addon": spec.Name},
+			Chart: &channelsapi.ChartSpec{
+				Name:        location,
+				ReleaseName: spec.Chart.ReleaseName,
+				Namespace:   spec.Chart.Namespace,
+				Values:      values,
+			},
+		})
+	}
+
+	return nil
+}
+
+// fetchChart reads the chart archive, resolving it through the index of the repository if one is specified
+func (b *BootstrapChannelBuilder) fetchChart(spec *kops.ChartSpec) ([]byte, error) {
+	if spec.Repository == "" {
+		return b.readChartFile(spec.Name)
+	}
+
+	index, err := b.readChartFile(chart.IndexURL(spec.Repository))
+	if err != nil {
+		return nil, err
+	}
+	entry, err := chart.FindInIndex(spec.Repository, index, spec.Name, spec.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := b.readChartFile(entry.URLs[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := entry.VerifyDigest(archive); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// readChartFile reads a file of a chart.  Files served over http(s) are assets, and so are
+// read from (and copied to) the file repository if one is configured.
+func (b *BootstrapChannelBuilder) readChartFile(location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q: %v", location, err)
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		return b.assetBuilder.ReadFileAsset(u)
+	}
+
+	data, err := vfs.Context.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %v", location, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func buildTestChartArchive(t *testing.T, files map[string]string) []byte {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatalf("error writing archive: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("error writing archive: %v", err)
+	}
+	return b.Bytes()
+}
+
+func TestBuildChartAddons(t *testing.T) {
+	vfs.Context.ResetMemfsContext(true)
+
+	archive := buildTestChartArchive(t, map[string]string{
+		"example/Chart.yaml":               "name: example\nversion: 1.2.3\n",
+		"example/templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: example\n",
+	})
+	index := `
+apiVersion: v1
+entries:
+  example:
+  - name: example
+    version: 1.2.3
+    urls:
+    - example-1.2.3.tgz
+`
+	for p, data := range map[string][]byte{
+		"memfs://charts/index.yaml":        []byte(index),
+		"memfs://charts/example-1.2.3.tgz": archive,
+	} {
+		vfsPath, err := vfs.Context.BuildVfsPath(p)
+		if err != nil {
+			t.Fatalf("error building path: %v", err)
+		}
+		if err := vfsPath.WriteFile(bytes.NewReader(data), nil); err != nil {
+			t.Fatalf("error writing %s: %v", p, err)
+		}
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "example.k8s.local"
+	cluster.Spec.Addons = []kops.AddonSpec{
+		{Manifest: "s3://bucket/addons/other.yaml"},
+		{
+			Name: "example.addons.k8s.io",
+			Chart: &kops.ChartSpec{
+				Repository: "memfs://charts",
+				Name:       "example",
+				Version:    "1.2.3",
+				Namespace:  "example",
+				Values:     "replicas: 2\n",
+			},
+		},
+	}
+
+	b := &BootstrapChannelBuilder{
+		cluster:      cluster,
+		assetBuilder: &assets.AssetBuilder{},
+	}
+	c := &fi.ModelBuilderContext{Tasks: make(map[string]fi.Task)}
+	addons := &api.Addons{}
+	if err := b.buildChartAddons(c, addons); err != nil {
+		t.Fatalf("error building chart addons: %v", err)
+	}
+
+	if len(addons.Spec.Addons) != 1 {
+		t.Fatalf("expected a single chart addon, got %d", len(addons.Spec.Addons))
+	}
+	addon := addons.Spec.Addons[0]
+	if fi.StringValue(addon.Name) != "example.addons.k8s.io" || fi.StringValue(addon.Version) != "1.2.3" {
+		t.Errorf("unexpected addon %+v", addon)
+	}
+	if addon.Chart.Name != "example.addons.k8s.io/example-1.2.3.tgz" || addon.Chart.Repository != "" || addon.Chart.Namespace != "example" {
+		t.Errorf("unexpected chart %+v", addon.Chart)
+	}
+	if addon.Chart.Values["replicas"] != float64(2) {
+		t.Errorf("unexpected values %v", addon.Chart.Values)
+	}
+
+	task := c.Tasks["example.k8s.local-addons-example.addons.k8s.io-chart"]
+	if task == nil {
+		t.Fatalf("chart archive task not found; tasks were %v", c.Tasks)
+	}
+	managedFile := task.(*fitasks.ManagedFile)
+	if fi.StringValue(managedFile.Location) != "addons/example.addons.k8s.io/example-1.2.3.tgz" {
+		t.Errorf("unexpected location %q", fi.StringValue(managedFile.Location))
+	}
+	contents, err := managedFile.Contents.AsBytes()
+	if err != nil {
+		t.Fatalf("error reading contents: %v", err)
+	}
+	if !bytes.Equal(contents, archive) {
+		t.Errorf("chart archive was not copied verbatim")
+	}
+}
@@ -56,8 +56,8 @@ func PerformAssignments(c *kops.Cluster) error {
 		}
 	}
 
-	// Currently only AWS uses NetworkCIDRs
-	setNetworkCIDR := (cloud.ProviderID() == kops.CloudProviderAWS) || (cloud.ProviderID() == kops.CloudProviderALI)
+	// Currently only AWS, Aliyun and Azure use NetworkCIDRs
+	setNetworkCIDR := (cloud.ProviderID() == kops.CloudProviderAWS) || (cloud.ProviderID() == kops.CloudProviderALI) || (cloud.ProviderID() == kops.CloudProviderAzure)
 	if setNetworkCIDR && c.Spec.NetworkCIDR == "" {
 		if c.SharedVPC() {
 			vpcInfo, err := cloud.FindVPCInfo(c.Spec.NetworkID)
@@ -77,6 +77,8 @@ func PerformAssignments(c *kops.Cluster) error {
 				c.Spec.NetworkCIDR = "172.20.0.0/16"
 			} else if cloud.ProviderID() == kops.CloudProviderALI {
 				c.Spec.NetworkCIDR = "192.168.0.0/16"
+			} else if cloud.ProviderID() == kops.CloudProviderAzure {
+				c.Spec.NetworkCIDR = "10.0.0.0/16"
 			}
 		}
 
@@ -101,7 +103,7 @@ func PerformAssignments(c *kops.Cluster) error {
 
 	// We only assign subnet CIDRs on AWS
 	pd := cloud.ProviderID()
-	if pd == kops.CloudProviderAWS || pd == kops.CloudProviderOpenstack || pd == kops.CloudProviderALI {
+	if pd == kops.CloudProviderAWS || pd == kops.CloudProviderOpenstack || pd == kops.CloudProviderALI || pd == kops.CloudProviderAzure {
 		// TODO: Use vpcInfo
 		err = assignCIDRsToSubnets(c)
 		if err != nil {
@@ -148,6 +148,12 @@ func (_ *Address) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *A
 	return t.RenderResource("google_compute_address", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &Address{}
+
+func (e *Address) TerraformImportID(t *terraform.TerraformTarget) string {
+	return t.Project + "/" + t.Region + "/" + fi.StringValue(e.Name)
+}
+
 func (e *Address) TerraformAddress() *terraform.Literal {
 	name := fi.StringValue(e.Name)
 
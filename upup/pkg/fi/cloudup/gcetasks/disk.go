@@ -192,3 +192,9 @@ func (_ *Disk) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Disk
 	}
 	return t.RenderResource("google_compute_disk", *e.Name, tf)
 }
+
+var _ terraform.HasTerraformImportID = &Disk{}
+
+func (e *Disk) TerraformImportID(t *terraform.TerraformTarget) string {
+	return t.Project + "/" + fi.StringValue(e.Zone) + "/" + fi.StringValue(e.Name)
+}
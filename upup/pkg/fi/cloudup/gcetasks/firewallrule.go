@@ -204,3 +204,9 @@ func (_ *FirewallRule) RenderTerraform(t *terraform.TerraformTarget, a, e, chang
 
 	return t.RenderResource("google_compute_firewall", *e.Name, tf)
 }
+
+var _ terraform.HasTerraformImportID = &FirewallRule{}
+
+func (e *FirewallRule) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.Name)
+}
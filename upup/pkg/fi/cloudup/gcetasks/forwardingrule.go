@@ -165,6 +165,12 @@ func (_ *ForwardingRule) RenderTerraform(t *terraform.TerraformTarget, a, e, cha
 	return t.RenderResource("google_compute_forwarding_rule", name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &ForwardingRule{}
+
+func (e *ForwardingRule) TerraformImportID(t *terraform.TerraformTarget) string {
+	return t.Project + "/" + t.Region + "/" + fi.StringValue(e.Name)
+}
+
 func (e *ForwardingRule) TerraformLink() *terraform.Literal {
 	name := fi.StringValue(e.Name)
 
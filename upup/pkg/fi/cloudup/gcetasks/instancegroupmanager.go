@@ -202,3 +202,9 @@ func (_ *InstanceGroupManager) RenderTerraform(t *terraform.TerraformTarget, a,
 
 	return t.RenderResource("google_compute_instance_group_manager", *e.Name, tf)
 }
+
+var _ terraform.HasTerraformImportID = &InstanceGroupManager{}
+
+func (e *InstanceGroupManager) TerraformImportID(t *terraform.TerraformTarget) string {
+	return t.Project + "/" + fi.StringValue(e.Zone) + "/" + fi.StringValue(e.Name)
+}
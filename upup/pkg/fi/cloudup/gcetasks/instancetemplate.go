@@ -580,6 +580,13 @@ func (_ *InstanceTemplate) RenderTerraform(t *terraform.TerraformTarget, a, e, c
 	return t.RenderResource("google_compute_instance_template", name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &InstanceTemplate{}
+
+func (e *InstanceTemplate) TerraformImportID(t *terraform.TerraformTarget) string {
+	// The name of the template is generated, so we import it by the name we found
+	return fi.StringValue(e.ID)
+}
+
 func (i *InstanceTemplate) TerraformLink() *terraform.Literal {
 	return terraform.LiteralSelfLink("google_compute_instance_template", *i.Name)
 }
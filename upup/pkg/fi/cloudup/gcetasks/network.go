@@ -175,6 +175,12 @@ func (_ *Network) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *N
 	return t.RenderResource("google_compute_network", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &Network{}
+
+func (e *Network) TerraformImportID(t *terraform.TerraformTarget) string {
+	return fi.StringValue(e.Name)
+}
+
 func (i *Network) TerraformName() *terraform.Literal {
 	return terraform.LiteralProperty("google_compute_network", *i.Name, "name")
 }
@@ -235,6 +235,12 @@ func (_ *Subnet) RenderSubnet(t *terraform.TerraformTarget, a, e, changes *Subne
 	return t.RenderResource("google_compute_subnetwork", *e.Name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &Subnet{}
+
+func (e *Subnet) TerraformImportID(t *terraform.TerraformTarget) string {
+	return t.Project + "/" + fi.StringValue(e.Region) + "/" + fi.StringValue(e.GCEName)
+}
+
 func (i *Subnet) TerraformName() *terraform.Literal {
 	return terraform.LiteralProperty("google_compute_subnetwork", *i.Name, "name")
 }
@@ -117,6 +117,12 @@ func (_ *TargetPool) RenderTerraform(t *terraform.TerraformTarget, a, e, changes
 	return t.RenderResource("google_compute_target_pool", name, tf)
 }
 
+var _ terraform.HasTerraformImportID = &TargetPool{}
+
+func (e *TargetPool) TerraformImportID(t *terraform.TerraformTarget) string {
+	return t.Project + "/" + t.Region + "/" + fi.StringValue(e.Name)
+}
+
 func (e *TargetPool) TerraformLink() *terraform.Literal {
 	name := fi.StringValue(e.Name)
 
@@ -247,6 +247,9 @@ func (l *Loader) addAssetFileCopyTasks(assets []*assets.FileAsset, lifecycle *fi
 				SHA:        fi.String(asset.SHAValue),
 				Lifecycle:  lifecycle,
 			}
+			if l.Cluster.Spec.Assets != nil {
+				copyFileTask.SignatureKey = l.Cluster.Spec.Assets.FileSignatureKey
+			}
 
 			context.AddTask(copyFileTask)
 			l.tasks = context.Tasks
@@ -64,6 +64,9 @@ func buildCloudupTags(cluster *api.Cluster) (sets.String, error) {
 		{
 			tags.Insert("_ali")
 		}
+
+	case api.CloudProviderAzure:
+		// No tags
 	default:
 		return nil, fmt.Errorf("unknown CloudProvider %q", cluster.Spec.CloudProvider)
 	}
@@ -19,4 +19,5 @@ package cloudup
 const TargetDirect = "direct"
 const TargetDryRun = "dryrun"
 const TargetTerraform = "terraform"
+const TargetTerraformModule = "terraform-module"
 const TargetCloudformation = "cloudformation"
@@ -31,6 +31,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -38,6 +39,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/dns"
 	"k8s.io/kops/pkg/featureflag"
+	"k8s.io/kops/pkg/flagbuilder"
 	"k8s.io/kops/pkg/model"
 	"k8s.io/kops/pkg/resources/spotinst"
 	"k8s.io/kops/upup/pkg/fi"
@@ -91,6 +93,8 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 
 	dest["DnsControllerArgv"] = tf.DnsControllerArgv
 	dest["ExternalDnsArgv"] = tf.ExternalDnsArgv
+	dest["ClusterAutoscalerArgv"] = tf.ClusterAutoscalerArgv
+	dest["CloudControllerConfigArgv"] = tf.CloudControllerConfigArgv
 
 	// TODO: Only for GCE?
 	dest["EncodeGCELabel"] = gce.EncodeGCELabel
@@ -268,6 +272,71 @@ func (tf *TemplateFunctions) ExternalDnsArgv() ([]string, error) {
 	return argv, nil
 }
 
+// ClusterAutoscalerArgv returns the args to the cluster-autoscaler, with a node group for each autoscaled InstanceGroup
+func (tf *TemplateFunctions) ClusterAutoscalerArgv() ([]string, error) {
+	config := tf.cluster.Spec.ClusterAutoscaler
+	if config == nil {
+		return nil, fmt.Errorf("clusterAutoscaler is not configured")
+	}
+
+	var argv []string
+
+	argv = append(argv, "./cluster-autoscaler")
+	argv = append(argv, "--v=2")
+	argv = append(argv, "--stderrthreshold=info")
+
+	switch kops.CloudProviderID(tf.cluster.Spec.CloudProvider) {
+	case kops.CloudProviderAWS:
+		argv = append(argv, "--cloud-provider=aws")
+	default:
+		return nil, fmt.Errorf("cluster-autoscaler is not supported on cloudprovider %q", tf.cluster.Spec.CloudProvider)
+	}
+
+	argv = append(argv, "--expander="+fi.StringValue(config.Expander))
+	argv = append(argv, fmt.Sprintf("--balance-similar-node-groups=%t", fi.BoolValue(config.BalanceSimilarNodeGroups)))
+	argv = append(argv, fmt.Sprintf("--scale-down-enabled=%t", fi.BoolValue(config.ScaleDownEnabled)))
+	if config.ScaleDownUtilizationThreshold != nil {
+		argv = append(argv, "--scale-down-utilization-threshold="+*config.ScaleDownUtilizationThreshold)
+	}
+	if config.ScaleDownUnneededTime != nil {
+		argv = append(argv, "--scale-down-unneeded-time="+config.ScaleDownUnneededTime.Duration.String())
+	}
+	if config.ScaleDownDelayAfterAdd != nil {
+		argv = append(argv, "--scale-down-delay-after-add="+config.ScaleDownDelayAfterAdd.Duration.String())
+	}
+	argv = append(argv, fmt.Sprintf("--skip-nodes-with-local-storage=%t", fi.BoolValue(config.SkipNodesWithLocalStorage)))
+	argv = append(argv, fmt.Sprintf("--skip-nodes-with-system-pods=%t", fi.BoolValue(config.SkipNodesWithSystemPods)))
+
+	var nodeGroups []string
+	for _, ig := range tf.instanceGroups {
+		if !tf.modelContext.IsAutoscaledInstanceGroup(ig) {
+			continue
+		}
+		nodeGroups = append(nodeGroups, fmt.Sprintf("--nodes=%d:%d:%s", fi.Int32Value(ig.Spec.MinSize), fi.Int32Value(ig.Spec.MaxSize), tf.modelContext.AutoscalingGroupName(ig)))
+	}
+	if len(nodeGroups) == 0 {
+		klog.Warningf("cluster-autoscaler is enabled, but no InstanceGroup has a maxSize greater than its minSize")
+	}
+	sort.Strings(nodeGroups)
+	argv = append(argv, nodeGroups...)
+
+	return argv, nil
+}
+
+// CloudControllerConfigArgv returns the args to the external cloud-controller-manager
+func (tf *TemplateFunctions) CloudControllerConfigArgv() ([]string, error) {
+	if tf.cluster.Spec.ExternalCloudControllerManager == nil {
+		return nil, fmt.Errorf("cloudControllerManager is not configured")
+	}
+
+	argv, err := flagbuilder.BuildFlagsList(tf.cluster.Spec.ExternalCloudControllerManager)
+	if err != nil {
+		return nil, err
+	}
+
+	return argv, nil
+}
+
 func (tf *TemplateFunctions) ProxyEnv() map[string]string {
 	envs := map[string]string{}
 	proxies := tf.cluster.Spec.EgressProxy
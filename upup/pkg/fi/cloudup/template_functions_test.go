@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestClusterAutoscalerArgv(t *testing.T) {
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "example.k8s.local"
+	cluster.Spec.CloudProvider = "aws"
+	cluster.Spec.ClusterAutoscaler = &kops.ClusterAutoscalerConfig{
+		Enabled:                       fi.Bool(true),
+		Expander:                      fi.String("least-waste"),
+		BalanceSimilarNodeGroups:      fi.Bool(true),
+		ScaleDownEnabled:              fi.Bool(true),
+		ScaleDownUtilizationThreshold: fi.String("0.6"),
+		ScaleDownUnneededTime:         &metav1.Duration{Duration: 5 * time.Minute},
+		SkipNodesWithLocalStorage:     fi.Bool(false),
+		SkipNodesWithSystemPods:       fi.Bool(true),
+	}
+
+	newInstanceGroup := func(name string, role kops.InstanceGroupRole, minSize, maxSize int32) *kops.InstanceGroup {
+		ig := &kops.InstanceGroup{}
+		ig.ObjectMeta.Name = name
+		ig.Spec.Role = role
+		ig.Spec.MinSize = fi.Int32(minSize)
+		ig.Spec.MaxSize = fi.Int32(maxSize)
+		return ig
+	}
+	instanceGroups := []*kops.InstanceGroup{
+		newInstanceGroup("master-us-test-1a", kops.InstanceGroupRoleMaster, 1, 3),
+		newInstanceGroup("nodes-spot", kops.InstanceGroupRoleNode, 0, 10),
+		newInstanceGroup("nodes-fixed", kops.InstanceGroupRoleNode, 2, 2),
+		newInstanceGroup("nodes", kops.InstanceGroupRoleNode, 2, 5),
+	}
+
+	modelContext := &model.KopsModelContext{Cluster: cluster, InstanceGroups: instanceGroups}
+	tf := &TemplateFunctions{cluster: cluster, instanceGroups: instanceGroups, modelContext: modelContext}
+
+	argv, err := tf.ClusterAutoscalerArgv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"./cluster-autoscaler",
+		"--v=2",
+		"--stderrthreshold=info",
+		"--cloud-provider=aws",
+		"--expander=least-waste",
+		"--balance-similar-node-groups=true",
+		"--scale-down-enabled=true",
+		"--scale-down-utilization-threshold=0.6",
+		"--scale-down-unneeded-time=5m0s",
+		"--skip-nodes-with-local-storage=false",
+		"--skip-nodes-with-system-pods=true",
+		"--nodes=0:10:nodes-spot.example.k8s.local",
+		"--nodes=2:5:nodes.example.k8s.local",
+	}
+	if !reflect.DeepEqual(argv, expected) {
+		t.Errorf("unexpected argv\nexpected %v\ngot      %v", expected, argv)
+	}
+
+	tags, err := modelContext.CloudTagsForInstanceGroup(instanceGroups[3])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tags["k8s.io/cluster-autoscaler/enabled"] != "true" || tags["k8s.io/cluster-autoscaler/example.k8s.local"] != "owned" {
+		t.Errorf("expected auto-discovery tags on autoscaled InstanceGroup, got %v", tags)
+	}
+	tags, err = modelContext.CloudTagsForInstanceGroup(instanceGroups[2])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found := tags["k8s.io/cluster-autoscaler/enabled"]; found {
+		t.Errorf("unexpected auto-discovery tags on InstanceGroup of fixed size, got %v", tags)
+	}
+
+	cluster.Spec.CloudProvider = "gce"
+	if _, err := tf.ClusterAutoscalerArgv(); err == nil {
+		t.Errorf("expected error for unsupported cloudprovider")
+	}
+}
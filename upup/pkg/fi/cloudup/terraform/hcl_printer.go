@@ -106,9 +106,10 @@ func hclPrint(node ast.Node) ([]byte, error) {
 	s = strings.Replace(s, "}\nresource", "}\n\nresource", -1)
 
 	// Workaround HCL insanity #6359: quotes are _not_ escaped in quotes (huh?)
-	// This hits the file function
+	// This hits the file function, and the list and map functions building the tags of autoscaling groups
 	s = strings.Replace(s, "(\\\"", "(\"", -1)
 	s = strings.Replace(s, "\\\")", "\")", -1)
+	s = strings.Replace(s, "\\\", \\\"", "\", \"", -1)
 
 	// We don't need to escape > or <
 	s = strings.Replace(s, "\\u003c", "<", -1)
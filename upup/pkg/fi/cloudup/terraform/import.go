@@ -0,0 +1,235 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/reflectutils"
+)
+
+// HasTerraformImportID is implemented by tasks whose Terraform resources can be imported with terraform import
+type HasTerraformImportID interface {
+	// TerraformImportID is called on the task found in the cloud, and returns the ID terraform import expects
+	TerraformImportID(t *TerraformTarget) string
+}
+
+// Import is a resource which exists in the cloud, at the address the terraform target renders it to
+type Import struct {
+	ResourceType string
+	ResourceName string
+
+	// ID is the ID of the existing resource, or empty if it cannot be imported automatically
+	ID string
+}
+
+// Address returns the address of the resource in the Terraform configuration
+func (i *Import) Address() string {
+	return i.ResourceType + "." + tfSanitize(i.ResourceName)
+}
+
+// FindImports finds the existing resources of the tasks of the context, which must target a TerraformTarget.
+// Tasks are found in dependency order, so that the IDs of their dependencies are known, and are then rendered to
+// the target to learn the address of their resources.
+func FindImports(c *fi.Context) ([]*Import, error) {
+	t, ok := c.Target.(*TerraformTarget)
+	if !ok {
+		return nil, fmt.Errorf("unexpected target type %T, expected a TerraformTarget", c.Target)
+	}
+
+	tasks := c.AllTasks()
+	keys, err := sortTasks(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var imports []*Import
+	for _, key := range keys {
+		e := tasks[key]
+
+		renderMethod := terraformRenderMethod(e)
+		if renderMethod == "" {
+			continue
+		}
+		if hl, ok := e.(fi.HasLifecycle); ok {
+			if lifecycle := hl.GetLifecycle(); lifecycle != nil && *lifecycle == fi.LifecycleIgnore {
+				continue
+			}
+		}
+		if hce, ok := e.(fi.HasCheckExisting); ok && !hce.CheckExisting(c) {
+			continue
+		}
+
+		a, err := findTask(e, c)
+		if err != nil {
+			return nil, fmt.Errorf("error finding %s: %v", key, err)
+		}
+		if a == nil {
+			klog.V(2).Infof("%s was not found, it will be created by terraform", key)
+			continue
+		}
+
+		resources, err := t.renderTask(e, renderMethod)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering %s: %v", key, err)
+		}
+
+		for _, res := range resources {
+			i := &Import{
+				ResourceType: res.ResourceType,
+				ResourceName: res.ResourceName,
+			}
+			// When a task renders several resources we cannot tell which of them the ID belongs to
+			if hid, ok := a.(HasTerraformImportID); ok && len(resources) == 1 {
+				i.ID = hid.TerraformImportID(t)
+			}
+			imports = append(imports, i)
+		}
+	}
+
+	return imports, nil
+}
+
+// sortTasks returns the keys of the tasks, ordered so that every task comes after its dependencies
+func sortTasks(tasks map[string]fi.Task) ([]string, error) {
+	dependencies := fi.FindTaskDependencies(tasks)
+
+	done := make(map[string]bool)
+	var sorted []string
+	for len(sorted) < len(tasks) {
+		var ready []string
+		for k := range tasks {
+			if done[k] {
+				continue
+			}
+			canRun := true
+			for _, dep := range dependencies[k] {
+				if !done[dep] {
+					canRun = false
+					break
+				}
+			}
+			if canRun {
+				ready = append(ready, k)
+			}
+		}
+		if len(ready) == 0 {
+			return nil, fmt.Errorf("cycle found in the dependencies of the tasks")
+		}
+
+		sort.Strings(ready)
+		for _, k := range ready {
+			done[k] = true
+		}
+		sorted = append(sorted, ready...)
+	}
+	return sorted, nil
+}
+
+// findTask calls the Find method of the task by reflection, returning nil if the task was not found
+func findTask(e fi.Task, c *fi.Context) (fi.Task, error) {
+	rv, err := reflectutils.InvokeMethod(e, "Find", c)
+	if err != nil {
+		if reflectutils.IsMethodNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !rv[1].IsNil() {
+		return nil, rv[1].Interface().(error)
+	}
+	if rv[0].IsNil() {
+		return nil, nil
+	}
+	return rv[0].Interface().(fi.Task), nil
+}
+
+// terraformRenderMethod returns the name of the method which renders the task to a TerraformTarget, or "" if it has none
+func terraformRenderMethod(e fi.Task) string {
+	targetType := reflect.TypeOf(&TerraformTarget{})
+
+	taskType := reflect.TypeOf(e)
+	for i := 0; i < taskType.NumMethod(); i++ {
+		method := taskType.Method(i)
+		if !strings.HasPrefix(method.Name, "Render") {
+			continue
+		}
+		for j := 0; j < method.Type.NumIn(); j++ {
+			if method.Type.In(j) == targetType {
+				return method.Name
+			}
+		}
+	}
+	return ""
+}
+
+// renderTask renders the task as if it did not exist, as the terraform target does, returning the resources it rendered
+func (t *TerraformTarget) renderTask(e fi.Task, renderMethod string) ([]*terraformResource, error) {
+	a := reflect.Zero(reflect.TypeOf(e)).Interface()
+	changes := reflect.New(reflect.TypeOf(e).Elem()).Interface().(fi.Task)
+	fi.BuildChanges(a, e, changes)
+
+	t.mutex.Lock()
+	before := len(t.resources)
+	t.mutex.Unlock()
+
+	rv, err := reflectutils.InvokeMethod(e, renderMethod, t, a, e, changes)
+	if err != nil {
+		return nil, err
+	}
+	if !rv[0].IsNil() {
+		return nil, rv[0].Interface().(error)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.resources[before:], nil
+}
+
+// RenderImportScript renders the imports as a shell script running terraform import.
+// If module is not empty, the resources are addressed within the module of that name.
+func RenderImportScript(imports []*Import, module string) []byte {
+	var b bytes.Buffer
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("set -e\n\n")
+	for _, i := range imports {
+		address := moduleAddress(module, i.Address())
+		if i.ID == "" {
+			fmt.Fprintf(&b, "# %s exists, but cannot be imported automatically\n", address)
+			continue
+		}
+		fmt.Fprintf(&b, "terraform import %s %s\n", shellQuote(address), shellQuote(i.ID))
+	}
+	return b.Bytes()
+}
+
+func moduleAddress(module string, address string) string {
+	if module == "" {
+		return address
+	}
+	return "module." + module + "." + address
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// testNetworkTask exists in the cloud, with an ID which is only known once it has been found
+type testNetworkTask struct {
+	Name *string
+	ID   *string
+}
+
+func (e *testNetworkTask) Run(c *fi.Context) error {
+	return nil
+}
+
+func (e *testNetworkTask) Find(c *fi.Context) (*testNetworkTask, error) {
+	e.ID = fi.String("net-1234")
+	return &testNetworkTask{Name: e.Name, ID: e.ID}, nil
+}
+
+func (_ *testNetworkTask) RenderTerraform(t *TerraformTarget, a, e, changes *testNetworkTask) error {
+	return t.RenderResource("test_network", *e.Name, &testVPC{CIDR: fi.String("172.20.0.0/16")})
+}
+
+func (e *testNetworkTask) TerraformImportID(t *TerraformTarget) string {
+	return fi.StringValue(e.ID)
+}
+
+// testSubnetTask can only be found once the ID of its network is known
+type testSubnetTask struct {
+	Name    *string
+	Network *testNetworkTask
+	Exists  bool
+}
+
+func (e *testSubnetTask) Run(c *fi.Context) error {
+	return nil
+}
+
+func (e *testSubnetTask) Find(c *fi.Context) (*testSubnetTask, error) {
+	if !e.Exists || e.Network.ID == nil {
+		return nil, nil
+	}
+	return &testSubnetTask{Name: e.Name, Network: &testNetworkTask{ID: e.Network.ID}}, nil
+}
+
+func (_ *testSubnetTask) RenderSubnet(t *TerraformTarget, a, e, changes *testSubnetTask) error {
+	return t.RenderResource("test_subnet", *e.Name, &testVPC{CIDR: fi.String("172.20.32.0/19")})
+}
+
+func (e *testSubnetTask) TerraformImportID(t *TerraformTarget) string {
+	return fi.StringValue(e.Network.ID) + "/" + fi.StringValue(e.Name)
+}
+
+// testAttachmentTask exists, but cannot be imported
+type testAttachmentTask struct {
+	Name *string
+}
+
+func (e *testAttachmentTask) Run(c *fi.Context) error {
+	return nil
+}
+
+func (e *testAttachmentTask) Find(c *fi.Context) (*testAttachmentTask, error) {
+	return &testAttachmentTask{Name: e.Name}, nil
+}
+
+func (_ *testAttachmentTask) RenderTerraform(t *TerraformTarget, a, e, changes *testAttachmentTask) error {
+	return t.RenderResource("test_attachment", *e.Name, &testVPC{})
+}
+
+// testKeypairTask is not rendered to terraform
+type testKeypairTask struct {
+	Name *string
+}
+
+func (e *testKeypairTask) Run(c *fi.Context) error {
+	return nil
+}
+
+func (e *testKeypairTask) Find(c *fi.Context) (*testKeypairTask, error) {
+	return &testKeypairTask{Name: e.Name}, nil
+}
+
+func TestFindImports(t *testing.T) {
+	network := &testNetworkTask{Name: fi.String("minimal.example.com")}
+	tasks := map[string]fi.Task{
+		"VPC/minimal.example.com":              network,
+		"Subnet/a.minimal.example.com":         &testSubnetTask{Name: fi.String("us-test-1a.minimal.example.com"), Network: network, Exists: true},
+		"Subnet/b.minimal.example.com":         &testSubnetTask{Name: fi.String("us-test-1b.minimal.example.com"), Network: network},
+		"Attachment/nodes":                     &testAttachmentTask{Name: fi.String("nodes")},
+		"Keypair/kubernetes-ca":                &testKeypairTask{Name: fi.String("kubernetes-ca")},
+		"Attachment/master-us-test-1a.masters": &testAttachmentTask{Name: fi.String("master-us-test-1a")},
+	}
+
+	target := NewTerraformTarget(&fakeCloud{}, "us-test-1", "", "", nil)
+	c, err := fi.NewContext(target, nil, target.Cloud, nil, nil, nil, true, tasks)
+	if err != nil {
+		t.Fatalf("error building context: %v", err)
+	}
+	defer c.Close()
+
+	imports, err := FindImports(c)
+	if err != nil {
+		t.Fatalf("error finding imports: %v", err)
+	}
+
+	actual := make(map[string]string)
+	for _, i := range imports {
+		actual[i.Address()] = i.ID
+	}
+	expected := map[string]string{
+		"test_network.minimal-example-com":           "net-1234",
+		"test_subnet.us-test-1a-minimal-example-com": "net-1234/us-test-1a.minimal.example.com",
+		"test_attachment.nodes":                      "",
+		"test_attachment.master-us-test-1a":          "",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected imports %v, expected %v", actual, expected)
+	}
+}
+
+func TestRenderImports(t *testing.T) {
+	imports := []*Import{
+		{ResourceType: "aws_vpc", ResourceName: "minimal.example.com", ID: "vpc-1234"},
+		{ResourceType: "aws_iam_role_policy", ResourceName: "nodes.minimal.example.com", ID: "nodes.minimal.example.com:nodes.minimal.example.com"},
+		{ResourceType: "aws_autoscaling_attachment", ResourceName: "nodes"},
+	}
+
+	script := string(RenderImportScript(imports, ""))
+	expectedScript := `#!/bin/sh
+set -e
+
+terraform import 'aws_vpc.minimal-example-com' 'vpc-1234'
+terraform import 'aws_iam_role_policy.nodes-minimal-example-com' 'nodes.minimal.example.com:nodes.minimal.example.com'
+# aws_autoscaling_attachment.nodes exists, but cannot be imported automatically
+`
+	if script != expectedScript {
+		t.Errorf("unexpected script:\n%s\nexpected:\n%s", script, expectedScript)
+	}
+
+	moduleScript := string(RenderImportScript(imports[:1], "minimal-example-com"))
+	expectedModuleScript := `#!/bin/sh
+set -e
+
+terraform import 'module.minimal-example-com.aws_vpc.minimal-example-com' 'vpc-1234'
+`
+	if moduleScript != expectedModuleScript {
+		t.Errorf("unexpected script for a module:\n%s\nexpected:\n%s", moduleScript, expectedModuleScript)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	grid := map[string]string{
+		"vpc-1234":  "'vpc-1234'",
+		"it's":      `'it'\''s'`,
+		"a_b c/d:e": "'a_b c/d:e'",
+	}
+	for s, expected := range grid {
+		if actual := shellQuote(s); actual != expected {
+			t.Errorf("shellQuote(%q) was %q, expected %q", s, actual, expected)
+		}
+	}
+}
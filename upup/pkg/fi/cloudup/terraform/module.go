@@ -0,0 +1,325 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// moduleFiles lists the files of a module, with the resource types written to each.
+// A type ending in an underscore matches every type it prefixes; resources which match none are written to main.tf.
+var moduleFiles = []struct {
+	File  string
+	Types []string
+}{
+	{
+		File: "iam.tf",
+		Types: []string{
+			"aws_iam_",
+			"google_project_iam_",
+			"google_service_account",
+			"google_service_account_",
+		},
+	},
+	{
+		File: "network.tf",
+		Types: []string{
+			"aws_egress_only_internet_gateway",
+			"aws_eip",
+			"aws_elb",
+			"aws_internet_gateway",
+			"aws_lb",
+			"aws_lb_",
+			"aws_load_balancer_",
+			"aws_nat_gateway",
+			"aws_route",
+			"aws_route_table",
+			"aws_route_table_association",
+			"aws_security_group",
+			"aws_security_group_rule",
+			"aws_subnet",
+			"aws_vpc",
+			"aws_vpc_",
+			"google_compute_address",
+			"google_compute_firewall",
+			"google_compute_forwarding_rule",
+			"google_compute_http_health_check",
+			"google_compute_network",
+			"google_compute_route",
+			"google_compute_subnetwork",
+			"google_compute_target_pool",
+		},
+	},
+	{
+		File: "compute.tf",
+		Types: []string{
+			"aws_autoscaling_",
+			"aws_ebs_volume",
+			"aws_instance",
+			"aws_key_pair",
+			"aws_launch_configuration",
+			"aws_launch_template",
+			"google_compute_disk",
+			"google_compute_instance",
+			"google_compute_instance_",
+		},
+	},
+}
+
+const moduleMainFile = "main.tf"
+
+// moduleRequiredVersion is the Terraform version a module requires.  Modules use locals, added in 0.10.3,
+// and are written in the syntax of Terraform 0.11 (e.g. "locals = {"), which Terraform 0.12 no longer accepts.
+const moduleRequiredVersion = ">= 0.10.3, < 0.12.0"
+
+// moduleFile returns the file of the module that resources of the type are written to
+func moduleFile(resourceType string) string {
+	for _, f := range moduleFiles {
+		for _, t := range f.Types {
+			if resourceType == t || (strings.HasSuffix(t, "_") && strings.HasPrefix(resourceType, t)) {
+				return f.File
+			}
+		}
+	}
+	return moduleMainFile
+}
+
+// terraformVariable is an input variable of a module
+type terraformVariable struct {
+	Description string      `json:"description"`
+	Default     interface{} `json:"default"`
+}
+
+// autoscalingGroupTags is the data source which turns the tags variable into the list of tags an autoscaling group
+// takes, as Terraform 0.11 can only iterate over a map with count.
+const autoscalingGroupTags = "autoscaling_group_tags"
+
+// moduleBuilder replaces the values of a module which users commonly override with input variables
+type moduleBuilder struct {
+	clusterName string
+	variables   map[string]*terraformVariable
+	// locals holds the local values the resources refer to, such as their own tags
+	locals map[string]interface{}
+	// data holds the data sources the resources refer to, by type and name
+	data map[string]map[string]interface{}
+}
+
+// variable declares an input variable, returning the expression which refers to it
+func (b *moduleBuilder) variable(name string, description string, defaultValue interface{}) string {
+	b.variables[name] = &terraformVariable{
+		Description: description,
+		Default:     defaultValue,
+	}
+	return "${var." + name + "}"
+}
+
+// variablePrefix returns the prefix of the variables of a resource, which is the name of its instance group
+// for the resources kops names after instance groups, such as nodes or master-us-east-1a
+func (b *moduleBuilder) variablePrefix(resourceName string) string {
+	name := tfSanitize(resourceName)
+	if b.clusterName != "" {
+		name = strings.TrimSuffix(name, "-"+tfSanitize(b.clusterName))
+		name = strings.TrimSuffix(name, "-masters")
+	}
+	return name
+}
+
+// parameterize replaces the values of a resource with variables
+func (b *moduleBuilder) parameterize(resourceType string, resourceName string, item map[string]interface{}) {
+	prefix := b.variablePrefix(resourceName)
+
+	switch resourceType {
+	case "aws_autoscaling_group":
+		if v, found := item["min_size"]; found {
+			item["min_size"] = b.variable(prefix+"_min_size", "Minimum number of instances of "+prefix, v)
+		}
+		if v, found := item["max_size"]; found {
+			item["max_size"] = b.variable(prefix+"_max_size", "Maximum number of instances of "+prefix, v)
+		}
+		// The provider does not accept tag blocks alongside a list of tags, so the tag blocks are declared as a
+		// local list, to which the tags variable is appended.  The list is built with the list and map functions,
+		// as Terraform 0.11 has no syntax for a list of maps, and its values are strings, as a map takes a single type.
+		var tags []string
+		if blocks, ok := item["tag"].([]interface{}); ok {
+			for _, block := range blocks {
+				if block, ok := block.(map[string]interface{}); ok {
+					tags = append(tags, terraformMap(block))
+				}
+			}
+		}
+		delete(item, "tag")
+		name := localName(resourceType, resourceName, "tags")
+		b.locals[name] = "${list(" + strings.Join(tags, ", ") + ")}"
+		item["tags"] = []interface{}{"${concat(local." + name + ", data.null_data_source." + autoscalingGroupTags + ".*.outputs)}"}
+		b.addData("null_data_source", autoscalingGroupTags, map[string]interface{}{
+			"count": "${length(keys(var.tags))}",
+			"inputs": map[string]interface{}{
+				"key":                 "${element(keys(var.tags), count.index)}",
+				"value":               "${element(values(var.tags), count.index)}",
+				"propagate_at_launch": "true",
+			},
+		})
+
+	case "aws_launch_configuration", "aws_launch_template":
+		if v, found := item["image_id"]; found {
+			item["image_id"] = b.variable(prefix+"_image_id", "AMI of the instances of "+prefix, v)
+		}
+
+	case "google_compute_instance_group_manager":
+		if v, found := item["target_size"]; found {
+			item["target_size"] = b.variable(prefix+"_target_size", "Number of instances of "+prefix, v)
+		}
+
+	case "google_compute_instance_template":
+		if disks, ok := item["disk"].([]interface{}); ok {
+			for _, disk := range disks {
+				if disk, ok := disk.(map[string]interface{}); ok && disk["boot"] == true && disk["source_image"] != nil {
+					disk["source_image"] = b.variable(prefix+"_image", "Image of the instances of "+prefix, disk["source_image"])
+				}
+			}
+		}
+	}
+
+	if tags, ok := item["tags"].(map[string]interface{}); ok {
+		item["tags"] = b.mergeTags(resourceType, resourceName, tags)
+	}
+}
+
+// mergeTags returns an expression which adds the tags variable to the tags of a resource.
+// The tags of the resource are declared as a local value, as Terraform 0.11 has no syntax for a map within an expression.
+func (b *moduleBuilder) mergeTags(resourceType string, resourceName string, tags map[string]interface{}) string {
+	name := localName(resourceType, resourceName, "tags")
+	b.locals[name] = tags
+	return "${merge(local." + name + ", var.tags)}"
+}
+
+// terraformMap returns a call of the map function building the map, with its values as strings
+func terraformMap(m map[string]interface{}) string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var args []string
+	for _, k := range keys {
+		args = append(args, strconv.Quote(k), strconv.Quote(fmt.Sprint(m[k])))
+	}
+	return "map(" + strings.Join(args, ", ") + ")"
+}
+
+// localName returns the name of a local value of a resource
+func localName(resourceType string, resourceName string, suffix string) string {
+	return resourceType + "_" + tfSanitize(resourceName) + "_" + suffix
+}
+
+// addData declares a data source
+func (b *moduleBuilder) addData(dataType string, name string, item map[string]interface{}) {
+	if b.data[dataType] == nil {
+		b.data[dataType] = make(map[string]interface{})
+	}
+	b.data[dataType][name] = item
+}
+
+// buildModule renders the resources and variables as a module
+func (t *TerraformTarget) buildModule(outputVariables map[string]interface{}, localVariables map[string]interface{}, terraformConfiguration map[string]interface{}) error {
+	b := &moduleBuilder{
+		clusterName: t.ClusterName,
+		variables:   make(map[string]*terraformVariable),
+		locals:      make(map[string]interface{}),
+		data:        make(map[string]map[string]interface{}),
+	}
+	b.variable("cluster_name", "Name of the cluster, passed through to the cluster_name output; it does not rename any resource", t.ClusterName)
+	b.variable("tags", "Additional tags to apply to the resources of the cluster", map[string]string{})
+
+	resourcesByFile := make(map[string]map[string]map[string]interface{})
+	for _, res := range t.resources {
+		tfName := tfSanitize(res.ResourceName)
+
+		// We round-trip the resource through JSON so we can treat every resource type alike
+		data, err := json.Marshal(res.Item)
+		if err != nil {
+			return fmt.Errorf("error marshaling %s.%s: %v", res.ResourceType, tfName, err)
+		}
+		var item map[string]interface{}
+		if err := json.Unmarshal(data, &item); err != nil {
+			return fmt.Errorf("error parsing %s.%s: %v", res.ResourceType, tfName, err)
+		}
+		b.parameterize(res.ResourceType, res.ResourceName, item)
+
+		file := moduleFile(res.ResourceType)
+		if resourcesByFile[file] == nil {
+			resourcesByFile[file] = make(map[string]map[string]interface{})
+		}
+		if resourcesByFile[file][res.ResourceType] == nil {
+			resourcesByFile[file][res.ResourceType] = make(map[string]interface{})
+		}
+		resourcesByFile[file][res.ResourceType][tfName] = item
+	}
+
+	// The name of the cluster is part of the names of many resources, of DNS records and of the state store paths,
+	// so the variable only sets the output and local value, for root modules that refer to the cluster by name
+	if outputVariables["cluster_name"] != nil {
+		outputVariables["cluster_name"] = map[string]interface{}{"value": "${var.cluster_name}"}
+	}
+	if localVariables["cluster_name"] != nil {
+		localVariables["cluster_name"] = "${var.cluster_name}"
+	}
+	if len(b.locals) != 0 {
+		if localVariables == nil {
+			localVariables = make(map[string]interface{})
+		}
+		for k, v := range b.locals {
+			localVariables[k] = v
+		}
+	}
+
+	files := make(map[string]map[string]interface{})
+	for file, resources := range resourcesByFile {
+		files[file] = map[string]interface{}{"resource": resources}
+	}
+	if len(b.data) != 0 {
+		// data sources are only declared for the tags of autoscaling groups, so they are written alongside them
+		files[moduleFile("aws_autoscaling_group")]["data"] = b.data
+	}
+	if len(localVariables) != 0 {
+		if files[moduleMainFile] == nil {
+			files[moduleMainFile] = make(map[string]interface{})
+		}
+		files[moduleMainFile]["locals"] = localVariables
+	}
+	files["variables.tf"] = map[string]interface{}{"variable": b.variables}
+	if len(outputVariables) != 0 {
+		files["outputs.tf"] = map[string]interface{}{"output": outputVariables}
+	}
+	terraformConfiguration["required_version"] = moduleRequiredVersion
+	files["versions.tf"] = map[string]interface{}{"terraform": terraformConfiguration}
+
+	for file, data := range files {
+		rendered, err := renderTerraform(data)
+		if err != nil {
+			return fmt.Errorf("error rendering %s: %v", file, err)
+		}
+		t.files[file] = rendered
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	hcl_parser "github.com/hashicorp/hcl/hcl/parser"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type fakeCloud struct {
+	fi.Cloud
+}
+
+func (c *fakeCloud) ProviderID() kops.CloudProviderID {
+	return kops.CloudProviderAWS
+}
+
+type testASG struct {
+	Name    *string                  `json:"name"`
+	MinSize *int64                   `json:"min_size"`
+	MaxSize *int64                   `json:"max_size"`
+	Tags    []map[string]interface{} `json:"tag"`
+	Config  *Literal                 `json:"launch_configuration"`
+}
+
+type testLaunchConfiguration struct {
+	ImageID *string `json:"image_id"`
+}
+
+type testVPC struct {
+	CIDR *string           `json:"cidr_block"`
+	Tags map[string]string `json:"tags"`
+}
+
+func TestModuleFile(t *testing.T) {
+	grid := map[string]string{
+		"aws_iam_role":                          "iam.tf",
+		"aws_vpc":                               "network.tf",
+		"aws_route":                             "network.tf",
+		"aws_route_table_association":           "network.tf",
+		"aws_route53_record":                    "main.tf",
+		"aws_vpc_dhcp_options":                  "network.tf",
+		"aws_launch_configuration":              "compute.tf",
+		"google_compute_instance_group_manager": "compute.tf",
+		"google_compute_firewall":               "network.tf",
+		"aws_s3_bucket_object":                  "main.tf",
+	}
+	for resourceType, expected := range grid {
+		if actual := moduleFile(resourceType); actual != expected {
+			t.Errorf("moduleFile(%q) was %q, expected %q", resourceType, actual, expected)
+		}
+	}
+}
+
+func TestFinishModule(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "terraform")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(outDir)
+
+	target := NewTerraformTarget(&fakeCloud{}, "us-test-1", "", outDir, nil)
+	target.ClusterName = "minimal.example.com"
+	target.Module = true
+
+	if err := target.RenderResource("aws_autoscaling_group", "nodes.minimal.example.com", &testASG{
+		Name:    fi.String("nodes.minimal.example.com"),
+		MinSize: fi.Int64(2),
+		MaxSize: fi.Int64(3),
+		Tags:    []map[string]interface{}{{"key": "KubernetesCluster", "value": "minimal.example.com", "propagate_at_launch": true}},
+		Config:  LiteralProperty("aws_launch_configuration", "nodes.minimal.example.com", "id"),
+	}); err != nil {
+		t.Fatalf("error rendering resource: %v", err)
+	}
+	if err := target.RenderResource("aws_launch_configuration", "nodes.minimal.example.com", &testLaunchConfiguration{
+		ImageID: fi.String("ami-12345678"),
+	}); err != nil {
+		t.Fatalf("error rendering resource: %v", err)
+	}
+	if err := target.RenderResource("aws_vpc", "minimal.example.com", &testVPC{
+		CIDR: fi.String("172.20.0.0/16"),
+		Tags: map[string]string{"KubernetesCluster": "minimal.example.com", "Name": "minimal.example.com"},
+	}); err != nil {
+		t.Fatalf("error rendering resource: %v", err)
+	}
+	if err := target.AddOutputVariable("cluster_name", LiteralFromStringValue("minimal.example.com")); err != nil {
+		t.Fatalf("error adding output: %v", err)
+	}
+
+	if err := target.Finish(nil); err != nil {
+		t.Fatalf("error finishing: %v", err)
+	}
+
+	expected := map[string][]string{
+		"variables.tf": {
+			`variable "nodes_min_size" {`,
+			`variable "nodes_image_id" {`,
+			`default     = "ami-12345678"`,
+			`variable "tags" {`,
+			`variable "cluster_name" {`,
+			`default     = "minimal.example.com"`,
+		},
+		"compute.tf": {
+			`min_size             = "${var.nodes_min_size}"`,
+			`launch_configuration = "${aws_launch_configuration.nodes-minimal-example-com.id}"`,
+			`["${concat(local.aws_autoscaling_group_nodes-minimal-example-com_tags, data.null_data_source.autoscaling_group_tags.*.outputs)}"]`,
+			`data "null_data_source" "autoscaling_group_tags" {`,
+			`count = "${length(keys(var.tags))}"`,
+			`key                 = "${element(keys(var.tags), count.index)}"`,
+			`image_id = "${var.nodes_image_id}"`,
+		},
+		"network.tf": {
+			`tags       = "${merge(local.aws_vpc_minimal-example-com_tags, var.tags)}"`,
+		},
+		"main.tf": {
+			`aws_vpc_minimal-example-com_tags = {`,
+			`KubernetesCluster = "minimal.example.com"`,
+			`Name              = "minimal.example.com"`,
+			`aws_autoscaling_group_nodes-minimal-example-com_tags = "${list(map("key", "KubernetesCluster", "propagate_at_launch", "true", "value", "minimal.example.com"))}"`,
+		},
+		"outputs.tf": {
+			`value = "${var.cluster_name}"`,
+		},
+		"versions.tf": {
+			`required_version = ">= 0.10.3, < 0.12.0"`,
+		},
+	}
+	for file, lines := range expected {
+		data, err := ioutil.ReadFile(filepath.Join(outDir, file))
+		if err != nil {
+			t.Errorf("error reading %s: %v", file, err)
+			continue
+		}
+		for _, line := range lines {
+			if !strings.Contains(string(data), line) {
+				t.Errorf("expected %s to contain %q, was:\n%s", file, line, data)
+			}
+		}
+		if strings.Contains(string(data), `provider "aws"`) {
+			t.Errorf("expected %s not to configure the provider", file)
+		}
+	}
+
+	// The provider rejects tag blocks alongside a list of tags
+	data, err := ioutil.ReadFile(filepath.Join(outDir, "compute.tf"))
+	if err != nil {
+		t.Fatalf("error reading compute.tf: %v", err)
+	}
+	f, err := hcl_parser.Parse(data)
+	if err != nil {
+		t.Fatalf("error parsing compute.tf: %v", err)
+	}
+	groups := f.Node.(*ast.ObjectList).Filter("resource", "aws_autoscaling_group", "nodes-minimal-example-com")
+	if len(groups.Items) != 1 {
+		t.Fatalf("expected a single autoscaling group in compute.tf, found %d", len(groups.Items))
+	}
+	group := groups.Items[0].Val.(*ast.ObjectType).List
+	if n := len(group.Filter("tag").Items); n != 0 {
+		t.Errorf("expected the autoscaling group to have no tag blocks, found %d", n)
+	}
+	if n := len(group.Filter("tags").Items); n != 1 {
+		t.Errorf("expected the autoscaling group to have a single list of tags, found %d", n)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "kubernetes.tf")); !os.IsNotExist(err) {
+		t.Errorf("expected kubernetes.tf not to be written for a module")
+	}
+}
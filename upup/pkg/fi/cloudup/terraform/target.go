@@ -38,6 +38,9 @@ type TerraformTarget struct {
 
 	ClusterName string
 
+	// Module is true if we write a reusable module, with variables and a file per resource category, instead of kubernetes.tf
+	Module bool
+
 	outDir string
 
 	// mutex protects the following items (resources & files)
@@ -259,46 +262,36 @@ func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 	terraformConfiguration := make(map[string]interface{})
 	terraformConfiguration["required_version"] = ">= 0.9.3"
 
-	data := make(map[string]interface{})
-	data["terraform"] = terraformConfiguration
-	data["resource"] = resourcesByType
-	if len(providersByName) != 0 {
-		data["provider"] = providersByName
-	}
-	if len(outputVariables) != 0 {
-		data["output"] = outputVariables
-	}
-	if len(localVariables) != 0 {
-		data["locals"] = localVariables
-	}
-
-	jsonBytes, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling terraform data to json: %v", err)
-	}
-
-	useJson := false
-
-	if useJson {
-		t.files["kubernetes.tf"] = jsonBytes
+	if t.Module {
+		// The provider is configured by the root module which uses this module
+		if err := t.buildModule(outputVariables, localVariables, terraformConfiguration); err != nil {
+			return err
+		}
 	} else {
-		f, err := hcl_parser.Parse(jsonBytes)
-		if err != nil {
-			return fmt.Errorf("error parsing terraform json: %v", err)
+		data := make(map[string]interface{})
+		data["terraform"] = terraformConfiguration
+		data["resource"] = resourcesByType
+		if len(providersByName) != 0 {
+			data["provider"] = providersByName
+		}
+		if len(outputVariables) != 0 {
+			data["output"] = outputVariables
+		}
+		if len(localVariables) != 0 {
+			data["locals"] = localVariables
 		}
 
-		b, err := hclPrint(f)
+		b, err := renderTerraform(data)
 		if err != nil {
-			return fmt.Errorf("error writing terraform data to output: %v", err)
+			return err
 		}
-
 		t.files["kubernetes.tf"] = b
 	}
 
 	for relativePath, contents := range t.files {
 		p := path.Join(t.outDir, relativePath)
 
-		err = os.MkdirAll(path.Dir(p), os.FileMode(0755))
+		err := os.MkdirAll(path.Dir(p), os.FileMode(0755))
 		if err != nil {
 			return fmt.Errorf("error creating output directory %q: %v", path.Dir(p), err)
 		}
@@ -313,3 +306,28 @@ func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 
 	return nil
 }
+
+// renderTerraform renders terraform configuration, expressed as the terraform JSON syntax, as HCL
+func renderTerraform(data map[string]interface{}) ([]byte, error) {
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling terraform data to json: %v", err)
+	}
+
+	useJson := false
+
+	if useJson {
+		return jsonBytes, nil
+	}
+
+	f, err := hcl_parser.Parse(jsonBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing terraform json: %v", err)
+	}
+
+	b, err := hclPrint(f)
+	if err != nil {
+		return nil, fmt.Errorf("error writing terraform data to output: %v", err)
+	}
+	return b, nil
+}
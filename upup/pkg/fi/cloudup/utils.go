@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/aliup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/azureup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/baremetal"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
@@ -157,6 +158,22 @@ func BuildCloud(cluster *kops.Cluster) (fi.Cloud, error) {
 
 			cloud = aliCloud
 		}
+
+	case kops.CloudProviderAzure:
+		{
+			region, err := azureup.FindRegion(cluster)
+			if err != nil {
+				return nil, err
+			}
+
+			cloudTags := map[string]string{azureup.TagClusterName: cluster.ObjectMeta.Name}
+			azureCloud, err := azureup.NewAzureCloud(azureup.FindSubscriptionID(cluster), azureup.FindResourceGroupName(cluster), region, cloudTags)
+			if err != nil {
+				return nil, err
+			}
+
+			cloud = azureCloud
+		}
 	default:
 		return nil, fmt.Errorf("unknown CloudProvider %q", cluster.Spec.CloudProvider)
 	}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fitasks
+
+import (
+	"fmt"
+
+	"k8s.io/kops/pkg/encryptionatrest"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// EncryptionKeySet creates the keys that the kube-apiserver uses to encrypt resources in etcd.
+// Existing keys are never changed; they are rotated by kops rotate encryption-key.
+//go:generate fitask -type=EncryptionKeySet
+type EncryptionKeySet struct {
+	Name      *string
+	Lifecycle *fi.Lifecycle
+}
+
+var _ fi.HasCheckExisting = &EncryptionKeySet{}
+
+// CheckExisting is always true, so the keys are not regenerated e.g. on terraform
+func (e *EncryptionKeySet) CheckExisting(c *fi.Context) bool {
+	return true
+}
+
+func (e *EncryptionKeySet) Find(c *fi.Context) (*EncryptionKeySet, error) {
+	keys, err := encryptionatrest.LoadKeySet(c.SecretStore)
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		return nil, nil
+	}
+
+	actual := &EncryptionKeySet{
+		Name: e.Name,
+	}
+
+	// Avoid spurious changes
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+func (e *EncryptionKeySet) Run(c *fi.Context) error {
+	return fi.DefaultDeltaRunMethod(e, c)
+}
+
+func (s *EncryptionKeySet) CheckChanges(a, e, changes *EncryptionKeySet) error {
+	if a != nil {
+		if changes.Name != nil {
+			return fi.CannotChangeField("Name")
+		}
+	}
+	return nil
+}
+
+func (_ *EncryptionKeySet) Render(c *fi.Context, a, e, changes *EncryptionKeySet) error {
+	keys, err := encryptionatrest.NewKeySet()
+	if err != nil {
+		return err
+	}
+
+	data, err := keys.Encode()
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := c.SecretStore.GetOrCreateSecret(encryptionatrest.SecretName, &fi.Secret{Data: data}); err != nil {
+		return fmt.Errorf("error creating secret %q: %v", encryptionatrest.SecretName, err)
+	}
+
+	return nil
+}
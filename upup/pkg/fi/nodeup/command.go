@@ -34,6 +34,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/vault"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/cloudinit"
 	"k8s.io/kops/upup/pkg/fi/nodeup/local"
@@ -64,36 +65,119 @@ type NodeUpCommand struct {
 	cluster        *api.Cluster
 	config         *nodeup.Config
 	instanceGroup  *api.InstanceGroup
+	vaultClient    *vault.Client
+}
+
+// nodeupTasks is the task graph built from the nodeup configuration and the cluster spec
+type nodeupTasks struct {
+	tasks map[string]fi.Task
+	// inPlace holds the keys of the tasks that are safe to apply to a running node
+	inPlace      sets.String
+	configBase   vfs.Path
+	nodeTags     sets.String
+	modelContext *model.NodeupModelContext
 }
 
 // Run is responsible for perform the nodeup process
 func (c *NodeUpCommand) Run(out io.Writer) error {
+	built, err := c.buildTasks()
+	if err != nil {
+		return err
+	}
+
+	taskMap := built.tasks
+
+	var cloud fi.Cloud
+	var keyStore fi.Keystore
+	var secretStore fi.SecretStore
+	var target fi.Target
+	checkExisting := true
+
+	switch c.Target {
+	case "direct":
+		target = &local.LocalTarget{
+			CacheDir: c.CacheDir,
+			Tags:     built.nodeTags,
+		}
+	case "dryrun":
+		assetBuilder := assets.NewAssetBuilder(c.cluster, "")
+		target = fi.NewDryRunTarget(assetBuilder, out)
+	case "cloudinit":
+		checkExisting = false
+		target = cloudinit.NewCloudInitTarget(out, built.nodeTags)
+	default:
+		return fmt.Errorf("unsupported target type %q", c.Target)
+	}
+
+	context, err := fi.NewContext(target, nil, cloud, keyStore, secretStore, built.configBase, checkExisting, taskMap)
+	if err != nil {
+		klog.Exitf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	var options fi.RunTasksOptions
+	options.InitDefaults()
+
+	err = context.RunTasks(options)
+	if err != nil {
+		klog.Exitf("error running tasks: %v", err)
+	}
+
+	err = target.Finish(taskMap)
+	if err != nil {
+		klog.Exitf("error closing target: %v", err)
+	}
+
+	if c.Target == "direct" && reconcileEnabled(c.cluster) {
+		// Record the applied tasks, as the baseline for the reconcile service
+		state, err := buildTaskState(built)
+		if err != nil {
+			return err
+		}
+		if err := state.write(c.taskStatePath()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildTasks loads the nodeup configuration, the cluster and the instance group, and builds the task graph of the node
+func (c *NodeUpCommand) buildTasks() (*nodeupTasks, error) {
 	if c.FSRoot == "" {
-		return fmt.Errorf("FSRoot is required")
+		return nil, fmt.Errorf("FSRoot is required")
 	}
 
 	if c.ConfigLocation != "" {
 		config, err := vfs.Context.ReadFile(c.ConfigLocation)
 		if err != nil {
-			return fmt.Errorf("error loading configuration %q: %v", c.ConfigLocation, err)
+			return nil, fmt.Errorf("error loading configuration %q: %v", c.ConfigLocation, err)
 		}
 
-		err = utils.YamlUnmarshal(config, &c.config)
+		c.config = &nodeup.Config{}
+		err = utils.YamlUnmarshal(config, c.config)
 		if err != nil {
-			return fmt.Errorf("error parsing configuration %q: %v", c.ConfigLocation, err)
+			return nil, fmt.Errorf("error parsing configuration %q: %v", c.ConfigLocation, err)
 		}
 	} else {
-		return fmt.Errorf("ConfigLocation is required")
+		return nil, fmt.Errorf("ConfigLocation is required")
 	}
 
 	if c.CacheDir == "" {
-		return fmt.Errorf("CacheDir is required")
+		return nil, fmt.Errorf("CacheDir is required")
 	}
 	assetStore := fi.NewAssetStore(c.CacheDir)
+	if c.config.FileSignatureKey != "" {
+		key, err := assets.ParseSignatureKey(c.config.FileSignatureKey)
+		if err != nil {
+			return nil, err
+		}
+		assetStore.SignatureKey = key
+	}
 	for _, asset := range c.config.Assets {
 		err := assetStore.Add(asset)
 		if err != nil {
-			return fmt.Errorf("error adding asset %q: %v", asset, err)
+			return nil, fmt.Errorf("error adding asset %q: %v", asset, err)
 		}
 	}
 
@@ -102,7 +186,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		var err error
 		configBase, err = vfs.Context.BuildVfsPath(*c.config.ConfigBase)
 		if err != nil {
-			return fmt.Errorf("cannot parse ConfigBase %q: %v", *c.config.ConfigBase, err)
+			return nil, fmt.Errorf("cannot parse ConfigBase %q: %v", *c.config.ConfigBase, err)
 		}
 	} else if fi.StringValue(c.config.ClusterLocation) != "" {
 		basePath := *c.config.ClusterLocation
@@ -114,10 +198,10 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		var err error
 		configBase, err = vfs.Context.BuildVfsPath(basePath)
 		if err != nil {
-			return fmt.Errorf("cannot parse inferred ConfigBase %q: %v", basePath, err)
+			return nil, fmt.Errorf("cannot parse inferred ConfigBase %q: %v", basePath, err)
 		}
 	} else {
-		return fmt.Errorf("ConfigBase is required")
+		return nil, fmt.Errorf("ConfigBase is required")
 	}
 
 	c.cluster = &api.Cluster{}
@@ -129,7 +213,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 			var err error
 			p, err = vfs.Context.BuildVfsPath(clusterLocation)
 			if err != nil {
-				return fmt.Errorf("error parsing ClusterLocation %q: %v", clusterLocation, err)
+				return nil, fmt.Errorf("error parsing ClusterLocation %q: %v", clusterLocation, err)
 			}
 		} else {
 			p = configBase.Join(registry.PathClusterCompleted)
@@ -137,12 +221,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 		b, err := p.ReadFile()
 		if err != nil {
-			return fmt.Errorf("error loading Cluster %q: %v", p, err)
+			return nil, fmt.Errorf("error loading Cluster %q: %v", p, err)
 		}
 
 		err = utils.YamlUnmarshal(b, c.cluster)
 		if err != nil {
-			return fmt.Errorf("error parsing Cluster %q: %v", p, err)
+			return nil, fmt.Errorf("error parsing Cluster %q: %v", p, err)
 		}
 	}
 
@@ -152,11 +236,11 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		c.instanceGroup = &api.InstanceGroup{}
 		b, err := instanceGroupLocation.ReadFile()
 		if err != nil {
-			return fmt.Errorf("error loading InstanceGroup %q: %v", instanceGroupLocation, err)
+			return nil, fmt.Errorf("error loading InstanceGroup %q: %v", instanceGroupLocation, err)
 		}
 
 		if err = utils.YamlUnmarshal(b, c.instanceGroup); err != nil {
-			return fmt.Errorf("error parsing InstanceGroup %q: %v", instanceGroupLocation, err)
+			return nil, fmt.Errorf("error parsing InstanceGroup %q: %v", instanceGroupLocation, err)
 		}
 	} else {
 		klog.Warningf("No instance group defined in nodeup config")
@@ -164,12 +248,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	err := evaluateSpec(c.cluster)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	distribution, err := distros.FindDistribution(c.FSRoot)
 	if err != nil {
-		return fmt.Errorf("error determining OS distribution: %v", err)
+		return nil, fmt.Errorf("error determining OS distribution: %v", err)
 	}
 
 	osTags := distribution.BuildTags()
@@ -190,36 +274,57 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		NodeupConfig:  c.config,
 	}
 
-	if c.cluster.Spec.SecretStore != "" {
+	if vault.IsVaultPath(c.cluster.Spec.SecretStore) {
+		klog.Infof("Building vault SecretStore at %q", c.cluster.Spec.SecretStore)
+		client, p, err := c.buildVaultClient(c.cluster.Spec.SecretStore)
+		if err != nil {
+			return nil, err
+		}
+
+		modelContext.SecretStore = secrets.NewVaultSecretStore(c.cluster, client, p)
+	} else if c.cluster.Spec.SecretStore != "" {
 		klog.Infof("Building SecretStore at %q", c.cluster.Spec.SecretStore)
 		p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.SecretStore)
 		if err != nil {
-			return fmt.Errorf("error building secret store path: %v", err)
+			return nil, fmt.Errorf("error building secret store path: %v", err)
 		}
 
 		modelContext.SecretStore = secrets.NewVFSSecretStore(c.cluster, p)
 	} else {
-		return fmt.Errorf("SecretStore not set")
+		return nil, fmt.Errorf("SecretStore not set")
 	}
 
-	if c.cluster.Spec.KeyStore != "" {
+	if vault.IsVaultPath(c.cluster.Spec.KeyStore) {
+		klog.Infof("Building vault KeyStore at %q", c.cluster.Spec.KeyStore)
+		client, p, err := c.buildVaultClient(c.cluster.Spec.KeyStore)
+		if err != nil {
+			return nil, err
+		}
+
+		modelContext.KeyStore = fi.NewVaultCAStore(c.cluster, client, p)
+	} else if c.cluster.Spec.KeyStore != "" {
 		klog.Infof("Building KeyStore at %q", c.cluster.Spec.KeyStore)
 		p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.KeyStore)
 		if err != nil {
-			return fmt.Errorf("error building key store path: %v", err)
+			return nil, fmt.Errorf("error building key store path: %v", err)
 		}
 
 		modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, p, false)
 	} else {
-		return fmt.Errorf("KeyStore not set")
+		return nil, fmt.Errorf("KeyStore not set")
 	}
 
 	if err := modelContext.Init(); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := loadKernelModules(modelContext); err != nil {
-		return err
+		return nil, err
+	}
+
+	reconcileCommand, err := c.reconcileCommand()
+	if err != nil {
+		return nil, err
 	}
 
 	loader := NewLoader(c.config, c.cluster, assetStore, nodeTags)
@@ -233,6 +338,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.HookBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SystemdUnitsBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.NodeAuthorizationBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeletBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubectlBuilder{NodeupModelContext: modelContext})
@@ -240,14 +346,18 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.LogrotateBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.ManifestsBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.PackagesBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.TrustedCABuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.SecretBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.NetworkBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.KernelModulesBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeAPIServerBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.KMSPluginBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.EtcdManagerTLSBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.NodeupReconcileBuilder{NodeupModelContext: modelContext, Command: reconcileCommand})
 	if c.cluster.Spec.Networking.Kuberouter == nil {
 		loader.Builders = append(loader.Builders, &model.KubeProxyBuilder{NodeupModelContext: modelContext})
 	} else {
@@ -256,6 +366,8 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	if c.cluster.Spec.Networking.Calico != nil || c.cluster.Spec.Networking.Cilium != nil {
 		loader.Builders = append(loader.Builders, &model.EtcdTLSBuilder{NodeupModelContext: modelContext})
 	}
+	// Must run last, as it restricts the modes of the files written by the other builders
+	loader.Builders = append(loader.Builders, &model.HardeningBuilder{NodeupModelContext: modelContext})
 
 	if c.cluster.Spec.Networking.LyftVPC != nil {
 
@@ -290,64 +402,62 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	taskMap, err := loader.Build(c.ModelDir)
 	if err != nil {
-		return fmt.Errorf("error building loader: %v", err)
+		return nil, fmt.Errorf("error building loader: %v", err)
 	}
 
 	for i, image := range c.config.Images {
 		taskMap["LoadImage."+strconv.Itoa(i)] = &nodetasks.LoadImageTask{
-			Sources: image.Sources,
-			Hash:    image.Hash,
+			Sources:      image.Sources,
+			Hash:         image.Hash,
+			SignatureKey: assetStore.SignatureKey,
 		}
 	}
 	if c.config.ProtokubeImage != nil {
 		taskMap["LoadImage.protokube"] = &nodetasks.LoadImageTask{
-			Sources: c.config.ProtokubeImage.Sources,
-			Hash:    c.config.ProtokubeImage.Hash,
+			Sources:      c.config.ProtokubeImage.Sources,
+			Hash:         c.config.ProtokubeImage.Hash,
+			SignatureKey: assetStore.SignatureKey,
 		}
 	}
 
-	var cloud fi.Cloud
-	var keyStore fi.Keystore
-	var secretStore fi.SecretStore
-	var target fi.Target
-	checkExisting := true
-
-	switch c.Target {
-	case "direct":
-		target = &local.LocalTarget{
-			CacheDir: c.CacheDir,
-			Tags:     nodeTags,
-		}
-	case "dryrun":
-		assetBuilder := assets.NewAssetBuilder(c.cluster, "")
-		target = fi.NewDryRunTarget(assetBuilder, out)
-	case "cloudinit":
-		checkExisting = false
-		target = cloudinit.NewCloudInitTarget(out, nodeTags)
-	default:
-		return fmt.Errorf("unsupported target type %q", c.Target)
-	}
+	return &nodeupTasks{
+		tasks:        taskMap,
+		inPlace:      loader.InPlaceTasks,
+		configBase:   configBase,
+		nodeTags:     nodeTags,
+		modelContext: modelContext,
+	}, nil
+}
 
-	context, err := fi.NewContext(target, nil, cloud, keyStore, secretStore, configBase, checkExisting, taskMap)
+// buildVaultClient builds a client for a vault:// store path, logging in to vault with the cloud identity of the instance.
+// The client is shared between the secret and key stores, so that we only log in once.
+func (c *NodeUpCommand) buildVaultClient(storePath string) (*vault.Client, *vault.StorePath, error) {
+	client, p, err := vault.NewStoreClient(c.cluster, storePath)
 	if err != nil {
-		klog.Exitf("error building context: %v", err)
+		return nil, nil, err
 	}
-	defer context.Close()
-
-	var options fi.RunTasksOptions
-	options.InitDefaults()
 
-	err = context.RunTasks(options)
-	if err != nil {
-		klog.Exitf("error running tasks: %v", err)
+	if c.vaultClient != nil && c.vaultClient.Address() == client.Address() {
+		return c.vaultClient, p, nil
 	}
 
-	err = target.Finish(taskMap)
-	if err != nil {
-		klog.Exitf("error closing target: %v", err)
+	spec := c.cluster.Spec.Vault
+	if !client.HasToken() {
+		if spec == nil || spec.AuthMethod == "" {
+			return nil, nil, fmt.Errorf("vault authMethod must be set for nodes to read from %q", storePath)
+		}
+
+		role := spec.NodeRole
+		if c.instanceGroup.IsMaster() {
+			role = spec.MasterRole
+		}
+		if err := client.Login(spec.AuthMethod, spec.AuthMount, role); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return nil
+	c.vaultClient = client
+	return client, p, nil
 }
 
 func evaluateSpec(c *api.Cluster) error {
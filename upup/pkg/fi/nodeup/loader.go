@@ -24,6 +24,7 @@ import (
 	"text/template"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kops/nodeup/pkg/model"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/upup/pkg/fi"
@@ -46,6 +47,9 @@ type Loader struct {
 
 	tags              sets.String
 	TemplateFunctions template.FuncMap
+
+	// InPlaceTasks holds the keys of the tasks built by model builders that are safe to apply in place
+	InPlaceTasks sets.String
 }
 
 func NewLoader(config *nodeup.Config, cluster *api.Cluster, assets *fi.AssetStore, tags sets.String) *Loader {
@@ -56,6 +60,7 @@ func NewLoader(config *nodeup.Config, cluster *api.Cluster, assets *fi.AssetStor
 	l.cluster = cluster
 	l.TemplateFunctions = make(template.FuncMap)
 	l.tags = tags
+	l.InPlaceTasks = sets.NewString()
 
 	return l
 }
@@ -130,6 +135,8 @@ func (l *Loader) Build(baseDir vfs.Path) (map[string]fi.Task, error) {
 	}
 
 	for _, builder := range l.Builders {
+		existing := sets.StringKeySet(l.tasks)
+
 		context := &fi.ModelBuilderContext{
 			Tasks: l.tasks,
 		}
@@ -138,6 +145,14 @@ func (l *Loader) Build(baseDir vfs.Path) (map[string]fi.Task, error) {
 			return nil, err
 		}
 		l.tasks = context.Tasks
+
+		if b, ok := builder.(model.InPlaceSafeBuilder); ok && b.InPlaceSafe() {
+			for key := range l.tasks {
+				if !existing.Has(key) {
+					l.InPlaceTasks.Insert(key)
+				}
+			}
+		}
 	}
 
 	// If there is a package task, we need an update packages task
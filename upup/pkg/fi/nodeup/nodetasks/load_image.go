@@ -17,6 +17,7 @@ limitations under the License.
 package nodetasks
 
 import (
+	"crypto"
 	"fmt"
 	"os/exec"
 	"path"
@@ -37,6 +38,9 @@ const dockerService = "docker.service"
 type LoadImageTask struct {
 	Sources []string
 	Hash    string
+
+	// SignatureKey, if set, is the key the detached signature of the image is verified against
+	SignatureKey crypto.PublicKey
 }
 
 var _ fi.Task = &LoadImageTask{}
@@ -92,9 +96,15 @@ func (_ *LoadImageTask) RenderLocal(t *local.LocalTarget, a, e, changes *LoadIma
 		if err != nil {
 			klog.Warningf("error downloading url %q: %v", url, err)
 			continue
-		} else {
-			break
 		}
+		if e.SignatureKey != nil {
+			err = fi.VerifyDownloadSignature(e.SignatureKey, url, localFile)
+			if err != nil {
+				klog.Warningf("error verifying signature of url %q: %v", url, err)
+				continue
+			}
+		}
+		break
 	}
 	if err != nil {
 		// Hack to try to avoid failed downloads causing massive bandwidth bills
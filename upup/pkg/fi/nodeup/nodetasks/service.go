@@ -56,6 +56,11 @@ type Service struct {
 
 	ManageState  *bool `json:"manageState,omitempty"`
 	SmartRestart *bool `json:"smartRestart,omitempty"`
+
+	// After are the names of the services that nodeup applies before this one
+	After []string `json:"after,omitempty"`
+	// Before are the names of the services that nodeup applies after this one
+	Before []string `json:"before,omitempty"`
 }
 
 var _ fi.HasDependencies = &Service{}
@@ -68,10 +73,15 @@ func (p *Service) GetDependencies(tasks map[string]fi.Task) []fi.Task {
 		// LoadImageTask. If there are any LoadImageTasks (e.g. we're
 		// launching a custom Kubernetes build), they all depend on
 		// the "docker.service" Service task.
-		switch v.(type) {
+		switch task := v.(type) {
 		case *File, *Package, *UpdatePackages, *UserTask, *GroupTask, *MountDiskTask, *Chattr:
 			deps = append(deps, v)
-		case *Service, *LoadImageTask:
+		case *Service:
+			// Services are only ordered when they declare it, following the systemd ordering of the units
+			if containsString(p.After, task.Name) || containsString(task.Before, p.Name) {
+				deps = append(deps, v)
+			}
+		case *LoadImageTask:
 			// ignore
 		default:
 			klog.Warningf("Unhandled type %T in Service::GetDependencies: %v", v, v)
@@ -176,6 +186,10 @@ func (e *Service) Find(c *fi.Context) (*Service, error) {
 			Name:       e.Name,
 			Definition: nil,
 			Running:    fi.Bool(false),
+
+			// Avoid spurious changes
+			After:  e.After,
+			Before: e.Before,
 		}, nil
 	}
 
@@ -186,6 +200,8 @@ func (e *Service) Find(c *fi.Context) (*Service, error) {
 		// Avoid spurious changes
 		ManageState:  e.ManageState,
 		SmartRestart: e.SmartRestart,
+		After:        e.After,
+		Before:       e.Before,
 	}
 
 	properties, err := getSystemdStatus(e.Name)
@@ -402,3 +418,12 @@ func (f *Service) GetName() *string {
 func (f *Service) SetName(name string) {
 	klog.Fatalf("SetName not supported for Service task")
 }
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -37,6 +37,25 @@ func TestServiceTask_Deps(t *testing.T) {
 	}
 }
 
+func TestServiceTask_OrderedDeps(t *testing.T) {
+	s := &Service{Name: "log-shipper.service", After: []string{"docker.service"}}
+
+	tasks := make(map[string]fi.Task)
+	tasks["docker"] = &Service{Name: "docker.service"}
+	tasks["kubelet"] = &Service{Name: "kubelet.service"}
+	tasks["node-agent"] = &Service{Name: "node-agent.service", Before: []string{"log-shipper.service"}}
+
+	deps := s.GetDependencies(tasks)
+	if len(deps) != 2 {
+		t.Fatalf("unexpected deps.  expected docker.service and node-agent.service, actual=%v", deps)
+	}
+	for _, dep := range deps {
+		if name := dep.(*Service).Name; name != "docker.service" && name != "node-agent.service" {
+			t.Errorf("unexpected dependency on %s", name)
+		}
+	}
+}
+
 type FakeTask struct {
 }
 
@@ -0,0 +1,347 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog"
+	"k8s.io/kops/nodeup/pkg/model"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/local"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// taskStateFile is where nodeup records the tasks it applied to the node, relative to FSRoot
+const taskStateFile = "var/lib/nodeup/tasks.json"
+
+// Reconcile runs nodeup in reconcile mode: every interval it rebuilds the task graph of the node from the current
+// configuration, applies the changed tasks that are safe to apply in place, and records the other changes
+// in the requires-replacement annotation of the node.  Reconcile only returns on invalid configuration.
+func (c *NodeUpCommand) Reconcile(interval time.Duration) error {
+	if c.Target != "direct" {
+		return fmt.Errorf("reconcile mode is not supported with target %q", c.Target)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("reconcile interval must be positive, was %s", interval)
+	}
+
+	for {
+		// nodeup applied the whole configuration when the node booted, so we start by waiting
+		time.Sleep(interval)
+
+		if err := c.reconcileOnce(); err != nil {
+			klog.Warningf("error reconciling node (will retry in %s): %v", interval, err)
+		}
+	}
+}
+
+// reconcileOnce performs a single reconciliation
+func (c *NodeUpCommand) reconcileOnce() error {
+	built, err := c.buildTasks()
+	if err != nil {
+		return err
+	}
+
+	current, err := buildTaskState(built)
+	if err != nil {
+		return err
+	}
+
+	p := c.taskStatePath()
+	applied, err := readTaskState(p)
+	if err != nil {
+		return err
+	}
+	if applied == nil {
+		klog.Infof("no record of the tasks applied to the node in %q; recording the current tasks", p)
+		return current.write(p)
+	}
+
+	inPlace, replace := applied.diff(current)
+
+	if len(inPlace) != 0 {
+		klog.Infof("applying changed tasks: %v", inPlace)
+		if err := c.applyTasks(built, inPlace); err != nil {
+			return err
+		}
+
+		for _, key := range inPlace {
+			applied.Tasks[key] = current.Tasks[key]
+		}
+		if err := applied.write(p); err != nil {
+			return err
+		}
+	}
+
+	if len(replace) != 0 {
+		klog.Infof("changed tasks that require replacing the node: %v", replace)
+	}
+
+	return reportReplacement(built.modelContext, replace)
+}
+
+// applyTasks runs the tasks with the given keys against the node
+func (c *NodeUpCommand) applyTasks(built *nodeupTasks, keys []string) error {
+	tasks := make(map[string]fi.Task)
+	for _, key := range keys {
+		tasks[key] = built.tasks[key]
+	}
+
+	target := &local.LocalTarget{
+		CacheDir: c.CacheDir,
+		Tags:     built.nodeTags,
+	}
+
+	context, err := fi.NewContext(target, nil, nil, nil, nil, built.configBase, true, tasks)
+	if err != nil {
+		return fmt.Errorf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	var options fi.RunTasksOptions
+	options.InitDefaults()
+	options.MaxTaskDuration = 5 * time.Minute
+
+	if err := context.RunTasks(options); err != nil {
+		return fmt.Errorf("error running tasks: %v", err)
+	}
+
+	if err := target.Finish(tasks); err != nil {
+		return fmt.Errorf("error closing target: %v", err)
+	}
+
+	return nil
+}
+
+// reconcileEnabled returns true if nodeup runs in reconcile mode on the nodes of the cluster
+func reconcileEnabled(cluster *api.Cluster) bool {
+	return cluster.Spec.NodeReconcile != nil && fi.BoolValue(cluster.Spec.NodeReconcile.Enabled)
+}
+
+// reconcileCommand returns the command line that runs this nodeup binary with the configuration of the node
+func (c *NodeUpCommand) reconcileCommand() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("error finding the nodeup executable: %v", err)
+	}
+
+	return []string{
+		exe,
+		"--conf=" + c.ConfigLocation,
+		"--cache=" + c.CacheDir,
+		"--rootfs=" + c.FSRoot,
+	}, nil
+}
+
+func (c *NodeUpCommand) taskStatePath() string {
+	return filepath.Join(c.FSRoot, taskStateFile)
+}
+
+// taskState records a hash of each task of the node
+type taskState struct {
+	Tasks map[string]taskStateEntry `json:"tasks"`
+}
+
+type taskStateEntry struct {
+	// Hash is the sha256 of the serialized task, including the contents of files
+	Hash string `json:"hash"`
+	// InPlace is true if the task can be applied to a running node
+	InPlace bool `json:"inPlace,omitempty"`
+}
+
+// buildTaskState computes the taskState of a task graph
+func buildTaskState(built *nodeupTasks) (*taskState, error) {
+	state := &taskState{Tasks: make(map[string]taskStateEntry)}
+	for key, task := range built.tasks {
+		hash, err := hashTask(task)
+		if err != nil {
+			return nil, fmt.Errorf("error hashing task %q: %v", key, err)
+		}
+		state.Tasks[key] = taskStateEntry{
+			Hash:    hash,
+			InPlace: built.inPlace.Has(key),
+		}
+	}
+	return state, nil
+}
+
+// hashTask returns the sha256 of the serialized task; the contents of files are included,
+// as most resources do not serialize their contents
+func hashTask(task fi.Task) (string, error) {
+	data, err := api.ToRawYaml(task)
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	hasher.Write(data)
+
+	if file, ok := task.(*nodetasks.File); ok && file.Contents != nil {
+		var contents []byte
+		var err error
+		// Generated contents such as credentials would differ on every build, so we hash what they are generated from
+		if generated, ok := file.Contents.(fi.GeneratedResource); ok {
+			contents, err = generated.Inputs()
+		} else {
+			contents, err = fi.ResourceAsBytes(file.Contents)
+		}
+		if err != nil {
+			return "", fmt.Errorf("error reading contents of %q: %v", file.Path, err)
+		}
+		hasher.Write(contents)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// diff compares the applied tasks with the current tasks.  It returns the keys of the changed tasks
+// that can be applied in place, and of the added, changed or removed tasks that require replacing the node.
+// Tasks that can be applied in place and were removed are ignored, as nodeup never deletes anything.
+func (s *taskState) diff(current *taskState) ([]string, []string) {
+	var inPlace, replace []string
+
+	for key, e := range current.Tasks {
+		applied, found := s.Tasks[key]
+		if found && applied.Hash == e.Hash {
+			continue
+		}
+		if e.InPlace && (!found || applied.InPlace) {
+			inPlace = append(inPlace, key)
+		} else {
+			replace = append(replace, key)
+		}
+	}
+
+	for key, applied := range s.Tasks {
+		if _, found := current.Tasks[key]; !found && !applied.InPlace {
+			replace = append(replace, key)
+		}
+	}
+
+	sort.Strings(inPlace)
+	sort.Strings(replace)
+	return inPlace, replace
+}
+
+// readTaskState reads the taskState from p, returning nil if it does not exist
+func readTaskState(p string) (*taskState, error) {
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %q: %v", p, err)
+	}
+
+	state := &taskState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("error parsing %q: %v", p, err)
+	}
+	if state.Tasks == nil {
+		state.Tasks = make(map[string]taskStateEntry)
+	}
+	return state, nil
+}
+
+// write writes the taskState to p
+func (s *taskState) write(p string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing task state: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("error creating directory %q: %v", filepath.Dir(p), err)
+	}
+
+	// Write to a temporary file and rename it, so that a crash does not leave a truncated file
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("error writing %q: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		return fmt.Errorf("error renaming %q to %q: %v", tmp, p, err)
+	}
+	return nil
+}
+
+// reportReplacement sets the requires-replacement annotation of the node to the keys of the tasks that require replacing it,
+// or removes the annotation when there are none.  It uses the credentials of the kubelet.
+func reportReplacement(modelContext *model.NodeupModelContext, replace []string) error {
+	nodeName, err := modelContext.NodeName()
+	if err != nil {
+		return err
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", modelContext.KubeletKubeConfig())
+	if err != nil {
+		return fmt.Errorf("error loading kubelet kubeconfig: %v", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error building kube client: %v", err)
+	}
+
+	node, err := client.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting node %q: %v", nodeName, err)
+	}
+
+	value := strings.Join(replace, ",")
+	if node.Annotations[api.NodeAnnotationRequiresReplacement] == value {
+		return nil
+	}
+
+	var annotation interface{}
+	if value != "" {
+		annotation = value
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				api.NodeAnnotationRequiresReplacement: annotation,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building node patch: %v", err)
+	}
+
+	klog.V(2).Infof("sending patch for node %q: %q", nodeName, string(patch))
+
+	if _, err := client.CoreV1().Nodes().Patch(nodeName, types.MergePatchType, patch); err != nil {
+		return fmt.Errorf("error applying patch to node %q: %v", nodeName, err)
+	}
+
+	return nil
+}
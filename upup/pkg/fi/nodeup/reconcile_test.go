@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func Test_TaskStateDiff(t *testing.T) {
+	applied := &taskState{
+		Tasks: map[string]taskStateEntry{
+			"File//etc/sysctl.d/99-k8s-general.conf": {Hash: "a", InPlace: true},
+			"File//srv/kubernetes/assets/removed":    {Hash: "b", InPlace: true},
+			"File//etc/sysconfig/kubelet":            {Hash: "c"},
+			"Service/kubelet.service":                {Hash: "d"},
+			"Service/removed.service":                {Hash: "e"},
+			"Service/hook.service":                   {Hash: "f"},
+		},
+	}
+	current := &taskState{
+		Tasks: map[string]taskStateEntry{
+			"File//etc/sysctl.d/99-k8s-general.conf": {Hash: "a2", InPlace: true},
+			"File//srv/kubernetes/assets/added":      {Hash: "g", InPlace: true},
+			"File//etc/sysconfig/kubelet":            {Hash: "c2"},
+			"Service/kubelet.service":                {Hash: "d"},
+			"Service/added.service":                  {Hash: "h"},
+			"Service/hook.service":                   {Hash: "f2", InPlace: true},
+		},
+	}
+
+	inPlace, replace := applied.diff(current)
+
+	expectedInPlace := []string{
+		"File//etc/sysctl.d/99-k8s-general.conf",
+		"File//srv/kubernetes/assets/added",
+	}
+	if !reflect.DeepEqual(inPlace, expectedInPlace) {
+		t.Errorf("unexpected in place changes, expected %v, got %v", expectedInPlace, inPlace)
+	}
+
+	expectedReplace := []string{
+		"File//etc/sysconfig/kubelet",
+		"Service/added.service",
+		"Service/hook.service",
+		"Service/removed.service",
+	}
+	if !reflect.DeepEqual(replace, expectedReplace) {
+		t.Errorf("unexpected replacement changes, expected %v, got %v", expectedReplace, replace)
+	}
+}
+
+func Test_HashTask(t *testing.T) {
+	file := func(contents string) fi.Task {
+		return &nodetasks.File{
+			Path:     "/etc/sysctl.d/99-k8s-general.conf",
+			Contents: fi.NewStringResource(contents),
+			Type:     nodetasks.FileType_File,
+		}
+	}
+
+	hash := func(task fi.Task) string {
+		h, err := hashTask(task)
+		if err != nil {
+			t.Fatalf("error hashing task: %v", err)
+		}
+		return h
+	}
+
+	if hash(file("net.ipv4.ip_forward=1")) != hash(file("net.ipv4.ip_forward=1")) {
+		t.Errorf("expected identical tasks to have the same hash")
+	}
+	if hash(file("net.ipv4.ip_forward=1")) == hash(file("net.ipv4.ip_forward=0")) {
+		t.Errorf("expected files with different contents to have different hashes")
+	}
+}
+
+// generatedResource generates different contents on every Open, as a freshly signed certificate would
+type generatedResource struct {
+	inputs string
+	opened int
+}
+
+func (r *generatedResource) Open() (io.Reader, error) {
+	r.opened++
+	return strings.NewReader(fmt.Sprintf("%s-%d", r.inputs, r.opened)), nil
+}
+
+func (r *generatedResource) Inputs() ([]byte, error) {
+	return []byte(r.inputs), nil
+}
+
+func Test_HashTaskGeneratedResource(t *testing.T) {
+	build := func(inputs string) (fi.Task, *generatedResource) {
+		contents := &generatedResource{inputs: inputs}
+		return &nodetasks.File{
+			Path:     "/var/lib/kubelet/kubeconfig",
+			Contents: contents,
+			Type:     nodetasks.FileType_File,
+		}, contents
+	}
+
+	hash := func(task fi.Task) string {
+		h, err := hashTask(task)
+		if err != nil {
+			t.Fatalf("error hashing task: %v", err)
+		}
+		return h
+	}
+
+	first, firstContents := build("system:node:node-1")
+	second, secondContents := build("system:node:node-1")
+	if hash(first) != hash(second) {
+		t.Errorf("expected consecutive builds of a generated file to have the same hash")
+	}
+	if firstContents.opened != 0 || secondContents.opened != 0 {
+		t.Errorf("expected generated contents not to be generated when hashing")
+	}
+
+	other, _ := build("system:node:node-2")
+	if hash(first) == hash(other) {
+		t.Errorf("expected generated files with different inputs to have different hashes")
+	}
+}
@@ -35,6 +35,13 @@ type TemplateResource interface {
 	Curry(args []string) TemplateResource
 }
 
+// GeneratedResource is a Resource whose contents differ each time they are generated, such as a freshly signed certificate.
+// Inputs returns the (deterministic) inputs to the generation, for comparing resources without generating them.
+type GeneratedResource interface {
+	Resource
+	Inputs() ([]byte, error)
+}
+
 func ResourcesMatch(a, b Resource) (bool, error) {
 	aReader, err := a.Open()
 	if err != nil {
@@ -0,0 +1,213 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/vault"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// VaultSecretStore is a SecretStore backed by a KV version 2 secrets engine in HashiCorp Vault.
+// Each secret is stored as an entry under the prefix, with the base64 encoded secret in the "data" field.
+type VaultSecretStore struct {
+	cluster *kops.Cluster
+	client  *vault.Client
+	path    *vault.StorePath
+}
+
+var _ fi.SecretStore = &VaultSecretStore{}
+
+// NewVaultSecretStore is the constructor for VaultSecretStore
+func NewVaultSecretStore(cluster *kops.Cluster, client *vault.Client, path *vault.StorePath) fi.SecretStore {
+	c := &VaultSecretStore{
+		cluster: cluster,
+		client:  client,
+		path:    path,
+	}
+	return c
+}
+
+// MirrorTo implements fi.SecretStore::MirrorTo
+func (c *VaultSecretStore) MirrorTo(basedir vfs.Path) error {
+	names, err := c.ListSecrets()
+	if err != nil {
+		return fmt.Errorf("error listing secrets for mirror: %v", err)
+	}
+
+	for _, name := range names {
+		secret, err := c.FindSecret(name)
+		if err != nil {
+			return fmt.Errorf("error reading secret %q for mirror: %v", name, err)
+		}
+		if secret == nil {
+			return fmt.Errorf("unable to find secret %q for mirror", name)
+		}
+
+		p := BuildVfsSecretPath(basedir, name)
+
+		data, err := json.Marshal(secret)
+		if err != nil {
+			return fmt.Errorf("error serializing secret: %v", err)
+		}
+
+		acl, err := acls.GetACL(p, c.cluster)
+		if err != nil {
+			return err
+		}
+
+		klog.Infof("mirroring secret %s -> %s", name, p)
+		if err := p.WriteFile(bytes.NewReader(data), acl); err != nil {
+			return fmt.Errorf("error writing secret to %q: %v", p, err)
+		}
+	}
+
+	return nil
+}
+
+// FindSecret implements fi.SecretStore::FindSecret
+func (c *VaultSecretStore) FindSecret(name string) (*fi.Secret, error) {
+	return c.loadSecret(name)
+}
+
+// ListSecrets implements fi.SecretStore::ListSecrets
+func (c *VaultSecretStore) ListSecrets() ([]string, error) {
+	keys, err := c.client.ListKV(c.path.Mount, c.path.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("error listing secrets in vault: %v", err)
+	}
+
+	var names []string
+	for _, key := range keys {
+		if key == "" || key[len(key)-1] == '/' {
+			continue
+		}
+		names = append(names, key)
+	}
+	return names, nil
+}
+
+// Secret implements fi.SecretStore::Secret
+func (c *VaultSecretStore) Secret(name string) (*fi.Secret, error) {
+	s, err := c.FindSecret(name)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("Secret not found: %q", name)
+	}
+	return s, nil
+}
+
+// DeleteSecret implements fi.SecretStore::DeleteSecret
+func (c *VaultSecretStore) DeleteSecret(name string) error {
+	if err := c.client.DeleteKV(c.path.Mount, c.path.Join(name)); err != nil {
+		return fmt.Errorf("error deleting secret %q from vault: %v", name, err)
+	}
+	return nil
+}
+
+// GetOrCreateSecret implements fi.SecretStore::GetOrCreateSecret
+func (c *VaultSecretStore) GetOrCreateSecret(name string, secret *fi.Secret) (*fi.Secret, bool, error) {
+	for i := 0; i < 2; i++ {
+		s, err := c.FindSecret(name)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if s != nil {
+			return s, false, nil
+		}
+
+		err = c.createSecret(secret, name, false)
+		if err != nil {
+			if err == vault.ErrCheckAndSetFailed && i == 0 {
+				klog.Infof("Got already-exists error when writing secret; likely due to concurrent creation.  Will retry")
+				continue
+			} else {
+				return nil, false, err
+			}
+		}
+
+		if err == nil {
+			break
+		}
+	}
+
+	// Make double-sure it round-trips
+	s, err := c.loadSecret(name)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to load secret immediately after creation %v: %v", name, err)
+	}
+	return s, true, nil
+}
+
+// ReplaceSecret implements fi.SecretStore::ReplaceSecret
+func (c *VaultSecretStore) ReplaceSecret(name string, secret *fi.Secret) (*fi.Secret, error) {
+	if err := c.createSecret(secret, name, true); err != nil {
+		return nil, fmt.Errorf("unable to write secret: %v", err)
+	}
+
+	// Confirm the secret exists
+	s, err := c.loadSecret(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load secret immediately after creation: %v", err)
+	}
+	return s, nil
+}
+
+// loadSecret returns the named secret, if it exists, otherwise returns nil
+func (c *VaultSecretStore) loadSecret(name string) (*fi.Secret, error) {
+	data, err := c.client.ReadKV(c.path.Mount, c.path.Join(name))
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret %q from vault: %v", name, err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	encoded, ok := data["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("secret %q in vault did not have a data field", name)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding secret %q from vault: %v", name, err)
+	}
+	return &fi.Secret{Data: decoded}, nil
+}
+
+// createSecret will create the Secret, overwriting an existing secret if replace is true
+func (c *VaultSecretStore) createSecret(s *fi.Secret, name string, replace bool) error {
+	data := map[string]interface{}{
+		"data": base64.StdEncoding.EncodeToString(s.Data),
+	}
+
+	cas := 0
+	if replace {
+		cas = -1
+	}
+	return c.client.WriteKV(c.path.Mount, c.path.Join(name), data, cas)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/cloudmock/vault/mockvault"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/vault"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestVaultSecretStore(t *testing.T) {
+	server := httptest.NewServer(mockvault.NewMockVault("root"))
+	defer server.Close()
+
+	client := vault.NewClient(server.URL, "")
+	client.SetToken("root")
+	store := NewVaultSecretStore(&kops.Cluster{}, client, &vault.StorePath{Address: server.URL, Mount: "secret", Prefix: "kops/secrets"})
+
+	if s, err := store.FindSecret("admin"); err != nil || s != nil {
+		t.Fatalf("expected missing secret, got %v %v", s, err)
+	}
+
+	s, created, err := store.GetOrCreateSecret("admin", &fi.Secret{Data: []byte("first")})
+	if err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+	if !created || string(s.Data) != "first" {
+		t.Errorf("unexpected result creating secret: %v %v", s, created)
+	}
+
+	s, created, err = store.GetOrCreateSecret("admin", &fi.Secret{Data: []byte("second")})
+	if err != nil {
+		t.Fatalf("error getting secret: %v", err)
+	}
+	if created || string(s.Data) != "first" {
+		t.Errorf("existing secret was replaced: %v %v", s, created)
+	}
+
+	if _, err := store.ReplaceSecret("admin", &fi.Secret{Data: []byte("third")}); err != nil {
+		t.Fatalf("error replacing secret: %v", err)
+	}
+	if _, _, err := store.GetOrCreateSecret("kube", &fi.Secret{Data: []byte{0, 1, 2}}); err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+
+	names, err := store.ListSecrets()
+	if err != nil {
+		t.Fatalf("error listing secrets: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"admin", "kube"}) {
+		t.Errorf("unexpected secrets: %v", names)
+	}
+
+	vfs.Context.ResetMemfsContext(true)
+	mirror, err := vfs.Context.BuildVfsPath("memfs://tests/mirror")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+	if err := store.MirrorTo(mirror); err != nil {
+		t.Fatalf("error mirroring secrets: %v", err)
+	}
+	mirrored, err := NewVFSSecretStore(&kops.Cluster{}, mirror).Secret("admin")
+	if err != nil {
+		t.Fatalf("error reading mirrored secret: %v", err)
+	}
+	if string(mirrored.Data) != "third" {
+		t.Errorf("unexpected mirrored secret: %q", mirrored.Data)
+	}
+
+	if err := store.DeleteSecret("admin"); err != nil {
+		t.Fatalf("error deleting secret: %v", err)
+	}
+	if _, err := store.Secret("admin"); err == nil {
+		t.Errorf("expected error reading deleted secret")
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/secretencryption"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestVFSSecretStoreEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secretstore")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))), 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+	defer os.Setenv("KOPS_SECRET_ENCRYPTION_KEY_FILE", os.Getenv("KOPS_SECRET_ENCRYPTION_KEY_FILE"))
+	os.Setenv("KOPS_SECRET_ENCRYPTION_KEY_FILE", keyFile)
+
+	vfs.Context.ResetMemfsContext(true)
+	basedir, err := vfs.Context.BuildVfsPath("memfs://tests/secrets")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	// A secret written before encryption was enabled
+	if err := basedir.Join("legacy").WriteFile(bytes.NewReader([]byte(`{"Data":"bGVnYWN5"}`)), nil); err != nil {
+		t.Fatalf("error writing plaintext secret: %v", err)
+	}
+
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			SecretEncryption: &kops.SecretEncryptionSpec{
+				Local: &kops.LocalSecretEncryptionSpec{},
+			},
+		},
+	}
+	store := NewVFSSecretStore(cluster, basedir)
+
+	if _, _, err := store.GetOrCreateSecret("admin", &fi.Secret{Data: []byte("password")}); err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+
+	raw, err := basedir.Join("admin").ReadFile()
+	if err != nil {
+		t.Fatalf("error reading secret file: %v", err)
+	}
+	if !secretencryption.IsEncrypted(raw) {
+		t.Fatalf("secret was written in plaintext: %q", raw)
+	}
+
+	for name, expected := range map[string]string{"admin": "password", "legacy": "legacy"} {
+		secret, err := store.FindSecret(name)
+		if err != nil {
+			t.Fatalf("error reading secret %q: %v", name, err)
+		}
+		if secret == nil || string(secret.Data) != expected {
+			t.Errorf("unexpected secret %q: %v", name, secret)
+		}
+	}
+}
@@ -0,0 +1,540 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/vault"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// VaultCAStore is a CAStore implementation that stores keysets in a KV version 2 secrets engine in HashiCorp Vault.
+// If the cluster configures a PKI secrets engine, the cluster CA is also loaded into it, so that
+// nodes can have Vault issue their certificates rather than reading the shared keypairs.
+type VaultCAStore struct {
+	cluster *kops.Cluster
+	client  *vault.Client
+	path    *vault.StorePath
+
+	mutex           sync.Mutex
+	cachedCaKeysets map[string]*keyset
+}
+
+var _ CAStore = &VaultCAStore{}
+var _ NodeCertificateIssuer = &VaultCAStore{}
+
+// vaultKeyset is the representation of a Keyset as a vault entry
+type vaultKeyset struct {
+	Type kops.KeysetType   `json:"type"`
+	Keys []vaultKeysetItem `json:"keys"`
+}
+
+// vaultKeysetItem is the representation of a KeysetItem as part of a vault entry; the material is PEM encoded
+type vaultKeysetItem struct {
+	Id              string `json:"id"`
+	PublicMaterial  string `json:"publicMaterial,omitempty"`
+	PrivateMaterial string `json:"privateMaterial,omitempty"`
+}
+
+// NewVaultCAStore is the constructor for VaultCAStore
+func NewVaultCAStore(cluster *kops.Cluster, client *vault.Client, path *vault.StorePath) *VaultCAStore {
+	c := &VaultCAStore{
+		cluster:         cluster,
+		client:          client,
+		path:            path,
+		cachedCaKeysets: make(map[string]*keyset),
+	}
+	return c
+}
+
+// readCAKeypairs retrieves the CA keypair.
+// (No longer generates a keypair if not found.)
+func (c *VaultCAStore) readCAKeypairs(id string) (*keyset, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached := c.cachedCaKeysets[id]
+	if cached != nil {
+		return cached, nil
+	}
+
+	keyset, err := c.loadKeyset(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset == nil {
+		return nil, nil
+	}
+	c.cachedCaKeysets[id] = keyset
+	return keyset, nil
+}
+
+// readKeyset reads the named keyset from vault, along with the version of the entry.
+// Returns (nil, 0, nil) if not found.
+func (c *VaultCAStore) readKeyset(name string) (*kops.Keyset, int, error) {
+	data, version, err := c.client.ReadKVVersion(c.path.Mount, c.path.Join(name))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading keyset %q from vault: %v", name, err)
+	}
+	if data == nil {
+		return nil, 0, nil
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	v := &vaultKeyset{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, 0, fmt.Errorf("error parsing keyset %q from vault: %v", name, err)
+	}
+
+	o := &kops.Keyset{}
+	o.Name = name
+	o.Spec.Type = v.Type
+	for _, item := range v.Keys {
+		o.Spec.Keys = append(o.Spec.Keys, kops.KeysetItem{
+			Id:              item.Id,
+			PublicMaterial:  []byte(item.PublicMaterial),
+			PrivateMaterial: []byte(item.PrivateMaterial),
+		})
+	}
+	return o, version, nil
+}
+
+// writeKeyset writes the keyset to vault; the write fails if the entry is no longer at the given version
+func (c *VaultCAStore) writeKeyset(o *kops.Keyset, version int) error {
+	v := &vaultKeyset{Type: o.Spec.Type}
+	for _, item := range o.Spec.Keys {
+		v.Keys = append(v.Keys, vaultKeysetItem{
+			Id:              item.Id,
+			PublicMaterial:  string(item.PublicMaterial),
+			PrivateMaterial: string(item.PrivateMaterial),
+		})
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	if err := c.client.WriteKV(c.path.Mount, c.path.Join(o.Name), data, version); err != nil {
+		if err == vault.ErrCheckAndSetFailed {
+			return err
+		}
+		return fmt.Errorf("error writing keyset %q to vault: %v", o.Name, err)
+	}
+	return nil
+}
+
+// loadKeyset gets the named keyset, or (nil, nil) if not found
+func (c *VaultCAStore) loadKeyset(name string) (*keyset, error) {
+	o, _, err := c.readKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, nil
+	}
+
+	keyset, err := parseKeyset(o)
+	if err != nil {
+		return nil, err
+	}
+	keyset.format = KeysetFormatV1Alpha2
+	return keyset, nil
+}
+
+// CertificatePool implements CAStore::CertificatePool
+func (c *VaultCAStore) CertificatePool(id string, createIfMissing bool) (*CertificatePool, error) {
+	cert, err := c.FindCertificatePool(id)
+	if err == nil && cert == nil {
+		if !createIfMissing {
+			klog.Warningf("using empty certificate, because running with DryRun")
+			return &CertificatePool{}, err
+		}
+		return nil, fmt.Errorf("cannot find certificate pool %q", id)
+	}
+	return cert, err
+}
+
+// FindKeypair implements CAStore::FindKeypair
+func (c *VaultCAStore) FindKeypair(name string) (*pki.Certificate, *pki.PrivateKey, KeysetFormat, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if keyset != nil && keyset.primary != nil {
+		return keyset.primary.certificate, keyset.primary.privateKey, keyset.format, nil
+	}
+
+	return nil, nil, "", nil
+}
+
+// FindCert implements CAStore::FindCert
+func (c *VaultCAStore) FindCert(name string) (*pki.Certificate, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset != nil && keyset.primary != nil {
+		return keyset.primary.certificate, nil
+	}
+
+	return nil, nil
+}
+
+// FindCertificatePool implements CAStore::FindCertificatePool
+func (c *VaultCAStore) FindCertificatePool(name string) (*CertificatePool, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+	if keyset == nil {
+		return nil, nil
+	}
+
+	pool := &CertificatePool{}
+	if keyset.primary != nil {
+		pool.Primary = keyset.primary.certificate
+	}
+	for id, item := range keyset.items {
+		if keyset.primary != nil && id == keyset.primary.id {
+			continue
+		}
+		pool.Secondary = append(pool.Secondary, item.certificate)
+	}
+	return pool, nil
+}
+
+// FindCertificateKeyset implements CAStore::FindCertificateKeyset
+func (c *VaultCAStore) FindCertificateKeyset(name string) (*kops.Keyset, error) {
+	o, _, err := c.readKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil {
+		return nil, nil
+	}
+	return removePrivateKeyMaterial(o), nil
+}
+
+// FindPrivateKey implements CAStore::FindPrivateKey
+func (c *VaultCAStore) FindPrivateKey(name string) (*pki.PrivateKey, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset != nil && keyset.primary != nil {
+		return keyset.primary.privateKey, nil
+	}
+	return nil, nil
+}
+
+// FindPrivateKeyset implements CAStore::FindPrivateKeyset
+func (c *VaultCAStore) FindPrivateKeyset(name string) (*kops.Keyset, error) {
+	o, _, err := c.readKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// ListKeysets implements CAStore::ListKeysets
+func (c *VaultCAStore) ListKeysets() ([]*kops.Keyset, error) {
+	keys, err := c.client.ListKV(c.path.Mount, c.path.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("error listing keysets in vault: %v", err)
+	}
+	sort.Strings(keys)
+
+	var items []*kops.Keyset
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+
+		o, _, err := c.readKeyset(key)
+		if err != nil {
+			return nil, err
+		}
+		if o == nil {
+			continue
+		}
+
+		switch o.Spec.Type {
+		case kops.SecretTypeKeypair:
+			items = append(items, o)
+		default:
+			return nil, fmt.Errorf("unhandled secret type %q in keyset %q", o.Spec.Type, key)
+		}
+	}
+	return items, nil
+}
+
+// CreateKeypair implements CAStore::CreateKeypair
+func (c *VaultCAStore) CreateKeypair(signer string, name string, template *x509.Certificate, privateKey *pki.PrivateKey) (*pki.Certificate, error) {
+	klog.Infof("Issuing new certificate: %q", name)
+
+	template.SerialNumber = c.buildSerial()
+
+	var cert *pki.Certificate
+	if template.IsCA {
+		var err error
+		cert, err = pki.SignNewCertificate(privateKey, template, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		caKeyset, err := c.readCAKeypairs(signer)
+		if err != nil {
+			return nil, err
+		}
+		if caKeyset == nil || caKeyset.primary == nil || caKeyset.primary.certificate == nil {
+			return nil, fmt.Errorf("ca certificate for %q was not found; cannot issue certificates", signer)
+		}
+		if caKeyset.primary.privateKey == nil {
+			return nil, fmt.Errorf("ca key for %q was not found; cannot issue certificates", signer)
+		}
+		cert, err = pki.SignNewCertificate(privateKey, template, caKeyset.primary.certificate.Certificate, caKeyset.primary.privateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.StoreKeypair(name, cert, privateKey); err != nil {
+		return nil, err
+	}
+
+	// Make double-sure it round-trips
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stored certificate: %v", err)
+	}
+	if keyset == nil || keyset.primary == nil || keyset.primary.id != cert.Certificate.SerialNumber.String() {
+		return nil, fmt.Errorf("stored certificate %q changed concurrently", name)
+	}
+	return keyset.primary.certificate, nil
+}
+
+// StoreKeypair implements CAStore::StoreKeypair
+func (c *VaultCAStore) StoreKeypair(name string, cert *pki.Certificate, privateKey *pki.PrivateKey) error {
+	item := &kops.KeysetItem{
+		Id: cert.Certificate.SerialNumber.String(),
+	}
+
+	var publicMaterial bytes.Buffer
+	if _, err := cert.WriteTo(&publicMaterial); err != nil {
+		return err
+	}
+	item.PublicMaterial = publicMaterial.Bytes()
+
+	if privateKey != nil {
+		var privateMaterial bytes.Buffer
+		if _, err := privateKey.WriteTo(&privateMaterial); err != nil {
+			return err
+		}
+		item.PrivateMaterial = privateMaterial.Bytes()
+	}
+
+	if err := c.addKey(name, item); err != nil {
+		return err
+	}
+
+	if name == CertificateId_CA && privateKey != nil {
+		if err := c.configurePKI(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddCert implements CAStore::AddCert
+func (c *VaultCAStore) AddCert(name string, cert *pki.Certificate) error {
+	klog.Infof("Adding TLS certificate: %q", name)
+
+	var publicMaterial bytes.Buffer
+	if _, err := cert.WriteTo(&publicMaterial); err != nil {
+		return err
+	}
+
+	// We add with a timestamp of zero so this will never be the newest cert
+	item := &kops.KeysetItem{
+		Id:             pki.BuildPKISerial(0).String(),
+		PublicMaterial: publicMaterial.Bytes(),
+	}
+	return c.addKey(name, item)
+}
+
+// addKey adds the item to the named keyset, creating the keyset if it does not exist
+func (c *VaultCAStore) addKey(name string, item *kops.KeysetItem) error {
+	for attempt := 0; ; attempt++ {
+		o, version, err := c.readKeyset(name)
+		if err != nil {
+			return err
+		}
+		if o == nil {
+			o = &kops.Keyset{}
+			o.Name = name
+			o.Spec.Type = kops.SecretTypeKeypair
+		}
+		if o.Spec.Type != kops.SecretTypeKeypair {
+			return fmt.Errorf("mismatch on Keyset type on %q", name)
+		}
+		o.Spec.Keys = append(o.Spec.Keys, *item)
+
+		err = c.writeKeyset(o, version)
+		if err == nil {
+			break
+		}
+		if err == vault.ErrCheckAndSetFailed && attempt == 0 {
+			klog.Infof("Keyset %q was modified concurrently; will retry", name)
+			continue
+		}
+		return err
+	}
+
+	c.mutex.Lock()
+	delete(c.cachedCaKeysets, name)
+	c.mutex.Unlock()
+	return nil
+}
+
+// DeleteKeysetItem implements CAStore::DeleteKeysetItem
+func (c *VaultCAStore) DeleteKeysetItem(item *kops.Keyset, id string) error {
+	if item.Spec.Type != kops.SecretTypeKeypair {
+		// Primarily because we need to make sure users can recreate them!
+		return fmt.Errorf("deletion of keystore items of type %v not (yet) supported", item.Spec.Type)
+	}
+
+	o, version, err := c.readKeyset(item.Name)
+	if err != nil {
+		return err
+	}
+	if o == nil {
+		return nil
+	}
+
+	var newKeys []kops.KeysetItem
+	found := false
+	for _, ki := range o.Spec.Keys {
+		if ki.Id == id {
+			found = true
+		} else {
+			newKeys = append(newKeys, ki)
+		}
+	}
+	if !found {
+		return fmt.Errorf("KeysetItem %q not found in Keyset %q", id, item.Name)
+	}
+
+	c.mutex.Lock()
+	delete(c.cachedCaKeysets, item.Name)
+	c.mutex.Unlock()
+
+	if len(newKeys) == 0 {
+		if err := c.client.DeleteKV(c.path.Mount, c.path.Join(item.Name)); err != nil {
+			return fmt.Errorf("error deleting Keyset %q: %v", item.Name, err)
+		}
+		return nil
+	}
+	o.Spec.Keys = newKeys
+	return c.writeKeyset(o, version)
+}
+
+// MirrorTo implements CAStore::MirrorTo
+func (c *VaultCAStore) MirrorTo(basedir vfs.Path) error {
+	keysets, err := c.ListKeysets()
+	if err != nil {
+		return err
+	}
+
+	for _, keyset := range keysets {
+		o, err := c.FindPrivateKeyset(keyset.Name)
+		if err != nil {
+			return err
+		}
+		if err := mirrorKeyset(c.cluster, basedir, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configurePKI loads the CA into the PKI secrets engine, if one is configured for the cluster
+func (c *VaultCAStore) configurePKI(item *kops.KeysetItem) error {
+	if c.cluster.Spec.Vault == nil || c.cluster.Spec.Vault.PKIMount == "" {
+		return nil
+	}
+	mount := c.cluster.Spec.Vault.PKIMount
+
+	klog.Infof("Loading CA into vault PKI secrets engine %q", mount)
+	bundle := string(item.PublicMaterial) + string(item.PrivateMaterial)
+	if err := c.client.ConfigureCA(mount, bundle); err != nil {
+		return fmt.Errorf("error loading CA into vault PKI secrets engine %q: %v", mount, err)
+	}
+	return nil
+}
+
+// IssueNodeCertificate implements NodeCertificateIssuer::IssueNodeCertificate, issuing a certificate from the PKI secrets engine
+func (c *VaultCAStore) IssueNodeCertificate(commonName string) (*pki.Certificate, *pki.PrivateKey, error) {
+	spec := c.cluster.Spec.Vault
+	if spec == nil || spec.PKIMount == "" || spec.NodeCertificateRole == "" {
+		return nil, nil, fmt.Errorf("vault pkiMount and nodeCertificateRole must be set to issue node certificates")
+	}
+
+	issued, err := c.client.IssueCertificate(spec.PKIMount, spec.NodeCertificateRole, commonName, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := pki.ParsePEMCertificate([]byte(issued.Certificate))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing certificate issued by vault: %v", err)
+	}
+	privateKey, err := pki.ParsePEMPrivateKey([]byte(issued.PrivateKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing private key issued by vault: %v", err)
+	}
+	return cert, privateKey, nil
+}
+
+// buildSerial returns a serial for use when issuing certificates
+func (c *VaultCAStore) buildSerial() *big.Int {
+	t := time.Now().UnixNano()
+	return pki.BuildPKISerial(t)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/cloudmock/vault/mockvault"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/vault"
+)
+
+func TestVaultCAStore(t *testing.T) {
+	mock := mockvault.NewMockVault("root")
+	mock.PKIRoles["pki/kubelet"] = []string{"system:nodes"}
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			Vault: &kops.VaultSpec{
+				PKIMount:            "pki",
+				NodeCertificateRole: "kubelet",
+			},
+		},
+	}
+	client := vault.NewClient(server.URL, "")
+	client.SetToken("root")
+	s := NewVaultCAStore(cluster, client, &vault.StorePath{Address: server.URL, Mount: "secret", Prefix: "kops/pki"})
+
+	if cert, err := s.FindCert(CertificateId_CA); err != nil || cert != nil {
+		t.Fatalf("expected missing CA, got %v %v", cert, err)
+	}
+
+	caKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	caCert, err := s.CreateKeypair("", CertificateId_CA, BuildCAX509Template(), caKey)
+	if err != nil {
+		t.Fatalf("error creating CA: %v", err)
+	}
+
+	key, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "kubecfg"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := s.CreateKeypair(CertificateId_CA, "kubecfg", template, key)
+	if err != nil {
+		t.Fatalf("error creating keypair: %v", err)
+	}
+	if err := cert.Certificate.CheckSignatureFrom(caCert.Certificate); err != nil {
+		t.Errorf("keypair was not signed by the CA: %v", err)
+	}
+
+	foundCert, foundKey, format, err := s.FindKeypair("kubecfg")
+	if err != nil {
+		t.Fatalf("error finding keypair: %v", err)
+	}
+	if foundCert == nil || !foundCert.Certificate.Equal(cert.Certificate) || foundKey == nil || format != KeysetFormatV1Alpha2 {
+		t.Errorf("unexpected keypair: %v %v %v", foundCert, foundKey, format)
+	}
+
+	certKeyset, err := s.FindCertificateKeyset("kubecfg")
+	if err != nil {
+		t.Fatalf("error finding keyset: %v", err)
+	}
+	if len(certKeyset.Spec.Keys) != 1 || len(certKeyset.Spec.Keys[0].PrivateMaterial) != 0 {
+		t.Errorf("certificate keyset should not include private key material: %v", certKeyset)
+	}
+
+	keysets, err := s.ListKeysets()
+	if err != nil {
+		t.Fatalf("error listing keysets: %v", err)
+	}
+	var names []string
+	for _, k := range keysets {
+		names = append(names, k.Name)
+	}
+	if !reflect.DeepEqual(names, []string{CertificateId_CA, "kubecfg"}) {
+		t.Errorf("unexpected keysets: %v", names)
+	}
+
+	// The CA was loaded into the PKI secrets engine, so vault can issue node certificates
+	nodeCert, nodeKey, err := s.IssueNodeCertificate("system:node:node-1")
+	if err != nil {
+		t.Fatalf("error issuing node certificate: %v", err)
+	}
+	if nodeKey == nil || nodeCert.Subject.CommonName != "system:node:node-1" {
+		t.Errorf("unexpected node certificate: %v", nodeCert.Subject)
+	}
+	if err := nodeCert.Certificate.CheckSignatureFrom(caCert.Certificate); err != nil {
+		t.Errorf("node certificate was not signed by the cluster CA: %v", err)
+	}
+
+	if err := s.DeleteKeysetItem(certKeyset, certKeyset.Spec.Keys[0].Id); err != nil {
+		t.Fatalf("error deleting keyset item: %v", err)
+	}
+	if cert, err := s.FindCert("kubecfg"); err != nil || cert != nil {
+		t.Errorf("expected deleted keypair to be missing, got %v %v", cert, err)
+	}
+}
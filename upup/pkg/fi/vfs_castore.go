@@ -36,6 +36,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/secretencryption"
 	"k8s.io/kops/pkg/sshcredentials"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -249,6 +250,11 @@ func (c *VFSCAStore) loadKeysetBundle(p vfs.Path) (*keyset, error) {
 		}
 	}
 
+	data, err = secretencryption.Decrypt(c.cluster, data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting bundle %q: %v", p, err)
+	}
+
 	o, format, err := c.parseKeysetYaml(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing bundle %q: %v", p, err)
@@ -309,6 +315,13 @@ func (c *VFSCAStore) writeKeysetBundle(p vfs.Path, name string, keyset *keyset,
 		return err
 	}
 
+	if includePrivateKeyMaterial {
+		objectData, err = encryptPrivateData(c.cluster, objectData)
+		if err != nil {
+			return err
+		}
+	}
+
 	acl, err := acls.GetACL(p, c.cluster)
 	if err != nil {
 		return err
@@ -316,6 +329,19 @@ func (c *VFSCAStore) writeKeysetBundle(p vfs.Path, name string, keyset *keyset,
 	return p.WriteFile(bytes.NewReader(objectData), acl)
 }
 
+// encryptPrivateData encrypts private key material, if secret encryption is enabled for the cluster
+func encryptPrivateData(cluster *kops.Cluster, data []byte) ([]byte, error) {
+	encrypter, err := secretencryption.ForCluster(cluster)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encrypter.Encrypt(data)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting private key material: %v", err)
+	}
+	return encrypted, nil
+}
+
 // serializeKeysetBundle converts a keyset bundle to yaml, for writing to VFS
 func serializeKeysetBundle(o *kops.Keyset) ([]byte, error) {
 	var objectData bytes.Buffer
@@ -685,6 +711,10 @@ func mirrorKeyset(cluster *kops.Cluster, basedir vfs.Path, keyset *kops.Keyset)
 			if err != nil {
 				return err
 			}
+			data, err = encryptPrivateData(cluster, data)
+			if err != nil {
+				return err
+			}
 			p := basedir.Join("private", keyset.Name, "keyset.yaml")
 			acl, err := acls.GetACL(p, cluster)
 			if err != nil {
@@ -881,6 +911,10 @@ func (c *VFSCAStore) loadOnePrivateKey(p vfs.Path) (*pki.PrivateKey, error) {
 		}
 		return nil, err
 	}
+	data, err = secretencryption.Decrypt(c.cluster, data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting private key from %q: %v", p, err)
+	}
 	k, err := pki.ParsePEMPrivateKey(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing private key from %q: %v", p, err)
@@ -979,12 +1013,17 @@ func (c *VFSCAStore) storePrivateKey(name string, ki *keysetItem) error {
 			return err
 		}
 
+		encrypted, err := encryptPrivateData(c.cluster, data.Bytes())
+		if err != nil {
+			return err
+		}
+
 		p := c.buildPrivateKeyPath(name, ki.id)
 		acl, err := acls.GetACL(p, c.cluster)
 		if err != nil {
 			return err
 		}
-		return p.WriteFile(bytes.NewReader(data.Bytes()), acl)
+		return p.WriteFile(bytes.NewReader(encrypted), acl)
 	}
 }
 
@@ -1152,6 +1191,10 @@ func (c *VFSCAStore) loadData(p vfs.Path) (*pki.PrivateKey, error) {
 		}
 		return nil, err
 	}
+	data, err = secretencryption.Decrypt(c.cluster, data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting private key from %q: %v", p, err)
+	}
 	k, err := pki.ParsePEMPrivateKey(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing private key from %q: %v", p, err)
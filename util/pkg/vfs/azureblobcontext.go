@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+const (
+	// azureBlobAPIVersion is the version of the blob service REST API; OAuth requires 2017-11-09 or later
+	azureBlobAPIVersion = "2018-11-09"
+	// azureStorageResource is the resource tokens for Azure Storage are issued for
+	azureStorageResource = "https://storage.azure.com/"
+)
+
+// AzureBlobClient is a client for the Azure Blob Storage REST API, authenticating with Azure Active Directory
+type AzureBlobClient struct {
+	httpClient *http.Client
+	token      *adal.ServicePrincipalToken
+	// endpointSuffix is the DNS suffix of storage accounts, e.g. core.windows.net
+	endpointSuffix string
+	// endpoint overrides the blob service URL, addressing accounts path-style (e.g. for the Azurite emulator)
+	endpoint string
+}
+
+// NewAzureBlobClient builds a client using the service principal in AZURE_TENANT_ID, AZURE_CLIENT_ID and
+// AZURE_CLIENT_SECRET if set, and otherwise the managed identity of the VM we are running on.
+// If AZURE_STORAGE_BLOB_ENDPOINT is set, requests are sent to that endpoint instead of the Azure cloud.
+func NewAzureBlobClient() (*AzureBlobClient, error) {
+	env := azure.PublicCloud
+	if name := os.Getenv("AZURE_ENVIRONMENT"); name != "" {
+		e, err := azure.EnvironmentFromName(name)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing AZURE_ENVIRONMENT %q: %v", name, err)
+		}
+		env = e
+	}
+
+	var token *adal.ServicePrincipalToken
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
+	if tenantID != "" && clientID != "" && clientSecret != "" {
+		oauthConfig, err := adal.NewOAuthConfig(env.ActiveDirectoryEndpoint, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("error building azure OAuth configuration: %v", err)
+		}
+		token, err = adal.NewServicePrincipalToken(*oauthConfig, clientID, clientSecret, azureStorageResource)
+		if err != nil {
+			return nil, fmt.Errorf("error building azure service principal token: %v", err)
+		}
+	} else {
+		msiEndpoint, err := adal.GetMSIVMEndpoint()
+		if err != nil {
+			return nil, fmt.Errorf("error getting azure managed identity endpoint: %v", err)
+		}
+		token, err = adal.NewServicePrincipalTokenFromMSI(msiEndpoint, azureStorageResource)
+		if err != nil {
+			return nil, fmt.Errorf("error building azure managed identity token: %v", err)
+		}
+	}
+
+	return &AzureBlobClient{
+		httpClient:     http.DefaultClient,
+		token:          token,
+		endpointSuffix: env.StorageEndpointSuffix,
+		endpoint:       strings.TrimSuffix(os.Getenv("AZURE_STORAGE_BLOB_ENDPOINT"), "/"),
+	}, nil
+}
+
+// accountURL returns the URL of the blob service of a storage account
+func (c *AzureBlobClient) accountURL(account string) string {
+	if c.endpoint != "" {
+		return c.endpoint + "/" + account
+	}
+	return "https://" + account + ".blob." + c.endpointSuffix
+}
+
+// do sends an authenticated request to the blob service
+func (c *AzureBlobClient) do(req *http.Request) (*http.Response, error) {
+	if c.token != nil {
+		if err := c.token.EnsureFresh(); err != nil {
+			return nil, fmt.Errorf("error refreshing azure token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token.OAuthToken())
+	}
+	req.Header.Set("x-ms-version", azureBlobAPIVersion)
+	return c.httpClient.Do(req)
+}
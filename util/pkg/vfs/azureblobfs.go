@@ -0,0 +1,494 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+// AzureBlobPath is a vfs path for Azure Blob Storage, of the form azureblob://<account>/<container>/<key>
+type AzureBlobPath struct {
+	client    *AzureBlobClient
+	account   string
+	container string
+	key       string
+
+	// md5Hash is the base64 encoded Content-MD5 of the blob, if known from a listing
+	md5Hash string
+}
+
+var _ Path = &AzureBlobPath{}
+var _ HasHash = &AzureBlobPath{}
+
+// AzureBlobAcl is an ACL implementation for blobs on Azure Blob Storage.
+// Access is controlled per container, so the ACL is checked against the container rather than set on the blob.
+type AzureBlobAcl struct {
+	// PublicAccess is the anonymous access the container must allow: "blob" or "container"; empty for private
+	PublicAccess string
+}
+
+var _ ACL = &AzureBlobAcl{}
+
+// azureBlobReadBackoff is the backoff strategy for Azure Blob Storage read retries
+var azureBlobReadBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   1.5,
+	Jitter:   0.1,
+	Steps:    4,
+}
+
+// azureBlobWriteBackoff is the backoff strategy for Azure Blob Storage write retries
+var azureBlobWriteBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   1.5,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// NewAzureBlobPath returns a path to a blob
+func NewAzureBlobPath(client *AzureBlobClient, account string, container string, key string) *AzureBlobPath {
+	return &AzureBlobPath{
+		client:    client,
+		account:   account,
+		container: strings.Trim(container, "/"),
+		key:       strings.TrimPrefix(key, "/"),
+	}
+}
+
+// Account returns the name of the storage account
+func (p *AzureBlobPath) Account() string {
+	return p.account
+}
+
+// Container returns the name of the container
+func (p *AzureBlobPath) Container() string {
+	return p.container
+}
+
+func (p *AzureBlobPath) blobURL() string {
+	return p.client.accountURL(p.account) + "/" + p.container + "/" + (&url.URL{Path: p.key}).EscapedPath()
+}
+
+// WriteTo implements io.WriteTo
+func (p *AzureBlobPath) WriteTo(out io.Writer) (int64, error) {
+	klog.V(4).Infof("Reading file %q", p)
+
+	req, err := http.NewRequest(http.MethodGet, p.blobURL(), nil)
+	if err != nil {
+		return 0, err
+	}
+	response, err := p.client.do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching %s: %v", p, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return 0, os.ErrNotExist
+	}
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("error fetching %s: %s", p, response.Status)
+	}
+
+	n, err := io.Copy(out, response.Body)
+	if err != nil {
+		return n, fmt.Errorf("error reading %s: %v", p, err)
+	}
+	return n, nil
+}
+
+func (p *AzureBlobPath) Join(relativePath ...string) Path {
+	args := []string{p.key}
+	args = append(args, relativePath...)
+	joined := path.Join(args...)
+	return &AzureBlobPath{
+		client:    p.client,
+		account:   p.account,
+		container: p.container,
+		key:       joined,
+	}
+}
+
+func (p *AzureBlobPath) ReadFile() ([]byte, error) {
+	var b bytes.Buffer
+	done, err := RetryWithBackoff(azureBlobReadBackoff, func() (bool, error) {
+		b.Reset()
+		_, err := p.WriteTo(&b)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Not recoverable
+				return true, err
+			}
+			return false, err
+		}
+		// Success!
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	} else if done {
+		return b.Bytes(), nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, wait.ErrWaitTimeout
+	}
+}
+
+func (p *AzureBlobPath) WriteFile(data io.ReadSeeker, acl ACL) error {
+	return p.put(data, acl, false)
+}
+
+// put uploads the blob; if ifNotExists is set the upload is conditional, and os.ErrExist is returned if the blob exists
+func (p *AzureBlobPath) put(data io.ReadSeeker, acl ACL, ifNotExists bool) error {
+	if acl != nil {
+		azureBlobAcl, ok := acl.(*AzureBlobAcl)
+		if !ok {
+			return fmt.Errorf("write to %s with ACL of unexpected type %T", p, acl)
+		}
+		if err := p.checkPublicAccess(azureBlobAcl.PublicAccess); err != nil {
+			return err
+		}
+	}
+
+	done, err := RetryWithBackoff(azureBlobWriteBackoff, func() (bool, error) {
+		klog.V(4).Infof("Writing file %q", p)
+
+		if _, err := data.Seek(0, 0); err != nil {
+			return false, fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
+		}
+		b, err := ioutil.ReadAll(data)
+		if err != nil {
+			return false, fmt.Errorf("error reading from data stream: %v", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPut, p.blobURL(), bytes.NewReader(b))
+		if err != nil {
+			return true, err
+		}
+		md5Hash := md5.Sum(b)
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md5Hash[:]))
+		if ifNotExists {
+			req.Header.Set("If-None-Match", "*")
+		}
+
+		response, err := p.client.do(req)
+		if err != nil {
+			return false, fmt.Errorf("error writing %s: %v", p, err)
+		}
+		defer response.Body.Close()
+
+		switch response.StatusCode {
+		case http.StatusCreated, http.StatusOK:
+			return true, nil
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			if ifNotExists {
+				return true, os.ErrExist
+			}
+		}
+		return false, fmt.Errorf("error writing %s: %s", p, response.Status)
+	})
+	if err != nil {
+		return err
+	} else if done {
+		return nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return wait.ErrWaitTimeout
+	}
+}
+
+// To prevent concurrent creates on the same file while maintaining atomicity of writes,
+// we take a process-wide lock during the operation; the conditional upload protects against other processes.
+var createFileLockAzureBlob sync.Mutex
+
+func (p *AzureBlobPath) CreateFile(data io.ReadSeeker, acl ACL) error {
+	createFileLockAzureBlob.Lock()
+	defer createFileLockAzureBlob.Unlock()
+
+	return p.put(data, acl, true)
+}
+
+// checkPublicAccess verifies the container allows the requested anonymous access.
+// We don't change the container ACL ourselves, as setting it also replaces the stored access policies.
+func (p *AzureBlobPath) checkPublicAccess(publicAccess string) error {
+	switch publicAccess {
+	case "":
+		return nil
+	case "blob", "container":
+	default:
+		return fmt.Errorf("unknown public access level %q for %s", publicAccess, p)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.client.accountURL(p.account)+"/"+p.container+"?restype=container", nil)
+	if err != nil {
+		return err
+	}
+	response, err := p.client.do(req)
+	if err != nil {
+		return fmt.Errorf("error fetching properties of container %q: %v", p.container, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("error fetching properties of container %q: %s", p.container, response.Status)
+	}
+
+	actual := response.Header.Get("x-ms-blob-public-access")
+	if actual == publicAccess || actual == "container" {
+		return nil
+	}
+	if actual == "" {
+		actual = "private"
+	}
+	return fmt.Errorf("%s requires %q public access, but container %q in storage account %q is %s", p, publicAccess, p.container, p.account, actual)
+}
+
+func (p *AzureBlobPath) Remove() error {
+	done, err := RetryWithBackoff(azureBlobWriteBackoff, func() (bool, error) {
+		klog.V(8).Infof("removing file %s", p)
+
+		req, err := http.NewRequest(http.MethodDelete, p.blobURL(), nil)
+		if err != nil {
+			return true, err
+		}
+		response, err := p.client.do(req)
+		if err != nil {
+			return false, fmt.Errorf("error deleting %s: %v", p, err)
+		}
+		defer response.Body.Close()
+
+		switch response.StatusCode {
+		case http.StatusAccepted, http.StatusOK:
+			return true, nil
+		case http.StatusNotFound:
+			return true, os.ErrNotExist
+		}
+		return false, fmt.Errorf("error deleting %s: %s", p, response.Status)
+	})
+	if err != nil {
+		return err
+	} else if done {
+		return nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return wait.ErrWaitTimeout
+	}
+}
+
+func (p *AzureBlobPath) Base() string {
+	return path.Base(p.key)
+}
+
+func (p *AzureBlobPath) PreferredHash() (*hashing.Hash, error) {
+	return p.Hash(hashing.HashAlgorithmMD5)
+}
+
+func (p *AzureBlobPath) Hash(a hashing.HashAlgorithm) (*hashing.Hash, error) {
+	if a != hashing.HashAlgorithmMD5 {
+		return nil, nil
+	}
+
+	md5 := p.md5Hash
+	if md5 == "" {
+		var err error
+		md5, err = p.fetchMD5()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if md5 == "" {
+		// Blobs uploaded in blocks by other tools may not have a Content-MD5
+		return nil, nil
+	}
+
+	md5Bytes, err := base64.StdEncoding.DecodeString(md5)
+	if err != nil {
+		return nil, fmt.Errorf("Content-MD5 was not a valid MD5 sum: %q", md5)
+	}
+
+	return &hashing.Hash{Algorithm: hashing.HashAlgorithmMD5, HashValue: md5Bytes}, nil
+}
+
+// fetchMD5 reads the Content-MD5 of the blob from its properties
+func (p *AzureBlobPath) fetchMD5() (string, error) {
+	req, err := http.NewRequest(http.MethodHead, p.blobURL(), nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := p.client.do(req)
+	if err != nil {
+		return "", fmt.Errorf("error fetching properties of %s: %v", p, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return "", os.ErrNotExist
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error fetching properties of %s: %s", p, response.Status)
+	}
+	return response.Header.Get("Content-MD5"), nil
+}
+
+func (p *AzureBlobPath) String() string {
+	return p.Path()
+}
+
+func (p *AzureBlobPath) Path() string {
+	return "azureblob://" + p.account + "/" + p.container + "/" + p.key
+}
+
+func (p *AzureBlobPath) ReadDir() ([]Path, error) {
+	prefix := p.key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	paths, err := p.list(prefix, "/")
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		// As with the other object stores, a directory only exists if it has children
+		return nil, os.ErrNotExist
+	}
+	return paths, nil
+}
+
+func (p *AzureBlobPath) ReadTree() ([]Path, error) {
+	prefix := p.key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	// No delimiter for recursive search
+	return p.list(prefix, "")
+}
+
+// azureBlobEnumerationResults is the response to a List Blobs request
+type azureBlobEnumerationResults struct {
+	Blobs struct {
+		Blob []struct {
+			Name       string `xml:"Name"`
+			Properties struct {
+				ContentMD5 string `xml:"Content-MD5"`
+			} `xml:"Properties"`
+		} `xml:"Blob"`
+		BlobPrefix []struct {
+			Name string `xml:"Name"`
+		} `xml:"BlobPrefix"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+func (p *AzureBlobPath) list(prefix string, delimiter string) ([]Path, error) {
+	var ret []Path
+
+	done, err := RetryWithBackoff(azureBlobReadBackoff, func() (bool, error) {
+		var paths []Path
+		marker := ""
+		for {
+			query := url.Values{}
+			query.Set("restype", "container")
+			query.Set("comp", "list")
+			query.Set("prefix", prefix)
+			if delimiter != "" {
+				query.Set("delimiter", delimiter)
+			}
+			if marker != "" {
+				query.Set("marker", marker)
+			}
+
+			req, err := http.NewRequest(http.MethodGet, p.client.accountURL(p.account)+"/"+p.container+"?"+query.Encode(), nil)
+			if err != nil {
+				return true, err
+			}
+			response, err := p.client.do(req)
+			if err != nil {
+				return false, fmt.Errorf("error listing %s: %v", p, err)
+			}
+			body, err := ioutil.ReadAll(response.Body)
+			response.Body.Close()
+			if err != nil {
+				return false, fmt.Errorf("error listing %s: %v", p, err)
+			}
+			if response.StatusCode == http.StatusNotFound {
+				return true, os.ErrNotExist
+			}
+			if response.StatusCode != http.StatusOK {
+				return false, fmt.Errorf("error listing %s: %s", p, response.Status)
+			}
+
+			results := &azureBlobEnumerationResults{}
+			if err := xml.Unmarshal(body, results); err != nil {
+				return false, fmt.Errorf("error parsing listing of %s: %v", p, err)
+			}
+
+			// Blobs represent files
+			for _, blob := range results.Blobs.Blob {
+				paths = append(paths, &AzureBlobPath{
+					client:    p.client,
+					account:   p.account,
+					container: p.container,
+					key:       blob.Name,
+					md5Hash:   blob.Properties.ContentMD5,
+				})
+			}
+			// BlobPrefixes represent directories
+			for _, blobPrefix := range results.Blobs.BlobPrefix {
+				paths = append(paths, &AzureBlobPath{
+					client:    p.client,
+					account:   p.account,
+					container: p.container,
+					key:       blobPrefix.Name,
+				})
+			}
+
+			if results.NextMarker == "" {
+				break
+			}
+			marker = results.NextMarker
+		}
+		klog.V(8).Infof("Listed files in %v: %v", p, paths)
+		ret = paths
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	} else if done {
+		return ret, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, wait.ErrWaitTimeout
+	}
+}
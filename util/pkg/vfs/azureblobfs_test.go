@@ -0,0 +1,371 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+// fakeAzureBlobServer is an in-memory implementation of the parts of the blob service REST API we use
+type fakeAzureBlobServer struct {
+	mutex sync.Mutex
+	// blobs maps <account>/<container>/<key> to the contents of the blob
+	blobs map[string][]byte
+	// md5s maps <account>/<container>/<key> to the Content-MD5 of the blob
+	md5s map[string]string
+	// publicAccess maps <account>/<container> to the public access level of the container
+	publicAccess map[string]string
+}
+
+func newFakeAzureBlobServer() *fakeAzureBlobServer {
+	return &fakeAzureBlobServer{
+		blobs:        make(map[string][]byte),
+		md5s:         make(map[string]string),
+		publicAccess: make(map[string]string),
+	}
+}
+
+func (s *fakeAzureBlobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	query := r.URL.Query()
+
+	if query.Get("restype") == "container" {
+		if query.Get("comp") == "list" {
+			s.list(w, name, query.Get("prefix"), query.Get("delimiter"))
+			return
+		}
+		if access := s.publicAccess[name]; access != "" {
+			w.Header().Set("x-ms-blob-public-access", access)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		data, found := s.blobs[name]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if s.md5s[name] != "" {
+			w.Header().Set("Content-MD5", s.md5s[name])
+		}
+		w.Write(data)
+
+	case http.MethodPut:
+		if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, found := s.blobs[name]; found && r.Header.Get("If-None-Match") == "*" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if contentMD5 := r.Header.Get("Content-MD5"); contentMD5 != "" {
+			actual := md5.Sum(data)
+			if contentMD5 != base64.StdEncoding.EncodeToString(actual[:]) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		s.blobs[name] = data
+		s.md5s[name] = r.Header.Get("Content-MD5")
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodDelete:
+		if _, found := s.blobs[name]; !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(s.blobs, name)
+		delete(s.md5s, name)
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list writes the blobs in the container with the prefix, grouping them by delimiter; we return one page at a time to exercise paging
+func (s *fakeAzureBlobServer) list(w http.ResponseWriter, container string, prefix string, delimiter string) {
+	type blob struct {
+		Name       string `xml:"Name"`
+		Properties struct {
+			ContentMD5 string `xml:"Content-MD5"`
+		} `xml:"Properties"`
+	}
+	type blobPrefix struct {
+		Name string `xml:"Name"`
+	}
+	results := struct {
+		XMLName xml.Name `xml:"EnumerationResults"`
+		Blobs   struct {
+			Blob       []blob       `xml:"Blob"`
+			BlobPrefix []blobPrefix `xml:"BlobPrefix"`
+		} `xml:"Blobs"`
+		NextMarker string `xml:"NextMarker"`
+	}{}
+
+	seenPrefixes := make(map[string]bool)
+	for name := range s.blobs {
+		if !strings.HasPrefix(name, container+"/") {
+			continue
+		}
+		key := strings.TrimPrefix(name, container+"/")
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i != -1 {
+				p := key[:len(prefix)+i+len(delimiter)]
+				if !seenPrefixes[p] {
+					seenPrefixes[p] = true
+					results.Blobs.BlobPrefix = append(results.Blobs.BlobPrefix, blobPrefix{Name: p})
+				}
+				continue
+			}
+		}
+		b := blob{Name: key}
+		b.Properties.ContentMD5 = s.md5s[name]
+		results.Blobs.Blob = append(results.Blobs.Blob, b)
+	}
+
+	if err := xml.NewEncoder(w).Encode(&results); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func newTestAzureBlobPath(t *testing.T, server *httptest.Server, p string) *AzureBlobPath {
+	context := &VFSContext{
+		azureBlobClient: &AzureBlobClient{
+			httpClient: server.Client(),
+			endpoint:   server.URL,
+		},
+	}
+	blobPath, err := context.buildAzureBlobPath(p)
+	if err != nil {
+		t.Fatalf("error building path %q: %v", p, err)
+	}
+	return blobPath
+}
+
+func TestAzureBlobPathReadWrite(t *testing.T) {
+	fake := newFakeAzureBlobServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	p := newTestAzureBlobPath(t, server, "azureblob://account/container/cluster/config")
+
+	if _, err := p.ReadFile(); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist reading missing blob, got %v", err)
+	}
+
+	if err := p.WriteFile(bytes.NewReader([]byte("hello")), nil); err != nil {
+		t.Fatalf("error writing blob: %v", err)
+	}
+	data, err := p.ReadFile()
+	if err != nil {
+		t.Fatalf("error reading blob: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("unexpected blob contents %q", data)
+	}
+
+	if err := p.CreateFile(bytes.NewReader([]byte("again")), nil); !os.IsExist(err) {
+		t.Fatalf("expected exists error creating existing blob, got %v", err)
+	}
+	if err := p.Join("..", "other").(*AzureBlobPath).CreateFile(bytes.NewReader([]byte("other")), nil); err != nil {
+		t.Fatalf("error creating blob: %v", err)
+	}
+
+	if err := p.Remove(); err != nil {
+		t.Fatalf("error removing blob: %v", err)
+	}
+	if err := p.Remove(); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist removing missing blob, got %v", err)
+	}
+}
+
+func TestAzureBlobPathList(t *testing.T) {
+	fake := newFakeAzureBlobServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	base := newTestAzureBlobPath(t, server, "azureblob://account/container/cluster")
+	for _, key := range []string{"config", "pki/ca.crt", "pki/private/ca.key", "instancegroup/nodes"} {
+		if err := base.Join(key).WriteFile(bytes.NewReader([]byte(key)), nil); err != nil {
+			t.Fatalf("error writing %q: %v", key, err)
+		}
+	}
+	if err := newTestAzureBlobPath(t, server, "azureblob://account/container/other/config").WriteFile(bytes.NewReader([]byte("other")), nil); err != nil {
+		t.Fatalf("error writing blob: %v", err)
+	}
+
+	grid := []struct {
+		List     func() ([]Path, error)
+		Expected []string
+	}{
+		{
+			List: base.ReadDir,
+			Expected: []string{
+				"azureblob://account/container/cluster/config",
+				"azureblob://account/container/cluster/instancegroup/",
+				"azureblob://account/container/cluster/pki/",
+			},
+		},
+		{
+			List: base.ReadTree,
+			Expected: []string{
+				"azureblob://account/container/cluster/config",
+				"azureblob://account/container/cluster/instancegroup/nodes",
+				"azureblob://account/container/cluster/pki/ca.crt",
+				"azureblob://account/container/cluster/pki/private/ca.key",
+			},
+		},
+		{
+			List: base.Join("pki").ReadDir,
+			Expected: []string{
+				"azureblob://account/container/cluster/pki/ca.crt",
+				"azureblob://account/container/cluster/pki/private/",
+			},
+		},
+	}
+	for _, g := range grid {
+		paths, err := g.List()
+		if err != nil {
+			t.Fatalf("error listing: %v", err)
+		}
+		var actual []string
+		for _, p := range paths {
+			actual = append(actual, p.Path())
+		}
+		sort.Strings(actual)
+		if !reflect.DeepEqual(actual, g.Expected) {
+			t.Errorf("unexpected listing %v, expected %v", actual, g.Expected)
+		}
+	}
+
+	if _, err := base.Join("missing").ReadDir(); !os.IsNotExist(err) {
+		t.Errorf("expected not-exist listing missing directory, got %v", err)
+	}
+}
+
+func TestAzureBlobPathHash(t *testing.T) {
+	fake := newFakeAzureBlobServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	base := newTestAzureBlobPath(t, server, "azureblob://account/container/files")
+	if err := base.Join("kubelet").WriteFile(bytes.NewReader([]byte("kubelet binary")), nil); err != nil {
+		t.Fatalf("error writing blob: %v", err)
+	}
+	expected, err := hashing.HashAlgorithmMD5.Hash(bytes.NewReader([]byte("kubelet binary")))
+	if err != nil {
+		t.Fatalf("error hashing: %v", err)
+	}
+
+	// The hash of a listed blob comes from the listing; otherwise it is fetched from the blob properties
+	listed, err := base.ReadTree()
+	if err != nil {
+		t.Fatalf("error listing: %v", err)
+	}
+	if len(listed) != 1 || listed[0].(*AzureBlobPath).md5Hash == "" {
+		t.Fatalf("expected listed blob with hash, got %v", listed)
+	}
+	for _, p := range []Path{listed[0], base.Join("kubelet")} {
+		hash, err := p.(HasHash).PreferredHash()
+		if err != nil {
+			t.Fatalf("error getting hash of %s: %v", p, err)
+		}
+		if hash == nil || !hash.Equal(expected) {
+			t.Errorf("unexpected hash %v of %s, expected %v", hash, p, expected)
+		}
+	}
+
+	hash, err := base.Join("kubelet").(HasHash).Hash(hashing.HashAlgorithmSHA256)
+	if err != nil || hash != nil {
+		t.Errorf("expected no SHA256 hash, got %v, %v", hash, err)
+	}
+}
+
+func TestAzureBlobPathACL(t *testing.T) {
+	fake := newFakeAzureBlobServer()
+	fake.publicAccess["account/public"] = "blob"
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	grid := []struct {
+		Path        string
+		ACL         ACL
+		ExpectError bool
+	}{
+		{
+			Path: "azureblob://account/private/file",
+			ACL:  &AzureBlobAcl{},
+		},
+		{
+			Path:        "azureblob://account/private/file",
+			ACL:         &AzureBlobAcl{PublicAccess: "blob"},
+			ExpectError: true,
+		},
+		{
+			Path: "azureblob://account/public/file",
+			ACL:  &AzureBlobAcl{PublicAccess: "blob"},
+		},
+		{
+			Path:        "azureblob://account/public/file",
+			ACL:         &AzureBlobAcl{PublicAccess: "container"},
+			ExpectError: true,
+		},
+		{
+			Path:        "azureblob://account/public/file",
+			ACL:         &S3Acl{},
+			ExpectError: true,
+		},
+	}
+	for _, g := range grid {
+		err := newTestAzureBlobPath(t, server, g.Path).WriteFile(bytes.NewReader([]byte("data")), g.ACL)
+		if g.ExpectError && err == nil {
+			t.Errorf("expected error writing %s with ACL %v", g.Path, g.ACL)
+		}
+		if !g.ExpectError && err != nil {
+			t.Errorf("unexpected error writing %s with ACL %v: %v", g.Path, g.ACL, err)
+		}
+	}
+}
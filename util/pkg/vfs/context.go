@@ -49,6 +49,8 @@ type VFSContext struct {
 	swiftClient *gophercloud.ServiceClient
 	// ossClient is the Aliyun Open Source Storage client
 	ossClient *oss.Client
+	// azureBlobClient is the Azure Blob Storage client
+	azureBlobClient *AzureBlobClient
 }
 
 var Context = VFSContext{
@@ -139,6 +141,10 @@ func (c *VFSContext) BuildVfsPath(p string) (Path, error) {
 		return c.buildOSSPath(p)
 	}
 
+	if strings.HasPrefix(p, "azureblob://") {
+		return c.buildAzureBlobPath(p)
+	}
+
 	return nil, fmt.Errorf("unknown / unhandled path type: %q", p)
 }
 
@@ -406,3 +412,43 @@ func (c *VFSContext) buildOSSPath(p string) (*OSSPath, error) {
 
 	return NewOSSPath(c.ossClient, bucket, u.Path)
 }
+
+func (c *VFSContext) buildAzureBlobPath(p string) (*AzureBlobPath, error) {
+	u, err := url.Parse(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure blob path: %q", p)
+	}
+
+	if u.Scheme != "azureblob" {
+		return nil, fmt.Errorf("invalid azure blob path: %q", p)
+	}
+
+	account := strings.TrimSuffix(u.Host, "/")
+	if account == "" {
+		return nil, fmt.Errorf("invalid azure blob path: %q", p)
+	}
+
+	// The first path component is the container
+	tokens := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	container := tokens[0]
+	if container == "" {
+		return nil, fmt.Errorf("invalid azure blob path, container not specified: %q", p)
+	}
+	key := ""
+	if len(tokens) > 1 {
+		key = tokens[1]
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.azureBlobClient == nil {
+		azureBlobClient, err := NewAzureBlobClient()
+		if err != nil {
+			return nil, err
+		}
+		c.azureBlobClient = azureBlobClient
+	}
+
+	return NewAzureBlobPath(c.azureBlobClient, account, container, key), nil
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"runtime"
@@ -55,6 +56,9 @@ type S3BucketDetails struct {
 	// name is the name of the bucket
 	name string
 
+	// endpoint is the S3-compatible endpoint serving the bucket, or nil for AWS
+	endpoint *s3Endpoint
+
 	// mutex protects applyServerSideEncryptionByDefault
 	mutex sync.Mutex
 
@@ -75,25 +79,26 @@ func NewS3Context() *S3Context {
 	}
 }
 
-func (s *S3Context) getClient(region string) (*s3.S3, error) {
+// getClient returns a client for the region of AWS S3, or of the S3-compatible endpoint if endpoint is not nil
+func (s *S3Context) getClient(region string, endpoint *s3Endpoint) (*s3.S3, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	s3Client := s.clients[region]
+	key := region
+	if endpoint != nil {
+		key = endpoint.key()
+	}
+
+	s3Client := s.clients[key]
 	if s3Client == nil {
 		var config *aws.Config
-		var err error
-		endpoint := os.Getenv("S3_ENDPOINT")
-		if endpoint == "" {
+		if endpoint == nil {
 			config = aws.NewConfig().WithRegion(region)
 			config = config.WithCredentialsChainVerboseErrors(true)
 		} else {
 			// Use customized S3 storage
-			klog.Infof("Found S3_ENDPOINT=%q, using as non-AWS S3 backend", endpoint)
-			config, err = getCustomS3Config(endpoint, region)
-			if err != nil {
-				return nil, err
-			}
+			klog.Infof("Using %q as non-AWS S3 backend", endpoint.url)
+			config = getCustomS3Config(endpoint)
 		}
 
 		sess, err := session.NewSession(config)
@@ -101,31 +106,99 @@ func (s *S3Context) getClient(region string) (*s3.S3, error) {
 			return nil, fmt.Errorf("error starting new AWS session: %v", err)
 		}
 		s3Client = s3.New(sess, config)
-		s.clients[region] = s3Client
+		s.clients[key] = s3Client
 	}
 
 	return s3Client, nil
 }
 
-func getCustomS3Config(endpoint string, region string) (*aws.Config, error) {
-	accessKeyID := os.Getenv("S3_ACCESS_KEY_ID")
-	if accessKeyID == "" {
-		return nil, fmt.Errorf("S3_ACCESS_KEY_ID cannot be empty when S3_ENDPOINT is not empty")
-	}
-	secretAccessKey := os.Getenv("S3_SECRET_ACCESS_KEY")
-	if secretAccessKey == "" {
-		return nil, fmt.Errorf("S3_SECRET_ACCESS_KEY cannot be empty when S3_ENDPOINT is not empty")
-	}
-
+func getCustomS3Config(endpoint *s3Endpoint) *aws.Config {
 	s3Config := &aws.Config{
-		Credentials:      credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""),
-		Endpoint:         aws.String(endpoint),
-		Region:           aws.String(region),
+		Credentials:      credentials.NewStaticCredentials(endpoint.accessKeyID, endpoint.secretAccessKey, ""),
+		Endpoint:         aws.String(endpoint.url),
+		Region:           aws.String(endpoint.region),
 		S3ForcePathStyle: aws.Bool(true),
 	}
 	s3Config = s3Config.WithCredentialsChainVerboseErrors(true)
 
-	return s3Config, nil
+	return s3Config
+}
+
+// s3Endpoint is an S3-compatible endpoint (e.g. MinIO or Ceph), used instead of AWS S3
+type s3Endpoint struct {
+	url             string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+}
+
+// key identifies the client for the endpoint
+func (e *s3Endpoint) key() string {
+	return e.url + "|" + e.region + "|" + e.accessKeyID
+}
+
+// findS3Endpoint returns the S3-compatible endpoint serving the bucket, or nil if the bucket is on AWS.
+// The endpoint of a bucket is taken from S3_BUCKET_ENDPOINTS, a comma separated list of <bucket>=<url>,
+// falling back to S3_ENDPOINT for all buckets.  Credentials can be set in the userinfo of the url,
+// and the region in a region query parameter; otherwise they are read from S3_ACCESS_KEY_ID,
+// S3_SECRET_ACCESS_KEY and S3_REGION.
+func findS3Endpoint(bucket string) (*s3Endpoint, error) {
+	endpoint := ""
+	for _, entry := range strings.Split(os.Getenv("S3_BUCKET_ENDPOINTS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		tokens := strings.SplitN(entry, "=", 2)
+		if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+			return nil, fmt.Errorf("invalid S3_BUCKET_ENDPOINTS entry %q, expected <bucket>=<url>", entry)
+		}
+		if tokens[0] == bucket {
+			endpoint = tokens[1]
+			break
+		}
+	}
+	if endpoint == "" {
+		endpoint = os.Getenv("S3_ENDPOINT")
+	}
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint for bucket %q: %v", bucket, err)
+	}
+
+	e := &s3Endpoint{
+		region:          u.Query().Get("region"),
+		accessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+	}
+	if u.User != nil {
+		e.accessKeyID = u.User.Username()
+		e.secretAccessKey, _ = u.User.Password()
+	}
+	if e.region == "" {
+		e.region = os.Getenv("S3_REGION")
+	}
+	if e.region == "" {
+		e.region = "us-east-1"
+	}
+
+	// Don't pass the credentials or region on to the client
+	u.User = nil
+	u.RawQuery = ""
+	e.url = u.String()
+
+	if e.accessKeyID == "" {
+		return nil, fmt.Errorf("no access key configured for S3 endpoint %q of bucket %q (set S3_ACCESS_KEY_ID)", e.url, bucket)
+	}
+	if e.secretAccessKey == "" {
+		return nil, fmt.Errorf("no secret key configured for S3 endpoint %q of bucket %q (set S3_SECRET_ACCESS_KEY)", e.url, bucket)
+	}
+
+	return e, nil
 }
 
 func (s *S3Context) getDetailsForBucket(bucket string) (*S3BucketDetails, error) {
@@ -143,17 +216,19 @@ func (s *S3Context) getDetailsForBucket(bucket string) (*S3BucketDetails, error)
 		name:    bucket,
 	}
 
-	// Probe to find correct region for bucket
-	endpoint := os.Getenv("S3_ENDPOINT")
-	if endpoint != "" {
+	endpoint, err := findS3Endpoint(bucket)
+	if err != nil {
+		return bucketDetails, err
+	}
+	if endpoint != nil {
 		// If customized S3 storage is set, return user-defined region
-		bucketDetails.region = os.Getenv("S3_REGION")
-		if bucketDetails.region == "" {
-			bucketDetails.region = "us-east-1"
-		}
+		bucketDetails.endpoint = endpoint
+		bucketDetails.region = endpoint.region
 		return bucketDetails, nil
 	}
 
+	// Probe to find correct region for bucket
+
 	awsRegion := os.Getenv("AWS_REGION")
 	if awsRegion == "" && isRunningOnEC2() {
 		region, err := getRegionFromMetadata()
@@ -179,7 +254,7 @@ func (s *S3Context) getDetailsForBucket(bucket string) (*S3BucketDetails, error)
 	}
 	var response *s3.GetBucketLocationOutput
 
-	s3Client, err := s.getClient(awsRegion)
+	s3Client, err := s.getClient(awsRegion, nil)
 	if err != nil {
 		return bucketDetails, fmt.Errorf("error connecting to S3: %s", err)
 	}
@@ -229,7 +304,7 @@ func (b *S3BucketDetails) hasServerSideEncryptionByDefault() bool {
 	// We only make one attempt to find the SSE policy (even if there's an error)
 	b.applyServerSideEncryptionByDefault = &applyServerSideEncryptionByDefault
 
-	client, err := b.context.getClient(b.region)
+	client, err := b.context.getClient(b.region, b.endpoint)
 	if err != nil {
 		klog.Warningf("Unable to read bucket encryption policy for %q in region %q: will encrypt using AES256", b.name, b.region)
 		return false
@@ -334,7 +334,7 @@ func (p *S3Path) client() (*s3.S3, error) {
 		}
 	}
 
-	client, err := p.s3Context.getClient(p.bucketDetails.region)
+	client, err := p.s3Context.getClient(p.bucketDetails.region, p.bucketDetails.endpoint)
 	if err != nil {
 		return nil, err
 	}